@@ -0,0 +1,47 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var advisoriesCmd = &cobra.Command{
+	Use:   "advisories",
+	Short: "Check mods against a feed of known-malicious or broken files",
+	Long: `The advisory feed (the advisory_feed config option, a url or a local file)
+lists mod files that are known to be malicious or broken, by the sha256 of
+their archive or by their nexus mod (and file). Once it's downloaded with
+` + "`modctl advisories update`" + `, importing or applying a malicious archive is
+refused (unless --ignore-advisories is passed) and broken ones are warned
+about.`,
+}
+
+// printAdvisory prints what the advisory feed knows about an archive that
+// is being imported.
+func printAdvisory(warning string) {
+	fmt.Println(ui.Warn.Render("  ⚠ " + warning))
+}
+
+func init() {
+	rootCmd.AddCommand(advisoriesCmd)
+}
@@ -0,0 +1,131 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var advisoriesListAll bool
+
+var advisoriesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the imported mods that have advisories",
+	Long: `List the imported mod files (of every game) that the advisory feed knows to
+be malicious or broken, or with --all every advisory of the feed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		feed, err := c.AdvisoryFeed(ctx)
+		if err != nil {
+			return err
+		}
+		if feed == nil {
+			fmt.Println(ui.Subtle.Render("No advisories yet; run `modctl advisories update`"))
+			return nil
+		}
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("Advisories from %s (updated %s)", feed.Source, feed.UpdatedAt)))
+
+		if advisoriesListAll {
+			all, err := c.Advisories(ctx)
+			if err != nil {
+				return err
+			}
+
+			rows := [][]string{}
+			for _, a := range all {
+				about := a.ArchiveSHA256
+				if len(about) > 12 {
+					about = about[:12]
+				}
+				if a.NexusModID != 0 {
+					about = fmt.Sprintf("%s/mods/%d", a.NexusDomain, a.NexusModID)
+					if a.NexusFileID != 0 {
+						about += fmt.Sprintf(" file %d", a.NexusFileID)
+					}
+				}
+				rows = append(rows, []string{
+					" " + a.ID + " ",
+					" " + severityLabel(a.Severity) + " ",
+					" " + about + " ",
+					" " + a.Summary + " ",
+				})
+			}
+
+			fmt.Println(table.New().
+				Headers(" ID ", " Severity ", " About ", " Summary ").
+				Rows(rows...))
+			return nil
+		}
+
+		matches, err := c.AdvisoryMatches(ctx)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			fmt.Println(ui.OK.Render("✓ no imported mod has an advisory"))
+			return nil
+		}
+
+		rows := [][]string{}
+		for _, m := range matches {
+			rows = append(rows, []string{
+				" " + m.Game + " ",
+				fmt.Sprintf(" %s / %s (v%d) ", m.ModName, m.FileLabel, m.VersionID),
+				" " + severityLabel(m.Severity) + " ",
+				" " + m.AdvisoryID + " ",
+				" " + m.Summary + " ",
+			})
+		}
+
+		fmt.Println(table.New().
+			Headers(" Game ", " Mod ", " Severity ", " ID ", " Summary ").
+			Rows(rows...))
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+// severityLabel renders the severity of an advisory.
+func severityLabel(severity string) string {
+	if severity == modctl.SeverityMalicious {
+		return ui.Err.Render(severity)
+	}
+	return ui.Warn.Render(severity)
+}
+
+func init() {
+	advisoriesCmd.AddCommand(advisoriesListCmd)
+
+	advisoriesListCmd.Flags().BoolVar(&advisoriesListAll, "all", false,
+		"List every advisory of the feed")
+}
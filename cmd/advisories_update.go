@@ -0,0 +1,99 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var advisoriesUpdateCmd = &cobra.Command{
+	Use:   "update [url-or-file]",
+	Short: "Download the advisory feed",
+	Long: `Download the advisory feed from the advisory_feed config option (or the
+given url or file instead) and replace the advisories in the database with
+it. Afterwards the imported mods that it has advisories for are listed.
+
+The feed is a JSON object:
+
+  {"format": "modctl-advisories", "version": 1,
+   "advisories": [{"id": "MA-2026-001", "severity": "malicious",
+                   "summary": "...", "url": "...", "published_at": "...",
+                   "sha256": "...",
+                   "nexus": {"game_domain": "...", "mod_id": 1,
+                             "file_id": 2}}, ...]}
+
+The severity is "malicious" (refused) or "broken" (warned about). An
+advisory needs the sha256 of the archive or the nexus mod; without a
+file_id it's about every file of the mod.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		source := viper.GetString("advisory_feed")
+		if len(args) == 1 {
+			source = args[0]
+		}
+		if source == "" {
+			return fmt.Errorf("no advisory feed configured; set advisory_feed in the config file or pass one")
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		cmd.SilenceUsage = true
+
+		n, err := c.UpdateAdvisories(ctx, source)
+		if err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("✓ %d advisories from %s", n, source)),
+			fmt.Sprintf("record %d advisories from %s", n, source))
+
+		matches, err := c.AdvisoryMatches(ctx)
+		if err != nil {
+			return err
+		}
+		if len(matches) > 0 {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"⚠ %d imported mod file(s) have advisories (see `modctl advisories list`)", len(matches))))
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	advisoriesCmd.AddCommand(advisoriesUpdateCmd)
+}
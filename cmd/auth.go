@@ -0,0 +1,60 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage credentials for external services",
+	Long: `Manage credentials (e.g., the Nexus Mods API key) for external services.
+
+Credentials are stored in the OS keyring (the Secret Service on Linux or the
+keychain on macOS), never in the config file. Set secrets_provider = "env" in
+the config file on systems without a keyring; then credentials are only read
+from environment variables such as MODCTL_NEXUS_API_KEY.`,
+}
+
+// authServices maps the user-facing service name to its secret key
+var authServices = map[string]string{
+	"nexus": secrets.NexusAPIKey,
+}
+
+func authSecretKey(args []string) (string, string, error) {
+	name := "nexus"
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	key, ok := authServices[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown service %q (supported: nexus)", name)
+	}
+	return name, key, nil
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+}
@@ -0,0 +1,123 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/offline"
+	"github.com/mfinelli/modctl/internal/secrets"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+var authLoginNoVerify bool
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login [service]",
+	Short: "Store an API key in the keyring",
+	Long: `Store the API key for a service (default: nexus) in the OS keyring.
+
+The key is read from the terminal without echoing it, or from stdin if stdin
+is not a terminal:
+
+  modctl auth login < ~/nexus-api-key.txt
+
+You can find your personal Nexus Mods API key at the bottom of
+https://www.nexusmods.com/users/myaccount?tab=api
+
+The key is checked against the Nexus API before it's stored; pass --no-verify
+to skip the check (e.g., when offline).`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"nexus"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, key, err := authSecretKey(args)
+		if err != nil {
+			return err
+		}
+
+		p, err := secrets.New(viper.GetString("secrets_provider"))
+		if err != nil {
+			return err
+		}
+
+		var value string
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Fprintf(os.Stderr, "%s API key: ", name)
+			b, err := term.ReadPassword(int(os.Stdin.Fd()))
+			fmt.Fprintln(os.Stderr)
+			if err != nil {
+				return fmt.Errorf("read API key: %w", err)
+			}
+			value = string(b)
+		} else {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("read API key from stdin: %w", err)
+			}
+			value = line
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("no API key provided")
+		}
+
+		if !authLoginNoVerify && offline.Enabled() {
+			fmt.Println(ui.Subtle.Render("Not checking the API key: offline"))
+		} else if !authLoginNoVerify {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			user, err := modctl.ValidateNexusKey(ctx, value)
+			if err != nil {
+				return fmt.Errorf("validate API key: %w", err)
+			}
+			fmt.Printf("Logged in to Nexus Mods as %s\n", user)
+		}
+
+		if err := p.Set(key, value); err != nil {
+			if errors.Is(err, secrets.ErrReadOnly) {
+				return fmt.Errorf("the %s secrets provider is read-only; set %s instead",
+					p.Name(), secrets.EnvVar(key))
+			}
+			return err
+		}
+
+		fmt.Printf("Stored %s API key in the %s\n", name, p.Name())
+
+		return nil
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authLoginCmd)
+
+	authLoginCmd.Flags().BoolVar(&authLoginNoVerify, "no-verify", false,
+		"Don't check the API key against the Nexus API")
+}
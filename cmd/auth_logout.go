@@ -0,0 +1,64 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/secrets"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var authLogoutCmd = &cobra.Command{
+	Use:       "logout [service]",
+	Short:     "Remove an API key from the keyring",
+	Long:      `Remove the stored API key for a service (default: nexus) from the OS keyring.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"nexus"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, key, err := authSecretKey(args)
+		if err != nil {
+			return err
+		}
+
+		p, err := secrets.New(viper.GetString("secrets_provider"))
+		if err != nil {
+			return err
+		}
+
+		switch err := p.Delete(key); err {
+		case nil:
+			fmt.Printf("Removed %s API key from the %s\n", name, p.Name())
+		case secrets.ErrNotFound:
+			fmt.Printf("No %s API key stored in the %s\n", name, p.Name())
+		case secrets.ErrReadOnly:
+			return fmt.Errorf("the %s secrets provider is read-only; unset %s instead",
+				p.Name(), secrets.EnvVar(key))
+		default:
+			return err
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authLogoutCmd)
+}
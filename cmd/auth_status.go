@@ -0,0 +1,74 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mfinelli/modctl/internal/secrets"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which credentials are configured",
+	Long: `Show which credentials are configured and where they are read from.
+
+The credentials themselves are never printed.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := secrets.New(viper.GetString("secrets_provider"))
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Subtle.Render("provider: " + p.Name()))
+
+		names := make([]string, 0, len(authServices))
+		for name := range authServices {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			key := authServices[name]
+			_, source, err := secrets.Lookup(p, key)
+			switch err {
+			case nil:
+				fmt.Printf("%s  %s\n", ui.OK.Render("✓ "+name),
+					ui.Subtle.Render("("+source+")"))
+			case secrets.ErrNotFound:
+				fmt.Printf("%s  %s\n", ui.Warn.Render("✗ "+name),
+					ui.Subtle.Render("(not configured; run `modctl auth login "+name+"`)"))
+			default:
+				fmt.Printf("%s  %s\n", ui.Warn.Render("✗ "+name),
+					ui.Subtle.Render("("+err.Error()+")"))
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authStatusCmd)
+}
@@ -0,0 +1,94 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var backupsExportCmd = &cobra.Command{
+	Use:   "export <game> <out.tar.zst>",
+	Short: "Export the backed-up game files into an archive",
+	Long: `Write every backup that modctl took for a game (the original game files that
+deployed mods replaced) into a single archive.
+
+Files are stored as <target>/<relpath>, e.g. game/bin/x64/foo.dll, so the
+archive is a snapshot of the pristine files that can be kept outside of modctl
+or extracted over the game directories by hand.
+
+The archive is compressed according to its extension (.tar.zst, .tar.xz, or
+.tar.gz), or as configured by archive_compression otherwise.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		}
+		if len(args) == 1 {
+			return []string{"tar.zst", "tar.xz", "tar.gz"}, cobra.ShellCompDirectiveFilterFileExt
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		// backups are removed again by unapply, keep them stable while
+		// we're reading them
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := c.Game(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		out := args[1]
+
+		cmd.SilenceUsage = true
+
+		res, err := c.ExportBackups(ctx, gi, out)
+		if err != nil {
+			return err
+		}
+		if res.Files == 0 {
+			fmt.Println(ui.Subtle.Render("No backups for " + gi.DisplayName))
+			return nil
+		}
+
+		fmt.Printf("Exported %d backed-up files (%s) of %s to %s\n", res.Files,
+			internal.FormatBytes(res.Size), gi.DisplayName, out)
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	backupsCmd.AddCommand(backupsExportCmd)
+}
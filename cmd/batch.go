@@ -0,0 +1,165 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchStopOnError bool
+	batchYAML        bool
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch <file|->",
+	Short: "Run a script of modctl commands",
+	Long: `Run the modctl commands in a file (or - for stdin) one after the other in a
+single process, e.g., to build a modlist from a script.
+
+The script has one command per line (without the leading "modctl"); blank
+lines and # comments are skipped and arguments are split like a POSIX shell
+does (quotes and backslash escapes work but nothing is expanded):
+
+  games set-active steam:1091500
+  mods import 'My Mod-123-1-0.zip' --name "My Mod"
+  profiles add 12
+
+Scripts ending in .yaml or .yml (or any script with --yaml) are YAML instead,
+where every step is either a command line or a list of arguments:
+
+  stop_on_error: true
+  steps:
+    - games set-active steam:1091500
+    - [mods, import, "My Mod-123-1-0.zip", --name, "My Mod"]
+
+Every step is a separate command with its own database transactions: a step
+that fails doesn't undo the steps before it. By default the remaining steps
+still run; pass --stop-on-error (or set stop_on_error in a YAML script) to stop
+at the first failure. modctl exits with an error if any step failed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		var in io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+
+			switch strings.ToLower(filepath.Ext(args[0])) {
+			case ".yaml", ".yml":
+				batchYAML = true
+			}
+		}
+
+		var script internal.BatchScript
+		var err error
+		if batchYAML {
+			script, err = internal.ParseBatchYAML(in)
+		} else {
+			script, err = internal.ParseBatchLines(in)
+		}
+		if err != nil {
+			return err
+		}
+
+		stopOnError := batchStopOnError
+		if !cmd.Flags().Changed("stop-on-error") && script.StopOnError != nil {
+			stopOnError = *script.StopOnError
+		}
+
+		// check the database once instead of in every step
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		internal.KeepMigrated()
+
+		cmd.SilenceUsage = true
+
+		keep := keptFlags()
+		var failed []internal.BatchStep
+		ran := 0
+		for _, step := range script.Steps {
+			if ctx.Err() != nil {
+				break
+			}
+
+			fmt.Fprintln(os.Stderr, ui.Subtle.Render(fmt.Sprintf(
+				"==> [%d] modctl %s", step.Line, strings.Join(step.Args, " "))))
+
+			ran++
+			if err := runCommand(step.Args, keep); err != nil {
+				failed = append(failed, step)
+				if stopOnError {
+					break
+				}
+			}
+		}
+
+		if ran < len(script.Steps) {
+			fmt.Fprintln(os.Stderr, ui.Warn.Render(fmt.Sprintf(
+				"Stopped: %d of %d step(s) didn't run", len(script.Steps)-ran, len(script.Steps))))
+		}
+
+		if len(failed) == 0 && ran == len(script.Steps) {
+			fmt.Fprintln(os.Stderr, ui.OK.Render(fmt.Sprintf("All %d step(s) succeeded", ran)))
+			return nil
+		}
+
+		if len(failed) > 0 {
+			lines := make([]string, len(failed))
+			for i, step := range failed {
+				lines[i] = fmt.Sprint(step.Line)
+			}
+			fmt.Fprintln(os.Stderr, ui.Warn.Render(fmt.Sprintf(
+				"%d of %d step(s) failed (line %s)", len(failed), ran, strings.Join(lines, ", "))))
+		}
+
+		cmd.SilenceErrors = true
+		return exitCodeError{code: 1}
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	batchCmd.Flags().BoolVar(&batchStopOnError, "stop-on-error", false,
+		"Stop at the first step that fails")
+	batchCmd.Flags().BoolVar(&batchYAML, "yaml", false,
+		"Read a YAML script (the default for .yaml and .yml files)")
+}
@@ -0,0 +1,301 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	bugreportOutput     string
+	bugreportOperations int64
+	bugreportAttach     []string
+	bugreportKeepHome   bool
+)
+
+// only include the tail of attached log files
+const bugreportMaxAttachBytes = 1 << 20
+
+var bugreportCmd = &cobra.Command{
+	Use:   "bugreport",
+	Short: "Collect diagnostics into an archive to attach to an issue",
+	Long: `Gather the information that is usually needed to investigate a problem into a
+single .tar.gz archive that can be attached to an issue report.
+
+The archive contains:
+  - version.txt: modctl, Go, OS/architecture, SQLite, bsdtar, and database
+    schema versions
+  - config.json: the effective configuration (secrets redacted) and any
+    MODCTL_* environment variables (secret values redacted)
+  - doctor.json: the output of doctor --json
+  - operations.json: the most recent apply/unapply operations
+  - active.json: the active store/game selection
+  - attachments/: any files passed with --attach (e.g., the output of a
+    scheduled modctl cron run); only the last 1MiB of each file is kept
+
+modctl doesn't write log files of its own, so use --attach to include any logs
+that you have captured.
+
+Your home directory is replaced with "~" in every file unless --keep-home is
+given. Nothing is uploaded: review the archive before sharing it.`,
+	Args:         cobra.ExactArgs(0),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		now := time.Now()
+		name := "modctl-bugreport-" + now.Format("20060102-150405")
+
+		out := bugreportOutput
+		if out == "" {
+			out = name + ".tar.gz"
+		}
+
+		report := collectDoctorReport(ctx)
+
+		var files []bugreportFile
+		var problems []string
+		add := func(name string, b []byte) {
+			files = append(files, bugreportFile{Name: name, Data: b})
+		}
+		addJSON := func(name string, v any) {
+			b, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+				return
+			}
+			add(name, append(b, '\n'))
+		}
+
+		add("version.txt", []byte(bugreportVersions(ctx, report)))
+
+		addJSON("config.json", map[string]any{
+			"config_file": viper.ConfigFileUsed(),
+			"settings":    internal.RedactSettings(viper.AllSettings()),
+			"environment": internal.RedactEnviron(os.Environ(), "MODCTL_"),
+		})
+
+		addJSON("doctor.json", report)
+
+		if report.Database.Usable {
+			ops, err := bugreportRecentOperations(ctx, bugreportOperations)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("operations.json: %v", err))
+			} else {
+				addJSON("operations.json", ops)
+			}
+		} else {
+			problems = append(problems, "operations.json: database is not usable")
+		}
+
+		if active, err := state.LoadActive(); err != nil {
+			problems = append(problems, fmt.Sprintf("active.json: %v", err))
+		} else {
+			addJSON("active.json", active)
+		}
+
+		for _, path := range bugreportAttach {
+			b, err := readTail(path, bugreportMaxAttachBytes)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("attachment %s: %v", path, err))
+				continue
+			}
+			add(filepath.Join("attachments", filepath.Base(path)), b)
+		}
+
+		if len(problems) > 0 {
+			add("errors.txt", []byte(strings.Join(problems, "\n")+"\n"))
+		}
+
+		if !bugreportKeepHome {
+			if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+				for i := range files {
+					files[i].Data = bytes.ReplaceAll(files[i].Data, []byte(home), []byte("~"))
+				}
+			}
+		}
+
+		if err := writeBugreport(out, name, now, files); err != nil {
+			return err
+		}
+
+		for _, p := range problems {
+			fmt.Println(ui.Warn.Render("  ⚠ " + p))
+		}
+		fmt.Println(ui.OK.Render("Wrote " + out))
+		fmt.Println(ui.Subtle.Render("  Review the contents before attaching it to an issue."))
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bugreportCmd)
+
+	bugreportCmd.Flags().StringVarP(&bugreportOutput, "output", "o", "",
+		"Where to write the archive (default modctl-bugreport-<timestamp>.tar.gz)")
+	bugreportCmd.MarkFlagFilename("output", "tar.gz")
+	bugreportCmd.Flags().Int64Var(&bugreportOperations, "operations", 50,
+		"How many recent operations to include")
+	bugreportCmd.Flags().StringArrayVar(&bugreportAttach, "attach", nil,
+		"Include a log file in the report (repeatable)")
+	bugreportCmd.Flags().BoolVar(&bugreportKeepHome, "keep-home", false,
+		"Don't replace your home directory with ~")
+}
+
+type bugreportFile struct {
+	Name string
+	Data []byte
+}
+
+func bugreportVersions(ctx context.Context, report doctorReport) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "modctl:  %s\n", rootCmd.Version)
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" || s.Key == "vcs.modified" {
+				fmt.Fprintf(&b, "  %s: %s\n", s.Key, s.Value)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "go:      %s\n", runtime.Version())
+	fmt.Fprintf(&b, "os/arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+
+	if runtime.GOOS == "linux" {
+		if out, err := exec.CommandContext(ctx, "uname", "-sr").Output(); err == nil {
+			fmt.Fprintf(&b, "kernel:  %s\n", strings.TrimSpace(string(out)))
+		}
+	}
+
+	if report.Database.Usable {
+		if c, err := modctl.OpenExisting(); err == nil {
+			if v, err := c.SQLiteVersion(ctx); err == nil {
+				fmt.Fprintf(&b, "sqlite:  %s\n", v)
+			}
+			c.Close()
+		}
+		fmt.Fprintf(&b, "schema:  %d (latest %d)\n",
+			report.Database.SchemaVersion, report.Database.TargetVersion)
+	} else {
+		fmt.Fprintf(&b, "schema:  unknown (database not usable)\n")
+	}
+
+	if report.Bsdtar.Version != "" {
+		fmt.Fprintf(&b, "bsdtar:  %s\n", report.Bsdtar.Version)
+	} else {
+		fmt.Fprintf(&b, "bsdtar:  not found\n")
+	}
+
+	return b.String()
+}
+
+func bugreportRecentOperations(ctx context.Context, limit int64) ([]modctl.Operation, error) {
+	c, err := modctl.OpenExisting()
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	return c.RecentOperations(ctx, limit)
+}
+
+// readTail returns (at most) the last max bytes of the file at path.
+func readTail(path string, max int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if st.Size() > max {
+		if _, err := f.Seek(st.Size()-max, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	return io.ReadAll(f)
+}
+
+func writeBugreport(path, prefix string, mtime time.Time, files []bugreportFile) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	werr := func() error {
+		for _, file := range files {
+			hdr := &tar.Header{
+				Name:    filepath.ToSlash(filepath.Join(prefix, file.Name)),
+				Mode:    0o644,
+				Size:    int64(len(file.Data)),
+				ModTime: mtime,
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write(file.Data); err != nil {
+				return err
+			}
+		}
+		if err := tw.Close(); err != nil {
+			return err
+		}
+		return gz.Close()
+	}()
+
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		_ = os.Remove(path)
+		return fmt.Errorf("write %s: %w", path, werr)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/httpcache"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached API responses",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := viper.GetString("http_cache_dir")
+		if err := httpcache.Clear(dir); err != nil {
+			return err
+		}
+
+		fmt.Println("Cleared API response cache")
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+}
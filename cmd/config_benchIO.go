@@ -0,0 +1,146 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configBenchIOSize string
+
+var configBenchIOCmd = &cobra.Command{
+	Use:   "bench-io [file]",
+	Short: "Time reading files with different io_* settings",
+	Long: `Read and hash a file the way modctl stores, verifies, and hashes files, with
+different io_* settings, to help pick them (e.g., on a network filesystem).
+
+The file is read with the configured settings first, then with every buffer
+size (io_buffer_size), and then with the fastest buffer size and each of the
+other settings changed one at a time: io_direct, every io_fadvise hint, and a
+few io_readahead windows. The file is dropped from the page cache before
+every run (where the kernel allows it) so that it's read from the disk each
+time.
+
+Without a file a test file of --size (default 256MiB) is written into
+tmp_dir and removed afterwards. Pass one of your archives (or any big file
+on the filesystem in question) to time that filesystem instead.
+
+Hashing takes time too: on fast disks every setting will be about as fast.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		base, err := internal.IOTuningFromConfig()
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		path := ""
+		if len(args) == 1 {
+			path = args[0]
+			if st, err := os.Stat(path); err != nil {
+				return err
+			} else if !st.Mode().IsRegular() {
+				return fmt.Errorf("%s is not a regular file", path)
+			}
+		} else {
+			size, err := internal.ParseBytes(configBenchIOSize)
+			if err != nil {
+				return err
+			}
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("Writing a %s test file into %s...",
+				internal.FormatBytes(size), viper.GetString("tmp_dir"))))
+			path, err = internal.WriteIOBenchFile(ctx, viper.GetString("tmp_dir"), size)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(path)
+		}
+
+		fmt.Println(ui.Header.Render("Reading " + path))
+		fmt.Println()
+
+		results, err := internal.BenchIO(ctx, path, base, func(r internal.IOBenchResult) {
+			line := fmt.Sprintf("  %-40s", describeTuning(r.Tuning))
+			if r.Error != "" {
+				fmt.Println(ui.Err.Render(line + "  " + r.Error))
+				return
+			}
+			fmt.Printf("%s  %10s/s  %s\n", line, internal.FormatBytes(int64(r.Throughput())),
+				ui.Subtle.Render(r.Duration.Round(time.Millisecond).String()))
+		})
+		if err != nil {
+			return err
+		}
+
+		best, ok := internal.FastestIOBench(results)
+		if !ok || best.Throughput() == 0 {
+			return fmt.Errorf("no run succeeded")
+		}
+
+		fmt.Println()
+		if best.Tuning == base {
+			fmt.Println(ui.OK.Render("The configured settings were the fastest"))
+			return nil
+		}
+		fmt.Println(ui.OK.Render("Fastest: " + describeTuning(best.Tuning)))
+		fmt.Println(ui.Subtle.Render("  in the config file:"))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  io_buffer_size = %q", internal.FormatBytes(int64(best.Tuning.BufferSize)))))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  io_direct = %t", best.Tuning.Direct)))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  io_fadvise = %q", best.Tuning.Fadvise)))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  io_readahead = %q", internal.FormatBytes(best.Tuning.ReadAhead))))
+
+		return nil
+	},
+}
+
+// describeTuning is a one-line summary of the io_* settings.
+func describeTuning(t blobstore.Tuning) string {
+	s := "buffer " + internal.FormatBytes(int64(t.BufferSize))
+	if t.Direct {
+		s += ", direct"
+	}
+	if t.Fadvise != "" {
+		s += ", " + t.Fadvise
+	}
+	if t.ReadAhead > 0 {
+		s += ", read ahead " + internal.FormatBytes(t.ReadAhead)
+	}
+	return s
+}
+
+func init() {
+	configCmd.AddCommand(configBenchIOCmd)
+
+	configBenchIOCmd.Flags().StringVar(&configBenchIOSize, "size", "256MiB",
+		"The size of the test file (without a file)")
+}
@@ -0,0 +1,80 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for mistakes",
+	Long: `Check the config file against the options that modctl knows.
+
+Unknown options (usually typos) are ignored by modctl, so they're reported
+as warnings. Values of the wrong type or that don't make sense (e.g., an
+unknown compression or secrets provider, or an invalid target template) are
+errors; every other command refuses to run while there are any.
+
+The paths (of the config file or the defaults) are checked as well:
+directories that don't exist yet are warnings (` + "`modctl init`" + ` creates
+them), and paths that can't be used as-is (e.g., with environment variables,
+modctl only expands a leading ~/) are errors.
+
+The exit status is non-zero if there are errors.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println(ui.Header.Render("Config"))
+		if f := viper.ConfigFileUsed(); f != "" {
+			fmt.Println(ui.Subtle.Render("  file: " + f))
+		} else {
+			fmt.Println(ui.Subtle.Render("  no config file, using the defaults"))
+		}
+
+		problems := internal.ValidateConfig(true)
+		if len(problems) == 0 {
+			fmt.Println(ui.OK.Render("  ✓ no problems found"))
+			return nil
+		}
+
+		for _, p := range problems {
+			if p.Warning {
+				fmt.Println(ui.Warn.Render("  ⚠ " + p.String()))
+			} else {
+				fmt.Println(ui.Err.Render("  ✗ " + p.String()))
+			}
+		}
+
+		if internal.HasConfigErrors(problems) {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			return exitCodeError{code: 1}
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
@@ -0,0 +1,79 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var contextRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Fix an active selection that can't be used",
+	Long: `Check the active selection (active.json in the modctl state directory) against
+the database and fix what's wrong with it:
+
+  - a corrupt file, or one written by a newer modctl, is reset (the other
+    commands ignore it with a warning until then)
+  - an active game install that no longer exists is replaced by the install
+    with the same selector if there is one (e.g., the game was removed and
+    found again by a refresh), and unset otherwise
+  - the selector and store of the active game are filled in or corrected
+  - an active store that doesn't exist is unset
+  - if the active game has no active profile but only one profile, that
+    profile is activated`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		cmd.SilenceUsage = true
+
+		fixes, err := c.RepairActive(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(fixes) == 0 {
+			fmt.Println("The active selection is fine")
+			return nil
+		}
+		for _, f := range fixes {
+			fmt.Println(ui.OK.Render("  ✓ " + f))
+		}
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	contextCmd.AddCommand(contextRepairCmd)
+}
@@ -0,0 +1,446 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/offline"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+// exit code when something changed (updates or drift were found)
+const cronChangedExitCode = 10
+
+var (
+	cronGame      string
+	cronNoRefresh bool
+	cronNoDrift   bool
+	cronNoUpdates bool
+	cronJSON      bool
+	cronNotify    bool
+)
+
+type cronInstall struct {
+	ID       int64  `json:"id"`
+	Selector string `json:"selector"`
+	Name     string `json:"name"`
+	Root     string `json:"install_root"`
+	OldRoot  string `json:"old_install_root,omitempty"`
+}
+
+type cronDriftedFile struct {
+	GameInstallID int64  `json:"game_install_id"`
+	Selector      string `json:"selector"`
+	Path          string `json:"path"`
+	// it's a vanilla game file again
+	Vanilla bool `json:"vanilla"`
+}
+
+type cronUpdate struct {
+	GameInstallID   int64  `json:"game_install_id"`
+	ModPageID       int64  `json:"mod_page_id"`
+	ModName         string `json:"mod_name"`
+	ImportedVersion string `json:"imported_version,omitempty"`
+	LatestVersion   string `json:"latest_version"`
+	// not reported by an earlier run
+	New bool `json:"new"`
+}
+
+type cronSummary struct {
+	CheckedAt string `json:"checked_at"`
+	Changed   bool   `json:"changed"`
+	// why nothing was checked (another modctl command was running)
+	Skipped string `json:"skipped,omitempty"`
+
+	Refresh struct {
+		Ran      bool          `json:"ran"`
+		Added    []cronInstall `json:"added"`
+		Removed  []cronInstall `json:"removed"`
+		Moved    []cronInstall `json:"moved"`
+		Warnings []string      `json:"warnings"`
+	} `json:"refresh"`
+
+	Drift struct {
+		Ran      bool              `json:"ran"`
+		Files    []cronDriftedFile `json:"files"`
+		Warnings []string          `json:"warnings"`
+	} `json:"drift"`
+
+	Updates struct {
+		Ran           bool         `json:"ran"`
+		SkippedReason string       `json:"skipped_reason,omitempty"`
+		Checked       int          `json:"checked"`
+		Outdated      []cronUpdate `json:"outdated"`
+		Deferred      int          `json:"deferred"`
+		Errors        []string     `json:"errors"`
+	} `json:"updates"`
+}
+
+var cronCmd = &cobra.Command{
+	Use:   "cron",
+	Short: "Refresh stores and check for mod updates (for timers)",
+	Long: `Refresh the enabled stores and check Nexus-linked mods for updates.
+
+This command is meant to be run periodically (e.g., from a systemd timer) so
+it is silent unless something changed: new, removed, or moved game installs,
+deployed files that were modified on disk since they were applied ("drift",
+the files that apply and unapply refuse to replace without --force), or mods
+with a newer version on Nexus. Drift is reported on every run until the
+profile is applied again, but updates are only reported once: the ones that
+an earlier run reported aren't again (until there is an even newer version). With --json a machine-readable summary is always printed
+instead, with every outdated mod (new says which weren't reported before).
+
+If another modctl command is running the run is skipped, to try again the
+next time.
+
+Exit status:
+  0   nothing changed (or the run was skipped)
+  10  new updates or drift were found
+  1   an error occurred
+
+If no Nexus API key is configured, or with --offline, the update check is
+skipped. With --notify a desktop notification (notify-send on Linux) is sent
+when something changed.
+
+Example systemd user units:
+
+  # ~/.config/systemd/user/modctl-cron.service
+  [Service]
+  Type=oneshot
+  ExecStart=modctl cron --notify
+
+  # ~/.config/systemd/user/modctl-cron.timer
+  [Timer]
+  OnCalendar=daily
+  Persistent=true
+
+  [Install]
+  WantedBy=timers.target`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		var summary cronSummary
+		summary.CheckedAt = time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+		summary.Refresh.Added = []cronInstall{}
+		summary.Refresh.Removed = []cronInstall{}
+		summary.Refresh.Moved = []cronInstall{}
+		summary.Refresh.Warnings = []string{}
+		summary.Drift.Files = []cronDriftedFile{}
+		summary.Drift.Warnings = []string{}
+		summary.Updates.Outdated = []cronUpdate{}
+		summary.Updates.Errors = []string{}
+
+		// the lock goes first: opening the database migrates it, and the
+		// refresh and the update check write to it
+		l, err := modctl.LockState(cmd.CommandPath())
+		var held *modctl.LockHeldError
+		if errors.As(err, &held) {
+			// try again on the next run instead of failing the job
+			summary.Skipped = held.Error()
+			if cronJSON {
+				return printCronJSON(summary)
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+		defer l.Release()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		toCron := func(gi modctl.Game) cronInstall {
+			return cronInstall{
+				ID:       gi.ID,
+				Selector: gi.Selector(),
+				Name:     gi.DisplayName,
+				Root:     gi.InstallRoot,
+			}
+		}
+
+		if !cronNoRefresh {
+			var warnings bytes.Buffer
+			drift, err := c.RefreshGames(ctx, &warnings)
+			if err != nil {
+				return err
+			}
+			for _, line := range strings.Split(strings.TrimSpace(warnings.String()), "\n") {
+				if line != "" {
+					summary.Refresh.Warnings = append(summary.Refresh.Warnings, line)
+				}
+			}
+
+			for _, gi := range drift.Added {
+				summary.Refresh.Added = append(summary.Refresh.Added, toCron(gi))
+			}
+			for _, gi := range drift.Removed {
+				summary.Refresh.Removed = append(summary.Refresh.Removed, toCron(gi))
+			}
+			for _, m := range drift.Moved {
+				ci := toCron(m.Game)
+				ci.OldRoot = m.OldRoot
+				summary.Refresh.Moved = append(summary.Refresh.Moved, ci)
+			}
+
+			summary.Refresh.Ran = true
+			summary.Changed = summary.Changed || !drift.Empty()
+		}
+
+		var installs []modctl.Game
+		if cronGame != "" {
+			gi, err := c.Game(ctx, cronGame)
+			if err != nil {
+				return err
+			}
+			installs = append(installs, gi)
+		} else {
+			all, err := c.Games(ctx)
+			if err != nil {
+				return err
+			}
+			for _, gi := range all {
+				if gi.Present {
+					installs = append(installs, gi)
+				}
+			}
+		}
+
+		if !cronNoDrift {
+			for _, gi := range installs {
+				drift, err := c.Drift(ctx, gi)
+				if err != nil {
+					return err
+				}
+				summary.Drift.Warnings = append(summary.Drift.Warnings, drift.Warnings...)
+
+				vanilla := map[string]bool{}
+				for _, p := range drift.Vanilla {
+					vanilla[p] = true
+				}
+				for _, p := range drift.Paths {
+					summary.Drift.Files = append(summary.Drift.Files, cronDriftedFile{
+						GameInstallID: gi.ID,
+						Selector:      gi.Selector(),
+						Path:          p,
+						Vanilla:       vanilla[p],
+					})
+				}
+			}
+
+			summary.Drift.Ran = true
+			summary.Changed = summary.Changed || len(summary.Drift.Files) > 0
+		}
+
+		if !cronNoUpdates {
+			err := c.CheckNexusKey(ctx)
+			switch {
+			case offline.Enabled():
+				// the cache would only say what the last check found
+				summary.Updates.SkippedReason = "offline"
+			case err != nil:
+				summary.Updates.SkippedReason = err.Error()
+			default:
+				summary.Updates.Ran = true
+				var all []modctl.UpdateCheck
+				for _, gi := range installs {
+					checks, err := c.CheckUpdates(ctx, gi)
+					if err != nil {
+						return err
+					}
+
+					for _, u := range checks {
+						summary.Updates.Checked++
+						switch u.Status {
+						case modctl.UpdateStatusOutdated:
+							summary.Updates.Outdated = append(summary.Updates.Outdated, cronUpdate{
+								GameInstallID:   gi.ID,
+								ModPageID:       u.PageID,
+								ModName:         u.ModName,
+								ImportedVersion: u.ImportedVersion,
+								LatestVersion:   u.LatestVersion,
+							})
+						case modctl.UpdateStatusDeferred:
+							summary.Updates.Deferred++
+						case modctl.UpdateStatusError:
+							summary.Updates.Errors = append(summary.Updates.Errors,
+								fmt.Sprintf("%s (page_id=%d): %v", u.ModName, u.PageID, u.Err))
+						}
+					}
+					all = append(all, checks...)
+				}
+
+				fresh, err := c.ReportUpdates(ctx, all)
+				if err != nil {
+					return err
+				}
+				isFresh := map[int64]bool{}
+				for _, u := range fresh {
+					isFresh[u.PageID] = true
+				}
+				for i, u := range summary.Updates.Outdated {
+					summary.Updates.Outdated[i].New = isFresh[u.ModPageID]
+				}
+				summary.Changed = summary.Changed || len(fresh) > 0
+			}
+		}
+
+		if cronJSON {
+			if err := printCronJSON(summary); err != nil {
+				return err
+			}
+		} else if summary.Changed {
+			for _, ci := range summary.Refresh.Added {
+				fmt.Printf("new game install: %s (%s)\n", ci.Name, ci.Selector)
+			}
+			for _, ci := range summary.Refresh.Removed {
+				fmt.Printf("game install missing: %s (%s)\n", ci.Name, ci.Selector)
+			}
+			for _, ci := range summary.Refresh.Moved {
+				fmt.Printf("game install moved: %s (%s): %s -> %s\n", ci.Name, ci.Selector, ci.OldRoot, ci.Root)
+			}
+			for _, f := range summary.Drift.Files {
+				line := fmt.Sprintf("deployed file changed: %s (%s)", f.Path, f.Selector)
+				if f.Vanilla {
+					line += ", it's a vanilla game file again"
+				}
+				fmt.Println(line)
+			}
+			for _, u := range summary.Updates.Outdated {
+				if !u.New {
+					continue
+				}
+				imported := u.ImportedVersion
+				if imported == "" {
+					imported = "?"
+				}
+				fmt.Printf("update available: %s %s -> %s\n", u.ModName, imported, u.LatestVersion)
+			}
+		}
+
+		// errors are worth mentioning even if nothing else happened
+		if !cronJSON {
+			for _, e := range summary.Updates.Errors {
+				fmt.Fprintln(os.Stderr, "update check failed: "+e)
+			}
+		}
+
+		if !summary.Changed {
+			return nil
+		}
+
+		if cronNotify {
+			if err := cronSendNotification(ctx, cronNotificationText(summary)); err != nil {
+				fmt.Fprintln(os.Stderr, "send notification: "+err.Error())
+			}
+		}
+
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return exitCodeError{code: cronChangedExitCode}
+	},
+}
+
+func printCronJSON(s cronSummary) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal summary: %w", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func cronNotificationText(s cronSummary) string {
+	parts := []string{}
+	n := 0
+	for _, u := range s.Updates.Outdated {
+		if u.New {
+			n++
+		}
+	}
+	if n > 0 {
+		parts = append(parts, fmt.Sprintf("%d mod update(s) available", n))
+	}
+	if n := len(s.Refresh.Added) + len(s.Refresh.Removed) + len(s.Refresh.Moved); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d game install(s) changed", n))
+	}
+	if n := len(s.Drift.Files); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d deployed file(s) changed", n))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func cronSendNotification(ctx context.Context, body string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.CommandContext(ctx, "osascript", "-e",
+			"display notification "+strconv.Quote(body)+" with title \"modctl\"")
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return errors.New("notify-send not found in $PATH")
+		}
+		c = exec.CommandContext(ctx, "notify-send", "--app-name=modctl", "modctl", body)
+	}
+
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(cronCmd)
+
+	cronCmd.Flags().StringVarP(&cronGame, "game", "g", "",
+		"Only check this game for drift and updates (default: all present games)")
+	cronCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	cronCmd.Flags().BoolVar(&cronNoRefresh, "no-refresh", false,
+		"Don't refresh the stores")
+	cronCmd.Flags().BoolVar(&cronNoDrift, "no-drift", false,
+		"Don't check the deployed files for drift")
+	cronCmd.Flags().BoolVar(&cronNoUpdates, "no-updates", false,
+		"Don't check for mod updates")
+	cronCmd.Flags().BoolVar(&cronJSON, "json", false,
+		"Always print a JSON summary")
+	cronCmd.Flags().BoolVar(&cronNotify, "notify", false,
+		"Send a desktop notification when something changed")
+}
@@ -0,0 +1,127 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/querylog"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	dbAnalyzeSlow time.Duration
+	dbAnalyzeAll  bool
+)
+
+var dbAnalyzeCmd = &cobra.Command{
+	Use:   "analyze [log]",
+	Short: "Report slow database queries",
+	Long: `Report the database queries that took long, from a query log: the file that
+the query_log config option points to (or the one given).
+
+The log is only written while query_log is set, e.g., in the config file:
+
+  query_log = "/tmp/modctl-queries.log"
+
+Queries are grouped (the ones that modctl itself runs by their name) and
+ordered by the total time spent in them. Only the queries that took at least
+--slow at least once are shown unless you pass --all.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.GetString("query_log")
+		if len(args) == 1 {
+			path = args[0]
+		}
+		if path == "" {
+			return fmt.Errorf("no query log: pass one or set query_log in the config")
+		}
+
+		cmd.SilenceUsage = true
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open query log: %w", err)
+		}
+		defer f.Close()
+
+		entries, err := querylog.Read(f)
+		if err != nil {
+			return fmt.Errorf("read query log %s: %w", path, err)
+		}
+
+		stats := querylog.Summarize(entries, dbAnalyzeSlow)
+		shown := 0
+		for _, s := range stats {
+			if s.Slow == 0 && !dbAnalyzeAll {
+				continue
+			}
+
+			if shown == 0 {
+				fmt.Println(ui.Header.Render(fmt.Sprintf("Queries in %s (slow: ≥ %s)", path, dbAnalyzeSlow)))
+				fmt.Println()
+			}
+			shown++
+
+			name := s.Name
+			if name == "" {
+				name = s.Query
+				if len(name) > 72 {
+					name = name[:71] + "…"
+				}
+			}
+			if s.Slow > 0 {
+				fmt.Println(ui.Warn.Render(name))
+			} else {
+				fmt.Println(name)
+			}
+
+			const precision = 100 * time.Microsecond
+			line := fmt.Sprintf("  calls=%d  slow=%d  mean=%s  max=%s  total=%s  rows=%d",
+				s.Calls, s.Slow, s.Mean().Round(precision), s.Max.Round(precision),
+				s.Total.Round(precision), s.Rows)
+			if s.Errors > 0 {
+				line += fmt.Sprintf("  errors=%d", s.Errors)
+			}
+			fmt.Println(ui.Subtle.Render(line))
+			fmt.Println()
+		}
+
+		if shown == 0 {
+			fmt.Println(ui.OK.Render(fmt.Sprintf("✓ no query took %s or longer (%d logged)",
+				dbAnalyzeSlow, len(entries))))
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	dbCmd.AddCommand(dbAnalyzeCmd)
+
+	dbAnalyzeCmd.Flags().DurationVar(&dbAnalyzeSlow, "slow", 50*time.Millisecond,
+		"Report queries that took at least this long")
+	dbAnalyzeCmd.Flags().BoolVar(&dbAnalyzeAll, "all", false,
+		"Also report the queries that were never slow")
+}
@@ -0,0 +1,130 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbExportFormat string
+	dbExportTables []string
+)
+
+var dbExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export the state in the database as JSON",
+	Long: `Export the games, profiles, mods, versions, installed files, and the rest of
+the state in the database as JSON, e.g., for backups, to compare two machines,
+or to feed a dashboard. The export is written to stdout unless a file is
+given.
+
+Every row is exported as stored, as an object of column name to value, with
+rows ordered by id. The json format (the default) is a single object:
+
+  {"format": "modctl-export", "version": 1, "schema_version": 24,
+   "exported_at": "...", "tables": {"profiles": [{"id": 1, ...}], ...}}
+
+The jsonl format (the default for .jsonl files) is the same header followed
+by one {"table": "profiles", "row": {...}} object per line.
+
+Pass --table (repeatable) to only export some of the tables:
+
+  ` + strings.Join(modctl.ExportTables, ", ") + `
+
+The export doesn't include the archives and backups themselves.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		format := dbExportFormat
+		if format == "" {
+			format = "json"
+			if len(args) == 1 && strings.EqualFold(filepath.Ext(args[0]), ".jsonl") {
+				format = "jsonl"
+			}
+		}
+		if format != "json" && format != "jsonl" {
+			return fmt.Errorf("unknown format %q (want json or jsonl)", format)
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		cmd.SilenceUsage = true
+
+		var w io.Writer = os.Stdout
+		var out *os.File
+		if len(args) == 1 {
+			out, err = os.Create(args[0])
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+			w = out
+		}
+
+		err = c.ExportDB(ctx, w, modctl.ExportOptions{
+			JSON:   format == "json",
+			Tables: dbExportTables,
+		})
+		if err != nil {
+			if out != nil {
+				os.Remove(out.Name())
+			}
+			return err
+		}
+
+		if out != nil {
+			if err := out.Close(); err != nil {
+				return fmt.Errorf("write %s: %w", out.Name(), err)
+			}
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	dbCmd.AddCommand(dbExportCmd)
+
+	dbExportCmd.Flags().StringVarP(&dbExportFormat, "format", "f", "",
+		"Output format: json or jsonl (default json, or jsonl for .jsonl files)")
+	dbExportCmd.RegisterFlagCompletionFunc("format",
+		cobra.FixedCompletions([]string{"json", "jsonl"}, cobra.ShellCompDirectiveNoFileComp))
+
+	dbExportCmd.Flags().StringSliceVarP(&dbExportTables, "table", "t", nil,
+		"Only export these tables")
+	dbExportCmd.RegisterFlagCompletionFunc("table",
+		cobra.FixedCompletions(modctl.ExportTables, cobra.ShellCompDirectiveNoFileComp))
+}
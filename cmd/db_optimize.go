@@ -0,0 +1,95 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var dbOptimizeNoVacuum bool
+
+var dbOptimizeCmd = &cobra.Command{
+	Use:   "optimize",
+	Short: "Check and compact the database",
+	Long: `Check the integrity of the database and then keep it small and fast: refresh
+the statistics that SQLite uses to plan queries (PRAGMA optimize), rebuild the
+database to reclaim the space of deleted rows (VACUUM), and copy the
+write-ahead log into the database and truncate it (PRAGMA wal_checkpoint).
+
+A database that fails the integrity check isn't touched; restore it from a
+backup (or run modctl init on a new one and import the mods again).
+
+modctl already refreshes the statistics and truncates the log after large
+operations (see the auto_optimize_rows config option); rebuilding the
+database only happens here. Pass --no-vacuum to skip it, e.g., for a very
+large database.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		cmd.SilenceUsage = true
+
+		rep, err := c.OptimizeDB(ctx, !dbOptimizeNoVacuum)
+		if err != nil {
+			return err
+		}
+
+		if len(rep.Problems) > 0 {
+			fmt.Println(ui.Err.Render("✗ integrity check failed; the database was left alone"))
+			for _, p := range rep.Problems {
+				fmt.Println(ui.Subtle.Render("  " + p))
+			}
+			cmd.SilenceErrors = true
+			return exitCodeError{code: 1}
+		}
+
+		fmt.Println(ui.OK.Render("✓ integrity check passed"))
+		if rep.Busy {
+			fmt.Println(ui.Warn.Render(
+				"  ⚠ another process is using the database; the write-ahead log couldn't be truncated"))
+		}
+		fmt.Println(ui.OK.Render(fmt.Sprintf("Database optimized: %s → %s",
+			internal.FormatBytes(rep.SizeBefore), internal.FormatBytes(rep.SizeAfter))))
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	dbCmd.AddCommand(dbOptimizeCmd)
+
+	dbOptimizeCmd.Flags().BoolVar(&dbOptimizeNoVacuum, "no-vacuum", false,
+		"Don't rebuild the database")
+}
@@ -20,9 +20,7 @@ package cmd
 
 import (
 	"context"
-	"crypto/sha256"
-	"database/sql"
-	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -32,17 +30,18 @@ import (
 	"strings"
 	"time"
 
-	"github.com/adrg/xdg"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/mfinelli/modctl/dbq"
 	"github.com/mfinelli/modctl/internal"
-	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/fscaps"
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var deepCheck bool
 var doctorRehash bool
+var doctorJSON bool
 
 var SampleTarGz []byte
 
@@ -54,24 +53,79 @@ var doctorCmd = &cobra.Command{
 
 Doctor verifies:
   - State directory layout and writability (archives/, backups/, overrides/,
-    tmp/)
+    tmp/), warns if tmp/ is on a different filesystem than the stores, and
+    reports leftover temporary files (see ` + "`modctl tmp clean`" + `)
   - Database is present and usable (SELECT 1), and reports pending migrations
   - SQLite integrity checks (quick_check by default; integrity_check +
     foreign_key_check with --deep)
   - External dependencies (bsdtar present, --version works, and can list a
     built-in test archive)
+  - Optional external tools (LOOT, a merge tool, 7-Zip, steam, xdg-open,
+    notify-send, fuse-overlayfs) and which features are unavailable without
+    them. A missing optional tool isn't a failure.
   - (TODO) Steam readiness when the Steam store is enabled (locates Steam root
     and parses libraryfolders.vdf)
-  - Integrity of blobs stored on disk (presence, size, hash)
+  - Integrity of blobs stored on disk (presence, size, and with --recheck the
+    hash; corrupted blobs are moved to the quarantine directory and the mods
+    and profiles that need them are listed)
+  - Consistency between active.json and the database: the active game still
+    exists and is present, every game has exactly one active profile, no
+    profile item references a missing mod version or a missing or corrupted
+    archive, no two items of a profile share a priority or enable versions of
+    the same mod file, and no applied profile is empty
+  - What the filesystems of the blob store and of every install target
+    support: hardlinks, reflinks, symlinks, and case-sensitive names (apply
+    uses this to choose how to put files in place). This is informational,
+    a missing capability isn't a failure.
+  - Leftovers of other mod managers (Vortex deployment manifests and
+    backups, Mod Organizer 2 instances and hidden files) near the top of the
+    targets of every game install, with how to clean them up or import them
+    (see ` + "`modctl games scan-orphans`" + `). This is informational too.
 
 Doctor does not modify Steam or your game installs. It may read files to
-validate integrity.`,
+validate integrity.
+
+With --read-only the directories aren't tested for writing, the filesystems
+aren't probed, and --recheck isn't supported.
+
+With --json the results are printed as a single JSON document instead (the
+--recheck rehash is not supported in this mode); the exit status is non-zero
+if any check failed.`,
 	Args:         cobra.ExactArgs(0),
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
 
+		if doctorRehash {
+			if err := readonly.Check("doctor --recheck"); err != nil {
+				return err
+			}
+
+			// corrupted blobs are quarantined
+			l, err := modctl.LockState(cmd.CommandPath() + " --recheck")
+			if err != nil {
+				return err
+			}
+			defer l.Release()
+		}
+
+		if doctorJSON {
+			report := collectDoctorReport(ctx)
+
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+
+			if !report.OK {
+				cmd.SilenceErrors = true
+				return exitCodeError{code: 1}
+			}
+			return nil
+		}
+
 		run := func() error {
 			if err := checkDb(ctx); err != nil {
 				return err
@@ -82,12 +136,22 @@ validate integrity.`,
 			if err := checkBsdtar(ctx); err != nil {
 				return err
 			}
+			checkTools()
 			if err := checkSteamStatus(); err != nil {
 				return err
 			}
 			if err := checkBlobs(ctx); err != nil {
 				return err
 			}
+			if err := checkConsistency(ctx); err != nil {
+				return err
+			}
+			if err := checkFilesystems(ctx); err != nil {
+				return err
+			}
+			if err := checkLeftovers(ctx); err != nil {
+				return err
+			}
 			return nil
 		}
 
@@ -107,25 +171,14 @@ func init() {
 
 	doctorCmd.Flags().BoolVar(&deepCheck, "full", false, "Runs a more complete database check")
 	doctorCmd.Flags().BoolVar(&doctorRehash, "recheck", false, "Rehashes all blobs in the blob store to ensure integrity")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Print the results as JSON")
 }
 
 // checkDb verifies the DB exists and is usable, and warns if migrations
 // are pending. Returns error only for non-recoverable failures.
 func checkDb(ctx context.Context) error {
-	// TODO: extract these somewhere else
-	headerStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("63"))
-	subtleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245"))
-	errStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("1"))
-	okStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("2"))
-	warnStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("3"))
-
-	fmt.Println(headerStyle.Render("Database Checks"))
-	fmt.Println(subtleStyle.Render("  db: " + viper.GetString("database")))
+	fmt.Println(ui.Header.Render("Database Checks"))
+	fmt.Println(ui.Subtle.Render("  db: " + viper.GetString("database")))
 	fmt.Println()
 
 	// 1) DB file existence
@@ -133,152 +186,97 @@ func checkDb(ctx context.Context) error {
 	info, err := os.Stat(dbPath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			fmt.Println(errStyle.Render("  ✗ database does not exist"))
-			fmt.Println(subtleStyle.Render("    run `modctl init` to create the state directory and database"))
+			fmt.Println(ui.Err.Render("  ✗ database does not exist"))
+			fmt.Println(ui.Subtle.Render("    run `modctl init` to create the state directory and database"))
 			fmt.Println()
 			return fmt.Errorf("database missing: %s", dbPath)
 		}
-		fmt.Println(errStyle.Render("  ✗ could not stat database file"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render("  ✗ could not stat database file"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		fmt.Println()
 		return fmt.Errorf("cannot stat database: %w", err)
 	}
 	if info.IsDir() {
-		fmt.Println(errStyle.Render("  ✗ database path is a directory, expected a file"))
+		fmt.Println(ui.Err.Render("  ✗ database path is a directory, expected a file"))
 		fmt.Println()
 		return fmt.Errorf("database path is a directory: %s", dbPath)
 	}
-	fmt.Println(okStyle.Render("  ✓ database file exists"))
-
-	// Keep doctor snappy.
-	ctxT, cancel := context.WithTimeout(ctx, 1*time.Second)
-	defer cancel()
+	fmt.Println(ui.OK.Render("  ✓ database file exists"))
 
 	// 2) Open DB + trivial query
-	db, err := internal.SetupDB()
+	c, err := modctl.OpenExisting()
 	if err != nil {
-		fmt.Println(errStyle.Render("  ✗ could not open database"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render("  ✗ could not open database"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		fmt.Println()
 		return fmt.Errorf("cannot open database: %w", err)
 	}
-	defer db.Close()
+	defer c.Close()
 
-	var one int
-	if err := db.QueryRowContext(ctxT, "SELECT 1").Scan(&one); err != nil || one != 1 {
-		fmt.Println(errStyle.Render("  ✗ basic query failed (SELECT 1)"))
-		if err != nil {
-			fmt.Println(subtleStyle.Render("    " + err.Error()))
-		}
+	if err := c.Ping(ctx); err != nil {
+		fmt.Println(ui.Err.Render("  ✗ basic query failed (SELECT 1)"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		fmt.Println()
 		return fmt.Errorf("database not usable: %w", err)
 	}
-	fmt.Println(okStyle.Render("  ✓ basic query OK (SELECT 1)"))
+	fmt.Println(ui.OK.Render("  ✓ basic query OK (SELECT 1)"))
 
 	// 3) migrations status
-	p, err := internal.GooseProvider(db)
+	current, target, err := c.Migrations(ctx)
 	if err != nil {
 		// if we can't determine migration state treat it as fatal
-		fmt.Println(errStyle.Render("  ✗ could not determine migration status"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render("  ✗ could not determine migration status"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		fmt.Println()
 		return fmt.Errorf("cannot determine migration status: %w", err)
 	}
 
-	pending, err := p.HasPending(ctx)
-	if err != nil {
-		// if we can't determine migration state treat it as fatal
-		fmt.Println(errStyle.Render("  ✗ could not determine migration status"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
-		fmt.Println()
-		return fmt.Errorf("cannot determine migration status: %w", err)
-	}
-
-	if pending {
-		current, target, verr := p.GetVersions(ctx)
-		if verr == nil {
-			fmt.Println(warnStyle.Render(fmt.Sprintf(
-				"  ⚠ pending migrations (db=%d, target=%d)",
-				current, target,
-			)))
-		} else {
-			fmt.Println(warnStyle.Render("  ⚠ pending migrations - other commands will auto-migrate"))
-		}
+	if current < target {
+		fmt.Println(ui.Warn.Render(fmt.Sprintf(
+			"  ⚠ pending migrations (db=%d, target=%d)",
+			current, target,
+		)))
 	} else {
-		fmt.Println(okStyle.Render("  ✓ migrations up to date"))
+		fmt.Println(ui.OK.Render("  ✓ migrations up to date"))
 	}
 
 	// 4) quick_check or integrity_check and foreign_key_check
-	pragma := "PRAGMA quick_check;"
 	label := "quick_check"
 	if deepCheck {
-		pragma = "PRAGMA integrity_check;"
 		label = "integrity_check"
 	}
 
-	rows, err := db.QueryContext(ctx, pragma)
+	problems, err := c.IntegrityProblems(ctx, deepCheck)
 	if err != nil {
-		fmt.Println(errStyle.Render(fmt.Sprintf("  ✗ %s failed", label)))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s failed", label)))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		return fmt.Errorf("%s failed: %w", label, err)
 	}
-	defer rows.Close()
-
-	var problems []string
-	for rows.Next() {
-		var result string
-		if err := rows.Scan(&result); err != nil {
-			return err
-		}
-		if result != "ok" {
-			problems = append(problems, result)
-		}
-	}
 
 	if len(problems) == 0 {
-		fmt.Println(okStyle.Render(fmt.Sprintf("  ✓ %s OK", label)))
+		fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s OK", label)))
 	} else {
-		fmt.Println(errStyle.Render(fmt.Sprintf("  ✗ %s reported corruption", label)))
+		fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s reported corruption", label)))
 		for _, p := range problems {
-			fmt.Println(subtleStyle.Render("    " + p))
+			fmt.Println(ui.Subtle.Render("    " + p))
 		}
 		return fmt.Errorf("database integrity check failed")
 	}
 
 	if deepCheck {
-		rows, err := db.QueryContext(ctx, "PRAGMA foreign_key_check;")
+		violations, err := c.ForeignKeyViolations(ctx)
 		if err != nil {
-			fmt.Println(errStyle.Render("  ✗ foreign_key_check failed"))
-			fmt.Println(subtleStyle.Render("    " + err.Error()))
+			fmt.Println(ui.Err.Render("  ✗ foreign_key_check failed"))
+			fmt.Println(ui.Subtle.Render("    " + err.Error()))
 			return fmt.Errorf("foreign_key_check failed: %w", err)
 		}
-		defer rows.Close()
-
-		var violations []string
-
-		for rows.Next() {
-			var table string
-			var rowid int64
-			var parent string
-			var fkid int64
-
-			if err := rows.Scan(&table, &rowid, &parent, &fkid); err != nil {
-				return err
-			}
-
-			violations = append(violations,
-				fmt.Sprintf("table=%s rowid=%d parent=%s fkid=%d",
-					table, rowid, parent, fkid,
-				),
-			)
-		}
 
 		if len(violations) == 0 {
-			fmt.Println(okStyle.Render("  ✓ foreign_key_check OK"))
+			fmt.Println(ui.OK.Render("  ✓ foreign_key_check OK"))
 		} else {
-			fmt.Println(errStyle.Render("  ✗ foreign_key_check reported violations"))
+			fmt.Println(ui.Err.Render("  ✗ foreign_key_check reported violations"))
 			for _, v := range violations {
-				fmt.Println(subtleStyle.Render("    " + v))
+				fmt.Println(ui.Subtle.Render("    " + v))
 			}
 			return fmt.Errorf("foreign key violations detected")
 		}
@@ -290,18 +288,9 @@ func checkDb(ctx context.Context) error {
 }
 
 func checkPaths() error {
-	// TODO: extract these somewhere else
-	headerStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("63"))
-	subtleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245"))
-	errStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("1"))
-	okStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("2"))
-
-	fmt.Println(headerStyle.Render("State Directory Checks"))
-	fmt.Println(subtleStyle.Render("  root: " + filepath.Join(xdg.DataHome, "modctl")))
+	fmt.Println(ui.Header.Render("State Directory Checks"))
+	fmt.Println(ui.Subtle.Render("  root: " + viper.GetString("data_dir")))
+	fmt.Println(ui.Subtle.Render("  state: " + viper.GetString("state_dir")))
 	fmt.Println()
 
 	required := []string{
@@ -317,27 +306,60 @@ func checkPaths() error {
 		name := filepath.Base(path)
 		info, err := os.Stat(path)
 		if err != nil {
-			fmt.Println(errStyle.Render(fmt.Sprintf("  ✗ %s: does not exist (%s)", name, path)))
+			fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: does not exist (%s)", name, path)))
 			fatalErr = errors.New("missing required state directory")
 			continue
 		}
 
 		if !info.IsDir() {
-			fmt.Println(errStyle.Render(fmt.Sprintf("  ✗ %s: not a directory (%s)", name, path)))
+			fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: not a directory (%s)", name, path)))
 			fatalErr = errors.New("invalid state directory type")
 			continue
 		}
 
+		if readonly.Enabled() {
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s: OK (%s; not tested for writing)", name, path)))
+			continue
+		}
+
 		// Test writability by creating a temp file
 		testFile := filepath.Join(path, ".modctl-doctor-write-test")
 		if err := os.WriteFile(testFile, []byte("ok"), 0o600); err != nil {
-			fmt.Println(errStyle.Render(fmt.Sprintf("  ✗ %s: not writable (%s)", name, path)))
+			fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: not writable (%s)", name, path)))
 			fatalErr = errors.New("state directory not writable")
 			continue
 		}
 		_ = os.Remove(testFile)
 
-		fmt.Println(okStyle.Render(fmt.Sprintf("  ✓ %s: OK (%s)", name, path)))
+		fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s: OK (%s)", name, path)))
+	}
+
+	for _, path := range required {
+		fs := fscaps.NetworkFS(path)
+		if fs == "" {
+			continue
+		}
+		fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s is on a network filesystem (%s)", filepath.Base(path), fs)))
+		if path == required[3] {
+			fmt.Println(ui.Subtle.Render("    archives are extracted here for every apply, a local disk is much faster"))
+		} else {
+			fmt.Println(ui.Subtle.Render("    importing, verifying, and deploying read and write its files over the network (slower, especially `doctor --recheck` and `mods verify`)"))
+		}
+	}
+
+	tmp := viper.GetString("tmp_dir")
+	for _, path := range required[:3] {
+		if crossDevice(path, tmp) {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s is not on the same filesystem as tmp",
+				filepath.Base(path))))
+			fmt.Println(ui.Subtle.Render("    files are copied into it instead of renamed (slower, and needs the space twice while ingesting)"))
+		}
+	}
+
+	if n, size := reclaimableTmp(tmp); n > 0 {
+		fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ tmp: %s in %d leftover temporary file(s)",
+			internal.FormatBytes(size), n)))
+		fmt.Println(ui.Subtle.Render("    run `modctl tmp clean` to remove them"))
 	}
 
 	fmt.Println()
@@ -346,29 +368,19 @@ func checkPaths() error {
 }
 
 func checkBsdtar(ctx context.Context) error {
-	// TODO: extract these somewhere else
-	headerStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("63"))
-	subtleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245"))
-	errStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("1"))
-	okStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("2"))
-
 	bsdtar := viper.GetString("bsdtar")
-	fmt.Println(headerStyle.Render("bsdtar Checks"))
-	fmt.Println(subtleStyle.Render("  search: " + bsdtar))
+	fmt.Println(ui.Header.Render("bsdtar Checks"))
+	fmt.Println(ui.Subtle.Render("  search: " + bsdtar))
 	fmt.Println()
 
 	resolvedPath, err := exec.LookPath(bsdtar)
 	if err != nil {
-		fmt.Println(errStyle.Render("  ✗ bsdtar not found in PATH"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render("  ✗ bsdtar not found in PATH"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		return fmt.Errorf("bsdtar not found: %w", err)
 	}
 
-	fmt.Println(okStyle.Render("  ✓ bsdtar found: " + resolvedPath))
+	fmt.Println(ui.OK.Render("  ✓ bsdtar found: " + resolvedPath))
 
 	// Use short timeout for all subprocess calls
 	cmdCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
@@ -377,13 +389,13 @@ func checkBsdtar(ctx context.Context) error {
 	versionCmd := exec.CommandContext(cmdCtx, resolvedPath, "--version")
 	versionOutput, err := versionCmd.CombinedOutput()
 	if err != nil {
-		fmt.Println(errStyle.Render("  ✗ bsdtar --version failed"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render("  ✗ bsdtar --version failed"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		return fmt.Errorf("bsdtar --version failed: %w", err)
 	}
 
-	fmt.Println(okStyle.Render("  ✓ bsdtar --version OK"))
-	fmt.Println(subtleStyle.Render("      " + strings.TrimSpace(string(versionOutput))))
+	fmt.Println(ui.OK.Render("  ✓ bsdtar --version OK"))
+	fmt.Println(ui.Subtle.Render("      " + strings.TrimSpace(string(versionOutput))))
 
 	tmpFile, err := os.CreateTemp("", "modctl-bsdtar-*.tar.gz")
 	if err != nil {
@@ -400,36 +412,63 @@ func checkBsdtar(ctx context.Context) error {
 	listCmd := exec.CommandContext(cmdCtx, resolvedPath, "-t", "-f", tmpPath)
 	listOutput, err := listCmd.CombinedOutput()
 	if err != nil {
-		fmt.Println(errStyle.Render("  ✗ bsdtar failed to list sample archive"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render("  ✗ bsdtar failed to list sample archive"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		return fmt.Errorf("bsdtar test archive failed: %w", err)
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(listOutput)), "\n")
 
 	if len(lines) != 1 {
-		fmt.Println(errStyle.Render("  ✗ unexpected archive contents"))
-		fmt.Println(subtleStyle.Render(fmt.Sprintf("    expected 1 entry, got %d", len(lines))))
+		fmt.Println(ui.Err.Render("  ✗ unexpected archive contents"))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("    expected 1 entry, got %d", len(lines))))
 		for _, e := range lines {
-			fmt.Println(subtleStyle.Render("    " + e))
+			fmt.Println(ui.Subtle.Render("    " + e))
 		}
 		return fmt.Errorf("invalid sample archive contents")
 	}
 
 	if lines[0] != "hello.txt" {
-		fmt.Println(errStyle.Render("  ✗ archive entry mismatch"))
-		fmt.Println(subtleStyle.Render("    expected: hello.txt"))
-		fmt.Println(subtleStyle.Render("    got:      " + lines[0]))
+		fmt.Println(ui.Err.Render("  ✗ archive entry mismatch"))
+		fmt.Println(ui.Subtle.Render("    expected: hello.txt"))
+		fmt.Println(ui.Subtle.Render("    got:      " + lines[0]))
 		return fmt.Errorf("archive contents incorrect")
 	}
 
-	fmt.Println(okStyle.Render("  ✓ bsdtar archive test OK"))
+	fmt.Println(ui.OK.Render("  ✓ bsdtar archive test OK"))
 
 	fmt.Println()
 
 	return nil
 }
 
+// checkTools lists the optional external tools and the features that are
+// disabled because they're missing. It never fails.
+func checkTools() {
+	fmt.Println(ui.Header.Render("Optional Tools"))
+
+	for _, t := range internal.CheckTools() {
+		if t.Found {
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s: %s", t.Name, t.Path)))
+			continue
+		}
+
+		if t.Command == "" {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: not configured", t.Name)))
+		} else {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: %s not found in PATH", t.Name, t.Command)))
+		}
+		for _, f := range t.Features {
+			fmt.Println(ui.Subtle.Render("    unavailable: " + f))
+		}
+		if t.Config != "" {
+			fmt.Println(ui.Subtle.Render("    set " + t.Config + " in the config to use a different command"))
+		}
+	}
+
+	fmt.Println()
+}
+
 func checkSteamStatus() error {
 	// TODO loop through game installs and ensure that we can write into them
 	return nil
@@ -441,131 +480,223 @@ func checkSteamStatus() error {
 // For now this is "presence + size sanity". If rehashCheck is enabled we’ll
 // add a second pass later to stream-hash and update verified_at.
 func checkBlobs(ctx context.Context) error {
-	// TODO: extract these somewhere else
-	headerStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("63"))
-	subtleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245"))
-	errStyle := lipgloss.NewStyle().Bold(true).
-		Foreground(lipgloss.Color("1"))
-	okStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("2"))
-	warnStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("3"))
-
-	fmt.Println(headerStyle.Render("Blob Store Checks"))
-	fmt.Println(subtleStyle.Render("  archives:  " + viper.GetString("archives_dir")))
-	fmt.Println(subtleStyle.Render("  backups:   " + viper.GetString("backups_dir")))
-	fmt.Println(subtleStyle.Render("  overrides: " + viper.GetString("overrides_dir")))
+	fmt.Println(ui.Header.Render("Blob Store Checks"))
+	fmt.Println(ui.Subtle.Render("  archives:  " + viper.GetString("archives_dir")))
+	fmt.Println(ui.Subtle.Render("  backups:   " + viper.GetString("backups_dir")))
+	fmt.Println(ui.Subtle.Render("  overrides: " + viper.GetString("overrides_dir")))
 	fmt.Println()
 
-	db, err := internal.SetupDB()
+	c, err := modctl.OpenExisting()
 	if err != nil {
-		fmt.Println(errStyle.Render("  ✗ could not open database"))
-		fmt.Println(subtleStyle.Render("    " + err.Error()))
+		fmt.Println(ui.Err.Render("  ✗ could not open database"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		fmt.Println()
 		return fmt.Errorf("cannot open database: %w", err)
 	}
-	defer db.Close()
+	defer c.Close()
 
-	q := dbq.New(db)
-
-	bs := blobstore.Store{
-		ArchivesDir:  viper.GetString("archives_dir"),
-		BackupsDir:   viper.GetString("backups_dir"),
-		OverridesDir: viper.GetString("overrides_dir"),
-	}
+	mismatched := 0
+	for _, kind := range modctl.BlobKinds {
+		counts, err := c.CountBlobs(ctx, kind)
+		if err != nil {
+			fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: failed to check blobs", kind)))
+			fmt.Println(ui.Subtle.Render("    " + err.Error()))
+			fmt.Println()
+			return err
+		}
 
-	kinds := []blobstore.Kind{
-		blobstore.KindArchive,
-		blobstore.KindBackup,
-		blobstore.KindOverride,
+		// TODO: there should be a way to surface to the user _which_ blobs
+		//       are missing (eg original filename or which games a blob is
+		//       associated with)
+		switch {
+		case counts.Recorded == 0:
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s: no blobs recorded", kind)))
+		case counts.Missing == 0 && counts.Quarantined == 0:
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s: %d/%d present", kind, counts.Recorded, counts.Recorded)))
+		case counts.Quarantined == 0:
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: %d/%d present (%d missing)", kind, counts.Present(), counts.Recorded, counts.Missing)))
+		default:
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: %d/%d present (%d missing, %d quarantined)", kind, counts.Present(), counts.Recorded, counts.Missing, counts.Quarantined)))
+		}
+		if counts.SizeMismatch > 0 {
+			fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: %d with the wrong size", kind, counts.SizeMismatch)))
+			mismatched += counts.SizeMismatch
+		}
 	}
 
-	for _, kind := range kinds {
-		rows, err := q.ListBlobsByKind(ctx, string(kind))
-		if err != nil {
-			fmt.Println(errStyle.Render(fmt.Sprintf("  ✗ %s: failed to list blobs", kind)))
-			fmt.Println(subtleStyle.Render("    " + err.Error()))
+	if doctorRehash {
+		fmt.Println()
+		corrupted := 0
+		for _, kind := range modctl.BlobKinds {
+			n, err := rehashBlobs(ctx, c, kind)
+			if err != nil {
+				return err
+			}
+			corrupted += n
+		}
+		if corrupted > 0 {
 			fmt.Println()
-			return fmt.Errorf("list blobs kind=%s: %w", kind, err)
+			return fmt.Errorf("found %d corrupted blob(s) (moved to %s)", corrupted, viper.GetString("quarantine_dir"))
 		}
+	} else if mismatched > 0 {
+		fmt.Println(ui.Subtle.Render("    run `modctl doctor --recheck` to find and quarantine the corrupted blobs"))
+		fmt.Println()
+		return fmt.Errorf("found %d blob(s) with the wrong size", mismatched)
+	}
 
-		var missing int
-		for _, b := range rows {
-			path, perr := bs.PathFor(kind, b.Sha256)
-			if perr != nil {
-				return fmt.Errorf("derive blob path kind=%s sha=%s: %w", kind, b.Sha256, perr)
-			}
+	fmt.Println()
 
-			st, serr := os.Stat(path)
-			if serr != nil {
-				if errors.Is(serr, os.ErrNotExist) {
-					// TODO: there should be a way to surface to the user
-					//       _which_ blobs are missing (eg original filename or
-					//       which games a blob is associated with)
-					missing++
-					continue
-				}
-				return fmt.Errorf("stat blob kind=%s sha=%s path=%s: %w", kind, b.Sha256, path, serr)
-			}
+	return nil
+}
 
-			// size sanity: if it exists but size differs, something is wrong
-			if st.Size() != b.SizeBytes {
-				return fmt.Errorf(
-					"blob size mismatch kind=%s sha=%s path=%s db=%d disk=%d",
-					kind, b.Sha256, path, b.SizeBytes, st.Size(),
-				)
-			}
-		}
+// checkConsistency cross-checks active.json, profiles, and profile items
+// against the database and blob store and prints a hint for every problem.
+func checkConsistency(ctx context.Context) error {
+	fmt.Println(ui.Header.Render("State Consistency Checks"))
+	fmt.Println()
 
+	c, err := modctl.OpenExisting()
+	if err != nil {
+		fmt.Println(ui.Err.Render("  ✗ could not open database"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
+		fmt.Println()
+		return fmt.Errorf("cannot open database: %w", err)
+	}
+	defer c.Close()
+
+	issues, err := c.CheckConsistency(ctx)
+	if err != nil {
+		fmt.Println(ui.Err.Render("  ✗ could not load state"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
+		fmt.Println()
+		return err
+	}
+
+	if len(issues) == 0 {
+		fmt.Println(ui.OK.Render("  ✓ active.json, profiles, and profile items are consistent"))
+		fmt.Println()
+		return nil
+	}
+
+	for _, is := range issues {
+		fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: %s", is.Check, is.Problem)))
+		fmt.Println(ui.Subtle.Render("    " + is.Hint))
+	}
+	fmt.Println()
+
+	return fmt.Errorf("found %d state consistency problem(s)", len(issues))
+}
+
+// checkFilesystems reports what the filesystems of the blob store and the
+// install targets support. It only fails if the database can't be read.
+func checkFilesystems(ctx context.Context) error {
+	fmt.Println(ui.Header.Render("Filesystem Checks"))
+	if readonly.Enabled() {
+		fmt.Println(ui.Subtle.Render("  skipped: probing writes test files (--read-only)"))
+		fmt.Println()
+		return nil
+	}
+	fmt.Println(ui.Subtle.Render("  hardlinks and reflinks are probed from tmp/ (where apply extracts archives)"))
+	fmt.Println()
+
+	c, err := modctl.OpenExisting()
+	if err != nil {
+		fmt.Println(ui.Err.Render("  ✗ could not open database"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
+		fmt.Println()
+		return fmt.Errorf("cannot open database: %w", err)
+	}
+	defer c.Close()
+
+	reports, err := c.ProbeFilesystems(ctx)
+	for _, fr := range reports {
 		switch {
-		case len(rows) == 0:
-			fmt.Println(okStyle.Render(fmt.Sprintf("  ✓ %s: no blobs recorded", kind)))
-		case missing == 0:
-			fmt.Println(okStyle.Render(fmt.Sprintf("  ✓ %s: %d/%d present", kind, len(rows), len(rows))))
+		case fr.Error != "":
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: could not probe (%s)", fr.Name, fr.Path)))
+			fmt.Println(ui.Subtle.Render("    " + fr.Error))
+			continue
+		case !fr.CaseSensitive:
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: case-insensitive (%s)", fr.Name, fr.Path)))
 		default:
-			fmt.Println(warnStyle.Render(fmt.Sprintf("  ⚠ %s: %d/%d present (%d missing)", kind, len(rows)-missing, len(rows), missing)))
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s (%s)", fr.Name, fr.Path)))
 		}
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("    hardlinks: %s  reflinks: %s  symlinks: %s  case-sensitive: %s  windows names: %s",
+			yesNo(fr.Hardlinks), yesNo(fr.Reflinks), yesNo(fr.Symlinks), yesNo(fr.CaseSensitive), yesNo(fr.WindowsNames))))
 	}
+	if err != nil {
+		fmt.Println(ui.Err.Render("  ✗ could not list install targets"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
+		fmt.Println()
+		return err
+	}
+	fmt.Println()
 
-	if doctorRehash {
+	return nil
+}
+
+// checkLeftovers lists what other mod managers left in the targets of every
+// game install. It only fails if the database can't be read.
+func checkLeftovers(ctx context.Context) error {
+	fmt.Println(ui.Header.Render("Other Mod Managers"))
+	fmt.Println()
+
+	c, err := modctl.OpenExisting()
+	if err != nil {
+		fmt.Println(ui.Err.Render("  ✗ could not open database"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
 		fmt.Println()
-		for _, kind := range kinds {
-			if err := rehashBlobs(ctx, q, bs, kind, subtleStyle); err != nil {
-				return err
+		return fmt.Errorf("cannot open database: %w", err)
+	}
+	defer c.Close()
+
+	reports, err := c.ScanAllLeftovers(ctx)
+	for _, lr := range reports {
+		switch {
+		case lr.Error != "":
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: could not scan", lr.Game)))
+			fmt.Println(ui.Subtle.Render("    " + lr.Error))
+		case len(lr.Leftovers) == 0:
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s: nothing left by other mod managers", lr.Game)))
+		default:
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %s: %d leftover(s) (see `modctl games scan-orphans`)",
+				lr.Game, len(lr.Leftovers))))
+			for _, l := range lr.Leftovers {
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf("    %s %s: %s", l.Manager, l.Kind, l.String())))
+				fmt.Println(ui.Subtle.Render("      → " + l.Suggestion))
 			}
 		}
 	}
-
+	if err != nil {
+		fmt.Println(ui.Err.Render("  ✗ could not list game installs"))
+		fmt.Println(ui.Subtle.Render("    " + err.Error()))
+		fmt.Println()
+		return err
+	}
 	fmt.Println()
 
 	return nil
 }
 
-func rehashBlobs(
-	ctx context.Context,
-	q *dbq.Queries,
-	bs blobstore.Store,
-	kind blobstore.Kind,
-	subtleStyle lipgloss.Style,
-) error {
-	blobs, err := q.ListBlobsByKind(ctx, string(kind))
+// rehashBlobs hashes every blob of a kind again, quarantines the corrupted
+// ones, and returns how many were corrupted.
+func rehashBlobs(ctx context.Context, c *modctl.Client, kind string) (int, error) {
+	blobs, err := c.Blobs(ctx, kind)
 	if err != nil {
-		return fmt.Errorf("list blobs kind=%s: %w", kind, err)
+		return 0, err
 	}
 
 	total := len(blobs)
 	if total == 0 {
-		fmt.Println(subtleStyle.Render(fmt.Sprintf("  %s: (no blobs)", kind)))
-		return nil
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %s: (no blobs)", kind)))
+		return 0, nil
 	}
 
-	now := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
-	buf := make([]byte, 1024*1024) // 1MiB
-
 	var hashed int
 	var skippedMissing int
+	var skippedQuarantined int
+	var corrupted []*modctl.Quarantine
+	// corrupted blobs in the shared archive store can't be quarantined
+	var sharedCorrupted []string
 
 	label := fmt.Sprintf("  %s: rehash", kind)
 	// Print an initial line so \r updates have something to overwrite
@@ -575,79 +706,363 @@ func rehashBlobs(
 		select {
 		case <-ctx.Done():
 			fmt.Print("\n")
-			return ctx.Err()
+			return 0, ctx.Err()
 		default:
 		}
 
 		// Progress update (overwrite same line).
 		fmt.Printf("\r%s (%d/%d)", label, i+1, total)
 
-		path, perr := bs.PathFor(kind, b.Sha256)
-		if perr != nil {
+		// already quarantined (its file is gone)
+		if b.QuarantinedAt != "" {
+			skippedQuarantined++
+			continue
+		}
+
+		// keep going after a corrupted blob, the whole point of rehashing
+		// is to find all of them
+		problem, q, err := c.VerifyBlob(ctx, b, nil)
+		switch {
+		case err != nil:
+			fmt.Print("\n")
+			return 0, fmt.Errorf("rehash blob kind=%s sha=%s: %w", kind, b.SHA256, err)
+		case problem == nil:
+			hashed++
+		case q != nil:
+			corrupted = append(corrupted, q)
+		case errors.Is(problem, os.ErrNotExist):
+			skippedMissing++
+		case errors.Is(problem, modctl.ErrCorrupt):
+			sharedCorrupted = append(sharedCorrupted, problem.Error())
+		default:
 			fmt.Print("\n")
-			return fmt.Errorf("derive blob path kind=%s sha=%s: %w", kind, b.Sha256, perr)
+			return 0, fmt.Errorf("rehash blob kind=%s sha=%s: %w", kind, b.SHA256, problem)
 		}
+	}
 
-		st, serr := os.Stat(path)
-		if serr != nil {
-			if errors.Is(serr, os.ErrNotExist) {
-				skippedMissing++
-				continue
+	// Finish the progress line and print a summary
+	fmt.Print("\r") // return to start of line
+	fmt.Printf("%s (%d/%d)", label, total, total)
+	fmt.Print("\n")
+	if skippedMissing > 0 {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("    skipped %d missing blobs", skippedMissing)))
+	}
+	if skippedQuarantined > 0 {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("    skipped %d quarantined blobs", skippedQuarantined)))
+	}
+	fmt.Println(ui.Subtle.Render(fmt.Sprintf("    verified %d blobs", hashed)))
+
+	for _, q := range corrupted {
+		fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s %s is corrupted", kind, q.SHA256[:12])))
+		printQuarantine(q)
+	}
+	for _, msg := range sharedCorrupted {
+		fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ shared %s is corrupted", kind)))
+		fmt.Println(ui.Subtle.Render("    " + msg))
+		fmt.Println(ui.Subtle.Render("    modctl doesn't change shared_archives_dir, whoever manages it has to replace it"))
+	}
+
+	return len(corrupted) + len(sharedCorrupted), nil
+}
+
+// doctorReport is the machine-readable version of the doctor checks (see
+// doctor --json and bugreport).
+type doctorReport struct {
+	OK          bool                      `json:"ok"`
+	Database    doctorDatabaseReport      `json:"database"`
+	Paths       []doctorPathReport        `json:"paths"`
+	Bsdtar      doctorBsdtarReport        `json:"bsdtar"`
+	Tools       []internal.ToolStatus     `json:"tools"`
+	Blobs       []doctorBlobReport        `json:"blobs,omitempty"`
+	Consistency []modctl.ConsistencyIssue `json:"consistency,omitempty"`
+	Filesystems []modctl.FilesystemReport `json:"filesystems,omitempty"`
+	Leftovers   []modctl.GameLeftovers    `json:"leftovers,omitempty"`
+}
+
+type doctorDatabaseReport struct {
+	Path          string   `json:"path"`
+	Exists        bool     `json:"exists"`
+	Usable        bool     `json:"usable"`
+	SchemaVersion int64    `json:"schema_version"`
+	TargetVersion int64    `json:"target_version"`
+	Pending       bool     `json:"pending_migrations"`
+	Check         string   `json:"check,omitempty"`
+	CheckOK       bool     `json:"check_ok"`
+	Problems      []string `json:"problems,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+type doctorPathReport struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Exists   bool   `json:"exists"`
+	Writable bool   `json:"writable"`
+
+	// not on the same filesystem as tmp_dir (the stores only)
+	CrossDevice bool `json:"cross_device,omitempty"`
+	// the type of the network filesystem that it's on (e.g., nfs or smb)
+	Network string `json:"network,omitempty"`
+	// size of the leftover temporary files (tmp_dir only)
+	Reclaimable int64 `json:"reclaimable,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+type doctorBsdtarReport struct {
+	Search      string `json:"search"`
+	Path        string `json:"path,omitempty"`
+	Version     string `json:"version,omitempty"`
+	ArchiveTest bool   `json:"archive_test"`
+	Error       string `json:"error,omitempty"`
+}
+
+type doctorBlobReport struct {
+	Kind         string `json:"kind"`
+	Recorded     int    `json:"recorded"`
+	Missing      int    `json:"missing"`
+	Quarantined  int    `json:"quarantined"`
+	SizeMismatch int    `json:"size_mismatch"`
+	Error        string `json:"error,omitempty"`
+}
+
+// collectDoctorReport runs the same checks as the interactive doctor but
+// never prints anything and keeps going after a failure so that the report is
+// as complete as possible.
+func collectDoctorReport(ctx context.Context) doctorReport {
+	r := doctorReport{OK: true}
+	fail := func(msg *string, err error) {
+		*msg = err.Error()
+		r.OK = false
+	}
+
+	// database
+	r.Database.Path = viper.GetString("database")
+	if info, err := os.Stat(r.Database.Path); err != nil {
+		fail(&r.Database.Error, err)
+	} else if info.IsDir() {
+		fail(&r.Database.Error, fmt.Errorf("database path is a directory"))
+	} else {
+		r.Database.Exists = true
+	}
+
+	var c *modctl.Client
+	if r.Database.Exists {
+		var err error
+		c, err = modctl.OpenExisting()
+		if err != nil {
+			fail(&r.Database.Error, err)
+		} else {
+			defer c.Close()
+		}
+	}
+
+	if c != nil {
+		if err := c.Ping(ctx); err != nil {
+			fail(&r.Database.Error, err)
+		} else {
+			r.Database.Usable = true
+		}
+	}
+
+	if r.Database.Usable {
+		var err error
+		r.Database.SchemaVersion, r.Database.TargetVersion, err = c.Migrations(ctx)
+		if err != nil {
+			fail(&r.Database.Error, err)
+		}
+		r.Database.Pending = r.Database.SchemaVersion < r.Database.TargetVersion
+
+		r.Database.Check = "quick_check"
+		if deepCheck {
+			r.Database.Check = "integrity_check"
+		}
+
+		problems, err := c.IntegrityProblems(ctx, deepCheck)
+		if err != nil {
+			fail(&r.Database.Error, err)
+		} else {
+			r.Database.Problems = problems
+			r.Database.CheckOK = len(problems) == 0
+			if !r.Database.CheckOK {
+				r.OK = false
 			}
-			fmt.Print("\n")
-			return fmt.Errorf("stat blob kind=%s sha=%s path=%s: %w", kind, b.Sha256, path, serr)
 		}
-		if st.Size() != b.SizeBytes {
-			fmt.Print("\n")
-			return fmt.Errorf(
-				"blob size mismatch kind=%s sha=%s path=%s db=%d disk=%d",
-				kind, b.Sha256, path, b.SizeBytes, st.Size(),
-			)
+	}
+
+	// state directories
+	for _, key := range []string{"archives_dir", "backups_dir", "overrides_dir", "tmp_dir"} {
+		pr := doctorPathReport{
+			Name: strings.TrimSuffix(key, "_dir"),
+			Path: viper.GetString(key),
 		}
 
-		f, err := os.Open(path)
+		if info, err := os.Stat(pr.Path); err != nil {
+			fail(&pr.Error, err)
+		} else if !info.IsDir() {
+			fail(&pr.Error, fmt.Errorf("not a directory"))
+		} else {
+			pr.Exists = true
+
+			// not tested (so not reported as writable) with --read-only
+			if !readonly.Enabled() {
+				testFile := filepath.Join(pr.Path, ".modctl-doctor-write-test")
+				if err := os.WriteFile(testFile, []byte("ok"), 0o600); err != nil {
+					fail(&pr.Error, err)
+				} else {
+					_ = os.Remove(testFile)
+					pr.Writable = true
+				}
+			}
+
+			pr.Network = fscaps.NetworkFS(pr.Path)
+			if key != "tmp_dir" {
+				pr.CrossDevice = crossDevice(pr.Path, viper.GetString("tmp_dir"))
+			} else {
+				_, pr.Reclaimable = reclaimableTmp(pr.Path)
+			}
+		}
+
+		r.Paths = append(r.Paths, pr)
+	}
+
+	// bsdtar
+	r.Bsdtar.Search = viper.GetString("bsdtar")
+	if path, err := exec.LookPath(r.Bsdtar.Search); err != nil {
+		fail(&r.Bsdtar.Error, err)
+	} else {
+		r.Bsdtar.Path = path
+
+		cmdCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		defer cancel()
+
+		out, err := exec.CommandContext(cmdCtx, path, "--version").CombinedOutput()
 		if err != nil {
-			fmt.Print("\n")
-			return fmt.Errorf("open blob kind=%s sha=%s path=%s: %w", kind, b.Sha256, path, err)
+			fail(&r.Bsdtar.Error, err)
+		} else {
+			r.Bsdtar.Version = strings.TrimSpace(string(out))
+
+			if err := testBsdtarSample(cmdCtx, path); err != nil {
+				fail(&r.Bsdtar.Error, err)
+			} else {
+				r.Bsdtar.ArchiveTest = true
+			}
 		}
+	}
 
-		h := sha256.New()
-		_, cerr := blobstore.CopyWithContext(ctx, h, f, buf)
-		_ = f.Close()
-		if cerr != nil {
-			fmt.Print("\n")
-			return fmt.Errorf("hash blob kind=%s sha=%s path=%s: %w", kind, b.Sha256, path, cerr)
+	// optional tools (informational)
+	r.Tools = internal.CheckTools()
+
+	// blobs (presence and size only)
+	if r.Database.Usable {
+		for _, kind := range modctl.BlobKinds {
+			br := doctorBlobReport{Kind: kind}
+
+			counts, err := c.CountBlobs(ctx, kind)
+			if err != nil {
+				fail(&br.Error, err)
+				r.Blobs = append(r.Blobs, br)
+				continue
+			}
+
+			br.Recorded = counts.Recorded
+			br.Missing = counts.Missing
+			br.Quarantined = counts.Quarantined
+			br.SizeMismatch = counts.SizeMismatch
+			if br.SizeMismatch > 0 {
+				r.OK = false
+			}
+
+			r.Blobs = append(r.Blobs, br)
 		}
 
-		sumHex := hex.EncodeToString(h.Sum(nil))
-		if sumHex != b.Sha256 {
-			fmt.Print("\n")
-			return fmt.Errorf(
-				"blob hash mismatch kind=%s expected=%s got=%s path=%s",
-				kind, b.Sha256, sumHex, path,
-			)
+		issues, err := c.CheckConsistency(ctx)
+		if err != nil {
+			fail(&r.Database.Error, err)
+		} else {
+			r.Consistency = issues
+			if len(r.Consistency) > 0 {
+				r.OK = false
+			}
 		}
 
-		// only after a successful rehash do we update verified_at
-		if err := q.TouchBlobVerifiedAt(ctx, dbq.TouchBlobVerifiedAtParams{
-			VerifiedAt: sql.NullString{String: now, Valid: true},
-			Sha256:     b.Sha256,
-		}); err != nil {
-			fmt.Print("\n")
-			return fmt.Errorf("update verified_at sha=%s: %w", b.Sha256, err)
+		if !readonly.Enabled() {
+			fs, err := c.ProbeFilesystems(ctx)
+			if err != nil {
+				fail(&r.Database.Error, err)
+			}
+			r.Filesystems = fs
 		}
 
-		hashed++
+		leftovers, err := c.ScanAllLeftovers(ctx)
+		if err != nil {
+			fail(&r.Database.Error, err)
+		}
+		r.Leftovers = leftovers
 	}
 
-	// Finish the progress line and print a summary
-	fmt.Print("\r") // return to start of line
-	fmt.Printf("%s (%d/%d)", label, total, total)
-	fmt.Print("\n")
-	if skippedMissing > 0 {
-		fmt.Println(subtleStyle.Render(fmt.Sprintf("    skipped %d missing blobs", skippedMissing)))
+	return r
+}
+
+// testBsdtarSample lists the embedded sample archive with bsdtar.
+func testBsdtarSample(ctx context.Context, bsdtar string) error {
+	tmpFile, err := os.CreateTemp("", "modctl-bsdtar-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = tmpFile.Write(SampleTarGz)
+	tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write sample archive: %w", err)
+	}
+
+	out, err := exec.CommandContext(ctx, bsdtar, "-t", "-f", tmpPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("bsdtar failed to list sample archive: %w", err)
+	}
+
+	if strings.TrimSpace(string(out)) != "hello.txt" {
+		return fmt.Errorf("archive contents incorrect")
 	}
-	fmt.Println(subtleStyle.Render(fmt.Sprintf("    verified %d blobs", hashed)))
 
 	return nil
 }
+
+// reclaimableTmp returns how many leftover temporary files are in the tmp
+// dir and their size. Doctor doesn't take the state lock so some of them could
+// belong to a command that is running right now.
+func reclaimableTmp(dir string) (int, int64) {
+	entries, err := internal.TmpEntries(dir, 0)
+	if err != nil {
+		return 0, 0
+	}
+
+	var size int64
+	for _, e := range entries {
+		size += e.Size
+	}
+	return len(entries), size
+}
+
+// crossDevice reports whether path is on a different filesystem than tmp
+// (false if that can't be found out).
+func crossDevice(path, tmp string) bool {
+	a, err := fscaps.Device(path)
+	if err != nil {
+		return false
+	}
+	b, err := fscaps.Device(tmp)
+	if err != nil {
+		return false
+	}
+	return a != b
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
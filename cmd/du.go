@@ -0,0 +1,235 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	duGame string
+	duTop  int64
+)
+
+var duCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Show how much disk space modctl is using",
+	Long: `Show the disk space used by modctl's stores.
+
+Without flags this prints:
+  - the size of each blob store (archives, backups, overrides)
+  - how much space is saved by deduplication (blobs that are referenced more
+    than once are only stored once)
+  - how much space could be reclaimed by garbage collection (blobs that
+    nothing references anymore)
+  - the size of the database, scratch space, and HTTP cache
+  - the space used by each game install
+
+Use --game to break a single game down by mod, and --top N to list the N
+largest mods (across all games, or within --game).
+
+Sizes per game and per mod count every blob they reference, so blobs that
+are shared between games or mods are counted for each of them.`,
+	Args:         cobra.ExactArgs(0),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		var game *modctl.Game
+		if duGame != "" {
+			gi, err := c.Game(ctx, duGame)
+			if err != nil {
+				return err
+			}
+			game = &gi
+		}
+
+		if duTop < 0 {
+			return fmt.Errorf("--top must be positive")
+		}
+
+		row := func(label string, bytes int64, extra string) {
+			fmt.Printf("  %-28s %12s", label, internal.FormatBytes(bytes))
+			if extra != "" {
+				fmt.Print(ui.Subtle.Render("  " + extra))
+			}
+			fmt.Println()
+		}
+
+		printMods := func(title string, limit int64) error {
+			mods, err := c.ModUsage(ctx, game, limit)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(ui.Header.Render(title))
+			if len(mods) == 0 {
+				fmt.Println(ui.Subtle.Render("  (no mods)"))
+			}
+			for _, m := range mods {
+				extra := fmt.Sprintf("id=%d  versions=%d", m.PageID, m.Versions)
+				if game == nil {
+					extra += "  game=" + m.Game
+				}
+				row(truncate(m.Name, 28), m.Bytes, extra)
+			}
+			fmt.Println()
+
+			return nil
+		}
+
+		// a single game: its totals and every mod (or the top N)
+		if game != nil {
+			games, err := c.GameUsage(ctx)
+			if err != nil {
+				return err
+			}
+			for _, g := range games {
+				if g.ID != game.ID {
+					continue
+				}
+				fmt.Println(ui.Header.Render(fmt.Sprintf("%s (%s)", g.DisplayName, g.Selector())))
+				row("archives", g.ArchiveBytes, fmt.Sprintf("%d mods", g.Mods))
+				row("backups", g.BackupBytes, "")
+				row("overrides", g.OverrideBytes, "")
+				fmt.Println()
+			}
+
+			title := "Mods"
+			limit := int64(0)
+			if duTop > 0 {
+				title = fmt.Sprintf("Largest %d mods", duTop)
+				limit = duTop
+			}
+			return printMods(title, limit)
+		}
+
+		if duTop > 0 {
+			return printMods(fmt.Sprintf("Largest %d mods", duTop), duTop)
+		}
+
+		kinds, err := c.BlobUsage(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Header.Render("Blob stores"))
+		var total, unrefBlobs, unrefBytes int64
+		for _, k := range kinds {
+			row(k.Kind+"s", k.Bytes, fmt.Sprintf("%d blobs", k.Blobs))
+			total += k.Bytes
+			unrefBlobs += k.UnreferencedBlobs
+			unrefBytes += k.UnreferencedBytes
+		}
+		if len(kinds) == 0 {
+			fmt.Println(ui.Subtle.Render("  (no blobs)"))
+		}
+		row("total", total, "")
+		fmt.Println()
+
+		dedup, err := c.DedupSavings(ctx)
+		if err != nil {
+			return err
+		}
+		row("saved by dedup", dedup.SavedBytes,
+			fmt.Sprintf("%d blobs referenced more than once", dedup.SharedBlobs))
+		row("reclaimable by GC", unrefBytes,
+			fmt.Sprintf("%d unreferenced blobs", unrefBlobs))
+		fmt.Println()
+
+		fmt.Println(ui.Header.Render("Other"))
+		if st, err := os.Stat(viper.GetString("database")); err == nil {
+			row("database", st.Size(), viper.GetString("database"))
+		}
+		for _, d := range []struct{ label, key string }{
+			{"scratch space", "tmp_dir"},
+			{"http cache", "http_cache_dir"},
+			{"apply reports", "reports_dir"},
+		} {
+			size, err := internal.DirSize(viper.GetString(d.key))
+			if err != nil {
+				return fmt.Errorf("size of %s: %w", d.label, err)
+			}
+			row(d.label, size, viper.GetString(d.key))
+		}
+		fmt.Println()
+
+		games, err := c.GameUsage(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Header.Render("Games"))
+		if len(games) == 0 {
+			fmt.Println(ui.Subtle.Render("  (no games)"))
+		}
+		for _, g := range games {
+			row(fmt.Sprintf("%d  %s", g.ID, truncate(g.DisplayName, 24)),
+				g.ArchiveBytes+g.BackupBytes+g.OverrideBytes,
+				fmt.Sprintf("mods=%d  archives=%s  backups=%s  overrides=%s",
+					g.Mods,
+					internal.FormatBytes(g.ArchiveBytes),
+					internal.FormatBytes(g.BackupBytes),
+					internal.FormatBytes(g.OverrideBytes)))
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	rootCmd.AddCommand(duCmd)
+
+	duCmd.Flags().StringVarP(&duGame, "game", "g", "",
+		"Break down the disk usage of a single game")
+	duCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	duCmd.Flags().Int64VarP(&duTop, "top", "n", 0,
+		"Only show the N largest mods")
+}
+
+// truncate shortens s to at most n runes, marking that it was cut.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
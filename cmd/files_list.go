@@ -0,0 +1,161 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	filesListGame   string
+	filesListFormat string
+)
+
+var filesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the files that are deployed into the game",
+	Long: `List every file that modctl deployed into the game install (by applying a
+profile) with the mod version or override that it comes from.
+
+Files that were modified or deleted by something else since they were
+deployed are marked with ! if ` + "`modctl watch`" + ` noticed it.
+
+With --format csv (or tsv) every file is a record (with its size in bytes and
+full sha256), for spreadsheets.
+
+The current active game is used unless --game is provided.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := ui.CheckFormat(filesListFormat); err != nil {
+			return err
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, filesListGame)
+		if err != nil {
+			return err
+		}
+
+		files, err := c.InstalledFiles(ctx, gi)
+		if err != nil {
+			return err
+		}
+
+		if filesListFormat != ui.FormatText {
+			return writeFilesRecords(files)
+		}
+
+		if len(files) == 0 {
+			fmt.Println(ui.Subtle.Render("No deployed files"))
+			return nil
+		}
+
+		fmt.Println(ui.Header.Render("Deployed files of " + gi.DisplayName))
+		fmt.Println()
+
+		var total int64
+		for _, f := range files {
+			total += f.Size
+
+			prefix := "  "
+			if f.DriftKind != "" {
+				prefix = ui.Warn.Render("! ")
+			}
+			fmt.Printf("%s%s  %s\n", prefix, f, ui.Subtle.Render(
+				fmt.Sprintf("(%s, %s)", installedFileOwner(f), internal.FormatBytes(f.Size))))
+		}
+
+		fmt.Println()
+		fmt.Printf("%d files, %s\n", len(files), internal.FormatBytes(total))
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+// installedFileOwner describes where a deployed file comes from.
+func installedFileOwner(f modctl.InstalledFile) string {
+	if f.Override {
+		return "override"
+	}
+	owner := fmt.Sprintf("%s / %s (v%d)", f.ModName, f.FileLabel, f.VersionID)
+	if f.VersionString != "" {
+		owner += " " + f.VersionString
+	}
+	return owner
+}
+
+// writeFilesRecords writes the deployed files of a game as csv or tsv.
+func writeFilesRecords(files []modctl.InstalledFile) error {
+	records := make([][]string, 0, len(files))
+	for _, f := range files {
+		records = append(records, []string{
+			f.Target,
+			f.RelPath,
+			strconv.FormatInt(f.Size, 10),
+			f.SHA256,
+			f.Profile,
+			f.ModName,
+			f.FileLabel,
+			intField(f.VersionID),
+			f.VersionString,
+			strconv.FormatBool(f.Override),
+			f.InstalledAt,
+			f.VerifiedAt,
+			f.DriftKind,
+			f.DriftedAt,
+		})
+	}
+	return ui.WriteRecords(os.Stdout, filesListFormat, []string{
+		"target", "relpath", "size_bytes", "sha256", "profile", "mod", "file",
+		"version_id", "version", "override", "installed_at", "verified_at",
+		"drift_kind", "drifted_at",
+	}, records)
+}
+
+func init() {
+	filesCmd.AddCommand(filesListCmd)
+
+	filesListCmd.Flags().StringVarP(&filesListGame, "game", "g", "",
+		"Override the currently active game")
+	filesListCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	addFormatFlag(filesListCmd, &filesListFormat)
+}
@@ -0,0 +1,146 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gamesDetectTargetsYes  bool
+	gamesDetectTargetsOnly []string
+)
+
+var gamesDetectTargetsCmd = &cobra.Command{
+	Use:   "detect-targets <game>",
+	Short: "Propose targets for the mod folders of a game",
+	Long: `Look for the directories that mod loaders and engines conventionally load
+mods from in the install root of a game that modctl doesn't know, and propose
+them as targets:
+
+  mods                 a Mods directory
+  bepinex_plugins      BepInEx/plugins (and bepinex_patchers)
+  melonloader_plugins  Plugins, if MelonLoader is installed
+  addons, custom       <game>/addons and <game>/custom of source engine games
+
+Directories that are only looked for by the mod loader (e.g., MelonLoader's
+Plugins) are proposed even if they don't exist yet. Nothing is created
+without --yes: it only shows the proposals. Pass --only to create some of
+them.
+
+Games with a dedicated handler (e.g., unreal engine games, whose pak and
+UE4SS mods are put into place automatically) or default targets don't need
+this (see also the target_templates config option).
+
+The game can be given as its install id or a selector (e.g., steam:1091500).`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.GameInstallSelectors(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if gamesDetectTargetsYes {
+			l, err := modctl.LockState(cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+			defer l.Release()
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := c.Game(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		proposed, err := c.ProposeTargets(ctx, gi)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range gamesDetectTargetsOnly {
+			if !slices.ContainsFunc(proposed, func(f modctl.ModFolder) bool { return f.Target == name }) {
+				return fmt.Errorf("%s is not one of the proposed targets", name)
+			}
+		}
+		if len(gamesDetectTargetsOnly) > 0 {
+			proposed = slices.DeleteFunc(proposed, func(f modctl.ModFolder) bool {
+				return !slices.Contains(gamesDetectTargetsOnly, f.Target)
+			})
+		}
+
+		if len(proposed) == 0 {
+			fmt.Printf("No new mod folders found in %s\n", gi.InstallRoot)
+			return nil
+		}
+
+		for _, f := range proposed {
+			line := fmt.Sprintf("  %s: %s", f.Target, f.RelPath)
+			note := f.Reason
+			if f.Missing {
+				note += ", doesn't exist yet"
+			}
+			fmt.Println(line + ui.Subtle.Render(" ("+note+")"))
+		}
+
+		if !gamesDetectTargetsYes {
+			fmt.Println(ui.Subtle.Render("Nothing was created; run again with --yes to create these targets (or --only to pick some)"))
+			return nil
+		}
+
+		if err := c.CreateTargets(ctx, gi, proposed); err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("Created %d target(s) for %s", len(proposed), gi.DisplayName)),
+			fmt.Sprintf("create %d target(s) for %s", len(proposed), gi.DisplayName))
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	gamesCmd.AddCommand(gamesDetectTargetsCmd)
+
+	gamesDetectTargetsCmd.Flags().BoolVar(&gamesDetectTargetsYes, "yes", false,
+		"Create the proposed targets")
+	gamesDetectTargetsCmd.Flags().StringSliceVar(&gamesDetectTargetsOnly, "only", nil,
+		"Only these of the proposed targets (by name)")
+}
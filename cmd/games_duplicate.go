@@ -0,0 +1,135 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gamesDuplicateTo       string
+	gamesDuplicateInstance string
+	gamesDuplicateCopy     bool
+)
+
+var gamesDuplicateCmd = &cobra.Command{
+	Use:   "duplicate <game> --to <path>",
+	Short: "Make a copy of a game install to test mods on",
+	Long: `Copy a game install to a new directory and register the copy as another
+instance of the same game (copy, copy_2, ... unless --instance is given), with
+its own default profile, so that an experimental set of mods can be applied to
+it without touching the original.
+
+Files are cloned (copy-on-write) where the filesystem supports it, otherwise
+hardlinked if the copy is on the same filesystem, otherwise copied. modctl
+replaces files instead of writing into them, so applying a profile to the copy
+never changes the original, but a game or a tool that edits a hardlinked file
+in place changes it in both; pass --copy to always copy.
+
+Only the targets below the install root are part of the copy: the proton
+prefix (and with it the documents and appdata targets) would be the original's.
+Files that modctl deployed to the original are copied as they are but are not
+tracked for the copy, so unapply the original first for a clean copy. Use
+` + "`modctl profiles copy`" + ` to bring profiles of the original to the copy.
+
+The game can be given as its install id or a selector (e.g., steam:1091500).`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.GameInstallSelectors(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := c.Game(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		progress, finish := blobProgress("  " + gi.DisplayName)
+		res, err := c.DuplicateGame(ctx, gi, gamesDuplicateTo, modctl.DuplicateOptions{
+			Instance: gamesDuplicateInstance,
+			Copy:     gamesDuplicateCopy,
+			Progress: progress,
+		})
+		finish()
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("cancelled")
+		}
+		if err != nil {
+			return err
+		}
+
+		sel := res.Game.Selector()
+		fmt.Println(ui.OK.Render(fmt.Sprintf("✓ Copied %s to %s as %s", gi.DisplayName, res.Game.InstallRoot, sel)))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %d files (%s): %d cloned, %d hardlinked, %d copied",
+			res.Files, internal.FormatBytes(res.Bytes), res.Reflinked, res.Hardlinked, res.Copied)))
+
+		if res.Hardlinked > 0 {
+			fmt.Println(ui.Warn.Render("  ⚠ hardlinked files are shared with the original until modctl replaces them"))
+		}
+		if res.Untracked > 0 {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"  ⚠ the copy includes %d file(s) deployed to the original, which modctl doesn't track for it",
+				res.Untracked)))
+		}
+		for _, name := range res.Skipped {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"  ⚠ target %s is outside of the install root and was not duplicated", name)))
+		}
+
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  switch to it with `modctl games set-active %s`", sel)))
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	gamesCmd.AddCommand(gamesDuplicateCmd)
+
+	gamesDuplicateCmd.Flags().StringVar(&gamesDuplicateTo, "to", "",
+		"Directory to copy the game to (must not exist yet)")
+	gamesDuplicateCmd.MarkFlagRequired("to")
+	gamesDuplicateCmd.MarkFlagDirname("to")
+
+	gamesDuplicateCmd.Flags().StringVar(&gamesDuplicateInstance, "instance", "",
+		"Instance id of the copy (default: copy, copy_2, ...)")
+
+	gamesDuplicateCmd.Flags().BoolVar(&gamesDuplicateCopy, "copy", false,
+		"Always copy files instead of cloning or hardlinking them")
+}
@@ -20,16 +20,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
-	"go.finelli.dev/util"
 )
 
 var gamesInfoCmd = &cobra.Command{
@@ -54,54 +52,50 @@ provided.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
+		gi, err := c.Game(ctx, args[0])
 		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
+			return err
 		}
 
-		q := dbq.New(db)
-		gi, err := internal.ResolveGameInstallArg(ctx, q, args[0])
+		targets, err := c.Targets(ctx, gi)
 		if err != nil {
 			return err
 		}
 
-		targets, err := q.ListTargetsForGameInstall(ctx, gi.ID)
+		profiles, err := c.Profiles(ctx, gi)
 		if err != nil {
-			return fmt.Errorf("list targets: %w", err)
+			return err
 		}
 
-		profiles, err := q.GetProfilesForGameInstall(ctx, gi.ID)
-		if err != nil {
-			return fmt.Errorf("list profiles: %w", err)
+		active, err := c.ActiveGame(ctx)
+		if err != nil && !errors.Is(err, modctl.ErrNoActiveGame) {
+			return err
 		}
+		isCurrent := active.ID == gi.ID
 
-		a, err := state.LoadActive()
-		if err != nil {
-			return err
+		var account modctl.SteamAccount
+		var accountErr error
+		if gi.RunsInSteam {
+			account, accountErr = c.SteamAccount(ctx, gi)
 		}
-		isCurrent := a.ActiveGameInstallID == gi.ID
 
-		fmt.Println(renderGameInfo(gi, targets, profiles, isCurrent))
+		fmt.Println(renderGameInfo(gi, targets, profiles, isCurrent, account, accountErr))
 		return nil
 	},
+	Annotations: supportsDryRun,
 }
 
 func init() {
 	gamesCmd.AddCommand(gamesInfoCmd)
 }
 
-func renderGameInfo(gi dbq.GameInstall, targets []dbq.Target, profiles []dbq.Profile, isCurrentContext bool) string {
+func renderGameInfo(gi modctl.Game, targets []modctl.Target, profiles []modctl.Profile, isCurrentContext bool, a modctl.SteamAccount, accountErr error) string {
 	// styles
 	cardBorder := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -140,8 +134,8 @@ func renderGameInfo(gi dbq.GameInstall, targets []dbq.Target, profiles []dbq.Pro
 	inactiveDot := lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("○")
 
 	// Header card
-	fullSel := internal.FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
-	shortSel := internal.ShortSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
+	fullSel := gi.Selector()
+	shortSel := gi.ShortSelector()
 	selText := fullSel
 	if shortSel != fullSel {
 		selText = fmt.Sprintf("%s (short: %s)", fullSel, shortSel)
@@ -161,7 +155,7 @@ func renderGameInfo(gi dbq.GameInstall, targets []dbq.Target, profiles []dbq.Pro
 	b.WriteString("\n")
 
 	// Not present warning
-	if gi.IsPresent == 0 {
+	if !gi.Present {
 		b.WriteString("\n")
 		b.WriteString(warningBanner.Render("⚠  This install is not currently present on disk"))
 		b.WriteString("\n")
@@ -176,13 +170,40 @@ func renderGameInfo(gi dbq.GameInstall, targets []dbq.Target, profiles []dbq.Pro
 	writeKV(&b, "Path:", gi.InstallRoot)
 
 	present := "yes"
-	if gi.IsPresent == 0 {
+	if !gi.Present {
 		present = "no"
 	}
 	writeKV(&b, "Present:", present)
+	if gi.CopyOf != 0 {
+		writeKV(&b, "Copy of:", fmt.Sprintf("%d", gi.CopyOf))
+	}
+	if gi.Flatpak {
+		writeKV(&b, "Flatpak:", "yes (runs in the steam sandbox)")
+	}
 
-	if gi.LastSeenAt.Valid {
-		writeKV(&b, "Last seen:", gi.LastSeenAt.String)
+	if gi.LastSeenAt != "" {
+		writeKV(&b, "Last seen:", gi.LastSeenAt)
+	}
+	if gi.VerifiedVanillaAt != "" {
+		writeKV(&b, "Vanilla:", "verified "+gi.VerifiedVanillaAt)
+	}
+
+	// Steam account (userdata, e.g., cloud saves)
+	if gi.RunsInSteam {
+		b.WriteString("\n" + sectionTitleStyle.Render("Steam account") + "\n")
+		if accountErr != nil {
+			writeKV(&b, "Account:", "unknown: "+accountErr.Error())
+		} else {
+			writeKV(&b, "Account:", fmt.Sprintf("%s  [%d]", a.Name, a.AccountID))
+			userdata := "(none)"
+			if a.Userdata != "" {
+				userdata = a.Userdata
+			}
+			writeKV(&b, "Userdata:", userdata)
+		}
+		if a.Owner != 0 && (accountErr != nil || a.Owner != a.SteamID64) {
+			writeKV(&b, "Owner:", fmt.Sprintf("%d (another account, e.g., Family Sharing)", a.Owner))
+		}
 	}
 
 	// Targets
@@ -192,8 +213,14 @@ func renderGameInfo(gi dbq.GameInstall, targets []dbq.Target, profiles []dbq.Pro
 	} else {
 		for _, t := range targets {
 			b.WriteString("  • " + t.Name + "\n")
-			writeKVIndented(&b, "path:", t.RootPath)
+			writeKVIndented(&b, "path:", t.Root)
+			if t.Template != "" {
+				writeKVIndented(&b, "template:", t.Template)
+			}
 			writeKVIndented(&b, "origin:", t.Origin)
+			if t.DeployMethod != modctl.DeployMethodAuto || t.BackupPolicy != modctl.BackupPolicyBackup {
+				writeKVIndented(&b, "strategy:", t.DeployMethod+", backup "+t.BackupPolicy)
+			}
 		}
 	}
 
@@ -206,20 +233,20 @@ func renderGameInfo(gi dbq.GameInstall, targets []dbq.Target, profiles []dbq.Pro
 			dot := inactiveDot
 			line := "  "
 
-			if p.IsActive != 0 {
+			if p.Active {
 				dot = activeDot
 			}
 
 			line += dot + " " + p.Name
 
-			if util.SqliteIntToBool(p.IsActive) {
+			if p.Active {
 				line += "   " + activeTagStyle.Render("(active)")
 			}
 
 			b.WriteString(inactiveProfileStyle.Render(line) + "\n")
 
-			if p.Description.Valid && strings.TrimSpace(p.Description.String) != "" {
-				writeKVIndentedInactive(&b, "description:", p.Description.String)
+			if strings.TrimSpace(p.Description) != "" {
+				writeKVIndentedInactive(&b, "description:", p.Description)
 			}
 
 			b.WriteString("\n")
@@ -21,18 +21,20 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/charmbracelet/lipgloss/table"
-	"github.com/mfinelli/modctl/dbq"
 	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
-	"go.finelli.dev/util"
 )
 
 var gamesListAll bool
 var gamesListStore string
+var gamesListFormat string
 
 var gamesListCmd = &cobra.Command{
 	Use:   "list",
@@ -42,71 +44,78 @@ var gamesListCmd = &cobra.Command{
 By default, only the active store is included. Use --store to filter by a
 specific store. Or use --all to include games from all stores.
 
+With --format csv (or tsv) every install is a record with its selector, name,
+path, whether it's present, and when it was last seen, for spreadsheets.
+
 (TODO) The active game install (if any) is highlighted.`,
 	Args: cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		err := internal.EnsureDBExists()
-		if err != nil {
+		if err := ui.CheckFormat(gamesListFormat); err != nil {
 			return err
 		}
 
-		db, err := internal.SetupDB()
+		c, err := modctl.Open(ctx)
 		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
+			return err
 		}
-		defer db.Close()
+		defer c.Close()
 
-		err = internal.MigrateDB(ctx, db)
+		all, err := c.Games(ctx)
 		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
+			return err
 		}
 
-		q := dbq.New(db)
-		var games []dbq.GameInstall
-
-		if gamesListAll {
-			games, err = q.ListAllGameInstalls(ctx)
-		} else if gamesListStore != "" {
-			games, err = q.ListGameInstallsByStore(ctx, gamesListStore)
-		} else {
-			a, aerr := state.LoadActive()
-			if aerr != nil {
-				return fmt.Errorf("error getting active store: %w", err)
+		store := gamesListStore
+		if !gamesListAll && store == "" {
+			if store, err = c.ActiveStore(ctx); err != nil {
+				return err
 			}
+		}
 
-			if a.ActiveStoreID != "" {
-				games, err = q.ListGameInstallsByStore(ctx, a.ActiveStoreID)
-			} else {
-				// we default to steam for now since it's the only
-				// store that we support (TODO when we add more stores)
-				games, err = q.ListGameInstallsByStore(ctx, "steam")
+		var games []modctl.Game
+		for _, game := range all {
+			if gamesListAll || game.StoreID == store {
+				games = append(games, game)
 			}
 		}
-		if err != nil {
-			return fmt.Errorf("error listing games: %w", err)
+
+		if gamesListFormat != ui.FormatText {
+			records := make([][]string, 0, len(games))
+			for _, game := range games {
+				records = append(records, []string{
+					strconv.FormatInt(game.ID, 10),
+					internal.FullSelector(game.StoreID, game.StoreGameID, game.InstanceID),
+					game.StoreID,
+					game.StoreGameID,
+					game.InstanceID,
+					game.DisplayName,
+					game.InstallRoot,
+					strconv.FormatBool(game.Present),
+					game.LastSeenAt,
+				})
+			}
+			return ui.WriteRecords(os.Stdout, gamesListFormat, []string{
+				"id", "selector", "store", "store_game_id", "instance", "name",
+				"install_root", "present", "last_seen_at",
+			}, records)
 		}
 
 		rows := [][]string{}
 		for _, game := range games {
 			present := "✗"
-			if util.SqliteIntToBool(game.IsPresent) {
+			if game.Present {
 				present = "✓"
 			}
 
-			lastSeen := ""
-			if game.LastSeenAt.Valid {
-				lastSeen = game.LastSeenAt.String
-			}
-
 			rows = append(rows, []string{
 				fmt.Sprintf(" %d ", game.ID),
 				fmt.Sprintf(" %s ", internal.FullSelector(game.StoreID, game.StoreGameID, game.InstanceID)),
 				fmt.Sprintf(" %s ", game.DisplayName),
 				fmt.Sprintf(" %s ", game.InstallRoot),
 				fmt.Sprintf(" %s ", present),
-				fmt.Sprintf(" %s ", lastSeen),
+				fmt.Sprintf(" %s ", game.LastSeenAt),
 			})
 		}
 
@@ -118,6 +127,7 @@ specific store. Or use --all to include games from all stores.
 
 		return nil
 	},
+	Annotations: supportsDryRun,
 }
 
 func init() {
@@ -134,4 +144,6 @@ func init() {
 		})
 
 	gamesListCmd.MarkFlagsMutuallyExclusive("all", "store")
+
+	addFormatFlag(gamesListCmd, &gamesListFormat)
 }
@@ -20,9 +20,9 @@ package cmd
 
 import (
 	"context"
-	"fmt"
+	"os"
 
-	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
@@ -35,29 +35,23 @@ var gamesRefreshCmd = &cobra.Command{
 This command detects installed games, updates their install paths, and marks
 missing installs as not present.
 
-It is safe to run multiple times.`,
-	Args: cobra.ExactArgs(0),
+It is safe to run multiple times. Only one command that modifies modctl's
+state can run at a time.`,
+	Args:         cobra.ExactArgs(0),
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return err
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		return internal.ScanStores(ctx, db)
+		_, err = c.RefreshGames(ctx, os.Stdout)
+		return err
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
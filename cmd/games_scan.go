@@ -0,0 +1,150 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gamesScanRebaseline bool
+	gamesScanVerify     bool
+	gamesScanAll        bool
+)
+
+// how many user-created/unknown files to list unless --verbose
+const gamesScanListLimit = 50
+
+var gamesScanCmd = &cobra.Command{
+	Use:   "scan [game]",
+	Short: "Find out where the files in a game's directories came from",
+	Long: `Compare the files in the targets of a game install (the game directory and
+any other directories that mods are deployed to) with its baseline, a record
+of what was there before modctl deployed anything, and with what modctl
+deployed. Every file is one of:
+
+  vanilla  there when the baseline was recorded, and unchanged
+  mod      deployed by modctl, and unchanged
+  user     created after the baseline was recorded (by you, the game, or
+           another tool)
+  unknown  changed since it was recorded or deployed (e.g., by a game
+           update), or there is no baseline to compare it to
+
+The baseline is recorded the first time a profile is applied (unless
+baseline_on_apply is false), or by the first scan. Pass --rebaseline to record
+it again, e.g. after a game update.
+
+Files whose size and modification time didn't change aren't hashed again
+unless --verify is given.
+
+The game defaults to the active game.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.GameInstallSelectors(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := argGame(ctx, c, args)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		if gamesScanRebaseline || gi.BaselineScannedAt == "" {
+			fmt.Println(ui.Subtle.Render("Recording the baseline of " + gi.DisplayName + " (this hashes every file)..."))
+			n, err := c.RecordBaseline(ctx, gi)
+			if err != nil {
+				return fmt.Errorf("record baseline: %w", err)
+			}
+			dryrun.Report(os.Stdout,
+				ui.OK.Render(fmt.Sprintf("Recorded the baseline of %s: %d files", gi.DisplayName, n)),
+				fmt.Sprintf("record the baseline of %s: %d files", gi.DisplayName, n))
+			return nil
+		}
+
+		files, err := c.ClassifyFiles(ctx, gi, gamesScanVerify)
+		if err != nil {
+			return err
+		}
+
+		counts := map[modctl.Provenance]int{}
+		var listed []modctl.ClassifiedFile
+		for _, f := range files {
+			counts[f.Provenance]++
+			if gamesScanAll || (f.Provenance != modctl.ProvenanceVanilla && f.Provenance != modctl.ProvenanceMod) {
+				listed = append(listed, f)
+			}
+		}
+
+		fmt.Println(ui.OK.Render(fmt.Sprintf("%s: %d files", gi.DisplayName, len(files))))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %d vanilla, %d mod, %d user, %d unknown (baseline from %s)",
+			counts[modctl.ProvenanceVanilla], counts[modctl.ProvenanceMod],
+			counts[modctl.ProvenanceUser], counts[modctl.ProvenanceUnknown],
+			gi.BaselineScannedAt)))
+
+		for i, f := range listed {
+			if i == gamesScanListLimit && !verbose {
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf("  ... and %d more (pass --verbose to list them all)",
+					len(listed)-i)))
+				break
+			}
+			line := fmt.Sprintf("  %-8s %s", f.Provenance, f.String())
+			if f.Provenance == modctl.ProvenanceUnknown {
+				fmt.Println(ui.Warn.Render(line))
+			} else {
+				fmt.Println(line)
+			}
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	gamesCmd.AddCommand(gamesScanCmd)
+
+	gamesScanCmd.Flags().BoolVar(&gamesScanRebaseline, "rebaseline", false,
+		"Record the baseline again from what is there now")
+	gamesScanCmd.Flags().BoolVar(&gamesScanVerify, "verify", false,
+		"Hash every file instead of trusting unchanged sizes and modification times")
+	gamesScanCmd.Flags().BoolVar(&gamesScanAll, "all", false,
+		"List vanilla and mod files too")
+}
@@ -0,0 +1,123 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var gamesScanOrphansJSON bool
+
+var gamesScanOrphansCmd = &cobra.Command{
+	Use:   "scan-orphans [game]",
+	Short: "Find what other mod managers left in a game's directories",
+	Long: `Look through the targets of a game install for the leftovers of other mod
+managers, which get in the way of (or are overwritten by) what modctl deploys:
+
+  Vortex  deployment manifests (vortex.deployment*.json, with how many of
+          the files that they list are still deployed), staging folders,
+          and backups of the files that it replaced (*.vortex_backup)
+  MO2     portable Mod Organizer 2 instances, the files that tools wrote
+          into their overwrite directory, and files that it hid (*.mohidden)
+
+Every leftover comes with a suggestion: how to clean it up or how to import
+the mods into modctl (modctl mods pack packages a directory as a mod).
+
+Nothing is changed. modctl status and modctl doctor do a quicker version of
+this scan, that only looks a couple of directories deep.
+
+The game defaults to the active game.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.GameInstallSelectors(cmd, toComplete)
+	},
+	Annotations: supportsDryRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := argGame(ctx, c, args)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		leftovers, err := c.ScanLeftovers(ctx, gi, false)
+		if err != nil {
+			return err
+		}
+
+		if gamesScanOrphansJSON {
+			if leftovers == nil {
+				leftovers = []modctl.Leftover{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(leftovers)
+		}
+
+		if len(leftovers) == 0 {
+			fmt.Println(ui.OK.Render(fmt.Sprintf("%s: nothing left by other mod managers", gi.DisplayName)))
+			return nil
+		}
+
+		fmt.Println(ui.Warn.Render(fmt.Sprintf("%s: %d leftover(s) of other mod managers", gi.DisplayName, len(leftovers))))
+		printLeftovers(leftovers)
+
+		return nil
+	},
+}
+
+// printLeftovers lists leftovers of other mod managers with their details
+// and suggestions.
+func printLeftovers(leftovers []modctl.Leftover) {
+	for _, l := range leftovers {
+		fmt.Printf("  %-6s %s: %s\n", l.Manager, l.Kind, l.String())
+		if l.Detail != "" {
+			fmt.Println(ui.Subtle.Render("         " + l.Detail))
+		}
+		fmt.Println(ui.Subtle.Render("         → " + l.Suggestion))
+	}
+}
+
+func init() {
+	gamesCmd.AddCommand(gamesScanOrphansCmd)
+
+	gamesScanOrphansCmd.Flags().BoolVar(&gamesScanOrphansJSON, "json", false,
+		"Print the leftovers as JSON")
+}
@@ -22,10 +22,8 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
@@ -51,52 +49,27 @@ desired instance explicitly.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
+		gi, err := c.Game(ctx, args[0])
 		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
+			return err
 		}
 
-		q := dbq.New(db)
-		gi, err := internal.ResolveGameInstallArg(ctx, q, args[0])
-		if err != nil {
+		if err := c.SetActiveGame(ctx, gi); err != nil {
 			return err
 		}
 
-		return persistActiveGameInstall(gi)
+		fmt.Printf("Active game set to %s (%s)\n", gi.Selector(), gi.DisplayName)
+		return nil
 	},
+	Annotations: mutating,
 }
 
 func init() {
 	gamesCmd.AddCommand(gamesSetActiveCmd)
 }
-
-func persistActiveGameInstall(gi dbq.GameInstall) error {
-	a, err := state.LoadActive()
-	if err != nil {
-		return err
-	}
-
-	fullSel := internal.FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
-
-	a.ActiveStoreID = gi.StoreID // keeps store context in sync
-	a.ActiveGameInstallID = gi.ID
-	a.ActiveGameInstallSelector = fullSel
-
-	if err := state.SaveActive(a); err != nil {
-		return err
-	}
-
-	fmt.Printf("Active game set to %s (%s)\n", fullSel, gi.DisplayName)
-	return nil
-}
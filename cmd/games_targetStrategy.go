@@ -0,0 +1,163 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gamesTargetStrategyGame   string
+	gamesTargetStrategyMethod string
+	gamesTargetStrategyBackup string
+)
+
+var gamesTargetStrategyCmd = &cobra.Command{
+	Use:   "target-strategy <target>",
+	Short: "Show or set how apply deploys to a target",
+	Long: `Show or set how apply puts files in place on a target of the current game, and
+what happens to the files there that modctl didn't deploy, independently of
+the other targets: e.g., copy into the game directory but keep no backups of
+the caches in a config directory.
+
+--method is one of:
+
+  auto      reflink, else hardlink, else copy (what the filesystem supports)
+  copy      always write a copy
+  reflink   clone files (copy-on-write), else copy
+  hardlink  hardlink the files extracted for the apply, else reflink or copy
+  symlink   symlink the files (where the filesystem supports it, else auto)
+  overlay   mount a layer with the files over the target (linux)
+
+Files from the blob store (overrides) are never hardlinked, whatever the
+method. Symlinks point into stores in the data directory, which have to stay
+where they are: the files of mods (and expanded template overrides) are kept
+in extracted_dir for as long as a symlink points to them, other overrides are
+linked to where they're stored. Editing a deployed file through the symlink
+changes what is stored.
+
+An overlay leaves the target itself alone: the files are deployed into a
+layer in overlays_dir that apply mounts over the target with
+overlay_mount_command (fuse-overlayfs unless configured otherwise) and
+unapply unmounts with overlay_unmount_command, so nothing is backed up. What
+the game writes to the target while it's mounted is kept in the upper layer
+next to it, and apply mounts it again (e.g., after a reboot). A target that
+has files deployed can't switch to or from an overlay, and targets per game
+version can't be one.
+
+--backup is one of:
+
+  backup  store files that modctl didn't deploy before replacing them, and
+          restore them on unapply (default)
+  none    replace them without a backup: unapply just removes the deployed
+          file (for files that the game writes again, like caches)
+
+Without flags the current strategy is shown. The next apply uses the new one;
+files that are already deployed stay as they are until they change.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.TargetNames(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		changing := cmd.Flags().Changed("method") || cmd.Flags().Changed("backup")
+		if changing {
+			l, err := modctl.LockState(cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+			defer l.Release()
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, gamesTargetStrategyGame)
+		if err != nil {
+			return err
+		}
+
+		t, err := c.Target(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		if changing {
+			method, policy := t.DeployMethod, t.BackupPolicy
+			if cmd.Flags().Changed("method") {
+				method = strings.ToLower(strings.TrimSpace(gamesTargetStrategyMethod))
+			}
+			if cmd.Flags().Changed("backup") {
+				policy = strings.ToLower(strings.TrimSpace(gamesTargetStrategyBackup))
+			}
+			t, err = c.SetTargetStrategy(ctx, gi, t.Name, method, policy)
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("%s  %s\n", t.Name, ui.Subtle.Render(t.Root))
+		fmt.Printf("  method: %s\n", t.DeployMethod)
+		fmt.Printf("  backup: %s\n", t.BackupPolicy)
+		if t.BackupPolicy == modctl.BackupPolicyNone {
+			fmt.Println(ui.Warn.Render("  ⚠ files that modctl didn't deploy are replaced without a backup"))
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	gamesCmd.AddCommand(gamesTargetStrategyCmd)
+
+	gamesTargetStrategyCmd.Flags().StringVarP(&gamesTargetStrategyGame, "game", "g", "",
+		"Override the currently active game")
+	gamesTargetStrategyCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	gamesTargetStrategyCmd.Flags().StringVar(&gamesTargetStrategyMethod, "method", "",
+		"How files are put in place ("+strings.Join(modctl.DeployMethods, ", ")+")")
+	gamesTargetStrategyCmd.RegisterFlagCompletionFunc("method",
+		cobra.FixedCompletions(modctl.DeployMethods, cobra.ShellCompDirectiveNoFileComp))
+
+	gamesTargetStrategyCmd.Flags().StringVar(&gamesTargetStrategyBackup, "backup", "",
+		"What happens to files that modctl didn't deploy ("+strings.Join(modctl.BackupPolicies, ", ")+")")
+	gamesTargetStrategyCmd.RegisterFlagCompletionFunc("backup",
+		cobra.FixedCompletions(modctl.BackupPolicies, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,205 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var gamesVerifyVanillaSteam bool
+
+// how many changed/missing/extra files to list unless --verbose
+const gamesVerifyVanillaListLimit = 20
+
+var gamesVerifyVanillaCmd = &cobra.Command{
+	Use:   "verify-vanilla [game]",
+	Short: "Check that a game install is vanilla before building a new modlist",
+	Long: `Check that a game install is pristine: that no profile is applied (modctl
+deployed nothing to it), and that its files are the vanilla ones. For steam
+games every file is compared to Steam's depot manifests (see
+steam_depot_manifests); if a baseline was recorded (see ` + "`modctl games scan`" + `)
+every file is compared to it too. Every file is hashed.
+
+Files that aren't part of the game (not in the depot manifests, or created
+after the baseline was recorded) are listed but don't make the game any less
+vanilla: they can be left over from a manual install, or the game wrote them.
+
+If the game is vanilla, the time is recorded ("verified vanilla", see
+` + "`modctl games info`" + `), as is its baseline if it doesn't have one yet. Apply
+then uses the depot manifests of a verified steam game even if
+steam_depot_manifests is off: the vanilla files that it replaces aren't
+backed up because verifying the game files in Steam restores them.
+
+For steam games, --steam asks Steam to verify the game files first (it opens
+steam://validate/<appid>); Steam does that in the background, so run the
+command again without --steam once it's done.
+
+The game defaults to the active game.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.GameInstallSelectors(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := argGame(ctx, c, args)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		if gamesVerifyVanillaSteam {
+			if gi.StoreID != "steam" {
+				return fmt.Errorf("%s is not a steam game", gi.DisplayName)
+			}
+			url := steamValidateURL(gi.StoreGameID)
+			if dryrun.Enabled() {
+				dryrun.Note("open %s", url)
+				return nil
+			}
+			if err := openURL(url); err != nil {
+				fmt.Println(ui.Warn.Render("⚠ could not open " + url + ": " + err.Error()))
+				fmt.Println(ui.Subtle.Render("  verify the game files in Steam (Properties → Installed Files) instead"))
+			} else {
+				fmt.Println(ui.OK.Render("Asked Steam to verify the game files of " + gi.DisplayName))
+			}
+			fmt.Println(ui.Subtle.Render("  run `modctl games verify-vanilla` again once Steam is done"))
+			return nil
+		}
+
+		fmt.Println(ui.Subtle.Render("Checking the files of " + gi.DisplayName + " (this hashes every file)..."))
+		r, err := c.VerifyVanilla(ctx, gi)
+		for _, w := range r.Warnings {
+			fmt.Println(ui.Warn.Render("  ⚠ " + w))
+		}
+		if errors.Is(err, modctl.ErrNothingToVerify) {
+			return fmt.Errorf("%s has no depot manifests or baseline to compare its files to: record one with `modctl games scan` on a vanilla install", gi.DisplayName)
+		}
+		if err != nil {
+			return err
+		}
+
+		var against []string
+		if r.SteamFiles > 0 {
+			against = append(against, fmt.Sprintf("%d files in Steam's depot manifests", r.SteamFiles))
+		}
+		if r.Baseline {
+			against = append(against, "the baseline from "+gi.BaselineScannedAt)
+		}
+		for _, a := range against {
+			fmt.Println(ui.Subtle.Render("  compared to " + a))
+		}
+
+		if r.Deployed > 0 {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ %d files are deployed by modctl: run `modctl profiles unapply` first", r.Deployed)))
+		}
+		printVanillaPaths("changed", r.Changed, ui.Warn.Render)
+		printVanillaPaths("missing", r.Missing, ui.Warn.Render)
+		printVanillaPaths("not part of the game", r.Extra, ui.Subtle.Render)
+
+		if !r.Pristine() {
+			if gi.StoreID == "steam" {
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+					"  verify the game files in Steam to restore them (%s, or pass --steam)", steamValidateURL(gi.StoreGameID))))
+			}
+			return fmt.Errorf("%s is not vanilla", gi.DisplayName)
+		}
+
+		if err := c.RecordVerifiedVanilla(ctx, gi); err != nil {
+			return err
+		}
+		fmt.Println(ui.OK.Render(gi.DisplayName + " is vanilla"))
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+// printVanillaPaths lists (some of) the files that verify-vanilla found.
+func printVanillaPaths(what string, paths []modctl.ChangedPath, render func(...string) string) {
+	if len(paths) == 0 {
+		return
+	}
+	fmt.Println(render(fmt.Sprintf("  %d %s:", len(paths), what)))
+	for i, p := range paths {
+		if i == gamesVerifyVanillaListLimit && !verbose {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("    ... and %d more (pass --verbose to list them all)",
+				len(paths)-i)))
+			break
+		}
+		fmt.Println(render("    " + p.String()))
+	}
+}
+
+// openURL opens a URL (e.g., a steam:// one) with the desktop's handler,
+// without waiting for it.
+func openURL(url string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"open", url}}
+	case "windows":
+		candidates = [][]string{{"rundll32", "url.dll,FileProtocolHandler", url}}
+	default:
+		candidates = [][]string{{"xdg-open", url}, {"steam", url}}
+	}
+
+	for _, c := range candidates {
+		path, err := exec.LookPath(c[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, c[1:]...)
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		return cmd.Process.Release()
+	}
+	return errors.New("no program to open it with (install xdg-open)")
+}
+
+func init() {
+	gamesCmd.AddCommand(gamesVerifyVanillaCmd)
+
+	gamesVerifyVanillaCmd.Flags().BoolVar(&gamesVerifyVanillaSteam, "steam", false,
+		"Ask Steam to verify the game files instead (steam games)")
+}
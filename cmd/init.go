@@ -20,13 +20,27 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/adrg/xdg"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/scan"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+)
+
+var (
+	initWriteConfig bool
+	initNoDoctor    bool
 )
 
 // initCmd represents the init command
@@ -35,48 +49,286 @@ var initCmd = &cobra.Command{
 	Short: "initializes the modctl database and filesystem",
 	Long: `Initialize modctl's local state.
 
-Creates the required data directories (archives, backups, overrides, tmp) and
-initializes or upgrades the internal database. This command is safe to run
-multiple times and will not overwrite existing data.`,
-	Args: cobra.ExactArgs(0),
+This command:
+  - creates the required data directories (archives, backups, overrides, tmp)
+  - creates the database (or upgrades an existing one) by running all pending
+    migrations
+  - adds any missing built-in stores to the stores table (existing stores are
+    left untouched)
+  - checks that bsdtar is available
+  - runs a quick health check (database, directories, bsdtar); skip it with
+    --no-doctor
+
+With --write-config a commented default config.toml is written, unless the
+config file already exists.
+
+This command is safe to run multiple times and will not overwrite existing
+data.`,
+	Args:         cobra.ExactArgs(0),
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
 
-		err := os.MkdirAll(viper.GetString("archives_dir"), 0o0755)
-		if err != nil {
-			return fmt.Errorf("error creating archives directory: %w", err)
+		fmt.Println(ui.Header.Render("Initializing modctl"))
+		fmt.Println()
+
+		// 1) directory layout
+		for _, key := range []string{"archives_dir", "backups_dir", "overrides_dir", "tmp_dir"} {
+			dir := viper.GetString(key)
+			if err := os.MkdirAll(dir, 0o0755); err != nil {
+				return fmt.Errorf("error creating %s directory: %w",
+					strings.TrimSuffix(key, "_dir"), err)
+			}
+			fmt.Println(ui.OK.Render("  ✓ " + strings.TrimSuffix(key, "_dir") + ": " + dir))
 		}
 
-		err = os.MkdirAll(viper.GetString("backups_dir"), 0o0755)
+		// 2) database
+		dbPath := viper.GetString("database")
+		db, err := modctl.InitDB(ctx)
 		if err != nil {
-			return fmt.Errorf("error creating backups directory: %w", err)
+			return err
 		}
 
-		err = os.MkdirAll(viper.GetString("overrides_dir"), 0o0755)
-		if err != nil {
-			return fmt.Errorf("error creating overrides directory: %w", err)
+		switch {
+		case db.Created:
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ database created (schema version %d): %s", db.Version, dbPath)))
+		case db.Migrations > 0:
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ database upgraded (%d migrations, schema version %d): %s",
+				db.Migrations, db.Version, dbPath)))
+		default:
+			fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ database up to date (schema version %d): %s", db.Version, dbPath)))
 		}
 
-		err = os.MkdirAll(viper.GetString("tmp_dir"), 0o0755)
-		if err != nil {
-			return fmt.Errorf("error creating tmp directory: %w", err)
+		// 3) stores
+		if len(db.StoresAdded) > 0 {
+			fmt.Println(ui.OK.Render("  ✓ stores added: " + strings.Join(db.StoresAdded, ", ")))
+		} else {
+			fmt.Println(ui.OK.Render("  ✓ stores: OK"))
 		}
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error opening database: %w", err)
+		// 4) bsdtar
+		if path, err := exec.LookPath(viper.GetString("bsdtar")); err != nil {
+			fmt.Println(ui.Warn.Render("  ⚠ bsdtar not found; install libarchive (e.g., `pacman -S libarchive`, `apt install libarchive-tools`, or `brew install libarchive`) or set bsdtar in the config file"))
+		} else {
+			fmt.Println(ui.OK.Render("  ✓ bsdtar: " + path))
 		}
-		defer db.Close()
 
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
+		// 5) config file
+		if initWriteConfig {
+			path, written, err := writeDefaultConfig()
+			if err != nil {
+				return err
+			}
+			if written {
+				fmt.Println(ui.OK.Render("  ✓ config written: " + path))
+			} else {
+				fmt.Println(ui.Subtle.Render("  config already exists, not overwriting: " + path))
+			}
 		}
 
+		fmt.Println()
+
+		if initNoDoctor {
+			return nil
+		}
+
+		// 6) mini-doctor
+		if err := checkDb(ctx); err != nil {
+			return err
+		}
+		if err := checkPaths(); err != nil {
+			return err
+		}
+		if err := checkBsdtar(ctx); err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Subtle.Render("Next: run `modctl games refresh` to discover your installed games."))
+
 		return nil
 	},
+	Annotations: mutating,
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolVar(&initWriteConfig, "write-config", false,
+		"Write a default config.toml (never overwrites an existing one)")
+	initCmd.Flags().BoolVar(&initNoDoctor, "no-doctor", false,
+		"Skip the health check at the end")
+}
+
+// writeDefaultConfig writes a config file with every option commented out
+// (set to its current value) so that it documents what can be configured.
+func writeDefaultConfig() (string, bool, error) {
+	path := cfgFile
+	if path == "" {
+		p, err := xdg.ConfigFile(filepath.Join("modctl", "config.toml"))
+		if err != nil {
+			return "", false, fmt.Errorf("locate config file: %w", err)
+		}
+		path = p
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return path, false, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return path, false, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	var b strings.Builder
+	b.WriteString("# modctl configuration\n")
+	b.WriteString("#\n")
+	b.WriteString("# Every setting is optional; uncomment a line to change it.\n")
+
+	opt := func(comment, key string, value any) {
+		b.WriteString("\n# " + comment + "\n")
+		switch v := value.(type) {
+		case string:
+			fmt.Fprintf(&b, "#%s = %q\n", key, v)
+		default:
+			fmt.Fprintf(&b, "#%s = %v\n", key, v)
+		}
+	}
+
+	opt("bsdtar executable (name to search in $PATH, or absolute path)",
+		"bsdtar", viper.GetString("bsdtar"))
+	opt("compression of archives created by modctl: zstd, xz, or gzip, with an optional level (e.g., \"xz:9\")",
+		"archive_compression", viper.GetString("archive_compression"))
+	opt("directory for the database and the stores below unless they're set on their own (--data-dir and $MODCTL_DATA_DIR take precedence)",
+		"data_dir", viper.GetString("data_dir"))
+	opt("sqlite database", "database", viper.GetString("database"))
+	opt("content-addressed stores", "archives_dir", viper.GetString("archives_dir"))
+	b.WriteString(fmt.Sprintf("#backups_dir = %q\n", viper.GetString("backups_dir")))
+	b.WriteString(fmt.Sprintf("#overrides_dir = %q\n", viper.GetString("overrides_dir")))
+	opt("scratch space (should be on the same filesystem as the stores)",
+		"tmp_dir", viper.GetString("tmp_dir"))
+	opt("remove what modctl left in tmp_dir (e.g., after a crash) once it's this old, when a command that changes something starts (\"0s\": never; see `modctl tmp clean`)",
+		"tmp_max_age", viper.GetString("tmp_max_age"))
+	opt("read-only archive store shared by the users of this machine, read before archives_dir; archives in it aren't stored again (empty: none)",
+		"shared_archives_dir", viper.GetString("shared_archives_dir"))
+	opt("where corrupted blobs are moved to (see doctor --recheck and mods verify)",
+		"quarantine_dir", viper.GetString("quarantine_dir"))
+	opt("files of mods that targets with the symlink deploy method link to (see `modctl games target-strategy --help`)",
+		"extracted_dir", viper.GetString("extracted_dir"))
+	opt("layers of targets with the overlay deploy method", "overlays_dir", viper.GetString("overlays_dir"))
+	opt("active selection, lock, http cache, apply reports, and shell history ($XDG_STATE_HOME/modctl for the default data_dir, otherwise in it)",
+		"state_dir", viper.GetString("state_dir"))
+	opt("cache nexus api responses on disk", "http_cache", viper.GetBool("http_cache"))
+	b.WriteString(fmt.Sprintf("#http_cache_dir = %q\n", viper.GetString("http_cache_dir")))
+	opt("reports of every apply (see `modctl ops show`)", "reports_dir", viper.GetString("reports_dir"))
+	opt("don't use the network (like --offline): answer nexus requests from the cache, however old, and refuse downloads",
+		"offline", viper.GetBool("offline"))
+	opt("nexus api requests to always keep in reserve", "nexus_rate_limit_reserve",
+		viper.GetInt64("nexus_rate_limit_reserve"))
+	opt("how long to wait for the nexus rate limit to reset before deferring requests",
+		"nexus_rate_limit_max_wait", viper.GetString("nexus_rate_limit_max_wait"))
+	opt("how fast to download files, in bytes per second (e.g., \"5M\"; 0: no limit; see `modctl nexus download-limits` to limit hosts)",
+		"download_limit_rate", viper.GetString("download_limit_rate"))
+	opt("how many files to download at the same time (0: no limit)",
+		"download_concurrency", viper.GetInt64("download_concurrency"))
+	opt("where to store credentials: \"keyring\" or \"env\"", "secrets_provider",
+		viper.GetString("secrets_provider"))
+	opt("proton script used to run windows tools (empty: newest proton in the game's library)",
+		"proton", viper.GetString("proton"))
+	opt("steam account for ${userdata} targets: login name, persona name, or id (empty: the account that installed the game, else the last one that logged in)",
+		"steam_account", viper.GetString("steam_account"))
+	opt("also apply the profile on `modctl profiles set-active` (like `modctl profiles switch`)",
+		"apply_on_switch", viper.GetBool("apply_on_switch"))
+	opt("don't back up vanilla files of steam games (checked against steam's depot manifests); verifying the game files in steam restores them",
+		"steam_depot_manifests", viper.GetBool("steam_depot_manifests"))
+	opt("record what is in the game directories before applying a profile for the first time (see `modctl games scan`)",
+		"baseline_on_apply", viper.GetBool("baseline_on_apply"))
+	opt("sort the plugin load order with LOOT (loot_command) after an apply that changed files, like `modctl plugins sort`",
+		"loot_after_apply", viper.GetBool("loot_after_apply"))
+	opt("run REDmod's deploy step after an apply that changed REDmod content of cyberpunk 2077, like `modctl redmod deploy`",
+		"redmod_deploy_after_apply", viper.GetBool("redmod_deploy_after_apply"))
+	opt("optimize the database after operations that change at least this many rows (0: never; see `modctl db optimize`)",
+		"auto_optimize_rows", viper.GetInt64("auto_optimize_rows"))
+	opt("log how long every database query takes to this file (empty: don't; see `modctl db analyze`)",
+		"query_log", viper.GetString("query_log"))
+	opt("how much to read at once while storing, verifying, or hashing files (a multiple of 4KiB; see `modctl config bench-io`)",
+		"io_buffer_size", viper.GetString("io_buffer_size"))
+	opt("read files with O_DIRECT, bypassing the page cache (linux only)",
+		"io_direct", viper.GetBool("io_direct"))
+	opt("access pattern hint for the kernel: \"sequential\", \"noreuse\", or \"dontneed\" (drop the files from the page cache after reading them; empty: no hint)",
+		"io_fadvise", viper.GetString("io_fadvise"))
+	opt("ask the kernel to read this far ahead (e.g., \"16MiB\", can help on network filesystems; 0: its default)",
+		"io_readahead", viper.GetString("io_readahead"))
+	opt("where `modctl advisories update` downloads the feed of known-malicious or broken mod files from: a url or a local file (empty: none)",
+		"advisory_feed", viper.GetString("advisory_feed"))
+	opt("what to run when modctl is run without any arguments (e.g., \"status\"; empty: show the help)",
+		"default_command", viper.GetString("default_command"))
+	b.WriteString("\n# how to run LOOT to sort plugins (see `modctl plugins sort --help`)\n")
+	fmt.Fprintf(&b, "#loot_command = [%s]\n", tomlStrings(viper.GetStringSlice("loot_command")))
+	b.WriteString("\n# scan archives when they're imported; exits with 0 if the file (${file}) is\n")
+	b.WriteString("# clean and 1 if something was detected, like clamscan (empty: don't)\n")
+	fmt.Fprintf(&b, "#scan_command = [%s]\n", tomlStrings(scan.ClamAV))
+	b.WriteString("\n# how to mount the layer of an overlay target (${files}) and the directory for\n")
+	b.WriteString("# what is written to it (${upper} and ${work}) over the target (${root}), and\n")
+	b.WriteString("# unmount it again (see `modctl games target-strategy --help`)\n")
+	fmt.Fprintf(&b, "#overlay_mount_command = [%s]\n", tomlStrings(viper.GetStringSlice("overlay_mount_command")))
+	fmt.Fprintf(&b, "#overlay_unmount_command = [%s]\n", tomlStrings(viper.GetStringSlice("overlay_unmount_command")))
+	b.WriteString("\n# how to merge witcher 3 scripts (see `modctl witcher3 merge --help`)\n")
+	fmt.Fprintf(&b, "#witcher3_merge_command = [%s]\n", tomlStrings(viper.GetStringSlice("witcher3_merge_command")))
+	b.WriteString("\n# where apply fetches archives that are missing from archives_dir: other\n")
+	b.WriteString("# modctl data dirs, archives dirs (e.g., a NAS export), or http(s) urls\n")
+	b.WriteString("# with the same <ab>/<sha256> layout, tried in order (empty: none)\n")
+	fmt.Fprintf(&b, "#blob_mirrors = [%s]\n", tomlStrings([]string{"/mnt/nas/modctl", "https://example.com/modctl/archives"}))
+
+	// tables have to come last
+	b.WriteString("\n# additional targets created by `modctl games refresh`, relative to the\n")
+	b.WriteString("# install root (many popular games have defaults; \"\" removes one) or\n")
+	b.WriteString("# starting with one of ${home}, ${config}, ${data}, ${install_root},\n")
+	b.WriteString("# ${compatdata}, ${prefix}, ${documents}, ${appdata_local},\n")
+	b.WriteString("# ${appdata_roaming}, or ${userdata} (<steam>/userdata/<account>/<appid>)\n")
+	b.WriteString("# for targets outside of it; games that keep the mods of every version\n")
+	b.WriteString("# apart can use ${game_version} (the version that the profile is for, see\n")
+	b.WriteString("# `modctl profiles game-version`), which is resolved when applying\n")
+	b.WriteString("#[target_templates.\"steam:413150\"]\n")
+	b.WriteString("#mods = \"Mods\"\n")
+	b.WriteString("#saves = \"${appdata_roaming}/StardewValley/Saves\"\n")
+	b.WriteString("#versioned = \"mods/${game_version}\"\n")
+	b.WriteString("\n# variables for template overrides (see `modctl overrides set --help`),\n")
+	b.WriteString("# for every game or only a game (store:game) or install (store:game#instance)\n")
+	b.WriteString("#[override_vars]\n")
+	b.WriteString("#resolution = \"1920x1080\"\n")
+	b.WriteString("#[override_vars.\"steam:489830#deck\"]\n")
+	b.WriteString("#resolution = \"1280x800\"\n")
+	b.WriteString("\n# command aliases: `modctl up Foo` runs `modctl mods outdated --changelogs\n")
+	b.WriteString("# Foo` (aliases can use other aliases, but they can't replace a command)\n")
+	b.WriteString("#[aliases]\n")
+	b.WriteString("#i = \"mods import\"\n")
+	b.WriteString("#up = \"mods outdated --changelogs\"\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return path, false, fmt.Errorf("create config directory: %w", err)
+	}
+
+	// O_EXCL: never clobber a config file that appeared in the meantime
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return path, false, nil
+		}
+		return path, false, fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		return path, false, fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return path, true, nil
+}
+
+// tomlStrings formats a list of strings as the contents of a TOML array.
+func tomlStrings(ss []string) string {
+	quoted := make([]string, 0, len(ss))
+	for _, s := range ss {
+		quoted = append(quoted, strconv.Quote(s))
+	}
+	return strings.Join(quoted, ", ")
 }
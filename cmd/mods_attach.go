@@ -0,0 +1,124 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsAttachGame  string
+	modsAttachLabel string
+	modsAttachNotes string
+)
+
+var modsAttachCmd = &cobra.Command{
+	Use:   "attach <mod> <file>",
+	Short: "Attach a supplementary file to a mod",
+	Long: `Keep a supplementary file (e.g., a patch, a customized INI, a screenshot or
+some notes) with a mod so that everything related to it lives in one place.
+
+The file is copied into the blob store: archives are kept with the mod
+archives and anything else with the overrides. Attachments are never
+deployed; they are shown by modctl mods info and can be written back out with
+modctl mods get-attachment.
+
+The label defaults to the file name and must be unique within the mod.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.ModPages(cmd, toComplete)
+		}
+		if len(args) == 1 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srcPath := args[1]
+		st, err := os.Stat(srcPath)
+		if err != nil {
+			return err
+		}
+		if !st.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", srcPath)
+		}
+
+		label := strings.TrimSpace(modsAttachLabel)
+		if label == "" {
+			label = filepath.Base(srcPath)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsAttachGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.ModPage(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		sha, err := c.Attach(ctx, p, label, srcPath, modsAttachNotes)
+		if err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Attached %q to %s (sha256 %s)", label, p.Name, sha[:12]),
+			fmt.Sprintf("attach %q to %s (sha256 %s)", label, p.Name, sha[:12]))
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsAttachCmd)
+
+	modsAttachCmd.Flags().StringVarP(&modsAttachGame, "game", "g", "",
+		"Override the currently active game")
+	modsAttachCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+	modsAttachCmd.Flags().StringVarP(&modsAttachLabel, "label", "l", "",
+		"Label of the attachment (default: the file name)")
+	modsAttachCmd.Flags().StringVar(&modsAttachNotes, "notes", "",
+		"Notes about the attachment")
+}
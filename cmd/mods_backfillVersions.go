@@ -0,0 +1,111 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsBackfillVersionsGame string
+)
+
+var modsBackfillVersionsCmd = &cobra.Command{
+	Use:   "backfill-versions",
+	Short: "Guess missing version strings from archive filenames",
+	Long: `Fill in missing version strings for already-imported mod file versions.
+
+For every mod file version of the game that doesn't have a version string,
+modctl looks at the original archive filename and, if it looks like a Nexus
+Mods download (e.g., SomeMod-1234-2-0-1-1699999999.7z), records the version and
+upload time encoded in it.
+
+Versions that already have a version string are never modified. If the mod page
+is linked to a Nexus mod, the mod id in the filename must match.
+
+The filename doesn't contain the Nexus file id and this command doesn't use the
+network: run ` + "`modctl nexus backfill`" + ` to look the file ids up (and replace the
+guessed versions with the ones from Nexus) for mods that are linked to Nexus.
+
+Use --dry-run to see what would change without updating the database.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsBackfillVersionsGame)
+		if err != nil {
+			return err
+		}
+
+		// under --dry-run this only changes the throwaway copy of the database
+		guessed, skipped, err := c.BackfillVersions(ctx, gi)
+		if err != nil {
+			return err
+		}
+
+		for _, g := range guessed {
+			fmt.Printf("v%d  %s  %s\n", g.VersionID, g.ModName,
+				ui.Subtle.Render(fmt.Sprintf("version=%q  (%s)",
+					g.Version, g.OriginalName)))
+		}
+
+		if dryrun.Enabled() {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+				"dry run: would update %d versions (%d without a recognizable filename)",
+				len(guessed), skipped)))
+			return nil
+		}
+
+		fmt.Println(ui.OK.Render(fmt.Sprintf("Updated %d versions", len(guessed))))
+		if skipped > 0 {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+				"  %d versions without a recognizable filename were skipped", skipped)))
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsBackfillVersionsCmd)
+
+	modsBackfillVersionsCmd.Flags().StringVarP(&modsBackfillVersionsGame, "game", "g", "",
+		"Override the currently active game")
+	modsBackfillVersionsCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
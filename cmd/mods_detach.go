@@ -0,0 +1,94 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var modsDetachGame string
+
+var modsDetachCmd = &cobra.Command{
+	Use:   "detach <mod> <label>",
+	Short: "Remove a supplementary file from a mod",
+	Long: `Remove an attachment (see modctl mods attach) from a mod. The stored file is
+deleted too unless something else still uses it.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.ModPages(cmd, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsDetachGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.ModPage(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		a, warnings, err := c.Detach(ctx, p, args[1])
+		if err != nil {
+			return err
+		}
+		for _, w := range warnings {
+			fmt.Fprintln(os.Stderr, ui.Warn.Render("warning: "+w))
+		}
+
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Detached %q from %s", a.Label, p.Name),
+			fmt.Sprintf("detach %q from %s", a.Label, p.Name))
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsDetachCmd)
+
+	modsDetachCmd.Flags().StringVarP(&modsDetachGame, "game", "g", "",
+		"Override the currently active game")
+	modsDetachCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
@@ -0,0 +1,119 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsGetAttachmentGame   string
+	modsGetAttachmentOutput string
+)
+
+var modsGetAttachmentCmd = &cobra.Command{
+	Use:   "get-attachment <mod> <label>",
+	Short: "Write a mod's supplementary file back out",
+	Long: `Write an attachment of a mod (see modctl mods attach) to standard output or,
+with --output, to a new file. Use --output . to write it to the current
+directory under its original name.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.ModPages(cmd, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsGetAttachmentGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.ModPage(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		a, err := c.Attachment(ctx, p, args[1])
+		if err != nil {
+			return err
+		}
+
+		var dst io.Writer = os.Stdout
+		out := modsGetAttachmentOutput
+		if out == "." {
+			out = a.OriginalName
+		}
+		if out != "" && out != "-" {
+			// never clobber an existing file
+			f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			dst = f
+		}
+
+		if err := c.WriteAttachment(ctx, a, dst); err != nil {
+			return err
+		}
+
+		if f, ok := dst.(*os.File); ok && f != os.Stdout {
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("write attachment: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Wrote %q (%s) to %s\n", a.Label,
+				internal.FormatBytes(a.Size), out)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	modsCmd.AddCommand(modsGetAttachmentCmd)
+
+	modsGetAttachmentCmd.Flags().StringVarP(&modsGetAttachmentGame, "game", "g", "",
+		"Override the currently active game")
+	modsGetAttachmentCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+	modsGetAttachmentCmd.Flags().StringVarP(&modsGetAttachmentOutput, "output", "o", "",
+		"Write the attachment to this (new) file instead of standard output")
+}
@@ -19,28 +19,20 @@
 package cmd
 
 import (
-	"archive/tar"
-	"bytes"
-	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os"
-	"os/exec"
 	"os/signal"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/lipgloss"
-	"github.com/mfinelli/modctl/dbq"
 	"github.com/mfinelli/modctl/internal"
-	"github.com/mfinelli/modctl/internal/blobstore"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/importer"
-	"github.com/mfinelli/modctl/internal/nexus"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -51,18 +43,15 @@ var (
 	modsImportLabel       string
 	modsImportNexusUrl    string
 	modsImportRm          bool
+	modsImportNoGuess     bool
+	modsImportAllowDup    bool
 	modsImportListTimeout int64
 	modsImportPageID      int64
+	modsImportIgnAdv      bool
+	modsImportForce       bool
+	modsImportUnscanned   bool
 )
 
-type prepareArchiveResult struct {
-	PathToImport string
-	Wrapped      bool
-	WrappedFrom  string // e.g. "pdf" (without dot), or "" if unknown
-	MemberName   string // tar member name (basename of input)
-	Cleanup      func()
-}
-
 var modsImportCmd = &cobra.Command{
 	Use:   "import",
 	Short: "Import a mod archive into the blob store",
@@ -76,11 +65,29 @@ By default, the input file is treated as an archive. modctl will validate the
 file by listing its contents using bsdtar before importing it.
 
 If the input file is not a supported archive format, modctl will wrap it into a
-new .tar.gz archive containing the file, then import that archive. This ensures
-that all stored archives can be inspected and extracted consistently later.
+new archive containing the file (compressed as configured by
+archive_compression), then import that archive. This ensures that all stored
+archives can be inspected and extracted consistently later.
 
 You can optionally attach Nexus metadata at import time using --nexus-url.
 
+If the input filename looks like a Nexus Mods download (e.g.,
+SomeMod-1234-2-0-1-1699999999.7z) modctl uses it to guess the version string
+and upload time of the mod file version. Pass --no-guess to disable this. The
+filename doesn't contain the Nexus file id: ` + "`modctl nexus backfill`" + ` looks it up.
+
+If the archive (by SHA-256) has already been imported for the game, modctl
+reports the existing mod file version instead of importing it again. Pass
+--allow-duplicate to import it anyway (e.g., to attach the same archive to a
+different mod page).
+
+If the scan_command config option is set (e.g., ["clamscan", "--no-summary",
+"--infected", "${file}"]) archives that weren't in the store yet are scanned
+with it: the command exits with 0 if the file is clean and with 1 if
+something was detected. A detection refuses the import unless --force is
+passed, and so does a scan that failed unless --allow-unscanned is passed;
+the verdict is recorded with the archive.
+
 If --rm is provided, the original input file is deleted only after the archive
 has been safely stored and the database has been updated successfully.`,
 	Args: cobra.ExactArgs(1),
@@ -88,46 +95,13 @@ has been safely stored and the database has been updated successfully.`,
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
 
-		// TODO: extract these somewhere else
-		subtleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
-
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
-
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
+		defer c.Close()
 
 		inputPath := args[0]
-		archivesDir := viper.GetString("archives_dir")
-
-		bs := blobstore.Store{
-			ArchivesDir:  archivesDir,
-			BackupsDir:   viper.GetString("backups_dir"),
-			OverridesDir: viper.GetString("overrides_dir"),
-		}
-
-		// Optional nexus parse
-		var gameDomain *string
-		var modID *int64
-		if modsImportNexusUrl != "" {
-			ref, err := nexus.ParseModURL(modsImportNexusUrl)
-			if err != nil {
-				return fmt.Errorf("parse --nexus-url: %w", err)
-			}
-			gameDomain = &ref.GameDomain
-			modID = &ref.ModID
-		}
 
 		// Safety checks for --rm up front.
 		info, err := os.Lstat(inputPath)
@@ -141,7 +115,7 @@ has been safely stored and the database has been updated successfully.`,
 			if !info.Mode().IsRegular() {
 				return fmt.Errorf("--rm requires a regular file input")
 			}
-			under, err := internal.IsUnderDir(inputPath, archivesDir)
+			under, err := internal.IsUnderDir(inputPath, viper.GetString("archives_dir"))
 			if err != nil {
 				return fmt.Errorf("check --rm safety: %w", err)
 			}
@@ -150,79 +124,84 @@ has been safely stored and the database has been updated successfully.`,
 			}
 		}
 
-		// Validate input as an archive using bsdtar -t, otherwise wrap into .tar.gz.
-		listTimeout := time.Duration(modsImportListTimeout) * time.Second
-		prep, err := prepareImportArchive(ctx, inputPath, listTimeout)
+		gi, err := clientGame(ctx, c, modsImportGame)
 		if err != nil {
 			return err
 		}
-		defer prep.Cleanup()
-
-		if prep.Wrapped {
-			fmt.Println(warnStyle.Render("  ⚠ input was not a supported archive; wrapped into .tar.gz for storage"))
-		}
-
-		q := dbq.New(db)
 
-		// Resolve game install id: --game overrides active selection
-		if modsImportGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			modsImportGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
-
-		gi, err := internal.ResolveGameInstallArg(ctx, q, modsImportGame)
-		if err != nil {
-			return err
-		}
-
-		opts := importer.ImportOptions{
-			GameInstallID:    gi.ID,
-			ArchivePath:      prep.PathToImport,
-			OriginalBasename: filepath.Base(inputPath),
-			PageID:           &modsImportPageID,
-			NexusURL:         ptrIfNonEmpty(modsImportNexusUrl),
-			NexusGameDomain:  gameDomain,
-			NexusModID:       modID,
-			Wrapped:          prep.Wrapped,
-			WrappedFrom:      prep.WrappedFrom,
-			MemberName:       prep.MemberName,
-		}
-		if modsImportName != "" {
-			opts.ModName = &modsImportName
-		}
-		if modsImportLabel != "" {
-			opts.FileLabel = &modsImportLabel
-		}
-
-		pageID, fileID, versionID, sha, size, err := importer.ImportArchive(ctx, db, q, bs, opts)
+		progress, finish := blobProgress("  " + filepath.Base(inputPath))
+		res, err := c.ImportMod(ctx, gi, inputPath, modctl.ImportOptions{
+			PageID:           modsImportPageID,
+			ModName:          modsImportName,
+			FileLabel:        modsImportLabel,
+			NexusURL:         modsImportNexusUrl,
+			GuessVersion:     !modsImportNoGuess,
+			Wrap:             true,
+			ListTimeout:      time.Duration(modsImportListTimeout) * time.Second,
+			AllowDuplicate:   modsImportAllowDup,
+			IgnoreAdvisories: modsImportIgnAdv,
+			ForceScan:        modsImportForce,
+			AllowUnscanned:   modsImportUnscanned,
+			Progress:         progress,
+			OnWarning:        printAdvisory,
+		})
+		finish()
 		if err != nil {
+			var dup *modctl.DuplicateError
+			if errors.As(err, &dup) {
+				if dup.Restored {
+					dryrun.Report(os.Stdout, "Restored the missing archive of:", "restore the missing archive of:")
+				} else {
+					fmt.Println(ui.Warn.Render("Already imported:"))
+				}
+				fmt.Printf("  mod_page_id: %d\n", dup.PageID)
+				fmt.Printf("  mod_file_id: %d\n", dup.FileID)
+				fmt.Printf("  mod_file_version_id: %d\n", dup.VersionID)
+				fmt.Printf("  mod: %s / %s\n", dup.ModName, dup.FileLabel)
+				if dup.Version != "" {
+					fmt.Printf("  version: %s\n", dup.Version)
+				}
+				fmt.Printf("  sha256: %s\n", dup.SHA256)
+				if !dup.Restored {
+					fmt.Println(ui.Subtle.Render("  pass --allow-duplicate to import it again"))
+				}
+				if modsImportRm {
+					fmt.Println(ui.Subtle.Render("  original input file was kept"))
+				}
+				return nil
+			}
 			return err
 		}
 
 		// Delete original only after successful import + DB commit
-		if modsImportRm {
+		if modsImportRm && dryrun.Enabled() {
+			dryrun.Note("remove %s", inputPath)
+		} else if modsImportRm {
 			if err := os.Remove(inputPath); err != nil {
 				// Import is done; keep this as a loud error because the user asked for --rm.
 				return fmt.Errorf("import succeeded but failed to remove original file: %w", err)
 			}
-			fmt.Println(subtleStyle.Render("  removed original input file"))
+			fmt.Println(ui.Subtle.Render("  removed original input file"))
 		}
 
-		fmt.Println("Imported:")
-		fmt.Printf("  mod_page_id: %d\n", pageID)
-		fmt.Printf("  mod_file_id: %d\n", fileID)
-		fmt.Printf("  mod_file_version_id: %d\n", versionID)
-		fmt.Printf("  sha256: %s\n", sha)
-		fmt.Printf("  size_bytes: %d\n", size)
+		dryrun.Report(os.Stdout, "Imported:", "import:")
+		fmt.Printf("  mod_page_id: %d\n", res.PageID)
+		fmt.Printf("  mod_file_id: %d\n", res.FileID)
+		fmt.Printf("  mod_file_version_id: %d\n", res.VersionID)
+		fmt.Printf("  sha256: %s\n", res.SHA256)
+		fmt.Printf("  size_bytes: %d\n", res.Size)
+		if res.GuessedVersion != "" {
+			fmt.Printf("  version: %s %s\n", res.GuessedVersion,
+				ui.Subtle.Render("(guessed from filename)"))
+		}
+		if len(res.Docs) > 0 {
+			fmt.Printf("  docs: %s %s\n", strings.Join(res.Docs, ", "),
+				ui.Subtle.Render("(see `modctl mods readme`)"))
+		}
 
 		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
@@ -245,6 +224,16 @@ func init() {
 		"Attach the mod to an existing page")
 	modsImportCmd.Flags().BoolVar(&modsImportRm, "rm", false,
 		"Remove original archive after import")
+	modsImportCmd.Flags().BoolVar(&modsImportNoGuess, "no-guess", false,
+		"Don't guess the version from the archive filename")
+	modsImportCmd.Flags().BoolVar(&modsImportAllowDup, "allow-duplicate", false,
+		"Import the archive even if it was already imported for this game")
+	modsImportCmd.Flags().BoolVar(&modsImportIgnAdv, "ignore-advisories", false,
+		"Import the archive even if the advisory feed knows it to be malicious")
+	modsImportCmd.Flags().BoolVar(&modsImportForce, "force", false,
+		"Import the archive even if the virus scanner detected something")
+	modsImportCmd.Flags().BoolVar(&modsImportUnscanned, "allow-unscanned", false,
+		"Import the archive even if the virus scanner couldn't scan it")
 	modsImportCmd.Flags().Int64VarP(&modsImportListTimeout, "list-timeout",
 		"t", 60, "Set timeout in seconds to list the contents of the passed archive")
 
@@ -258,142 +247,3 @@ func ptrIfNonEmpty(s string) *string {
 	}
 	return &s
 }
-
-func prepareImportArchive(ctx context.Context, inputPath string, listTimeout time.Duration) (prepareArchiveResult, error) {
-	// First, try to validate as an archive with bsdtar -t
-	ctxT, cancel := context.WithTimeout(ctx, listTimeout)
-	defer cancel()
-
-	if err := bsdtarListOK(ctxT, inputPath); err == nil {
-		return prepareArchiveResult{PathToImport: inputPath, Wrapped: false, Cleanup: func() {}}, nil
-	}
-
-	// Not an archive (or bsdtar couldn't list it) -- wrap into tar.gz.
-	tmpDir := viper.GetString("tmp_dir")
-	wrapped, cleanup, err := wrapIntoTarGz(tmpDir, inputPath)
-	if err != nil {
-		return prepareArchiveResult{}, err
-	}
-
-	// Validate the wrapped archive too (should succeed unless we wrote bad tar.gz)
-	ctxT2, cancel2 := context.WithTimeout(ctx, listTimeout)
-	defer cancel2()
-	if err := bsdtarListOK(ctxT2, wrapped); err != nil {
-		cleanup()
-		return prepareArchiveResult{}, fmt.Errorf("wrapped archive failed bsdtar validation: %w", err)
-	}
-
-	return prepareArchiveResult{PathToImport: wrapped, Wrapped: true, Cleanup: cleanup}, nil
-}
-
-func bsdtarListOK(ctx context.Context, archivePath string) error {
-	// Keep output quiet on success; capture stderr for failure message.
-	cmd := exec.CommandContext(ctx, viper.GetString("bsdtar"), "-t", "-f", archivePath)
-	var stderr bytes.Buffer
-	cmd.Stdout = io.Discard
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		msg := strings.TrimSpace(stderr.String())
-		if msg != "" {
-			return fmt.Errorf("bsdtar -t failed: %s", msg)
-		}
-		return fmt.Errorf("bsdtar -t failed: %w", err)
-	}
-	return nil
-}
-
-// Note Mode: int64(info.Mode().Perm()) preserves permission bits but does
-// _NOT_ Sticky/setuid bits and so Perm() drops them. this is our desired
-// behavior
-//
-// This writes a tar member named as the input basename, with:
-//   - uid/gid 0, uname/gname root/root
-//   - original modtime from os.Stat
-//   - original mode (including executable bit)
-//   - content is raw bytes of the input file
-func wrapIntoTarGz(tmpDir, srcPath string) (wrappedPath string, cleanup func(), err error) {
-	info, err := os.Stat(srcPath)
-	if err != nil {
-		return "", nil, err
-	}
-	if !info.Mode().IsRegular() {
-		return "", nil, fmt.Errorf("cannot wrap non-regular file: %s", srcPath)
-	}
-
-	base := filepath.Base(srcPath)
-	if base == "" || base == "." || base == ".." {
-		return "", nil, fmt.Errorf("invalid input filename: %q", base)
-	}
-
-	// Create temp file
-	f, err := os.CreateTemp(tmpDir, "modctl-wrap-*.tar.gz")
-	if err != nil {
-		return "", nil, fmt.Errorf("create temp archive: %w", err)
-	}
-	tmpName := f.Name()
-
-	cleanup = func() { _ = os.Remove(tmpName) }
-
-	// Stream: gzip -> tar -> file contents
-	gw := gzip.NewWriter(f)
-	tw := tar.NewWriter(gw)
-
-	// Ensure we close in reverse order, capturing the first error.
-	closeAll := func() error {
-		var first error
-
-		setFirst := func(err error) {
-			if err != nil && first == nil {
-				first = err
-			}
-		}
-
-		setFirst(tw.Close())
-		setFirst(gw.Close())
-		setFirst(f.Sync())
-		setFirst(f.Close())
-
-		return first
-	}
-
-	src, err := os.Open(srcPath)
-	if err != nil {
-		_ = f.Close()
-		cleanup()
-		return "", nil, fmt.Errorf("open source: %w", err)
-	}
-	defer src.Close()
-
-	hdr := &tar.Header{
-		Name:    base,
-		Mode:    int64(info.Mode().Perm()),
-		Size:    info.Size(),
-		ModTime: info.ModTime(),
-
-		Uid:   0,
-		Gid:   0,
-		Uname: "root",
-		Gname: "root",
-
-		Typeflag: tar.TypeReg,
-	}
-	if err := tw.WriteHeader(hdr); err != nil {
-		_ = closeAll()
-		cleanup()
-		return "", nil, fmt.Errorf("write tar header: %w", err)
-	}
-
-	if _, err := io.Copy(tw, src); err != nil {
-		_ = closeAll()
-		cleanup()
-		return "", nil, fmt.Errorf("write tar body: %w", err)
-	}
-
-	if err := closeAll(); err != nil {
-		cleanup()
-		return "", nil, fmt.Errorf("finalize temp archive: %w", err)
-	}
-
-	return tmpName, cleanup, nil
-}
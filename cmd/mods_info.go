@@ -0,0 +1,186 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var modsInfoGame string
+
+var modsInfoCmd = &cobra.Command{
+	Use:   "info <mod>",
+	Short: "Show details about a mod",
+	Long: `Show everything modctl knows about a mod: its source, files, imported
+versions, attachments, and which profiles use them.
+
+The mod can be given as its id (see modctl mods list), its name
+(case-insensitive), or the sha256 (or a prefix of at least eight characters)
+of one of its archives.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModPagesOrArchives(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsInfoGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.ModPage(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		info, err := c.ModInfo(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Header.Render(fmt.Sprintf("%d  %s", p.ID, p.Name)))
+
+		line := "  source=" + p.SourceKind
+		if p.NexusDomain != "" && p.NexusModID != 0 {
+			line += fmt.Sprintf("  nexus=%s:%d", p.NexusDomain, p.NexusModID)
+		}
+		if p.SourceRef != "" {
+			line += fmt.Sprintf("  ref=%q", p.SourceRef)
+		}
+		fmt.Println(ui.Subtle.Render(line))
+		if p.SourceURL != "" {
+			fmt.Println(ui.Subtle.Render("  url=" + p.SourceURL))
+		}
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  created_at=%s  updated_at=%s", p.CreatedAt, p.UpdatedAt)))
+		if p.Notes != "" {
+			fmt.Println(ui.Subtle.Render("  notes: " + p.Notes))
+		}
+		fmt.Println()
+
+		if len(info.Attachments) > 0 {
+			fmt.Println("Attachments:")
+			for _, a := range info.Attachments {
+				fmt.Printf("  %s  %s  %s\n", a.Label, a.Kind, internal.FormatBytes(a.Size))
+				aline := "      sha256=" + a.SHA256 + "  attached_at=" + a.AttachedAt
+				if a.OriginalName != a.Label {
+					aline += "  original_name=" + a.OriginalName
+				}
+				fmt.Println(ui.Subtle.Render(aline))
+				if a.Notes != "" {
+					fmt.Println(ui.Subtle.Render("      notes: " + a.Notes))
+				}
+			}
+			fmt.Println()
+		}
+
+		if len(info.Files) == 0 {
+			fmt.Println(ui.Subtle.Render("  (no files)"))
+			return nil
+		}
+
+		for _, f := range info.Files {
+			primaryTag := ""
+			if f.Primary {
+				primaryTag = " (primary)"
+			}
+			fmt.Printf("File %d: %s%s\n", f.ID, f.Label, primaryTag)
+			if f.NexusFileID != 0 {
+				nexusInfo := fmt.Sprintf("  nexus_file_id=%d", f.NexusFileID)
+				if f.Category != "" {
+					nexusInfo += " category=" + f.Category
+				}
+				fmt.Println(ui.Subtle.Render(nexusInfo))
+			}
+
+			if len(f.Versions) == 0 {
+				fmt.Println(ui.Subtle.Render("  (no versions)"))
+				continue
+			}
+
+			for _, v := range f.Versions {
+				vline := fmt.Sprintf("  v%d", v.ID)
+				if v.Version != "" {
+					vline += fmt.Sprintf("  version=%q", v.Version)
+				}
+				if v.UploadedAt != "" {
+					vline += "  uploaded_at=" + v.UploadedAt
+				}
+				vline += "  imported_at=" + v.ImportedAt
+				if v.SizeBytes != 0 {
+					vline += "  size=" + internal.FormatBytes(v.SizeBytes)
+				}
+				fmt.Println(vline)
+
+				fmt.Println(ui.Subtle.Render("      sha256=" + v.ArchiveSHA256))
+				if v.OriginalName != "" {
+					fmt.Println(ui.Subtle.Render("      original_name=" + v.OriginalName))
+				}
+				if v.FetchedFrom != "" {
+					fmt.Println(ui.Subtle.Render("      fetched_from=" + v.FetchedFrom +
+						"  fetched_at=" + v.FetchedAt))
+				}
+				if v.Notes != "" {
+					fmt.Println(ui.Subtle.Render("      notes: " + v.Notes))
+				}
+				for _, u := range v.Profiles {
+					state := "disabled"
+					if u.Enabled {
+						state = "enabled"
+					}
+					fmt.Println(ui.Subtle.Render(fmt.Sprintf("      profile: %s (%s, priority %d)",
+						u.Profile, state, u.Priority)))
+				}
+			}
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsInfoCmd)
+
+	modsInfoCmd.Flags().StringVarP(&modsInfoGame, "game", "g", "",
+		"Override the currently active game")
+	modsInfoCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
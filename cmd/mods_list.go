@@ -20,25 +20,23 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"sort"
 	"strconv"
 
-	"github.com/charmbracelet/lipgloss"
-	"github.com/mfinelli/modctl/dbq"
 	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
 var (
 	modsListGame    string
 	modsListDetails bool
+	modsListFormat  string
 )
 
 var modsListCmd = &cobra.Command{
@@ -50,255 +48,306 @@ By default, this shows one line per mod page with counts and the latest imported
 archive across all files under that page.
 
 With --details, the output expands each mod page to show its mod files and their
-versions.
+versions, with the space that every version takes up once it's extracted
+(install_size; it's read from the archive the first time and remembered).
+
+For steam games the items that steam downloaded from the Steam Workshop
+(steamapps/workshop/content/<appid>) are listed too. modctl doesn't manage
+them, but it takes them into account when looking for conflicts (see modctl
+profiles conflicts).
+
+With --format csv (or tsv) every mod page is a record, or with --details every
+version (with its archive size and install size, in bytes), for spreadsheets;
+workshop items aren't included.
 
 TODO:
 - Show latest version information from the Nexus API for Nexus-linked mods and
   compare it with imported versions.`,
 	Args: cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// TODO: extract these somewhere else
-		headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
-		subtleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
 
-		err := internal.EnsureDBExists()
+		if err := ui.CheckFormat(modsListFormat); err != nil {
+			return err
+		}
+
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
+		gi, err := clientGame(ctx, c, modsListGame)
 		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
+			return err
 		}
-		defer db.Close()
 
-		err = internal.MigrateDB(ctx, db)
+		mods, err := c.Mods(ctx, gi)
 		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
+			return err
 		}
 
-		q := dbq.New(db)
+		// keep deterministic order even if SQL already sorted.
+		sort.SliceStable(mods, func(i, j int) bool {
+			if mods[i].Name == mods[j].Name {
+				return mods[i].PageID < mods[j].PageID
+			}
+			return mods[i].Name < mods[j].Name
+		})
 
-		// Resolve game install id: --game overrides active selection
-		if modsListGame == "" {
-			active, err := state.LoadActive()
+		if modsListFormat != ui.FormatText {
+			return writeModsRecords(ctx, c, gi, mods)
+		}
+
+		// Workshop items aren't managed by modctl but they're mods all
+		// the same, so show them after the imported ones
+		printWorkshop := func() error {
+			items, err := c.WorkshopItems(ctx, gi)
 			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
+				return err
 			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
+			if len(items) == 0 {
+				return nil
 			}
-			modsListGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
 
-		gi, err := internal.ResolveGameInstallArg(ctx, q, modsListGame)
-		if err != nil {
-			return err
-		}
+			fmt.Println(ui.Header.Render("Steam Workshop"))
+			fmt.Println()
+			for _, item := range items {
+				fmt.Printf("%s  %s\n", item.ID, ui.Subtle.Render(item.Dir))
 
-		rows, err := q.ListModsByGameInstall(ctx, gi.ID)
-		if err != nil {
-			return fmt.Errorf("list mods: %w", err)
-		}
+				line := fmt.Sprintf("  files=%d  size=%s", len(item.Files), internal.FormatBytes(item.SizeBytes))
+				if !item.TimeUpdated.IsZero() {
+					line += "  updated_at=" + item.TimeUpdated.Format("2006-01-02T15:04:05Z")
+				}
+				if !item.Subscribed {
+					line += "  (not in the workshop manifest)"
+				}
+				fmt.Println(ui.Subtle.Render(line))
+				fmt.Println()
+			}
 
-		if len(rows) == 0 {
-			fmt.Println(subtleStyle.Render("No mods imported for this game yet."))
-			fmt.Println(subtleStyle.Render("Use `modctl mods import <archive>` to add one."))
 			return nil
 		}
 
-		fmt.Println(headerStyle.Render("Mods"))
-		fmt.Println()
-
-		// Summary query is already "one row per page" (rn=1). We'll build a stable list of page IDs.
-		type pageSummary struct {
-			ModPageID   int64
-			ModName     string
-			SourceKind  string
-			NexusDomain sql.NullString
-			NexusModID  sql.NullInt64
-
-			FilesCount    int64
-			VersionsCount int64
-
-			LatestFileLabel  sql.NullString
-			LatestVersionID  sql.NullInt64
-			LatestVersionStr sql.NullString
-			LatestArchiveSHA sql.NullString
-			LatestImportedAt sql.NullString
-		}
-
-		pages := make([]pageSummary, 0, len(rows))
-		for _, r := range rows {
-			pages = append(pages, pageSummary{
-				ModPageID:   r.ModPageID,
-				ModName:     r.ModName,
-				SourceKind:  r.SourceKind,
-				NexusDomain: r.NexusGameDomain,
-				NexusModID:  r.NexusModID,
-
-				FilesCount:    r.FilesCount,
-				VersionsCount: r.VersionsCount,
-
-				LatestFileLabel:  r.ModFileLabel,
-				LatestVersionID:  r.ModFileVersionID,
-				LatestVersionStr: r.VersionString,
-				LatestArchiveSHA: r.ArchiveSha256,
-				LatestImportedAt: r.ImportedAt,
-			})
+		if len(mods) == 0 {
+			fmt.Println(ui.Subtle.Render("No mods imported for this game yet."))
+			fmt.Println(ui.Subtle.Render("Use `modctl mods import <archive>` to add one."))
+			fmt.Println()
+			return printWorkshop()
 		}
 
-		// keep deterministic order even if SQL already sorted.
-		sort.Slice(pages, func(i, j int) bool {
-			if pages[i].ModName == pages[j].ModName {
-				return pages[i].ModPageID < pages[j].ModPageID
-			}
-			return pages[i].ModName < pages[j].ModName
-		})
+		fmt.Println(ui.Header.Render("Mods"))
+		fmt.Println()
 
 		// Helper formatters
-		shortSHA := func(ns sql.NullString) string {
-			if !ns.Valid || ns.String == "" {
+		shortSHA := func(s string) string {
+			if s == "" {
 				return "—"
 			}
-			s := ns.String
 			if len(s) > 12 {
 				s = s[:12]
 			}
 			return s
 		}
-		strOrDash := func(ns sql.NullString) string {
-			if !ns.Valid || ns.String == "" {
+		strOrDash := func(s string) string {
+			if s == "" {
 				return "—"
 			}
-			return ns.String
+			return s
 		}
-		intOrDash := func(ni sql.NullInt64) string {
-			if !ni.Valid {
-				return "—"
+		nexusRef := func(m modctl.Mod) string {
+			if m.NexusDomain == "" || m.NexusModID == 0 {
+				return ""
 			}
-			return fmt.Sprintf("%d", ni.Int64)
+			return fmt.Sprintf("%s:%d", m.NexusDomain, m.NexusModID)
 		}
 
 		if !modsListDetails {
-			for _, p := range pages {
+			for _, m := range mods {
 				// Header line
-				fmt.Printf("%d  %s\n", p.ModPageID, p.ModName)
-
-				// Optional nexus ref
-				nexusRef := ""
-				if p.NexusDomain.Valid && p.NexusModID.Valid {
-					nexusRef = fmt.Sprintf("%s:%d", p.NexusDomain.String, p.NexusModID.Int64)
-				}
+				fmt.Printf("%d  %s\n", m.PageID, m.Name)
 
 				line := fmt.Sprintf(
 					"  source=%s  files=%d  versions=%d",
-					p.SourceKind, p.FilesCount, p.VersionsCount,
+					m.SourceKind, m.Files, m.Versions,
 				)
 
-				if p.LatestVersionID.Valid {
+				if m.LatestVersionID != 0 {
 					line += fmt.Sprintf(
-						"  latest_file=%q  latest_version_id=%s  imported_at=%s  sha=%s",
-						strOrDash(p.LatestFileLabel),
-						intOrDash(p.LatestVersionID),
-						strOrDash(p.LatestImportedAt),
-						shortSHA(p.LatestArchiveSHA),
+						"  latest_file=%q  latest_version_id=%d  imported_at=%s  sha=%s",
+						strOrDash(m.LatestFileLabel),
+						m.LatestVersionID,
+						strOrDash(m.LatestImportedAt),
+						shortSHA(m.LatestArchiveSHA256),
 					)
-					if p.LatestVersionStr.Valid && p.LatestVersionStr.String != "" {
-						line += fmt.Sprintf("  version=%q", p.LatestVersionStr.String)
+					if m.LatestVersion != "" {
+						line += fmt.Sprintf("  version=%q", m.LatestVersion)
 					}
 				} else {
 					line += "  (no imported archives yet)"
 				}
 
-				if nexusRef != "" {
-					line += fmt.Sprintf("  nexus=%s", nexusRef)
+				if ref := nexusRef(m); ref != "" {
+					line += fmt.Sprintf("  nexus=%s", ref)
 					// TODO: add "nexus_latest=..." once Nexus API integration exists
 				}
 
-				fmt.Println(subtleStyle.Render(line))
+				fmt.Println(ui.Subtle.Render(line))
 				fmt.Println()
 			}
 
-			return nil
+			return printWorkshop()
 		}
 
-		for _, p := range pages {
-			fmt.Printf("%d  %s\n", p.ModPageID, p.ModName)
+		files, err := c.ModFiles(ctx, gi)
+		if err != nil {
+			return err
+		}
+		filesByPage := map[int64][]modctl.ModFile{}
+		for _, f := range files {
+			filesByPage[f.PageID] = append(filesByPage[f.PageID], f)
+		}
 
-			nexusRef := ""
-			if p.NexusDomain.Valid && p.NexusModID.Valid {
-				nexusRef = fmt.Sprintf("%s:%d", p.NexusDomain.String, p.NexusModID.Int64)
+		installSize := func(sha string) string {
+			n, err := c.InstallSize(ctx, sha)
+			if err != nil {
+				// e.g., the archive is missing (see modctl mods verify)
+				return "—"
 			}
+			return internal.FormatBytes(n)
+		}
+
+		for _, m := range mods {
+			fmt.Printf("%d  %s\n", m.PageID, m.Name)
 
 			line := fmt.Sprintf(
 				"  source=%s  files=%d  versions=%d",
-				p.SourceKind, p.FilesCount, p.VersionsCount,
+				m.SourceKind, m.Files, m.Versions,
 			)
-			if nexusRef != "" {
-				line += fmt.Sprintf("  nexus=%s", nexusRef)
+			if ref := nexusRef(m); ref != "" {
+				line += fmt.Sprintf("  nexus=%s", ref)
 				// TODO: add "nexus_latest=..." once Nexus API integration exists
 			}
-			fmt.Println(subtleStyle.Render(line))
-
-			files, err := q.ListModFilesByPage(ctx, p.ModPageID)
-			if err != nil {
-				return fmt.Errorf("list mod files (page_id=%d): %w", p.ModPageID, err)
-			}
+			fmt.Println(ui.Subtle.Render(line))
 
+			files := filesByPage[m.PageID]
 			if len(files) == 0 {
-				fmt.Println(subtleStyle.Render("  (no files)"))
+				fmt.Println(ui.Subtle.Render("  (no files)"))
 				fmt.Println()
 				continue
 			}
 
 			for _, f := range files {
 				primaryTag := ""
-				if f.IsPrimary != 0 {
+				if f.Primary {
 					primaryTag = " (primary)"
 				}
-				fmt.Println(subtleStyle.Render(fmt.Sprintf("  File: %s%s", f.Label, primaryTag)))
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf("  File %d: %s%s", f.ID, f.Label, primaryTag)))
 
-				vers, err := q.ListModFileVersionsByFile(ctx, f.ID)
-				if err != nil && !errors.Is(err, sql.ErrNoRows) {
-					return fmt.Errorf("list versions (file_id=%d): %w", f.ID, err)
-				}
-				if len(vers) == 0 {
-					fmt.Println(subtleStyle.Render("    (no versions)"))
+				if len(f.Versions) == 0 {
+					fmt.Println(ui.Subtle.Render("    (no versions)"))
 					continue
 				}
 
-				for _, v := range vers {
+				for _, v := range f.Versions {
 					vline := fmt.Sprintf(
 						"    v%d  imported_at=%s  sha=%s",
 						v.ID,
-						v.CreatedAt,
-						func() string {
-							s := v.ArchiveSha256
-							if len(s) > 12 {
-								s = s[:12]
-							}
-							return s
-						}(),
+						v.ImportedAt,
+						shortSHA(v.ArchiveSHA256),
 					)
 
-					if v.VersionString.Valid && v.VersionString.String != "" {
-						vline += fmt.Sprintf("  version=%q", v.VersionString.String)
+					if v.Version != "" {
+						vline += fmt.Sprintf("  version=%q", v.Version)
 					}
+					vline += "  install_size=" + installSize(v.ArchiveSHA256)
 
-					// TODO: think about also showing v.OriginalName later (only if not-null)
-					fmt.Println(subtleStyle.Render(vline))
+					// TODO: think about also showing the original name later
+					fmt.Println(ui.Subtle.Render(vline))
 				}
 			}
 
 			fmt.Println()
 		}
 
-		return nil
+		return printWorkshop()
 	},
+	Annotations: supportsDryRun,
+}
+
+// writeModsRecords writes the mods of a game as csv or tsv: a record per mod
+// page, or per version with --details.
+func writeModsRecords(ctx context.Context, c *modctl.Client, gi modctl.Game, mods []modctl.Mod) error {
+	if !modsListDetails {
+		records := make([][]string, 0, len(mods))
+		for _, m := range mods {
+			records = append(records, []string{
+				strconv.FormatInt(m.PageID, 10),
+				m.Name,
+				m.SourceKind,
+				m.NexusDomain,
+				intField(m.NexusModID),
+				strconv.FormatInt(m.Files, 10),
+				strconv.FormatInt(m.Versions, 10),
+				m.LatestFileLabel,
+				intField(m.LatestVersionID),
+				m.LatestVersion,
+				m.LatestImportedAt,
+				m.LatestArchiveSHA256,
+			})
+		}
+		return ui.WriteRecords(os.Stdout, modsListFormat, []string{
+			"mod_page_id", "name", "source", "nexus_game_domain", "nexus_mod_id",
+			"files", "versions", "latest_file", "latest_version_id",
+			"latest_version", "latest_imported_at", "latest_sha256",
+		}, records)
+	}
+
+	files, err := c.ModFiles(ctx, gi)
+	if err != nil {
+		return err
+	}
+	filesByPage := map[int64][]modctl.ModFile{}
+	for _, f := range files {
+		filesByPage[f.PageID] = append(filesByPage[f.PageID], f)
+	}
+
+	var records [][]string
+	for _, m := range mods {
+		for _, f := range filesByPage[m.PageID] {
+			for _, v := range f.Versions {
+				installSize := ""
+				// e.g., the archive is missing (see modctl mods verify)
+				if n, err := c.InstallSize(ctx, v.ArchiveSHA256); err == nil {
+					installSize = strconv.FormatInt(n, 10)
+				}
+				records = append(records, []string{
+					strconv.FormatInt(m.PageID, 10),
+					m.Name,
+					m.SourceKind,
+					m.NexusDomain,
+					intField(m.NexusModID),
+					strconv.FormatInt(f.ID, 10),
+					f.Label,
+					strconv.FormatBool(f.Primary),
+					intField(f.NexusFileID),
+					strconv.FormatInt(v.ID, 10),
+					v.Version,
+					v.UploadedAt,
+					v.ImportedAt,
+					v.ArchiveSHA256,
+					intField(v.SizeBytes),
+					installSize,
+				})
+			}
+		}
+	}
+	return ui.WriteRecords(os.Stdout, modsListFormat, []string{
+		"mod_page_id", "name", "source", "nexus_game_domain", "nexus_mod_id",
+		"file_id", "file", "primary", "nexus_file_id", "version_id", "version",
+		"uploaded_at", "imported_at", "sha256", "size_bytes", "install_size_bytes",
+	}, records)
 }
 
 func init() {
@@ -312,4 +361,5 @@ func init() {
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return completion.GameInstallSelectors(cmd, toComplete)
 		})
+	addFormatFlag(modsListCmd, &modsListFormat)
 }
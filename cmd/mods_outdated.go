@@ -0,0 +1,216 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsOutdatedGame       string
+	modsOutdatedChangelogs bool
+	modsOutdatedAll        bool
+)
+
+var modsOutdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Check Nexus-linked mods for newer versions",
+	Long: `Compare the newest imported version of every Nexus-linked mod with the
+latest version on Nexus Mods.
+
+Mods without an imported version string are reported as unknown; use
+` + "`modctl mods set-version`" + ` or ` + "`modctl mods backfill-versions`" + ` to fill them in.
+
+The files of the mods are compared too. A mod is outdated if a newer upload
+replaced one of its imported files (Nexus records which file replaced which)
+or if Nexus moved one to the old files, even if the version of the mod didn't
+change. Files that were uploaded since the last import and that don't replace
+an imported file (e.g., a new optional file) are listed without making the mod
+outdated. Use ` + "`modctl nexus files`" + ` to import them.
+
+With --changelogs, the upstream changelog entries between the imported and
+the latest version are shown for every outdated mod.
+
+Responses are cached, so running this repeatedly is cheap. If the API rate
+limit is reached, the remaining mods are reported as deferred. With --offline
+the cached responses are used however old they are, and the mods without any
+are deferred.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsOutdatedGame)
+		if err != nil {
+			return err
+		}
+
+		checks, err := c.CheckUpdates(ctx, gi)
+		if err != nil {
+			return err
+		}
+
+		if len(checks) == 0 {
+			fmt.Println(ui.Subtle.Render("No Nexus-linked mods for this game."))
+			fmt.Println(ui.Subtle.Render("Use `modctl mods import --nexus-url ...` to link one."))
+			return nil
+		}
+
+		fmt.Println(ui.Header.Render("Mod updates"))
+		fmt.Println()
+
+		counts := map[modctl.UpdateStatus]int{}
+		for _, u := range checks {
+			counts[u.Status]++
+
+			imported := u.ImportedVersion
+			if imported == "" {
+				imported = "?"
+			}
+
+			switch u.Status {
+			case modctl.UpdateStatusUpToDate:
+				if modsOutdatedAll {
+					fmt.Printf("%d  %s  %s\n", u.PageID, u.ModName,
+						ui.OK.Render(imported+" (up to date)"))
+				}
+				continue
+			case modctl.UpdateStatusOutdated:
+				if u.FilesReplaced() {
+					fmt.Printf("%d  %s  %s\n", u.PageID, u.ModName,
+						ui.Warn.Render(imported+" (files replaced upstream)"))
+				} else {
+					fmt.Printf("%d  %s  %s\n", u.PageID, u.ModName,
+						ui.Warn.Render(imported+" → "+u.LatestVersion))
+				}
+			case modctl.UpdateStatusNewFiles:
+				fmt.Printf("%d  %s  %s\n", u.PageID, u.ModName,
+					ui.OK.Render(imported+" (up to date, new files)"))
+			case modctl.UpdateStatusUnknown:
+				fmt.Printf("%d  %s  %s\n", u.PageID, u.ModName,
+					ui.Subtle.Render("? → "+u.LatestVersion+" (imported version unknown)"))
+			case modctl.UpdateStatusDeferred:
+				fmt.Printf("%d  %s  %s\n", u.PageID, u.ModName,
+					ui.Subtle.Render("deferred ("+deferredReason()+")"))
+				continue
+			default:
+				fmt.Printf("%d  %s  %s\n", u.PageID, u.ModName,
+					ui.Err.Render(u.Err.Error()))
+				continue
+			}
+
+			for _, s := range u.Superseded {
+				if s.NewFileID == 0 {
+					fmt.Println(ui.Warn.Render(fmt.Sprintf("    ↳ %s (file %d) was moved to the old files",
+						s.Label, s.NexusFileID)))
+					continue
+				}
+				replacement := fmt.Sprintf("file %d", s.NewFileID)
+				if s.NewFile.Name != "" {
+					replacement = fmt.Sprintf("%s (file %d)", s.NewFile.Name, s.NewFileID)
+					if s.NewFile.Version != "" {
+						replacement = fmt.Sprintf("%s v%s (file %d)", s.NewFile.Name, s.NewFile.Version, s.NewFileID)
+					}
+				}
+				fmt.Println(ui.Warn.Render(fmt.Sprintf("    ↳ %s (file %d) was replaced by %s",
+					s.Label, s.NexusFileID, replacement)))
+			}
+			for _, f := range u.NewFiles {
+				line := fmt.Sprintf("    + %s", f.Name)
+				if f.Version != "" {
+					line += " v" + f.Version
+				}
+				fmt.Println(line + ui.Subtle.Render(fmt.Sprintf("  new %s file %d",
+					strings.ReplaceAll(f.Category, "_", " "), f.FileID)))
+			}
+
+			if !modsOutdatedChangelogs || u.Status == modctl.UpdateStatusNewFiles {
+				continue
+			}
+
+			entries, err := c.Changelog(ctx, u)
+			if err != nil {
+				fmt.Println(ui.Err.Render("    changelog: " + err.Error()))
+				continue
+			}
+			if len(entries) == 0 {
+				fmt.Println(ui.Subtle.Render("    (no changelog)"))
+				continue
+			}
+			for _, e := range entries {
+				fmt.Println(ui.Subtle.Render("    " + e.Version + ":"))
+				for _, change := range e.Changes {
+					fmt.Println("      - " + html.UnescapeString(change))
+				}
+			}
+		}
+
+		fmt.Println()
+		summary := fmt.Sprintf("%d outdated, %d up to date",
+			counts[modctl.UpdateStatusOutdated], counts[modctl.UpdateStatusUpToDate])
+		if n := counts[modctl.UpdateStatusNewFiles]; n > 0 {
+			summary += fmt.Sprintf(", %d with new files", n)
+		}
+		if n := counts[modctl.UpdateStatusUnknown]; n > 0 {
+			summary += fmt.Sprintf(", %d unknown", n)
+		}
+		if n := counts[modctl.UpdateStatusDeferred]; n > 0 {
+			summary += fmt.Sprintf(", %d deferred", n)
+		}
+		if n := counts[modctl.UpdateStatusError]; n > 0 {
+			summary += fmt.Sprintf(", %d failed", n)
+		}
+		fmt.Println(ui.Subtle.Render(summary))
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsOutdatedCmd)
+
+	modsOutdatedCmd.Flags().StringVarP(&modsOutdatedGame, "game", "g", "",
+		"Override the currently active game")
+	modsOutdatedCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsOutdatedCmd.Flags().BoolVarP(&modsOutdatedChangelogs, "changelogs", "c", false,
+		"Show the upstream changelog of outdated mods")
+	modsOutdatedCmd.Flags().BoolVarP(&modsOutdatedAll, "all", "a", false,
+		"Also list mods that are up to date")
+}
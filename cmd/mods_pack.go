@@ -0,0 +1,164 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsPackGame     string
+	modsPackName     string
+	modsPackLabel    string
+	modsPackVersion  string
+	modsPackPageID   int64
+	modsPackOutput   string
+	modsPackAllowDup bool
+)
+
+var modsPackCmd = &cobra.Command{
+	Use:   "pack <dir>",
+	Short: "Package a directory as a mod archive and import it",
+	Long: `Package a directory (e.g., a mod that you made or changed yourself) into a
+compressed archive and import it like modctl mods import does.
+
+The archive is compressed as configured by archive_compression (default
+zstd:19; also xz or gzip, e.g. "xz:9").
+
+The archive is reproducible: entries are sorted, owned by root, and have a
+fixed modification time (the Unix epoch, or SOURCE_DATE_EPOCH if it is set),
+so packing the same files again produces the same archive, which modctl
+recognizes as already imported.
+
+The mod is named after the directory unless --name is given. Use --page-id to
+add the archive as a new version of an existing mod instead (e.g., after
+changing your mod).
+
+Pass --output to only write the archive to the given file without importing
+it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		dir := filepath.Clean(args[0])
+
+		if modsPackOutput != "" {
+			cmd.SilenceUsage = true
+			if err := modctl.PackDir(ctx, dir, modsPackOutput); err != nil {
+				return err
+			}
+			fmt.Printf("Packed %s into %s\n", dir, modsPackOutput)
+			return nil
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsPackGame)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		progress, finish := blobProgress("  " + filepath.Base(dir))
+		res, err := c.PackMod(ctx, gi, dir, modctl.ImportOptions{
+			PageID:         modsPackPageID,
+			ModName:        modsPackName,
+			FileLabel:      modsPackLabel,
+			Version:        modsPackVersion,
+			AllowDuplicate: modsPackAllowDup,
+			Progress:       progress,
+			OnWarning: func(w string) {
+				fmt.Println(ui.Warn.Render("  ⚠ " + w))
+			},
+		})
+		finish()
+		if err != nil {
+			var dup *modctl.DuplicateError
+			if errors.As(err, &dup) {
+				if dup.Restored {
+					fmt.Println("Restored the missing archive of:")
+				} else {
+					fmt.Println(ui.Warn.Render("Already imported (the files didn't change):"))
+				}
+				fmt.Printf("  mod_file_version_id: %d\n", dup.VersionID)
+				fmt.Printf("  mod: %s / %s\n", dup.ModName, dup.FileLabel)
+				fmt.Printf("  sha256: %s\n", dup.SHA256)
+				return nil
+			}
+			return err
+		}
+
+		fmt.Println("Packed and imported:")
+		fmt.Printf("  mod_page_id: %d\n", res.PageID)
+		fmt.Printf("  mod_file_id: %d\n", res.FileID)
+		fmt.Printf("  mod_file_version_id: %d\n", res.VersionID)
+		fmt.Printf("  sha256: %s\n", res.SHA256)
+		fmt.Printf("  size_bytes: %d\n", res.Size)
+		if len(res.Docs) > 0 {
+			fmt.Printf("  docs: %s %s\n", strings.Join(res.Docs, ", "),
+				ui.Subtle.Render("(see `modctl mods readme`)"))
+		}
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	modsCmd.AddCommand(modsPackCmd)
+
+	modsPackCmd.Flags().StringVarP(&modsPackGame, "game", "g", "",
+		"Override the currently active game")
+	modsPackCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsPackCmd.Flags().StringVar(&modsPackName, "name", "",
+		"Name for the mod (defaults to the directory name)")
+	modsPackCmd.Flags().StringVar(&modsPackLabel, "label", "",
+		"Label for the mod file (defaults to 'Main File')")
+	modsPackCmd.Flags().StringVar(&modsPackVersion, "version", "",
+		"Version string of the packed files")
+	modsPackCmd.Flags().Int64Var(&modsPackPageID, "page-id", 0,
+		"Attach the archive to an existing mod page")
+	modsPackCmd.Flags().StringVarP(&modsPackOutput, "output", "o", "",
+		"Only write the archive to this file, don't import it")
+	modsPackCmd.Flags().BoolVar(&modsPackAllowDup, "allow-duplicate", false,
+		"Import the archive even if it was already imported for this game")
+
+	modsPackCmd.MarkFlagsMutuallyExclusive("name", "page-id")
+}
@@ -0,0 +1,166 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsPruneGame         string
+	modsPruneKeepLatest   int64
+	modsPruneUnreferenced bool
+	modsPruneSuperseded   bool
+)
+
+var modsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old versions of mod files",
+	Long: `Remove superseded mod file versions of the current game.
+
+For every mod file, the --keep-latest newest versions (by import time) are
+kept and the older ones are removed. Archives that are no longer referenced
+by any mod file version (of any game) are deleted from the archive store.
+
+Versions whose files are currently installed or that are pinned in a locked
+profile (see ` + "`modctl profiles lock`" + `) are never removed. Versions that are
+pinned in other profiles are removed from those profiles as well unless
+--unreferenced-only is given, in which case they are kept.
+
+With --superseded-only, only versions whose Nexus file was replaced by a newer
+upload are removed, as recorded by the last ` + "`modctl mods outdated`" + ` (or
+` + "`modctl nexus files`" + `); versions without a known Nexus file are kept.
+
+Use --dry-run to see what would be removed and how much space it would free.`,
+	Args:         cobra.ExactArgs(0),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if modsPruneKeepLatest < 1 {
+			return fmt.Errorf("--keep-latest must be at least 1")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsPruneGame)
+		if err != nil {
+			return err
+		}
+
+		plan, err := c.PlanPrune(ctx, gi, modctl.PruneOptions{
+			KeepLatest:       modsPruneKeepLatest,
+			UnreferencedOnly: modsPruneUnreferenced,
+			SupersededOnly:   modsPruneSuperseded,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(plan.Versions) == 0 {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+				"Nothing to prune: no mod file has more than %d versions.", modsPruneKeepLatest)))
+			return nil
+		}
+
+		lastFile := int64(0)
+		for _, v := range plan.Versions {
+			if v.FileID != lastFile {
+				fmt.Println(ui.Header.Render(v.ModName + " / " + v.FileLabel))
+				lastFile = v.FileID
+			}
+
+			line := fmt.Sprintf("  v%d  imported_at=%s  sha=%s  size=%s",
+				v.ID, v.ImportedAt, v.SHA256[:12], internal.FormatBytes(v.Size))
+			if v.Version != "" {
+				line += fmt.Sprintf("  version=%q", v.Version)
+			}
+
+			if v.Kept != "" {
+				fmt.Println(ui.Warn.Render(line + "  (kept: " + v.Kept + ")"))
+				continue
+			}
+			if len(v.Profiles) > 0 {
+				line += "  (removed from profiles " + strings.Join(v.Profiles, ", ") + ")"
+			}
+			fmt.Println(ui.Subtle.Render(line))
+		}
+		fmt.Println()
+
+		if plan.Removed == 0 {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("Nothing pruned (%d versions kept)", plan.Kept())))
+			return nil
+		}
+
+		res, err := c.Prune(ctx, plan)
+		if err != nil {
+			return err
+		}
+		for _, w := range res.Warnings {
+			fmt.Fprintln(os.Stderr, ui.Warn.Render("warning: "+w))
+		}
+
+		freed := internal.FormatBytes(res.Freed)
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("Removed %d versions and %d archives, freed %s",
+				res.Versions, res.Archives, freed)),
+			fmt.Sprintf("remove %d versions and %d archives, freeing %s",
+				res.Versions, res.Archives, freed))
+		if plan.Kept() > 0 {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %d versions were kept", plan.Kept())))
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsPruneCmd)
+
+	modsPruneCmd.Flags().StringVarP(&modsPruneGame, "game", "g", "",
+		"Override the currently active game")
+	modsPruneCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsPruneCmd.Flags().Int64VarP(&modsPruneKeepLatest, "keep-latest", "k", 1,
+		"Number of versions to keep for each mod file")
+	modsPruneCmd.Flags().BoolVar(&modsPruneUnreferenced, "unreferenced-only", false,
+		"Only remove versions that aren't pinned in any profile")
+	modsPruneCmd.Flags().BoolVar(&modsPruneSuperseded, "superseded-only", false,
+		"Only remove versions whose Nexus file was replaced by a newer upload")
+}
@@ -0,0 +1,107 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the commands share their flags and the config: not parallel
+func TestModsPruneDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(cfg, nil, 0o644))
+	t.Cleanup(func() {
+		dryRun, cfgFile, dataDir = false, "", ""
+		modsPruneGame = ""
+		viper.Reset()
+	})
+
+	viper.Set("data_dir", dir)
+	require.NoError(t, internal.LoadConfig(cfg))
+	_, err := modctl.InitDB(ctx)
+	require.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", "file:"+viper.GetString("database")+internal.DB_PRAGMAS)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`
+		INSERT INTO game_installs (id, store_id, store_game_id, display_name, install_root)
+		VALUES (1, 'steam', '489830', 'Skyrim', ?);
+		INSERT INTO mod_pages (id, game_install_id, name, source_kind) VALUES (1, 1, 'SkyUI', 'manual');
+		INSERT INTO mod_files (id, mod_page_id, label) VALUES (1, 1, 'main');`,
+		filepath.Join(dir, "game"))
+	require.NoError(t, err)
+
+	// two versions of the same mod file, the older one is pruned
+	bs := internal.BlobStoreFromConfig()
+	var archives []string
+	for i, content := range []string{"old", "new"} {
+		sum := sha256.Sum256([]byte(content))
+		sha := hex.EncodeToString(sum[:])
+		path, err := bs.PathFor(blobstore.KindArchive, sha)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		archives = append(archives, path)
+
+		_, err = db.Exec(`INSERT INTO blobs (sha256, kind, size_bytes) VALUES (?, 'archive', ?)`,
+			sha, len(content))
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO mod_file_versions (id, mod_file_id, archive_sha256, created_at)
+			VALUES (?, 1, ?, ?)`, i+1, sha, []string{"2026-01-01T00:00:00.000Z", "2026-02-01T00:00:00.000Z"}[i])
+		require.NoError(t, err)
+	}
+
+	rootCmd.SetArgs([]string{"--config", cfg, "--data-dir", dir, "--dry-run",
+		"mods", "prune", "--game", "1"})
+	_, err = rootCmd.ExecuteC()
+	releaseStateLock()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, internal.FinishDryRun(ctx, &out))
+	assert.Contains(t, out.String(), "remove archive "+archives[0])
+
+	// nothing was actually removed
+	for _, path := range archives {
+		assert.FileExists(t, path)
+	}
+	var versions, blobs int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM mod_file_versions`).Scan(&versions))
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM blobs`).Scan(&blobs))
+	assert.Equal(t, 2, versions)
+	assert.Equal(t, 2, blobs)
+}
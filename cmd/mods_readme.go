@@ -0,0 +1,172 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsReadmeGame    string
+	modsReadmeVersion int64
+	modsReadmeKind    string
+	modsReadmeList    bool
+)
+
+var modsReadmeCmd = &cobra.Command{
+	Use:   "readme <mod>",
+	Short: "Show the readme, changelog, and license of a mod",
+	Long: `Show the documentation files (readme, changelog, and license) that were
+found in a mod's archive when it was imported.
+
+By default the newest version of the mod's primary file is used; pass
+--version to pick a specific mod file version. Use --kind to only show one
+kind of file (readme, changelog, or license) and --list to only list the files.
+
+Archives that were imported before modctl started keeping documentation files
+are read directly from the archive store instead.
+
+The mod can be given as its id, its name (case-insensitive), or the sha256 (or
+a prefix of at least eight characters) of one of its archives.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModPagesOrArchives(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch modsReadmeKind {
+		case "", modctl.DocReadme, modctl.DocChangelog, modctl.DocLicense:
+		default:
+			return fmt.Errorf("invalid --kind %q (expected readme, changelog, or license)", modsReadmeKind)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsReadmeGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.ModPage(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		v, err := c.Docs(ctx, p, modsReadmeVersion)
+		if err != nil {
+			return err
+		}
+
+		docs := v.Docs
+		if modsReadmeKind != "" {
+			filtered := docs[:0]
+			for _, d := range docs {
+				if d.Kind == modsReadmeKind {
+					filtered = append(filtered, d)
+				}
+			}
+			docs = filtered
+		}
+
+		title := fmt.Sprintf("%d  %s / %s  v%d", p.ID, p.Name, v.FileLabel, v.VersionID)
+		if v.Version != "" {
+			title += fmt.Sprintf(" (%s)", v.Version)
+		}
+
+		if len(docs) == 0 {
+			fmt.Println(ui.Header.Render(title))
+			what := "documentation files"
+			if modsReadmeKind != "" {
+				what = modsReadmeKind + " files"
+			}
+			fmt.Println(ui.Subtle.Render("  (no " + what + " found in the archive)"))
+			return nil
+		}
+
+		if modsReadmeList {
+			fmt.Println(ui.Header.Render(title))
+			for _, d := range docs {
+				fmt.Printf("  %-9s  %s  %s\n", d.Kind, d.Path,
+					ui.Subtle.Render(internal.FormatBytes(int64(len(d.Text)))))
+			}
+			return nil
+		}
+
+		for i, d := range docs {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(ui.Header.Render(fmt.Sprintf("== %s (%s) ==", d.Path, d.Kind)))
+			fmt.Print(d.Text)
+			if d.Truncated {
+				fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ truncated to the first %s",
+					internal.FormatBytes(modctl.MaxDocBytes))))
+			}
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsReadmeCmd)
+
+	modsReadmeCmd.Flags().StringVarP(&modsReadmeGame, "game", "g", "",
+		"Override the currently active game")
+	modsReadmeCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsReadmeCmd.Flags().Int64Var(&modsReadmeVersion, "version", 0,
+		"Show the files of this mod file version instead of the newest one")
+	modsReadmeCmd.RegisterFlagCompletionFunc("version",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ModFileVersions(cmd, toComplete)
+		})
+
+	modsReadmeCmd.Flags().StringVar(&modsReadmeKind, "kind", "",
+		"Only show files of this kind (readme, changelog, or license)")
+	modsReadmeCmd.RegisterFlagCompletionFunc("kind",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"readme", "changelog", "license"}, cobra.ShellCompDirectiveNoFileComp
+		})
+
+	modsReadmeCmd.Flags().BoolVar(&modsReadmeList, "list", false,
+		"Only list the documentation files")
+}
@@ -0,0 +1,154 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsRepairGame string
+	modsRepairRate string
+)
+
+var modsRepairCmd = &cobra.Command{
+	Use:   "repair [mod]",
+	Short: "Download missing or corrupted archives from Nexus again",
+	Long: `Find the archives that are missing from the blob store or were quarantined
+(by doctor --recheck or mods verify) and download them again from Nexus, for
+the mods that were imported with Nexus metadata.
+
+The file of the mod file is tried first, then files with the original name of
+the archive and then other files of the same size; a download only replaces
+the archive if its sha256 is exactly the one that was imported, so a newer
+upload of the same file is never used instead.
+
+Without a mod every archive of the game is checked. The mod can be given as its
+id, its name (case-insensitive), or the sha256 (or a prefix of at least eight
+characters) of one of its archives.
+
+Downloading through the Nexus API needs a premium account; archives of mods
+without Nexus metadata have to be imported again by hand. The downloads are
+no faster than download_limit_rate (or --limit-rate) and the limits of their
+host (see modctl nexus download-limits).
+
+The exit status is 1 if an archive could not be repaired.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModPagesOrArchives(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := setDownloadFlags(cmd, modsRepairRate, 0); err != nil {
+			return err
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsRepairGame)
+		if err != nil {
+			return err
+		}
+
+		var page *modctl.ModPage
+		if len(args) == 1 {
+			p, err := c.ModPage(ctx, gi, args[0])
+			if err != nil {
+				return err
+			}
+			page = &p
+		}
+
+		broken, err := c.BrokenArchives(ctx, gi, page)
+		if err != nil {
+			return err
+		}
+
+		if len(broken) == 0 {
+			fmt.Println(ui.OK.Render("✓ No missing or corrupted archives"))
+			return nil
+		}
+
+		failed := 0
+		for _, a := range broken {
+			label := fmt.Sprintf("%d  %s / %s", a.PageID, a.ModName, a.FileLabel)
+			problem := "missing"
+			if a.Quarantined {
+				problem = "quarantined"
+			}
+
+			progress, finish := blobProgress("  " + label)
+			err := c.RepairArchive(ctx, a, progress)
+			finish()
+			if errors.Is(err, context.Canceled) {
+				return fmt.Errorf("cancelled")
+			}
+			if err != nil {
+				failed++
+				fmt.Println(ui.Err.Render(fmt.Sprintf("✗ %s: archive is %s", label, problem)))
+				fmt.Println(ui.Subtle.Render("    " + err.Error()))
+				continue
+			}
+			fmt.Println(ui.OK.Render(fmt.Sprintf("✓ %s", label)) + "  " +
+				ui.Subtle.Render(fmt.Sprintf("%s  %s", a.SHA256[:12],
+					internal.FormatBytes(a.Size))))
+		}
+
+		if failed > 0 {
+			cmd.SilenceErrors = true
+			return exitCodeError{code: 1}
+		}
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	modsCmd.AddCommand(modsRepairCmd)
+
+	modsRepairCmd.Flags().StringVarP(&modsRepairGame, "game", "g", "",
+		"Override the currently active game")
+	modsRepairCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsRepairCmd.Flags().StringVar(&modsRepairRate, "limit-rate", "",
+		"Download no faster than this many bytes per second (e.g., 5M)")
+}
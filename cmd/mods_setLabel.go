@@ -0,0 +1,97 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var modsSetLabelGame string
+
+var modsSetLabelCmd = &cobra.Command{
+	Use:   "set-label <file-id> <label>",
+	Short: "Rename a mod file",
+	Long: `Change the label of a mod file (e.g., "Main File" or "Optional - 2K Textures").
+
+The file id is shown in ` + "`modctl mods list --details`" + `. Labels must be unique
+within a mod page.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileID, ok := internal.ParseInt64(args[0])
+		if !ok {
+			return fmt.Errorf("invalid file id %q", args[0])
+		}
+
+		label := strings.TrimSpace(args[1])
+		if label == "" {
+			return fmt.Errorf("label cannot be empty")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsSetLabelGame)
+		if err != nil {
+			return err
+		}
+
+		f, err := c.ModFile(ctx, gi, fileID)
+		if err != nil {
+			return err
+		}
+
+		if err := c.SetFileLabel(ctx, f, label); err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Renamed file %d (%s): %q -> %q", f.ID, f.ModName, f.Label, label),
+			fmt.Sprintf("rename file %d (%s): %q -> %q", f.ID, f.ModName, f.Label, label))
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsSetLabelCmd)
+
+	modsSetLabelCmd.Flags().StringVarP(&modsSetLabelGame, "game", "g", "",
+		"Override the currently active game")
+	modsSetLabelCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
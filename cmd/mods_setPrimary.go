@@ -0,0 +1,114 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsSetPrimaryGame  string
+	modsSetPrimaryUnset bool
+)
+
+var modsSetPrimaryCmd = &cobra.Command{
+	Use:   "set-primary <file-id>",
+	Short: "Mark a mod file as the primary file of its mod",
+	Long: `Mark a mod file as the primary file of its mod page.
+
+A mod page has at most one primary file, so any other file on the same page
+loses its primary flag. Use --unset to remove the flag from the given file
+without choosing a new one.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileID, ok := internal.ParseInt64(args[0])
+		if !ok {
+			return fmt.Errorf("invalid file id %q", args[0])
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsSetPrimaryGame)
+		if err != nil {
+			return err
+		}
+
+		f, err := c.ModFile(ctx, gi, fileID)
+		if err != nil {
+			return err
+		}
+
+		if modsSetPrimaryUnset {
+			if !f.Primary {
+				fmt.Printf("File %d (%s) is not the primary file; nothing to do\n", f.ID, f.ModName)
+				return nil
+			}
+		} else if f.Primary {
+			fmt.Printf("File %d (%s) is already the primary file\n", f.ID, f.ModName)
+			return nil
+		}
+
+		if err := c.SetPrimaryFile(ctx, f, !modsSetPrimaryUnset); err != nil {
+			return err
+		}
+
+		if modsSetPrimaryUnset {
+			dryrun.Report(os.Stdout,
+				fmt.Sprintf("File %d (%s) is no longer the primary file", f.ID, f.ModName),
+				fmt.Sprintf("unset file %d (%s) as the primary file", f.ID, f.ModName))
+		} else {
+			dryrun.Report(os.Stdout,
+				fmt.Sprintf("File %d (%s) is now the primary file", f.ID, f.ModName),
+				fmt.Sprintf("make file %d (%s) the primary file", f.ID, f.ModName))
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsSetPrimaryCmd)
+
+	modsSetPrimaryCmd.Flags().StringVarP(&modsSetPrimaryGame, "game", "g", "",
+		"Override the currently active game")
+	modsSetPrimaryCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsSetPrimaryCmd.Flags().BoolVar(&modsSetPrimaryUnset, "unset", false,
+		"Remove the primary flag instead of setting it")
+}
@@ -0,0 +1,130 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsSetVersionGame       string
+	modsSetVersionClear      bool
+	modsSetVersionUploadedAt string
+)
+
+var modsSetVersionCmd = &cobra.Command{
+	Use:   "set-version <version-id> [version]",
+	Short: "Correct the version string or upload time of an imported archive",
+	Long: `Set the version string and/or upload time of an imported mod file version.
+
+The version id is the "v<ID>" shown by ` + "`modctl mods list --details`" + `.
+
+Use --clear to remove the version string instead of setting it. Use
+--uploaded-at to set the upstream upload time; it accepts RFC 3339 timestamps,
+"YYYY-MM-DD HH:MM:SS", plain dates, or unix timestamps. Pass an empty string to
+clear it.`,
+	Example: `  modctl mods set-version 12 2.0.1
+  modctl mods set-version 12 --uploaded-at 2024-03-01
+  modctl mods set-version 12 --clear`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionID, ok := internal.ParseInt64(args[0])
+		if !ok {
+			return fmt.Errorf("invalid version id %q", args[0])
+		}
+
+		uploadedAtChanged := cmd.Flags().Changed("uploaded-at")
+		if len(args) == 2 && modsSetVersionClear {
+			return fmt.Errorf("cannot pass a version and --clear at the same time")
+		}
+		if len(args) == 1 && !modsSetVersionClear && !uploadedAtChanged {
+			return fmt.Errorf("nothing to do; pass a version, --clear, or --uploaded-at")
+		}
+
+		var u modctl.VersionUpdate
+		if len(args) == 2 {
+			u.Version = &args[1]
+		} else if modsSetVersionClear {
+			u.Version = new(string)
+		}
+		if uploadedAtChanged {
+			if modsSetVersionUploadedAt != "" {
+				if _, ok := internal.ParseUserTimestamp(modsSetVersionUploadedAt); !ok {
+					return fmt.Errorf("invalid --uploaded-at %q", modsSetVersionUploadedAt)
+				}
+			}
+			u.UploadedAt = &modsSetVersionUploadedAt
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsSetVersionGame)
+		if err != nil {
+			return err
+		}
+
+		v, err := c.ModFileVersion(ctx, gi, versionID)
+		if err != nil {
+			return err
+		}
+
+		if err := c.SetVersion(ctx, v, u); err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Updated v%d (%s / %s)", v.ID, v.ModName, v.FileLabel),
+			fmt.Sprintf("update v%d (%s / %s)", v.ID, v.ModName, v.FileLabel))
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsSetVersionCmd)
+
+	modsSetVersionCmd.Flags().StringVarP(&modsSetVersionGame, "game", "g", "",
+		"Override the currently active game")
+	modsSetVersionCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsSetVersionCmd.Flags().BoolVar(&modsSetVersionClear, "clear", false,
+		"Remove the version string")
+	modsSetVersionCmd.Flags().StringVar(&modsSetVersionUploadedAt, "uploaded-at", "",
+		"Set the upstream upload time (empty to clear)")
+}
@@ -0,0 +1,243 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modsVerifyGame    string
+	modsVerifyFile    int64
+	modsVerifyVersion int64
+)
+
+var modsVerifyCmd = &cobra.Command{
+	Use:   "verify <mod>",
+	Short: "Check the archives of a mod for corruption",
+	Long: `Hash the archives of a mod again and compare them with what was recorded
+when they were imported, e.g., after a crash or a disk problem or before
+sharing an export bundle. Unlike doctor --recheck this only reads the archives
+of one mod.
+
+By default every version of every file of the mod is checked; pass --file or
+--version to only check the versions of one mod file or a single version.
+
+A corrupted archive is moved to the quarantine directory (quarantine_dir in the
+config file) and the profiles that use it are listed; importing the archive
+again (e.g., downloaded from its Nexus page again) or mods repair puts it
+back.
+
+The mod can be given as its id, its name (case-insensitive), or the sha256 (or
+a prefix of at least eight characters) of one of its archives.
+
+The exit status is 1 if an archive is missing or corrupted.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModPagesOrArchives(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		// a corrupted archive is quarantined (which --read-only refuses)
+		if !readonly.Enabled() {
+			l, err := modctl.LockState(cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+			defer l.Release()
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, modsVerifyGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.ModPage(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		rows, err := c.ModArchives(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		vers := rows[:0]
+		for _, v := range rows {
+			if modsVerifyFile != 0 && v.FileID != modsVerifyFile {
+				continue
+			}
+			if modsVerifyVersion != 0 && v.ID != modsVerifyVersion {
+				continue
+			}
+			vers = append(vers, v)
+		}
+		switch {
+		case len(vers) > 0:
+		case modsVerifyVersion != 0:
+			return fmt.Errorf("mod file version %d does not belong to mod %d (%s)",
+				modsVerifyVersion, p.ID, p.Name)
+		case modsVerifyFile != 0:
+			return fmt.Errorf("mod file %d does not belong to mod %d (%s) or has no versions",
+				modsVerifyFile, p.ID, p.Name)
+		default:
+			return fmt.Errorf("mod %d (%s) has no imported versions", p.ID, p.Name)
+		}
+
+		fmt.Println(ui.Header.Render(fmt.Sprintf("%d  %s", p.ID, p.Name)))
+
+		// versions can share an archive, it's only checked (and
+		// quarantined) once
+		checked := map[string]error{}
+		bad, missing := 0, 0
+		var quarantined []*modctl.Quarantine
+		for _, v := range vers {
+			label := fmt.Sprintf("v%d  %s", v.ID, v.FileLabel)
+			if v.Version != "" {
+				label += fmt.Sprintf(" (%s)", v.Version)
+			}
+
+			verr, done := checked[v.SHA256]
+			if !done {
+				progress, finish := blobProgress("  " + label)
+				problem, q, err := c.VerifyArchive(ctx, v, progress)
+				finish()
+				if errors.Is(err, context.Canceled) {
+					return fmt.Errorf("cancelled")
+				}
+				if err != nil {
+					return err
+				}
+				if q != nil {
+					quarantined = append(quarantined, q)
+				}
+				verr = problem
+				checked[v.SHA256] = verr
+			}
+
+			switch {
+			case verr == nil:
+				fmt.Println(ui.OK.Render(fmt.Sprintf("  ✓ %s", label)) + "  " +
+					ui.Subtle.Render(fmt.Sprintf("%s  %s", v.SHA256[:12],
+						internal.FormatBytes(v.Size))))
+			case errors.Is(verr, os.ErrNotExist):
+				bad++
+				missing++
+				fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: archive is missing", label)))
+				fmt.Println(ui.Subtle.Render("    " + verr.Error()))
+			default:
+				bad++
+				fmt.Println(ui.Err.Render(fmt.Sprintf("  ✗ %s: archive is corrupted", label)))
+				fmt.Println(ui.Subtle.Render("    " + verr.Error()))
+			}
+		}
+
+		for _, q := range quarantined {
+			fmt.Println()
+			dryrun.Report(os.Stdout,
+				ui.Err.Render(fmt.Sprintf("Quarantined archive %s", q.SHA256[:12])),
+				fmt.Sprintf("quarantine archive %s", q.SHA256[:12]))
+			printQuarantine(q)
+		}
+
+		if missing > 0 {
+			fmt.Println()
+			fmt.Println(ui.Subtle.Render("  importing a missing archive again (or `modctl mods repair`) puts it back"))
+		}
+		if bad > 0 {
+			cmd.SilenceErrors = true
+			return exitCodeError{code: 1}
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	modsCmd.AddCommand(modsVerifyCmd)
+
+	modsVerifyCmd.Flags().StringVarP(&modsVerifyGame, "game", "g", "",
+		"Override the currently active game")
+	modsVerifyCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	modsVerifyCmd.Flags().Int64Var(&modsVerifyFile, "file", 0,
+		"Only check the versions of this mod file")
+
+	modsVerifyCmd.Flags().Int64Var(&modsVerifyVersion, "version", 0,
+		"Only check this mod file version")
+	modsVerifyCmd.RegisterFlagCompletionFunc("version",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ModFileVersions(cmd, toComplete)
+		})
+}
+
+// printQuarantine prints where a corrupted blob went and, for an archive,
+// which mods and profiles need it and where to download it again.
+func printQuarantine(q *modctl.Quarantine) {
+	if q.Problem != "" {
+		fmt.Println(ui.Subtle.Render("    " + q.Problem))
+	}
+	fmt.Println(ui.Subtle.Render("    moved to " + q.Path))
+
+	for _, m := range q.Mods {
+		line := fmt.Sprintf("    used by %d  %s / %s  v%d", m.PageID, m.ModName, m.FileLabel, m.VersionID)
+		if m.Version != "" {
+			line += fmt.Sprintf(" (%s)", m.Version)
+		}
+		fmt.Println(line + ui.Subtle.Render("  "+m.GameName))
+		if m.NexusFilesURL != "" {
+			fmt.Println(ui.Subtle.Render("      download it again: " + m.NexusFilesURL))
+		}
+	}
+	for _, p := range q.Profiles {
+		fmt.Println("    in profile " + p.Name + ui.Subtle.Render("  "+p.GameName))
+	}
+
+	if len(q.Mods) > 0 {
+		fmt.Println(ui.Subtle.Render("    importing the archive again (`modctl mods import`) puts it back, or"))
+		fmt.Println(ui.Subtle.Render("    `modctl mods repair` downloads it from nexus"))
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/offline"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// nexusCmd represents the nexus command
+var nexusCmd = &cobra.Command{
+	Use:   "nexus",
+	Short: "Interact with the Nexus Mods API",
+}
+
+func init() {
+	rootCmd.AddCommand(nexusCmd)
+}
+
+// setDownloadFlags makes --limit-rate and --concurrency (if they were given)
+// override download_limit_rate and download_concurrency.
+func setDownloadFlags(cmd *cobra.Command, rate string, concurrency int) error {
+	if cmd.Flags().Changed("limit-rate") {
+		if _, err := internal.ParseBytes(rate); err != nil {
+			return fmt.Errorf("--limit-rate: %w", err)
+		}
+		viper.Set("download_limit_rate", rate)
+	}
+	if cmd.Flags().Changed("concurrency") {
+		if concurrency < 0 {
+			return fmt.Errorf("--concurrency must not be negative: %d", concurrency)
+		}
+		viper.Set("download_concurrency", concurrency)
+	}
+	return nil
+}
+
+// deferredReason says why nexus requests were deferred: the rate limit, or
+// offline mode without cached responses.
+func deferredReason() string {
+	if offline.Enabled() {
+		return "offline, not cached"
+	}
+	return "rate limit"
+}
@@ -0,0 +1,151 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nexusBackfillGame string
+)
+
+var nexusBackfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Fill in missing version metadata from Nexus",
+	Long: `Fill in the file id, version string, upload time, and upstream notes of
+imported Nexus files that are missing them.
+
+For every mod file version of the game whose mod is linked to a Nexus mod and
+that is missing any of them, modctl looks the file up on Nexus Mods and
+records what it finds: the version of the file, when it was uploaded, and the
+changelog entry of that version as the upstream notes. A version without a
+file id (e.g., one that was imported from an archive that was downloaded with
+a browser) is matched to the file with the same filename, or to the only file
+that was uploaded at the time in a Nexus download filename. A version string that
+was guessed from the archive filename (see ` + "`modctl mods backfill-versions`" + `)
+is replaced with the one from Nexus; anything else that is already set is
+never modified.
+
+Every mod costs one API request, two if notes are missing. Responses are
+cached, and if the API rate limit is reached the remaining versions are
+reported as deferred, so just run the command again later.
+
+Use --dry-run to see what would change without updating the database.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, nexusBackfillGame)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		fills, err := c.BackfillNexus(ctx, gi)
+		if err != nil {
+			return err
+		}
+
+		if len(fills) == 0 {
+			fmt.Println(ui.Subtle.Render("No Nexus files are missing metadata."))
+			return nil
+		}
+
+		counts := map[modctl.BackfillStatus]int{}
+		for _, b := range fills {
+			counts[b.Status]++
+
+			prefix := fmt.Sprintf("v%d  %s / %s", b.VersionID, b.ModName, b.FileLabel)
+			switch b.Status {
+			case modctl.BackfillStatusFilled:
+				var filled []string
+				if b.FileResolved {
+					filled = append(filled, fmt.Sprintf("file_id=%d", b.FileID))
+				}
+				if b.VersionString != "" {
+					filled = append(filled, fmt.Sprintf("version=%q", b.VersionString))
+				}
+				if b.UploadedAt != "" {
+					filled = append(filled, "uploaded_at="+b.UploadedAt)
+				}
+				if b.UpstreamNotes != "" {
+					filled = append(filled, "notes")
+				}
+				fmt.Printf("%s  %s\n", prefix, ui.Subtle.Render(strings.Join(filled, "  ")))
+			case modctl.BackfillStatusNotFound:
+				fmt.Printf("%s  %s\n", prefix, ui.Warn.Render(fmt.Sprintf(
+					"file %d isn't on nexus anymore", b.FileID)))
+			case modctl.BackfillStatusUnmatched:
+				fmt.Printf("%s  %s\n", prefix, ui.Warn.Render(fmt.Sprintf(
+					"no file on nexus matches %q", b.OriginalName)))
+			case modctl.BackfillStatusError:
+				fmt.Printf("%s  %s\n", prefix, ui.Err.Render(b.Err.Error()))
+			}
+		}
+
+		summary := fmt.Sprintf("%d unchanged (nexus doesn't know more)",
+			counts[modctl.BackfillStatusUnchanged])
+		if n := counts[modctl.BackfillStatusDeferred]; n > 0 {
+			summary += fmt.Sprintf(", %d deferred (%s)", n, deferredReason())
+		}
+
+		if dryrun.Enabled() {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("dry run: would update %d versions; %s",
+				counts[modctl.BackfillStatusFilled], summary)))
+			return nil
+		}
+
+		fmt.Println(ui.OK.Render(fmt.Sprintf("Updated %d versions",
+			counts[modctl.BackfillStatusFilled])))
+		fmt.Println(ui.Subtle.Render("  " + summary))
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	nexusCmd.AddCommand(nexusBackfillCmd)
+
+	nexusBackfillCmd.Flags().StringVarP(&nexusBackfillGame, "game", "g", "",
+		"Override the currently active game")
+	nexusBackfillCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
@@ -0,0 +1,162 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nexusDownloadLimitsRate  string
+	nexusDownloadLimitsConc  int
+	nexusDownloadLimitsReset bool
+)
+
+var nexusDownloadLimitsCmd = &cobra.Command{
+	Use:   "download-limits [host]",
+	Short: "Show or set how politely files are downloaded from a host",
+	Long: `Show or set the download limits of a host, on top of the overall ones
+(download_limit_rate and download_concurrency): how fast its files are
+downloaded (bytes per second, e.g., 2M) and how many of them at the same
+time. The limits of a host also apply to its subdomains (e.g., nexus-cdn.com
+to supporter-files.nexus-cdn.com), which share them; the most specific host
+wins.
+
+Without a host, the overall limits and those of every host are shown. With a
+host and --limit-rate or --concurrency its limits are set (0 removes that
+limit); --reset removes both.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		changing := cmd.Flags().Changed("limit-rate") || cmd.Flags().Changed("concurrency") ||
+			nexusDownloadLimitsReset
+		if changing && len(args) == 0 {
+			return fmt.Errorf("which host? (e.g., modctl nexus download-limits nexus-cdn.com --limit-rate 2M)")
+		}
+		if changing {
+			l, err := modctl.LockState(cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+			defer l.Release()
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		if len(args) == 0 {
+			return printDownloadLimits(ctx, c)
+		}
+
+		limits, err := c.HostDownloadLimits(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if changing {
+			cmd.SilenceUsage = true
+
+			if cmd.Flags().Changed("limit-rate") {
+				n, err := internal.ParseBytes(nexusDownloadLimitsRate)
+				if err != nil {
+					return fmt.Errorf("--limit-rate: %w", err)
+				}
+				limits.Rate = max(n, 0)
+			}
+			if cmd.Flags().Changed("concurrency") {
+				if nexusDownloadLimitsConc < 0 {
+					return fmt.Errorf("--concurrency must not be negative: %d", nexusDownloadLimitsConc)
+				}
+				limits.Concurrency = int64(nexusDownloadLimitsConc)
+			}
+			if nexusDownloadLimitsReset {
+				limits.Rate, limits.Concurrency = 0, 0
+			}
+
+			if err := c.SetDownloadLimits(ctx, limits); err != nil {
+				return err
+			}
+		}
+
+		fmt.Println(limits.Host)
+		fmt.Printf("  rate:        %s\n", formatLimitRate(limits.Rate))
+		fmt.Printf("  concurrency: %s\n", formatConcurrency(limits.Concurrency))
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+// printDownloadLimits prints the overall download limits and those of every
+// host.
+func printDownloadLimits(ctx context.Context, c *modctl.Client) error {
+	global, hosts, err := c.DownloadLimits(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Header.Render("Download limits"))
+	fmt.Printf("  overall  rate %s, concurrency %s\n", formatLimitRate(global.Rate),
+		formatConcurrency(global.Concurrency))
+	for _, h := range hosts {
+		fmt.Printf("  %s  rate %s, concurrency %s\n", h.Host, formatLimitRate(h.Rate),
+			formatConcurrency(h.Concurrency))
+	}
+	if len(hosts) == 0 {
+		fmt.Println(ui.Subtle.Render("  no host limits; set some with modctl nexus download-limits <host> --limit-rate ..."))
+	}
+	return nil
+}
+
+func formatLimitRate(n int64) string {
+	if n <= 0 {
+		return "no limit"
+	}
+	return internal.FormatBytes(n) + "/s"
+}
+
+func formatConcurrency(n int64) string {
+	if n <= 0 {
+		return "no limit"
+	}
+	return fmt.Sprint(n)
+}
+
+func init() {
+	nexusCmd.AddCommand(nexusDownloadLimitsCmd)
+
+	nexusDownloadLimitsCmd.Flags().StringVar(&nexusDownloadLimitsRate, "limit-rate", "",
+		"Download from the host no faster than this many bytes per second (e.g., 2M; 0: no limit)")
+	nexusDownloadLimitsCmd.Flags().IntVar(&nexusDownloadLimitsConc, "concurrency", 0,
+		"Download this many files from the host at the same time (0: no limit)")
+	nexusDownloadLimitsCmd.Flags().BoolVar(&nexusDownloadLimitsReset, "reset", false,
+		"Remove the limits of the host")
+}
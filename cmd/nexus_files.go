@@ -0,0 +1,260 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/offline"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nexusFilesGame     string
+	nexusFilesDownload []int64
+	nexusFilesPick     bool
+	nexusFilesIgnAdv   bool
+	nexusFilesForce    bool
+	nexusFilesUnscan   bool
+	nexusFilesRate     string
+	nexusFilesConc     int
+)
+
+var nexusFilesCmd = &cobra.Command{
+	Use:   "files <mod>",
+	Short: "List the files of a Nexus mod and import some of them",
+	Long: `List every file on the Nexus Mods page of a mod, grouped by category (main,
+updates, optional, miscellaneous, old), newest first. Files that were already
+imported are marked.
+
+The mod can be given as a Nexus mod page URL or as a mod that was imported
+with Nexus metadata: its id, its name (case-insensitive), or the sha256 (or a
+prefix of at least eight characters) of one of its archives.
+
+Pass the ids of the files to download and import with --download (repeat it or
+separate the ids with commas), or --pick to be asked for them after the list.
+Every file becomes a mod file of the mod with its Nexus file id and category,
+labeled with its name on Nexus; a newer upload of a file with the same name is
+imported as a new version of the same mod file.
+
+Downloading through the Nexus API needs a premium account. Up to
+download_concurrency files are downloaded at the same time, no faster than
+download_limit_rate overall and the limits of their host (see modctl nexus
+download-limits); --concurrency and --limit-rate override the config.
+
+The exit status is 1 if a file could not be imported.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModPagesOrArchives(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := setDownloadFlags(cmd, nexusFilesRate, nexusFilesConc); err != nil {
+			return err
+		}
+
+		if len(nexusFilesDownload) > 0 || nexusFilesPick {
+			if err := offline.Check("download nexus files"); err != nil {
+				return err
+			}
+
+			l, err := modctl.LockState(cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+			defer l.Release()
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, nexusFilesGame)
+		if err != nil {
+			return err
+		}
+
+		m, err := c.NexusFiles(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Header.Render(m.Name) + "  " + ui.Subtle.Render(m.FilesURL))
+		if len(m.Groups) == 0 {
+			fmt.Println(ui.Subtle.Render("  (no files)"))
+			return nil
+		}
+
+		for _, g := range m.Groups {
+			fmt.Println()
+			fmt.Println(ui.Header.Render(g.Label))
+			for _, f := range g.Files {
+				line := fmt.Sprintf("  %-8d %s", f.FileID, f.Name)
+				if f.Version != "" {
+					line += "  v" + f.Version
+				}
+				details := []string{}
+				if f.Size > 0 {
+					details = append(details, internal.FormatBytes(f.Size))
+				}
+				if !f.UploadedAt.IsZero() {
+					details = append(details, f.UploadedAt.Local().Format("2006-01-02"))
+				}
+				if len(details) > 0 {
+					line += "  " + ui.Subtle.Render(strings.Join(details, "  "))
+				}
+				if _, ok := m.Imported[f.FileID]; ok {
+					line += "  " + ui.OK.Render("✓ imported")
+				}
+				fmt.Println(line)
+				if f.FileName != "" {
+					fmt.Println(ui.Subtle.Render("           " + f.FileName))
+				}
+			}
+		}
+
+		selected := nexusFilesDownload
+		if nexusFilesPick {
+			fmt.Println()
+			fmt.Fprint(os.Stderr, "Files to download (ids separated by spaces, empty for none): ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("read file ids: %w", err)
+			}
+			for _, field := range strings.FieldsFunc(line, func(r rune) bool {
+				return r == ' ' || r == ',' || r == '\t' || r == '\n' || r == '\r'
+			}) {
+				id, ok := internal.ParseInt64(field)
+				if !ok {
+					return fmt.Errorf("invalid file id %q", field)
+				}
+				selected = append(selected, id)
+			}
+		}
+		if len(selected) == 0 {
+			return nil
+		}
+
+		// check every id before downloading anything
+		var todo []modctl.NexusFile
+		seen := map[int64]bool{}
+		for _, id := range selected {
+			f, ok := m.File(id)
+			if !ok {
+				return fmt.Errorf("nexus file %d isn't a file of %s", id, m.Name)
+			}
+			if !seen[id] {
+				seen[id] = true
+				todo = append(todo, f)
+			}
+		}
+
+		fmt.Println()
+		label := fmt.Sprintf("  %d files", len(todo))
+		if len(todo) == 1 {
+			label = fmt.Sprintf("  %d  %s", todo[0].FileID, todo[0].Name)
+		}
+		progress, finish := blobProgress(label)
+		imports, err := c.ImportNexusFiles(ctx, gi, m, todo, modctl.NexusImportOptions{
+			IgnoreAdvisories: nexusFilesIgnAdv,
+			ForceScan:        nexusFilesForce,
+			AllowUnscanned:   nexusFilesUnscan,
+			Progress:         progress,
+			OnWarning:        printAdvisory,
+		})
+		finish()
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("cancelled")
+		}
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, imp := range imports {
+			label := fmt.Sprintf("%d  %s", imp.File.FileID, imp.File.Name)
+
+			var dup *modctl.DuplicateError
+			switch {
+			case errors.As(imp.Err, &dup):
+				fmt.Println(ui.Warn.Render(fmt.Sprintf("✓ %s", label)) + "  " +
+					ui.Subtle.Render(fmt.Sprintf("already imported as v%d (%s / %s)",
+						dup.VersionID, dup.ModName, dup.FileLabel)))
+			case imp.Err != nil:
+				failed++
+				fmt.Println(ui.Err.Render(fmt.Sprintf("✗ %s", label)))
+				fmt.Println(ui.Subtle.Render("    " + imp.Err.Error()))
+			default:
+				fmt.Println(ui.OK.Render(fmt.Sprintf("✓ %s", label)) + "  " +
+					ui.Subtle.Render(fmt.Sprintf("imported as v%d of mod %d",
+						imp.Result.VersionID, imp.Result.PageID)))
+			}
+		}
+
+		if failed > 0 {
+			cmd.SilenceErrors = true
+			return exitCodeError{code: 1}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	nexusCmd.AddCommand(nexusFilesCmd)
+
+	nexusFilesCmd.Flags().StringVarP(&nexusFilesGame, "game", "g", "",
+		"Override the currently active game")
+	nexusFilesCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	nexusFilesCmd.Flags().Int64SliceVar(&nexusFilesDownload, "download", nil,
+		"Download and import the files with these ids")
+	nexusFilesCmd.Flags().BoolVar(&nexusFilesPick, "pick", false,
+		"Ask which files to download and import")
+	nexusFilesCmd.Flags().BoolVar(&nexusFilesIgnAdv, "ignore-advisories", false,
+		"Import files even if the advisory feed knows them to be malicious")
+	nexusFilesCmd.Flags().BoolVar(&nexusFilesForce, "force", false,
+		"Import files even if the virus scanner detected something")
+	nexusFilesCmd.Flags().BoolVar(&nexusFilesUnscan, "allow-unscanned", false,
+		"Import files even if the virus scanner couldn't scan them")
+	nexusFilesCmd.Flags().StringVar(&nexusFilesRate, "limit-rate", "",
+		"Download no faster than this many bytes per second (e.g., 5M)")
+	nexusFilesCmd.Flags().IntVar(&nexusFilesConc, "concurrency", 0,
+		"Download this many files at the same time (0: no limit)")
+}
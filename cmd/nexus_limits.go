@@ -0,0 +1,114 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/offline"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var nexusLimitsRefresh bool
+
+var nexusLimitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Show the remaining Nexus API quota",
+	Long: `Show the Nexus Mods API rate limits as of the last API request.
+
+Nexus allows a number of requests per day and, once those are used up, a
+smaller number of requests per hour. modctl records the limits from every
+response and, when only nexus_rate_limit_reserve requests are left, waits up
+to nexus_rate_limit_max_wait for them to reset. Otherwise it answers from the
+response cache where it can and defers the rest of the requests.
+
+Use --refresh to make a (cheap) API request to get the current numbers.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		if nexusLimitsRefresh && offline.Enabled() {
+			fmt.Println(ui.Subtle.Render("Not refreshing: offline"))
+		} else if nexusLimitsRefresh {
+			if err := c.RefreshNexusRateLimits(ctx); err != nil {
+				return err
+			}
+		}
+
+		rl, err := c.NexusRateLimits(ctx)
+		if err != nil {
+			return err
+		}
+		if rl == nil {
+			fmt.Println(ui.Subtle.Render("No Nexus API requests made yet; use --refresh to check."))
+			return nil
+		}
+
+		now := time.Now()
+		reset := func(t time.Time) string {
+			if t.IsZero() {
+				return "unknown"
+			}
+			if !t.After(now) {
+				return "reset at " + t.Local().Format("2006-01-02 15:04")
+			}
+			return fmt.Sprintf("resets in %s", t.Sub(now).Round(time.Minute))
+		}
+
+		fmt.Println(ui.Header.Render("Nexus API rate limits"))
+		fmt.Printf("  hourly: %d / %d  %s\n", rl.HourlyRemaining, rl.HourlyLimit,
+			ui.Subtle.Render("("+reset(rl.HourlyReset)+")"))
+		fmt.Printf("  daily:  %d / %d  %s\n", rl.DailyRemaining, rl.DailyLimit,
+			ui.Subtle.Render("("+reset(rl.DailyReset)+")"))
+		fmt.Println(ui.Subtle.Render("  as of " + rl.ObservedAt.Local().Format("2006-01-02 15:04:05")))
+
+		remaining := rl.Remaining(now)
+		reserve := viper.GetInt64("nexus_rate_limit_reserve")
+		if remaining > reserve {
+			fmt.Println(ui.OK.Render(fmt.Sprintf("%d requests available", remaining-reserve)))
+		} else {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"Rate limit reached (keeping %d requests in reserve); requests are deferred until the next reset",
+				reserve)))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	nexusCmd.AddCommand(nexusLimitsCmd)
+
+	nexusLimitsCmd.Flags().BoolVar(&nexusLimitsRefresh, "refresh", false,
+		"Make an API request to get the current limits")
+}
@@ -0,0 +1,184 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nukeGame    string
+	nukeForce   bool
+	nukeNoCache bool
+	nukeWait    time.Duration
+	nukeYes     bool
+)
+
+var nukeCmd = &cobra.Command{
+	Use:   "nuke",
+	Short: "Return a game to stock and forget its mods",
+	Long: `Return a game install to stock and remove everything that modctl knows
+about it.
+
+Nuke:
+  1. unapplies the applied profile: removes every file that modctl deployed
+     and restores the files that they replaced
+  2. puts back any other backups (e.g., left behind by an interrupted apply)
+  3. compares the game files to the baseline (see ` + "`modctl games scan`" + `), if
+     there is one, and lists the files that changed or are missing
+  4. removes the profiles, mods, targets, baseline, and history of the game
+     install from the database
+
+Every file that is changed is listed. The game install has to be given with
+--game (the active game isn't used) and nothing happens without --yes: it
+only shows what would be removed.
+
+Like unapply, nuke refuses to remove deployed files (or to put back backups
+over files) that were changed since unless --force is given; the database
+isn't touched then. Pass --no-cache to hash every file to check it for changes
+instead of trusting the hashes of the ones that weren't touched since.
+
+The archives and backups stay in their stores. ` + "`modctl games refresh`" + `
+finds the game again, without any mods.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := c.Game(ctx, nukeGame)
+		if err != nil {
+			return err
+		}
+		selector := internal.FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
+
+		cmd.SilenceUsage = true
+
+		if !nukeYes {
+			pv, err := c.PreviewNuke(ctx, gi)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Nuking %s would:\n", selector)
+			fmt.Printf("  remove %d deployed file(s) and restore %d backup(s)\n",
+				pv.Files, pv.Backups)
+			fmt.Printf("  forget %d profile(s) and %d mod(s)\n", pv.Profiles, pv.Mods)
+			fmt.Println(ui.Subtle.Render("  run again with --yes to do it"))
+			return nil
+		}
+
+		res, err := c.Nuke(ctx, gi, modctl.ApplyOptions{
+			Force:        nukeForce,
+			NoHashCache:  nukeNoCache,
+			WaitForSteam: nukeWait,
+			OnSteamWait:  printSteamWait,
+		})
+		if err != nil {
+			printDriftHelp(gi, err)
+			var conflict *modctl.BackupConflictError
+			if errors.As(err, &conflict) {
+				fmt.Println(ui.Subtle.Render(
+					"  to keep the changed files, copy them somewhere else before passing --force"))
+			}
+			return fmt.Errorf("nuke: %w", err)
+		}
+
+		printDeployResult(gi, "Unapplied", "unapply", res.Unapply)
+		for _, p := range res.Restored {
+			fmt.Println(ui.Subtle.Render("  restored backup: " + p.String()))
+		}
+
+		switch {
+		case dryrun.Enabled():
+			// nothing was put back to compare to the baseline
+		case !res.Verified:
+			fmt.Println(ui.Subtle.Render("  no baseline to compare the game files to"))
+		case len(res.Changed) == 0 && len(res.Missing) == 0:
+			fmt.Println(ui.OK.Render("  ✓ the game files match the baseline"))
+		default:
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"  ⚠ %d file(s) changed and %d missing since the baseline was recorded",
+				len(res.Changed), len(res.Missing))))
+			for _, p := range res.Changed {
+				fmt.Println(ui.Subtle.Render("  changed: " + p.String()))
+			}
+			for _, p := range res.Missing {
+				fmt.Println(ui.Subtle.Render("  missing: " + p.String()))
+			}
+		}
+		if res.Added > 0 {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+				"  %d file(s) were created since the baseline was recorded (e.g., saves) and were left alone",
+				res.Added)))
+		}
+		if gi.StoreID == "steam" {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+				"  verify the game files in Steam to be sure that they're stock (%s)",
+				steamValidateURL(gi.StoreGameID))))
+		}
+
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("Forgot %s: %d profile(s) and %d mod(s)", selector, res.Profiles, res.Mods)),
+			fmt.Sprintf("forget %s: %d profile(s) and %d mod(s)", selector, res.Profiles, res.Mods))
+		fmt.Println(ui.Subtle.Render(
+			"  the archives and backups are still in their stores; `modctl games refresh` finds the game again"))
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	rootCmd.AddCommand(nukeCmd)
+
+	nukeCmd.Flags().StringVarP(&nukeGame, "game", "g", "",
+		"The game install to return to stock")
+	nukeCmd.MarkFlagRequired("game")
+	nukeCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	nukeCmd.Flags().BoolVar(&nukeForce, "force", false,
+		"Replace files that were changed since they were deployed or backed up")
+	nukeCmd.Flags().BoolVar(&nukeNoCache, "no-cache", false,
+		"Hash every file to check it for changes")
+	nukeCmd.Flags().DurationVar(&nukeWait, "wait-for-steam", 0,
+		"Wait this long for Steam to finish downloading or updating the game")
+	nukeCmd.Flags().BoolVar(&nukeYes, "yes", false,
+		"Really return the game to stock and remove its records")
+}
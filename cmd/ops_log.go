@@ -0,0 +1,162 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	opsLogGame   string
+	opsLogNotes  bool
+	opsLogSearch string
+	opsLogLimit  int64
+	opsLogJSON   bool
+)
+
+var opsLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List past operations and the notes about them",
+	Long: `List the newest operations (applies and unapplies) of every game, or of
+one with --game, with the notes that were taken about them (see
+` + "`modctl ops note`" + `).
+
+Pass --notes to only list the operations with notes, and --search to only
+list the ones with a note that contains the text (ignoring case), e.g., to
+find out which combination of mods made the game crash somewhere. With
+--verbose the mods that each apply deployed are listed too.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		filter := modctl.OperationLogFilter{
+			NotedOnly: opsLogNotes,
+			Search:    opsLogSearch,
+			Limit:     opsLogLimit,
+		}
+		if opsLogGame != "" {
+			gi, err := c.Game(ctx, opsLogGame)
+			if err != nil {
+				return err
+			}
+			filter.GameInstallID = gi.ID
+		}
+
+		cmd.SilenceUsage = true
+
+		entries, err := c.OperationLog(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		if opsLogJSON {
+			js, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encode log: %w", err)
+			}
+			fmt.Println(string(js))
+			return nil
+		}
+
+		if len(entries) == 0 {
+			fmt.Println(ui.Subtle.Render("No operations"))
+			return nil
+		}
+
+		for i, e := range entries {
+			if i > 0 {
+				fmt.Println()
+			}
+			printOperationLogEntry(e)
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+// printOperationLogEntry prints an operation of the log with its notes.
+func printOperationLogEntry(e modctl.OperationLogEntry) {
+	what := e.Game
+	if e.Profile != "" {
+		what += " / " + e.Profile
+	}
+	line := fmt.Sprintf("#%d  %s  %s %s  %s", e.ID, e.StartedAt, e.Type, e.Status, what)
+	switch e.Verdict() {
+	case modctl.NoteStatusPass:
+		fmt.Println(line + "  " + ui.OK.Render("✓ pass"))
+	case modctl.NoteStatusFail:
+		fmt.Println(line + "  " + ui.Err.Render("✗ fail"))
+	default:
+		fmt.Println(line)
+	}
+
+	if e.Type == "apply" && len(e.Mods) > 0 {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %d mods", len(e.Mods))))
+		if verbose {
+			for _, m := range e.Mods {
+				fmt.Println(ui.Subtle.Render("    " + m))
+			}
+		}
+	}
+
+	for _, n := range e.Notes {
+		text := n.Note
+		switch n.Status {
+		case modctl.NoteStatusPass:
+			text = ui.OK.Render("pass") + " " + text
+		case modctl.NoteStatusFail:
+			text = ui.Err.Render("fail") + " " + text
+		}
+		fmt.Println("  " + ui.Subtle.Render(n.CreatedAt) + "  " + text)
+	}
+}
+
+func init() {
+	opsCmd.AddCommand(opsLogCmd)
+
+	opsLogCmd.Flags().StringVarP(&opsLogGame, "game", "g", "",
+		"Only the operations of this game")
+	opsLogCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	opsLogCmd.Flags().BoolVar(&opsLogNotes, "notes", false, "Only the operations with notes")
+	opsLogCmd.Flags().StringVar(&opsLogSearch, "search", "",
+		"Only the operations with a note that contains this text")
+	opsLogCmd.Flags().Int64VarP(&opsLogLimit, "limit", "n", 20, "How many operations to list (0: all of them)")
+	opsLogCmd.Flags().BoolVar(&opsLogJSON, "json", false, "Print the log as JSON")
+}
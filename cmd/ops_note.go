@@ -0,0 +1,101 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	opsNotePass bool
+	opsNoteFail bool
+)
+
+var opsNoteCmd = &cobra.Command{
+	Use:   "note <id> [note]",
+	Short: "Take a note about how an applied revision worked",
+	Long: `Record what testing the mods that an apply deployed found, e.g.:
+
+  modctl ops note 42 "crashes in Whiterun" --fail
+  modctl ops note 43 --pass
+
+Pass --pass or --fail to say whether that combination of mods worked. An
+operation can have several notes; the newest one with a status is its
+verdict. ` + "`modctl ops log --notes`" + ` lists the applies with notes (and
+--search finds them by their text) together with the mods that they
+deployed.
+
+The id of an operation is printed at the end of an apply.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opID, ok := internal.ParseInt64(args[0])
+		if !ok {
+			return fmt.Errorf("invalid operation id %q", args[0])
+		}
+		note := ""
+		if len(args) == 2 {
+			note = args[1]
+		}
+		status := ""
+		switch {
+		case opsNotePass:
+			status = modctl.NoteStatusPass
+		case opsNoteFail:
+			status = modctl.NoteStatusFail
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		cmd.SilenceUsage = true
+
+		if err := c.AddOperationNote(ctx, opID, status, note); err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("Added a note to operation %d", opID)),
+			fmt.Sprintf("add a note to operation %d", opID))
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	opsCmd.AddCommand(opsNoteCmd)
+
+	opsNoteCmd.Flags().BoolVar(&opsNotePass, "pass", false, "The revision worked")
+	opsNoteCmd.Flags().BoolVar(&opsNoteFail, "fail", false, "The revision didn't work")
+	opsNoteCmd.MarkFlagsMutuallyExclusive("pass", "fail")
+}
@@ -0,0 +1,107 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var opsShowJSON bool
+
+var opsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show the report of a past operation",
+	Long: `Show the report of a past operation: what was deployed, overwritten,
+backed up, and skipped, and how long each phase took.
+
+Every apply writes its report (as text and as JSON) into the reports
+directory (see reports_dir). If the report file is gone (or the operation
+predates reports) it's put back together from the database, without the
+timing of the phases.
+
+The notes about the operation (see ` + "`modctl ops note`" + `) are listed after
+the report.
+
+The id of an operation is printed at the end of an apply.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: supportsDryRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opID, ok := internal.ParseInt64(args[0])
+		if !ok {
+			return fmt.Errorf("invalid operation id %q", args[0])
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		out, err := c.OperationReport(ctx, opID, opsShowJSON)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(string(out))
+		if !opsShowJSON {
+			return printOperationNotes(ctx, c, opID)
+		}
+		return nil
+	},
+}
+
+// printOperationNotes prints the notes about an operation, if it has any.
+func printOperationNotes(ctx context.Context, c *modctl.Client, opID int64) error {
+	notes, err := c.OperationNotes(ctx, opID)
+	if err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\nNotes (%d)\n", len(notes))
+	for _, n := range notes {
+		line := "  " + n.CreatedAt
+		if n.Status != "" {
+			line += "  " + n.Status
+		}
+		if n.Note != "" {
+			line += "  " + n.Note
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func init() {
+	opsCmd.AddCommand(opsShowCmd)
+
+	opsShowCmd.Flags().BoolVar(&opsShowJSON, "json", false, "Print the report as JSON")
+}
@@ -0,0 +1,38 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var overridesCmd = &cobra.Command{
+	Use:   "overrides",
+	Short: "Manage the files that a profile deploys on top of its mods",
+	Long: `Overrides are whole files that a profile deploys on top of its mods (e.g., a
+tweaked config file), they win over every mod that provides the same path.
+
+Template overrides can have ${name} variables that are expanded whenever the
+profile is applied, so the same override works in every install of the game
+(see modctl overrides set --help).`,
+}
+
+func init() {
+	rootCmd.AddCommand(overridesCmd)
+}
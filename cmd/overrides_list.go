@@ -0,0 +1,112 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overridesListGame    string
+	overridesListProfile string
+)
+
+var overridesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the overrides of a profile",
+	Long: `List the files that a profile deploys on top of its mods, by target. Template
+overrides are marked as such.`,
+	Args:        cobra.ExactArgs(0),
+	Annotations: supportsDryRun,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, overridesListGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, overridesListProfile)
+		if err != nil {
+			return err
+		}
+
+		overrides, err := c.Overrides(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		if len(overrides) == 0 {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("Profile %q has no overrides", p.Name)))
+			return nil
+		}
+
+		fmt.Println(ui.Header.Render(fmt.Sprintf("Overrides of %q", p.Name)))
+		fmt.Println()
+
+		for _, o := range overrides {
+			line := fmt.Sprintf("  %s/%s", o.Target, o.RelPath)
+			if o.Template {
+				line += ui.Subtle.Render(" (template)")
+			}
+			fmt.Println(line)
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("    %s  %s  updated %s",
+				o.SHA256[:12], internal.FormatBytes(o.Size), o.UpdatedAt)))
+			if o.Notes != "" {
+				fmt.Println(ui.Subtle.Render("    " + o.Notes))
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	overridesCmd.AddCommand(overridesListCmd)
+
+	overridesListCmd.Flags().StringVarP(&overridesListGame, "game", "g", "",
+		"Override the currently active game")
+	overridesListCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	overridesListCmd.Flags().StringVarP(&overridesListProfile, "profile", "p", "",
+		"Override the currently active profile")
+	overridesListCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
@@ -0,0 +1,144 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overridesSetGame     string
+	overridesSetProfile  string
+	overridesSetTarget   string
+	overridesSetNotes    string
+	overridesSetTemplate bool
+)
+
+var overridesSetCmd = &cobra.Command{
+	Use:   "set <path> <file>",
+	Short: "Deploy a file of your own at a path of a profile",
+	Long: `Store a copy of file as the override of path (relative to the target, by
+default ` + modctl.DefaultTarget + `) in a profile, replacing any previous override of the
+same path. The override wins over every mod that provides the same path and
+takes effect the next time the profile is applied.
+
+With --template the file is a template: ${name} variables are expanded for
+the game install whenever the profile is applied, so a config file with
+absolute paths (or a resolution) works in every install and instance. Write
+$${ for a literal ${. The variables are:
+
+  ${install_root}          the game's install directory
+  ${install_root_windows}  the same, as a proton game sees it (Z:\...)
+  ${profile}               the name of the profile
+  ${instance}              the instance of the install (e.g., default)
+  ${game_version}          the installed version of the game, if known
+
+the directories that targets can start with (${home}, ${config}, ${data},
+${compatdata}, ${prefix}, ${documents}, ${appdata_local},
+${appdata_roaming}, ${userdata}), and whatever the override_vars config
+option sets, e.g.:
+
+  [override_vars]
+  resolution = "1920x1080"
+
+  [override_vars."steam:489830#deck"]
+  resolution = "1280x800"
+
+Tables for a game (store:game) or an install (store:game#instance) win over
+the variables for every game. A template with a variable that doesn't exist
+for the install fails the apply instead of deploying a half expanded file.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, overridesSetGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, overridesSetProfile)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		var warnings []string
+		o, err := c.SetOverride(ctx, gi, p, overridesSetTarget, args[0], args[1], modctl.OverrideOptions{
+			Notes:     overridesSetNotes,
+			Template:  overridesSetTemplate,
+			OnWarning: func(w string) { warnings = append(warnings, w) },
+		})
+		if err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("Stored the override of %s/%s in profile %q", o.Target, o.RelPath, p.Name)),
+			fmt.Sprintf("store the override of %s/%s in profile %q", o.Target, o.RelPath, p.Name))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %s", o.SHA256[:12])))
+		for _, w := range warnings {
+			fmt.Println(ui.Warn.Render("warning: " + w))
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	overridesCmd.AddCommand(overridesSetCmd)
+
+	overridesSetCmd.Flags().StringVarP(&overridesSetTarget, "target", "t", modctl.DefaultTarget,
+		"The target that the path is relative to")
+	overridesSetCmd.Flags().StringVar(&overridesSetNotes, "notes", "",
+		"Why the file is overridden")
+	overridesSetCmd.Flags().BoolVar(&overridesSetTemplate, "template", false,
+		"Expand ${name} variables in the file when the profile is applied")
+
+	overridesSetCmd.Flags().StringVarP(&overridesSetGame, "game", "g", "",
+		"Override the currently active game")
+	overridesSetCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	overridesSetCmd.Flags().StringVarP(&overridesSetProfile, "profile", "p", "",
+		"Override the currently active profile")
+	overridesSetCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
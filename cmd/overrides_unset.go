@@ -0,0 +1,103 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overridesUnsetGame    string
+	overridesUnsetProfile string
+	overridesUnsetTarget  string
+)
+
+var overridesUnsetCmd = &cobra.Command{
+	Use:   "unset <path>",
+	Short: "Remove the override of a path from a profile",
+	Long: `Remove the override of path (relative to the target, by default
+` + modctl.DefaultTarget + `) from a profile. The stored copy of the file is deleted
+unless something else uses it.
+
+An override that is deployed can't be removed: unapply the profile first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, overridesUnsetGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, overridesUnsetProfile)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		rel, err := c.UnsetOverride(ctx, gi, p, overridesUnsetTarget, args[0])
+		if err != nil {
+			return err
+		}
+
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("Removed the override of %s/%s from profile %q", overridesUnsetTarget, rel, p.Name)),
+			fmt.Sprintf("remove the override of %s/%s from profile %q", overridesUnsetTarget, rel, p.Name))
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	overridesCmd.AddCommand(overridesUnsetCmd)
+
+	overridesUnsetCmd.Flags().StringVarP(&overridesUnsetTarget, "target", "t", modctl.DefaultTarget,
+		"The target that the path is relative to")
+
+	overridesUnsetCmd.Flags().StringVarP(&overridesUnsetGame, "game", "g", "",
+		"Override the currently active game")
+	overridesUnsetCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	overridesUnsetCmd.Flags().StringVarP(&overridesUnsetProfile, "profile", "p", "",
+		"Override the currently active profile")
+	overridesUnsetCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
@@ -0,0 +1,37 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Manage a profile's plugin load order",
+	Long: `Manage the plugin load order of games that have one (Skyrim Special Edition,
+Fallout 4, Starfield, etc.).
+
+modctl stores the load order per profile; it is the source of truth and is
+written to the game's plugins.txt. The load order can be sorted with LOOT.`,
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+}
@@ -0,0 +1,104 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pluginsListGame    string
+	pluginsListProfile string
+)
+
+var pluginsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show a profile's plugin load order",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, pluginsListGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, pluginsListProfile)
+		if err != nil {
+			return err
+		}
+
+		order, err := c.LoadOrder(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Header.Render(fmt.Sprintf("%s / %s", gi.DisplayName, p.Name)))
+		if len(order.Plugins) == 0 {
+			fmt.Println(ui.Subtle.Render("  (no load order yet; run `modctl plugins sort`)"))
+			return nil
+		}
+
+		for i, pl := range order.Plugins {
+			mark := " "
+			if pl.Enabled {
+				mark = "*"
+			}
+			fmt.Printf("  %3d %s %s\n", i, mark, pl.Name)
+		}
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  source=%s  updated_at=%s",
+			order.Source, order.UpdatedAt)))
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	pluginsCmd.AddCommand(pluginsListCmd)
+
+	pluginsListCmd.Flags().StringVarP(&pluginsListGame, "game", "g", "",
+		"Override the currently active game")
+	pluginsListCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	pluginsListCmd.Flags().StringVarP(&pluginsListProfile, "profile", "p", "",
+		"Override the currently active profile")
+	pluginsListCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
@@ -0,0 +1,125 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pluginsSortGame    string
+	pluginsSortProfile string
+)
+
+var pluginsSortCmd = &cobra.Command{
+	Use:   "sort",
+	Short: "Sort the plugin load order with LOOT",
+	Long: `Sort a profile's plugin load order using LOOT.
+
+modctl writes the profile's current load order (if it has one) to the game's
+plugins.txt, runs LOOT to sort it, and then reads the sorted order back into
+the database.
+
+The LOOT invocation can be changed with the loot_command config option (a list
+of arguments). The following variables are replaced in each argument:
+
+  ${game}         LOOT's name for the game (e.g., SkyrimSE)
+  ${game_path}    the game's install directory
+  ${local_path}   the directory that contains plugins.txt
+  ${plugins_txt}  the path to plugins.txt
+  ${prefix}       the game's proton prefix
+
+For example, to use the LOOT flatpak:
+
+  loot_command = ["flatpak", "run", "io.github.loot.loot", "--game", "${game}",
+    "--game-path", "${game_path}", "--auto-sort"]
+
+Set the loot_after_apply config option to sort the load order after every
+apply (or switch) that changed files as well.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, pluginsSortGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, pluginsSortProfile)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		before, after, err := c.SortPlugins(ctx, gi, p)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.OK.Render(fmt.Sprintf("Sorted %d plugins for profile %q", len(after), p.Name)))
+		if len(before) > 0 {
+			moved := 0
+			for i := range after {
+				if i >= len(before) || !strings.EqualFold(before[i].Name, after[i].Name) {
+					moved++
+				}
+			}
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %d positions changed", moved)))
+		}
+		fmt.Println(ui.Subtle.Render("  run `modctl plugins list` to see the load order"))
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	pluginsCmd.AddCommand(pluginsSortCmd)
+
+	pluginsSortCmd.Flags().StringVarP(&pluginsSortGame, "game", "g", "",
+		"Override the currently active game")
+	pluginsSortCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	pluginsSortCmd.Flags().StringVarP(&pluginsSortProfile, "profile", "p", "",
+		"Override the currently active profile")
+	pluginsSortCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
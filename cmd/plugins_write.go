@@ -0,0 +1,95 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pluginsWriteGame    string
+	pluginsWriteProfile string
+)
+
+var pluginsWriteCmd = &cobra.Command{
+	Use:   "write",
+	Short: "Write a profile's plugin load order to the game",
+	Long: `Write a profile's stored plugin load order to the game's plugins.txt.
+
+Use this to restore modctl's load order after the game or another tool changed
+plugins.txt.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, pluginsWriteGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, pluginsWriteProfile)
+		if err != nil {
+			return err
+		}
+
+		pluginsTxt, n, err := c.WritePlugins(ctx, gi, p)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.OK.Render(fmt.Sprintf("Wrote %d plugins for profile %q", n, p.Name)))
+		fmt.Println(ui.Subtle.Render("  " + pluginsTxt))
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	pluginsCmd.AddCommand(pluginsWriteCmd)
+
+	pluginsWriteCmd.Flags().StringVarP(&pluginsWriteGame, "game", "g", "",
+		"Override the currently active game")
+	pluginsWriteCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	pluginsWriteCmd.Flags().StringVarP(&pluginsWriteProfile, "profile", "p", "",
+		"Override the currently active profile")
+	pluginsWriteCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
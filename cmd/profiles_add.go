@@ -20,18 +20,14 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
 
-	"github.com/mattn/go-sqlite3"
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
@@ -54,6 +50,12 @@ override the target profile with --profile.
 If --priority is not provided, modctl assigns the next highest priority in the
 profile. Higher priority wins conflicts.`,
 	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModFileVersions(cmd, toComplete)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
@@ -63,128 +65,39 @@ profile. Higher priority wins conflicts.`,
 			return fmt.Errorf("invalid mod_file_version_id %q (expected a positive integer)", args[0])
 		}
 
-		err = internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesAddGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesAddGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
-
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesAddGame)
+		gi, err := clientGame(ctx, c, profilesAddGame)
 		if err != nil {
 			return err
 		}
 
-		p, err := internal.ResolveProfileArg(ctx, q, &gi, profilesAddProfile)
+		p, err := c.Profile(ctx, gi, profilesAddProfile)
 		if err != nil {
 			return err
 		}
 
-		tx, err := db.BeginTx(ctx, nil)
-		if err != nil {
-			return fmt.Errorf("error starting transaction: %w", err)
-		}
-		defer tx.Rollback()
-		qtx := q.WithTx(tx)
-
-		// Validate mod_file_version exists (nicer than FK failure).
-		if _, err := qtx.ExistsModFileVersion(ctx, versionID); err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("mod file version %d not found", versionID)
-			}
-			return fmt.Errorf("check mod file version: %w", err)
-		}
-
-		// Compute priority if not explicitly provided.
-		priority := profilesAddPriority
-		if profilesAddPriority == 0 {
-			maxPrio, err := qtx.GetMaxPriorityForProfile(ctx, p.ID)
-			if err != nil {
-				return fmt.Errorf("get max priority: %w", err)
-			}
-			priority = maxPrio + 1
-		} else {
-			// Explicit priority: ensure it isn't already in use for this profile.
-			_, err := qtx.IsPriorityTaken(ctx, dbq.IsPriorityTakenParams{
-				ProfileID: p.ID,
-				Priority:  profilesAddPriority,
-			})
-			if err == nil {
-				return fmt.Errorf("priority %d is already used in profile %q", profilesAddPriority, p.Name)
-			}
-			if !errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("check priority: %w", err)
-			}
-		}
-
-		enabledVal := int64(1) // default enabled=true
-		if profilesAddDisabled {
-			enabledVal = 0
-		}
-
-		itemID, err := qtx.CreateProfileItem(ctx, dbq.CreateProfileItemParams{
-			ProfileID:        p.ID,
-			ModFileVersionID: versionID,
-			Enabled:          enabledVal,
-			Priority:         priority,
+		itemID, priority, err := c.AddToProfile(ctx, p, versionID, modctl.AddOptions{
+			Priority: profilesAddPriority,
+			Disabled: profilesAddDisabled,
 		})
 		if err != nil {
-			// NOTE: This tells us it was a UNIQUE constraint, but not which one.
-			// We rely on the explicit IsPriorityTaken check for a clear duplicate-priority message.
-			var se sqlite3.Error
-			if errors.As(err, &se) {
-				if se.Code == sqlite3.ErrConstraint && se.ExtendedCode == sqlite3.ErrConstraintUnique {
-					// Most common case: duplicate version in profile
-					// (UNIQUE(profile_id, mod_file_version_id)).
-					// If user provided a priority on the command line
-					// duplicate priority should have been caught above
-					// unless a race occurred.
-					if profilesAddPriority != 0 {
-						return fmt.Errorf("could not add version %d to profile %q (duplicate version or priority conflict)", versionID, p.Name)
-					}
-					return fmt.Errorf("version %d is already in profile %q", versionID, p.Name)
-				}
-				if se.Code == sqlite3.ErrConstraint && se.ExtendedCode == sqlite3.ErrConstraintForeignKey {
-					// Should be prevented by ExistsModFileVersion,
-					// but keep a friendly message anyway.
-					return fmt.Errorf("invalid reference while adding version %d to profile %q", versionID, p.Name)
-				}
-			}
-			return fmt.Errorf("add to profile: %w", err)
-		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit: %w", err)
+			return err
 		}
 
-		fmt.Printf("Added version %d to profile %q (item_id=%d, priority=%d, enabled=%t)\n",
-			versionID, p.Name, itemID, priority, enabledVal != 0)
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Added version %d to profile %q (item_id=%d, priority=%d, enabled=%t)",
+				versionID, p.Name, itemID, priority, !profilesAddDisabled),
+			fmt.Sprintf("add version %d to profile %q (priority=%d, enabled=%t)",
+				versionID, p.Name, priority, !profilesAddDisabled))
 
 		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
@@ -197,7 +110,7 @@ func init() {
 			return completion.GameInstallSelectors(cmd, toComplete)
 		})
 
-	profilesAddCmd.Flags().StringVar(&profilesAddProfile, "profile", "p",
+	profilesAddCmd.Flags().StringVarP(&profilesAddProfile, "profile", "p", "",
 		"Override the currently active profile")
 	profilesAddCmd.RegisterFlagCompletionFunc("profile",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
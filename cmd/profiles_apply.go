@@ -0,0 +1,325 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	profilesApplyGame    string
+	profilesApplyProfile string
+	profilesApplyForce   bool
+	profilesApplyFull    bool
+	profilesApplyNoCache bool
+	profilesApplyWait    time.Duration
+	profilesApplyIgnAdv  bool
+	profilesApplyYes     bool
+	profilesApplyNoRed   bool
+)
+
+var profilesApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Deploy a profile to the game",
+	Long: `Deploy the files of a profile (as shown by modctl profiles plan) and its
+overrides to the game.
+
+Files that are already there and weren't deployed by modctl are backed up
+first and restored when the profile is unapplied. If a different profile is
+currently applied, the files that only it deploys are removed, so applying a
+profile also switches to it (see also modctl profiles switch).
+
+Only what changed since the profile was last applied is deployed: files that
+the same mod version or override already deployed are left alone. Pass --full
+to deploy every file again (e.g., to repair files that were changed outside of
+modctl).
+
+modctl refuses to replace files that it deployed but that were changed since
+(e.g., by the game or another tool) unless --force is given. It also refuses
+to start if the archives, files, and backups wouldn't fit on their
+filesystems, or while Steam is downloading, updating, or verifying the game
+(it would race modctl over the same files); pass --wait-for-steam to wait
+for it instead.
+
+To check for changes, only the deployed files that were touched since they
+were last hashed (by their size, modification time, and inode) are read
+again. Pass --no-cache to hash every one of them.
+
+Before an apply that is going to take a while (about a minute or more, going
+by how fast the previous applies were, or at least 2 GiB to extract if there
+are none), what it does is summarized: the mods, how much has to be
+extracted, and how long it should take. On a terminal modctl asks before
+going ahead; otherwise it refuses unless --yes is given.
+
+With the loot_after_apply config option, an apply that changed files sorts
+the plugin load order of the profile with LOOT afterwards (like modctl
+plugins sort) if modctl manages the load order of the game.
+
+For Cyberpunk 2077, an apply that changed REDmod mods (in the game's mods/
+directory) runs REDmod's deploy step afterwards, like modctl redmod deploy.
+Pass --no-redmod-deploy (or set the redmod_deploy_after_apply config option
+to false) to run it yourself later.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		// --game overrides active selection
+		gi, err := clientGame(ctx, c, profilesApplyGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, profilesApplyProfile)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		declined := false
+		err = applyProfile(ctx, c, gi, p, modctl.ApplyOptions{
+			Force:        profilesApplyForce,
+			Full:         profilesApplyFull,
+			NoHashCache:  profilesApplyNoCache,
+			WaitForSteam: profilesApplyWait,
+			OnSteamWait:  printSteamWait,
+
+			IgnoreAdvisories: profilesApplyIgnAdv,
+			Confirm:          confirmApply(profilesApplyYes, &declined),
+			OnAfterApply:     printAfterApply,
+			NoRedmodDeploy:   profilesApplyNoRed,
+		})
+		if declined && errors.Is(err, modctl.ErrApplyCancelled) {
+			fmt.Println("Nothing was changed.")
+			return nil
+		}
+		return err
+	},
+	Annotations: mutatingDryRun,
+}
+
+// confirmApply returns an ApplyOptions.Confirm that asks before a long apply
+// (see confirmLongApply); declined is set if the user said no when asked.
+func confirmApply(yes bool, declined *bool) func(modctl.ApplyEstimate) bool {
+	return func(est modctl.ApplyEstimate) bool {
+		ok, asked := confirmLongApply(est, yes)
+		*declined = asked && !ok
+		return ok
+	}
+}
+
+// confirmLongApply summarizes a long apply and returns whether to go ahead
+// with it: always with yes, otherwise if the user says so when asked on a
+// terminal (asked reports whether they were).
+func confirmLongApply(est modctl.ApplyEstimate, yes bool) (ok, asked bool) {
+	fmt.Println(ui.Header.Render("This apply is going to take a while:"))
+	fmt.Printf("  %d mods, %d of them to extract\n", est.Mods, est.ModsToExtract)
+	fmt.Printf("  %s of archives, %s to extract, %d files to deploy\n",
+		internal.FormatBytes(est.ArchiveBytes), internal.FormatBytes(est.UnpackedBytes), est.Files)
+	if est.DownloadBytes > 0 {
+		fmt.Printf("  %s of missing archives to fetch from the blob mirrors first\n",
+			internal.FormatBytes(est.DownloadBytes))
+	}
+	if est.Duration > 0 {
+		fmt.Printf("  about %s (going by the previous applies)\n", est.Duration.Round(time.Second))
+	} else {
+		fmt.Println(ui.Subtle.Render("  no previous applies to estimate how long it takes"))
+	}
+
+	if yes {
+		return true, false
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(ui.Subtle.Render("  run again with --yes to do it"))
+		return false, false
+	}
+
+	fmt.Fprint(os.Stderr, "Continue? [y/N] ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, true
+	}
+	return false, true
+}
+
+// applyProfile plans a profile and deploys it to the game.
+func applyProfile(ctx context.Context, c *modctl.Client, gi modctl.Game, p modctl.Profile, opts modctl.ApplyOptions) error {
+	pl, err := c.Plan(ctx, gi, p)
+	if err != nil {
+		return err
+	}
+	for _, w := range pl.Warnings {
+		fmt.Println(ui.Warn.Render("  ⚠ " + w))
+	}
+
+	res, err := c.Apply(ctx, gi, p, pl, opts)
+	if err != nil {
+		printDriftHelp(gi, err)
+		return fmt.Errorf("apply profile %q: %w", p.Name, err)
+	}
+
+	printDeployResult(gi, fmt.Sprintf("Applied profile %q", p.Name),
+		fmt.Sprintf("apply profile %q", p.Name), res)
+	return nil
+}
+
+// printDeployResult prints a summary of an apply or unapply.
+func printDeployResult(gi modctl.Game, done, would string, res modctl.DeployResult) {
+	for _, w := range res.Warnings {
+		fmt.Println(ui.Warn.Render("  ⚠ " + w))
+	}
+
+	dryrun.Report(os.Stdout, ui.OK.Render(done), would)
+	summary := fmt.Sprintf(
+		"  %d written, %d replaced, %d removed, %d restored, %d unchanged (%d backed up",
+		res.Written, res.Overwritten, res.Removed, res.Restored, res.Unchanged, res.BackedUp)
+	if res.Vanilla > 0 {
+		summary += fmt.Sprintf(", %d vanilla not backed up", res.Vanilla)
+	}
+	if res.NotBackedUp > 0 {
+		summary += fmt.Sprintf(", %d not backed up by target policy", res.NotBackedUp)
+	}
+	fmt.Println(ui.Subtle.Render(summary + ")"))
+	if res.OperationID != 0 && !dryrun.Enabled() {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+			"  report: modctl ops show %d", res.OperationID)))
+	}
+
+	if verbose {
+		for _, c := range res.Changed {
+			fmt.Println(ui.Subtle.Render("  " + c.String()))
+		}
+	}
+	if res.PluginsSorted > 0 {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  sorted %d plugins with LOOT", res.PluginsSorted)))
+	}
+
+	if len(res.SteamRestore) > 0 {
+		fmt.Println(ui.Warn.Render(fmt.Sprintf(
+			"  ⚠ %d vanilla file(s) were removed without a backup; restore them by verifying the game files in Steam (%s)",
+			len(res.SteamRestore), steamValidateURL(gi.StoreGameID))))
+		if verbose {
+			for _, c := range res.SteamRestore {
+				fmt.Println(ui.Subtle.Render("  " + c.String()))
+			}
+		}
+	}
+
+	if res.RedmodDeployed {
+		fmt.Println(ui.Subtle.Render("  REDmod mods changed: ran REDmod's deploy step"))
+	} else if res.RedmodPending {
+		fmt.Println(ui.Subtle.Render("  REDmod mods changed: run `modctl redmod deploy`"))
+	}
+}
+
+// steamValidateURL returns the URL that makes Steam verify the integrity of
+// the files of a game (by its app id).
+func steamValidateURL(appID string) string {
+	return "steam://validate/" + appID
+}
+
+// printAfterApply tells the user that a step after the apply (e.g., sorting
+// the load order) started.
+func printAfterApply(step string) {
+	fmt.Println(ui.Subtle.Render("  " + step + "..."))
+}
+
+// printSteamWait tells the user that an apply or unapply is waiting for
+// Steam.
+func printSteamWait(reason string) {
+	fmt.Println(ui.Subtle.Render("  waiting for Steam (" + reason + ")..."))
+}
+
+// printDriftHelp explains how to get back to vanilla files after a refused
+// apply or unapply.
+func printDriftHelp(gi modctl.Game, err error) {
+	var busy *modctl.SteamBusyError
+	if errors.As(err, &busy) {
+		fmt.Println(ui.Subtle.Render(
+			"  pass --wait-for-steam (e.g., --wait-for-steam 30m) to wait for it instead"))
+		return
+	}
+
+	var drift *modctl.DriftError
+	if !errors.As(err, &drift) || gi.StoreID != "steam" {
+		return
+	}
+
+	fmt.Println(ui.Subtle.Render(
+		"  to keep the changed files, copy them somewhere else before passing --force"))
+	fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+		"  to go back to vanilla files, run `modctl profiles unapply --force` and then verify the game files in Steam (%s)",
+		steamValidateURL(gi.StoreGameID))))
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesApplyCmd)
+
+	profilesApplyCmd.Flags().StringVarP(&profilesApplyGame, "game", "g", "",
+		"Override the currently active game")
+	profilesApplyCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesApplyCmd.Flags().StringVarP(&profilesApplyProfile, "profile", "p", "",
+		"Override the currently active profile")
+	profilesApplyCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+
+	profilesApplyCmd.Flags().BoolVar(&profilesApplyForce, "force", false,
+		"Replace deployed files even if they were changed since")
+	profilesApplyCmd.Flags().BoolVar(&profilesApplyFull, "full", false,
+		"Deploy every file again instead of only what changed")
+	profilesApplyCmd.Flags().BoolVar(&profilesApplyNoCache, "no-cache", false,
+		"Hash every deployed file to check it for changes")
+	profilesApplyCmd.Flags().DurationVar(&profilesApplyWait, "wait-for-steam", 0,
+		"Wait this long for Steam to finish downloading or updating the game")
+	profilesApplyCmd.Flags().BoolVar(&profilesApplyIgnAdv, "ignore-advisories", false,
+		"Apply mods that the advisory feed knows to be malicious")
+	profilesApplyCmd.Flags().BoolVar(&profilesApplyYes, "yes", false,
+		"Don't ask before an apply that is going to take a while")
+	profilesApplyCmd.Flags().BoolVar(&profilesApplyNoRed, "no-redmod-deploy", false,
+		"Don't run REDmod's deploy step after the apply")
+}
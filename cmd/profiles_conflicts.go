@@ -0,0 +1,201 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesConflictsGame    string
+	profilesConflictsProfile string
+	profilesConflictsMatrix  bool
+)
+
+var profilesConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Show the conflicts between the mods of a profile",
+	Long: `Show the files that more than one enabled mod of a profile provides, and
+which mod wins each of them (the one with the highest priority).
+
+Game integrations can report additional conflicts, e.g., Witcher 3 scripts
+that more than one mod changes and that need to be merged.
+
+For steam games the files are also compared with the subscribed Steam Workshop
+items (steamapps/workshop/content/<appid>): modctl doesn't manage those, but a
+Workshop item that ships the same file as a mod of the profile conflicts with
+it all the same.
+
+With --matrix, a matrix of the mods that conflict is shown instead of the
+files: the row of a mod counts the files that it wins over the mod of every
+column, its column the files that it loses to the mod of every row. That shows
+at a glance which mod keeps the texture or plugin of another from taking
+effect (see ` + "`modctl profiles hide`" + ` to let a lower priority mod win a
+file).`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, profilesConflictsGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, profilesConflictsProfile)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		pl, err := c.Plan(ctx, gi, p)
+		if err != nil {
+			return err
+		}
+
+		conflicts := pl.Conflicts()
+		fmt.Println(ui.Header.Render(fmt.Sprintf("%s / %s", gi.DisplayName, p.Name)))
+		for _, w := range pl.Warnings {
+			fmt.Println(ui.Warn.Render("  ⚠ " + w))
+		}
+		if len(conflicts) == 0 && len(pl.Notes) == 0 {
+			fmt.Println(ui.OK.Render("  no conflicts"))
+			return nil
+		}
+
+		if profilesConflictsMatrix {
+			printConflictMatrix(pl.ConflictMatrix())
+			if len(pl.Notes) > 0 {
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+					"  and %d conflicts that game integrations found, run without --matrix to see them",
+					len(pl.Notes))))
+			}
+			return nil
+		}
+
+		for _, f := range conflicts {
+			fmt.Printf("  %s\n", f.RelPath)
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("    winner: v%d %s (priority %d)",
+				f.Winner.VersionID, f.Winner.ModName, f.Winner.Priority)))
+			for _, s := range f.Shadowed {
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf("    over:   v%d %s (priority %d)",
+					s.VersionID, s.ModName, s.Priority)))
+			}
+		}
+
+		for _, n := range pl.Notes {
+			fmt.Printf("  %s\n", n.Key)
+			if n.Merged {
+				fmt.Println(ui.OK.Render("    merged (stored as an override of the profile)"))
+			} else {
+				fmt.Println(ui.Warn.Render("    " + n.Message))
+			}
+			for _, s := range n.Sources {
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf("    from:   v%d %s (%s)",
+					s.VersionID, s.ModName, s.Member)))
+			}
+			if !n.Merged && n.Kind == "script_merge" {
+				fmt.Println(ui.Subtle.Render("    run `modctl witcher3 merge " + n.Key + "`"))
+			}
+		}
+
+		fmt.Println()
+		fmt.Printf("%d conflicts\n", len(conflicts)+len(pl.Notes))
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+// printConflictMatrix renders the files that the mods of a conflict matrix
+// win over each other, with a column per mod (numbered like the rows).
+func printConflictMatrix(m modctl.ConflictMatrix) {
+	if len(m.Mods) == 0 {
+		return
+	}
+
+	headers := []string{" # ", " Mod "}
+	for i := range m.Mods {
+		headers = append(headers, " "+strconv.Itoa(i+1)+" ")
+	}
+	headers = append(headers, " Won ", " Lost ")
+
+	rows := [][]string{}
+	for i, it := range m.Mods {
+		row := []string{
+			" " + strconv.Itoa(i+1) + " ",
+			fmt.Sprintf(" %s (v%d, priority %d) ", truncate(it.ModName, 32), it.VersionID, it.Priority),
+		}
+		won := 0
+		for j := range m.Mods {
+			switch n := m.Won[i][j]; {
+			case i == j:
+				row = append(row, ui.Subtle.Render(" - "))
+			case n == 0:
+				row = append(row, ui.Subtle.Render(" · "))
+			default:
+				row = append(row, " "+strconv.Itoa(n)+" ")
+				won += n
+			}
+		}
+		row = append(row, " "+strconv.Itoa(won)+" ", " "+strconv.Itoa(m.Lost(i))+" ")
+		rows = append(rows, row)
+	}
+
+	fmt.Println(table.New().Headers(headers...).Rows(rows...))
+	fmt.Println(ui.Subtle.Render("  each cell counts the files that the mod of the row wins over the mod of the column"))
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesConflictsCmd)
+
+	profilesConflictsCmd.Flags().StringVarP(&profilesConflictsGame, "game", "g", "",
+		"Override the currently active game")
+	profilesConflictsCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesConflictsCmd.Flags().StringVarP(&profilesConflictsProfile, "profile", "p", "",
+		"Override the currently active profile")
+	profilesConflictsCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+
+	profilesConflictsCmd.Flags().BoolVarP(&profilesConflictsMatrix, "matrix", "m", false,
+		"Show which mods win how many files over which instead of the files")
+}
@@ -0,0 +1,152 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesCopyGame string
+	profilesCopyTo   string
+	profilesCopyName string
+)
+
+var profilesCopyCmd = &cobra.Command{
+	Use:   "copy [profile] --to <game>",
+	Short: "Copy a profile to another install of the same game",
+	Long: `Copy a profile (the active one unless a name is given) to another install of
+the same game, e.g., in a second Steam library, a GOG copy, or a copy made with
+` + "`modctl games duplicate`" + `. The copy has the same name unless --name is
+given.
+
+The copy keeps the order, enabled state, remap rules, and hidden files of the
+mods, the plugin order, and the overrides of the profile. The archives are
+already in the blob store, so nothing is downloaded or imported again: every
+mod uses the version of the other install with the same archive if there is
+one, otherwise a version of the archive is added there, under the mod with the
+same Nexus mod if it has one or under a copy of the mod page.
+
+Two installs are of the same game if they have the same canonical game, or
+else the same store game. The game version that the profile is built against
+is only copied between installs of the same store. Overrides of targets that
+the other install doesn't have are skipped. The copy isn't applied or made
+active.
+
+The game to copy to can be given as its install id or a selector (e.g.,
+steam:489830:copy).`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ProfileNames(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, profilesCopyGame)
+		if err != nil {
+			return err
+		}
+
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		}
+		p, err := c.Profile(ctx, gi, name)
+		if err != nil {
+			return err
+		}
+
+		to, err := c.Game(ctx, profilesCopyTo)
+		if err != nil {
+			return err
+		}
+
+		newName := profilesCopyName
+		if newName == "" {
+			newName = p.Name
+		}
+
+		cmd.SilenceUsage = true
+
+		res, err := c.CopyProfile(ctx, gi, p, to, newName)
+		if err != nil {
+			return err
+		}
+
+		dest := to.Selector()
+		dryrun.Report(os.Stdout,
+			ui.OK.Render(fmt.Sprintf("✓ Copied %s / %s to %s / %s", gi.DisplayName, p.Name, dest, newName)),
+			fmt.Sprintf("copy %s / %s to %s / %s", gi.DisplayName, p.Name, dest, newName))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf(
+			"  %d mods: %d already imported, %d added to existing mods, %d added as new mods",
+			res.Items, res.Reused, res.Linked, res.Created)))
+		if res.Plugins > 0 || res.Overrides > 0 {
+			fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %d plugins, %d overrides",
+				res.Plugins, res.Overrides)))
+		}
+		for _, o := range res.SkippedOverrides {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"  ⚠ override %s was skipped: the other install doesn't have its target", o)))
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesCopyCmd)
+
+	profilesCopyCmd.Flags().StringVarP(&profilesCopyGame, "game", "g", "",
+		"Override the currently active game")
+	profilesCopyCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesCopyCmd.Flags().StringVar(&profilesCopyTo, "to", "",
+		"Game install to copy the profile to")
+	profilesCopyCmd.MarkFlagRequired("to")
+	profilesCopyCmd.RegisterFlagCompletionFunc("to",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesCopyCmd.Flags().StringVar(&profilesCopyName, "name", "",
+		"Name of the copy (default: the name of the profile)")
+}
@@ -20,24 +20,20 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
-	"strconv"
 
-	"github.com/mattn/go-sqlite3"
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
 var (
 	profilesCreateGame        string
 	profilesCreateDescription string
+	profilesCreateGameVersion string
 )
 
 var profilesCreateCmd = &cobra.Command{
@@ -48,6 +44,9 @@ var profilesCreateCmd = &cobra.Command{
 Profiles are named mod sets. Exactly one profile can be active per game install.
 New profiles start inactive; use ` + "`modctl profiles set-active`" + ` to activate one.
 
+Use --game-version to declare the version of the game that the profile is
+built against (see ` + "`modctl profiles game-version`" + `).
+
 Note: modctl automatically creates a "default" profile during game refresh.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -56,63 +55,29 @@ Note: modctl automatically creates a "default" profile during game refresh.`,
 
 		name := args[0]
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesCreateGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesCreateGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
-
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesCreateGame)
+		gi, err := clientGame(ctx, c, profilesCreateGame)
 		if err != nil {
 			return err
 		}
 
-		var desc sql.NullString
-		if profilesCreateDescription != "" {
-			desc = sql.NullString{String: profilesCreateDescription, Valid: true}
-		}
-
-		id, err := q.CreateProfile(ctx, dbq.CreateProfileParams{
-			GameInstallID: gi.ID,
-			Name:          name,
-			Description:   desc,
-		})
+		p, err := c.CreateProfile(ctx, gi, name, profilesCreateDescription, profilesCreateGameVersion)
 		if err != nil {
-			var se sqlite3.Error
-			if errors.As(err, &se) && se.Code == sqlite3.ErrConstraint && se.ExtendedCode == sqlite3.ErrConstraintUnique {
-				return fmt.Errorf("profile %q already exists for this game", name)
-			}
-			return fmt.Errorf("create profile: %w", err)
+			return err
 		}
 
-		fmt.Printf("Created profile %q (id=%d)\n", name, id)
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Created profile %q (id=%d)", name, p.ID),
+			fmt.Sprintf("create profile %q", name))
 
 		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
@@ -127,4 +92,6 @@ func init() {
 
 	profilesCreateCmd.Flags().StringVarP(&profilesCreateDescription, "description", "d", "",
 		"Optional profile description")
+	profilesCreateCmd.Flags().StringVar(&profilesCreateGameVersion, "game-version", "",
+		"Game version that the profile is built against")
 }
@@ -20,17 +20,13 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
-	"strconv"
 
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
@@ -50,6 +46,7 @@ and related metadata). It does not change the filesystem; any installed files
 remain tracked via installed_files until you run apply/unapply later.
 
 Safety checks:
+- A locked profile (see modctl profiles lock) must be unlocked first.
 - If the profile is currently active (the default profile for commands), you
   must pass --force.
 - If the profile is the last applied profile for this game, you must pass
@@ -65,68 +62,30 @@ Safety checks:
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
 
-		profileName := args[0]
-
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesDeleteGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesDeleteGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
-
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesDeleteGame)
+		gi, err := clientGame(ctx, c, profilesDeleteGame)
 		if err != nil {
 			return err
 		}
 
-		p, err := q.GetProfileByName(ctx, dbq.GetProfileByNameParams{
-			GameInstallID: gi.ID,
-			Name:          profileName,
-		})
+		p, err := c.Profile(ctx, gi, args[0])
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("profile %q not found for this game", profileName)
-			}
-			return fmt.Errorf("lookup profile: %w", err)
+			return err
 		}
 
-		// Check applied profile guard.
-		appliedID, err := q.GetAppliedProfileIDForGame(ctx, gi.ID)
+		applied, err := c.AppliedProfile(ctx, gi)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("game install %d not found", gi.ID)
-			}
-			return fmt.Errorf("get applied profile: %w", err)
+			return err
 		}
-
-		isApplied := appliedID.Valid && appliedID.Int64 == p.ID
-		isActive := p.IsActive != 0
+		isApplied := applied != nil && applied.ID == p.ID
 
 		// Enforce safety flags.
-		if isActive && !profilesDeleteForce {
+		if p.Active && !profilesDeleteForce {
 			return fmt.Errorf("profile %q is currently active; pass --force to delete it", p.Name)
 		}
 
@@ -145,25 +104,17 @@ Safety checks:
 			)
 		}
 
-		tx, err := db.BeginTx(ctx, nil)
-		if err != nil {
-			return fmt.Errorf("error starting transaction: %w", err)
-		}
-		defer tx.Rollback()
-		qtx := q.WithTx(tx)
-
-		if err := qtx.DeleteProfileByID(ctx, p.ID); err != nil {
-			return fmt.Errorf("delete profile: %w", err)
-		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit: %w", err)
+		if err := c.DeleteProfile(ctx, p); err != nil {
+			return err
 		}
 
-		fmt.Printf("Deleted profile %q\n", p.Name)
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Deleted profile %q", p.Name),
+			fmt.Sprintf("delete profile %q", p.Name))
 
 		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
@@ -25,10 +25,9 @@ import (
 	"os/signal"
 	"strconv"
 
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
@@ -45,6 +44,12 @@ var profilesDisableCmd = &cobra.Command{
 This keeps the version in the profile but marks it as inactive. Disabled
 versions are ignored when computing the applied mod set.`,
 	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModFileVersions(cmd, toComplete)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
@@ -54,48 +59,37 @@ versions are ignored when computing the applied mod set.`,
 			return fmt.Errorf("invalid mod_file_version_id %q (expected a positive integer)", args[0])
 		}
 
-		err = internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
+		gi, err := clientGame(ctx, c, profilesDisableGame)
 		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesDisableGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesDisableGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
+			return err
 		}
 
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesDisableGame)
+		p, err := c.Profile(ctx, gi, profilesDisableProfile)
 		if err != nil {
 			return err
 		}
 
-		p, err := internal.ResolveProfileArg(ctx, q, &gi, profilesDisableProfile)
+		changed, err := c.SetEnabled(ctx, p, versionID, false)
 		if err != nil {
 			return err
 		}
+		if !changed {
+			fmt.Printf("Version %d is already disabled in profile %q\n", versionID, p.Name)
+			return nil
+		}
 
-		return internal.SetProfileItemEnabled(ctx, &p, q, versionID, false)
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Disabled version %d in profile %q", versionID, p.Name),
+			fmt.Sprintf("disable version %d in profile %q", versionID, p.Name))
+		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
@@ -108,7 +102,7 @@ func init() {
 			return completion.GameInstallSelectors(cmd, toComplete)
 		})
 
-	profilesDisableCmd.Flags().StringVar(&profilesDisableProfile, "profile", "p",
+	profilesDisableCmd.Flags().StringVarP(&profilesDisableProfile, "profile", "p", "",
 		"Override the currently active profile")
 	profilesDisableCmd.RegisterFlagCompletionFunc("profile",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,149 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesExportGame    string
+	profilesExportProfile string
+)
+
+var profilesExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export a profile as a shareable collection",
+	Long: `Describe a profile as a collection that others can use to reproduce it: for
+every mod its name, file, and version, where to download it (the nexus game
+domain, mod ID, and file ID, or its source URL), the sha256 and size of the
+archive to check the download against, and how it's installed (enabled,
+priority, remap rules, hidden files), followed by the plugin load order. The
+collection is written to stdout unless a file is given.
+
+The collection is a JSON object:
+
+  {"format": "modctl-collection", "version": 1, "exported_at": "...",
+   "game": {"name": "...", "store": "steam", "store_game_id": "..."},
+   "profile": {"name": "...", "game_version": "..."},
+   "mods": [{"name": "...", "file": "...", "version": "...",
+             "sha256": "...", "size_bytes": 123, "enabled": true,
+             "priority": 10, "symlinks": "copy",
+             "source": {"kind": "nexus"},
+             "nexus": {"game_domain": "...", "mod_id": 1, "file_id": 2,
+                       "url": "..."},
+             "rules": [{"type": "strip_components", "int": 1}],
+             "hidden": ["..."]}, ...],
+   "plugins": [{"name": "...", "enabled": true}, ...]}
+
+Mods are in install order (lowest priority first), so when two of them
+provide the same file the later one wins.
+
+The archives themselves and the overrides of the profile aren't exported.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, profilesExportGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, profilesExportProfile)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		var w io.Writer = os.Stdout
+		var out *os.File
+		if len(args) == 1 {
+			out, err = os.Create(args[0])
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+			w = out
+		}
+
+		res, err := c.ExportProfile(ctx, gi, p, w)
+		if err != nil {
+			if out != nil {
+				os.Remove(out.Name())
+			}
+			return err
+		}
+
+		if out != nil {
+			if err := out.Close(); err != nil {
+				return fmt.Errorf("write %s: %w", out.Name(), err)
+			}
+			fmt.Printf("Exported %d mods of %s / %s to %s\n", res.Mods,
+				gi.DisplayName, p.Name, out.Name())
+		}
+
+		// stderr, so that they don't end up in the collection on stdout
+		if res.Overrides > 0 {
+			fmt.Fprintln(os.Stderr, ui.Warn.Render(fmt.Sprintf(
+				"⚠ the %d overrides of the profile aren't part of the collection", res.Overrides)))
+		}
+		if res.Unsourced > 0 {
+			fmt.Fprintln(os.Stderr, ui.Warn.Render(fmt.Sprintf(
+				"⚠ %d mods have neither a nexus file nor a source URL to download them from", res.Unsourced)))
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesExportCmd)
+
+	profilesExportCmd.Flags().StringVarP(&profilesExportGame, "game", "g", "",
+		"Override the currently active game")
+	profilesExportCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesExportCmd.Flags().StringVarP(&profilesExportProfile, "profile", "p", "",
+		"Override the currently active profile")
+	profilesExportCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
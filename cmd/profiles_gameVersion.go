@@ -0,0 +1,163 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesGameVersionGame    string
+	profilesGameVersionCurrent bool
+	profilesGameVersionClear   bool
+)
+
+var profilesGameVersionCmd = &cobra.Command{
+	Use:   "game-version <profile> [version]",
+	Short: "Show or set the game version that a profile was built against",
+	Long: `Show or declare the version of the game that a profile was built against.
+
+Mods (and mod lists) often break when a game is patched, so applying a
+profile to another version of the game than the one that it declares warns.
+For Steam games the version is the build id that Steam installed (every update
+is a new build); modctl can't detect the version of other games, so their
+profiles never warn.
+
+Without a version, the declared and the installed version are shown. Pass
+--current to declare the version that is installed now (e.g., once the profile
+works with it), or --clear to stop comparing the versions.
+
+The declared version (or else the installed one) is also what targets whose
+template has a ${game_version} directory resolve to when the profile is
+applied: their files move to the directory of the new version.`,
+	Args: cobra.RangeArgs(1, 2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.ProfileNames(cmd, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 2 && (profilesGameVersionCurrent || profilesGameVersionClear) {
+			return fmt.Errorf("pass either a version, --current, or --clear")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, profilesGameVersionGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		installed, err := c.InstalledGameVersion(ctx, gi)
+		if err != nil {
+			return err
+		}
+
+		var version string
+		switch {
+		case len(args) == 2:
+			version = strings.TrimSpace(args[1])
+			if version == "" {
+				return fmt.Errorf("empty game version; pass --clear to unset it")
+			}
+		case profilesGameVersionCurrent:
+			if installed == "" {
+				return fmt.Errorf("can't detect the version of %s; pass it explicitly", gi.DisplayName)
+			}
+			version = installed
+		case profilesGameVersionClear:
+		default:
+			declared := ui.Subtle.Render("(not declared)")
+			if p.GameVersion != "" {
+				declared = p.GameVersion
+			}
+			detected := ui.Subtle.Render("(unknown)")
+			if installed != "" {
+				detected = installed
+			}
+			fmt.Printf("built against: %s\n", declared)
+			fmt.Printf("installed:     %s\n", detected)
+			if p.GameVersion != "" && installed != "" && installed != p.GameVersion {
+				fmt.Println(ui.Warn.Render("  ⚠ the installed version is different; mods might not work"))
+			}
+			return nil
+		}
+
+		if err := c.SetProfileGameVersion(ctx, p, version); err != nil {
+			return err
+		}
+
+		if version == "" {
+			dryrun.Report(os.Stdout,
+				fmt.Sprintf("Cleared the game version of profile %q", p.Name),
+				fmt.Sprintf("clear the game version of profile %q", p.Name))
+			return nil
+		}
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Profile %q is built against game version %s", p.Name, version),
+			fmt.Sprintf("set the game version of profile %q to %s", p.Name, version))
+		if installed != "" && installed != version {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("  ⚠ version %s is installed", installed)))
+		}
+
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesGameVersionCmd)
+
+	profilesGameVersionCmd.Flags().StringVarP(&profilesGameVersionGame, "game", "g", "",
+		"Override the currently active game")
+	profilesGameVersionCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesGameVersionCmd.Flags().BoolVar(&profilesGameVersionCurrent, "current", false,
+		"Declare the version of the game that is installed now")
+	profilesGameVersionCmd.Flags().BoolVar(&profilesGameVersionClear, "clear", false,
+		"Don't compare the game version when applying the profile")
+
+	profilesGameVersionCmd.MarkFlagsMutuallyExclusive("current", "clear")
+}
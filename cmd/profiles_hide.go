@@ -0,0 +1,115 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesHideGame    string
+	profilesHideProfile string
+)
+
+var profilesHideCmd = &cobra.Command{
+	Use:   "hide <version-id> <path>",
+	Short: "Hide a file of a mod version in a profile",
+	Long: `Hide a single file of a mod file version within a profile so that it isn't
+deployed. If a lower priority mod provides the same file it wins instead,
+without having to reorder the mods.
+
+The file is given by its destination path, as shown by modctl profiles
+conflicts or modctl profiles plan --files (e.g., Data/textures/a.dds).
+
+Hidden files take effect the next time the profile is applied.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModFileVersions(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		versionID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || versionID <= 0 {
+			return fmt.Errorf("invalid mod_file_version_id %q (expected a positive integer)", args[0])
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, profilesHideGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, profilesHideProfile)
+		if err != nil {
+			return err
+		}
+
+		rel, changed, err := c.SetFileHidden(ctx, p, versionID, args[1], true)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			fmt.Printf("%s is already hidden for version %d in profile %q\n", rel, versionID, p.Name)
+			return nil
+		}
+
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Hid %s of version %d in profile %q", rel, versionID, p.Name),
+			fmt.Sprintf("hide %s of version %d in profile %q", rel, versionID, p.Name))
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesHideCmd)
+
+	profilesHideCmd.Flags().StringVarP(&profilesHideGame, "game", "g", "",
+		"Override the currently active game")
+	profilesHideCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesHideCmd.Flags().StringVarP(&profilesHideProfile, "profile", "p", "",
+		"Override the currently active profile")
+	profilesHideCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
@@ -25,15 +25,18 @@ import (
 	"os/signal"
 	"strconv"
 
-	"github.com/charmbracelet/lipgloss"
-	"github.com/mfinelli/modctl/dbq"
 	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
-var profilesListGame string
+var (
+	profilesListGame    string
+	profilesListDetails bool
+	profilesListFormat  string
+)
 
 var profilesListCmd = &cobra.Command{
 	Use:   "list",
@@ -45,79 +48,168 @@ The active profile is marked with an asterisk (*).
 Profiles are independent mod configurations for a single game.
 Use: ` + "`modctl profiles set-active <name>`" + ` to switch the active profile.
 
+With --details, the enabled mods of every profile are listed (highest
+priority first) with the space that they take up once they're extracted, and
+the total that deploying the profile is estimated to take up. The estimate
+doesn't account for files that more than one mod provides, or for remap rules
+and hidden files that leave some of them out.
+
+With --format csv (or tsv) every profile is a record, or with --details every
+enabled mod of every profile (with its install size in bytes), for
+spreadsheets.
+
 The current active game is used unless --game is provided.`,
 	Args: cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// TODO: extract these somewhere else
-		headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
-		subtleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-		okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
-
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
 
-		err := internal.EnsureDBExists()
-		if err != nil {
+		if err := ui.CheckFormat(profilesListFormat); err != nil {
 			return err
 		}
 
-		db, err := internal.SetupDB()
+		c, err := modctl.Open(ctx)
 		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
+			return err
 		}
-		defer db.Close()
+		defer c.Close()
 
-		err = internal.MigrateDB(ctx, db)
+		gi, err := clientGame(ctx, c, profilesListGame)
 		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesListGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesListGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
+			return err
 		}
 
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesListGame)
+		profiles, err := c.Profiles(ctx, gi)
 		if err != nil {
 			return err
 		}
 
-		rows, err := q.ListProfilesByGameInstall(ctx, gi.ID)
-		if err != nil {
-			return fmt.Errorf("list profiles: %w", err)
+		if profilesListFormat != ui.FormatText {
+			return writeProfilesRecords(ctx, c, profiles)
 		}
 
-		if len(rows) == 0 {
-			fmt.Println(subtleStyle.Render("No profiles found"))
+		if len(profiles) == 0 {
+			fmt.Println(ui.Subtle.Render("No profiles found"))
 			return nil
 		}
 
-		fmt.Println(headerStyle.Render("Profiles"))
+		fmt.Println(ui.Header.Render("Profiles"))
 		fmt.Println()
 
-		for _, p := range rows {
+		for _, p := range profiles {
 			prefix := "  "
-			if p.IsActive != 0 {
-				prefix = okStyle.Render("  * ")
+			if p.Active {
+				prefix = ui.OK.Render("  * ")
+			}
+			lockTag := ""
+			if p.Locked {
+				lockTag = ui.Subtle.Render(" (locked)")
 			}
-			fmt.Printf("%s%s\n", prefix, p.Name)
+			fmt.Printf("%s%s%s\n", prefix, p.Name, lockTag)
 
-			if p.Description.Valid && p.Description.String != "" {
-				fmt.Println(subtleStyle.Render("    " + p.Description.String))
+			if p.Description != "" {
+				fmt.Println(ui.Subtle.Render("    " + p.Description))
+			}
+			if p.GameVersion != "" {
+				fmt.Println(ui.Subtle.Render("    built against game version " + p.GameVersion))
+			}
+
+			if profilesListDetails {
+				if err := printProfileSizes(ctx, c, p); err != nil {
+					return err
+				}
 			}
 		}
 
 		return nil
 	},
+	Annotations: supportsDryRun,
+}
+
+// printProfileSizes lists the enabled items of a profile with their install
+// size and the total.
+func printProfileSizes(ctx context.Context, c *modctl.Client, p modctl.Profile) error {
+	items, err := c.ProfileMods(ctx, p)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println(ui.Subtle.Render("    (no enabled mods)"))
+		return nil
+	}
+
+	var total int64
+	unknown := 0
+	for _, it := range items {
+		size := "—"
+		n, err := c.InstallSize(ctx, it.ArchiveSHA256)
+		if err != nil {
+			unknown++
+		} else {
+			total += n
+			size = internal.FormatBytes(n)
+		}
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("    %4d  %s / %s (v%d)  %s",
+			it.Priority, it.ModName, it.FileLabel, it.VersionID, size)))
+	}
+
+	line := fmt.Sprintf("    %d enabled mods, about %s when deployed", len(items), internal.FormatBytes(total))
+	if unknown > 0 {
+		line += fmt.Sprintf(" (without %d whose archive can't be read)", unknown)
+	}
+	fmt.Println(line)
+	return nil
+}
+
+// writeProfilesRecords writes the profiles of a game as csv or tsv: a record
+// per profile, or per enabled mod with --details.
+func writeProfilesRecords(ctx context.Context, c *modctl.Client, profiles []modctl.Profile) error {
+	if !profilesListDetails {
+		records := make([][]string, 0, len(profiles))
+		for _, p := range profiles {
+			records = append(records, []string{
+				strconv.FormatInt(p.ID, 10),
+				p.Name,
+				strconv.FormatBool(p.Active),
+				p.LockedAt,
+				p.Description,
+				p.GameVersion,
+				p.CreatedAt,
+				p.UpdatedAt,
+			})
+		}
+		return ui.WriteRecords(os.Stdout, profilesListFormat, []string{
+			"id", "name", "active", "locked_at", "description", "game_version",
+			"created_at", "updated_at",
+		}, records)
+	}
+
+	var records [][]string
+	for _, p := range profiles {
+		items, err := c.ProfileMods(ctx, p)
+		if err != nil {
+			return err
+		}
+		for _, it := range items {
+			installSize := ""
+			if n, err := c.InstallSize(ctx, it.ArchiveSHA256); err == nil {
+				installSize = strconv.FormatInt(n, 10)
+			}
+			records = append(records, []string{
+				p.Name,
+				strconv.FormatInt(it.Priority, 10),
+				it.ModName,
+				it.FileLabel,
+				strconv.FormatInt(it.VersionID, 10),
+				it.ArchiveSHA256,
+				installSize,
+			})
+		}
+	}
+	return ui.WriteRecords(os.Stdout, profilesListFormat, []string{
+		"profile", "priority", "mod", "file", "version_id", "sha256",
+		"install_size_bytes",
+	}, records)
 }
 
 func init() {
@@ -125,6 +217,9 @@ func init() {
 
 	profilesListCmd.Flags().StringVarP(&profilesListGame, "game", "g", "",
 		"Override the currently active game")
+	profilesListCmd.Flags().BoolVarP(&profilesListDetails, "details", "d", false,
+		"Show the enabled mods of every profile and their install size")
+	addFormatFlag(profilesListCmd, &profilesListFormat)
 	profilesListCmd.RegisterFlagCompletionFunc("game",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return completion.GameInstallSelectors(cmd, toComplete)
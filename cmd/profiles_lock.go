@@ -0,0 +1,115 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var profilesLockGame string
+
+var profilesLockCmd = &cobra.Command{
+	Use:   "lock [profile]",
+	Short: "Freeze a profile so that its mods can't be changed",
+	Long: `Lock a profile (the active one by default), e.g., a known-good profile of a
+finished playthrough, to protect it from accidental changes.
+
+The mods of a locked profile can't be added, removed, enabled, disabled,
+hidden, or reordered, the profile can't be deleted, and modctl mods prune
+keeps the versions that it uses. It can still be applied. Run modctl profiles
+unlock to change it again.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ProfileNames(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setProfileLocked(args, profilesLockGame, true)
+	},
+	Annotations: mutatingDryRun,
+}
+
+// setProfileLocked implements modctl profiles lock and unlock.
+func setProfileLocked(args []string, game string, locked bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c, err := modctl.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	gi, err := clientGame(ctx, c, game)
+	if err != nil {
+		return err
+	}
+
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
+	}
+	p, err := c.Profile(ctx, gi, name)
+	if err != nil {
+		return err
+	}
+
+	changed, err := c.SetProfileLocked(ctx, p, locked)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !changed && locked:
+		fmt.Printf("Profile %q is already locked\n", p.Name)
+	case !changed:
+		fmt.Printf("Profile %q is not locked\n", p.Name)
+	case locked:
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Locked profile %q", p.Name),
+			fmt.Sprintf("lock profile %q", p.Name))
+	default:
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Unlocked profile %q", p.Name),
+			fmt.Sprintf("unlock profile %q", p.Name))
+	}
+
+	return nil
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesLockCmd)
+
+	profilesLockCmd.Flags().StringVarP(&profilesLockGame, "game", "g", "",
+		"Override the currently active game")
+	profilesLockCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
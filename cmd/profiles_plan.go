@@ -0,0 +1,147 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesPlanGame    string
+	profilesPlanProfile string
+	profilesPlanFiles   bool
+)
+
+var profilesPlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what applying a profile would deploy",
+	Long: `Compute the files that applying a profile would deploy without changing
+anything.
+
+For every enabled mod in the profile modctl lists the files in its archive,
+maps them to their destination (using the mod's remap rules, or the game's
+integration if it has one, e.g., Cyberpunk 2077's REDmod), and picks the mod
+with the highest priority as the winner of every path that more than one mod
+provides.
+
+Pass --files to list every file that would be deployed.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, profilesPlanGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, profilesPlanProfile)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		pl, err := c.Plan(ctx, gi, p)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Header.Render(fmt.Sprintf("%s / %s", gi.DisplayName, p.Name)))
+		if pl.Integration != "generic" {
+			fmt.Println(ui.Subtle.Render("  integration: " + pl.Integration))
+		}
+		fmt.Println()
+
+		if len(pl.Mods) == 0 {
+			fmt.Println(ui.Subtle.Render("  (no enabled mods)"))
+			return nil
+		}
+
+		for _, m := range pl.Mods {
+			fmt.Printf("  %4d  v%-5d %s / %s\n", m.Priority, m.VersionID,
+				m.ModName, m.FileLabel)
+			line := fmt.Sprintf("               %d files", m.Files)
+			if m.Won != m.Files {
+				line += fmt.Sprintf(" (%d overwritten)", m.Files-m.Won)
+			}
+			if m.Hidden > 0 {
+				line += fmt.Sprintf(" (%d hidden)", m.Hidden)
+			}
+			line += "  layout=" + m.Layout
+			fmt.Println(ui.Subtle.Render(line))
+		}
+		fmt.Println()
+
+		conflicts := pl.Conflicts()
+		fmt.Printf("%d files, %d conflicts\n", len(pl.Files), len(conflicts)+len(pl.Notes))
+		if len(conflicts)+len(pl.Notes) > 0 {
+			fmt.Println(ui.Subtle.Render("  run `modctl profiles conflicts` for details"))
+		}
+
+		if profilesPlanFiles {
+			fmt.Println()
+			for _, f := range pl.Files {
+				fmt.Printf("  %s  %s\n", f.RelPath, ui.Subtle.Render(fmt.Sprintf("(v%d)", f.Winner.VersionID)))
+			}
+		}
+
+		for _, w := range pl.Warnings {
+			fmt.Println(ui.Warn.Render("  ⚠ " + w))
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesPlanCmd)
+
+	profilesPlanCmd.Flags().StringVarP(&profilesPlanGame, "game", "g", "",
+		"Override the currently active game")
+	profilesPlanCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesPlanCmd.Flags().StringVarP(&profilesPlanProfile, "profile", "p", "",
+		"Override the currently active profile")
+	profilesPlanCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+
+	profilesPlanCmd.Flags().BoolVar(&profilesPlanFiles, "files", false,
+		"List every file that would be deployed")
+}
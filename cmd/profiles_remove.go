@@ -20,17 +20,14 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
 
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
@@ -47,6 +44,12 @@ var profilesRemoveCmd = &cobra.Command{
 This permanently removes the version from the profile (opposite of "add").
 It does not change files on disk; changes take effect the next time you apply.`,
 	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ModFileVersions(cmd, toComplete)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
@@ -56,76 +59,32 @@ It does not change files on disk; changes take effect the next time you apply.`,
 			return fmt.Errorf("invalid mod_file_version_id %q (expected a positive integer)", args[0])
 		}
 
-		err = internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesRenameGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesRenameGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
-
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesRenameGame)
+		gi, err := clientGame(ctx, c, profilesRemoveGame)
 		if err != nil {
 			return err
 		}
 
-		p, err := internal.ResolveProfileArg(ctx, q, &gi, profilesAddProfile)
+		p, err := c.Profile(ctx, gi, profilesRemoveProfile)
 		if err != nil {
 			return err
 		}
 
-		// Locate the profile item row
-		id, err := q.GetProfileItemIDByVersion(ctx, dbq.GetProfileItemIDByVersionParams{
-			ProfileID:        p.ID,
-			ModFileVersionID: versionID,
-		})
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("version %d is not in profile %q", versionID, p.Name)
-			}
-			return fmt.Errorf("lookup profile item: %w", err)
-		}
-
-		tx, err := db.BeginTx(ctx, nil)
-		if err != nil {
-			return fmt.Errorf("error starting transaction: %w", err)
-		}
-		defer tx.Rollback()
-		qtx := q.WithTx(tx)
-
-		if err := qtx.DeleteProfileItemByID(ctx, id); err != nil {
-			return fmt.Errorf("remove from profile: %w", err)
-		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit: %w", err)
+		if err := c.RemoveFromProfile(ctx, p, versionID); err != nil {
+			return err
 		}
 
-		fmt.Printf("Removed version %d from profile %q\n", versionID, p.Name)
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Removed version %d from profile %q", versionID, p.Name),
+			fmt.Sprintf("remove version %d from profile %q", versionID, p.Name))
 		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
@@ -138,7 +97,7 @@ func init() {
 			return completion.GameInstallSelectors(cmd, toComplete)
 		})
 
-	profilesRemoveCmd.Flags().StringVar(&profilesRemoveProfile, "profile", "p",
+	profilesRemoveCmd.Flags().StringVarP(&profilesRemoveProfile, "profile", "p", "",
 		"Override the currently active profile")
 	profilesRemoveCmd.RegisterFlagCompletionFunc("profile",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -20,18 +20,13 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
-	"strconv"
 
-	"github.com/mattn/go-sqlite3"
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
 )
 
@@ -58,37 +53,13 @@ Profile names must be unique per game.`,
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesRenameGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesRenameGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
-
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesRenameGame)
+		gi, err := clientGame(ctx, c, profilesRenameGame)
 		if err != nil {
 			return err
 		}
@@ -96,40 +67,28 @@ Profile names must be unique per game.`,
 		oldName := args[0]
 		newName := args[1]
 
-		// Friendly "not found" error
-		p, err := q.GetProfileByName(ctx, dbq.GetProfileByNameParams{
-			GameInstallID: gi.ID,
-			Name:          oldName,
-		})
+		p, err := c.Profile(ctx, gi, oldName)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("profile %q not found for this game", oldName)
-			}
-			return fmt.Errorf("lookup profile: %w", err)
+			return err
 		}
 
-		// Attempt rename
-		if err := q.RenameProfile(ctx, dbq.RenameProfileParams{
-			Name: newName,
-			ID:   p.ID,
-		}); err != nil {
-			var se sqlite3.Error
-			if errors.As(err, &se) && se.Code == sqlite3.ErrConstraint && se.ExtendedCode == sqlite3.ErrConstraintUnique {
-				return fmt.Errorf("profile %q already exists for this game", newName)
-			}
-			return fmt.Errorf("rename profile: %w", err)
+		if err := c.RenameProfile(ctx, p, newName); err != nil {
+			return err
 		}
 
-		fmt.Printf("Renamed profile %q -> %q\n", oldName, newName)
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Renamed profile %q -> %q", oldName, newName),
+			fmt.Sprintf("rename profile %q -> %q", oldName, newName))
 
 		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
 	profilesCmd.AddCommand(profilesRenameCmd)
 
-	profilesRenameCmd.Flags().StringVarP(&profilesListGame, "game", "g", "",
+	profilesRenameCmd.Flags().StringVarP(&profilesRenameGame, "game", "g", "",
 		"Override the currently active game")
 	profilesRenameCmd.RegisterFlagCompletionFunc("game",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -20,21 +20,22 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
-	"strconv"
 
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
-var profilesSetActiveGame string
+var (
+	profilesSetActiveGame  string
+	profilesSetActiveForce bool
+	profilesSetActiveYes   bool
+)
 
 var profilesSetActiveCmd = &cobra.Command{
 	Use:   "set-active",
@@ -44,6 +45,10 @@ var profilesSetActiveCmd = &cobra.Command{
 Exactly one profile may be active per game at a time. Commands that operate on
 profile contents default to the active profile unless --profile is provided.
 
+If the apply_on_switch config option is enabled the profile is also applied
+(replacing the applied profile) like modctl profiles switch does, asking
+first if that is going to take a while (unless --yes is given).
+
 The current active game is used unless --game is provided.`,
 	Args: cobra.ExactArgs(1),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -57,95 +62,61 @@ The current active game is used unless --game is provided.`,
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer stop()
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
-		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-
-		// Resolve game install id: --game overrides active selection
-		if profilesSetActiveGame == "" {
-			active, err := state.LoadActive()
-			if err != nil {
-				return fmt.Errorf("load active selection: %w", err)
-			}
-			if active.ActiveGameInstallID == 0 {
-				return fmt.Errorf("no active game selected; run `modctl games set-active ...` or pass --game")
-			}
-			profilesSetActiveGame = strconv.FormatInt(active.ActiveGameInstallID, 10)
-		}
-
-		gi, err := internal.ResolveGameInstallArg(ctx, q, profilesSetActiveGame)
+		// --game overrides active selection
+		gi, err := clientGame(ctx, c, profilesSetActiveGame)
 		if err != nil {
 			return err
 		}
 
 		profileName := args[0]
 
-		p, err := q.GetProfileByName(ctx, dbq.GetProfileByNameParams{
-			GameInstallID: gi.ID,
-			Name:          profileName,
-		})
+		p, err := c.Profile(ctx, gi, profileName)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("profile %q not found for this game", profileName)
-			}
-			return fmt.Errorf("lookup profile: %w", err)
+			return err
 		}
-		if p.IsActive != 0 {
+		if p.Active {
 			// Already active: treat as idempotent.
 			fmt.Printf("Profile %q is already active\n", profileName)
 			return nil
 		}
 
-		tx, err := db.BeginTx(ctx, nil)
-		if err != nil {
-			return fmt.Errorf("error starting transaction: %w", err)
-		}
-		defer tx.Rollback()
-
-		qtx := q.WithTx(tx)
-
-		if err := qtx.DeactivateProfilesForGame(ctx, gi.ID); err != nil {
-			return fmt.Errorf("deactivate existing active profile: %w", err)
-		}
-
-		if err := qtx.ActivateProfileByName(ctx, dbq.ActivateProfileByNameParams{
-			GameInstallID: gi.ID,
-			Name:          profileName,
-		}); err != nil {
-			return fmt.Errorf("activate profile: %w", err)
+		if viper.GetBool("apply_on_switch") {
+			cmd.SilenceUsage = true
+			return switchProfile(ctx, c, gi, p, modctl.ApplyOptions{Force: profilesSetActiveForce},
+				profilesSetActiveYes)
 		}
 
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit: %w", err)
+		if err := c.ActivateProfile(ctx, gi, profileName); err != nil {
+			return err
 		}
 
-		fmt.Printf("Active profile set to %q\n", profileName)
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Active profile set to %q", profileName),
+			fmt.Sprintf("set the active profile to %q", profileName))
 
 		return nil
 	},
+	Annotations: mutatingDryRun,
 }
 
 func init() {
 	profilesCmd.AddCommand(profilesSetActiveCmd)
 
-	profilesSetActiveCmd.Flags().StringVarP(&profilesListGame, "game", "g", "",
+	profilesSetActiveCmd.Flags().StringVarP(&profilesSetActiveGame, "game", "g", "",
 		"Override the currently active game")
 	profilesSetActiveCmd.RegisterFlagCompletionFunc("game",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return completion.GameInstallSelectors(cmd, toComplete)
 		})
+
+	profilesSetActiveCmd.Flags().BoolVar(&profilesSetActiveForce, "force", false,
+		"With apply_on_switch, replace deployed files even if they were changed since")
+	profilesSetActiveCmd.Flags().BoolVar(&profilesSetActiveYes, "yes", false,
+		"With apply_on_switch, don't ask before a switch that is going to take a while")
 }
@@ -0,0 +1,184 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesSwitchGame    string
+	profilesSwitchForce   bool
+	profilesSwitchNoCache bool
+	profilesSwitchWait    time.Duration
+	profilesSwitchIgnAdv  bool
+	profilesSwitchYes     bool
+	profilesSwitchNoRed   bool
+)
+
+var profilesSwitchCmd = &cobra.Command{
+	Use:   "switch <profile>",
+	Short: "Replace the applied profile with another one and activate it",
+	Long: `Switch the game to a different profile in one step: the files of the
+currently applied profile are replaced by the files of the new profile, which
+then becomes the active profile.
+
+If deploying the new profile fails the previously applied profile is applied
+again (or, if there wasn't one, everything that was deployed is removed) and
+the active profile doesn't change.
+
+modctl refuses to replace files that it deployed but that were changed since
+(e.g., by the game or another tool) unless --force is given.
+
+To check for changes, only the deployed files that were touched since they
+were last hashed (by their size, modification time, and inode) are read
+again. Pass --no-cache to hash every one of them.
+
+Before a switch that is going to take a while what it deploys is summarized
+and modctl asks before going ahead (or, if it can't ask, refuses unless --yes
+is given), like modctl profiles apply does.
+
+Like modctl profiles apply, a switch that changed REDmod mods runs REDmod's
+deploy step afterwards unless --no-redmod-deploy is given.
+
+Set the apply_on_switch config option to make modctl profiles set-active
+behave like this command.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Only complete the first positional arg.
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ProfileNames(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		// --game overrides active selection
+		gi, err := clientGame(ctx, c, profilesSwitchGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		return switchProfile(ctx, c, gi, p, modctl.ApplyOptions{
+			Force:        profilesSwitchForce,
+			NoHashCache:  profilesSwitchNoCache,
+			WaitForSteam: profilesSwitchWait,
+			OnSteamWait:  printSteamWait,
+
+			IgnoreAdvisories: profilesSwitchIgnAdv,
+			NoRedmodDeploy:   profilesSwitchNoRed,
+		}, profilesSwitchYes)
+	},
+	Annotations: mutatingDryRun,
+}
+
+// switchProfile applies p in place of the applied profile and makes it the
+// active profile. If applying fails it puts back what was applied before.
+// A long apply is confirmed first (see confirmApply).
+func switchProfile(ctx context.Context, c *modctl.Client, gi modctl.Game, p modctl.Profile, opts modctl.ApplyOptions, yes bool) error {
+	prev, err := c.AppliedProfile(ctx, gi)
+	if err != nil {
+		return err
+	}
+
+	// plan first: nothing is touched if the new profile can't be planned
+	pl, err := c.Plan(ctx, gi, p)
+	if err != nil {
+		return err
+	}
+	for _, w := range pl.Warnings {
+		fmt.Println(ui.Warn.Render("  ⚠ " + w))
+	}
+
+	if prev != nil && prev.ID != p.ID {
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("Switching from %q to %q", prev.Name, p.Name)))
+	}
+
+	declined := false
+	opts.Confirm = confirmApply(yes, &declined)
+	opts.OnAfterApply = printAfterApply
+
+	res, err := c.Switch(ctx, gi, p, pl, opts)
+	if err != nil {
+		if declined && errors.Is(err, modctl.ErrApplyCancelled) {
+			fmt.Println("Nothing was changed.")
+			return nil
+		}
+
+		var serr *modctl.SwitchError
+		if errors.As(err, &serr) && serr.RollbackErr == nil {
+			fmt.Println(ui.Warn.Render(fmt.Sprintf("Applying %q failed, rolled back", p.Name)))
+		}
+		printDriftHelp(gi, err)
+		return err
+	}
+
+	printDeployResult(gi, fmt.Sprintf("Switched to profile %q", p.Name),
+		fmt.Sprintf("switch to profile %q", p.Name), res)
+	return nil
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesSwitchCmd)
+
+	profilesSwitchCmd.Flags().StringVarP(&profilesSwitchGame, "game", "g", "",
+		"Override the currently active game")
+	profilesSwitchCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesSwitchCmd.Flags().BoolVar(&profilesSwitchForce, "force", false,
+		"Replace deployed files even if they were changed since")
+	profilesSwitchCmd.Flags().BoolVar(&profilesSwitchNoCache, "no-cache", false,
+		"Hash every deployed file to check it for changes")
+	profilesSwitchCmd.Flags().DurationVar(&profilesSwitchWait, "wait-for-steam", 0,
+		"Wait this long for Steam to finish downloading or updating the game")
+	profilesSwitchCmd.Flags().BoolVar(&profilesSwitchIgnAdv, "ignore-advisories", false,
+		"Apply mods that the advisory feed knows to be malicious")
+	profilesSwitchCmd.Flags().BoolVar(&profilesSwitchYes, "yes", false,
+		"Don't ask before a switch that is going to take a while")
+	profilesSwitchCmd.Flags().BoolVar(&profilesSwitchNoRed, "no-redmod-deploy", false,
+		"Don't run REDmod's deploy step after the switch")
+}
@@ -0,0 +1,123 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesSymlinksGame    string
+	profilesSymlinksProfile string
+)
+
+var profilesSymlinksCmd = &cobra.Command{
+	Use:   "symlinks <version-id> <copy|skip>",
+	Short: "Choose what happens to the symlinks of a mod version in a profile",
+	Long: `Choose what happens to the symlinks in the archive of a mod file version
+within a profile:
+
+  copy  deploy a copy of the file that a symlink points to if it's in the
+        archive as well; symlinks that point outside of the archive (or to
+        directories) are skipped with a warning (the default)
+  skip  don't deploy any of the symlinks
+
+The symlinks of an archive are never deployed as they are (on a target with the
+symlink deploy method the copy is a symlink into modctl's store, see modctl
+games target-strategy). The policy takes effect the next time the profile is
+applied.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return completion.ModFileVersions(cmd, toComplete)
+		case 1:
+			return []string{modctl.SymlinksCopy, modctl.SymlinksSkip}, cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		versionID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || versionID <= 0 {
+			return fmt.Errorf("invalid mod_file_version_id %q (expected a positive integer)", args[0])
+		}
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, profilesSymlinksGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, profilesSymlinksProfile)
+		if err != nil {
+			return err
+		}
+
+		policy := args[1]
+		changed, err := c.SetSymlinks(ctx, p, versionID, policy)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			fmt.Printf("Version %d already uses the %s symlink policy in profile %q\n", versionID, policy, p.Name)
+			return nil
+		}
+
+		dryrun.Report(os.Stdout,
+			fmt.Sprintf("Set the symlink policy of version %d in profile %q to %s", versionID, p.Name, policy),
+			fmt.Sprintf("set the symlink policy of version %d in profile %q to %s", versionID, p.Name, policy))
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesSymlinksCmd)
+
+	profilesSymlinksCmd.Flags().StringVarP(&profilesSymlinksGame, "game", "g", "",
+		"Override the currently active game")
+	profilesSymlinksCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesSymlinksCmd.Flags().StringVarP(&profilesSymlinksProfile, "profile", "p", "",
+		"Override the currently active profile")
+	profilesSymlinksCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+}
@@ -0,0 +1,109 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profilesUnapplyGame    string
+	profilesUnapplyForce   bool
+	profilesUnapplyNoCache bool
+	profilesUnapplyWait    time.Duration
+)
+
+var profilesUnapplyCmd = &cobra.Command{
+	Use:   "unapply",
+	Short: "Remove the applied profile from the game",
+	Long: `Remove every file that modctl deployed to the game and restore the files
+that they replaced.
+
+modctl refuses to remove files that it deployed but that were changed since
+(e.g., by the game or another tool) unless --force is given.
+
+To check for changes, only the deployed files that were touched since they
+were last hashed (by their size, modification time, and inode) are read
+again. Pass --no-cache to hash every one of them.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		// --game overrides active selection
+		gi, err := clientGame(ctx, c, profilesUnapplyGame)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		if gi.AppliedProfileID == 0 {
+			fmt.Println(ui.Subtle.Render("No profile is applied; removing any leftover files"))
+		}
+
+		res, err := c.Unapply(ctx, gi, modctl.ApplyOptions{
+			Force:        profilesUnapplyForce,
+			NoHashCache:  profilesUnapplyNoCache,
+			WaitForSteam: profilesUnapplyWait,
+			OnSteamWait:  printSteamWait,
+		})
+		if err != nil {
+			printDriftHelp(gi, err)
+			return fmt.Errorf("unapply: %w", err)
+		}
+
+		printDeployResult(gi, "Unapplied", "unapply", res)
+		return nil
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesUnapplyCmd)
+
+	profilesUnapplyCmd.Flags().StringVarP(&profilesUnapplyGame, "game", "g", "",
+		"Override the currently active game")
+	profilesUnapplyCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	profilesUnapplyCmd.Flags().BoolVar(&profilesUnapplyForce, "force", false,
+		"Remove deployed files even if they were changed since")
+	profilesUnapplyCmd.Flags().BoolVar(&profilesUnapplyNoCache, "no-cache", false,
+		"Hash every deployed file to check it for changes")
+	profilesUnapplyCmd.Flags().DurationVar(&profilesUnapplyWait, "wait-for-steam", 0,
+		"Wait this long for Steam to finish downloading or updating the game")
+}
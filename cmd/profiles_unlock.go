@@ -0,0 +1,55 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/spf13/cobra"
+)
+
+var profilesUnlockGame string
+
+var profilesUnlockCmd = &cobra.Command{
+	Use:   "unlock [profile]",
+	Short: "Allow changing the mods of a locked profile again",
+	Long: `Unlock a profile (the active one by default) that was locked with modctl
+profiles lock so that its mods can be changed again.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.ProfileNames(cmd, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setProfileLocked(args, profilesUnlockGame, false)
+	},
+	Annotations: mutatingDryRun,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesUnlockCmd)
+
+	profilesUnlockCmd.Flags().StringVarP(&profilesUnlockGame, "game", "g", "",
+		"Override the currently active game")
+	profilesUnlockCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
@@ -0,0 +1,103 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var redmodDeployGame string
+
+var redmodCmd = &cobra.Command{
+	Use:   "redmod",
+	Short: "Cyberpunk 2077 REDmod tools",
+}
+
+var redmodDeployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Run REDmod's deploy step",
+	Long: `Run REDmod's deploy step (redMod.exe deploy) through proton.
+
+REDmod mods (in the game's mods/ directory) only take effect after they have
+been deployed, which compiles their scripts, tweaks, and sounds. This requires
+the (free) REDmod DLC.
+
+The proton used is the newest one installed in the game's steam library unless
+the proton config option points to a different proton script.
+
+modctl profiles apply and switch run it after an apply that changed REDmod
+mods (unless the redmod_deploy_after_apply config option is false), so this
+is only needed if they didn't.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, redmodDeployGame)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		fmt.Println(ui.Subtle.Render("Running redmod deploy (this can take a while)..."))
+		out, err := c.RedmodDeploy(ctx, gi)
+		if err != nil {
+			if strings.TrimSpace(out) != "" {
+				fmt.Fprintln(os.Stderr, strings.TrimSpace(out))
+			}
+			return err
+		}
+		if verbose && strings.TrimSpace(out) != "" {
+			fmt.Println(ui.Subtle.Render(strings.TrimSpace(out)))
+		}
+
+		fmt.Println(ui.OK.Render("REDmod deploy finished"))
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	rootCmd.AddCommand(redmodCmd)
+	redmodCmd.AddCommand(redmodDeployCmd)
+
+	redmodDeployCmd.Flags().StringVarP(&redmodDeployGame, "game", "g", "",
+		"Override the currently active game")
+	redmodDeployCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
@@ -19,21 +19,72 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"slices"
+	"strings"
 
-	"github.com/adrg/xdg"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/lock"
+	"github.com/mfinelli/modctl/internal/offline"
+	"github.com/mfinelli/modctl/internal/perf"
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 )
 
 var (
 	cfgFile string
+	dataDir string
 	verbose bool
+	quiet   bool
+	dryRun  bool
+
+	profilePerf bool
+	readOnly    bool
+	offlineFlag bool
+)
+
+// dryRunAnnotation marks the commands that support --dry-run: they only
+// change the database and the blob stores.
+const dryRunAnnotation = "modctl_dry_run"
+
+// supportsDryRun is the Annotations value of commands that support --dry-run.
+var supportsDryRun = map[string]string{dryRunAnnotation: "true"}
+
+// stateLockAnnotation marks the commands that change the state directory,
+// the database, or game installs: the state lock (see internal.LockState) is
+// taken before they run, which also refuses them with --read-only. Commands
+// that only change something with some flags (e.g., doctor --recheck) take
+// the lock themselves.
+const stateLockAnnotation = "modctl_state_lock"
+
+// mutating is the Annotations value of commands that take the state lock,
+// mutatingDryRun the one of those that support --dry-run as well.
+var (
+	mutating       = map[string]string{stateLockAnnotation: "true"}
+	mutatingDryRun = map[string]string{stateLockAnnotation: "true", dryRunAnnotation: "true"}
 )
 
+// stateLock is the state lock of the running command, if it takes it (see
+// stateLockAnnotation); releaseStateLock releases it once the command is done.
+var stateLock *lock.Lock
+
+func releaseStateLock() {
+	if stateLock != nil {
+		stateLock.Release()
+		stateLock = nil
+	}
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "modctl",
@@ -46,16 +97,203 @@ This program comes with ABSOLUTELY NO WARRANTY; This program is free
 software, and you are welcome to redistribute it under certain conditions;
 You should have received a copy of the GNU General Public License (version
 3) along with this program. If not, see https://www.gnu.org/licenses/.`,
-	Version: "1.0.0",
+	Version: modctl.Version,
+	// errors are reported by reportError
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkConfig(cmd); err != nil {
+			return err
+		}
+		// config validate reports invalid values itself
+		if cmd != configValidateCmd {
+			if err := internal.ApplyIOConfig(); err != nil {
+				return err
+			}
+		}
+
+		if profilePerf && cmd.Name() != cobra.ShellCompRequestCmd {
+			perf.Enable()
+		}
+
+		// modctl shell and batch run many commands: only the ones that
+		// are given --read-only (or all of them) are
+		if readOnly {
+			readonly.Enable()
+		} else {
+			readonly.Reset()
+		}
+		if offlineFlag || viper.GetBool("offline") {
+			offline.Enable()
+		} else {
+			offline.Reset()
+		}
+
+		// completions (e.g., in modctl shell --dry-run) don't change
+		// anything
+		if dryRun && cmd.Name() != cobra.ShellCompRequestCmd {
+			if cmd.Annotations[dryRunAnnotation] == "" {
+				return fmt.Errorf("%s doesn't support --dry-run", cmd.CommandPath())
+			}
+			dryrun.Enable()
+		}
+
+		if cmd.Annotations[stateLockAnnotation] != "" {
+			l, err := internal.LockState(cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+			stateLock = l
+		}
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	args, err := expandArgs(os.Args[1:])
 	if err != nil {
+		ui.PrintError(os.Stderr, err)
 		os.Exit(1)
 	}
+	rootCmd.SetArgs(args)
+
+	c, err := rootCmd.ExecuteC()
+	if derr := internal.FinishDryRun(context.Background(), os.Stdout); derr != nil {
+		ui.PrintError(os.Stderr, derr)
+	}
+	releaseStateLock()
+	perf.Report(os.Stderr)
+	if err != nil {
+		os.Exit(reportError(c, err))
+	}
+}
+
+// reportError reports the error that cmd failed with, as a JSON object on
+// stdout if cmd was asked for JSON output (with --json), and returns the code
+// to exit with (see internal.ExitCode).
+func reportError(cmd *cobra.Command, err error) int {
+	var ec exitCodeError
+	if errors.As(err, &ec) {
+		return ec.code
+	}
+
+	if f := cmd.Flags().Lookup("json"); f != nil && f.Value.String() == "true" {
+		if jerr := internal.WriteErrorJSON(os.Stdout, err); jerr == nil {
+			return internal.ExitCode(err)
+		}
+	}
+	ui.PrintError(os.Stderr, err)
+	return internal.ExitCode(err)
+}
+
+// expandArgs expands the command alias (see the aliases config option) in
+// args, or runs the default_command if there are no args at all. The config
+// is only read this early if it's needed for that, i.e., not for builtin
+// commands.
+func expandArgs(args []string) ([]string, error) {
+	i := commandIndex(args)
+	if len(args) > 0 && (i == len(args) || isBuiltinCommand(args[i])) {
+		return args, nil
+	}
+
+	// cobra parses the flags (again) later, but --config and --data-dir
+	// are needed now
+	fs := pflag.NewFlagSet("modctl", pflag.ContinueOnError)
+	fs.AddFlagSet(rootCmd.PersistentFlags())
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+	_ = fs.Parse(args[:i])
+	if dataDir != "" {
+		viper.Set("data_dir", dataDir)
+	}
+	if err := internal.LoadConfig(cfgFile); err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 {
+		def, err := internal.DefaultCommand()
+		if err != nil {
+			return nil, err
+		}
+		if len(def) == 0 {
+			return args, nil
+		}
+		args = def
+	}
+
+	return expandAlias(args)
+}
+
+// expandAlias expands the alias in args (after the global flags), if any.
+func expandAlias(args []string) ([]string, error) {
+	i := commandIndex(args)
+	if i == len(args) || isBuiltinCommand(args[i]) {
+		return args, nil
+	}
+
+	aliases, err := internal.CommandAliases()
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := internal.ExpandAlias(aliases, args[i:], isBuiltinCommand)
+	if err != nil {
+		return nil, err
+	}
+	return append(slices.Clone(args[:i]), expanded...), nil
+}
+
+// commandIndex returns the index of the command (or alias) in args, after
+// the global flags; len(args) if there isn't one.
+func commandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			return len(args)
+		}
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			return i
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		var f *pflag.Flag
+		if strings.HasPrefix(a, "--") {
+			f = rootCmd.PersistentFlags().Lookup(name)
+		} else if len(name) == 1 {
+			f = rootCmd.PersistentFlags().ShorthandLookup(name)
+		}
+		// e.g., --config path
+		if f != nil && !hasValue && f.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return len(args)
+}
+
+// isBuiltinCommand reports whether name is one of modctl's commands (which
+// aliases can't replace).
+func isBuiltinCommand(name string) bool {
+	switch name {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// exitCodeError makes modctl exit with the given code. Commands that return
+// it have already reported everything that they needed to and should set
+// SilenceErrors and SilenceUsage.
+type exitCodeError struct {
+	code int
+}
+
+func (e exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
 }
 
 func init() {
@@ -69,6 +307,14 @@ func init() {
 	)
 	rootCmd.MarkFlagFilename("config", "toml")
 
+	rootCmd.PersistentFlags().StringVar(
+		&dataDir,
+		"data-dir",
+		"",
+		"directory for the database, archives, backups, overrides, tmp, and state (default is $MODCTL_DATA_DIR or $XDG_DATA_HOME/modctl)",
+	)
+	rootCmd.MarkPersistentFlagDirname("data-dir")
+
 	rootCmd.PersistentFlags().BoolVarP(
 		&verbose,
 		"verbose",
@@ -76,67 +322,108 @@ func init() {
 		false,
 		"enable verbose output",
 	)
+
+	rootCmd.PersistentFlags().BoolVarP(
+		&quiet,
+		"quiet",
+		"q",
+		false,
+		"don't show progress bars",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"show what a command would change without changing anything",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&readOnly,
+		"read-only",
+		false,
+		"open the database read-only and refuse to change anything (e.g., to inspect a backup of the state)",
+	)
+	rootCmd.MarkFlagsMutuallyExclusive("dry-run", "read-only")
+
+	rootCmd.PersistentFlags().BoolVar(
+		&offlineFlag,
+		"offline",
+		false,
+		"don't use the network: answer nexus requests from the cache and refuse downloads",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&profilePerf,
+		"profile-perf",
+		false,
+		"report where the time went (database, hashing, extraction, external commands) on stderr",
+	)
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	// if unspecified just search $PATH
-	viper.SetDefault("bsdtar", "bsdtar")
-
-	dbPath, err := xdg.DataFile(filepath.Join("modctl", "modctl.db"))
-	cobra.CheckErr(err)
-	viper.SetDefault("database", dbPath)
+	if dataDir != "" {
+		viper.Set("data_dir", dataDir)
+	}
+	cobra.CheckErr(internal.LoadConfig(cfgFile))
 
-	viper.SetDefault("archives_dir",
-		filepath.Join(xdg.DataHome, "modctl", "archives"))
-	viper.SetDefault("backups_dir",
-		filepath.Join(xdg.DataHome, "modctl", "backups"))
-	viper.SetDefault("overrides_dir",
-		filepath.Join(xdg.DataHome, "modctl", "overrides"))
-	viper.SetDefault("tmp_dir",
-		filepath.Join(xdg.DataHome, "modctl", "tmp"))
+	if verbose && viper.ConfigFileUsed() != "" {
+		fmt.Fprintln(os.Stderr, "Using config file: ",
+			viper.ConfigFileUsed())
+	}
+}
 
-	if cfgFile != "" {
-		// User explicitly provided a config file: it must work.
-		viper.SetConfigFile(cfgFile)
-		viper.SetConfigType("toml")
+// checkConfig warns about unknown config options and fails on invalid
+// values, which would otherwise silently fall back to the defaults (`config
+// validate` reports them itself).
+func checkConfig(cmd *cobra.Command) error {
+	if cmd == configValidateCmd || cmd.Name() == cobra.ShellCompRequestCmd {
+		return nil
+	}
 
-		if err := viper.ReadInConfig(); err != nil {
-			cobra.CheckErr(err)
+	problems := internal.ValidateConfig(false)
+	for _, p := range problems {
+		if p.Warning {
+			fmt.Fprintf(os.Stderr, "Warning: config: %s\n", p)
 		}
+	}
 
-		if verbose {
-			fmt.Fprintln(os.Stderr, "Using config file: ",
-				viper.ConfigFileUsed())
+	if internal.HasConfigErrors(problems) {
+		var msgs []string
+		for _, p := range problems {
+			if !p.Warning {
+				msgs = append(msgs, p.String())
+			}
 		}
-
-		return
+		return fmt.Errorf("invalid config (see `modctl config validate`): %s",
+			strings.Join(msgs, "; "))
 	}
 
-	defaultPath, err := xdg.ConfigFile(filepath.Join("modctl", "config.toml"))
-	cobra.CheckErr(err)
+	return nil
+}
 
-	if _, err := os.Stat(defaultPath); errors.Is(err, os.ErrNotExist) {
-		return // default config file doesn't exist -- use defaults
+// blobProgress returns a Progress function for a blob store that draws a
+// progress bar (labeled label) on stdout, and a function to call once the
+// file is done. There's no progress function if stdout isn't a terminal or
+// --quiet is set.
+func blobProgress(label string) (func(done, total int64), func()) {
+	if quiet || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, func() {}
 	}
 
-	viper.SetConfigFile(defaultPath)
-	viper.SetConfigType("toml")
-
-	if err := viper.ReadInConfig(); err != nil {
-		// missing config file is fine -- use the built-in defaults
-		var notFound viper.ConfigFileNotFoundError
-		if errors.As(err, &notFound) {
-			return
+	var bar *internal.ProgressBar
+	progress := func(done, total int64) {
+		if bar == nil {
+			bar = internal.NewProgressBar(os.Stdout, label, total)
 		}
-
-		// parse/permission errors should fail loudly
-		cobra.CheckErr(err)
-		return
+		bar.Update(done)
 	}
-
-	if verbose {
-		fmt.Fprintln(os.Stderr, "Using config file: ",
-			viper.ConfigFileUsed())
+	finish := func() {
+		if bar != nil {
+			bar.Finish()
+		}
 	}
+
+	return progress, finish
 }
@@ -0,0 +1,76 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+// clientGame returns the game install selected by arg, or the active game
+// install if arg is empty.
+func clientGame(ctx context.Context, c *modctl.Client, arg string) (modctl.Game, error) {
+	if arg != "" {
+		return c.Game(ctx, arg)
+	}
+
+	gi, err := c.ActiveGame(ctx)
+	if errors.Is(err, modctl.ErrNoActiveGame) {
+		return gi, internal.NoActiveGameError("--game")
+	}
+	return gi, err
+}
+
+// argGame returns the game install given as the (optional) argument of a
+// command, or the active game install if there isn't one.
+func argGame(ctx context.Context, c *modctl.Client, args []string) (modctl.Game, error) {
+	if len(args) == 1 {
+		return c.Game(ctx, args[0])
+	}
+
+	gi, err := c.ActiveGame(ctx)
+	if errors.Is(err, modctl.ErrNoActiveGame) {
+		return gi, internal.NoActiveGameError("a game")
+	}
+	return gi, err
+}
+
+// addFormatFlag adds --format to a list command: the usual text, or csv or
+// tsv (see ui.WriteRecords) for spreadsheets.
+func addFormatFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVar(format, "format", ui.FormatText,
+		"Output format: text, csv, or tsv (one record per line, with a header)")
+	cmd.RegisterFlagCompletionFunc("format",
+		cobra.FixedCompletions(ui.Formats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// intField is an id or size of the Go API, where 0 means none, as a csv
+// field (empty for 0).
+func intField(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,347 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/perf"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// shellHistorySize is how many lines of the shell history are kept.
+const shellHistorySize = 1000
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run modctl commands interactively",
+	Long: `Start an interactive shell that runs modctl commands (without the leading
+"modctl") in a single process, so that many successive commands don't pay for
+starting modctl and checking the database each time.
+
+The prompt shows the active game and profile. Arguments are split like a POSIX
+shell does (quotes and backslash escapes work but nothing is expanded). The
+command history is kept in shell_history in the state directory (state_dir),
+and tab completes commands, flags, and arguments like the shell completion
+does.
+
+Type exit or press Ctrl-D to leave the shell. If the input isn't a terminal
+the commands are read line by line without a prompt.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		internal.KeepMigrated()
+
+		cmd.SilenceUsage = true
+
+		keep := keptFlags()
+
+		// interrupts are for the running command (which can cancel its
+		// context), not for the shell
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt)
+		defer signal.Stop(sigs)
+
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			s := bufio.NewScanner(os.Stdin)
+			for s.Scan() {
+				if runShellLine(s.Text(), keep) {
+					return nil
+				}
+			}
+			return s.Err()
+		}
+
+		t := term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "")
+		if h, err := state.LoadHistory(shellHistorySize); err == nil {
+			t.History = h
+		} else if verbose {
+			fmt.Fprintf(os.Stderr, "couldn't load the shell history: %v\n", err)
+		}
+		t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+			if key != '\t' {
+				return "", 0, false
+			}
+			return shellComplete(t, line, pos, keep)
+		}
+
+		for {
+			t.SetPrompt(shellPrompt(ctx, c))
+			if w, h, err := term.GetSize(fd); err == nil {
+				t.SetSize(w, h)
+			}
+
+			old, err := term.MakeRaw(fd)
+			if err != nil {
+				return fmt.Errorf("set up terminal: %w", err)
+			}
+			line, err := t.ReadLine()
+			term.Restore(fd, old)
+
+			if errors.Is(err, io.EOF) {
+				fmt.Println()
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if runShellLine(line, keep) {
+				return nil
+			}
+		}
+	},
+	Annotations: supportsDryRun,
+}
+
+// runShellLine runs one line of shell input. It returns true if the shell
+// should exit.
+func runShellLine(line string, keep map[*pflag.Flag]string) bool {
+	args, err := internal.SplitArgs(line)
+	if err != nil {
+		ui.PrintError(os.Stderr, err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	if args[0] == "modctl" {
+		args = args[1:]
+		if len(args) == 0 {
+			return false
+		}
+	}
+
+	if args[0] == "exit" || args[0] == "quit" {
+		return true
+	}
+
+	// errors were already reported by runCommand
+	_ = runCommand(args, keep)
+
+	return false
+}
+
+// runCommand runs a modctl command in this process, for modctl shell and
+// modctl batch.
+func runCommand(args []string, keep map[*pflag.Flag]string) error {
+	args, err := expandAlias(args)
+	if err != nil {
+		ui.PrintError(os.Stderr, err)
+		return err
+	}
+
+	switch args[0] {
+	case "shell", "batch":
+		err := fmt.Errorf("modctl %s can't be run from modctl shell or modctl batch", args[0])
+		ui.PrintError(os.Stderr, err)
+		return err
+	}
+
+	// a dry run that was asked for by this command alone (and not by
+	// the whole shell or batch) ends with it
+	session := dryrun.Enabled()
+
+	resetFlags(rootCmd, keep)
+	rootCmd.SetArgs(args)
+	c, err := rootCmd.ExecuteC()
+	if err != nil {
+		reportError(c, err)
+	}
+
+	if !session {
+		if derr := internal.FinishDryRun(context.Background(), os.Stdout); derr != nil {
+			ui.PrintError(os.Stderr, derr)
+		}
+	}
+	releaseStateLock()
+	// every command gets its own report
+	perf.Report(os.Stderr)
+	return err
+}
+
+// keptFlags returns the flags given to modctl shell or modctl batch itself
+// (e.g., --config) which apply to every command that they run.
+func keptFlags() map[*pflag.Flag]string {
+	keep := map[*pflag.Flag]string{}
+	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			keep[f] = f.Value.String()
+		}
+	})
+	return keep
+}
+
+// resetFlags puts the flags of every command back to their defaults (or to
+// the values in keep) so that the flags of one shell command don't carry
+// over to the next one.
+func resetFlags(c *cobra.Command, keep map[*pflag.Flag]string) {
+	reset := func(f *pflag.Flag) {
+		v, kept := keep[f]
+		if !kept {
+			v = f.DefValue
+		}
+
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			var vals []string
+			if v = strings.Trim(v, "[]"); v != "" {
+				vals = strings.Split(v, ",")
+			}
+			_ = sv.Replace(vals)
+		} else {
+			_ = f.Value.Set(v)
+		}
+		f.Changed = kept
+	}
+
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub, keep)
+	}
+}
+
+// shellPrompt returns the prompt with the active game and profile.
+func shellPrompt(ctx context.Context, c *modctl.Client) string {
+	gi, err := c.ActiveGame(ctx)
+	if err != nil {
+		return "modctl> "
+	}
+
+	where := internal.ShortSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
+	if p, err := c.Profile(ctx, gi, ""); err == nil {
+		where += " " + p.Name
+	}
+
+	return "modctl " + ui.Subtle.Render("["+where+"]") + "> "
+}
+
+// shellComplete completes the word before the cursor with what the shell
+// completion (modctl __complete) suggests. If there are several candidates
+// it completes their common prefix or, if there's nothing to add, lists them.
+func shellComplete(t *term.Terminal, line string, pos int, keep map[*pflag.Flag]string) (string, int, bool) {
+	head, tail := line[:pos], line[pos:]
+
+	start := strings.LastIndexAny(head, " \t") + 1
+	args, err := internal.SplitArgs(head[:start])
+	if err != nil {
+		return "", 0, false
+	}
+	word, err := internal.SplitArgs(head[start:])
+	if err != nil || len(word) > 1 {
+		return "", 0, false
+	}
+	toComplete := ""
+	if len(word) == 1 {
+		toComplete = word[0]
+	}
+
+	candidates := shellCompletions(args, toComplete, keep)
+	if len(candidates) == 0 {
+		return line, pos, true
+	}
+
+	prefix := candidates[0]
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	if len(candidates) > 1 && prefix == toComplete {
+		fmt.Fprintf(t, "%s\n", strings.Join(candidates, "  "))
+		return line, pos, true
+	}
+
+	repl := shellQuote(prefix)
+	if len(candidates) == 1 {
+		repl += " "
+	}
+	return head[:start] + repl + tail, start + len(repl), true
+}
+
+// shellCompletions runs the shell completion of cobra for the arguments.
+func shellCompletions(args []string, toComplete string, keep map[*pflag.Flag]string) []string {
+	var out bytes.Buffer
+
+	resetFlags(rootCmd, keep)
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs(append(append([]string{cobra.ShellCompRequestCmd}, args...), toComplete))
+	_ = rootCmd.Execute()
+	rootCmd.SetOut(nil)
+	rootCmd.SetErr(nil)
+
+	var candidates []string
+	for line := range strings.Lines(out.String()) {
+		line = strings.TrimRight(line, "\n")
+		// the last line is the completion directive
+		if line == "" || strings.HasPrefix(line, ":") || strings.HasPrefix(line, "_activeHelp_") {
+			continue
+		}
+		c, _, _ := strings.Cut(line, "\t")
+		if strings.HasPrefix(c, toComplete) && !slices.Contains(candidates, c) {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+// shellQuote escapes what SplitArgs would otherwise split or interpret.
+func shellQuote(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(" \t\\'\"#", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
@@ -0,0 +1,178 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var statusGame string
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the applied profile and its pending changes",
+	Long: `Show which profile is active and which one is applied to the game, and
+what applying the active profile would change since it was last applied
+(mods that were enabled, disabled, updated, or reordered, and overrides).
+
+Deployed files that were modified or deleted by something else are listed
+as well if ` + "`modctl watch`" + ` noticed it, and so is what other mod managers
+(Vortex, Mod Organizer 2) left in the game's directories (see
+` + "`modctl games scan-orphans`" + `).
+
+The current active game is used unless --game is provided.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, statusGame)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		fmt.Println(ui.Header.Render(gi.DisplayName))
+
+		active, err := c.ActiveProfile(ctx, gi)
+		if err != nil {
+			return err
+		}
+		if active != nil {
+			fmt.Printf("  active profile:  %s\n", active.Name)
+		} else {
+			fmt.Printf("  active profile:  %s\n", ui.Subtle.Render("(none)"))
+		}
+
+		applied, err := c.AppliedProfile(ctx, gi)
+		if err != nil {
+			return err
+		}
+		if applied == nil {
+			fmt.Printf("  applied profile: %s\n", ui.Subtle.Render("(none)"))
+			printStatusLeftovers(ctx, c, gi)
+			if active != nil {
+				fmt.Println()
+				fmt.Println(ui.Warn.Render(fmt.Sprintf("Profile %q is not applied; run `modctl profiles apply`", active.Name)))
+			}
+			return nil
+		}
+
+		fmt.Printf("  applied profile: %s %s\n", applied.Name,
+			ui.Subtle.Render("(at "+gi.AppliedAt+")"))
+		printStatusLeftovers(ctx, c, gi)
+
+		drifted, err := c.DriftedFiles(ctx, gi)
+		if err != nil {
+			return err
+		}
+		if len(drifted) > 0 {
+			fmt.Println()
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"%d deployed file(s) changed on disk (seen by `modctl watch`):", len(drifted))))
+			for _, f := range drifted {
+				fmt.Printf("  ! %s %s\n", f, ui.Subtle.Render("("+f.Kind+" at "+f.At+")"))
+			}
+		}
+
+		if active == nil {
+			return nil
+		}
+
+		changes, known, err := c.PendingChanges(ctx, gi, *active)
+		if err != nil {
+			return err
+		}
+		if !known {
+			fmt.Println()
+			fmt.Println(ui.Subtle.Render("The applied revision is unknown; apply the profile again to track changes"))
+			return nil
+		}
+
+		fmt.Println()
+		switch {
+		case active.ID != applied.ID:
+			fmt.Println(ui.Warn.Render(fmt.Sprintf(
+				"Profile %q is active but not applied; run `modctl profiles switch %s`", active.Name, active.Name)))
+		case len(changes) == 0:
+			fmt.Println(ui.OK.Render("No pending changes"))
+		default:
+			fmt.Println(ui.Warn.Render("Pending changes (run `modctl profiles apply`):"))
+		}
+		for _, ch := range changes {
+			fmt.Printf("  %s %s\n", pendingChangeMarker(ch.Kind), ch.Message)
+		}
+
+		return nil
+	},
+	Annotations: supportsDryRun,
+}
+
+// printStatusLeftovers lists what other mod managers left in the targets
+// (only looking a couple of directories deep, unlike games scan-orphans).
+func printStatusLeftovers(ctx context.Context, c *modctl.Client, gi modctl.Game) {
+	leftovers, err := c.ScanLeftovers(ctx, gi, true)
+	if err != nil {
+		fmt.Println()
+		fmt.Println(ui.Warn.Render("Could not look for leftovers of other mod managers: " + err.Error()))
+		return
+	}
+	if len(leftovers) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Warn.Render(fmt.Sprintf(
+		"%d leftover(s) of other mod managers (see `modctl games scan-orphans`):", len(leftovers))))
+	printLeftovers(leftovers)
+}
+
+func pendingChangeMarker(kind string) string {
+	switch kind {
+	case "added":
+		return "+"
+	case "removed":
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringVarP(&statusGame, "game", "g", "",
+		"Override the currently active game")
+	statusCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+}
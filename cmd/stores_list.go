@@ -23,10 +23,8 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss/table"
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
-	"go.finelli.dev/util"
 )
 
 var storesListNoDisabled bool
@@ -41,37 +39,21 @@ Only enabled stores are scanned during discovery.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
+		stores, err := c.Stores(ctx, storesListNoDisabled)
 		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		q := dbq.New(db)
-		var stores []dbq.Store
-		if storesListNoDisabled {
-			stores, err = q.ListEnabledStores(ctx)
-		} else {
-			stores, err = q.ListAllStores(ctx)
-		}
-		if err != nil {
-			return fmt.Errorf("error fetching stores: %w", err)
+			return err
 		}
 
 		rows := [][]string{}
 		for _, store := range stores {
 			en := "✗"
-			if util.SqliteIntToBool(store.Enabled) {
+			if store.Enabled {
 				en = "✓"
 			}
 
@@ -90,6 +72,7 @@ Only enabled stores are scanned during discovery.`,
 
 		return nil
 	},
+	Annotations: supportsDryRun,
 }
 
 func init() {
@@ -20,17 +20,11 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
-	"strings"
 
-	"github.com/mfinelli/modctl/dbq"
-	"github.com/mfinelli/modctl/internal"
 	"github.com/mfinelli/modctl/internal/completion"
-	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/pkg/modctl"
 	"github.com/spf13/cobra"
-	"go.finelli.dev/util"
 )
 
 var storesSetActiveCmd = &cobra.Command{
@@ -52,44 +46,14 @@ The store must already be configured.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		err := internal.EnsureDBExists()
+		c, err := modctl.Open(ctx)
 		if err != nil {
 			return err
 		}
+		defer c.Close()
 
-		db, err := internal.SetupDB()
+		store, err := c.SetActiveStore(ctx, args[0])
 		if err != nil {
-			return fmt.Errorf("error setting up database: %w", err)
-		}
-		defer db.Close()
-
-		err = internal.MigrateDB(ctx, db)
-		if err != nil {
-			return fmt.Errorf("error migrating database: %w", err)
-		}
-
-		storeID := normalizeStoreID(args[0])
-		q := dbq.New(db)
-		store, err := q.GetStoreById(ctx, storeID)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fmt.Errorf("unknown store %q", storeID)
-			}
-			return fmt.Errorf("get store: %w", err)
-		}
-
-		if !util.SqliteIntToBool(store.Enabled) {
-			return fmt.Errorf("store %q is disabled", storeID)
-		}
-
-		a, err := state.LoadActive()
-		if err != nil {
-			return err
-		}
-
-		a.ActiveStoreID = store.ID
-
-		if err := state.SaveActive(a); err != nil {
 			return err
 		}
 
@@ -97,15 +61,9 @@ The store must already be configured.`,
 
 		return nil
 	},
+	Annotations: mutating,
 }
 
 func init() {
 	storesCmd.AddCommand(storesSetActiveCmd)
 }
-
-func normalizeStoreID(s string) string {
-	// store ids are meant to be stable identifiers like "steam"
-	// normalize to lowercase to avoid surprising mismatches
-	// TODO pull this somewhere that we can reuse it
-	return strings.ToLower(strings.TrimSpace(s))
-}
@@ -0,0 +1,39 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// tmpCmd represents the tmp command
+var tmpCmd = &cobra.Command{
+	Use:   "tmp",
+	Short: "Manage the scratch space",
+	Long: `Manage what modctl keeps in tmp_dir while it works: staged archives,
+blobs that are being ingested, wrapped imports, and downloads.
+
+modctl removes them when it's done, but a crash (or a kill) can leave them
+behind. Commands that change something remove the ones that are older than
+tmp_max_age (24 hours by default) when they start.`,
+}
+
+func init() {
+	rootCmd.AddCommand(tmpCmd)
+}
@@ -0,0 +1,79 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var tmpCleanOlderThan time.Duration
+
+var tmpCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove leftover temporary files",
+	Long: `Remove what modctl left in tmp_dir, e.g., after a crash.
+
+Only files and directories that modctl creates are removed, even if tmp_dir
+is shared with other programs. Since this takes the state lock, no other
+modctl command can be using them. Use --older-than to keep the recent ones.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// list them first: taking the lock already removes the stale ones
+		entries, err := internal.TmpEntries(viper.GetString("tmp_dir"), tmpCleanOlderThan)
+		if err != nil {
+			return fmt.Errorf("list tmp dir: %w", err)
+		}
+
+		cmd.SilenceUsage = true
+
+		if len(entries) == 0 {
+			fmt.Println("Nothing to clean up")
+			return nil
+		}
+
+		if verbose {
+			for _, e := range entries {
+				fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %s (%s, %s)", e.Path,
+					internal.FormatBytes(e.Size), e.ModTime.Format(time.DateTime))))
+			}
+		}
+
+		freed, err := internal.RemoveTmpEntries(entries)
+		if err != nil {
+			return fmt.Errorf("clean tmp dir (freed %s): %w", internal.FormatBytes(freed), err)
+		}
+		fmt.Printf("Removed %d temporary file(s), freed %s\n", len(entries), internal.FormatBytes(freed))
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+func init() {
+	tmpCmd.AddCommand(tmpCleanCmd)
+
+	tmpCleanCmd.Flags().DurationVar(&tmpCleanOlderThan, "older-than", 0,
+		"Only remove what didn't change for this long (e.g., 1h)")
+}
@@ -0,0 +1,144 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchGame     string
+	watchDebounce time.Duration
+	watchRescan   time.Duration
+	watchNotify   bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the deployed files of a game for changes",
+	Long: `Watch the files that modctl deployed into a game install and record
+changes that something else made to them (they were modified or deleted, or
+are back to what was deployed) as drift in the database, so that
+` + "`modctl status`" + ` can show them right away without hashing anything.
+
+It runs until it's interrupted and is meant to be run in the background,
+e.g., as a systemd user service. Only one watcher can watch a game install at
+a time, but other commands (like apply) keep working while it runs: it only
+takes the state lock while it records a change (and tries again later if
+another command holds it), and files that they deploy again are no longer
+drifted.
+
+Changes are checked once a file was left alone for --debounce and all of the
+deployed files are checked again every --rescan (and when it starts, for
+what changed while nobody was watching). With --notify a desktop
+notification is sent for every drifted file.
+
+The directories of the deployed files are watched with inotify, so this is
+only supported on Linux.
+
+Example systemd user unit:
+
+  # ~/.config/systemd/user/modctl-watch@.service
+  [Service]
+  ExecStart=modctl watch --game %i --notify
+  Restart=on-failure
+
+  [Install]
+  WantedBy=default.target`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchDebounce <= 0 || watchRescan <= 0 {
+			return fmt.Errorf("--debounce and --rescan must be positive")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, watchGame)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		stamp := func() string {
+			return ui.Subtle.Render(time.Now().Format("2006-01-02 15:04:05"))
+		}
+
+		emit := func(ev modctl.DriftEvent) {
+			if ev.Kind == "" {
+				fmt.Println(stamp(), ui.OK.Render(ev.String()))
+				return
+			}
+			fmt.Println(stamp(), ui.Warn.Render(ev.String()))
+			if watchNotify {
+				body := fmt.Sprintf("%s: %s/%s was %s", gi.DisplayName, ev.Target, ev.RelPath, ev.Kind)
+				if err := cronSendNotification(ctx, body); err != nil {
+					fmt.Fprintln(os.Stderr, ui.Warn.Render("  ⚠ send notification: "+err.Error()))
+				}
+			}
+		}
+		warn := func(err error) {
+			fmt.Fprintln(os.Stderr, stamp(), ui.Warn.Render("⚠ "+err.Error()))
+		}
+
+		fmt.Println(stamp(), ui.Subtle.Render(fmt.Sprintf(
+			"watching the deployed files of %s (Ctrl-C to stop)", gi.DisplayName)))
+		return c.Watch(ctx, gi, cmd.CommandPath(), modctl.WatchOptions{
+			Debounce:  watchDebounce,
+			Rescan:    watchRescan,
+			OnDrift:   emit,
+			OnWarning: warn,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVarP(&watchGame, "game", "g", "",
+		"Override the currently active game")
+	watchCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", time.Second,
+		"How long a file has to be left alone before it's checked")
+	watchCmd.Flags().DurationVar(&watchRescan, "rescan", 5*time.Minute,
+		"How often all of the deployed files are checked again")
+	watchCmd.Flags().BoolVar(&watchNotify, "notify", false,
+		"Send a desktop notification for every drifted file")
+}
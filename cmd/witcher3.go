@@ -0,0 +1,192 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/internal/completion"
+	"github.com/mfinelli/modctl/internal/ui"
+	"github.com/mfinelli/modctl/internal/vars"
+	"github.com/mfinelli/modctl/pkg/modctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	witcher3MergeGame    string
+	witcher3MergeProfile string
+	witcher3MergeFrom    string
+)
+
+var witcher3Cmd = &cobra.Command{
+	Use:   "witcher3",
+	Short: "The Witcher 3 tools",
+}
+
+var witcher3MergeCmd = &cobra.Command{
+	Use:   "merge <script>",
+	Short: "Merge a script that more than one mod changes",
+	Long: `Merge a Witcher 3 script (.ws) that more than one mod of the profile
+changes and store the result as an override of the profile. The merged script
+is deployed to mods/` + modctl.Witcher3MergedDir + ` which the game gives
+precedence over every other mod.
+
+The script is given relative to the mod's content directory, as shown by
+modctl profiles conflicts (e.g., scripts/game/player/r4Player.ws).
+
+By default modctl extracts every mod's version of the script and runs the
+witcher3_merge_command config option (kdiff3 unless configured otherwise) to
+merge them one after the other, using the game's own script as the common
+base. The following variables are replaced in each argument:
+
+  ${base}     the unmodified script from the game
+  ${ours}     the script merged so far
+  ${theirs}   the next mod's script
+  ${output}   where to write the merged script
+
+If you merged the script some other way pass the merged file with --from.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		c, err := modctl.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		gi, err := clientGame(ctx, c, witcher3MergeGame)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.Profile(ctx, gi, witcher3MergeProfile)
+		if err != nil {
+			return err
+		}
+
+		script, err := c.Witcher3Script(ctx, gi, p, args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		if len(script.Sources) == 0 && witcher3MergeFrom == "" {
+			return fmt.Errorf("%s isn't changed by more than one mod of profile %q", script.Key, p.Name)
+		}
+
+		merged := witcher3MergeFrom
+		if merged == "" {
+			tmp, err := os.MkdirTemp(viper.GetString("tmp_dir"), "modctl-w3merge-*")
+			if err != nil {
+				return fmt.Errorf("create temp dir: %w", err)
+			}
+			defer os.RemoveAll(tmp)
+
+			merged, err = mergeWitcher3Script(ctx, c, gi, script, tmp)
+			if err != nil {
+				return err
+			}
+		}
+
+		if st, err := os.Stat(merged); err != nil {
+			return fmt.Errorf("merged script: %w", err)
+		} else if st.Size() == 0 {
+			return fmt.Errorf("merged script %s is empty", merged)
+		}
+
+		o, err := c.SetOverride(ctx, gi, p, modctl.DefaultTarget, script.RelPath, merged, modctl.OverrideOptions{
+			Notes: "merged witcher 3 script",
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.OK.Render(fmt.Sprintf("Stored merged %s in profile %q", script.Key, p.Name)))
+		fmt.Println(ui.Subtle.Render(fmt.Sprintf("  %s  (%s)", o.RelPath, o.SHA256[:12])))
+
+		return nil
+	},
+	Annotations: mutating,
+}
+
+// mergeWitcher3Script extracts every mod's version of a conflicting script
+// and merges them with the configured merge tool. The mod that the game
+// gives precedence to goes first.
+func mergeWitcher3Script(ctx context.Context, c *modctl.Client, gi modctl.Game, script modctl.Witcher3Script, tmp string) (string, error) {
+	base, files, err := c.ExtractWitcher3Script(ctx, gi, script, tmp)
+	if err != nil {
+		return "", err
+	}
+
+	ours := files[0]
+	for i, theirs := range files[1:] {
+		output := filepath.Join(tmp, fmt.Sprintf("merged-%d.ws", i))
+		command, err := vars.ExpandCommand(viper.GetStringSlice("witcher3_merge_command"), map[string]string{
+			"base":   base,
+			"ours":   ours,
+			"theirs": theirs,
+			"output": output,
+		})
+		if err != nil {
+			return "", fmt.Errorf("witcher3_merge_command: %w", err)
+		}
+
+		merge := exec.CommandContext(ctx, command[0], command[1:]...)
+		merge.Stdin, merge.Stdout, merge.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := merge.Run(); err != nil {
+			return "", fmt.Errorf("%s failed (merge aborted?): %w", command[0], err)
+		}
+
+		ours = output
+	}
+
+	return ours, nil
+}
+
+func init() {
+	rootCmd.AddCommand(witcher3Cmd)
+	witcher3Cmd.AddCommand(witcher3MergeCmd)
+
+	witcher3MergeCmd.Flags().StringVarP(&witcher3MergeGame, "game", "g", "",
+		"Override the currently active game")
+	witcher3MergeCmd.RegisterFlagCompletionFunc("game",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.GameInstallSelectors(cmd, toComplete)
+		})
+
+	witcher3MergeCmd.Flags().StringVarP(&witcher3MergeProfile, "profile", "p", "",
+		"Override the currently active profile")
+	witcher3MergeCmd.RegisterFlagCompletionFunc("profile",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.ProfileNames(cmd, toComplete)
+		})
+
+	witcher3MergeCmd.Flags().StringVar(&witcher3MergeFrom, "from", "",
+		"Store this (already merged) file instead of running the merge tool")
+	witcher3MergeCmd.MarkFlagFilename("from")
+}
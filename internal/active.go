@@ -0,0 +1,326 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/mfinelli/modctl/internal/state"
+	"go.finelli.dev/util"
+)
+
+// The active selection is kept in active.json (where every command reads
+// it) and mirrored in the settings table, so that it travels with a
+// database that's shared between machines. SyncActive copies the newer of
+// the two over the other one whenever a command opens the database.
+const (
+	settingActiveStore    = "active_store_id"
+	settingActiveGame     = "active_game_install_id"
+	settingActiveSelector = "active_game_install_selector"
+)
+
+// SaveActive records a new active selection in active.json and in the
+// database.
+func SaveActive(ctx context.Context, db *sql.DB, q *dbq.Queries, a state.Active) error {
+	if err := readonly.Check("save the active selection"); err != nil {
+		return err
+	}
+
+	a.UpdatedAt = state.Timestamp()
+	if err := saveActiveSettings(ctx, db, q, a); err != nil {
+		return err
+	}
+	if dryrun.Enabled() {
+		dryrun.Note("save the active selection to active.json")
+		return nil
+	}
+	return state.WriteActive(a)
+}
+
+// LoadActiveFromDB returns the active selection recorded in the database,
+// or false if there isn't one.
+func LoadActiveFromDB(ctx context.Context, q *dbq.Queries) (state.Active, bool, error) {
+	rows, err := q.ListSettings(ctx)
+	if err != nil {
+		return state.Active{}, false, fmt.Errorf("list settings: %w", err)
+	}
+	return activeFromSettings(rows)
+}
+
+// SyncActive makes active.json and the database agree on the active
+// selection by copying the one that changed last over the other. Nothing is
+// changed in read-only mode or during a dry run.
+func SyncActive(ctx context.Context, db *sql.DB) error {
+	if readonly.Enabled() || dryrun.Enabled() {
+		return nil
+	}
+
+	q := dbq.New(db)
+	inDB, dbOK, err := LoadActiveFromDB(ctx, q)
+	if err != nil {
+		return err
+	}
+	inFile, problem, err := state.ReadActive()
+	if err != nil {
+		return err
+	}
+	fileOK := problem == nil && inFile != (state.Active{Version: state.ActiveVersion})
+
+	toFile, toDB := syncActiveDirection(inFile, fileOK, inDB, dbOK)
+	switch {
+	case toFile:
+		return state.WriteActive(inDB)
+	case toDB:
+		if inFile.UpdatedAt == "" {
+			inFile.UpdatedAt = state.Timestamp()
+		}
+		return saveActiveSettings(ctx, db, q, inFile)
+	}
+	return nil
+}
+
+// syncActiveDirection decides which copy of the active selection wins: the
+// one that exists, or the one that was changed last.
+func syncActiveDirection(inFile state.Active, fileOK bool, inDB state.Active, dbOK bool) (toFile, toDB bool) {
+	switch {
+	case dbOK && !fileOK:
+		return true, false
+	case fileOK && !dbOK:
+		return false, true
+	case !fileOK && !dbOK:
+		return false, false
+	}
+
+	if inFile.ActiveStoreID == inDB.ActiveStoreID &&
+		inFile.ActiveGameInstallID == inDB.ActiveGameInstallID &&
+		inFile.ActiveGameInstallSelector == inDB.ActiveGameInstallSelector {
+		return false, false
+	}
+	if inDB.UpdatedAt > inFile.UpdatedAt {
+		return true, false
+	}
+	return false, true
+}
+
+func saveActiveSettings(ctx context.Context, db *sql.DB, q *dbq.Queries, a state.Active) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := q.WithTx(tx)
+
+	game := ""
+	if a.ActiveGameInstallID != 0 {
+		game = strconv.FormatInt(a.ActiveGameInstallID, 10)
+	}
+	for _, s := range []struct{ key, value string }{
+		{settingActiveStore, a.ActiveStoreID},
+		{settingActiveGame, game},
+		{settingActiveSelector, a.ActiveGameInstallSelector},
+	} {
+		if err := qtx.SetSetting(ctx, dbq.SetSettingParams{
+			Key:       s.key,
+			Value:     s.value,
+			UpdatedAt: a.UpdatedAt,
+		}); err != nil {
+			return fmt.Errorf("save setting %s: %w", s.key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// activeFromSettings reads the active selection from the settings table.
+// It's as old as its newest key.
+func activeFromSettings(rows []dbq.Setting) (state.Active, bool, error) {
+	a := state.Active{Version: state.ActiveVersion}
+	found := false
+
+	for _, r := range rows {
+		switch r.Key {
+		case settingActiveStore:
+			a.ActiveStoreID = r.Value
+		case settingActiveGame:
+			if r.Value != "" {
+				id, err := strconv.ParseInt(r.Value, 10, 64)
+				if err != nil || id < 0 {
+					return state.Active{}, false, fmt.Errorf("invalid setting %s: %q", r.Key, r.Value)
+				}
+				a.ActiveGameInstallID = id
+			}
+		case settingActiveSelector:
+			a.ActiveGameInstallSelector = r.Value
+		default:
+			continue
+		}
+
+		found = true
+		if r.UpdatedAt > a.UpdatedAt {
+			a.UpdatedAt = r.UpdatedAt
+		}
+	}
+
+	return a, found, nil
+}
+
+// RepairActive makes active.json usable again: a corrupt one is reset (and
+// so is an unreadable copy in the database), a game install that no longer
+// exists is replaced by the install with the same selector (e.g., after a
+// refresh re-added it) or unset, an old file is upgraded with the selector
+// and store of its game, and, if the active game has no active profile but
+// only one profile, that one is activated. It returns what it fixed.
+func RepairActive(ctx context.Context, db *sql.DB, q *dbq.Queries) ([]string, error) {
+	var fixes []string
+
+	a, problem, err := state.ReadActive()
+	if err != nil {
+		return nil, err
+	}
+	if problem != nil {
+		if err := state.ResetActive(); err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, fmt.Sprintf("reset the active selection (%v)", problem))
+		a = state.Active{Version: state.ActiveVersion}
+	}
+
+	// SyncActive gives up on settings that it can't read
+	if _, _, err := LoadActiveFromDB(ctx, q); err != nil {
+		stamped := a
+		stamped.UpdatedAt = state.Timestamp()
+		if err := saveActiveSettings(ctx, db, q, stamped); err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, fmt.Sprintf("replaced the active selection in the database (%v)", err))
+	}
+
+	installs, err := q.ListAllGameInstalls(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list game installs: %w", err)
+	}
+	stores, err := q.ListAllStores(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list stores: %w", err)
+	}
+	storeIDs := make(map[string]bool, len(stores))
+	for _, s := range stores {
+		storeIDs[s.ID] = true
+	}
+
+	repaired, more := repairActiveSelection(a, installs, storeIDs)
+	if repaired != a {
+		if err := SaveActive(ctx, db, q, repaired); err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, more...)
+	}
+
+	if repaired.ActiveGameInstallID == 0 {
+		return fixes, nil
+	}
+
+	// commands that take a profile expect the active game to have an
+	// active one
+	profiles, err := q.GetProfilesForGameInstall(ctx, repaired.ActiveGameInstallID)
+	if err != nil {
+		return nil, fmt.Errorf("list profiles: %w", err)
+	}
+	for _, p := range profiles {
+		if util.SqliteIntToBool(p.IsActive) {
+			return fixes, nil
+		}
+	}
+	if len(profiles) == 1 {
+		if err := ActivateProfile(ctx, db, q, repaired.ActiveGameInstallID, profiles[0].Name); err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, fmt.Sprintf("activated profile %q, the only one of %s",
+			profiles[0].Name, repaired.ActiveGameInstallSelector))
+	}
+
+	return fixes, nil
+}
+
+// repairActiveSelection points an active selection at game installs and
+// stores that exist, returning the repaired selection and what changed.
+func repairActiveSelection(a state.Active, installs []dbq.GameInstall, storeIDs map[string]bool) (state.Active, []string) {
+	var fixes []string
+
+	byID := make(map[int64]dbq.GameInstall, len(installs))
+	bySelector := make(map[string]dbq.GameInstall, len(installs))
+	for _, gi := range installs {
+		byID[gi.ID] = gi
+		bySelector[FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)] = gi
+	}
+
+	// the selector survives an install being removed and found again
+	var sel string
+	if a.ActiveGameInstallSelector != "" {
+		if storeID, storeGameID, instanceID, err := ParseSelector(a.ActiveGameInstallSelector); err == nil {
+			sel = FullSelector(storeID, storeGameID, instanceID)
+		}
+	}
+
+	if a.ActiveGameInstallID != 0 || a.ActiveGameInstallSelector != "" {
+		gi, ok := byID[a.ActiveGameInstallID]
+		switch {
+		case ok:
+		case bySelector[sel].ID != 0:
+			gi, ok = bySelector[sel], true
+			fixes = append(fixes, fmt.Sprintf("the active game %s is now game install %d (was %d)",
+				sel, gi.ID, a.ActiveGameInstallID))
+		default:
+			fixes = append(fixes, fmt.Sprintf("unset the active game: game install %d (%s) no longer exists",
+				a.ActiveGameInstallID, a.ActiveGameInstallSelector))
+			a.ActiveGameInstallID = 0
+			a.ActiveGameInstallSelector = ""
+		}
+
+		if ok {
+			full := FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
+			if a.ActiveGameInstallSelector != full {
+				fixes = append(fixes, fmt.Sprintf("set the selector of the active game to %s", full))
+			}
+			if a.ActiveStoreID != gi.StoreID {
+				fixes = append(fixes, fmt.Sprintf("set the active store to %s", gi.StoreID))
+			}
+			a.ActiveGameInstallID = gi.ID
+			a.ActiveGameInstallSelector = full
+			a.ActiveStoreID = gi.StoreID
+		}
+	}
+
+	if a.ActiveStoreID != "" && !storeIDs[a.ActiveStoreID] {
+		fixes = append(fixes, fmt.Sprintf("unset the active store: %s doesn't exist", a.ActiveStoreID))
+		a.ActiveStoreID = ""
+	}
+
+	return a, fixes
+}
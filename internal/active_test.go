@@ -0,0 +1,158 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairActiveSelection(t *testing.T) {
+	t.Parallel()
+
+	installs := []dbq.GameInstall{
+		{ID: 1, StoreID: "steam", StoreGameID: "100", InstanceID: "default"},
+		{ID: 5, StoreID: "heroic", StoreGameID: "abc", InstanceID: "default"},
+	}
+	stores := map[string]bool{"steam": true, "heroic": true}
+
+	tests := []struct {
+		name  string
+		input state.Active
+		want  state.Active
+		fixes int
+	}{
+		{
+			name:  "nothing selected",
+			input: state.Active{Version: 1},
+			want:  state.Active{Version: 1},
+		},
+		{
+			name:  "fine",
+			input: state.Active{Version: 1, ActiveStoreID: "steam", ActiveGameInstallID: 1, ActiveGameInstallSelector: "steam:100#default"},
+			want:  state.Active{Version: 1, ActiveStoreID: "steam", ActiveGameInstallID: 1, ActiveGameInstallSelector: "steam:100#default"},
+		},
+		{
+			name:  "old file without a selector",
+			input: state.Active{Version: 1, ActiveGameInstallID: 5},
+			want:  state.Active{Version: 1, ActiveStoreID: "heroic", ActiveGameInstallID: 5, ActiveGameInstallSelector: "heroic:abc#default"},
+			fixes: 2,
+		},
+		{
+			name:  "install found again",
+			input: state.Active{Version: 1, ActiveStoreID: "steam", ActiveGameInstallID: 9, ActiveGameInstallSelector: "steam:100"},
+			want:  state.Active{Version: 1, ActiveStoreID: "steam", ActiveGameInstallID: 1, ActiveGameInstallSelector: "steam:100#default"},
+			fixes: 2,
+		},
+		{
+			name:  "install gone",
+			input: state.Active{Version: 1, ActiveStoreID: "steam", ActiveGameInstallID: 9, ActiveGameInstallSelector: "steam:999#default"},
+			want:  state.Active{Version: 1, ActiveStoreID: "steam"},
+			fixes: 1,
+		},
+		{
+			name:  "store gone",
+			input: state.Active{Version: 1, ActiveStoreID: "gog"},
+			want:  state.Active{Version: 1},
+			fixes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, fixes := repairActiveSelection(tt.input, installs, stores)
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, fixes, tt.fixes)
+		})
+	}
+}
+
+func TestActiveFromSettings(t *testing.T) {
+	t.Parallel()
+
+	a, ok, err := activeFromSettings(nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, state.Active{Version: state.ActiveVersion}, a)
+
+	a, ok, err = activeFromSettings([]dbq.Setting{
+		{Key: "active_game_install_id", Value: "3", UpdatedAt: "2026-03-01T10:00:00.000Z"},
+		{Key: "active_game_install_selector", Value: "steam:100#default", UpdatedAt: "2026-03-01T10:00:00.000Z"},
+		{Key: "active_store_id", Value: "steam", UpdatedAt: "2026-03-02T10:00:00.000Z"},
+		{Key: "something_else", Value: "x", UpdatedAt: "2026-04-01T10:00:00.000Z"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, state.Active{
+		Version:                   state.ActiveVersion,
+		ActiveStoreID:             "steam",
+		ActiveGameInstallID:       3,
+		ActiveGameInstallSelector: "steam:100#default",
+		UpdatedAt:                 "2026-03-02T10:00:00.000Z",
+	}, a)
+
+	// an unset game is an empty value
+	a, ok, err = activeFromSettings([]dbq.Setting{{Key: "active_game_install_id", Value: ""}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Zero(t, a.ActiveGameInstallID)
+
+	_, _, err = activeFromSettings([]dbq.Setting{{Key: "active_game_install_id", Value: "three"}})
+	assert.Error(t, err)
+}
+
+func TestSyncActiveDirection(t *testing.T) {
+	t.Parallel()
+
+	older := state.Active{ActiveGameInstallID: 1, UpdatedAt: "2026-03-01T10:00:00.000Z"}
+	newer := state.Active{ActiveGameInstallID: 2, UpdatedAt: "2026-03-02T10:00:00.000Z"}
+
+	tests := []struct {
+		name         string
+		file         state.Active
+		fileOK       bool
+		db           state.Active
+		dbOK         bool
+		toFile, toDB bool
+	}{
+		{name: "neither"},
+		{name: "only in the file", file: older, fileOK: true, toDB: true},
+		{name: "only in the database", db: older, dbOK: true, toFile: true},
+		{name: "the same", file: older, fileOK: true, db: older, dbOK: true},
+		{name: "same selection, other time", file: older, fileOK: true,
+			db: state.Active{ActiveGameInstallID: 1, UpdatedAt: newer.UpdatedAt}, dbOK: true},
+		{name: "newer in the database", file: older, fileOK: true, db: newer, dbOK: true, toFile: true},
+		{name: "newer in the file", file: newer, fileOK: true, db: older, dbOK: true, toDB: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			toFile, toDB := syncActiveDirection(tt.file, tt.fileOK, tt.db, tt.dbOK)
+			assert.Equal(t, tt.toFile, toFile)
+			assert.Equal(t, tt.toDB, toDB)
+		})
+	}
+}
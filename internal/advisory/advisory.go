@@ -0,0 +1,218 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package advisory reads the advisory feed: a list of mod files that are
+// known to be malicious or broken, by the sha256 of their archive or by
+// their nexus mod (and file). Import and apply check the archives against
+// it: malicious ones are refused and broken ones are warned about.
+package advisory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/offline"
+)
+
+// FeedFormat identifies advisory feeds, FeedVersion is the newest version
+// of their shape that modctl understands.
+const (
+	FeedFormat  = "modctl-advisories"
+	FeedVersion = 1
+)
+
+// The severities of advisories: malicious archives are refused, broken ones
+// are warned about.
+const (
+	SeverityMalicious = "malicious"
+	SeverityBroken    = "broken"
+)
+
+// SettingFeed is the settings key of the feed that the advisories were last
+// updated from (its updated_at is when).
+const SettingFeed = "advisory_feed"
+
+// Feed is an advisory feed.
+type Feed struct {
+	Format     string     `json:"format"`
+	Version    int        `json:"version"`
+	Advisories []Advisory `json:"advisories"`
+}
+
+// Advisory is a mod file that is known to be malicious or broken. It needs
+// the sha256 of the archive or the nexus mod, or both.
+type Advisory struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Summary     string `json:"summary"`
+	URL         string `json:"url,omitempty"`
+	PublishedAt string `json:"published_at,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+	Nexus       *Nexus `json:"nexus,omitempty"`
+}
+
+// Nexus is the nexus mod of an advisory: every file of it unless FileID is
+// set.
+type Nexus struct {
+	GameDomain string `json:"game_domain"`
+	ModID      int64  `json:"mod_id"`
+	FileID     int64  `json:"file_id,omitempty"`
+}
+
+// Parse reads and checks an advisory feed.
+func Parse(data []byte) (Feed, error) {
+	var f Feed
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Feed{}, fmt.Errorf("parse advisory feed: %w", err)
+	}
+	if f.Format != FeedFormat {
+		return Feed{}, fmt.Errorf("not an advisory feed (format %q)", f.Format)
+	}
+	if f.Version < 1 || f.Version > FeedVersion {
+		return Feed{}, fmt.Errorf("unsupported advisory feed version %d (modctl understands up to %d)",
+			f.Version, FeedVersion)
+	}
+
+	seen := map[string]bool{}
+	for i := range f.Advisories {
+		a := &f.Advisories[i]
+		if a.ID == "" {
+			return Feed{}, fmt.Errorf("advisory %d: no id", i+1)
+		}
+		if seen[a.ID] {
+			return Feed{}, fmt.Errorf("advisory %s: duplicate id", a.ID)
+		}
+		seen[a.ID] = true
+
+		if a.Severity != SeverityMalicious && a.Severity != SeverityBroken {
+			return Feed{}, fmt.Errorf("advisory %s: invalid severity %q (want %s or %s)",
+				a.ID, a.Severity, SeverityMalicious, SeverityBroken)
+		}
+
+		a.SHA256 = strings.ToLower(a.SHA256)
+		if a.SHA256 != "" {
+			if b, err := hex.DecodeString(a.SHA256); err != nil || len(b) != 32 {
+				return Feed{}, fmt.Errorf("advisory %s: invalid sha256 %q", a.ID, a.SHA256)
+			}
+		}
+		if a.Nexus != nil && (a.Nexus.GameDomain == "" || a.Nexus.ModID <= 0 || a.Nexus.FileID < 0) {
+			return Feed{}, fmt.Errorf("advisory %s: nexus needs a game_domain and a mod_id", a.ID)
+		}
+		if a.SHA256 == "" && a.Nexus == nil {
+			return Feed{}, fmt.Errorf("advisory %s: needs a sha256 or a nexus mod", a.ID)
+		}
+	}
+
+	return f, nil
+}
+
+// Fetch reads the feed from source: an http(s) url (not in offline mode), or
+// else a local file.
+func Fetch(ctx context.Context, source, appVersion string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(strings.TrimPrefix(source, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("read advisory feed: %w", err)
+		}
+		return data, nil
+	}
+
+	if err := offline.Check("fetch advisory feed"); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "modctl/"+appVersion)
+
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch advisory feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch advisory feed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetch advisory feed: %w", err)
+	}
+	return data, nil
+}
+
+// Replace replaces the advisories in the database with the feed, and
+// records where it came from.
+func Replace(ctx context.Context, db *sql.DB, q *dbq.Queries, source string, f Feed) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	if err := qtx.DeleteAdvisories(ctx); err != nil {
+		return fmt.Errorf("delete advisories: %w", err)
+	}
+	for _, a := range f.Advisories {
+		if err := qtx.InsertAdvisory(ctx, insertParams(a)); err != nil {
+			return fmt.Errorf("insert advisory %s: %w", a.ID, err)
+		}
+	}
+
+	if err := qtx.SetSetting(ctx, dbq.SetSettingParams{
+		Key:       SettingFeed,
+		Value:     source,
+		UpdatedAt: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}); err != nil {
+		return fmt.Errorf("record advisory feed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+func insertParams(a Advisory) dbq.InsertAdvisoryParams {
+	p := dbq.InsertAdvisoryParams{
+		AdvisoryID:    a.ID,
+		Severity:      a.Severity,
+		Summary:       a.Summary,
+		Url:           sql.NullString{String: a.URL, Valid: a.URL != ""},
+		PublishedAt:   sql.NullString{String: a.PublishedAt, Valid: a.PublishedAt != ""},
+		ArchiveSha256: sql.NullString{String: a.SHA256, Valid: a.SHA256 != ""},
+	}
+	if a.Nexus != nil {
+		p.NexusGameDomain = sql.NullString{String: a.Nexus.GameDomain, Valid: true}
+		p.NexusModID = sql.NullInt64{Int64: a.Nexus.ModID, Valid: true}
+		p.NexusFileID = sql.NullInt64{Int64: a.Nexus.FileID, Valid: a.Nexus.FileID > 0}
+	}
+	return p
+}
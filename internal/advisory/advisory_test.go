@@ -0,0 +1,134 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package advisory
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSHA = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	f, err := Parse([]byte(`{"format": "modctl-advisories", "version": 1, "advisories": [
+		{"id": "MA-1", "severity": "malicious", "summary": "steals tokens",
+		 "sha256": "` + strings.ToUpper(testSHA) + `"},
+		{"id": "MA-2", "severity": "broken", "summary": "crashes on load",
+		 "nexus": {"game_domain": "skyrimspecialedition", "mod_id": 12604}}]}`))
+	require.NoError(t, err)
+	require.Len(t, f.Advisories, 2)
+	assert.Equal(t, testSHA, f.Advisories[0].SHA256)
+	assert.Equal(t, int64(12604), f.Advisories[1].Nexus.ModID)
+
+	p := insertParams(f.Advisories[1])
+	assert.False(t, p.ArchiveSha256.Valid)
+	assert.Equal(t, "skyrimspecialedition", p.NexusGameDomain.String)
+	assert.False(t, p.NexusFileID.Valid)
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"format":    `{"format": "modctl-collection", "version": 1}`,
+		"version":   `{"format": "modctl-advisories", "version": 2}`,
+		"id":        `{"format": "modctl-advisories", "version": 1, "advisories": [{"severity": "broken", "sha256": "` + testSHA + `"}]}`,
+		"duplicate": `{"format": "modctl-advisories", "version": 1, "advisories": [{"id": "a", "severity": "broken", "sha256": "` + testSHA + `"}, {"id": "a", "severity": "broken", "sha256": "` + testSHA + `"}]}`,
+		"severity":  `{"format": "modctl-advisories", "version": 1, "advisories": [{"id": "a", "severity": "bad", "sha256": "` + testSHA + `"}]}`,
+		"sha256":    `{"format": "modctl-advisories", "version": 1, "advisories": [{"id": "a", "severity": "broken", "sha256": "abc"}]}`,
+		"nexus":     `{"format": "modctl-advisories", "version": 1, "advisories": [{"id": "a", "severity": "broken", "nexus": {"mod_id": 1}}]}`,
+		"nothing":   `{"format": "modctl-advisories", "version": 1, "advisories": [{"id": "a", "severity": "broken"}]}`,
+		"json":      `{`,
+	}
+
+	for name, feed := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(feed))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "feed.json")
+	require.NoError(t, os.WriteFile(path, []byte("local"), 0o644))
+
+	data, err := Fetch(context.Background(), path, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "local", string(data))
+
+	data, err = Fetch(context.Background(), "file://"+path, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "local", string(data))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.json" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "modctl/test", r.Header.Get("User-Agent"))
+		w.Write([]byte("remote"))
+	}))
+	defer srv.Close()
+
+	data, err = Fetch(context.Background(), srv.URL+"/feed.json", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "remote", string(data))
+
+	_, err = Fetch(context.Background(), srv.URL+"/missing.json", "test")
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	notices := []Notice{
+		{ID: "MA-1", Severity: SeverityMalicious, Summary: "steals tokens", Mod: "Evil / main"},
+		{ID: "MA-2", Severity: SeverityBroken, Mod: "Crashy / main", URL: "https://example.com/MA-2"},
+	}
+
+	warnings, err := Check(notices, false)
+	assert.Equal(t, []string{"Crashy / main is broken (MA-2) https://example.com/MA-2"}, warnings)
+	var blocked *BlockedError
+	require.ErrorAs(t, err, &blocked)
+	assert.Equal(t, notices[:1], blocked.Notices)
+	assert.Equal(t, "refusing known-malicious mod files: Evil / main is malicious (MA-1): steals tokens", err.Error())
+
+	warnings, err = Check(notices, true)
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 2)
+
+	warnings, err = Check(nil, false)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
@@ -0,0 +1,142 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package advisory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// Notice is an advisory that matched a mod file.
+type Notice struct {
+	ID       string
+	Severity string
+	Summary  string
+	URL      string
+	// the mod file, e.g., "SkyUI / main"
+	Mod string
+}
+
+func (n Notice) String() string {
+	s := fmt.Sprintf("%s is %s (%s)", n.Mod, n.Severity, n.ID)
+	if n.Summary != "" {
+		s += ": " + n.Summary
+	}
+	if n.URL != "" {
+		s += " " + n.URL
+	}
+	return s
+}
+
+// BlockedError is returned when archives that are known to be malicious
+// were about to be imported or applied.
+type BlockedError struct {
+	Notices []Notice
+}
+
+func (e *BlockedError) Error() string {
+	notices := make([]string, len(e.Notices))
+	for i, n := range e.Notices {
+		notices[i] = n.String()
+	}
+	return "refusing known-malicious mod files: " + strings.Join(notices, "; ")
+}
+
+// ErrorHints returns what the user can do about it.
+func (e *BlockedError) ErrorHints() []string {
+	return []string{
+		"remove them (see `modctl advisories list`)",
+		"or pass --ignore-advisories if you're sure that the advisory doesn't apply",
+	}
+}
+
+// ForArchive returns the advisories of an archive that isn't attached to a
+// mod (yet), by its sha256 and its nexus mod (and file), if it's known.
+// mod names it in the notices.
+func ForArchive(ctx context.Context, q *dbq.Queries, sha string, gameDomain *string, modID, fileID *int64, mod string) ([]Notice, error) {
+	p := dbq.ListAdvisoriesForArchiveParams{
+		ArchiveSha256: sql.NullString{String: sha, Valid: true},
+	}
+	if gameDomain != nil && modID != nil {
+		p.NexusGameDomain = sql.NullString{String: *gameDomain, Valid: true}
+		p.NexusModID = sql.NullInt64{Int64: *modID, Valid: true}
+		if fileID != nil {
+			p.NexusFileID = sql.NullInt64{Int64: *fileID, Valid: true}
+		}
+	}
+
+	rows, err := q.ListAdvisoriesForArchive(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("list advisories: %w", err)
+	}
+
+	notices := make([]Notice, len(rows))
+	for i, r := range rows {
+		notices[i] = Notice{
+			ID:       r.AdvisoryID,
+			Severity: r.Severity,
+			Summary:  r.Summary,
+			URL:      r.Url.String,
+			Mod:      mod,
+		}
+	}
+	return notices, nil
+}
+
+// ForProfile returns the advisories of the enabled mods of a profile.
+func ForProfile(ctx context.Context, q *dbq.Queries, profileID int64) ([]Notice, error) {
+	rows, err := q.ListAdvisoriesForProfile(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("list advisories: %w", err)
+	}
+
+	notices := make([]Notice, len(rows))
+	for i, r := range rows {
+		notices[i] = Notice{
+			ID:       r.AdvisoryID,
+			Severity: r.Severity,
+			Summary:  r.Summary,
+			URL:      r.Url.String,
+			Mod:      fmt.Sprintf("%s / %s (v%d)", r.ModName, r.FileLabel, r.ModFileVersionID),
+		}
+	}
+	return notices, nil
+}
+
+// Check splits notices into warnings and a *BlockedError for the malicious
+// ones, which are only warnings as well with ignore.
+func Check(notices []Notice, ignore bool) (warnings []string, err error) {
+	var blocked []Notice
+	for _, n := range notices {
+		if n.Severity == SeverityMalicious && !ignore {
+			blocked = append(blocked, n)
+			continue
+		}
+		warnings = append(warnings, n.String())
+	}
+
+	if len(blocked) > 0 {
+		return warnings, &BlockedError{Notices: blocked}
+	}
+	return warnings, nil
+}
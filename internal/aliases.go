@@ -0,0 +1,105 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// maxAliasDepth is how many aliases an alias can go through (aliases can use
+// other aliases) before we assume that there's a cycle.
+const maxAliasDepth = 16
+
+// CommandAliases returns the aliases config option: the arguments that each
+// alias stands for, by name (which viper lowercases).
+func CommandAliases() (map[string][]string, error) {
+	aliases, err := parseAliases(viper.GetStringMap("aliases"))
+	if err != nil {
+		return nil, fmt.Errorf("aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+func parseAliases(m map[string]any) (map[string][]string, error) {
+	out := map[string][]string{}
+	for name, v := range m {
+		if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid alias name %q", name)
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string (e.g., \"mods import\")", name)
+		}
+		args, err := SplitArgs(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s is empty", name)
+		}
+		out[name] = args
+	}
+	return out, nil
+}
+
+func checkAliases(v any) error {
+	_, err := parseAliases(v.(map[string]any))
+	return err
+}
+
+// DefaultCommand returns the arguments of the default_command config option
+// (nil if it isn't set).
+func DefaultCommand() ([]string, error) {
+	args, err := SplitArgs(viper.GetString("default_command"))
+	if err != nil {
+		return nil, fmt.Errorf("default_command: %w", err)
+	}
+	return args, nil
+}
+
+func checkDefaultCommand(v any) error {
+	_, err := SplitArgs(v.(string))
+	return err
+}
+
+// ExpandAlias replaces the alias that args start with (if they do) with the
+// arguments that it stands for; the rest of args are appended to them.
+// Aliases can use other aliases, but builtin commands always win: they
+// can't be redefined.
+func ExpandAlias(aliases map[string][]string, args []string, builtin func(string) bool) ([]string, error) {
+	var seen []string
+	for len(args) > 0 && !builtin(args[0]) {
+		expansion, ok := aliases[args[0]]
+		if !ok {
+			break
+		}
+		if slices.Contains(seen, args[0]) || len(seen) == maxAliasDepth {
+			return nil, fmt.Errorf("alias %s expands to itself", seen[0])
+		}
+		seen = append(seen, args[0])
+
+		args = append(slices.Clone(expansion), args[1:]...)
+	}
+	return args, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAliases(t *testing.T) {
+	t.Parallel()
+
+	aliases, err := parseAliases(map[string]any{
+		"i":  "mods import",
+		"up": `mods outdated --changelogs "Some Mod"`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"i":  {"mods", "import"},
+		"up": {"mods", "outdated", "--changelogs", "Some Mod"},
+	}, aliases)
+
+	for _, m := range []map[string]any{
+		{"i": ""},
+		{"i": []any{"mods", "import"}},
+		{"i": `mods import "`},
+		{"-i": "mods import"},
+		{"a b": "mods import"},
+	} {
+		_, err := parseAliases(m)
+		assert.Error(t, err, m)
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	t.Parallel()
+
+	aliases := map[string][]string{
+		"i":      {"mods", "import"},
+		"up":     {"mods", "outdated", "--changelogs"},
+		"sky":    {"up", "--game", "skyrim"},
+		"status": {"mods", "list"},
+		"loop":   {"loop2"},
+		"loop2":  {"loop", "x"},
+	}
+	builtin := func(name string) bool {
+		return name == "mods" || name == "status"
+	}
+
+	expand := func(args ...string) []string {
+		t.Helper()
+		out, err := ExpandAlias(aliases, args, builtin)
+		require.NoError(t, err)
+		return out
+	}
+
+	assert.Equal(t, []string{"mods", "import", "a.zip"}, expand("i", "a.zip"))
+	assert.Equal(t, []string{"mods", "outdated", "--changelogs", "--game", "skyrim", "-v"}, expand("sky", "-v"))
+	assert.Equal(t, []string{"status"}, expand("status"))
+	assert.Equal(t, []string{"mods", "list"}, expand("mods", "list"))
+	assert.Equal(t, []string{"unknown"}, expand("unknown"))
+	assert.Empty(t, expand())
+
+	_, err := ExpandAlias(aliases, []string{"loop"}, builtin)
+	assert.ErrorContains(t, err, "alias loop expands to itself")
+}
@@ -0,0 +1,1502 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/advisory"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/fscaps"
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/mfinelli/modctl/internal/steam"
+)
+
+// Deployer applies profiles to game installs and removes them again.
+type Deployer struct {
+	DB     *sql.DB
+	Q      *dbq.Queries
+	Blobs  blobstore.Store
+	Bsdtar string
+
+	// Force replaces (or removes) files that changed on disk since modctl
+	// deployed them instead of refusing to touch them.
+	Force bool
+
+	// Full redeploys every file of a profile instead of only the ones that
+	// changed since it was last applied.
+	Full bool
+
+	// SteamManifests uses Steam's depot manifests to recognize the vanilla
+	// files of steam games: they aren't backed up since Steam can restore
+	// them. Games that `games verify-vanilla` found pristine use them
+	// either way.
+	SteamManifests bool
+
+	// Baseline records what is in the targets of a game install (see
+	// RecordBaseline) before deploying to it for the first time.
+	Baseline bool
+
+	// Hashes remembers the hashes of the deployed files so that checking
+	// them for changes only reads the ones that were touched (nil hashes
+	// every file).
+	Hashes deploy.HashCache
+
+	// ReportsDir is where a report of every apply is written to (see
+	// WriteOperationReport); empty doesn't write any.
+	ReportsDir string
+
+	// SteamWait is how long apply and unapply wait for Steam to finish
+	// downloading or updating a steam game before they give up with a
+	// *SteamBusyError (0 gives up right away). OnSteamWait, if set, is
+	// called when they start waiting.
+	SteamWait   time.Duration
+	OnSteamWait func(reason string)
+
+	// IgnoreAdvisories applies mods that the advisory feed knows to be
+	// malicious (with a warning) instead of refusing the profile with an
+	// *advisory.BlockedError.
+	IgnoreAdvisories bool
+
+	// Confirm, if set, is called with the estimate of an apply that is
+	// going to take long (see ApplyEstimate.Long) before anything is
+	// changed; unless it returns true, Apply gives up with
+	// ErrApplyCancelled.
+	Confirm func(ApplyEstimate) bool
+
+	// Mirrors are where archives that are missing from the blob store (or
+	// were quarantined) are fetched from before they're extracted (see
+	// BlobMirrors); without any the apply fails with a BlobMissingError.
+	Mirrors []string
+}
+
+// DeployResult summarizes an apply or unapply.
+type DeployResult struct {
+	OperationID int64 `json:"operation_id"`
+	Written     int   `json:"written"`
+	Overwritten int   `json:"overwritten"`
+	Removed     int   `json:"removed"`
+	Restored    int   `json:"restored"`
+	BackedUp    int   `json:"backed_up"`
+	Unchanged   int   `json:"unchanged"`
+	// vanilla files that were replaced without a backup
+	Vanilla int `json:"vanilla"`
+	// other files that were replaced without a backup because the backup
+	// policy of their target said so
+	NotBackedUp int `json:"not_backed_up,omitempty"`
+
+	// files of the plan that aren't deployed: hidden ones and the ones that
+	// lost a conflict to a higher priority mod
+	Hidden   int `json:"hidden,omitempty"`
+	Shadowed int `json:"shadowed,omitempty"`
+
+	Warnings []string `json:"warnings,omitempty"`
+	// what planning warned about (e.g., unsafe paths that are skipped)
+	PlanWarnings []string `json:"plan_warnings,omitempty"`
+	// how long the steps of an apply took
+	Phases []Phase `json:"phases,omitempty"`
+	// the size of the files extracted from the archives (to estimate the
+	// next applies, see ApplyEstimate)
+	ExtractedBytes int64 `json:"extracted_bytes,omitempty"`
+
+	Changed []ChangedPath `json:"-"`
+	// vanilla files that were removed without a backup to restore: verifying
+	// the game files in Steam brings them back
+	SteamRestore []ChangedPath `json:"-"`
+	// the report of an apply, if it was written
+	Report string `json:"-"`
+
+	// the contents of the deployed files that were removed or replaced: the
+	// ones in the extracted store may not be linked to anymore (see
+	// pruneExtracted)
+	released map[string]bool
+}
+
+// ChangedPath is a path that an apply or unapply changed.
+type ChangedPath struct {
+	Target  string
+	RelPath string
+}
+
+func (c ChangedPath) String() string {
+	return c.Target + "/" + c.RelPath
+}
+
+// DriftError is returned when files that modctl deployed were changed by
+// something else.
+type DriftError struct {
+	Paths []string
+	// the changed files that are vanilla game files again (e.g., because
+	// Steam verified or updated the game)
+	Vanilla []string
+}
+
+func (e *DriftError) Error() string {
+	shown := e.Paths
+	if len(shown) > 5 {
+		shown = shown[:5]
+	}
+	msg := fmt.Sprintf("%d deployed file(s) changed on disk since they were deployed: %s",
+		len(e.Paths), strings.Join(shown, ", "))
+	if len(e.Paths) > len(shown) {
+		msg += ", ..."
+	}
+	if len(e.Vanilla) > 0 {
+		msg += fmt.Sprintf(" (%d of them are vanilla game files again, did Steam verify or update the game?)",
+			len(e.Vanilla))
+	}
+	return msg + " (pass --force to replace them)"
+}
+
+func (e *DriftError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+type deployTarget struct {
+	row  dbq.Target
+	root string
+
+	// for a versioned target whose game version changed: the directory of
+	// the previous version, which still has the deployed files
+	prevRoot string
+
+	// the vanilla files of the game, if known (game_dir only)
+	vanilla *steam.Index
+
+	// what the filesystem of the target supports (see probeTargets)
+	caps fscaps.Caps
+}
+
+// method returns how to put a file in place on the target (see its deploy
+// method): files extracted from an archive for this apply can be hardlinked,
+// but files from the blob store are only ever cloned or copied so that
+// changing a deployed file can't change the store.
+func (t *deployTarget) method(extracted bool) deploy.Method {
+	return targetMethod(t.row.DeployMethod, t.caps, extracted)
+}
+
+// symlink reports whether the files of the profile are deployed to the
+// target as symlinks into a store (see linkSource).
+func (t *deployTarget) symlink() bool {
+	return t.row.DeployMethod == DeployMethodSymlink && t.caps.Symlinks
+}
+
+// deployed returns the target as it is for the files that are deployed to
+// it, i.e., in the directory of the previous version if it moved.
+func (t *deployTarget) deployed() *deployTarget {
+	if t.prevRoot == "" {
+		return t
+	}
+	prev := *t
+	prev.root, prev.prevRoot = t.prevRoot, ""
+	// the previous directory wasn't probed
+	prev.caps = fscaps.Caps{CaseSensitive: true}
+	return &prev
+}
+
+type pathKey struct {
+	targetID int64
+	relpath  string
+}
+
+// desiredFile is a file that a profile deploys: either a member of a mod
+// archive or an override.
+type desiredFile struct {
+	target  *deployTarget
+	relpath string
+
+	versionID  int64
+	archiveSHA string
+	member     string
+	rules      []RevisionRule
+	symlinks   string
+
+	overrideID int64
+	blobSHA    string
+	// the content of a template override, expanded for the install, and
+	// its sha256
+	rendered    []byte
+	renderedSHA string
+}
+
+// contentSHA is the sha256 of what an override deploys.
+func (f *desiredFile) contentSHA() string {
+	if f.rendered != nil {
+		return f.renderedSHA
+	}
+	return f.blobSHA
+}
+
+// Apply deploys a profile (as planned) to a game install. Files that the
+// previously applied profile deployed but this one doesn't are removed (and
+// whatever they replaced is restored), so applying a profile over another
+// one switches between them.
+//
+// Unless Full is set only the difference to what is deployed is applied:
+// files that the same mod version (with the same remap rules) or override
+// already deployed are left alone, and only the archives that provide the
+// other files are extracted.
+func (d *Deployer) Apply(ctx context.Context, gi dbq.GameInstall, p dbq.Profile, pl *plan.Plan) (res DeployResult, err error) {
+	phases := phaseTimer{res: &res}
+	phases.next("prepare")
+	res.PlanWarnings = append(res.PlanWarnings, pl.Warnings...)
+	for _, it := range pl.Items {
+		res.Hidden += it.Hidden
+	}
+	for _, f := range pl.Files {
+		res.Shadowed += len(f.Shadowed)
+	}
+
+	notices, err := advisory.ForProfile(ctx, d.Q, p.ID)
+	if err != nil {
+		return res, err
+	}
+	warnings, err := advisory.Check(notices, d.IgnoreAdvisories)
+	res.Warnings = append(res.Warnings, warnings...)
+	if err != nil {
+		return res, err
+	}
+
+	if err := WaitForSteam(ctx, gi, d.SteamWait, d.OnSteamWait); err != nil {
+		return res, err
+	}
+
+	targets, err := d.resolveTargets(ctx, gi)
+	if err != nil {
+		return res, err
+	}
+	if err := resolveVersionedTargets(gi, p, targets); err != nil {
+		return res, err
+	}
+	d.loadVanilla(gi, targets, &res)
+	d.probeTargets(targets, &res)
+	if w := gameVersionWarning(gi, p); w != "" {
+		res.Warnings = append(res.Warnings, w)
+	}
+	if w := steamUpdateWarning(gi); w != "" {
+		res.Warnings = append(res.Warnings, w)
+	}
+
+	desired, err := d.desiredFiles(ctx, gi, p, pl, targets)
+	if err != nil {
+		return res, err
+	}
+	if err := checkCaseCollisions(desired); err != nil {
+		return res, err
+	}
+	if err := checkWindowsNames(desired); err != nil {
+		return res, err
+	}
+	if err := checkTargetAccess(gi, desired, &res); err != nil {
+		return res, err
+	}
+
+	rev, err := CurrentRevision(ctx, d.Q, p.ID)
+	if err != nil {
+		return res, err
+	}
+	revJSON, err := json.Marshal(rev)
+	if err != nil {
+		return res, fmt.Errorf("encode revision: %w", err)
+	}
+
+	installed, err := d.Q.ListInstalledFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return res, fmt.Errorf("list installed files: %w", err)
+	}
+	byKey := map[pathKey]dbq.InstalledFile{}
+	deployed := make(map[int64]*deployTarget, len(targets))
+	for id, t := range targets {
+		deployed[id] = t.deployed()
+	}
+	for _, row := range installed {
+		// the files of a target that moved are all deployed again
+		if t, ok := targets[row.TargetID]; ok && t.prevRoot != "" {
+			continue
+		}
+		byKey[pathKey{row.TargetID, row.Relpath}] = row
+	}
+
+	unchanged, touched, pending := changedFiles(gi, desired, installed, byKey, d.Full)
+	if err := d.checkDrift(ctx, touched, deployed); err != nil {
+		return res, err
+	}
+
+	// before the archives are fetched: a declined apply doesn't download
+	// anything
+	if d.Confirm != nil {
+		est, err := d.estimate(ctx, desired, pending)
+		if err != nil {
+			return res, err
+		}
+		if est.Long() && !d.Confirm(est) {
+			return res, ErrApplyCancelled
+		}
+	}
+
+	if err := d.fetchMissingArchives(ctx, desired, pending, &res); err != nil {
+		return res, err
+	}
+	if err := d.checkDiskSpace(ctx, desired, pending, byKey, &res); err != nil {
+		return res, err
+	}
+
+	if d.Baseline && !gi.BaselineScannedAt.Valid {
+		if _, err := RecordBaseline(ctx, d.DB, d.Q, gi); err != nil {
+			return res, fmt.Errorf("record baseline: %w", err)
+		}
+	}
+
+	opID, err := d.Q.CreateOperation(ctx, dbq.CreateOperationParams{
+		GameInstallID: gi.ID,
+		ProfileID:     sql.NullInt64{Int64: p.ID, Valid: true},
+		OpType:        "apply",
+		Revision:      sql.NullString{String: string(revJSON), Valid: true},
+	})
+	if err != nil {
+		return res, fmt.Errorf("create operation: %w", err)
+	}
+	res.OperationID = opID
+	defer func() {
+		phases.stop()
+		d.pruneExtracted(&res)
+		d.finishOperation(opID, &res, err)
+		d.writeReport(opID, &res)
+	}()
+
+	phases.next("extract")
+	if err := os.MkdirAll(d.Blobs.TmpDir, 0o755); err != nil {
+		return res, fmt.Errorf("create tmp dir: %w", err)
+	}
+	staging, err := os.MkdirTemp(d.Blobs.TmpDir, "apply-")
+	if err != nil {
+		return res, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	staged := map[string]map[string]string{}
+	for k, f := range desired {
+		if f.archiveSHA == "" || staged[f.archiveSHA] != nil || unchanged[k] {
+			continue
+		}
+		ap, err := d.Blobs.Locate(blobstore.KindArchive, f.archiveSHA)
+		if err != nil {
+			return res, err
+		}
+		if err := d.verifyArchive(ctx, f.archiveSHA); err != nil {
+			return res, err
+		}
+		files, _, err := deploy.Stage(ctx, d.Bsdtar, ap, filepath.Join(staging, f.archiveSHA))
+		if err != nil {
+			return res, fmt.Errorf("extract archive %s: %w", shortSHA(f.archiveSHA), err)
+		}
+		staged[f.archiveSHA] = files
+		res.ExtractedBytes += stagedBytes(files)
+	}
+
+	// remove stale files first so that a file that moved to a different
+	// owner can't be removed after it was written
+	phases.next("remove")
+	if err := unmountOverlays(ctx, gi, targets); err != nil {
+		return res, err
+	}
+	for _, row := range installed {
+		if _, ok := byKey[pathKey{row.TargetID, row.Relpath}]; ok {
+			if _, ok := desired[pathKey{row.TargetID, row.Relpath}]; ok {
+				continue
+			}
+		}
+		if err := d.removeInstalled(ctx, gi, opID, deployed[row.TargetID], row, &res); err != nil {
+			return res, err
+		}
+	}
+
+	// from now on the files of the moved targets are in their new directory
+	for _, t := range targets {
+		if t.prevRoot == "" {
+			continue
+		}
+		if err := d.Q.SetTargetRootPath(ctx, dbq.SetTargetRootPathParams{
+			RootPath: t.root,
+			ID:       t.row.ID,
+		}); err != nil {
+			return res, fmt.Errorf("update target %s: %w", t.row.Name, err)
+		}
+		res.Warnings = append(res.Warnings, fmt.Sprintf("target %s moved to %s (the game version changed)",
+			t.row.Name, t.root))
+	}
+
+	phases.next("deploy")
+	keys := make([]pathKey, 0, len(desired))
+	for k := range desired {
+		if unchanged[k] {
+			res.Unchanged++
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].targetID != keys[j].targetID {
+			return keys[i].targetID < keys[j].targetID
+		}
+		return keys[i].relpath < keys[j].relpath
+	})
+
+	// an extracted file is only hardlinked once, otherwise changing one of
+	// the deployed files would change the others as well
+	linked := map[string]bool{}
+
+	for _, k := range keys {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+
+		f := desired[k]
+		src := ""
+		m := f.target.method(false)
+		if f.rendered != nil {
+			// deployed from the staging directory, like archive members
+			src = filepath.Join(staging, "overrides", strconv.FormatInt(f.overrideID, 10))
+			if err := os.MkdirAll(filepath.Dir(src), 0o755); err != nil {
+				return res, err
+			}
+			if err := os.WriteFile(src, f.rendered, 0o644); err != nil {
+				return res, fmt.Errorf("render override %s: %w", f.relpath, err)
+			}
+		} else if f.overrideID != 0 {
+			src, err = d.Blobs.PathFor(blobstore.KindOverride, f.blobSHA)
+			if err != nil {
+				return res, err
+			}
+		} else {
+			var ok bool
+			src, ok = staged[f.archiveSHA][f.member]
+			if !ok {
+				return res, fmt.Errorf("%s not found in archive %s", f.member, shortSHA(f.archiveSHA))
+			}
+			if !linked[src] {
+				m = f.target.method(true)
+			}
+			if m == deploy.Hardlink {
+				linked[src] = true
+			}
+		}
+		if f.target.symlink() {
+			if src, err = d.linkSource(ctx, f, src); err != nil {
+				return res, err
+			}
+			m = deploy.Symlink
+		}
+
+		row, owned := byKey[k]
+		if err := d.writeDesired(ctx, gi, p, opID, f, src, m, row, owned, &res); err != nil {
+			return res, err
+		}
+	}
+
+	if err := mountOverlays(ctx, gi, desired); err != nil {
+		return res, err
+	}
+
+	// the files that were left alone now belong to this profile as well
+	if err := d.Q.SetInstalledFilesProfile(ctx, dbq.SetInstalledFilesProfileParams{
+		OwnerProfileID: sql.NullInt64{Int64: p.ID, Valid: true},
+		GameInstallID:  gi.ID,
+	}); err != nil {
+		return res, fmt.Errorf("update installed files: %w", err)
+	}
+
+	if err := d.Q.SetAppliedProfile(ctx, dbq.SetAppliedProfileParams{
+		AppliedProfileID:   sql.NullInt64{Int64: p.ID, Valid: true},
+		AppliedOperationID: sql.NullInt64{Int64: opID, Valid: true},
+		AppliedRevision:    sql.NullString{String: string(revJSON), Valid: true},
+		ID:                 gi.ID,
+	}); err != nil {
+		return res, fmt.Errorf("set applied profile: %w", err)
+	}
+
+	return res, nil
+}
+
+// Unapply removes every file that modctl deployed to a game install and
+// restores the files that they replaced.
+func (d *Deployer) Unapply(ctx context.Context, gi dbq.GameInstall) (res DeployResult, err error) {
+	if err := WaitForSteam(ctx, gi, d.SteamWait, d.OnSteamWait); err != nil {
+		return res, err
+	}
+
+	targets, err := d.resolveTargets(ctx, gi)
+	if err != nil {
+		return res, err
+	}
+	d.loadVanilla(gi, targets, &res)
+
+	installed, err := d.Q.ListInstalledFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return res, fmt.Errorf("list installed files: %w", err)
+	}
+	if err := d.checkDrift(ctx, installed, targets); err != nil {
+		return res, err
+	}
+
+	opID, err := d.Q.CreateOperation(ctx, dbq.CreateOperationParams{
+		GameInstallID: gi.ID,
+		ProfileID:     gi.AppliedProfileID,
+		OpType:        "unapply",
+	})
+	if err != nil {
+		return res, fmt.Errorf("create operation: %w", err)
+	}
+	res.OperationID = opID
+	defer func() {
+		d.pruneExtracted(&res)
+		d.finishOperation(opID, &res, err)
+	}()
+
+	if err := unmountOverlays(ctx, gi, targets); err != nil {
+		return res, err
+	}
+	for _, row := range installed {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		if err := d.removeInstalled(ctx, gi, opID, targets[row.TargetID], row, &res); err != nil {
+			return res, err
+		}
+	}
+
+	if err := d.Q.ClearAppliedProfile(ctx, dbq.ClearAppliedProfileParams{
+		AppliedOperationID: sql.NullInt64{Int64: opID, Valid: true},
+		ID:                 gi.ID,
+	}); err != nil {
+		return res, fmt.Errorf("clear applied profile: %w", err)
+	}
+
+	return res, nil
+}
+
+func (d *Deployer) resolveTargets(ctx context.Context, gi dbq.GameInstall) (map[int64]*deployTarget, error) {
+	rows, err := d.Q.ListTargetsForGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list targets: %w", err)
+	}
+
+	targets := make(map[int64]*deployTarget, len(rows))
+	for _, t := range rows {
+		root, err := DeployedRoot(gi, t)
+		if err != nil {
+			return nil, err
+		}
+		targets[t.ID] = &deployTarget{row: t, root: root}
+	}
+
+	return targets, nil
+}
+
+// resolveVersionedTargets points the versioned targets of a game install at
+// the directory of the game version that a profile is applied for. The ones
+// that move remember where their files were.
+func resolveVersionedTargets(gi dbq.GameInstall, p dbq.Profile, targets map[int64]*deployTarget) error {
+	version, known := "", false
+	for _, t := range targets {
+		if !IsVersionedTarget(t.row) {
+			continue
+		}
+
+		if !known {
+			var err error
+			version, err = ProfileGameVersion(gi, p)
+			if err != nil {
+				return fmt.Errorf("detect game version: %w", err)
+			}
+			if version == "" {
+				return fmt.Errorf("target %s is per game version but the version of %s isn't known: "+
+					"declare it with `modctl profiles game-version %s <version>`", t.row.Name, gi.DisplayName, p.Name)
+			}
+			known = true
+		}
+
+		root, err := VersionedTargetRoot(gi, t.row, version)
+		if err != nil {
+			return err
+		}
+		if root != t.root {
+			t.prevRoot, t.root = t.root, root
+		}
+	}
+
+	return nil
+}
+
+// probeTargets finds out what the filesystems of the targets support. If a
+// target can't be probed its files are copied and it's assumed to be
+// case-sensitive.
+func (d *Deployer) probeTargets(targets map[int64]*deployTarget, res *DeployResult) {
+	for _, t := range targets {
+		caps, err := fscaps.Probe(t.root, d.Blobs.TmpDir)
+		if err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("probe target %s: %v", t.row.Name, err))
+			caps = fscaps.Caps{CaseSensitive: true}
+		}
+		t.caps = caps
+	}
+}
+
+// checkCaseCollisions returns an error if a profile deploys files to a
+// case-insensitive target whose paths only differ in case: they would end up
+// as the same file.
+func checkCaseCollisions(desired map[pathKey]*desiredFile) error {
+	seen := map[pathKey]string{}
+	var clashes []string
+	for k, f := range desired {
+		if f.target.caps.CaseSensitive {
+			continue
+		}
+
+		lk := pathKey{k.targetID, strings.ToLower(k.relpath)}
+		other, ok := seen[lk]
+		if !ok {
+			seen[lk] = k.relpath
+			continue
+		}
+		a, b := min(other, k.relpath), max(other, k.relpath)
+		clashes = append(clashes, fmt.Sprintf("%s: %s and %s", f.target.row.Name, a, b))
+	}
+	if len(clashes) == 0 {
+		return nil
+	}
+
+	sort.Strings(clashes)
+	return fmt.Errorf("these files would overwrite each other on a case-insensitive filesystem (hide one of them with `modctl profiles hide`): %s",
+		strings.Join(clashes, "; "))
+}
+
+// checkWindowsNames returns an error if a profile deploys files to a target
+// with the naming rules of Windows (see fscaps.Caps) that can't be created
+// there.
+func checkWindowsNames(desired map[pathKey]*desiredFile) error {
+	var bad []string
+	for k, f := range desired {
+		if !f.target.caps.WindowsNames {
+			continue
+		}
+		if err := plan.WindowsNameError(k.relpath); err != nil {
+			bad = append(bad, fmt.Sprintf("%s: %s (%v)", f.target.row.Name, k.relpath, err))
+		}
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+
+	sort.Strings(bad)
+	return fmt.Errorf("these files can't be created on a filesystem with Windows naming rules (hide them with `modctl profiles hide`): %s",
+		strings.Join(bad, "; "))
+}
+
+// checkTargetAccess makes sure that the targets that a profile deploys to
+// can be written to before anything changes, so that apply doesn't stop
+// halfway through. Games of flatpak steam run in its sandbox, which only sees
+// the steam data directory and the libraries (and whatever else was granted
+// to it), so targets outside of them only warn that the game may not see the
+// files.
+func checkTargetAccess(gi dbq.GameInstall, desired map[pathKey]*desiredFile, res *DeployResult) error {
+	flatpak := SteamFlatpak(gi)
+
+	var sandboxDirs []string
+	if flatpak {
+		sandboxDirs = append(sandboxDirs, flatpakSteamDir())
+		if steamapps, err := SteamappsDir(gi); err == nil {
+			sandboxDirs = append(sandboxDirs, filepath.Dir(steamapps))
+		}
+	}
+
+	checked := map[*deployTarget]bool{}
+	var problems []string
+	for _, f := range desired {
+		t := f.target
+		if checked[t] {
+			continue
+		}
+		checked[t] = true
+
+		if err := fscaps.Writable(t.root); err != nil {
+			msg := fmt.Sprintf("target %s (%s) is not writable: %v", t.row.Name, t.root, err)
+			if flatpak {
+				msg += fmt.Sprintf(" (flatpak steam: grant access with `%s`)",
+					FlatpakOverrideCommand(t.root))
+			}
+			problems = append(problems, msg)
+			continue
+		}
+
+		if flatpak && !underAny(t.root, sandboxDirs) {
+			res.Warnings = append(res.Warnings, fmt.Sprintf(
+				"target %s (%s) is outside of the flatpak steam sandbox, the game may not see it; grant access with `%s`",
+				t.row.Name, t.root, FlatpakOverrideCommand(t.root)))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+func underAny(path string, dirs []string) bool {
+	for _, d := range dirs {
+		if d == "" {
+			continue
+		}
+		if ok, err := IsUnderDir(path, d); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// loadVanilla attaches the vanilla files of a steam game (according to its
+// depot manifests) to its game_dir target. Without them every replaced file
+// is backed up, so failing to read them only warns.
+func (d *Deployer) loadVanilla(gi dbq.GameInstall, targets map[int64]*deployTarget, res *DeployResult) {
+	if (!d.SteamManifests && !gi.VerifiedVanillaAt.Valid) || gi.StoreID != "steam" {
+		return
+	}
+
+	var gameDir *deployTarget
+	for _, t := range targets {
+		if t.row.Name == plan.DefaultTarget && filepath.Clean(t.root) == filepath.Clean(gi.InstallRoot) {
+			gameDir = t
+		}
+	}
+	if gameDir == nil {
+		return
+	}
+
+	ix, warnings, err := SteamVanilla(gi)
+	if err != nil {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("steam depot manifests: %v", err))
+		return
+	}
+	for _, w := range warnings {
+		res.Warnings = append(res.Warnings, "steam depot manifests: "+w)
+	}
+	if ix.Len() > 0 {
+		gameDir.vanilla = ix
+	}
+}
+
+// desiredFiles returns the files that a profile deploys: the files of its
+// plan, with the profile's overrides (template overrides expanded for the
+// install) on top.
+func (d *Deployer) desiredFiles(ctx context.Context, gi dbq.GameInstall, p dbq.Profile, pl *plan.Plan, targets map[int64]*deployTarget) (map[pathKey]*desiredFile, error) {
+	byName := make(map[string]*deployTarget, len(targets))
+	for _, t := range targets {
+		byName[t.row.Name] = t
+	}
+
+	desired := map[pathKey]*desiredFile{}
+	for _, f := range pl.Files {
+		t, ok := byName[f.Target]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown target %q (run `modctl games refresh`?)", f.RelPath, f.Target)
+		}
+		desired[pathKey{t.row.ID, f.RelPath}] = &desiredFile{
+			target:     t,
+			relpath:    f.RelPath,
+			versionID:  f.Winner.Item.VersionID,
+			archiveSHA: f.Winner.Item.ArchiveSHA256,
+			member:     f.Winner.File(),
+			rules:      revisionRules(f.Winner.Item.Rules),
+			symlinks:   f.Winner.Item.Symlinks,
+		}
+	}
+
+	overrides, err := d.Q.ListOverridesForProfile(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list overrides: %w", err)
+	}
+	var vars map[string]string
+	for _, o := range overrides {
+		t, ok := targets[o.TargetID]
+		if !ok {
+			return nil, fmt.Errorf("override %s: target %d not found", o.Relpath, o.TargetID)
+		}
+		f := &desiredFile{
+			target:     t,
+			relpath:    o.Relpath,
+			overrideID: o.ID,
+			blobSHA:    o.BlobSha256,
+		}
+
+		if o.IsTemplate != 0 {
+			if vars == nil {
+				if vars, err = OverrideVars(gi, p); err != nil {
+					return nil, err
+				}
+			}
+			src, err := d.Blobs.PathFor(blobstore.KindOverride, o.BlobSha256)
+			if err != nil {
+				return nil, err
+			}
+			content, err := os.ReadFile(src)
+			if err != nil {
+				return nil, fmt.Errorf("override %s: %w", o.Relpath, err)
+			}
+			if f.rendered, err = RenderOverride(content, vars); err != nil {
+				return nil, fmt.Errorf("override %s: %w", o.Relpath, err)
+			}
+			sum := sha256.Sum256(f.rendered)
+			f.renderedSHA = hex.EncodeToString(sum[:])
+		}
+
+		desired[pathKey{o.TargetID, o.Relpath}] = f
+	}
+
+	return desired, nil
+}
+
+// changedFiles splits an apply into the desired files that are already
+// deployed (none of them with full), the deployed files that are about to be
+// replaced or removed (only these have to be checked for changes) and the
+// desired files that have to be written.
+func changedFiles(gi dbq.GameInstall, desired map[pathKey]*desiredFile, installed []dbq.InstalledFile, byKey map[pathKey]dbq.InstalledFile, full bool) (map[pathKey]bool, []dbq.InstalledFile, []pathKey) {
+	unchanged := map[pathKey]bool{}
+	if !full {
+		unchanged = unchangedFiles(gi, desired, byKey)
+	}
+
+	var touched []dbq.InstalledFile
+	for _, row := range installed {
+		if !unchanged[pathKey{row.TargetID, row.Relpath}] {
+			touched = append(touched, row)
+		}
+	}
+
+	pending := make([]pathKey, 0, len(desired))
+	for k := range desired {
+		if !unchanged[k] {
+			pending = append(pending, k)
+		}
+	}
+
+	return unchanged, touched, pending
+}
+
+// unchangedFiles returns the desired files that are already deployed: the
+// same override, or the same mod version with the same remap rules as in the
+// applied revision (so the same archive member ends up at the path) and
+// the same symlink policy (a link in the archive may be skipped now). Files
+// whose size on disk doesn't match what was deployed are redeployed.
+func unchangedFiles(gi dbq.GameInstall, desired map[pathKey]*desiredFile, installed map[pathKey]dbq.InstalledFile) map[pathKey]bool {
+	out := map[pathKey]bool{}
+
+	applied, ok, err := AppliedRevision(gi)
+	if err != nil || !ok {
+		// nothing to compare to: deploy everything
+		return out
+	}
+	appliedItems := map[int64]RevisionItem{}
+	for _, it := range applied.Items {
+		appliedItems[it.VersionID] = it
+	}
+
+	for k, f := range desired {
+		row, ok := installed[k]
+		if !ok {
+			continue
+		}
+
+		if f.overrideID != 0 {
+			if row.OwnerOverrideID.Int64 != f.overrideID || row.ContentSha256 != f.contentSHA() {
+				continue
+			}
+		} else {
+			it, ok := appliedItems[f.versionID]
+			if !ok || row.OwnerModFileVersionID.Int64 != f.versionID || !slices.Equal(it.Rules, f.rules) ||
+				symlinkPolicy(it.Symlinks) != symlinkPolicy(f.symlinks) {
+				continue
+			}
+		}
+
+		st, err := os.Stat(filepath.Join(f.target.root, filepath.FromSlash(f.relpath)))
+		if err != nil || !st.Mode().IsRegular() || st.Size() != row.SizeBytes {
+			continue
+		}
+
+		out[k] = true
+	}
+
+	return out
+}
+
+func revisionRules(rules []plan.Rule) []RevisionRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	out := make([]RevisionRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, RevisionRule{Type: r.Type, Int: r.Int, Text: r.Text})
+	}
+	return out
+}
+
+// checkDrift refuses to continue (unless forced) if files that modctl
+// deployed were modified. Files that are gone are fine: they are written
+// again or there is nothing left to remove.
+func (d *Deployer) checkDrift(ctx context.Context, installed []dbq.InstalledFile, targets map[int64]*deployTarget) error {
+	drift, err := d.findDrift(ctx, installed, targets)
+	if err != nil {
+		return err
+	}
+	if drift != nil && !d.Force {
+		return drift
+	}
+	return nil
+}
+
+// Drift returns the files that modctl deployed to a game install that were
+// changed on disk since they were deployed (the files that an apply or an
+// unapply refuses to replace), or nil if there aren't any. The warnings are
+// about the steam depot manifests that the vanilla files are checked with.
+func (d *Deployer) Drift(ctx context.Context, gi dbq.GameInstall) (*DriftError, []string, error) {
+	targets, err := d.resolveTargets(ctx, gi)
+	if err != nil {
+		return nil, nil, err
+	}
+	var res DeployResult
+	d.loadVanilla(gi, targets, &res)
+
+	installed, err := d.Q.ListInstalledFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return nil, res.Warnings, fmt.Errorf("list installed files: %w", err)
+	}
+	drift, err := d.findDrift(ctx, installed, targets)
+	return drift, res.Warnings, err
+}
+
+// findDrift returns the installed files that were modified (see
+// checkDrift), or nil if there aren't any.
+func (d *Deployer) findDrift(ctx context.Context, installed []dbq.InstalledFile, targets map[int64]*deployTarget) (*DriftError, error) {
+	var drifted, vanilla []string
+
+	for _, row := range installed {
+		t, ok := targets[row.TargetID]
+		if !ok {
+			return nil, fmt.Errorf("installed file %s: target %d not found", row.Relpath, row.TargetID)
+		}
+
+		dst := filepath.Join(t.root, filepath.FromSlash(row.Relpath))
+		sha, _, err := deploy.HashFileCached(ctx, d.Hashes, dst)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if sha == row.ContentSha256 {
+			continue
+		}
+
+		drifted = append(drifted, t.row.Name+"/"+row.Relpath)
+		if t.vanilla != nil {
+			ok, err := t.vanilla.Matches(row.Relpath, dst)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				vanilla = append(vanilla, t.row.Name+"/"+row.Relpath)
+			}
+		}
+	}
+
+	if len(drifted) == 0 {
+		return nil, nil
+	}
+	return &DriftError{Paths: drifted, Vanilla: vanilla}, nil
+}
+
+// verifyArchive checks an archive that was never deployed before against its
+// blob record (by hashing all of it), so that a corrupted blob is caught
+// before its files end up in the game and not afterwards. Archives that were
+// deployed before were checked then (doctor --recheck checks all of them),
+// but quarantined ones are always refused.
+func (d *Deployer) verifyArchive(ctx context.Context, sha string) error {
+	blob, err := d.Q.GetBlob(ctx, sha)
+	if err != nil {
+		return fmt.Errorf("lookup blob %s: %w", shortSHA(sha), err)
+	}
+	if blob.CorruptedAt.Valid {
+		return BlobMissingError(sha, fmt.Errorf("%w, it was quarantined", blobstore.ErrCorrupt))
+	}
+
+	_, err = d.Q.ArchiveWasDeployed(ctx, sha)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("lookup deployments of archive %s: %w", shortSHA(sha), err)
+	}
+
+	if err := d.Blobs.Verify(ctx, blobstore.KindArchive, sha, blob.SizeBytes); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return BlobMissingError(sha, err)
+		}
+		return fmt.Errorf("verify archive %s: %w (see `modctl mods verify`)", shortSHA(sha), err)
+	}
+
+	return d.Q.TouchBlobVerifiedAt(ctx, dbq.TouchBlobVerifiedAtParams{
+		VerifiedAt: sql.NullString{String: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"), Valid: true},
+		Sha256:     sha,
+	})
+}
+
+// removeInstalled removes a deployed file and restores its backup, if
+// there is one.
+func (d *Deployer) removeInstalled(ctx context.Context, gi dbq.GameInstall, opID int64, t *deployTarget, row dbq.InstalledFile, res *DeployResult) error {
+	dst := filepath.Join(t.root, filepath.FromSlash(row.Relpath))
+
+	backup, err := d.Q.GetBackupForPath(ctx, dbq.GetBackupForPathParams{
+		GameInstallID: gi.ID,
+		TargetID:      row.TargetID,
+		Relpath:       row.Relpath,
+	})
+	hasBackup := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("lookup backup: %w", err)
+	}
+
+	change := dbq.InsertOperationChangeParams{
+		OperationID:      opID,
+		GameInstallID:    gi.ID,
+		TargetID:         row.TargetID,
+		Relpath:          row.Relpath,
+		Action:           "remove",
+		OldContentSha256: sql.NullString{String: row.ContentSha256, Valid: true},
+		OldSizeBytes:     sql.NullInt64{Int64: row.SizeBytes, Valid: true},
+		ModFileVersionID: row.OwnerModFileVersionID,
+	}
+
+	res.release(row.ContentSha256)
+	if hasBackup {
+		src, err := d.Blobs.Locate(blobstore.Kind(backup.BlobKind), backup.BackupBlobSha256)
+		if err != nil {
+			return err
+		}
+		sha, size, err := deploy.PlaceFile(ctx, src, dst, t.method(false))
+		if err != nil {
+			return fmt.Errorf("restore %s: %w", row.Relpath, err)
+		}
+		if backup.ReadOnly != 0 {
+			if err := deploy.SetReadOnly(dst); err != nil {
+				return fmt.Errorf("restore %s: %w", row.Relpath, err)
+			}
+		}
+		change.Action = "restore_backup"
+		change.NewContentSha256 = sql.NullString{String: sha, Valid: true}
+		change.NewSizeBytes = sql.NullInt64{Int64: size, Valid: true}
+		change.BackupBlobSha256 = sql.NullString{String: backup.BackupBlobSha256, Valid: true}
+		res.Restored++
+	} else {
+		if err := deploy.RemoveFile(dst, t.root); err != nil {
+			return err
+		}
+		res.Removed++
+
+		// a vanilla file that was replaced without a backup
+		if vf, ok := t.vanilla.Lookup(row.Relpath); ok && vf.Restorable() {
+			res.SteamRestore = append(res.SteamRestore, ChangedPath{t.row.Name, row.Relpath})
+		}
+	}
+	res.Changed = append(res.Changed, ChangedPath{t.row.Name, row.Relpath})
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := d.Q.WithTx(tx)
+
+	if err := qtx.DeleteInstalledFile(ctx, row.ID); err != nil {
+		return fmt.Errorf("delete installed file: %w", err)
+	}
+	if hasBackup {
+		// the backup blob stays in the store until it's pruned
+		if err := qtx.DeleteBackup(ctx, backup.ID); err != nil {
+			return fmt.Errorf("delete backup: %w", err)
+		}
+	}
+	if err := qtx.InsertOperationChange(ctx, change); err != nil {
+		return fmt.Errorf("record change: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// writeDesired deploys a file (with the given method), backing up whatever
+// (not deployed by modctl) was there before unless it's a vanilla file that
+// Steam can restore or the backup policy of the target is none.
+func (d *Deployer) writeDesired(ctx context.Context, gi dbq.GameInstall, p dbq.Profile, opID int64, f *desiredFile, src string, m deploy.Method, row dbq.InstalledFile, owned bool, res *DeployResult) error {
+	dst := filepath.Join(f.target.root, filepath.FromSlash(f.relpath))
+
+	srcSHA, _, err := deploy.HashFile(src)
+	if err != nil {
+		return err
+	}
+
+	change := dbq.InsertOperationChangeParams{
+		OperationID:   opID,
+		GameInstallID: gi.ID,
+		TargetID:      f.target.row.ID,
+		Relpath:       f.relpath,
+		Action:        "write",
+	}
+	if f.versionID != 0 {
+		change.ModFileVersionID = sql.NullInt64{Int64: f.versionID, Valid: true}
+	}
+
+	if owned {
+		onDisk, _, err := deploy.HashFileCached(ctx, d.Hashes, dst)
+		if err == nil && onDisk == srcSHA && row.ContentSha256 == srcSHA {
+			res.Unchanged++
+			return d.recordInstalled(ctx, gi, p, opID, f, srcSHA, row.SizeBytes, nil)
+		}
+		change.Action = "overwrite"
+		change.OldContentSha256 = sql.NullString{String: row.ContentSha256, Valid: true}
+		change.OldSizeBytes = sql.NullInt64{Int64: row.SizeBytes, Valid: true}
+		res.release(row.ContentSha256)
+	}
+
+	var backupRow *dbq.UpsertBackupParams
+	var backupKind blobstore.Kind
+	if !owned {
+		st, err := os.Lstat(dst)
+		vanilla := false
+		if err == nil && st.Mode().IsRegular() && f.target.vanilla != nil {
+			vanilla, err = f.target.vanilla.Matches(f.relpath, dst)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case vanilla:
+			sha, size, err := deploy.HashFile(dst)
+			if err != nil {
+				return err
+			}
+			change.Action = "overwrite"
+			change.OldContentSha256 = sql.NullString{String: sha, Valid: true}
+			change.OldSizeBytes = sql.NullInt64{Int64: size, Valid: true}
+			change.Notes = sql.NullString{String: "vanilla file (steam depot manifest), not backed up", Valid: true}
+			res.Vanilla++
+		case err == nil && st.Mode().IsRegular() && f.target.row.BackupPolicy == BackupPolicyNone:
+			sha, size, err := deploy.HashFile(dst)
+			if err != nil {
+				return err
+			}
+			change.Action = "overwrite"
+			change.OldContentSha256 = sql.NullString{String: sha, Valid: true}
+			change.OldSizeBytes = sql.NullInt64{Int64: size, Valid: true}
+			change.Notes = sql.NullString{String: "not backed up (backup policy of the target)", Valid: true}
+			res.NotBackedUp++
+		case err == nil && st.Mode().IsRegular():
+			bak, kind, err := d.backUp(ctx, dst)
+			if err != nil {
+				return fmt.Errorf("back up %s: %w", f.relpath, err)
+			}
+			backupKind = kind
+			change.Action = "overwrite"
+			change.OldContentSha256 = sql.NullString{String: bak.SHA256Hex, Valid: true}
+			change.OldSizeBytes = sql.NullInt64{Int64: bak.SizeBytes, Valid: true}
+			change.BackupBlobSha256 = sql.NullString{String: bak.SHA256Hex, Valid: true}
+			backupRow = &dbq.UpsertBackupParams{
+				GameInstallID:         gi.ID,
+				TargetID:              f.target.row.ID,
+				Relpath:               f.relpath,
+				BackupBlobSha256:      bak.SHA256Hex,
+				OriginalContentSha256: sql.NullString{String: bak.SHA256Hex, Valid: true},
+				SizeBytes:             bak.SizeBytes,
+				CreatedByOperationID:  sql.NullInt64{Int64: opID, Valid: true},
+			}
+			// so that the restore makes it read-only again
+			if st.Mode().Perm()&0o200 == 0 {
+				backupRow.ReadOnly = 1
+			}
+			res.BackedUp++
+		case err == nil:
+			return fmt.Errorf("%s: exists and is not a regular file", dst)
+		case !errors.Is(err, os.ErrNotExist):
+			return err
+		}
+	}
+
+	sha, size, err := deploy.PlaceFile(ctx, src, dst, m)
+	if err != nil {
+		return fmt.Errorf("deploy %s: %w", f.relpath, err)
+	}
+	if err := deploy.RememberHash(ctx, d.Hashes, dst, sha); err != nil {
+		return err
+	}
+	change.NewContentSha256 = sql.NullString{String: sha, Valid: true}
+	change.NewSizeBytes = sql.NullInt64{Int64: size, Valid: true}
+
+	if change.Action == "write" {
+		res.Written++
+	} else {
+		res.Overwritten++
+	}
+	res.Changed = append(res.Changed, ChangedPath{f.target.row.Name, f.relpath})
+
+	return d.recordInstalled(ctx, gi, p, opID, f, sha, size, func(qtx *dbq.Queries) error {
+		if backupRow != nil {
+			base := filepath.Base(f.relpath)
+			if err := blobstore.EnsureBlobRecorded(ctx, qtx, backupRow.BackupBlobSha256,
+				string(backupKind), backupRow.SizeBytes, &base); err != nil {
+				return err
+			}
+			if err := qtx.UpsertBackup(ctx, *backupRow); err != nil {
+				return fmt.Errorf("record backup: %w", err)
+			}
+		}
+		if err := qtx.InsertOperationChange(ctx, change); err != nil {
+			return fmt.Errorf("record change: %w", err)
+		}
+		return nil
+	})
+}
+
+// linkSource returns what a file is symlinked to on a symlink target: a
+// stored override as it is, anything else (archive members and expanded
+// template overrides, which are only in the staging directory) stored in the
+// extracted store first.
+func (d *Deployer) linkSource(ctx context.Context, f *desiredFile, src string) (string, error) {
+	if f.overrideID != 0 && f.rendered == nil {
+		return src, nil
+	}
+
+	r, err := d.Blobs.IngestFile(ctx, blobstore.KindExtracted, src)
+	if err != nil {
+		return "", fmt.Errorf("store %s: %w", f.relpath, err)
+	}
+	if dryrun.Enabled() && !r.Existed {
+		// it was only pretended to be stored
+		return src, nil
+	}
+	return d.Blobs.PathFor(blobstore.KindExtracted, r.SHA256Hex)
+}
+
+func (r *DeployResult) release(sha string) {
+	if r.released == nil {
+		r.released = map[string]bool{}
+	}
+	r.released[sha] = true
+}
+
+// pruneExtracted removes the files of the extracted store that no deployed
+// file links to anymore. It runs even if the command was interrupted (see
+// finishOperation) and only warns: a file that is left behind is just
+// garbage.
+func (d *Deployer) pruneExtracted(res *DeployResult) {
+	ctx := context.Background()
+	for sha := range res.released {
+		path, err := d.Blobs.PathFor(blobstore.KindExtracted, sha)
+		if err != nil {
+			return
+		}
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+
+		n, err := d.Q.CountInstalledFilesWithContent(ctx, sha)
+		if err == nil && n == 0 {
+			err = d.Blobs.Remove(blobstore.KindExtracted, sha)
+		}
+		if err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("prune extracted file %s: %v", shortSHA(sha), err))
+		}
+	}
+}
+
+// unmountOverlays unmounts the overlays of the targets that have one (see
+// DeployMethodOverlay): their layers only change while they aren't mounted.
+func unmountOverlays(ctx context.Context, gi dbq.GameInstall, targets map[int64]*deployTarget) error {
+	for _, t := range targets {
+		if t.row.DeployMethod != DeployMethodOverlay {
+			continue
+		}
+		if err := UnmountOverlay(ctx, gi, t.row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mountOverlays mounts the overlays of the targets that a profile deploys
+// files to.
+func mountOverlays(ctx context.Context, gi dbq.GameInstall, desired map[pathKey]*desiredFile) error {
+	mounted := map[*deployTarget]bool{}
+	for _, f := range desired {
+		t := f.target
+		if t.row.DeployMethod != DeployMethodOverlay || mounted[t] {
+			continue
+		}
+		mounted[t] = true
+		if err := MountOverlay(ctx, gi, t.row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backUp stores a copy of a file that is about to be replaced and returns it
+// with the kind of blob that has it. Content is only stored once: backups of
+// the same file in several targets or game installs share their blob, and a
+// file whose content is already stored as another kind of blob (e.g., an
+// override) uses that one.
+func (d *Deployer) backUp(ctx context.Context, path string) (blobstore.IngestResult, blobstore.Kind, error) {
+	bak, err := d.Blobs.IngestFile(ctx, blobstore.KindBackup, path)
+	if err != nil {
+		return bak, "", err
+	}
+
+	blob, err := d.Q.GetBlob(ctx, bak.SHA256Hex)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && blob.Kind == string(blobstore.KindBackup)) {
+		return bak, blobstore.KindBackup, nil
+	}
+	if err != nil {
+		return bak, "", fmt.Errorf("lookup blob %s: %w", shortSHA(bak.SHA256Hex), err)
+	}
+
+	kind := blobstore.Kind(blob.Kind)
+	if !bak.Existed {
+		if err := d.Blobs.Remove(blobstore.KindBackup, bak.SHA256Hex); err != nil {
+			return bak, "", err
+		}
+	}
+	if blob.CorruptedAt.Valid {
+		// it was quarantined: this puts it back
+		if bak, err = d.Blobs.IngestFile(ctx, kind, path); err != nil {
+			return bak, "", err
+		}
+	}
+	return bak, kind, nil
+}
+
+// recordInstalled stores the new state of a deployed file together with
+// whatever else (backups, the change log) extra records.
+func (d *Deployer) recordInstalled(ctx context.Context, gi dbq.GameInstall, p dbq.Profile, opID int64, f *desiredFile, sha string, size int64, extra func(*dbq.Queries) error) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := d.Q.WithTx(tx)
+
+	if extra != nil {
+		if err := extra(qtx); err != nil {
+			return err
+		}
+	}
+
+	params := dbq.UpsertInstalledFileParams{
+		GameInstallID:   gi.ID,
+		TargetID:        f.target.row.ID,
+		Relpath:         f.relpath,
+		ContentSha256:   sha,
+		SizeBytes:       size,
+		OwnerProfileID:  sql.NullInt64{Int64: p.ID, Valid: true},
+		LastOperationID: sql.NullInt64{Int64: opID, Valid: true},
+	}
+	if f.overrideID != 0 {
+		params.OwnerOverrideID = sql.NullInt64{Int64: f.overrideID, Valid: true}
+	} else {
+		params.OwnerModFileVersionID = sql.NullInt64{Int64: f.versionID, Valid: true}
+	}
+
+	if err := qtx.UpsertInstalledFile(ctx, params); err != nil {
+		return fmt.Errorf("record installed file: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// finishOperation marks an operation as succeeded or failed. It runs even
+// if the command was interrupted, so it doesn't use the command's context.
+func (d *Deployer) finishOperation(opID int64, res *DeployResult, opErr error) {
+	status := "success"
+	var msg sql.NullString
+	if opErr != nil {
+		status = "failed"
+		msg = sql.NullString{String: opErr.Error(), Valid: true}
+	}
+
+	var meta sql.NullString
+	if b, err := json.Marshal(res); err == nil {
+		meta = sql.NullString{String: string(b), Valid: true}
+	}
+
+	_ = d.Q.FinishOperation(context.Background(), dbq.FinishOperationParams{
+		Status:   status,
+		Message:  msg,
+		Metadata: meta,
+		ID:       opID,
+	})
+
+	// every change is an operation change and (most of the time) an
+	// installed file row
+	AutoOptimize(context.Background(), d.DB, int64(2*len(res.Changed)))
+}
+
+// writeReport writes the report of an operation (see ReportsDir). It's only
+// a warning if that fails: the operation is done either way.
+func (d *Deployer) writeReport(opID int64, res *DeployResult) {
+	if d.ReportsDir == "" {
+		return
+	}
+	if dryrun.Enabled() {
+		dryrun.Note("write the report of operation %d to %s", opID, d.ReportsDir)
+		return
+	}
+
+	r, err := BuildOperationReport(context.Background(), d.Q, opID)
+	if err == nil {
+		res.Report, err = WriteOperationReport(d.ReportsDir, r)
+	}
+	if err != nil {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("write report of operation %d: %v", opID, err))
+	}
+}
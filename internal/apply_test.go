@@ -0,0 +1,474 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDeployer returns a deployer with a migrated database and empty blob
+// stores, and a game install with one target (game_dir, id 1) whose root is
+// returned too.
+func testDeployer(t *testing.T) (*Deployer, dbq.GameInstall, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "modctl.db")+DB_PRAGMAS)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	p, err := GooseProvider(db)
+	require.NoError(t, err)
+	_, err = p.Up(ctx)
+	require.NoError(t, err)
+
+	root := filepath.Join(dir, "game")
+	require.NoError(t, os.MkdirAll(root, 0o755))
+	_, err = db.Exec(`INSERT INTO game_installs (id, store_id, store_game_id, display_name, install_root)
+		VALUES (1, 'steam', '489830', 'Skyrim', ?)`, root)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO targets (id, game_install_id, name, root_path, origin)
+		VALUES (1, 1, 'game_dir', ?, 'user_override')`, root)
+	require.NoError(t, err)
+
+	q := dbq.New(db)
+	gi, err := q.GetGameInstallByID(ctx, 1)
+	require.NoError(t, err)
+
+	d := &Deployer{
+		DB: db,
+		Q:  q,
+		Blobs: blobstore.Store{
+			ArchivesDir:       filepath.Join(dir, "archives"),
+			SharedArchivesDir: filepath.Join(dir, "shared"),
+			BackupsDir:        filepath.Join(dir, "backups"),
+			OverridesDir:      filepath.Join(dir, "overrides"),
+			TmpDir:            filepath.Join(dir, "tmp"),
+			QuarantineDir:     filepath.Join(dir, "quarantine"),
+			ExtractedDir:      filepath.Join(dir, "extracted"),
+		},
+	}
+
+	return d, gi, root
+}
+
+// writeBlob puts content in a blob store directory (<root>/ab/<sha256>) and
+// records it as a blob of the given kind; it returns its sha256.
+func writeBlob(t *testing.T, d *Deployer, root string, kind blobstore.Kind, content string) string {
+	t.Helper()
+
+	tmp := filepath.Join(t.TempDir(), "blob")
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0o644))
+	sha, size, err := deploy.HashFile(tmp)
+	require.NoError(t, err)
+
+	path := filepath.Join(root, sha[:2], sha)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.Rename(tmp, path))
+
+	_, err = d.DB.Exec(`INSERT INTO blobs (sha256, kind, size_bytes) VALUES (?, ?, ?)`,
+		sha, string(kind), size)
+	require.NoError(t, err)
+
+	return sha
+}
+
+// installModFile deploys content to relpath as a file of a mod (file
+// version 1 with the archive archiveSHA) and records it; it returns the
+// sha256 of the content.
+func installModFile(t *testing.T, d *Deployer, root, relpath, content, archiveSHA string) string {
+	t.Helper()
+
+	path := filepath.Join(root, filepath.FromSlash(relpath))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	sha, size, err := deploy.HashFile(path)
+	require.NoError(t, err)
+
+	_, err = d.DB.Exec(`
+		INSERT OR IGNORE INTO mod_pages (id, game_install_id, name, source_kind) VALUES (1, 1, 'SkyUI', 'manual');
+		INSERT OR IGNORE INTO mod_files (id, mod_page_id, label) VALUES (1, 1, 'main');`)
+	require.NoError(t, err)
+	_, err = d.DB.Exec(`INSERT OR IGNORE INTO mod_file_versions (id, mod_file_id, archive_sha256)
+		VALUES (1, 1, ?)`, archiveSHA)
+	require.NoError(t, err)
+	_, err = d.DB.Exec(`INSERT INTO installed_files (game_install_id, target_id, relpath, content_sha256, size_bytes, owner_mod_file_version_id)
+		VALUES (1, 1, ?, ?, ?, 1)`, relpath, sha, size)
+	require.NoError(t, err)
+
+	return sha
+}
+
+func TestUnapplyRestoresBackupFromSharedStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+
+	// the file that was replaced is an archive that only the shared
+	// archive store has
+	original := "the original file"
+	sha := writeBlob(t, d, d.Blobs.SharedArchivesDir, blobstore.KindArchive, original)
+	installModFile(t, d, root, "data/a.esp", "modded", sha)
+	_, err := d.DB.Exec(`
+		INSERT INTO backups (game_install_id, target_id, relpath, backup_blob_sha256, size_bytes)
+		VALUES (1, 1, 'data/a.esp', ?, ?)`, sha, len(original))
+	require.NoError(t, err)
+
+	res, err := d.Unapply(ctx, gi)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Restored)
+
+	got, err := os.ReadFile(filepath.Join(root, "data", "a.esp"))
+	require.NoError(t, err)
+	assert.Equal(t, original, string(got))
+
+	backups, err := d.Q.ListBackupsForGame(ctx, gi.ID)
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+	assert.NoFileExists(t, filepath.Join(d.Blobs.ArchivesDir, sha[:2], sha))
+}
+
+// deployOver deploys content as a file of a mod (see installModFile) over a
+// file that modctl doesn't know about yet, which is backed up, like an apply
+// does.
+func deployOver(t *testing.T, d *Deployer, gi dbq.GameInstall, target *deployTarget, relpath, existing, content string) DeployResult {
+	t.Helper()
+	ctx := context.Background()
+
+	archive := writeBlob(t, d, d.Blobs.ArchivesDir, blobstore.KindArchive, "archive of "+relpath)
+	_, err := d.DB.Exec(`
+		INSERT OR IGNORE INTO mod_pages (id, game_install_id, name, source_kind) VALUES (1, 1, 'SkyUI', 'manual');
+		INSERT OR IGNORE INTO mod_files (id, mod_page_id, label) VALUES (1, 1, 'main');
+		INSERT OR IGNORE INTO profiles (id, game_install_id, name) VALUES (1, 1, 'default');`)
+	require.NoError(t, err)
+	res, err := d.DB.Exec(`INSERT INTO mod_file_versions (mod_file_id, archive_sha256) VALUES (1, ?)`, archive)
+	require.NoError(t, err)
+	versionID, err := res.LastInsertId()
+	require.NoError(t, err)
+
+	p, err := d.Q.GetProfileByID(ctx, 1)
+	require.NoError(t, err)
+	opID, err := d.Q.CreateOperation(ctx, dbq.CreateOperationParams{
+		GameInstallID: gi.ID,
+		ProfileID:     sql.NullInt64{Int64: p.ID, Valid: true},
+		OpType:        "apply",
+	})
+	require.NoError(t, err)
+
+	dst := filepath.Join(target.root, filepath.FromSlash(relpath))
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+	require.NoError(t, os.WriteFile(dst, []byte(existing), 0o644))
+	src := filepath.Join(t.TempDir(), "src")
+	require.NoError(t, os.WriteFile(src, []byte(content), 0o644))
+
+	f := &desiredFile{target: target, relpath: relpath, versionID: versionID, archiveSHA: archive}
+	var out DeployResult
+	require.NoError(t, d.writeDesired(ctx, gi, p, opID, f, src, deploy.Copy, dbq.InstalledFile{}, false, &out))
+	return out
+}
+
+// testTargets returns the game_dir target of testDeployer and a second one
+// (data_dir, id 2) with its own root.
+func testTargets(t *testing.T, d *Deployer, root string) (*deployTarget, *deployTarget) {
+	t.Helper()
+	ctx := context.Background()
+
+	root2 := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.MkdirAll(root2, 0o755))
+	_, err := d.DB.Exec(`INSERT INTO targets (id, game_install_id, name, root_path, origin)
+		VALUES (2, 1, 'data_dir', ?, 'user_override')`, root2)
+	require.NoError(t, err)
+
+	t1, err := d.Q.GetTargetByName(ctx, dbq.GetTargetByNameParams{GameInstallID: 1, Name: "game_dir"})
+	require.NoError(t, err)
+	t2, err := d.Q.GetTargetByName(ctx, dbq.GetTargetByNameParams{GameInstallID: 1, Name: "data_dir"})
+	require.NoError(t, err)
+	return &deployTarget{row: t1, root: root}, &deployTarget{row: t2, root: root2}
+}
+
+// storedBlobs returns the names of the blobs in a blob store directory.
+func storedBlobs(t *testing.T, root string) []string {
+	t.Helper()
+
+	var out []string
+	err := filepath.WalkDir(root, func(path string, e os.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+		if err == nil && !e.IsDir() {
+			out = append(out, e.Name())
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return out
+}
+
+func TestBackUpSharesBlobAcrossTargets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	t1, t2 := testTargets(t, d, root)
+
+	res := deployOver(t, d, gi, t1, "a.ini", "the original file", "modded")
+	assert.Equal(t, 1, res.BackedUp)
+	res = deployOver(t, d, gi, t2, "b.ini", "the original file", "modded too")
+	assert.Equal(t, 1, res.BackedUp)
+
+	backups, err := d.Q.ListBackupsForGame(ctx, gi.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	sha := backups[0].BackupBlobSha256
+	assert.Equal(t, sha, backups[1].BackupBlobSha256)
+
+	blob, err := d.Q.GetBlob(ctx, sha)
+	require.NoError(t, err)
+	assert.Equal(t, string(blobstore.KindBackup), blob.Kind)
+	assert.Equal(t, []string{sha}, storedBlobs(t, d.Blobs.BackupsDir))
+
+	// and both files come back from it
+	unapplied, err := d.Unapply(ctx, gi)
+	require.NoError(t, err)
+	assert.Equal(t, 2, unapplied.Restored)
+	for _, path := range []string{filepath.Join(root, "a.ini"), filepath.Join(t2.root, "b.ini")} {
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "the original file", string(got))
+	}
+}
+
+func TestBackUpUsesOverrideBlob(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	t1, _ := testTargets(t, d, root)
+
+	// the file that is replaced has the content of an override
+	_, err := d.DB.Exec(`INSERT INTO profiles (id, game_install_id, name) VALUES (1, 1, 'default')`)
+	require.NoError(t, err)
+	sha := writeBlob(t, d, d.Blobs.OverridesDir, blobstore.KindOverride, "my settings")
+	_, err = d.DB.Exec(`INSERT INTO overrides (profile_id, target_id, relpath, blob_sha256)
+		VALUES (1, 1, 'other.ini', ?)`, sha)
+	require.NoError(t, err)
+
+	res := deployOver(t, d, gi, t1, "settings.ini", "my settings", "modded")
+	assert.Equal(t, 1, res.BackedUp)
+
+	backups, err := d.Q.ListBackupsForGame(ctx, gi.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, sha, backups[0].BackupBlobSha256)
+
+	blob, err := d.Q.GetBlob(ctx, sha)
+	require.NoError(t, err)
+	assert.Equal(t, string(blobstore.KindOverride), blob.Kind)
+	assert.Empty(t, storedBlobs(t, d.Blobs.BackupsDir))
+
+	// unapply restores it from the override's blob, which the override
+	// still needs
+	unapplied, err := d.Unapply(ctx, gi)
+	require.NoError(t, err)
+	assert.Equal(t, 1, unapplied.Restored)
+	got, err := os.ReadFile(filepath.Join(root, "settings.ini"))
+	require.NoError(t, err)
+	assert.Equal(t, "my settings", string(got))
+	assert.Equal(t, []string{sha}, storedBlobs(t, d.Blobs.OverridesDir))
+}
+
+func TestBackUpReingestsQuarantinedBlob(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	t1, _ := testTargets(t, d, root)
+
+	// an override whose blob was found corrupted and quarantined
+	_, err := d.DB.Exec(`INSERT INTO profiles (id, game_install_id, name) VALUES (1, 1, 'default')`)
+	require.NoError(t, err)
+	sha := writeBlob(t, d, d.Blobs.OverridesDir, blobstore.KindOverride, "my settings")
+	_, err = d.DB.Exec(`INSERT INTO overrides (profile_id, target_id, relpath, blob_sha256)
+		VALUES (1, 1, 'other.ini', ?)`, sha)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join(d.Blobs.OverridesDir, sha[:2], sha)))
+	_, err = d.DB.Exec(`UPDATE blobs SET corrupted_at = '2026-01-01T00:00:00.000Z' WHERE sha256 = ?`, sha)
+	require.NoError(t, err)
+
+	deployOver(t, d, gi, t1, "settings.ini", "my settings", "modded")
+
+	got, err := os.ReadFile(filepath.Join(d.Blobs.OverridesDir, sha[:2], sha))
+	require.NoError(t, err)
+	assert.Equal(t, "my settings", string(got))
+	assert.Empty(t, storedBlobs(t, d.Blobs.BackupsDir))
+
+	blob, err := d.Q.GetBlob(ctx, sha)
+	require.NoError(t, err)
+	assert.Equal(t, string(blobstore.KindOverride), blob.Kind)
+	assert.False(t, blob.CorruptedAt.Valid)
+}
+
+func TestApplySymlinkTarget(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	_, err := d.DB.Exec(`UPDATE targets SET deploy_method = 'symlink' WHERE id = 1;
+		INSERT INTO profiles (id, game_install_id, name) VALUES (1, 1, 'default')`)
+	require.NoError(t, err)
+
+	// a template override is expanded for the install, so it's linked from
+	// the extracted store
+	plain := writeBlob(t, d, d.Blobs.OverridesDir, blobstore.KindOverride, "my settings")
+	tmpl := writeBlob(t, d, d.Blobs.OverridesDir, blobstore.KindOverride, "my template")
+	_, err = d.DB.Exec(`INSERT INTO overrides (profile_id, target_id, relpath, blob_sha256, is_template)
+		VALUES (1, 1, 'a.ini', ?, 0), (1, 1, 'b.ini', ?, 1)`, plain, tmpl)
+	require.NoError(t, err)
+	p, err := d.Q.GetProfileByID(ctx, 1)
+	require.NoError(t, err)
+
+	res, err := d.Apply(ctx, gi, p, &plan.Plan{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Written)
+
+	link, err := os.Readlink(filepath.Join(root, "a.ini"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(d.Blobs.OverridesDir, plain[:2], plain), link)
+	link, err = os.Readlink(filepath.Join(root, "b.ini"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(d.Blobs.ExtractedDir, tmpl[:2], tmpl), link)
+
+	// unapply removes the links and what only they pointed to
+	gi, err = d.Q.GetGameInstallByID(ctx, 1)
+	require.NoError(t, err)
+	unapplied, err := d.Unapply(ctx, gi)
+	require.NoError(t, err)
+	assert.Equal(t, 2, unapplied.Removed)
+	assert.NoFileExists(t, filepath.Join(root, "a.ini"))
+	assert.NoFileExists(t, filepath.Join(root, "b.ini"))
+	assert.Empty(t, storedBlobs(t, d.Blobs.ExtractedDir))
+	assert.ElementsMatch(t, []string{plain, tmpl}, storedBlobs(t, d.Blobs.OverridesDir))
+}
+
+func TestApplyOverlayTarget(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	_, err := d.DB.Exec(`INSERT INTO profiles (id, game_install_id, name) VALUES (1, 1, 'default')`)
+	require.NoError(t, err)
+	target, err := d.Q.GetTargetByName(ctx, dbq.GetTargetByNameParams{GameInstallID: 1, Name: "game_dir"})
+	require.NoError(t, err)
+
+	// nothing is mounted for real
+	dir := t.TempDir()
+	mounts := filepath.Join(dir, "mounts")
+	viper.Set("overlays_dir", filepath.Join(dir, "overlays"))
+	viper.Set("overlay_mount_command", []string{"sh", "-c", `echo "$1 $2" >> "$0"`, mounts, "${files}", "${root}"})
+	t.Cleanup(func() {
+		viper.Set("overlays_dir", nil)
+		viper.Set("overlay_mount_command", nil)
+	})
+
+	require.NoError(t, CheckTargetMethodChange(ctx, d.Q, target, DeployMethodOverlay))
+	require.NoError(t, d.Q.SetTargetStrategy(ctx, dbq.SetTargetStrategyParams{
+		DeployMethod: DeployMethodOverlay,
+		BackupPolicy: BackupPolicyBackup,
+		ID:           target.ID,
+	}))
+	target.DeployMethod = DeployMethodOverlay
+
+	// the file in the target itself isn't replaced (or backed up)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.ini"), []byte("the original file"), 0o644))
+	sha := writeBlob(t, d, d.Blobs.OverridesDir, blobstore.KindOverride, "my settings")
+	_, err = d.DB.Exec(`INSERT INTO overrides (profile_id, target_id, relpath, blob_sha256)
+		VALUES (1, 1, 'a.ini', ?)`, sha)
+	require.NoError(t, err)
+	p, err := d.Q.GetProfileByID(ctx, 1)
+	require.NoError(t, err)
+
+	res, err := d.Apply(ctx, gi, p, &plan.Plan{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Written)
+	assert.Zero(t, res.BackedUp)
+
+	files := filepath.Join(OverlayDir(target), "files")
+	got, err := os.ReadFile(filepath.Join(files, "a.ini"))
+	require.NoError(t, err)
+	assert.Equal(t, "my settings", string(got))
+	got, err = os.ReadFile(filepath.Join(root, "a.ini"))
+	require.NoError(t, err)
+	assert.Equal(t, "the original file", string(got))
+	got, err = os.ReadFile(mounts)
+	require.NoError(t, err)
+	assert.Equal(t, files+" "+root+"\n", string(got))
+
+	// it has files in its layer now
+	assert.ErrorContains(t, CheckTargetMethodChange(ctx, d.Q, target, DeployMethodCopy), "has 1 deployed file")
+
+	gi, err = d.Q.GetGameInstallByID(ctx, 1)
+	require.NoError(t, err)
+	unapplied, err := d.Unapply(ctx, gi)
+	require.NoError(t, err)
+	assert.Equal(t, 1, unapplied.Removed)
+	assert.NoFileExists(t, filepath.Join(files, "a.ini"))
+	assert.FileExists(t, filepath.Join(root, "a.ini"))
+	assert.NoError(t, CheckTargetMethodChange(ctx, d.Q, target, DeployMethodCopy))
+}
+
+func TestDrift(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	archive := writeBlob(t, d, d.Blobs.ArchivesDir, blobstore.KindArchive, "the archive")
+	installModFile(t, d, root, "Data/a.esp", "content of a.esp", archive)
+	installModFile(t, d, root, "Data/b.esp", "content of b.esp", archive)
+	installModFile(t, d, root, "Data/c.esp", "content of c.esp", archive)
+
+	drift, _, err := d.Drift(ctx, gi)
+	require.NoError(t, err)
+	assert.Nil(t, drift)
+
+	// modified files drift, missing ones don't
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Data", "a.esp"), []byte("changed"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(root, "Data", "c.esp")))
+	drift, _, err = d.Drift(ctx, gi)
+	require.NoError(t, err)
+	require.NotNil(t, drift)
+	assert.Equal(t, []string{"game_dir/Data/a.esp"}, drift.Paths)
+	assert.Empty(t, drift.Vanilla)
+}
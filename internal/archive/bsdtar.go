@@ -0,0 +1,240 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package archive inspects mod archives using bsdtar.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/perf"
+)
+
+// List returns the names of the entries in the archive at path.
+func List(ctx context.Context, bsdtar, path string) ([]string, error) {
+	defer perf.Track(perf.Commands)()
+
+	cmd := exec.CommandContext(ctx, bsdtar, "-t", "-f", path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start bsdtar: %w", err)
+	}
+
+	var names []string
+	sc := bufio.NewScanner(out)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if name := sc.Text(); name != "" {
+			names = append(names, name)
+		}
+	}
+	scanErr := sc.Err()
+
+	if err := cmd.Wait(); err != nil {
+		return nil, bsdtarError("-t", err, stderr.String())
+	}
+	if scanErr != nil {
+		return nil, fmt.Errorf("read bsdtar output: %w", scanErr)
+	}
+
+	return names, nil
+}
+
+// verboseLine is an entry of bsdtar's verbose listing: mode, links, owner,
+// group, size, date (three fields), and name.
+var verboseLine = regexp.MustCompile(`^(\S+)\s+\d+\s+\S+\s+\S+\s+(\d+)\s+\S+\s+\d+\s+\S+ (.+)$`)
+
+// Sizes returns the (uncompressed) size of every regular file in the archive
+// at path.
+func Sizes(ctx context.Context, bsdtar, path string) (map[string]int64, error) {
+	defer perf.Track(perf.Commands)()
+
+	cmd := exec.CommandContext(ctx, bsdtar, "-t", "-v", "-f", path)
+	// the dates are localized
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, bsdtarError("-t", err, stderr.String())
+	}
+
+	return parseVerboseListing(string(out))
+}
+
+func parseVerboseListing(out string) (map[string]int64, error) {
+	sizes := map[string]int64{}
+	for line := range strings.Lines(out) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			continue
+		}
+
+		m := verboseLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("unexpected bsdtar listing: %q", line)
+		}
+		if !strings.HasPrefix(m[1], "-") {
+			// directories, symlinks, and hardlinks (" link to ...") don't
+			// take up space of their own
+			continue
+		}
+
+		n, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected bsdtar listing: %q", line)
+		}
+		sizes[m[3]] = n
+	}
+
+	return sizes, nil
+}
+
+// ListWithLinks is like List but it also returns the targets of the symlinks
+// in the archive (keyed by their name, which is in the list as well).
+func ListWithLinks(ctx context.Context, bsdtar, path string) ([]string, map[string]string, error) {
+	defer perf.Track(perf.Commands)()
+
+	cmd := exec.CommandContext(ctx, bsdtar, "-t", "-v", "-f", path)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, bsdtarError("-t", err, stderr.String())
+	}
+
+	return parseVerboseNames(string(out))
+}
+
+func parseVerboseNames(out string) ([]string, map[string]string, error) {
+	var names []string
+	links := map[string]string{}
+	for line := range strings.Lines(out) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			continue
+		}
+
+		m := verboseLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, nil, fmt.Errorf("unexpected bsdtar listing: %q", line)
+		}
+
+		name := m[3]
+		switch m[1][0] {
+		case 'l':
+			// a name with " -> " in it is ambiguous: assume that the
+			// first one is the separator
+			var target string
+			name, target, _ = strings.Cut(name, " -> ")
+			links[name] = target
+		case 'h':
+			// hardlinks are extracted as regular files
+			name, _, _ = strings.Cut(name, " link to ")
+		}
+		names = append(names, name)
+	}
+
+	return names, links, nil
+}
+
+// ReadMember returns (up to max bytes of) the contents of a single entry of
+// the archive at path. The second return value reports whether the entry was
+// truncated.
+func ReadMember(ctx context.Context, bsdtar, path, member string, max int64) ([]byte, bool, error) {
+	defer perf.Track(perf.Extraction)()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// -q stops after the first match, -O extracts to stdout
+	cmd := exec.CommandContext(ctx, bsdtar, "-x", "-q", "-O", "-f", path, member)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, false, fmt.Errorf("start bsdtar: %w", err)
+	}
+
+	b, readErr := io.ReadAll(io.LimitReader(out, max+1))
+	truncated := int64(len(b)) > max
+	if truncated {
+		b = b[:max]
+		// we don't need the rest: stop bsdtar instead of draining it
+		cancel()
+		_ = cmd.Wait()
+		return b, true, nil
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, false, bsdtarError("-x", err, stderr.String())
+	}
+	if readErr != nil {
+		return nil, false, fmt.Errorf("read bsdtar output: %w", readErr)
+	}
+
+	return b, false, nil
+}
+
+// Extract extracts the archive at path into dir (which must exist). Owners
+// and permissions from the archive are not restored and bsdtar's default
+// safety checks (no absolute paths, no "..", no extracting through
+// symlinks) stay on.
+func Extract(ctx context.Context, bsdtar, path, dir string) error {
+	defer perf.Track(perf.Extraction)()
+
+	cmd := exec.CommandContext(ctx, bsdtar, "-x", "-o", "--no-same-permissions",
+		"-f", path, "-C", dir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return bsdtarError("-x", err, stderr.String())
+	}
+
+	return nil
+}
+
+func bsdtarError(mode string, err error, stderr string) error {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("bsdtar %s failed: %s", mode, msg)
+	}
+	return fmt.Errorf("bsdtar %s failed: %w", mode, err)
+}
@@ -0,0 +1,69 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVerboseListing(t *testing.T) {
+	t.Parallel()
+
+	out := `drwxr-xr-x  0 0      0           0 Jan  1  2020 Data/
+-rw-r--r--  0 1000   1000     5000 Mar 14 09:26 Data/My Mod.esp
+-rw-r--r--  0 mario  users   12345 Jan  1  2020 Data/textures/a b.dds
+lrwxrwxrwx  0 mario  users       0 Jan  1  2020 Data/link -> My Mod.esp
+hrw-r--r--  0 mario  users       0 Jan  1  2020 Data/hard.esp link to Data/My Mod.esp
+`
+	sizes, err := parseVerboseListing(out)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		"Data/My Mod.esp":       5000,
+		"Data/textures/a b.dds": 12345,
+	}, sizes)
+
+	_, err = parseVerboseListing("Data/My Mod.esp\n")
+	assert.Error(t, err)
+}
+
+func TestParseVerboseNames(t *testing.T) {
+	t.Parallel()
+
+	out := `drwxr-xr-x  0 0      0           0 Jan  1  2020 Data/
+-rw-r--r--  0 1000   1000     5000 Mar 14 09:26 Data/My Mod.esp
+lrwxrwxrwx  0 mario  users       0 Jan  1  2020 Data/link -> My Mod.esp
+lrwxrwxrwx  0 mario  users       0 Jan  1  2020 Data/abs -> /etc/passwd
+hrw-r--r--  0 mario  users       0 Jan  1  2020 Data/hard.esp link to Data/My Mod.esp
+`
+	names, links, err := parseVerboseNames(out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Data/", "Data/My Mod.esp", "Data/link", "Data/abs", "Data/hard.esp",
+	}, names)
+	assert.Equal(t, map[string]string{
+		"Data/link": "My Mod.esp",
+		"Data/abs":  "/etc/passwd",
+	}, links)
+
+	_, _, err = parseVerboseNames("Data/My Mod.esp\n")
+	assert.Error(t, err)
+}
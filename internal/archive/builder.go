@@ -0,0 +1,350 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/perf"
+)
+
+// DefaultEpoch is the modification time of the entries of built archives
+// unless SOURCE_DATE_EPOCH says otherwise.
+var DefaultEpoch = time.Unix(0, 0).UTC()
+
+// Compression is how built archives are compressed.
+type Compression struct {
+	Format string // "zstd", "xz", or "gzip"
+	Level  int    // 0 uses the default level of the format
+}
+
+// DefaultCompression is used when nothing is configured.
+var DefaultCompression = Compression{Format: "zstd", Level: 19}
+
+// ParseCompression parses a compression setting like "zstd", "zstd:19", or
+// "xz:9".
+func ParseCompression(s string) (Compression, error) {
+	format, level, hasLevel := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ":")
+
+	c := Compression{Format: format}
+	var max int
+	switch format {
+	case "zstd":
+		max = 22
+	case "xz", "gzip":
+		max = 9
+	default:
+		return c, fmt.Errorf("unsupported compression %q (expected zstd, xz, or gzip)", format)
+	}
+
+	if hasLevel {
+		n, err := strconv.Atoi(level)
+		if err != nil || n < 1 || n > max {
+			return c, fmt.Errorf("invalid %s compression level %q (expected 1-%d)", format, level, max)
+		}
+		c.Level = n
+	}
+
+	return c, nil
+}
+
+// Extension returns the file extension of archives with this compression
+// (e.g., ".tar.zst").
+func (c Compression) Extension() string {
+	switch c.Format {
+	case "xz":
+		return ".tar.xz"
+	case "gzip":
+		return ".tar.gz"
+	default:
+		return ".tar.zst"
+	}
+}
+
+// CompressionForName returns the compression matching the extension of
+// name (at the default level of the format), if it has a known one.
+func CompressionForName(name string) (Compression, bool) {
+	lower := strings.ToLower(name)
+	for _, format := range []string{"zstd", "xz", "gzip"} {
+		c := Compression{Format: format}
+		if strings.HasSuffix(lower, c.Extension()) {
+			return c, true
+		}
+	}
+	if strings.HasSuffix(lower, ".tgz") {
+		return Compression{Format: "gzip"}, true
+	}
+	return Compression{}, false
+}
+
+func (c Compression) bsdtarArgs() []string {
+	var flag string
+	var opts []string
+	switch c.Format {
+	case "xz":
+		flag = "--xz"
+	case "gzip":
+		// the gzip header has a timestamp too
+		flag = "-z"
+		opts = append(opts, "gzip:!timestamp")
+	default:
+		flag = "--zstd"
+	}
+	if c.Level > 0 {
+		opts = append(opts, fmt.Sprintf("%s:compression-level=%d", c.Format, c.Level))
+	}
+
+	args := []string{flag}
+	if len(opts) > 0 {
+		args = append(args, "--options", strings.Join(opts, ","))
+	}
+	return args
+}
+
+// SourceDateEpoch returns the time from the SOURCE_DATE_EPOCH environment
+// variable (see https://reproducible-builds.org/specs/source-date-epoch/)
+// or DefaultEpoch if it isn't set.
+func SourceDateEpoch() (time.Time, error) {
+	v := strings.TrimSpace(os.Getenv("SOURCE_DATE_EPOCH"))
+	if v == "" {
+		return DefaultEpoch, nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q", v)
+	}
+	return time.Unix(n, 0).UTC(), nil
+}
+
+// Entry is a file or directory to add to an archive.
+type Entry struct {
+	Name string // path inside of the archive (slash separated)
+	Path string // file on disk; empty for directories
+}
+
+// Builder writes reproducible archives: entries are sorted by name, owned
+// by root, have the same modification time, and only keep whether they are
+// executable, so building an archive from the same content always produces
+// the same archive (and so the same sha256).
+//
+// The tar is written here and compressed by bsdtar, which re-writes it
+// as-is.
+type Builder struct {
+	Bsdtar      string
+	Compression Compression
+	Epoch       time.Time
+}
+
+// Build writes an archive of the given entries to out.
+func (b Builder) Build(ctx context.Context, out string, entries []Entry) error {
+	tmp, err := os.CreateTemp(filepath.Dir(out), ".modctl-archive-*")
+	if err != nil {
+		return fmt.Errorf("create temp archive: %w", err)
+	}
+	tmpName := tmp.Name()
+	_ = tmp.Close()
+	defer os.Remove(tmpName) // no-op after the rename
+
+	args := append([]string{"-c"}, b.Compression.bsdtarArgs()...)
+	args = append(args, "-f", tmpName, "@-")
+
+	defer perf.Track(perf.Commands)()
+
+	pr, pw := io.Pipe()
+	cmd := exec.CommandContext(ctx, b.Bsdtar, args...)
+	var stderr bytes.Buffer
+	cmd.Stdin = pr
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start bsdtar: %w", err)
+	}
+
+	werr := WriteTar(ctx, pw, entries, b.Epoch)
+	_ = pw.CloseWithError(werr)
+
+	if err := cmd.Wait(); err != nil {
+		if werr != nil {
+			return werr
+		}
+		return bsdtarError("-c", err, stderr.String())
+	}
+	if werr != nil {
+		return werr
+	}
+
+	if err := os.Rename(tmpName, out); err != nil {
+		return fmt.Errorf("rename into place: %w", err)
+	}
+	return nil
+}
+
+// PackDir writes an archive of the contents of dir to out.
+func (b Builder) PackDir(ctx context.Context, dir, out string) error {
+	entries, err := DirEntries(dir)
+	if err != nil {
+		return err
+	}
+	return b.Build(ctx, out, entries)
+}
+
+// DirEntries returns the files and directories below dir as archive
+// entries. Symlinks and other special files are an error.
+func DirEntries(dir string) ([]Entry, error) {
+	var entries []Entry
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			entries = append(entries, Entry{Name: filepath.ToSlash(rel)})
+		case d.Type().IsRegular():
+			entries = append(entries, Entry{Name: filepath.ToSlash(rel), Path: p})
+		default:
+			return fmt.Errorf("%s: only regular files and directories can be archived", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", dir, err)
+	}
+
+	return entries, nil
+}
+
+// WriteTar writes a reproducible (see Builder) tar of the given entries to
+// w. Directories that entries are in don't have to be listed.
+func WriteTar(ctx context.Context, w io.Writer, entries []Entry, epoch time.Time) error {
+	// every parent directory gets an entry of its own
+	dirs := map[string]bool{}
+	files := map[string]string{}
+	for _, e := range entries {
+		name := strings.Trim(path.Clean(e.Name), "/")
+		if name == "" || name == "." || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("invalid archive entry name %q", e.Name)
+		}
+
+		if e.Path == "" {
+			dirs[name] = true
+		} else {
+			if _, ok := files[name]; ok {
+				return fmt.Errorf("duplicate archive entry %q", name)
+			}
+			files[name] = e.Path
+		}
+		for d := path.Dir(name); d != "."; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+
+	names := make([]string, 0, len(dirs)+len(files))
+	for d := range dirs {
+		if _, ok := files[d]; ok {
+			return fmt.Errorf("archive entry %q is both a file and a directory", d)
+		}
+		names = append(names, d)
+	}
+	for f := range files {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	tw := tar.NewWriter(w)
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		hdr := &tar.Header{
+			Name:    name,
+			ModTime: epoch,
+			Uname:   "root",
+			Gname:   "root",
+		}
+
+		src, isFile := files[name]
+		if !isFile {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+			hdr.Mode = 0o755
+			if err := tw.WriteHeader(hdr); err != nil {
+				return fmt.Errorf("write tar header: %w", err)
+			}
+			continue
+		}
+
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			f.Close()
+			return fmt.Errorf("%s: only regular files can be archived", src)
+		}
+
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = info.Size()
+		hdr.Mode = 0o644
+		if info.Mode().Perm()&0o111 != 0 {
+			hdr.Mode = 0o755
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			f.Close()
+			return fmt.Errorf("write tar header: %w", err)
+		}
+		_, err = io.Copy(tw, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("write %s: %w", src, err)
+		}
+	}
+
+	return tw.Close()
+}
@@ -0,0 +1,187 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTar(t *testing.T) {
+	t.Parallel()
+
+	mkTree := func(mtime time.Time) string {
+		dir := t.TempDir()
+		files := map[string]os.FileMode{
+			"b/readme.txt":   0o600,
+			"a/tool.sh":      0o700,
+			"a/z/plugin.esp": 0o664,
+		}
+		for name, mode := range files {
+			p := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+			require.NoError(t, os.WriteFile(p, []byte(name), mode))
+			require.NoError(t, os.Chtimes(p, mtime, mtime))
+		}
+		return dir
+	}
+
+	writeTree := func(w io.Writer, dir string) error {
+		entries, err := DirEntries(dir)
+		if err != nil {
+			return err
+		}
+		return WriteTar(context.Background(), w, entries, DefaultEpoch)
+	}
+
+	var first, second bytes.Buffer
+	require.NoError(t, writeTree(&first, mkTree(time.Now())))
+	require.NoError(t, writeTree(&second, mkTree(time.Now().Add(-time.Hour))))
+	assert.Equal(t, first.Bytes(), second.Bytes())
+
+	var names []string
+	modes := map[string]int64{}
+	tr := tar.NewReader(&first)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		modes[hdr.Name] = hdr.Mode
+		assert.True(t, hdr.ModTime.Equal(DefaultEpoch))
+		assert.Equal(t, "root", hdr.Uname)
+	}
+
+	assert.Equal(t, []string{"a/", "a/tool.sh", "a/z/", "a/z/plugin.esp", "b/", "b/readme.txt"}, names)
+	assert.Equal(t, int64(0o755), modes["a/tool.sh"])
+	assert.Equal(t, int64(0o644), modes["b/readme.txt"])
+}
+
+func TestWriteTarEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(src, []byte("a"), 0o644))
+
+	epoch := time.Unix(1700000000, 0)
+	var buf bytes.Buffer
+	require.NoError(t, WriteTar(context.Background(), &buf, []Entry{
+		{Name: "x/y/renamed.txt", Path: src},
+		{Name: "empty"},
+	}, epoch))
+
+	var names []string
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		assert.True(t, hdr.ModTime.Equal(epoch))
+	}
+	assert.Equal(t, []string{"empty/", "x/", "x/y/", "x/y/renamed.txt"}, names)
+
+	err := WriteTar(context.Background(), io.Discard, []Entry{{Name: "../evil", Path: src}}, epoch)
+	assert.Error(t, err)
+
+	err = WriteTar(context.Background(), io.Discard, []Entry{{Name: "a", Path: src}, {Name: "a/b", Path: src}}, epoch)
+	assert.Error(t, err)
+}
+
+func TestDirEntriesSymlink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(dir, "b.txt")))
+
+	_, err := DirEntries(dir)
+	assert.Error(t, err)
+}
+
+func TestParseCompression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    Compression
+		wantErr bool
+	}{
+		{"zstd", Compression{Format: "zstd"}, false},
+		{"zstd:19", Compression{Format: "zstd", Level: 19}, false},
+		{"XZ:6", Compression{Format: "xz", Level: 6}, false},
+		{"gzip:9", Compression{Format: "gzip", Level: 9}, false},
+		{"xz:10", Compression{}, true},
+		{"zstd:fast", Compression{}, true},
+		{"bzip2", Compression{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCompression(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompressionArgs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"--zstd", "--options", "zstd:compression-level=19"},
+		Compression{Format: "zstd", Level: 19}.bsdtarArgs())
+	assert.Equal(t, []string{"-z", "--options", "gzip:!timestamp"},
+		Compression{Format: "gzip"}.bsdtarArgs())
+	assert.Equal(t, ".tar.xz", Compression{Format: "xz"}.Extension())
+}
+
+func TestCompressionForName(t *testing.T) {
+	t.Parallel()
+
+	c, ok := CompressionForName("backups.TAR.XZ")
+	assert.True(t, ok)
+	assert.Equal(t, Compression{Format: "xz"}, c)
+
+	c, ok = CompressionForName("backups.tgz")
+	assert.True(t, ok)
+	assert.Equal(t, Compression{Format: "gzip"}, c)
+
+	_, ok = CompressionForName("backups.zip")
+	assert.False(t, ok)
+}
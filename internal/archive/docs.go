@@ -0,0 +1,169 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DocKind is the kind of documentation file found in an archive.
+type DocKind string
+
+const (
+	DocReadme    DocKind = "readme"
+	DocChangelog DocKind = "changelog"
+	DocLicense   DocKind = "license"
+)
+
+// Doc is a documentation file extracted from an archive.
+type Doc struct {
+	Path      string  `json:"path"`
+	Kind      DocKind `json:"kind"`
+	Text      string  `json:"text"`
+	Truncated bool    `json:"truncated,omitempty"`
+}
+
+const (
+	// MaxDocs is the most documentation files kept per archive.
+	MaxDocs = 10
+	// MaxDocBytes is the most text kept per documentation file.
+	MaxDocBytes = 256 * 1024
+)
+
+var docPrefixes = []struct {
+	prefix string
+	kind   DocKind
+}{
+	{"readme", DocReadme},
+	{"read me", DocReadme},
+	{"read_me", DocReadme},
+	{"changelog", DocChangelog},
+	{"change log", DocChangelog},
+	{"changes", DocChangelog},
+	{"history", DocChangelog},
+	{"license", DocLicense},
+	{"licence", DocLicense},
+	{"copying", DocLicense},
+}
+
+var docExtensions = map[string]bool{
+	"":          true,
+	".txt":      true,
+	".md":       true,
+	".markdown": true,
+	".rst":      true,
+	".nfo":      true,
+}
+
+// ClassifyDoc reports whether an archive entry looks like a readme,
+// changelog, or license (by its file name) that we can show as text.
+func ClassifyDoc(name string) (DocKind, bool) {
+	if strings.HasSuffix(name, "/") {
+		return "", false // directory
+	}
+
+	base := strings.ToLower(path.Base(strings.ReplaceAll(name, `\`, "/")))
+	ext := path.Ext(base)
+	if !docExtensions[ext] {
+		return "", false
+	}
+	stem := strings.TrimSuffix(base, ext)
+
+	for _, p := range docPrefixes {
+		if stem == p.prefix || strings.HasPrefix(stem, p.prefix+"-") ||
+			strings.HasPrefix(stem, p.prefix+"_") ||
+			strings.HasPrefix(stem, p.prefix+" ") ||
+			strings.HasPrefix(stem, p.prefix+".") {
+			return p.kind, true
+		}
+	}
+
+	return "", false
+}
+
+// FindDocs extracts the readme, changelog, and license files from the archive
+// at path. Shallower files come first so that the top-level readme is the
+// first one shown.
+func FindDocs(ctx context.Context, bsdtar, archivePath string) ([]Doc, error) {
+	names, err := List(ctx, bsdtar, archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []Doc
+	for _, n := range names {
+		if kind, ok := ClassifyDoc(n); ok {
+			docs = append(docs, Doc{Path: n, Kind: kind})
+		}
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		di := strings.Count(strings.TrimPrefix(docs[i].Path, "./"), "/")
+		dj := strings.Count(strings.TrimPrefix(docs[j].Path, "./"), "/")
+		if di != dj {
+			return di < dj
+		}
+		return docs[i].Path < docs[j].Path
+	})
+	if len(docs) > MaxDocs {
+		docs = docs[:MaxDocs]
+	}
+
+	out := docs[:0]
+	for _, d := range docs {
+		b, truncated, err := ReadMember(ctx, bsdtar, archivePath, d.Path, MaxDocBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		text, ok := DocText(b)
+		if !ok {
+			continue // binary file with a doc-like name
+		}
+		d.Text = text
+		d.Truncated = truncated
+		out = append(out, d)
+	}
+
+	return out, nil
+}
+
+// DocText normalizes the contents of a text file for display (drops a BOM,
+// converts line endings, and replaces invalid UTF-8). It returns false if the
+// contents look binary.
+func DocText(b []byte) (string, bool) {
+	if bytes.IndexByte(b, 0) >= 0 {
+		return "", false
+	}
+
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	s := strings.ReplaceAll(string(b), "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	if !utf8.ValidString(s) {
+		// most likely windows-1252/latin-1; keep what we can
+		s = strings.ToValidUTF8(s, "�")
+	}
+
+	return strings.TrimRight(s, "\n\t ") + "\n", true
+}
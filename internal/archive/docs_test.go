@@ -0,0 +1,93 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyDoc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		wantKind DocKind
+		wantOK   bool
+	}{
+		{"README.txt", DocReadme, true},
+		{"readme", DocReadme, true},
+		{"Data/Docs/ReadMe - Installation.md", DocReadme, true},
+		{"Read Me.txt", DocReadme, true},
+		{"readme_fr.txt", DocReadme, true},
+		{`Docs\README.md`, DocReadme, true},
+		{"CHANGELOG.md", DocChangelog, true},
+		{"changes.txt", DocChangelog, true},
+		{"LICENSE", DocLicense, true},
+		{"licence.txt", DocLicense, true},
+		{"COPYING", DocLicense, true},
+		{"readme.pdf", "", false},
+		{"readme/", "", false},
+		{"readme/image.png", "", false},
+		{"readmeplease.txt", "", false},
+		{"textures/readme.dds", "", false},
+		{"historyofskyrim.esp", "", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			kind, ok := ClassifyDoc(tt.input)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantKind, kind)
+		})
+	}
+}
+
+func TestDocText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  []byte
+		want   string
+		wantOK bool
+	}{
+		{"plain", []byte("hello\nworld\n"), "hello\nworld\n", true},
+		{"crlf and bom", []byte("\xef\xbb\xbfhello\r\nworld\r\n\r\n"), "hello\nworld\n", true},
+		{"latin-1", []byte("caf\xe9"), "caf�\n", true},
+		{"binary", []byte("MZ\x00\x01"), "", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := DocText(tt.input)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
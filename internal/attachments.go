@@ -0,0 +1,152 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/deploy"
+)
+
+// AttachFile stores the file at srcPath in the blob store and attaches it to
+// a mod page under label. Archives (isArchive) are stored as archive blobs
+// and everything else as override blobs, unless the store already has the
+// file in which case the existing blob is reused whatever its kind.
+func AttachFile(
+	ctx context.Context,
+	db *sql.DB,
+	q *dbq.Queries,
+	bs blobstore.Store,
+	pageID int64,
+	label, srcPath, notes string,
+	isArchive bool,
+) (string, error) {
+	if _, err := q.GetModAttachmentByLabel(ctx, dbq.GetModAttachmentByLabelParams{
+		ModPageID: pageID,
+		Label:     label,
+	}); err == nil {
+		return "", fmt.Errorf("mod already has an attachment labeled %q", label)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("get attachment: %w", err)
+	}
+
+	kind := blobstore.KindOverride
+	if isArchive {
+		kind = blobstore.KindArchive
+	}
+
+	sha, _, err := deploy.HashFile(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("hash %s: %w", srcPath, err)
+	}
+	if b, err := q.GetBlob(ctx, sha); err == nil {
+		kind = blobstore.Kind(b.Kind)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("get blob: %w", err)
+	}
+
+	// filesystem first: an unreferenced blob is harmless
+	res, err := bs.IngestFile(ctx, kind, srcPath)
+	if err != nil {
+		return "", fmt.Errorf("ingest attachment: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	base := filepath.Base(srcPath)
+	if err := blobstore.EnsureBlobRecorded(ctx, qtx, res.SHA256Hex,
+		string(kind), res.SizeBytes, &base); err != nil {
+		return "", err
+	}
+
+	if _, err := qtx.CreateModAttachment(ctx, dbq.CreateModAttachmentParams{
+		ModPageID:    pageID,
+		Label:        label,
+		BlobSha256:   res.SHA256Hex,
+		OriginalName: base,
+		Notes:        sql.NullString{String: notes, Valid: notes != ""},
+	}); err != nil {
+		return "", fmt.Errorf("create attachment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit: %w", err)
+	}
+
+	return res.SHA256Hex, nil
+}
+
+// DetachFile removes an attachment from its mod page. Its blob is deleted
+// too unless something else (another attachment, an archive version, an
+// override or a backup) still references it; the returned bool reports
+// whether it was.
+func DetachFile(
+	ctx context.Context,
+	db *sql.DB,
+	q *dbq.Queries,
+	bs blobstore.Store,
+	a dbq.GetModAttachmentByLabelRow,
+) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	if err := qtx.DeleteModAttachment(ctx, a.ID); err != nil {
+		return false, fmt.Errorf("delete attachment: %w", err)
+	}
+
+	refs, err := qtx.CountBlobReferences(ctx, a.BlobSha256)
+	if err != nil {
+		return false, fmt.Errorf("count blob references: %w", err)
+	}
+	if refs == 0 {
+		if err := qtx.DeleteBlob(ctx, a.BlobSha256); err != nil {
+			return false, fmt.Errorf("delete blob: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+
+	if refs > 0 {
+		return false, nil
+	}
+
+	// filesystem last: if this fails the file is just unreferenced
+	if err := bs.Remove(blobstore.Kind(a.Kind), a.BlobSha256); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
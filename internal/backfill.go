@@ -0,0 +1,232 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/mfinelli/modctl/internal/offline"
+)
+
+type BackfillStatus string
+
+const (
+	BackfillStatusFilled    BackfillStatus = "filled"
+	BackfillStatusUnchanged BackfillStatus = "unchanged" // nexus doesn't know more either
+	BackfillStatusNotFound  BackfillStatus = "not_found" // the file is gone from nexus
+	BackfillStatusUnmatched BackfillStatus = "unmatched" // no nexus file has the archive's filename
+	BackfillStatusDeferred  BackfillStatus = "deferred"  // out of api requests
+	BackfillStatusError     BackfillStatus = "error"
+)
+
+// NexusBackfill is what nexus knows about a mod file version that is missing
+// metadata. The version string, upload time, and notes are only set if they
+// are missing (or, for the version string, guessed from the filename) and
+// nexus has them.
+type NexusBackfill struct {
+	VersionID    int64
+	ModName      string
+	FileLabel    string
+	OriginalName string
+	GameDomain   string
+	NexusModID   int64
+	FileID       int64
+	// the version didn't have a file id: FileID was found by the filename
+	// of its archive (see nexus.MatchDownloadFile)
+	FileResolved bool
+
+	VersionString string
+	UploadedAt    string
+	UpstreamNotes string
+
+	Status BackfillStatus
+	Err    error
+}
+
+// PlanNexusBackfill looks up the nexus files of every mod file version of the
+// game install that is linked to nexus and is missing its file id, version
+// string, upload time, or upstream notes. The file id of a version that
+// doesn't have one (e.g., because it was imported from a downloaded archive)
+// is looked up by the filename of its archive. It only reads from the
+// database, use ApplyNexusBackfill to record the result.
+//
+// Every mod costs one request (two if notes are missing, to get its
+// changelogs). A failure to look up a single mod doesn't abort the whole
+// backfill: the error is recorded instead. Once the API rate limit is reached,
+// the remaining versions are marked as deferred.
+func PlanNexusBackfill(ctx context.Context, q *dbq.Queries, c *nexus.Client, gameInstallID int64) ([]NexusBackfill, error) {
+	rows, err := q.ListNexusVersionsToBackfill(ctx, gameInstallID)
+	if err != nil {
+		return nil, fmt.Errorf("list versions: %w", err)
+	}
+
+	out := make([]NexusBackfill, 0, len(rows))
+	var deferred error
+	for i := 0; i < len(rows); {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+
+		// the rows are ordered by mod, look every mod up once
+		domain, modID := rows[i].NexusGameDomain.String, rows[i].NexusModID.Int64
+		j := i
+		needNotes := false
+		for ; j < len(rows) && rows[j].NexusGameDomain.String == domain && rows[j].NexusModID.Int64 == modID; j++ {
+			if !rows[j].UpstreamNotes.Valid {
+				needNotes = true
+			}
+		}
+
+		var list nexus.FileList
+		var logs map[string][]string
+		err := deferred
+		if err == nil {
+			list, err = c.GetFileList(ctx, domain, modID)
+		}
+		if err == nil && needNotes {
+			logs, err = c.GetChangelogs(ctx, domain, modID)
+		}
+		var rlErr *nexus.RateLimitError
+		if errors.As(err, &rlErr) {
+			deferred = err
+		}
+
+		byID := map[int64]nexus.File{}
+		for _, f := range list.Files {
+			byID[f.FileID] = f
+		}
+
+		for _, r := range rows[i:j] {
+			b := NexusBackfill{
+				VersionID:    r.ID,
+				ModName:      r.ModName,
+				FileLabel:    r.FileLabel,
+				OriginalName: r.OriginalName.String,
+				GameDomain:   domain,
+				NexusModID:   modID,
+				FileID:       r.NexusFileID.Int64,
+			}
+
+			f, ok := byID[b.FileID]
+			if !r.NexusFileID.Valid {
+				f, ok = nexus.MatchDownloadFile(list.Files, b.OriginalName)
+				b.FileID, b.FileResolved = f.FileID, ok
+			}
+			switch {
+			case deferred != nil:
+				b.Status = BackfillStatusDeferred
+				b.Err = deferred
+			case errors.Is(err, offline.ErrOffline):
+				// only this mod has no cached responses
+				b.Status = BackfillStatusDeferred
+				b.Err = err
+			case err != nil:
+				b.Status = BackfillStatusError
+				b.Err = err
+			case !ok && !r.NexusFileID.Valid:
+				b.Status = BackfillStatusUnmatched
+			case !ok:
+				b.Status = BackfillStatusNotFound
+			default:
+				b.VersionString, b.UploadedAt, b.UpstreamNotes = nexusBackfillValues(
+					!r.VersionString.Valid || r.VersionSource == "filename",
+					!r.UploadedAt.Valid, !r.UpstreamNotes.Valid, f, logs)
+				b.Status = BackfillStatusUnchanged
+				if b.FileResolved || b.VersionString != "" || b.UploadedAt != "" || b.UpstreamNotes != "" {
+					b.Status = BackfillStatusFilled
+				}
+			}
+
+			out = append(out, b)
+		}
+
+		i = j
+	}
+
+	return out, nil
+}
+
+// ApplyNexusBackfill records what nexus knows about a mod file version.
+// Fields that aren't missing anymore are left alone.
+func ApplyNexusBackfill(ctx context.Context, q *dbq.Queries, b NexusBackfill) error {
+	if b.Status != BackfillStatusFilled {
+		return nil
+	}
+
+	nullable := func(s string) sql.NullString {
+		return sql.NullString{String: s, Valid: s != ""}
+	}
+
+	if err := q.BackfillModFileVersion(ctx, dbq.BackfillModFileVersionParams{
+		NexusFileID:   sql.NullInt64{Int64: b.FileID, Valid: b.FileResolved},
+		VersionString: nullable(b.VersionString),
+		UploadedAt:    nullable(b.UploadedAt),
+		UpstreamNotes: nullable(b.UpstreamNotes),
+		ID:            b.VersionID,
+	}); err != nil {
+		return fmt.Errorf("update version %d: %w", b.VersionID, err)
+	}
+
+	return nil
+}
+
+// nexusBackfillValues returns the values of the missing fields from the nexus
+// file (and the changelogs of its mod); "" for fields that aren't missing or
+// that nexus doesn't have either. The notes are the changelog of the version
+// of the file, one change per line.
+func nexusBackfillValues(needVersion, needUploadedAt, needNotes bool, f nexus.File, logs map[string][]string) (string, string, string) {
+	var version, uploadedAt, notes string
+
+	v := strings.TrimSpace(f.Version)
+	if needVersion {
+		version = v
+	}
+
+	if needUploadedAt && f.UploadedTimestamp > 0 {
+		uploadedAt = time.Unix(f.UploadedTimestamp, 0).UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+
+	if needNotes && v != "" {
+		changes, ok := logs[v]
+		if !ok {
+			for lv, c := range logs {
+				if nexus.CompareVersions(lv, v) == 0 {
+					changes = c
+					break
+				}
+			}
+		}
+		lines := make([]string, 0, len(changes))
+		for _, c := range changes {
+			if c = strings.TrimSpace(c); c != "" {
+				lines = append(lines, "- "+c)
+			}
+		}
+		notes = strings.Join(lines, "\n")
+	}
+
+	return version, uploadedAt, notes
+}
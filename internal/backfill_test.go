@@ -0,0 +1,81 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNexusBackfillValues(t *testing.T) {
+	t.Parallel()
+
+	f := nexus.File{FileID: 7, Version: " 1.2.0 ", UploadedTimestamp: 1700000000}
+	logs := map[string][]string{
+		"1.1":   {"fixed a crash"},
+		"1.2":   {"new feature", " ", "updated translations"},
+		"1.2.1": {"hotfix"},
+	}
+
+	tests := []struct {
+		name                             string
+		needVersion, needUploaded, needN bool
+		file                             nexus.File
+		wantVersion, wantUploaded, wantN string
+	}{
+		{
+			name:        "everything missing",
+			needVersion: true, needUploaded: true, needN: true,
+			file:         f,
+			wantVersion:  "1.2.0",
+			wantUploaded: "2023-11-14T22:13:20.000Z",
+			wantN:        "- new feature\n- updated translations",
+		},
+		{
+			name:         "only the upload time",
+			needUploaded: true,
+			file:         f,
+			wantUploaded: "2023-11-14T22:13:20.000Z",
+		},
+		{
+			name:        "nexus doesn't know either",
+			needVersion: true, needUploaded: true, needN: true,
+			file: nexus.File{FileID: 7},
+		},
+		{
+			name:  "no changelog for the version",
+			needN: true,
+			file:  nexus.File{FileID: 7, Version: "3.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v, u, n := nexusBackfillValues(tt.needVersion, tt.needUploaded, tt.needN, tt.file, logs)
+			assert.Equal(t, tt.wantVersion, v)
+			assert.Equal(t, tt.wantUploaded, u)
+			assert.Equal(t, tt.wantN, n)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// BatchScript is what `modctl batch` runs.
+type BatchScript struct {
+	// set if the script decides whether to stop on the first failed step
+	StopOnError *bool
+	Steps       []BatchStep
+}
+
+// BatchStep is one modctl command (without the leading "modctl").
+type BatchStep struct {
+	// where the step is in the script, for messages
+	Line int
+	Args []string
+}
+
+// ParseBatchLines parses a script of one command per line. Blank lines and
+// # comments are skipped; arguments are split like SplitArgs does.
+func ParseBatchLines(r io.Reader) (BatchScript, error) {
+	var script BatchScript
+
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		args, err := SplitArgs(s.Text())
+		if err != nil {
+			return BatchScript{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(args) == 0 {
+			continue
+		}
+		script.Steps = append(script.Steps, BatchStep{Line: line, Args: trimModctl(args)})
+	}
+	if err := s.Err(); err != nil {
+		return BatchScript{}, err
+	}
+
+	return script, nil
+}
+
+// ParseBatchYAML parses a YAML script, e.g.:
+//
+//	stop_on_error: true
+//	steps:
+//	  - games set-active steam:1091500
+//	  - [mods, import, "My Mod-123-1-0.zip"]
+//
+// where each step is either a command line (split like SplitArgs does) or
+// a list of arguments.
+func ParseBatchYAML(r io.Reader) (BatchScript, error) {
+	var doc struct {
+		StopOnError *bool       `yaml:"stop_on_error"`
+		Steps       []yaml.Node `yaml:"steps"`
+	}
+
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil && err != io.EOF {
+		return BatchScript{}, fmt.Errorf("parse batch script: %w", err)
+	}
+
+	script := BatchScript{StopOnError: doc.StopOnError}
+	for _, n := range doc.Steps {
+		var args []string
+		switch n.Kind {
+		case yaml.ScalarNode:
+			var err error
+			if args, err = SplitArgs(n.Value); err != nil {
+				return BatchScript{}, fmt.Errorf("line %d: %w", n.Line, err)
+			}
+		case yaml.SequenceNode:
+			if err := n.Decode(&args); err != nil {
+				return BatchScript{}, fmt.Errorf("line %d: %w", n.Line, err)
+			}
+		default:
+			return BatchScript{}, fmt.Errorf("line %d: a step is a command line or a list of arguments", n.Line)
+		}
+		if len(args) == 0 {
+			continue
+		}
+		script.Steps = append(script.Steps, BatchStep{Line: n.Line, Args: trimModctl(args)})
+	}
+
+	return script, nil
+}
+
+// trimModctl drops a leading "modctl" so that scripts can be written either
+// way.
+func trimModctl(args []string) []string {
+	if len(args) > 1 && args[0] == "modctl" {
+		return args[1:]
+	}
+	return args
+}
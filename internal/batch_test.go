@@ -0,0 +1,79 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatchLines(t *testing.T) {
+	t.Parallel()
+
+	script, err := ParseBatchLines(strings.NewReader(`# build the modlist
+games set-active steam:1091500
+
+modctl mods import 'My Mod-123-1-0.zip'
+profiles add 12 # the texture pack
+`))
+	require.NoError(t, err)
+
+	assert.Nil(t, script.StopOnError)
+	assert.Equal(t, []BatchStep{
+		{Line: 2, Args: []string{"games", "set-active", "steam:1091500"}},
+		{Line: 4, Args: []string{"mods", "import", "My Mod-123-1-0.zip"}},
+		{Line: 5, Args: []string{"profiles", "add", "12"}},
+	}, script.Steps)
+
+	_, err = ParseBatchLines(strings.NewReader("games list\nmods import 'oops\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestParseBatchYAML(t *testing.T) {
+	t.Parallel()
+
+	script, err := ParseBatchYAML(strings.NewReader(`stop_on_error: false
+steps:
+  - games set-active steam:1091500
+  - [mods, import, "My Mod-123-1-0.zip"]
+  - modctl profiles add 12
+`))
+	require.NoError(t, err)
+
+	require.NotNil(t, script.StopOnError)
+	assert.False(t, *script.StopOnError)
+	assert.Equal(t, []BatchStep{
+		{Line: 3, Args: []string{"games", "set-active", "steam:1091500"}},
+		{Line: 4, Args: []string{"mods", "import", "My Mod-123-1-0.zip"}},
+		{Line: 5, Args: []string{"profiles", "add", "12"}},
+	}, script.Steps)
+
+	script, err = ParseBatchYAML(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, script.Steps)
+
+	_, err = ParseBatchYAML(strings.NewReader("steps:\n  - {a: b}\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = ParseBatchYAML(strings.NewReader("stepz: []\n"))
+	assert.Error(t, err)
+}
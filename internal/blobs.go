@@ -0,0 +1,40 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/spf13/viper"
+)
+
+// BlobStoreFromConfig returns the blob store with the directories of the
+// config (archives_dir, shared_archives_dir, ...). Every command and the Go
+// API use it so that none of them misses a store, e.g., ingests an archive
+// that is already in the shared archive store again.
+func BlobStoreFromConfig() blobstore.Store {
+	return blobstore.Store{
+		ArchivesDir:       viper.GetString("archives_dir"),
+		SharedArchivesDir: viper.GetString("shared_archives_dir"),
+		BackupsDir:        viper.GetString("backups_dir"),
+		OverridesDir:      viper.GetString("overrides_dir"),
+		ExtractedDir:      viper.GetString("extracted_dir"),
+		TmpDir:            viper.GetString("tmp_dir"),
+		QuarantineDir:     viper.GetString("quarantine_dir"),
+	}
+}
@@ -27,6 +27,11 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"syscall"
+
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/perf"
+	"github.com/mfinelli/modctl/internal/readonly"
 )
 
 type Kind string
@@ -35,6 +40,12 @@ const (
 	KindArchive  Kind = "archive"
 	KindBackup   Kind = "backup"
 	KindOverride Kind = "override"
+
+	// KindExtracted are files of archives (and expanded template
+	// overrides) that are deployed as symlinks. They aren't recorded in
+	// the blobs table: the installed files that link to them are their
+	// only references.
+	KindExtracted Kind = "extracted"
 )
 
 type Store struct {
@@ -42,6 +53,23 @@ type Store struct {
 	BackupsDir   string
 	OverridesDir string
 	TmpDir       string
+
+	// ExtractedDir has the blobs of KindExtracted; without it there's no
+	// such store.
+	ExtractedDir string
+
+	// SharedArchivesDir is an archive store that the users of a machine
+	// share (e.g., /srv/modctl/archives), with the same layout. modctl only
+	// reads it: archives in it are found there before ArchivesDir (see
+	// Locate) and aren't stored in ArchivesDir again when they're ingested.
+	SharedArchivesDir string
+
+	// where Quarantine moves corrupted blobs to
+	QuarantineDir string
+
+	// Progress, if set, is called while IngestFile or Verify reads a file
+	// with the bytes read so far and the size of the file.
+	Progress func(done, total int64)
 }
 
 func (s Store) RootFor(kind Kind) (string, error) {
@@ -52,6 +80,11 @@ func (s Store) RootFor(kind Kind) (string, error) {
 		return s.BackupsDir, nil
 	case KindOverride:
 		return s.OverridesDir, nil
+	case KindExtracted:
+		if s.ExtractedDir == "" {
+			return "", errors.New("no store for extracted files")
+		}
+		return s.ExtractedDir, nil
 	default:
 		return "", fmt.Errorf("unknown blob kind: %q", string(kind))
 	}
@@ -70,6 +103,32 @@ func (s Store) PathFor(kind Kind, shaHex string) (string, error) {
 	return filepath.Join(root, fan, shaHex), nil
 }
 
+// Locate returns where a blob is to read it: in the shared archive store if
+// it's there, otherwise where PathFor says (whether it exists or not).
+func (s Store) Locate(kind Kind, shaHex string) (string, error) {
+	if p, ok := s.shared(kind, shaHex); ok {
+		return p, nil
+	}
+	return s.PathFor(kind, shaHex)
+}
+
+// shared returns the path of a blob in the shared archive store, if it's
+// there.
+func (s Store) shared(kind Kind, shaHex string) (string, bool) {
+	if kind != KindArchive || s.SharedArchivesDir == "" || len(shaHex) != 64 {
+		return "", false
+	}
+	p := filepath.Join(s.SharedArchivesDir, shaHex[:2], shaHex)
+	if st, err := os.Stat(p); err != nil || !st.Mode().IsRegular() {
+		return "", false
+	}
+	return p, true
+}
+
+// ErrShared is returned when a blob in the shared archive store would have
+// to be changed.
+var ErrShared = errors.New("blob is in the shared archive store")
+
 type IngestResult struct {
 	SHA256Hex string
 	SizeBytes int64
@@ -77,13 +136,24 @@ type IngestResult struct {
 }
 
 // IngestFile streams srcPath into the blob store, addressed by sha256.
-// Writes a temp file in the destination directory and renames into place atomically.
+// Writes a temp file in the tmp directory and renames into place atomically
+// (see moveFile).
 func (s Store) IngestFile(ctx context.Context, kind Kind, srcPath string) (IngestResult, error) {
+	if dryrun.Enabled() {
+		return s.pretendIngest(ctx, kind, srcPath)
+	}
+	if err := readonly.Check("store " + string(kind)); err != nil {
+		return IngestResult{}, err
+	}
+
+	// the file is copied into the store while it's hashed
+	defer perf.Track(perf.Hashing)()
+
 	var res IngestResult
 
 	finalTmpKey := "" // helps error messages if we get far enough
 
-	src, err := os.Open(srcPath)
+	src, err := OpenRead(srcPath)
 	if err != nil {
 		return res, fmt.Errorf("open src: %w", err)
 	}
@@ -111,9 +181,15 @@ func (s Store) IngestFile(ctx context.Context, kind Kind, srcPath string) (Inges
 
 	// Stream copy: write bytes to tmp while hashing.
 	w := io.MultiWriter(tmp, h)
+	if s.Progress != nil {
+		st, err := src.Stat()
+		if err != nil {
+			return res, fmt.Errorf("stat src: %w", err)
+		}
+		w = NewProgressWriter(w, st.Size(), s.Progress)
+	}
 
-	buf := make([]byte, 1024*1024) // 1MiB buffer; fine for big archives
-	n, err := CopyWithContext(ctx, w, src, buf)
+	n, err := CopyWithContext(ctx, w, src, nil)
 	if err != nil {
 		return res, fmt.Errorf("copy: %w", err)
 	}
@@ -128,6 +204,10 @@ func (s Store) IngestFile(ctx context.Context, kind Kind, srcPath string) (Inges
 	sum := h.Sum(nil)
 	shaHex := hex.EncodeToString(sum)
 
+	if existed, err := s.sharedHas(kind, shaHex, n); existed || err != nil {
+		return IngestResult{SHA256Hex: shaHex, SizeBytes: n, Existed: existed}, err
+	}
+
 	finalPath, err := s.PathFor(kind, shaHex)
 	if err != nil {
 		return res, err
@@ -155,7 +235,7 @@ func (s Store) IngestFile(ctx context.Context, kind Kind, srcPath string) (Inges
 	}
 
 	// Move into place.
-	if err := os.Rename(tmpName, finalPath); err != nil {
+	if err := moveFile(ctx, tmpName, finalPath); err != nil {
 		// If we raced and it appeared, treat as dedupe.
 		if st, statErr := os.Stat(finalPath); statErr == nil {
 			if st.Size() != n {
@@ -175,8 +255,182 @@ func (s Store) IngestFile(ctx context.Context, kind Kind, srcPath string) (Inges
 	return IngestResult{SHA256Hex: shaHex, SizeBytes: n, Existed: false}, nil
 }
 
-// CopyWithContext copies bytes from src to dst using the provided buffer,
-// periodically checking ctx for cancellation.
+// sharedHas reports whether the shared archive store already has a blob
+// that was just hashed, so that it doesn't have to be stored again.
+func (s Store) sharedHas(kind Kind, shaHex string, size int64) (bool, error) {
+	p, ok := s.shared(kind, shaHex)
+	if !ok {
+		return false, nil
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		return false, fmt.Errorf("stat shared: %w", err)
+	}
+	if st.Size() != size {
+		return false, fmt.Errorf(
+			"blob collision/corruption: %s exists with size=%d, ingest size=%d",
+			p, st.Size(), size,
+		)
+	}
+	return true, nil
+}
+
+// moveFile renames src to dst. The tmp directory doesn't have to be on the
+// same filesystem as the stores though, and a rename can't cross
+// filesystems (EXDEV): then src is copied to a temp file next to dst, synced,
+// and that is renamed into place instead, so dst still appears atomically.
+// Known different filesystems aren't even tried: network filesystems don't
+// all answer a rename from another mount with EXDEV.
+func moveFile(ctx context.Context, src, dst string) error {
+	if crossDevice(filepath.Dir(src), filepath.Dir(dst)) {
+		return copyIntoPlace(ctx, src, dst)
+	}
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	return copyIntoPlace(ctx, src, dst)
+}
+
+// copyIntoPlace is the fallback of moveFile for different filesystems.
+func copyIntoPlace(ctx context.Context, src, dst string) error {
+	in, err := OpenRead(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".ingest-*")
+	if err != nil {
+		return fmt.Errorf("create temp: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName) // no-op if rename succeeded
+	}()
+
+	if _, err := CopyWithContext(ctx, tmp, in, nil); err != nil {
+		return fmt.Errorf("copy across filesystems: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("fsync temp: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp: %w", err)
+	}
+
+	if err := os.Rename(tmpName, dst); err != nil {
+		return err
+	}
+	_ = os.Remove(src)
+
+	return nil
+}
+
+// ErrCorrupt is returned by Verify if a blob doesn't match its hash.
+var ErrCorrupt = errors.New("blob is corrupted")
+
+// Verify hashes a blob again and checks it against its address and its
+// recorded size. A missing blob is an os.ErrNotExist error.
+func (s Store) Verify(ctx context.Context, kind Kind, shaHex string, size int64) error {
+	defer perf.Track(perf.Hashing)()
+
+	path, err := s.Locate(kind, shaHex)
+	if err != nil {
+		return err
+	}
+
+	f, err := OpenRead(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	var w io.Writer = h
+	if s.Progress != nil {
+		st, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		w = NewProgressWriter(w, st.Size(), s.Progress)
+	}
+
+	n, err := CopyWithContext(ctx, w, f, nil)
+	if err != nil {
+		return fmt.Errorf("hash %s: %w", path, err)
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); got != shaHex || n != size {
+		return fmt.Errorf("%w: %s should be %d bytes with sha256 %s but is %d bytes with sha256 %s",
+			ErrCorrupt, path, size, shaHex, n, got)
+	}
+
+	return nil
+}
+
+// pretendIngest hashes srcPath like IngestFile does but only notes that it
+// would have stored it, for --dry-run.
+func (s Store) pretendIngest(ctx context.Context, kind Kind, srcPath string) (IngestResult, error) {
+	defer perf.Track(perf.Hashing)()
+
+	src, err := OpenRead(srcPath)
+	if err != nil {
+		return IngestResult{}, fmt.Errorf("open src: %w", err)
+	}
+	defer src.Close()
+
+	h := sha256.New()
+	n, err := CopyWithContext(ctx, h, src, nil)
+	if err != nil {
+		return IngestResult{}, fmt.Errorf("hash: %w", err)
+	}
+	shaHex := hex.EncodeToString(h.Sum(nil))
+
+	if existed, err := s.sharedHas(kind, shaHex, n); existed || err != nil {
+		return IngestResult{SHA256Hex: shaHex, SizeBytes: n, Existed: existed}, err
+	}
+
+	finalPath, err := s.PathFor(kind, shaHex)
+	if err != nil {
+		return IngestResult{}, err
+	}
+	if _, err := os.Stat(finalPath); err == nil {
+		return IngestResult{SHA256Hex: shaHex, SizeBytes: n, Existed: true}, nil
+	}
+
+	dryrun.Note("store %s as %s %s", srcPath, kind, finalPath)
+	return IngestResult{SHA256Hex: shaHex, SizeBytes: n}, nil
+}
+
+// NewProgressWriter returns a writer that writes to w and reports the bytes
+// written so far (of total) to fn, like the Progress of a Store.
+func NewProgressWriter(w io.Writer, total int64, fn func(done, total int64)) io.Writer {
+	return &progressWriter{w: w, total: total, fn: fn}
+}
+
+// progressWriter reports the bytes written through it to a Progress
+// function.
+type progressWriter struct {
+	w     io.Writer
+	done  int64
+	total int64
+	fn    func(done, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	p.fn(p.done, p.total)
+	return n, err
+}
+
+// CopyWithContext copies bytes from src to dst using the provided buffer (or
+// one from NewBuffer if it's nil), periodically checking ctx for
+// cancellation. If src is a file the kernel gets the tuned hints about it
+// (see Tuning). Without a buffer, files on network filesystems are copied
+// with a larger one (see NetworkBufferSize).
 //
 // It behaves similarly to io.CopyBuffer, but allows the caller to cancel
 // long-running copy operations (e.g., very large archives) via context.
@@ -193,6 +447,19 @@ func (s Store) IngestFile(ctx context.Context, kind Kind, srcPath string) (Inges
 func CopyWithContext(ctx context.Context, dst io.Writer, src io.Reader, buf []byte) (int64, error) {
 	var total int64
 
+	if buf == nil {
+		if networkFile(src) || networkFile(dst) {
+			buf = newBuffer(max(CurrentTuning().BufferSize, NetworkBufferSize))
+		} else {
+			buf = NewBuffer()
+		}
+	}
+	var hints *readHints
+	if f, ok := src.(*os.File); ok {
+		hints = startReadHints(f)
+		defer hints.done()
+	}
+
 	for {
 		// Allow cancellation between read iterations.
 		// We intentionally check before reading to avoid unnecessary work.
@@ -204,6 +471,9 @@ func CopyWithContext(ctx context.Context, dst io.Writer, src io.Reader, buf []by
 
 		// Read up to len(buf) bytes.
 		nr, er := src.Read(buf)
+		if hints != nil && nr > 0 {
+			hints.read(nr)
+		}
 		if nr > 0 {
 			// Write exactly what was read.
 			nw, ew := dst.Write(buf[:nr])
@@ -231,6 +501,104 @@ func CopyWithContext(ctx context.Context, dst io.Writer, src io.Reader, buf []by
 	}
 }
 
+// Remove deletes a blob from the store. Removing a blob that doesn't exist
+// is not an error. The fan-out directory is removed too if it's now empty.
+//
+// Callers must delete the blobs row first (and commit): a row without a file
+// is reported by doctor, a file without a row is just garbage.
+func (s Store) Remove(kind Kind, shaHex string) error {
+	path, err := s.PathFor(kind, shaHex)
+	if err != nil {
+		return err
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Note("remove %s %s", kind, path)
+		return nil
+	}
+	if err := readonly.Check("remove " + string(kind)); err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove blob: %w", err)
+	}
+
+	// fails (harmlessly) if other blobs share the fan-out directory
+	_ = os.Remove(filepath.Dir(path))
+	_ = fsyncDir(filepath.Dir(filepath.Dir(path)))
+
+	return nil
+}
+
+// Quarantine moves a (corrupted) blob out of the store into the quarantine
+// directory, to <quarantine>/<kind>/<sha256>, and returns its new path. Unlike
+// Remove it keeps the file so that it can still be looked at.
+func (s Store) Quarantine(ctx context.Context, kind Kind, shaHex string) (string, error) {
+	path, err := s.PathFor(kind, shaHex)
+	if err != nil {
+		return "", err
+	}
+	// the copy that was found to be corrupted is the shared one (see Locate)
+	if p, ok := s.shared(kind, shaHex); ok {
+		return "", fmt.Errorf("%w, which modctl doesn't change: %s", ErrShared, p)
+	}
+	if s.QuarantineDir == "" {
+		return "", errors.New("no quarantine directory")
+	}
+	dst := filepath.Join(s.QuarantineDir, string(kind), shaHex)
+
+	if dryrun.Enabled() {
+		dryrun.Note("quarantine %s %s", kind, path)
+		return dst, nil
+	}
+	if err := readonly.Check("quarantine " + string(kind)); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", fmt.Errorf("mkdir quarantine: %w", err)
+	}
+	if err := moveFile(ctx, path, dst); err != nil {
+		return "", fmt.Errorf("quarantine blob: %w", err)
+	}
+
+	// fails (harmlessly) if other blobs share the fan-out directory
+	_ = os.Remove(filepath.Dir(path))
+	_ = fsyncDir(filepath.Dir(filepath.Dir(path)))
+	_ = fsyncDir(filepath.Dir(dst))
+
+	return dst, nil
+}
+
+// Unquarantine moves a quarantined blob back into the store, undoing
+// Quarantine (e.g., when the quarantine couldn't be recorded).
+func (s Store) Unquarantine(ctx context.Context, kind Kind, shaHex string) error {
+	path, err := s.PathFor(kind, shaHex)
+	if err != nil {
+		return err
+	}
+	src := filepath.Join(s.QuarantineDir, string(kind), shaHex)
+
+	if dryrun.Enabled() {
+		return nil
+	}
+	if err := readonly.Check("unquarantine " + string(kind)); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("mkdir blob dir: %w", err)
+	}
+	if err := moveFile(ctx, src, path); err != nil {
+		return fmt.Errorf("unquarantine blob: %w", err)
+	}
+
+	_ = fsyncDir(filepath.Dir(path))
+	_ = fsyncDir(filepath.Dir(src))
+	return nil
+}
+
 // fsyncDir calls fsync(2) on a directory to ensure that metadata changes
 // within that directory are durably persisted to disk.
 //
@@ -249,8 +617,14 @@ func CopyWithContext(ctx context.Context, dst io.Writer, src io.Reader, buf []by
 // proper crash-consistency for atomic rename patterns.
 //
 // It is intentionally non-fatal in callers because durability is strongly
-// desired but not worth aborting the operation if unsupported.
+// desired but not worth aborting the operation if unsupported. It's skipped
+// on network filesystems: NFS commits the rename on the server before it
+// returns, and SMB servers reject fsync on directories (or it's a round trip
+// for nothing).
 func fsyncDir(dir string) error {
+	if onNetwork(dir) {
+		return nil
+	}
 	f, err := os.Open(dir)
 	if err != nil {
 		return err
@@ -0,0 +1,254 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyIntoPlace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "tmp", "incoming")
+	dst := filepath.Join(dir, "archives", "ab", "blob")
+	require.NoError(t, os.MkdirAll(filepath.Dir(src), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o600))
+
+	require.NoError(t, copyIntoPlace(context.Background(), src, dst))
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+
+	// the source is gone, like after a rename, and no temp files are left
+	assert.NoFileExists(t, src)
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestMoveFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "incoming")
+	dst := filepath.Join(dir, "blob")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o600))
+
+	// same filesystem: renamed
+	assert.False(t, crossDevice(dir, dir))
+	require.NoError(t, moveFile(context.Background(), src, dst))
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+	assert.NoFileExists(t, src)
+	assert.False(t, onNetwork(dir))
+}
+
+func TestIngestFile(t *testing.T) {
+	t.Parallel()
+
+	var done, total int64
+	dir := t.TempDir()
+	s := Store{
+		ArchivesDir: filepath.Join(dir, "archives"),
+		TmpDir:      filepath.Join(dir, "tmp"),
+		Progress:    func(d, n int64) { done, total = d, n },
+	}
+	src := filepath.Join(dir, "mod.zip")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o644))
+
+	res, err := s.IngestFile(context.Background(), KindArchive, src)
+	require.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", res.SHA256Hex)
+	assert.Equal(t, int64(5), res.SizeBytes)
+	assert.False(t, res.Existed)
+	assert.Equal(t, int64(5), done)
+	assert.Equal(t, int64(5), total)
+
+	path, err := s.PathFor(KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	res, err = s.IngestFile(context.Background(), KindArchive, src)
+	require.NoError(t, err)
+	assert.True(t, res.Existed)
+}
+
+func TestSharedArchives(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	shared := Store{
+		ArchivesDir: filepath.Join(dir, "shared"),
+		TmpDir:      filepath.Join(dir, "tmp"),
+	}
+	s := Store{
+		ArchivesDir:       filepath.Join(dir, "archives"),
+		SharedArchivesDir: shared.ArchivesDir,
+		TmpDir:            filepath.Join(dir, "tmp"),
+		QuarantineDir:     filepath.Join(dir, "quarantine"),
+	}
+	src := filepath.Join(dir, "mod.zip")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o644))
+
+	res, err := shared.IngestFile(context.Background(), KindArchive, src)
+	require.NoError(t, err)
+	sharedPath, err := shared.PathFor(KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+	own, err := s.PathFor(KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+
+	// it isn't stored again
+	res, err = s.IngestFile(context.Background(), KindArchive, src)
+	require.NoError(t, err)
+	assert.True(t, res.Existed)
+	assert.NoFileExists(t, own)
+
+	path, err := s.Locate(KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+	assert.Equal(t, sharedPath, path)
+	require.NoError(t, s.Verify(context.Background(), KindArchive, res.SHA256Hex, res.SizeBytes))
+
+	_, err = s.Quarantine(context.Background(), KindArchive, res.SHA256Hex)
+	assert.ErrorIs(t, err, ErrShared)
+	assert.FileExists(t, sharedPath)
+
+	// other archives are in the own store
+	other := filepath.Join(dir, "other.zip")
+	require.NoError(t, os.WriteFile(other, []byte("world"), 0o644))
+	res, err = s.IngestFile(context.Background(), KindArchive, other)
+	require.NoError(t, err)
+	assert.False(t, res.Existed)
+	path, err = s.Locate(KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "archives", res.SHA256Hex[:2], res.SHA256Hex), path)
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := Store{
+		ArchivesDir: filepath.Join(dir, "archives"),
+		TmpDir:      filepath.Join(dir, "tmp"),
+	}
+	src := filepath.Join(dir, "mod.zip")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o644))
+
+	res, err := s.IngestFile(context.Background(), KindArchive, src)
+	require.NoError(t, err)
+	require.NoError(t, s.Verify(context.Background(), KindArchive, res.SHA256Hex, res.SizeBytes))
+
+	// recorded with a different size
+	assert.ErrorIs(t, s.Verify(context.Background(), KindArchive, res.SHA256Hex, 6), ErrCorrupt)
+
+	path, err := s.PathFor(KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("jello"), 0o600))
+	assert.ErrorIs(t, s.Verify(context.Background(), KindArchive, res.SHA256Hex, res.SizeBytes), ErrCorrupt)
+
+	require.NoError(t, os.Remove(path))
+	assert.ErrorIs(t, s.Verify(context.Background(), KindArchive, res.SHA256Hex, res.SizeBytes), os.ErrNotExist)
+}
+
+func TestQuarantine(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := Store{
+		ArchivesDir:   filepath.Join(dir, "archives"),
+		TmpDir:        filepath.Join(dir, "tmp"),
+		QuarantineDir: filepath.Join(dir, "quarantine"),
+	}
+	src := filepath.Join(dir, "mod.zip")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o644))
+
+	res, err := s.IngestFile(context.Background(), KindArchive, src)
+	require.NoError(t, err)
+	path, err := s.PathFor(KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+
+	dst, err := s.Quarantine(context.Background(), KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "quarantine", "archive", res.SHA256Hex), dst)
+	assert.FileExists(t, dst)
+	assert.NoFileExists(t, path)
+	assert.NoDirExists(t, filepath.Dir(path))
+
+	// undone
+	require.NoError(t, s.Unquarantine(context.Background(), KindArchive, res.SHA256Hex))
+	assert.NoFileExists(t, dst)
+	assert.FileExists(t, path)
+	_, err = s.Quarantine(context.Background(), KindArchive, res.SHA256Hex)
+	require.NoError(t, err)
+
+	// ingesting it again puts it back
+	res, err = s.IngestFile(context.Background(), KindArchive, src)
+	require.NoError(t, err)
+	assert.False(t, res.Existed)
+	assert.FileExists(t, path)
+}
+
+func TestCopyWithContextTuned(t *testing.T) {
+	// not parallel: the tuning is global
+	SetTuning(Tuning{BufferSize: 2 * DirectAlignment, Direct: true, Fadvise: "dontneed", ReadAhead: 64 * 1024})
+	t.Cleanup(func() { SetTuning(DefaultTuning) })
+
+	buf := NewBuffer()
+	assert.Len(t, buf, 2*DirectAlignment)
+	assert.Zero(t, uintptr(unsafe.Pointer(&buf[0]))%DirectAlignment)
+
+	// not a multiple of the buffer size, so the last read is short
+	data := make([]byte, 10*DirectAlignment+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	f, err := OpenRead(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var out bytes.Buffer
+	n, err := CopyWithContext(context.Background(), &out, f, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	assert.Equal(t, data, out.Bytes())
+}
+
+func TestSetTuningDefaultsBufferSize(t *testing.T) {
+	SetTuning(Tuning{Fadvise: "sequential"})
+	t.Cleanup(func() { SetTuning(DefaultTuning) })
+
+	assert.Equal(t, DefaultTuning.BufferSize, CurrentTuning().BufferSize)
+	assert.Equal(t, "sequential", CurrentTuning().Fadvise)
+}
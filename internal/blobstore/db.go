@@ -34,6 +34,8 @@ import (
 //
 // verified_at is set only on insert. For existing blobs, verified_at is
 // reserved for doctor --deep (rehash verification), not for "we saw a file".
+// The exception is a quarantined blob (see QuarantineBlob): its file was
+// moved away, so the caller just ingested it again and it's good again.
 func EnsureBlobRecorded(
 	ctx context.Context,
 	q *dbq.Queries,
@@ -58,7 +60,16 @@ func EnsureBlobRecorded(
 				sha256, existing.SizeBytes, sizeBytes,
 			)
 		}
-		// Do not update verified_at here. That is only updated by deep verification.
+		if existing.CorruptedAt.Valid {
+			if err := q.ClearBlobCorrupted(ctx, dbq.ClearBlobCorruptedParams{
+				VerifiedAt: sql.NullString{String: now, Valid: true},
+				Sha256:     sha256,
+			}); err != nil {
+				return fmt.Errorf("clear blob corrupted: %w", err)
+			}
+		}
+		// Otherwise do not update verified_at here. That is only updated by
+		// deep verification.
 		return nil
 	}
 
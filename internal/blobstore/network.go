@@ -0,0 +1,58 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package blobstore
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mfinelli/modctl/internal/fscaps"
+)
+
+// networkDirs caches onNetwork by directory.
+var networkDirs sync.Map
+
+// onNetwork reports whether dir is on a network filesystem (see
+// fscaps.NetworkFS). It's only looked up once per directory.
+func onNetwork(dir string) bool {
+	if v, ok := networkDirs.Load(dir); ok {
+		return v.(bool)
+	}
+	n := fscaps.NetworkFS(dir) != ""
+	networkDirs.Store(dir, n)
+	return n
+}
+
+// networkFile reports whether v is a file on a network filesystem.
+func networkFile(v any) bool {
+	f, ok := v.(*os.File)
+	return ok && onNetwork(filepath.Dir(f.Name()))
+}
+
+// crossDevice reports whether a and b are known to be on different
+// filesystems.
+func crossDevice(a, b string) bool {
+	da, err := fscaps.Device(a)
+	if err != nil {
+		return false
+	}
+	db, err := fscaps.Device(b)
+	return err == nil && da != db
+}
@@ -0,0 +1,168 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package blobstore
+
+import (
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// Tuning is how files are read while they're copied into the store or
+// hashed (see SetTuning). The defaults are fine for local disks; the hints
+// help on network filesystems or when reading big archives shouldn't push
+// everything else out of the page cache.
+type Tuning struct {
+	// BufferSize is how much is read at once. With Direct it has to be a
+	// multiple of DirectAlignment.
+	BufferSize int
+	// Direct opens the files that are read with O_DIRECT (linux only),
+	// bypassing the page cache. Filesystems that don't support it are read
+	// normally.
+	Direct bool
+	// Fadvise is the access pattern hint given to the kernel before a file
+	// is read: "sequential" (read ahead more), "noreuse" (the data is only
+	// read once), or "dontneed" (drop it from the page cache afterwards).
+	Fadvise string
+	// ReadAhead asks the kernel to start reading this many bytes ahead of
+	// what has been read so far (0: leave it to the kernel).
+	ReadAhead int64
+}
+
+// DirectAlignment is what O_DIRECT reads have to be aligned to (the
+// buffer, its size, and the offsets).
+const DirectAlignment = 4096
+
+// FadviseModes are the valid values of Tuning.Fadvise ("" gives no hint).
+var FadviseModes = []string{"", "sequential", "noreuse", "dontneed"}
+
+// NetworkBufferSize is the smallest buffer that CopyWithContext reads
+// files on network filesystems with: every read is a round trip to the
+// server.
+const NetworkBufferSize = 8 * 1024 * 1024
+
+// DefaultTuning is used until SetTuning is called.
+var DefaultTuning = Tuning{BufferSize: 1024 * 1024}
+
+var (
+	tuningMu sync.Mutex
+	tuning   = DefaultTuning
+)
+
+// SetTuning changes how files are read from now on (e.g., from the io_*
+// config options). A buffer size that isn't positive is the default one.
+func SetTuning(t Tuning) {
+	if t.BufferSize <= 0 {
+		t.BufferSize = DefaultTuning.BufferSize
+	}
+
+	tuningMu.Lock()
+	defer tuningMu.Unlock()
+	tuning = t
+}
+
+// CurrentTuning returns how files are read.
+func CurrentTuning() Tuning {
+	tuningMu.Lock()
+	defer tuningMu.Unlock()
+	return tuning
+}
+
+// OpenRead opens a file for reading as tuned (i.e., with O_DIRECT if it's
+// enabled and the filesystem supports it). Read it with a buffer from
+// NewBuffer, which CopyWithContext does if it isn't given one.
+func OpenRead(path string) (*os.File, error) {
+	if CurrentTuning().Direct {
+		if f, err := openDirect(path); err == nil {
+			return f, nil
+		}
+	}
+	return os.Open(path)
+}
+
+// NewBuffer returns a read buffer of the tuned size, aligned for O_DIRECT.
+func NewBuffer() []byte {
+	return newBuffer(CurrentTuning().BufferSize)
+}
+
+func newBuffer(size int) []byte {
+	b := make([]byte, size+DirectAlignment)
+	off := 0
+	if rem := int(uintptr(unsafe.Pointer(&b[0])) % DirectAlignment); rem != 0 {
+		off = DirectAlignment - rem
+	}
+	return b[off : off+size : off+size]
+}
+
+// DropCache asks the kernel to drop a file from the page cache (e.g., to
+// time reading it from the disk). It's best-effort.
+func DropCache(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fadvise(f, 0, 0, "dontneed")
+}
+
+// readHints gives the kernel the tuned hints about a file while
+// CopyWithContext reads it.
+type readHints struct {
+	f      *os.File
+	t      Tuning
+	offset int64
+	// how far the read ahead has been requested
+	ahead int64
+}
+
+func startReadHints(f *os.File) *readHints {
+	h := &readHints{f: f, t: CurrentTuning()}
+	if h.t.Fadvise == "sequential" || h.t.Fadvise == "noreuse" {
+		fadvise(f, 0, 0, h.t.Fadvise)
+	}
+	h.readAhead()
+	return h
+}
+
+// read notes that n more bytes were read.
+func (h *readHints) read(n int) {
+	h.offset += int64(n)
+	// request the next window once half of the previous one is read
+	if h.t.ReadAhead > 0 && h.ahead-h.offset < h.t.ReadAhead/2 {
+		h.readAhead()
+	}
+}
+
+func (h *readHints) readAhead() {
+	if h.t.ReadAhead <= 0 {
+		return
+	}
+	start := max(h.ahead, h.offset)
+	end := h.offset + h.t.ReadAhead
+	if end > start {
+		fadvise(h.f, start, end-start, "willneed")
+		h.ahead = end
+	}
+}
+
+func (h *readHints) done() {
+	if h.t.Fadvise == "dontneed" {
+		fadvise(h.f, 0, 0, "dontneed")
+	}
+}
@@ -0,0 +1,50 @@
+//go:build linux
+
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package blobstore
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func openDirect(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDONLY|unix.O_DIRECT, 0)
+}
+
+// fadvise gives the kernel a hint about a range of a file (length 0: to
+// the end). Hints are best-effort, errors are ignored.
+func fadvise(f *os.File, offset, length int64, advice string) {
+	var a int
+	switch advice {
+	case "sequential":
+		a = unix.FADV_SEQUENTIAL
+	case "noreuse":
+		a = unix.FADV_NOREUSE
+	case "dontneed":
+		a = unix.FADV_DONTNEED
+	case "willneed":
+		a = unix.FADV_WILLNEED
+	default:
+		return
+	}
+	_ = unix.Fadvise(int(f.Fd()), offset, length, a)
+}
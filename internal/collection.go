@@ -0,0 +1,207 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/nexus"
+)
+
+// CollectionFormat identifies collections, CollectionVersion changes when their
+// shape does.
+const (
+	CollectionFormat  = "modctl-collection"
+	CollectionVersion = 1
+)
+
+// Collection is a shareable description of a profile: what to download
+// (nexus IDs and file IDs, or the source URL, and the sha256 of the archive
+// to check it), and how to install it (order, remap rules, hidden files),
+// so that the setup can be reproduced on another machine. The archives and
+// the overrides of the profile aren't part of it.
+type Collection struct {
+	Format     string             `json:"format"`
+	Version    int                `json:"version"`
+	ExportedAt string             `json:"exported_at"`
+	Game       CollectionGame     `json:"game"`
+	Profile    CollectionProfile  `json:"profile"`
+	Mods       []CollectionMod    `json:"mods"`
+	Plugins    []CollectionPlugin `json:"plugins,omitempty"`
+}
+
+// CollectionGame is the game that a collection is for.
+type CollectionGame struct {
+	Name        string `json:"name"`
+	Store       string `json:"store"`
+	StoreGameID string `json:"store_game_id"`
+}
+
+// CollectionProfile is the profile that a collection was exported from.
+type CollectionProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	GameVersion string `json:"game_version,omitempty"`
+}
+
+// CollectionMod is an archive in a collection. Mods are in install order:
+// when two of them provide the same file the later one wins.
+type CollectionMod struct {
+	Name        string           `json:"name"`
+	File        string           `json:"file"`
+	Version     string           `json:"version,omitempty"`
+	ArchiveName string           `json:"archive_name,omitempty"`
+	SHA256      string           `json:"sha256"`
+	SizeBytes   int64            `json:"size_bytes"`
+	Enabled     bool             `json:"enabled"`
+	Priority    int64            `json:"priority"`
+	Symlinks    string           `json:"symlinks"`
+	Source      CollectionSource `json:"source"`
+	Nexus       *CollectionNexus `json:"nexus,omitempty"`
+	Rules       []RevisionRule   `json:"rules,omitempty"`
+	Hidden      []string         `json:"hidden,omitempty"`
+}
+
+// CollectionSource is where a mod came from.
+type CollectionSource struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url,omitempty"`
+}
+
+// CollectionNexus identifies the nexus file of a mod.
+type CollectionNexus struct {
+	GameDomain string `json:"game_domain"`
+	ModID      int64  `json:"mod_id"`
+	FileID     int64  `json:"file_id,omitempty"`
+	URL        string `json:"url"`
+}
+
+// CollectionPlugin is an entry of the plugin load order of a collection.
+type CollectionPlugin struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// BuildCollection describes a profile as a collection.
+func BuildCollection(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall, p dbq.Profile) (Collection, error) {
+	c := Collection{
+		Format:     CollectionFormat,
+		Version:    CollectionVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Game: CollectionGame{
+			Name:        gi.DisplayName,
+			Store:       gi.StoreID,
+			StoreGameID: gi.StoreGameID,
+		},
+		Profile: CollectionProfile{
+			Name:        p.Name,
+			Description: p.Description.String,
+			GameVersion: p.GameVersion.String,
+		},
+		Mods: []CollectionMod{},
+	}
+
+	rows, err := q.ListProfileItemsForCollection(ctx, p.ID)
+	if err != nil {
+		return c, fmt.Errorf("list profile items: %w", err)
+	}
+
+	rules, err := q.ListRemapRulesForProfile(ctx, p.ID)
+	if err != nil {
+		return c, fmt.Errorf("list remap rules: %w", err)
+	}
+	rulesByItem := map[int64][]RevisionRule{}
+	for _, r := range rules {
+		rulesByItem[r.ProfileItemID] = append(rulesByItem[r.ProfileItemID], RevisionRule{
+			Type: r.RuleType,
+			Int:  r.IntValue.Int64,
+			Text: r.TextValue.String,
+		})
+	}
+
+	hidden, err := q.ListHiddenFilesForProfile(ctx, p.ID)
+	if err != nil {
+		return c, fmt.Errorf("list hidden files: %w", err)
+	}
+	hiddenByItem := map[int64][]string{}
+	for _, h := range hidden {
+		hiddenByItem[h.ProfileItemID] = append(hiddenByItem[h.ProfileItemID], h.Relpath)
+	}
+
+	for _, r := range rows {
+		m := collectionMod(r)
+		m.Rules = rulesByItem[r.ID]
+		m.Hidden = hiddenByItem[r.ID]
+		c.Mods = append(c.Mods, m)
+	}
+
+	plugins, err := q.ListPluginOrderForProfile(ctx, p.ID)
+	if err != nil {
+		return c, fmt.Errorf("list plugin order: %w", err)
+	}
+	for _, pl := range plugins {
+		c.Plugins = append(c.Plugins, CollectionPlugin{
+			Name:    pl.PluginName,
+			Enabled: pl.Enabled != 0,
+		})
+	}
+
+	return c, nil
+}
+
+// collectionMod describes a profile item (without its rules and hidden
+// files).
+func collectionMod(r dbq.ListProfileItemsForCollectionRow) CollectionMod {
+	m := CollectionMod{
+		Name:        r.ModName,
+		File:        r.FileLabel,
+		Version:     r.VersionString.String,
+		ArchiveName: r.OriginalName.String,
+		SHA256:      r.ArchiveSha256,
+		SizeBytes:   r.SizeBytes,
+		Enabled:     r.Enabled != 0,
+		Priority:    r.Priority,
+		Symlinks:    r.Symlinks,
+		Source: CollectionSource{
+			Kind: r.SourceKind,
+			URL:  r.FileSourceUrl.String,
+		},
+	}
+	if m.Source.URL == "" {
+		m.Source.URL = r.SourceUrl.String
+	}
+
+	if r.SourceKind == "nexus" && r.NexusGameDomain.Valid && r.NexusModID.Valid {
+		ref := nexus.ModRef{GameDomain: r.NexusGameDomain.String, ModID: r.NexusModID.Int64}
+		m.Nexus = &CollectionNexus{
+			GameDomain: ref.GameDomain,
+			ModID:      ref.ModID,
+			URL:        ref.FilesURL(),
+		}
+		if r.NexusFileID.Valid {
+			m.Nexus.FileID = r.NexusFileID.Int64
+			m.Nexus.URL = ref.FileURL(r.NexusFileID.Int64)
+		}
+	}
+
+	return m
+}
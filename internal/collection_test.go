@@ -0,0 +1,88 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionMod(t *testing.T) {
+	t.Parallel()
+
+	row := dbq.ListProfileItemsForCollectionRow{
+		ID:              7,
+		Enabled:         1,
+		Priority:        20,
+		Symlinks:        "copy",
+		ModName:         "SkyUI",
+		SourceKind:      "nexus",
+		NexusGameDomain: sql.NullString{String: "skyrimspecialedition", Valid: true},
+		NexusModID:      sql.NullInt64{Int64: 12604, Valid: true},
+		FileLabel:       "main",
+		NexusFileID:     sql.NullInt64{Int64: 35407, Valid: true},
+		VersionString:   sql.NullString{String: "5.2SE", Valid: true},
+		OriginalName:    sql.NullString{String: "SkyUI_5_2_SE-12604-5-2SE.7z", Valid: true},
+		ArchiveSha256:   "abc",
+		SizeBytes:       1234,
+	}
+
+	m := collectionMod(row)
+	assert.Equal(t, "SkyUI", m.Name)
+	assert.Equal(t, "5.2SE", m.Version)
+	assert.True(t, m.Enabled)
+	require.NotNil(t, m.Nexus)
+	assert.Equal(t, int64(12604), m.Nexus.ModID)
+	assert.Equal(t, int64(35407), m.Nexus.FileID)
+	assert.Equal(t, "https://www.nexusmods.com/skyrimspecialedition/mods/12604?tab=files&file_id=35407", m.Nexus.URL)
+
+	// without a file id the files tab is linked
+	row.NexusFileID = sql.NullInt64{}
+	m = collectionMod(row)
+	require.NotNil(t, m.Nexus)
+	assert.Zero(t, m.Nexus.FileID)
+	assert.Equal(t, "https://www.nexusmods.com/skyrimspecialedition/mods/12604?tab=files", m.Nexus.URL)
+
+	// the url of the file is preferred over the one of the mod page
+	local := dbq.ListProfileItemsForCollectionRow{
+		ModName:       "patch",
+		SourceKind:    "url",
+		SourceUrl:     sql.NullString{String: "https://example.com/patch", Valid: true},
+		FileSourceUrl: sql.NullString{String: "https://example.com/patch/v2.zip", Valid: true},
+		FileLabel:     "v2",
+		Symlinks:      "skip",
+	}
+	m = collectionMod(local)
+	assert.Nil(t, m.Nexus)
+	assert.False(t, m.Enabled)
+	assert.Equal(t, CollectionSource{Kind: "url", URL: "https://example.com/patch/v2.zip"}, m.Source)
+
+	local.FileSourceUrl = sql.NullString{}
+	assert.Equal(t, "https://example.com/patch", collectionMod(local).Source.URL)
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), `"nexus"`)
+	assert.NotContains(t, string(b), `"rules"`)
+}
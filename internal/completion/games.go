@@ -25,20 +25,10 @@ import (
 
 	"github.com/mfinelli/modctl/dbq"
 	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/state"
 	"github.com/spf13/cobra"
 )
 
-func likePrefixPattern(s string) string {
-	// Escape LIKE wildcards so user input is treated literally.
-	// Then append % for prefix match.
-	repl := strings.NewReplacer(
-		`\`, `\\`,
-		`%`, `\%`,
-		`_`, `\_`,
-	)
-	return repl.Replace(s) + `%`
-}
-
 // GameInstallSelectors completes "games set-active <selector>".
 // It returns *full selectors* (always includes #instance) with a description.
 func GameInstallSelectors(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -51,7 +41,7 @@ func GameInstallSelectors(cmd *cobra.Command, toComplete string) ([]string, cobr
 	}
 	defer db.Close()
 
-	pat := likePrefixPattern(strings.TrimSpace(toComplete))
+	pat := internal.LikePrefixPattern(strings.TrimSpace(toComplete))
 
 	q := dbq.New(db)
 	rows, err := q.CompleteGameInstallsByPrefix(ctx, pat)
@@ -73,3 +63,27 @@ func GameInstallSelectors(cmd *cobra.Command, toComplete string) ([]string, cobr
 
 	return out, cobra.ShellCompDirectiveNoFileComp
 }
+
+// resolveGameInstall resolves the game install that completions should be
+// scoped to: the --game flag (an id or a selector) if the command has one and
+// it was given, otherwise the active game. All completions that are scoped to
+// a game go through here so that they agree with what the command will do.
+//
+// N.B. cmd.Flags() also contains the persistent flags inherited from parent
+// commands once cobra has parsed the command line (which it has by the time
+// completion functions are called).
+func resolveGameInstall(ctx context.Context, q *dbq.Queries, cmd *cobra.Command) (int64, bool) {
+	if f := cmd.Flags().Lookup("game"); f != nil && f.Changed {
+		gi, err := internal.ResolveGameInstallArg(ctx, q, f.Value.String())
+		if err != nil {
+			return 0, false
+		}
+		return gi.ID, true
+	}
+
+	active, err := state.LoadActive()
+	if err != nil || active.ActiveGameInstallID <= 0 {
+		return 0, false
+	}
+	return active.ActiveGameInstallID, true
+}
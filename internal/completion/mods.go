@@ -0,0 +1,168 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package completion
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/spf13/cobra"
+)
+
+// ModPages completes mod page ids (described by the mod name) for the
+// current game. If what has been typed so far isn't a number it completes
+// mod names instead.
+func ModPages(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := context.Background()
+
+	db, err := internal.SetupDBReadOnly()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer db.Close()
+
+	q := dbq.New(db)
+	gameID, ok := resolveGameInstall(ctx, q, cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	byName := toComplete != ""
+	if _, err := strconv.ParseInt(toComplete, 10, 64); err == nil {
+		byName = false
+	}
+
+	// the prefix search happens in sqlite (on an index for names) so that
+	// completion stays fast with huge libraries
+	pattern := internal.LikePrefixPattern(toComplete)
+	if byName {
+		rows, err := q.CompleteModPagesForGame(ctx, dbq.CompleteModPagesForGameParams{
+			GameInstallID: gameID,
+			Pattern:       pattern,
+		})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		out := make([]string, 0, len(rows))
+		for _, r := range rows {
+			out = append(out, fmt.Sprintf("%s\t%d", r.Name, r.ID))
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rows, err := q.CompleteModPageIDsForGame(ctx, dbq.CompleteModPageIDsForGameParams{
+		GameInstallID: gameID,
+		Pattern:       pattern,
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	out := make([]string, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fmt.Sprintf("%d\t%s", r.ID, r.Name))
+	}
+
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// ModFileVersions completes mod_file_version ids for the current game.
+// Returns candidates in "id\tMod Name / File label version" format.
+func ModFileVersions(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := context.Background()
+
+	db, err := internal.SetupDBReadOnly()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer db.Close()
+
+	q := dbq.New(db)
+	gameID, ok := resolveGameInstall(ctx, q, cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rows, err := q.CompleteModFileVersionsForGame(ctx, dbq.CompleteModFileVersionsForGameParams{
+		GameInstallID: gameID,
+		Pattern:       internal.LikePrefixPattern(toComplete),
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	out := make([]string, 0, len(rows))
+	for _, r := range rows {
+		desc := r.ModName + " / " + r.FileLabel
+		if r.VersionString.Valid && r.VersionString.String != "" {
+			desc += " " + r.VersionString.String
+		}
+		out = append(out, fmt.Sprintf("%d\t%s", r.ID, desc))
+	}
+
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// ArchiveSHAs completes the sha256 of archives imported for the current
+// game. Returns candidates in "sha256\tMod Name" format.
+func ArchiveSHAs(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := context.Background()
+
+	db, err := internal.SetupDBReadOnly()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer db.Close()
+
+	q := dbq.New(db)
+	gameID, ok := resolveGameInstall(ctx, q, cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rows, err := q.CompleteArchiveBlobsForGame(ctx, dbq.CompleteArchiveBlobsForGameParams{
+		GameInstallID: gameID,
+		Pattern:       internal.GlobPrefixPattern(strings.ToLower(strings.TrimSpace(toComplete))),
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	out := make([]string, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fmt.Sprintf("%s\t%s", r.ArchiveSha256, r.ModName))
+	}
+
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// ModPagesOrArchives completes mod page ids/names, and archive hashes once
+// what has been typed so far can only be a hash prefix.
+func ModPagesOrArchives(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	out, directive := ModPages(cmd, toComplete)
+	if len(out) == 0 && len(toComplete) >= 4 {
+		return ArchiveSHAs(cmd, toComplete)
+	}
+	return out, directive
+}
@@ -25,19 +25,12 @@ import (
 
 	"github.com/mfinelli/modctl/dbq"
 	"github.com/mfinelli/modctl/internal"
-	"github.com/mfinelli/modctl/internal/state"
 	"github.com/spf13/cobra"
 )
 
 // ProfileNames completes profile names for the current game install.
-// If the command has a --game flag set, it is used; otherwise the active game
-// is used.
-//
-// N.B. that at the moment we're using Flags() on all of the profile commands
-//
-//	individually (instead of PersistentFlags() on the profiles root
-//	command) which means if we ever switch to PersistentFlags we need to
-//	update here to use cmd.InheritedFlags() instead of cmd.Flags().
+// If the command has a --game flag set (an id or a selector), it is used;
+// otherwise the active game is used.
 //
 // Returns candidates in "name\t(active)" format.
 func ProfileNames(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -49,23 +42,12 @@ func ProfileNames(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellC
 	}
 	defer db.Close()
 
-	// Resolve game install id for completion scope.
-	var gameID int64
-	if f := cmd.Flags().Lookup("game"); f != nil && f.Changed {
-		v, err := cmd.Flags().GetInt64("game")
-		if err != nil || v <= 0 {
-			return nil, cobra.ShellCompDirectiveNoFileComp
-		}
-		gameID = v
-	} else {
-		active, err := state.LoadActive()
-		if err != nil || active.ActiveGameInstallID <= 0 {
-			return nil, cobra.ShellCompDirectiveNoFileComp
-		}
-		gameID = active.ActiveGameInstallID
+	q := dbq.New(db)
+	gameID, ok := resolveGameInstall(ctx, q, cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	q := dbq.New(db)
 	rows, err := q.ListProfilesForCompletion(ctx, gameID)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -0,0 +1,62 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package completion
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/spf13/cobra"
+)
+
+// TargetNames completes the target names of the current game install (the
+// --game flag if the command has one set, otherwise the active game).
+//
+// Returns candidates in "name\troot path" format.
+func TargetNames(cmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := context.Background()
+
+	db, err := internal.SetupDBReadOnly()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer db.Close()
+
+	q := dbq.New(db)
+	gameID, ok := resolveGameInstall(ctx, q, cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rows, err := q.ListTargetsForGameInstall(ctx, gameID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	out := make([]string, 0, len(rows))
+	for _, t := range rows {
+		if strings.HasPrefix(t.Name, toComplete) {
+			out = append(out, t.Name+"\t"+t.RootPath)
+		}
+	}
+
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,245 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/mfinelli/modctl/internal/loot"
+	"github.com/spf13/viper"
+)
+
+// SetConfigDefaults sets the default of every config option. The config
+// file (if any) is read on top of them.
+func SetConfigDefaults() error {
+	// if unspecified just search $PATH
+	viper.SetDefault("bsdtar", "bsdtar")
+
+	// how archives that modctl creates itself are compressed
+	viper.SetDefault("archive_compression", "zstd:19")
+
+	// the database and the stores are in here unless they're configured on
+	// their own; --data-dir or $MODCTL_DATA_DIR point modctl at another one
+	// (e.g., to keep a separate set of mods for testing, or on an external
+	// drive)
+	viper.SetDefault("data_dir", filepath.Join(xdg.DataHome, "modctl"))
+	if err := viper.BindEnv("data_dir", "MODCTL_DATA_DIR"); err != nil {
+		return fmt.Errorf("bind MODCTL_DATA_DIR: %w", err)
+	}
+	setDataDirDefaults()
+
+	// remove what modctl left in tmp_dir (e.g., after a crash) once it's
+	// this old, when a command that changes something starts (0: never)
+	viper.SetDefault("tmp_max_age", "24h")
+
+	// an archive store that the users of the machine share (e.g.,
+	// /srv/modctl/archives, with the layout of archives_dir): it's only
+	// read, before archives_dir, and archives that it has aren't stored
+	// again (empty: none)
+	viper.SetDefault("shared_archives_dir", "")
+
+	// on-disk cache of API responses (e.g., nexus mod metadata), in
+	// http_cache_dir (see setDataDirDefaults)
+	viper.SetDefault("http_cache", true)
+
+	viper.SetDefault("nexus_api_url", "https://api.nexusmods.com")
+
+	// don't use the network (like --offline): nexus requests are answered
+	// from http_cache_dir and downloads are refused
+	viper.SetDefault("offline", false)
+
+	// how many nexus api requests to always keep in reserve, and how long
+	// to wait for the rate limits to reset before deferring a request
+	viper.SetDefault("nexus_rate_limit_reserve", 20)
+	viper.SetDefault("nexus_rate_limit_max_wait", "0s")
+
+	// how fast files are downloaded (bytes per second, e.g., "5M"; 0: no
+	// limit) and how many at the same time (0: no limit), overall; hosts
+	// can be limited further (see `modctl nexus download-limits`)
+	viper.SetDefault("download_limit_rate", "0")
+	viper.SetDefault("download_concurrency", 2)
+
+	// where apply fetches archives that are missing from archives_dir (or
+	// were quarantined): other modctl data dirs, archives dirs (e.g., a NAS
+	// export), or http(s) urls with the same layout, tried in order
+	viper.SetDefault("blob_mirrors", []string{})
+
+	// where to store credentials: "keyring" or "env"
+	viper.SetDefault("secrets_provider", "keyring")
+
+	// proton script used to run windows tools (e.g., redmod); empty means
+	// the newest proton in the game's steam library
+	viper.SetDefault("proton", "")
+
+	// steam account whose userdata ${userdata} targets point into (login
+	// name, persona name, or id); empty means the account that steam
+	// installed the game for, or else the one that logged in last
+	viper.SetDefault("steam_account", "")
+
+	// make `profiles set-active` deploy the profile as well
+	viper.SetDefault("apply_on_switch", false)
+
+	// recognize vanilla files of steam games with steam's depot manifests
+	// and don't back them up
+	viper.SetDefault("steam_depot_manifests", false)
+
+	// record what is in the game directories before the first apply so
+	// that files can be told apart later (see `modctl games scan`)
+	viper.SetDefault("baseline_on_apply", true)
+
+	// refresh the query planner statistics and truncate the WAL after
+	// operations that change at least this many rows (0: never)
+	viper.SetDefault("auto_optimize_rows", 1000)
+
+	// log how long every database query takes to this file, to find the
+	// slow ones with `modctl db analyze` (empty: don't)
+	viper.SetDefault("query_log", "")
+
+	// how blobs are read while they're stored, verified, or hashed (see
+	// `modctl config bench-io` to compare settings): the buffer size,
+	// O_DIRECT, a posix_fadvise hint (sequential, noreuse, or dontneed),
+	// and how far ahead to ask the kernel to read (0: its default)
+	viper.SetDefault("io_buffer_size", "1MiB")
+	viper.SetDefault("io_direct", false)
+	viper.SetDefault("io_fadvise", "")
+	viper.SetDefault("io_readahead", "0")
+
+	// how to run LOOT to sort plugins (see `modctl plugins sort --help`)
+	viper.SetDefault("loot_command", loot.DefaultCommand)
+
+	// sort the plugin load order with LOOT after an apply that changed
+	// files, like `modctl plugins sort`
+	viper.SetDefault("loot_after_apply", false)
+
+	// run REDmod's deploy step (like `modctl redmod deploy`) after an apply
+	// that changed REDmod content of cyberpunk 2077
+	viper.SetDefault("redmod_deploy_after_apply", true)
+
+	// how to mount the layer of an overlay target over it and unmount it
+	// again (see `modctl games target-strategy --help`)
+	viper.SetDefault("overlay_mount_command", []string{
+		"fuse-overlayfs", "-o", "lowerdir=${files}:${root},upperdir=${upper},workdir=${work}", "${root}",
+	})
+	viper.SetDefault("overlay_unmount_command", []string{"fusermount3", "-u", "${root}"})
+
+	// scan archives when they're imported (e.g., with clamscan, see
+	// `modctl mods import --help`; empty: don't)
+	viper.SetDefault("scan_command", []string{})
+
+	// how to merge witcher 3 scripts (see `modctl witcher3 merge --help`)
+	viper.SetDefault("witcher3_merge_command", []string{
+		"kdiff3", "${base}", "${ours}", "${theirs}", "-o", "${output}",
+	})
+
+	// where `modctl advisories update` downloads the advisory feed from: a
+	// url or a local file (empty: none)
+	viper.SetDefault("advisory_feed", "")
+
+	// what to run when modctl is run without any arguments (e.g.,
+	// "status"; empty: show the help)
+	viper.SetDefault("default_command", "")
+
+	return nil
+}
+
+// setDataDirDefaults sets the defaults of the paths in the data directory.
+func setDataDirDefaults() {
+	dir := viper.GetString("data_dir")
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	viper.SetDefault("database", filepath.Join(dir, "modctl.db"))
+	viper.SetDefault("archives_dir", filepath.Join(dir, "archives"))
+	viper.SetDefault("backups_dir", filepath.Join(dir, "backups"))
+	viper.SetDefault("overrides_dir", filepath.Join(dir, "overrides"))
+	viper.SetDefault("extracted_dir", filepath.Join(dir, "extracted"))
+	viper.SetDefault("overlays_dir", filepath.Join(dir, "overlays"))
+	viper.SetDefault("tmp_dir", filepath.Join(dir, "tmp"))
+	viper.SetDefault("quarantine_dir", filepath.Join(dir, "quarantine"))
+
+	// the state (the active selection, the lock, the http cache, apply
+	// reports, and the shell history) of the default data directory is in
+	// $XDG_STATE_HOME, the one of any other data directory in it: separate
+	// data directories must not share the selection or block each other
+	stateDir := filepath.Join(xdg.StateHome, "modctl")
+	if dir != filepath.Join(xdg.DataHome, "modctl") {
+		stateDir = filepath.Join(dir, "state")
+	}
+	viper.SetDefault("state_dir", stateDir)
+	stateDir = viper.GetString("state_dir")
+	viper.SetDefault("http_cache_dir", filepath.Join(stateDir, "http-cache"))
+	viper.SetDefault("reports_dir", filepath.Join(stateDir, "reports"))
+}
+
+// LoadConfig sets the config defaults and reads the config file on top of
+// them: path if it's given (which then has to exist), otherwise the default
+// config file if there is one.
+func LoadConfig(path string) error {
+	if err := SetConfigDefaults(); err != nil {
+		return err
+	}
+
+	if err := readConfig(path); err != nil {
+		return err
+	}
+	expandConfigPaths()
+
+	// the config file can move the data directory too
+	setDataDirDefaults()
+	return nil
+}
+
+func readConfig(path string) error {
+	if path != "" {
+		// User explicitly provided a config file: it must work.
+		viper.SetConfigFile(path)
+		viper.SetConfigType("toml")
+		return viper.ReadInConfig()
+	}
+
+	defaultPath, err := xdg.ConfigFile(filepath.Join("modctl", "config.toml"))
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(defaultPath); errors.Is(err, os.ErrNotExist) {
+		return nil // default config file doesn't exist -- use defaults
+	}
+
+	viper.SetConfigFile(defaultPath)
+	viper.SetConfigType("toml")
+
+	if err := viper.ReadInConfig(); err != nil {
+		// missing config file is fine -- use the built-in defaults
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+
+		// parse/permission errors should fail loudly
+		return err
+	}
+
+	return nil
+}
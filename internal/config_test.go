@@ -0,0 +1,191 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/lock"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// not parallel: the config is global
+
+func TestLoadConfigDataDirFromEnv(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	dir := t.TempDir()
+	t.Setenv("MODCTL_DATA_DIR", dir)
+
+	cfg := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(cfg, []byte("tmp_dir = \"/scratch\"\n"), 0o644))
+	require.NoError(t, LoadConfig(cfg))
+
+	assert.Equal(t, dir, viper.GetString("data_dir"))
+	assert.Equal(t, filepath.Join(dir, "modctl.db"), viper.GetString("database"))
+	assert.Equal(t, filepath.Join(dir, "archives"), viper.GetString("archives_dir"))
+	assert.Equal(t, filepath.Join(dir, "backups"), viper.GetString("backups_dir"))
+	assert.Equal(t, filepath.Join(dir, "overrides"), viper.GetString("overrides_dir"))
+	// options that are set on their own win
+	assert.Equal(t, "/scratch", viper.GetString("tmp_dir"))
+}
+
+func TestLoadConfigDataDirFromFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	dir := t.TempDir()
+	t.Setenv("MODCTL_DATA_DIR", "")
+
+	cfg := filepath.Join(dir, "config.toml")
+	data := filepath.Join(dir, "external")
+	require.NoError(t, os.WriteFile(cfg, []byte("data_dir = \""+data+"\"\n"), 0o644))
+	require.NoError(t, LoadConfig(cfg))
+
+	assert.Equal(t, filepath.Join(data, "modctl.db"), viper.GetString("database"))
+	assert.Equal(t, filepath.Join(data, "tmp"), viper.GetString("tmp_dir"))
+
+	// --data-dir
+	viper.Set("data_dir", filepath.Join(dir, "flag"))
+	require.NoError(t, LoadConfig(cfg))
+	assert.Equal(t, filepath.Join(dir, "flag", "archives"), viper.GetString("archives_dir"))
+}
+
+func TestDataDirsDontShareState(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(cfg, nil, 0o644))
+
+	load := func(data string) {
+		t.Helper()
+		viper.Reset()
+		t.Setenv("MODCTL_DATA_DIR", data)
+		require.NoError(t, LoadConfig(cfg))
+	}
+
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+
+	load(first)
+	assert.Equal(t, filepath.Join(first, "state"), viper.GetString("state_dir"))
+	assert.Equal(t, filepath.Join(first, "state", "http-cache"), viper.GetString("http_cache_dir"))
+	assert.Equal(t, filepath.Join(first, "state", "reports"), viper.GetString("reports_dir"))
+	require.NoError(t, state.SaveActive(state.Active{ActiveGameInstallID: 3}))
+	l, err := LockState("modctl test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Release() })
+
+	// the second data dir has nothing selected and its own lock
+	load(second)
+	a, err := state.LoadActive()
+	require.NoError(t, err)
+	assert.Zero(t, a.ActiveGameInstallID)
+	l2, err := LockState("modctl test")
+	require.NoError(t, err)
+	require.NoError(t, l2.Release())
+
+	// while the first one still has its own
+	load(first)
+	a, err = state.LoadActive()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), a.ActiveGameInstallID)
+	_, err = LockState("modctl test")
+	var held *lock.HeldError
+	assert.ErrorAs(t, err, &held)
+}
+
+func TestValidateConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	dir := t.TempDir()
+	t.Setenv("MODCTL_DATA_DIR", dir)
+
+	cfg := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(cfg, []byte(`bsdtr = "/usr/bin/bsdtar"
+http_cache = "no"
+secrets_provider = "env"
+archive_compression = "lz4"
+nexus_rate_limit_max_wait = "5 minutes"
+loot_command = []
+
+[target_templates."steam:413150"]
+mods = "../Mods"
+`), 0o644))
+	require.NoError(t, LoadConfig(cfg))
+
+	problems := ValidateConfig(false)
+	byKey := map[string]ConfigProblem{}
+	for _, p := range problems {
+		byKey[p.Key] = p
+	}
+
+	assert.True(t, byKey["bsdtr"].Warning)
+	assert.Contains(t, byKey["bsdtr"].Message, "did you mean bsdtar?")
+	assert.False(t, byKey["http_cache"].Warning)
+	assert.Contains(t, byKey["http_cache"].Message, "true or false")
+	assert.Contains(t, byKey, "archive_compression")
+	assert.Contains(t, byKey, "nexus_rate_limit_max_wait")
+	assert.Contains(t, byKey, "loot_command")
+	assert.Contains(t, byKey, "target_templates")
+	assert.NotContains(t, byKey, "secrets_provider")
+	assert.True(t, HasConfigErrors(problems))
+}
+
+func TestValidateConfigPaths(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MODCTL_DATA_DIR", "")
+
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(cfg, []byte(`data_dir = "~/modctl"
+tmp_dir = "$XDG_RUNTIME_DIR/modctl"
+proton = "/nonexistent/proton"
+`), 0o644))
+	require.NoError(t, LoadConfig(cfg))
+
+	// a leading ~/ is expanded, and the stores follow the data directory
+	assert.Equal(t, filepath.Join(home, "modctl"), viper.GetString("data_dir"))
+	assert.Equal(t, filepath.Join(home, "modctl", "archives"), viper.GetString("archives_dir"))
+
+	problems := ValidateConfig(true)
+	byKey := map[string]ConfigProblem{}
+	for _, p := range problems {
+		byKey[p.Key] = p
+	}
+
+	assert.True(t, byKey["archives_dir"].Warning)
+	assert.Contains(t, byKey["archives_dir"].Message, "doesn't exist yet")
+	assert.False(t, byKey["tmp_dir"].Warning)
+	assert.Contains(t, byKey["tmp_dir"].Message, "isn't expanded")
+	assert.False(t, byKey["proton"].Warning)
+	assert.NotContains(t, byKey, "query_log")
+}
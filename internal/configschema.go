@@ -0,0 +1,524 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/spf13/viper"
+)
+
+// configType is the type of the value of a config option.
+type configType int
+
+const (
+	configString configType = iota
+	configBool
+	configInt
+	configDuration
+	// a directory (modctl creates it if it's missing)
+	configDir
+	// a file that modctl creates if it's missing
+	configFile
+	// a file that has to exist (if it's set)
+	configExistingFile
+	// a list of arguments, the first one is the program
+	configCommand
+	configTable
+	// a size in bytes (an integer, or a string with a unit, see ParseBytes)
+	configSize
+	// a list of strings (that can be empty)
+	configList
+)
+
+func (t configType) String() string {
+	switch t {
+	case configBool:
+		return "true or false"
+	case configInt:
+		return "an integer"
+	case configDuration:
+		return `a duration (e.g., "90s" or "5m")`
+	case configCommand:
+		return `a list of arguments (e.g., ["program", "--flag"])`
+	case configTable:
+		return "a table"
+	case configSize:
+		return `a size (e.g., "1MiB" or 1048576)`
+	case configList:
+		return `a list of strings (e.g., ["a", "b"])`
+	default:
+		return "a string"
+	}
+}
+
+// configOption describes a config option: the type of its value, and any
+// further checks of the value.
+type configOption struct {
+	Type  configType
+	Check func(v any) error
+}
+
+// configSchema has every config option (see SetConfigDefaults).
+var configSchema = map[string]configOption{
+	"bsdtar":                    {Type: configString},
+	"archive_compression":       {Type: configString, Check: checkCompression},
+	"data_dir":                  {Type: configDir},
+	"database":                  {Type: configFile},
+	"archives_dir":              {Type: configDir},
+	"backups_dir":               {Type: configDir},
+	"overrides_dir":             {Type: configDir},
+	"extracted_dir":             {Type: configDir},
+	"overlays_dir":              {Type: configDir},
+	"tmp_dir":                   {Type: configDir},
+	"quarantine_dir":            {Type: configDir},
+	"state_dir":                 {Type: configDir},
+	"tmp_max_age":               {Type: configDuration},
+	"shared_archives_dir":       {Type: configString, Check: checkAbsPath},
+	"http_cache":                {Type: configBool},
+	"http_cache_dir":            {Type: configDir},
+	"reports_dir":               {Type: configDir},
+	"nexus_api_url":             {Type: configString, Check: checkHTTPURL},
+	"offline":                   {Type: configBool},
+	"nexus_rate_limit_reserve":  {Type: configInt, Check: checkNotNegative},
+	"nexus_rate_limit_max_wait": {Type: configDuration},
+	"download_limit_rate":       {Type: configSize},
+	"download_concurrency":      {Type: configInt, Check: checkNotNegative},
+	"blob_mirrors":              {Type: configList, Check: checkBlobMirrors},
+	"secrets_provider":          {Type: configString, Check: checkOneOf("keyring", "env")},
+	"proton":                    {Type: configExistingFile},
+	"steam_account":             {Type: configString},
+	"apply_on_switch":           {Type: configBool},
+	"steam_depot_manifests":     {Type: configBool},
+	"baseline_on_apply":         {Type: configBool},
+	"auto_optimize_rows":        {Type: configInt, Check: checkNotNegative},
+	"query_log":                 {Type: configFile},
+	"io_buffer_size":            {Type: configSize, Check: checkIOBufferSize},
+	"io_direct":                 {Type: configBool},
+	"io_fadvise":                {Type: configString, Check: checkOneOf(blobstore.FadviseModes...)},
+	"io_readahead":              {Type: configSize},
+	"loot_command":              {Type: configCommand},
+	"loot_after_apply":          {Type: configBool},
+	"redmod_deploy_after_apply": {Type: configBool},
+	"witcher3_merge_command":    {Type: configCommand},
+	"scan_command":              {Type: configCommand},
+	"overlay_mount_command":     {Type: configCommand},
+	"overlay_unmount_command":   {Type: configCommand},
+	"target_templates":          {Type: configTable, Check: checkTargetTemplates},
+	"override_vars":             {Type: configTable, Check: checkOverrideVars},
+	"aliases":                   {Type: configTable, Check: checkAliases},
+	"default_command":           {Type: configString, Check: checkDefaultCommand},
+	"advisory_feed":             {Type: configString, Check: checkAdvisoryFeed},
+}
+
+// ConfigProblem is something wrong with the config.
+type ConfigProblem struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+	// the option is still usable (e.g., a directory that doesn't exist yet)
+	Warning bool `json:"warning,omitempty"`
+}
+
+func (p ConfigProblem) String() string {
+	return p.Key + ": " + p.Message
+}
+
+// ValidateConfig checks the loaded config against the schema: unknown keys
+// (probably typos, they'd be ignored) are warnings and values of the wrong
+// type or that don't make sense are errors. With paths it also checks that
+// the configured paths exist, which is slower and only relevant to some
+// commands.
+func ValidateConfig(paths bool) []ConfigProblem {
+	var problems []ConfigProblem
+
+	keys := make([]string, 0)
+	for k := range viper.AllSettings() {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		opt, ok := configSchema[key]
+		if !ok {
+			msg := "unknown option, it's ignored"
+			if s := suggestConfigKey(key); s != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", s)
+			}
+			problems = append(problems, ConfigProblem{Key: key, Message: msg, Warning: true})
+			continue
+		}
+
+		// the defaults are fine, only check what the user set
+		if viper.InConfig(key) {
+			if err := checkConfigValue(opt, viper.Get(key)); err != nil {
+				problems = append(problems, ConfigProblem{Key: key, Message: err.Error()})
+				continue
+			}
+		}
+
+		if paths {
+			problems = append(problems, checkConfigPath(key, opt)...)
+		}
+	}
+
+	return problems
+}
+
+// HasConfigErrors reports whether any of the problems is an error.
+func HasConfigErrors(problems []ConfigProblem) bool {
+	return slices.ContainsFunc(problems, func(p ConfigProblem) bool {
+		return !p.Warning
+	})
+}
+
+func checkConfigValue(opt configOption, v any) error {
+	ok := true
+	switch opt.Type {
+	case configBool:
+		_, ok = v.(bool)
+	case configInt:
+		switch v.(type) {
+		case int, int64:
+		default:
+			ok = false
+		}
+	case configDuration:
+		s, isString := v.(string)
+		if !isString {
+			ok = false
+			break
+		}
+		if _, err := time.ParseDuration(s); err != nil {
+			return fmt.Errorf("must be %s: %q", opt.Type, s)
+		}
+	case configCommand:
+		args, isList := v.([]any)
+		if !isList || len(args) == 0 {
+			ok = false
+			break
+		}
+		for _, a := range args {
+			if s, isString := a.(string); !isString || s == "" {
+				return errors.New("every argument must be a non-empty string")
+			}
+		}
+	case configTable:
+		_, ok = v.(map[string]any)
+	case configList:
+		items, isList := v.([]any)
+		if !isList {
+			ok = false
+			break
+		}
+		for _, it := range items {
+			if s, isString := it.(string); !isString || s == "" {
+				return errors.New("every entry must be a non-empty string")
+			}
+		}
+	case configSize:
+		switch s := v.(type) {
+		case int, int64:
+		case string:
+			if _, err := ParseBytes(s); err != nil {
+				return fmt.Errorf("must be %s: %q", opt.Type, s)
+			}
+		default:
+			ok = false
+		}
+	default:
+		_, ok = v.(string)
+	}
+	if !ok {
+		return fmt.Errorf("must be %s, not %v", opt.Type, v)
+	}
+
+	if opt.Check != nil {
+		return opt.Check(v)
+	}
+	return nil
+}
+
+// checkConfigPath checks the (effective) value of a path option.
+func checkConfigPath(key string, opt configOption) []ConfigProblem {
+	switch opt.Type {
+	case configDir, configFile, configExistingFile:
+	default:
+		return nil
+	}
+
+	p := viper.GetString(key)
+	if p == "" {
+		// only optional files can be empty
+		if opt.Type == configDir {
+			return []ConfigProblem{{Key: key, Message: "must not be empty"}}
+		}
+		return nil
+	}
+
+	if strings.Contains(p, "$") || strings.HasPrefix(p, "~") {
+		return []ConfigProblem{{
+			Key:     key,
+			Message: fmt.Sprintf("%s isn't expanded: only a leading ~/ is, use an absolute path", p),
+		}}
+	}
+
+	var problems []ConfigProblem
+	if !filepath.IsAbs(p) {
+		problems = append(problems, ConfigProblem{
+			Key:     key,
+			Message: fmt.Sprintf("%s is relative to the current directory", p),
+			Warning: true,
+		})
+	}
+
+	fi, err := os.Stat(p)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		switch opt.Type {
+		case configExistingFile:
+			problems = append(problems, ConfigProblem{Key: key, Message: p + " doesn't exist"})
+		case configDir:
+			problems = append(problems, ConfigProblem{
+				Key:     key,
+				Message: p + " doesn't exist yet (`modctl init` creates it)",
+				Warning: true,
+			})
+		case configFile:
+			if _, err := os.Stat(filepath.Dir(p)); err != nil {
+				problems = append(problems, ConfigProblem{
+					Key:     key,
+					Message: filepath.Dir(p) + " doesn't exist yet",
+					Warning: true,
+				})
+			}
+		}
+	case err != nil:
+		problems = append(problems, ConfigProblem{Key: key, Message: err.Error()})
+	case opt.Type == configDir && !fi.IsDir():
+		problems = append(problems, ConfigProblem{Key: key, Message: p + " isn't a directory"})
+	case opt.Type != configDir && fi.IsDir():
+		problems = append(problems, ConfigProblem{Key: key, Message: p + " is a directory"})
+	}
+
+	return problems
+}
+
+// expandConfigPaths expands a leading ~/ in the path options.
+func expandConfigPaths() {
+	for key, opt := range configSchema {
+		switch opt.Type {
+		case configDir, configFile, configExistingFile:
+		default:
+			continue
+		}
+
+		p := viper.GetString(key)
+		if e := expandHome(p); e != p {
+			viper.Set(key, e)
+		}
+	}
+}
+
+func checkCompression(v any) error {
+	_, err := archive.ParseCompression(v.(string))
+	return err
+}
+
+// checkIOBufferSize checks that the buffer is reasonably sized and works
+// with io_direct.
+func checkIOBufferSize(v any) error {
+	n, err := configBytes(v)
+	if err != nil {
+		return err
+	}
+	if n < blobstore.DirectAlignment || n > 256<<20 || n%blobstore.DirectAlignment != 0 {
+		return fmt.Errorf("must be a multiple of 4KiB between 4KiB and 256MiB: %v", v)
+	}
+	return nil
+}
+
+func checkHTTPURL(v any) error {
+	u, err := url.Parse(v.(string))
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) url: %q", v)
+	}
+	return nil
+}
+
+// checkBlobMirrors accepts http(s) urls and absolute local paths (which only
+// have to exist once an archive is fetched from them).
+func checkBlobMirrors(v any) error {
+	for _, it := range v.([]any) {
+		m := it.(string)
+		if strings.Contains(m, "://") {
+			if err := checkHTTPURL(m); err != nil {
+				return err
+			}
+			continue
+		}
+		if !filepath.IsAbs(m) {
+			return fmt.Errorf("must be an http(s) url or an absolute path: %q", m)
+		}
+	}
+	return nil
+}
+
+// checkAbsPath accepts an absolute path, or nothing.
+func checkAbsPath(v any) error {
+	if s := v.(string); s != "" && !filepath.IsAbs(s) {
+		return fmt.Errorf("must be an absolute path: %q", s)
+	}
+	return nil
+}
+
+// checkAdvisoryFeed accepts an http(s) url or a local file (which only has
+// to exist once the feed is updated).
+func checkAdvisoryFeed(v any) error {
+	s := v.(string)
+	if strings.Contains(s, "://") && !strings.HasPrefix(s, "file://") {
+		return checkHTTPURL(s)
+	}
+	return nil
+}
+
+func checkNotNegative(v any) error {
+	var n int64
+	switch i := v.(type) {
+	case int:
+		n = int64(i)
+	case int64:
+		n = i
+	}
+	if n < 0 {
+		return fmt.Errorf("must not be negative: %d", n)
+	}
+	return nil
+}
+
+func checkOneOf(values ...string) func(v any) error {
+	return func(v any) error {
+		if !slices.Contains(values, v.(string)) {
+			return fmt.Errorf("must be one of %s: %q", strings.Join(values, ", "), v)
+		}
+		return nil
+	}
+}
+
+// checkTargetTemplates checks every configured target template, not just
+// the ones of the games that are installed (see TargetTemplates).
+func checkTargetTemplates(v any) error {
+	for game, t := range v.(map[string]any) {
+		m, ok := t.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%q must be a table of target = \"path\"", game)
+		}
+		for name, rel := range m {
+			s, ok := rel.(string)
+			if !ok {
+				return fmt.Errorf("%s.%s must be a string", game, name)
+			}
+			if s == "" {
+				continue
+			}
+			name = strings.ToLower(name)
+			if name == plan.DefaultTarget || !targetNamePattern.MatchString(name) {
+				return fmt.Errorf("%s: invalid target name %q", game, name)
+			}
+			if err := validateTargetTemplate(s); err != nil {
+				return fmt.Errorf("%s.%s: %w", game, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkOverrideVars checks the variables for every game, and the tables of
+// the games and installs that have their own (see OverrideVars).
+func checkOverrideVars(v any) error {
+	m := v.(map[string]any)
+	if err := overrideVarsTable(m, map[string]string{}); err != nil {
+		return err
+	}
+	for k, t := range m {
+		t, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, _, _, err := ParseSelector(k); err != nil {
+			return fmt.Errorf("%q: tables are for a game (store:game) or an install (store:game#instance)", k)
+		}
+		for name, val := range t {
+			if _, ok := val.(map[string]any); ok {
+				return fmt.Errorf("%s.%s must be a string", k, name)
+			}
+		}
+		if err := overrideVarsTable(t, map[string]string{}); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// suggestConfigKey returns the option that key is most likely a typo of, if
+// any.
+func suggestConfigKey(key string) string {
+	best, bestDist := "", len(key)/3+1
+	for k := range configSchema {
+		if d := editDistance(key, k); d < bestDist || (d == bestDist && best != "" && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance is the levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
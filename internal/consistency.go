@@ -0,0 +1,281 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/state"
+	"go.finelli.dev/util"
+)
+
+// ConsistencyIssue is a mismatch between active.json, the database, and the
+// blob store, with a hint on how to fix it.
+type ConsistencyIssue struct {
+	Check   string `json:"check"`
+	Problem string `json:"problem"`
+	Hint    string `json:"hint"`
+}
+
+// StateSnapshot is everything CheckConsistency looks at. BlobOnDisk reports
+// whether an archive blob exists in the blob store.
+type StateSnapshot struct {
+	Active        state.Active
+	ActiveProblem error // why active.json can't be used, if it can't
+	// the copy of the active selection in the database (see SyncActive)
+	DBActive        state.Active
+	DBActiveOK      bool
+	DBActiveProblem error
+	Installs        []dbq.GameInstall
+	Profiles        []dbq.Profile
+	ProfileItems    []dbq.ListProfileItemArchivesRow
+	BlobOnDisk      func(sha256 string) bool
+}
+
+// CheckConsistency verifies that active.json can be used, agrees with the
+// database, and points at a game install that still exists and is present,
+// that every game install has exactly one active profile, that every
+// profile item references a mod file version whose archive is recorded, not
+// corrupted, and on disk, that no two items of a profile share a priority or
+// enable two versions of the same mod file, and that no applied profile is
+// empty.
+func CheckConsistency(s StateSnapshot) []ConsistencyIssue {
+	var issues []ConsistencyIssue
+
+	installs := make(map[int64]dbq.GameInstall, len(s.Installs))
+	for _, gi := range s.Installs {
+		installs[gi.ID] = gi
+	}
+
+	// active.json
+	if s.ActiveProblem != nil {
+		issues = append(issues, ConsistencyIssue{
+			Check:   "active",
+			Problem: fmt.Sprintf("active.json is ignored: %v", s.ActiveProblem),
+			Hint:    "run `modctl context repair` to reset it",
+		})
+	}
+	if s.DBActiveProblem != nil {
+		issues = append(issues, ConsistencyIssue{
+			Check:   "active",
+			Problem: fmt.Sprintf("the active selection in the database can't be read: %v", s.DBActiveProblem),
+			Hint:    "run `modctl context repair` to replace it",
+		})
+	} else if s.DBActiveOK && s.ActiveProblem == nil &&
+		(s.DBActive.ActiveGameInstallID != s.Active.ActiveGameInstallID ||
+			s.DBActive.ActiveStoreID != s.Active.ActiveStoreID) {
+		issues = append(issues, ConsistencyIssue{
+			Check: "active",
+			Problem: fmt.Sprintf("active.json (game install %d) and the database (game install %d) disagree on the active selection",
+				s.Active.ActiveGameInstallID, s.DBActive.ActiveGameInstallID),
+			Hint: "run any modctl command that opens the database (e.g., `modctl status`) to copy the newer one over the other",
+		})
+	}
+	if id := s.Active.ActiveGameInstallID; id != 0 {
+		gi, ok := installs[id]
+		switch {
+		case !ok:
+			issues = append(issues, ConsistencyIssue{
+				Check: "active",
+				Problem: fmt.Sprintf("active game install %d (%s) no longer exists",
+					id, s.Active.ActiveGameInstallSelector),
+				Hint: "run `modctl context repair`, or `modctl games set-active <selector>` to choose another game",
+			})
+		case !util.SqliteIntToBool(gi.IsPresent):
+			issues = append(issues, ConsistencyIssue{
+				Check:   "active",
+				Problem: fmt.Sprintf("active game %s was not found during the last refresh", gameLabel(gi)),
+				Hint:    "run `modctl games refresh` if it was reinstalled, otherwise `modctl games set-active <selector>`",
+			})
+		default:
+			sel := FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
+			if s.Active.ActiveGameInstallSelector != "" && s.Active.ActiveGameInstallSelector != sel {
+				issues = append(issues, ConsistencyIssue{
+					Check: "active",
+					Problem: fmt.Sprintf("active.json selector %s does not match game install %d (%s)",
+						s.Active.ActiveGameInstallSelector, id, sel),
+					Hint: fmt.Sprintf("run `modctl games set-active %s` to rewrite active.json", sel),
+				})
+			}
+		}
+	}
+
+	// exactly one active profile per game
+	activeProfiles := make(map[int64][]string)
+	for _, p := range s.Profiles {
+		if util.SqliteIntToBool(p.IsActive) {
+			activeProfiles[p.GameInstallID] = append(activeProfiles[p.GameInstallID], p.Name)
+		}
+	}
+	for _, gi := range s.Installs {
+		sel := FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)
+		switch names := activeProfiles[gi.ID]; len(names) {
+		case 1:
+		case 0:
+			issues = append(issues, ConsistencyIssue{
+				Check:   "profiles",
+				Problem: fmt.Sprintf("%s has no active profile", gameLabel(gi)),
+				Hint:    fmt.Sprintf("run `modctl profiles set-active <name> --game %s`", sel),
+			})
+		default:
+			issues = append(issues, ConsistencyIssue{
+				Check:   "profiles",
+				Problem: fmt.Sprintf("%s has %d active profiles: %v", gameLabel(gi), len(names), names),
+				Hint:    fmt.Sprintf("run `modctl profiles set-active <name> --game %s` to pick one", sel),
+			})
+		}
+	}
+
+	// profile items -> versions -> blobs
+	for _, it := range s.ProfileItems {
+		where := fmt.Sprintf("profile %q (game %d)", it.ProfileName, it.GameInstallID)
+		removeHint := fmt.Sprintf("run `modctl profiles remove %d --profile %s` or re-import the archive with `modctl mods import`",
+			it.ModFileVersionID, it.ProfileName)
+
+		switch {
+		case !util.SqliteIntToBool(it.VersionExists):
+			issues = append(issues, ConsistencyIssue{
+				Check:   "profile_items",
+				Problem: fmt.Sprintf("%s references missing mod file version %d", where, it.ModFileVersionID),
+				Hint:    fmt.Sprintf("run `modctl profiles remove %d --profile %s`", it.ModFileVersionID, it.ProfileName),
+			})
+		case !util.SqliteIntToBool(it.BlobRecorded):
+			issues = append(issues, ConsistencyIssue{
+				Check: "profile_items",
+				Problem: fmt.Sprintf("%s: version %d references archive %s which is not in the blobs table",
+					where, it.ModFileVersionID, shortSHA(it.ArchiveSha256)),
+				Hint: removeHint,
+			})
+		case util.SqliteIntToBool(it.BlobCorrupted):
+			issues = append(issues, ConsistencyIssue{
+				Check: "profile_items",
+				Problem: fmt.Sprintf("%s: archive %s for version %d is corrupted (quarantined)",
+					where, shortSHA(it.ArchiveSha256), it.ModFileVersionID),
+				Hint: fmt.Sprintf("run `modctl mods repair` or `modctl profiles remove %d --profile %s`",
+					it.ModFileVersionID, it.ProfileName),
+			})
+		case s.BlobOnDisk != nil && !s.BlobOnDisk(it.ArchiveSha256):
+			issues = append(issues, ConsistencyIssue{
+				Check: "profile_items",
+				Problem: fmt.Sprintf("%s: archive %s for version %d is missing from the blob store",
+					where, shortSHA(it.ArchiveSha256), it.ModFileVersionID),
+				Hint: removeHint,
+			})
+		}
+	}
+
+	issues = append(issues, checkProfileItems(s)...)
+
+	return issues
+}
+
+// checkProfileItems looks for items of the same profile that share a
+// priority (the database prevents that, but only as long as its index is
+// intact) or that enable more than one version of the same mod file, and for
+// applied profiles without any items.
+func checkProfileItems(s StateSnapshot) []ConsistencyIssue {
+	var issues []ConsistencyIssue
+
+	type priorityKey struct{ profile, priority int64 }
+	type fileKey struct{ profile, file int64 }
+	priorities := map[priorityKey][]int64{}
+	enabled := map[fileKey][]int64{}
+	var priorityOrder []priorityKey
+	var fileOrder []fileKey
+	names := map[int64]string{}
+	items := map[int64]int{}
+
+	for _, it := range s.ProfileItems {
+		names[it.ProfileID] = it.ProfileName
+		items[it.ProfileID]++
+
+		pk := priorityKey{it.ProfileID, it.Priority}
+		if len(priorities[pk]) == 1 {
+			priorityOrder = append(priorityOrder, pk)
+		}
+		priorities[pk] = append(priorities[pk], it.ModFileVersionID)
+
+		if it.ModFileID == 0 || !util.SqliteIntToBool(it.Enabled) {
+			continue
+		}
+		fk := fileKey{it.ProfileID, it.ModFileID}
+		if len(enabled[fk]) == 1 {
+			fileOrder = append(fileOrder, fk)
+		}
+		enabled[fk] = append(enabled[fk], it.ModFileVersionID)
+	}
+
+	for _, pk := range priorityOrder {
+		versions := priorities[pk]
+		issues = append(issues, ConsistencyIssue{
+			Check: "profile_items",
+			Problem: fmt.Sprintf("profile %q: versions %v share priority %d (their order is undefined)",
+				names[pk.profile], versions, pk.priority),
+			Hint: fmt.Sprintf("run `modctl profiles remove %d --profile %s` and add it again with `modctl profiles add --priority <n>`",
+				versions[len(versions)-1], names[pk.profile]),
+		})
+	}
+
+	for _, fk := range fileOrder {
+		versions := enabled[fk]
+		issues = append(issues, ConsistencyIssue{
+			Check: "profile_items",
+			Problem: fmt.Sprintf("profile %q enables %d versions of mod file %d: %v",
+				names[fk.profile], len(versions), fk.file, versions),
+			Hint: fmt.Sprintf("run `modctl profiles disable <version-id> --profile %s` for all but one of them",
+				names[fk.profile]),
+		})
+	}
+
+	profiles := make(map[int64]dbq.Profile, len(s.Profiles))
+	for _, p := range s.Profiles {
+		profiles[p.ID] = p
+	}
+	for _, gi := range s.Installs {
+		if !gi.AppliedProfileID.Valid || items[gi.AppliedProfileID.Int64] > 0 {
+			continue
+		}
+		name := fmt.Sprintf("%d", gi.AppliedProfileID.Int64)
+		if p, ok := profiles[gi.AppliedProfileID.Int64]; ok {
+			name = p.Name
+		}
+		issues = append(issues, ConsistencyIssue{
+			Check: "profiles",
+			Problem: fmt.Sprintf("profile %q is applied to %s but has no items",
+				name, gameLabel(gi)),
+			Hint: fmt.Sprintf("run `modctl profiles unapply --game %s` to remove what it installed, or add its mods again",
+				FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)),
+		})
+	}
+
+	return issues
+}
+
+func gameLabel(gi dbq.GameInstall) string {
+	return fmt.Sprintf("%s (%s)", gi.DisplayName,
+		FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID))
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,199 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	t.Parallel()
+
+	installs := []dbq.GameInstall{
+		{ID: 1, StoreID: "steam", StoreGameID: "100", InstanceID: "default", DisplayName: "One", IsPresent: 1},
+		{ID: 2, StoreID: "steam", StoreGameID: "200", InstanceID: "default", DisplayName: "Two", IsPresent: 0},
+	}
+	profiles := []dbq.Profile{
+		{ID: 1, GameInstallID: 1, Name: "default", IsActive: 1},
+		{ID: 2, GameInstallID: 2, Name: "default", IsActive: 1},
+	}
+	sha := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	onDisk := func(string) bool { return true }
+
+	checks := func(issues []ConsistencyIssue) []string {
+		var got []string
+		for _, is := range issues {
+			got = append(got, is.Check)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name     string
+		snap     StateSnapshot
+		expected []string
+	}{
+		{
+			name: "consistent",
+			snap: StateSnapshot{
+				Active:   state.Active{ActiveGameInstallID: 1, ActiveGameInstallSelector: "steam:100#default"},
+				Installs: installs,
+				Profiles: profiles,
+				ProfileItems: []dbq.ListProfileItemArchivesRow{
+					{ProfileName: "default", GameInstallID: 1, ModFileVersionID: 5, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 1},
+				},
+				BlobOnDisk: onDisk,
+			},
+		},
+		{
+			name: "no active game",
+			snap: StateSnapshot{Installs: installs, Profiles: profiles},
+		},
+		{
+			name: "active game deleted",
+			snap: StateSnapshot{
+				Active:   state.Active{ActiveGameInstallID: 9},
+				Installs: installs,
+				Profiles: profiles,
+			},
+			expected: []string{"active"},
+		},
+		{
+			name: "corrupt active.json",
+			snap: StateSnapshot{
+				ActiveProblem: errors.New("parse: unexpected end of JSON input"),
+				Installs:      installs,
+				Profiles:      profiles,
+			},
+			expected: []string{"active"},
+		},
+		{
+			name: "database disagrees",
+			snap: StateSnapshot{
+				Active:     state.Active{ActiveGameInstallID: 1, ActiveGameInstallSelector: "steam:100#default"},
+				DBActive:   state.Active{ActiveGameInstallID: 2},
+				DBActiveOK: true,
+				Installs:   installs,
+				Profiles:   profiles,
+			},
+			expected: []string{"active"},
+		},
+		{
+			name: "active game not present",
+			snap: StateSnapshot{
+				Active:   state.Active{ActiveGameInstallID: 2},
+				Installs: installs,
+				Profiles: profiles,
+			},
+			expected: []string{"active"},
+		},
+		{
+			name: "stale selector",
+			snap: StateSnapshot{
+				Active:   state.Active{ActiveGameInstallID: 1, ActiveGameInstallSelector: "steam:999#default"},
+				Installs: installs,
+				Profiles: profiles,
+			},
+			expected: []string{"active"},
+		},
+		{
+			name: "missing and duplicate active profiles",
+			snap: StateSnapshot{
+				Installs: installs,
+				Profiles: []dbq.Profile{
+					{ID: 1, GameInstallID: 1, Name: "a", IsActive: 1},
+					{ID: 2, GameInstallID: 1, Name: "b", IsActive: 1},
+					{ID: 3, GameInstallID: 2, Name: "c", IsActive: 0},
+				},
+			},
+			expected: []string{"profiles", "profiles"},
+		},
+		{
+			name: "dangling profile items",
+			snap: StateSnapshot{
+				Installs: installs,
+				Profiles: profiles,
+				ProfileItems: []dbq.ListProfileItemArchivesRow{
+					{ProfileName: "default", ModFileVersionID: 1, Priority: 1, VersionExists: 0},
+					{ProfileName: "default", ModFileVersionID: 2, Priority: 2, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 0},
+					{ProfileName: "default", ModFileVersionID: 3, Priority: 3, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 1},
+				},
+				BlobOnDisk: func(string) bool { return false },
+			},
+			expected: []string{"profile_items", "profile_items", "profile_items"},
+		},
+		{
+			name: "corrupted archive",
+			snap: StateSnapshot{
+				Installs: installs,
+				Profiles: profiles,
+				ProfileItems: []dbq.ListProfileItemArchivesRow{
+					{ProfileName: "default", ModFileVersionID: 1, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 1, BlobCorrupted: 1},
+				},
+				BlobOnDisk: onDisk,
+			},
+			expected: []string{"profile_items"},
+		},
+		{
+			name: "duplicate priority and enabled versions",
+			snap: StateSnapshot{
+				Installs: installs,
+				Profiles: profiles,
+				ProfileItems: []dbq.ListProfileItemArchivesRow{
+					{ProfileID: 1, ProfileName: "default", ModFileVersionID: 1, ModFileID: 7, Enabled: 1, Priority: 10, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 1},
+					{ProfileID: 1, ProfileName: "default", ModFileVersionID: 2, ModFileID: 7, Enabled: 1, Priority: 10, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 1},
+					// disabled versions and other profiles don't count
+					{ProfileID: 1, ProfileName: "default", ModFileVersionID: 3, ModFileID: 7, Enabled: 0, Priority: 20, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 1},
+					{ProfileID: 2, ProfileName: "default", ModFileVersionID: 1, ModFileID: 7, Enabled: 1, Priority: 10, VersionExists: 1, ArchiveSha256: sha, BlobRecorded: 1},
+				},
+				BlobOnDisk: onDisk,
+			},
+			expected: []string{"profile_items", "profile_items"},
+		},
+		{
+			name: "empty applied profile",
+			snap: StateSnapshot{
+				Installs: []dbq.GameInstall{
+					{ID: 1, StoreID: "steam", StoreGameID: "100", InstanceID: "default", DisplayName: "One", IsPresent: 1,
+						AppliedProfileID: sql.NullInt64{Int64: 1, Valid: true}},
+				},
+				Profiles: profiles[:1],
+			},
+			expected: []string{"profiles"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := CheckConsistency(tt.snap)
+			assert.Equal(t, tt.expected, checks(issues))
+			for _, is := range issues {
+				assert.NotEmpty(t, is.Hint)
+			}
+		})
+	}
+}
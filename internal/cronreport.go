@@ -0,0 +1,109 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// SettingReportedUpdates is the settings key of the mod updates that
+// `modctl cron` reported last, so that it only reports new ones.
+const SettingReportedUpdates = "cron_reported_updates"
+
+// ReportedUpdate is a mod update that was reported: the mod page and the
+// version that it could be updated to.
+type ReportedUpdate struct {
+	ModPageID     int64  `json:"mod_page_id"`
+	LatestVersion string `json:"latest_version"`
+}
+
+// LoadReportedUpdates returns the updates that were reported last (none if
+// there is no usable record of them).
+func LoadReportedUpdates(ctx context.Context, q *dbq.Queries) ([]ReportedUpdate, error) {
+	s, err := q.GetSetting(ctx, SettingReportedUpdates)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get reported updates: %w", err)
+	}
+
+	var out []ReportedUpdate
+	if err := json.Unmarshal([]byte(s.Value), &out); err != nil {
+		// reporting them again is better than failing every run
+		return nil, nil
+	}
+	return out, nil
+}
+
+// SaveReportedUpdates records the updates that were reported (see
+// DiffReportedUpdates for which).
+func SaveReportedUpdates(ctx context.Context, q *dbq.Queries, updates []ReportedUpdate) error {
+	if updates == nil {
+		updates = []ReportedUpdate{}
+	}
+	b, err := json.Marshal(updates)
+	if err != nil {
+		return fmt.Errorf("marshal reported updates: %w", err)
+	}
+
+	if err := q.SetSetting(ctx, dbq.SetSettingParams{
+		Key:       SettingReportedUpdates,
+		Value:     string(b),
+		UpdatedAt: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}); err != nil {
+		return fmt.Errorf("record reported updates: %w", err)
+	}
+	return nil
+}
+
+// DiffReportedUpdates returns which of the updates that were found weren't
+// reported before (an update to another version is new), and what to
+// remember as reported: the updates that were found, and the ones that
+// were reported before for the mod pages that weren't checked this time
+// (another game, or deferred or failed), so that they aren't reported again
+// once they are. An update that went away (the mod was updated) is
+// forgotten.
+func DiffReportedUpdates(prev, found []ReportedUpdate, checked map[int64]bool) (fresh, keep []ReportedUpdate) {
+	reported := make(map[ReportedUpdate]bool, len(prev))
+	for _, u := range prev {
+		reported[u] = true
+	}
+
+	for _, u := range found {
+		if !reported[u] {
+			fresh = append(fresh, u)
+		}
+		keep = append(keep, u)
+	}
+	for _, u := range prev {
+		if !checked[u.ModPageID] {
+			keep = append(keep, u)
+		}
+	}
+
+	return fresh, keep
+}
@@ -0,0 +1,91 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffReportedUpdates(t *testing.T) {
+	t.Parallel()
+
+	u := func(page int64, version string) ReportedUpdate {
+		return ReportedUpdate{ModPageID: page, LatestVersion: version}
+	}
+
+	tests := []struct {
+		name      string
+		prev      []ReportedUpdate
+		found     []ReportedUpdate
+		checked   map[int64]bool
+		wantFresh []ReportedUpdate
+		wantKeep  []ReportedUpdate
+	}{
+		{
+			name:      "first run",
+			found:     []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+			checked:   map[int64]bool{1: true, 2: true},
+			wantFresh: []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+			wantKeep:  []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+		},
+		{
+			name:     "nothing new",
+			prev:     []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+			found:    []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+			checked:  map[int64]bool{1: true, 2: true},
+			wantKeep: []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+		},
+		{
+			name:      "newer version",
+			prev:      []ReportedUpdate{u(1, "1.1")},
+			found:     []ReportedUpdate{u(1, "1.2")},
+			checked:   map[int64]bool{1: true},
+			wantFresh: []ReportedUpdate{u(1, "1.2")},
+			wantKeep:  []ReportedUpdate{u(1, "1.2")},
+		},
+		{
+			name:    "updated in the meantime",
+			prev:    []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+			found:   []ReportedUpdate{u(2, "2.0")},
+			checked: map[int64]bool{1: true, 2: true},
+			// the next update of mod 1 is reported again
+			wantKeep: []ReportedUpdate{u(2, "2.0")},
+		},
+		{
+			name:     "not checked",
+			prev:     []ReportedUpdate{u(1, "1.1"), u(2, "2.0")},
+			found:    []ReportedUpdate{u(2, "2.0")},
+			checked:  map[int64]bool{2: true},
+			wantKeep: []ReportedUpdate{u(2, "2.0"), u(1, "1.1")},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fresh, keep := DiffReportedUpdates(tt.prev, tt.found, tt.checked)
+			assert.Equal(t, tt.wantFresh, fresh)
+			assert.Equal(t, tt.wantKeep, keep)
+		})
+	}
+}
@@ -21,55 +21,193 @@ package internal
 import (
 	"context"
 	"database/sql"
-	"embed"
 	"fmt"
-	"io/fs"
 	"net/url"
 	"os"
-
-	_ "github.com/mattn/go-sqlite3"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/perf"
+	"github.com/mfinelli/modctl/internal/querylog"
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/mfinelli/modctl/migrations"
 	"github.com/pressly/goose/v3"
 	"github.com/spf13/viper"
 )
 
 const DB_PRAGMAS = "?_foreign_keys=ON&_journal_mode=WAL&_synchronous=NORMAL"
 
-var Migrations embed.FS
+// queryLogDriver is the sqlite driver that also logs the queries, see the
+// query_log config option.
+const queryLogDriver = "sqlite3_querylog"
+
+// perfDriver is the sqlite driver that times the queries for --profile-perf
+// (when there's no query_log, whose driver does that as well).
+const perfDriver = "sqlite3_perf"
+
+var (
+	queryLogOnce sync.Once
+	queryLogErr  error
+	perfOnce     sync.Once
+)
+
+func observeQuery(d time.Duration) {
+	perf.Add(perf.DB, d)
+}
+
+// driverName returns the driver to open the database with: the one that
+// logs queries if there is a query_log.
+func driverName() (string, error) {
+	path := viper.GetString("query_log")
+	if path == "" {
+		if !perf.Enabled() {
+			return "sqlite3", nil
+		}
+		perfOnce.Do(func() {
+			sql.Register(perfDriver, querylog.Driver(&sqlite3.SQLiteDriver{},
+				querylog.NewLogger(nil).Observe(observeQuery)))
+		})
+		return perfDriver, nil
+	}
+
+	queryLogOnce.Do(func() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			queryLogErr = fmt.Errorf("create query log directory: %w", err)
+			return
+		}
+
+		// left open for the rest of the process, like the databases
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			queryLogErr = fmt.Errorf("open query log: %w", err)
+			return
+		}
 
+		sql.Register(queryLogDriver,
+			querylog.Driver(&sqlite3.SQLiteDriver{},
+				querylog.NewLogger(f).Observe(observeQuery)))
+	})
+	if queryLogErr != nil {
+		return "", queryLogErr
+	}
+
+	return queryLogDriver, nil
+}
+
+// SetupDB opens the configured database; with --dry-run it opens a throwaway
+// copy of it instead and with --read-only it opens it read-only.
 func SetupDB() (*sql.DB, error) {
-	return sql.Open("sqlite3", fmt.Sprintf("file:%s%s",
-		url.PathEscape(viper.GetString("database")), DB_PRAGMAS))
+	if readonly.Enabled() {
+		return SetupDBReadOnly()
+	}
+
+	path := viper.GetString("database")
+	if dryrun.Enabled() {
+		var err error
+		if path, err = dryRunCopy(path); err != nil {
+			return nil, err
+		}
+	}
+
+	driver, err := driverName()
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.Open(driver, fmt.Sprintf("file:%s%s",
+		url.PathEscape(path), DB_PRAGMAS))
 }
 
 func SetupDBReadOnly() (*sql.DB, error) {
-	return sql.Open("sqlite3", fmt.Sprintf("file:%s%s&mode=ro",
-		url.PathEscape(viper.GetString("database")), DB_PRAGMAS))
-}
+	path := viper.GetString("database")
+	if dryRunDB != "" {
+		// see what the dry run changed so far
+		path = dryRunDB
+	}
 
-func GooseProvider(db *sql.DB) (*goose.Provider, error) {
-	// Make the provider FS point at the "migrations" directory within the embed.FS.
-	fsys, err := fs.Sub(Migrations, "migrations")
+	driver, err := driverName()
 	if err != nil {
-		return nil, fmt.Errorf("error preparing migrations fs: %w", err)
+		return nil, err
 	}
 
-	return goose.NewProvider(goose.DialectSQLite3, db, fsys)
+	return sql.Open(driver, fmt.Sprintf("file:%s%s&mode=ro",
+		url.PathEscape(path), DB_PRAGMAS))
+}
+
+func GooseProvider(db *sql.DB) (*goose.Provider, error) {
+	return goose.NewProvider(goose.DialectSQLite3, db, migrations.FS)
+}
+
+// migrated is the database that MigrateDB doesn't need to check again, see
+// KeepMigrated.
+var migrated string
+
+// KeepMigrated makes MigrateDB skip the (already migrated) configured
+// database for the rest of the process, for `modctl shell` which runs many
+// commands against the same database.
+func KeepMigrated() {
+	migrated = viper.GetString("database")
 }
 
 func MigrateDB(ctx context.Context, db *sql.DB) error {
+	if migrated != "" && migrated == viper.GetString("database") {
+		return nil
+	}
+
 	p, err := GooseProvider(db)
 	if err != nil {
 		return fmt.Errorf("error setting up goose provider: %w", err)
 	}
 
+	if readonly.Enabled() {
+		pending, err := p.HasPending(ctx)
+		if err != nil {
+			return fmt.Errorf("error checking for migrations: %w", err)
+		}
+		if pending {
+			return fmt.Errorf("the database needs to be migrated to this version of modctl: %w",
+				readonly.ErrReadOnly)
+		}
+		return nil
+	}
+
 	_, err = p.Up(ctx)
 	if err != nil {
 		return fmt.Errorf("error migrating database: %w", err)
 	}
 
+	// best-effort: the commands still work with the selection in
+	// active.json
+	if err := SyncActive(ctx, db); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: sync the active selection: %v\n", err)
+	}
+
 	return nil
 }
 
+// OpenDB opens the configured database (see SetupDB) and migrates it, which
+// is how most commands and the Go API start. The caller closes it.
+func OpenDB(ctx context.Context) (*sql.DB, error) {
+	if err := EnsureDBExists(); err != nil {
+		return nil, err
+	}
+
+	db, err := SetupDB()
+	if err != nil {
+		return nil, fmt.Errorf("error setting up database: %w", err)
+	}
+
+	if err := MigrateDB(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error migrating database: %w", err)
+	}
+
+	return db, nil
+}
+
 // EnsureDBExists verifies that the configured database file exists
 // and is a regular file. If not, it returns a user-friendly error.
 func EnsureDBExists() error {
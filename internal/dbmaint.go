@@ -0,0 +1,133 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/spf13/viper"
+)
+
+// OptimizeReport is what OptimizeDB did.
+type OptimizeReport struct {
+	// size of the database and its WAL
+	SizeBefore int64
+	SizeAfter  int64
+
+	// what the integrity check found; empty if the database is fine
+	Problems []string
+
+	// the WAL couldn't be truncated because another process was using the
+	// database
+	Busy bool
+}
+
+// OptimizeDB checks the integrity of the database at path and then refreshes
+// the query planner statistics, rebuilds the database to reclaim free space
+// (if vacuum is set), and truncates the WAL. A database that fails the
+// integrity check is left alone.
+func OptimizeDB(ctx context.Context, db *sql.DB, path string, vacuum bool) (OptimizeReport, error) {
+	rep := OptimizeReport{SizeBefore: dbSize(path)}
+
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return rep, fmt.Errorf("integrity check: %w", err)
+	}
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			rows.Close()
+			return rep, fmt.Errorf("integrity check: %w", err)
+		}
+		if msg != "ok" {
+			rep.Problems = append(rep.Problems, msg)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return rep, fmt.Errorf("integrity check: %w", err)
+	}
+	if len(rep.Problems) > 0 {
+		rep.SizeAfter = rep.SizeBefore
+		return rep, nil
+	}
+
+	if _, err := db.ExecContext(ctx, "PRAGMA optimize"); err != nil {
+		return rep, fmt.Errorf("optimize: %w", err)
+	}
+
+	if vacuum {
+		if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+			return rep, fmt.Errorf("vacuum: %w", err)
+		}
+	}
+
+	busy, err := checkpointWAL(ctx, db)
+	if err != nil {
+		return rep, err
+	}
+	rep.Busy = busy
+
+	rep.SizeAfter = dbSize(path)
+	return rep, nil
+}
+
+// AutoOptimize refreshes the query planner statistics and truncates the WAL
+// after an operation that changed at least auto_optimize_rows rows so that
+// the database stays fast and small without running `modctl db optimize`.
+// It's best-effort: failing to optimize doesn't fail the operation.
+func AutoOptimize(ctx context.Context, db *sql.DB, rows int64) {
+	threshold := viper.GetInt64("auto_optimize_rows")
+	if threshold <= 0 || rows < threshold || dryrun.Enabled() {
+		return
+	}
+
+	if _, err := db.ExecContext(ctx, "PRAGMA optimize"); err != nil {
+		return
+	}
+	_, _ = checkpointWAL(ctx, db)
+}
+
+// checkpointWAL copies the WAL into the database and truncates it. It
+// reports whether the checkpoint couldn't finish because of other readers or
+// writers.
+func checkpointWAL(ctx context.Context, db *sql.DB) (bool, error) {
+	var busy, walPages, checkpointed int64
+	err := db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").
+		Scan(&busy, &walPages, &checkpointed)
+	if err != nil {
+		return false, fmt.Errorf("checkpoint: %w", err)
+	}
+	return busy != 0, nil
+}
+
+// dbSize returns the size of a database and its WAL.
+func dbSize(path string) int64 {
+	var size int64
+	for _, p := range []string{path, path + "-wal"} {
+		if st, err := os.Stat(p); err == nil {
+			size += st.Size()
+		}
+	}
+	return size
+}
@@ -0,0 +1,353 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package deploy has the filesystem side of applying a profile: staging mod
+// archives, writing files into a target, and removing them again.
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/fscaps"
+	"github.com/mfinelli/modctl/internal/perf"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// Stage extracts an archive into dir and returns its regular files keyed by
+// their normalized member name (see plan.NormalizeRelPath), i.e., the same
+// names that the planner uses. Anything that isn't a regular file (e.g., a
+// symlink) is skipped and reported as a warning.
+func Stage(ctx context.Context, bsdtar, archivePath, dir string) (map[string]string, []string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("create staging dir: %w", err)
+	}
+
+	if err := archive.Extract(ctx, bsdtar, archivePath, dir); err != nil {
+		return nil, nil, err
+	}
+
+	return stagedFiles(dir)
+}
+
+func stagedFiles(dir string) (map[string]string, []string, error) {
+	files := map[string]string{}
+	var warnings []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			warnings = append(warnings, fmt.Sprintf("skipping %q: not a regular file", rel))
+			return nil
+		}
+
+		// names with backslashes (from windows archives) were extracted
+		// as-is but the planner treats them as directory separators
+		member, err := plan.NormalizeRelPath(filepath.ToSlash(rel))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipping %q: %v", rel, err))
+			return nil
+		}
+		// several files can have the same normalized name (e.g., "a.txt"
+		// and "a.txt "): always use the first one by name
+		if prev, ok := files[member]; !ok || path < prev {
+			files[member] = path
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan staging dir: %w", err)
+	}
+
+	return files, warnings, nil
+}
+
+// HashFile returns the sha256 (lowercase hex) and size of a file.
+func HashFile(path string) (string, int64, error) {
+	sha, n, _, err := hashWithKey(path)
+	return sha, n, err
+}
+
+// Method is how PlaceFile puts a file in place.
+type Method int
+
+const (
+	// Copy writes a copy of the file.
+	Copy Method = iota
+	// Reflink clones the file (copy-on-write): it's instant and the clone
+	// is still independent of the source.
+	Reflink
+	// Hardlink links the file: it's instant but the source and the link
+	// are the same file afterwards, so it's only for sources that are
+	// thrown away (staged archive members) and each of them only once.
+	Hardlink
+	// Symlink links to the file (by its absolute path): it has to stay
+	// where it is for as long as the link is deployed, so it's only for
+	// sources in a store.
+	Symlink
+)
+
+func (m Method) String() string {
+	switch m {
+	case Reflink:
+		return "reflink"
+	case Hardlink:
+		return "hardlink"
+	case Symlink:
+		return "symlink"
+	default:
+		return "copy"
+	}
+}
+
+// WriteFile copies src to dst, creating the parent directories of dst as
+// needed. The copy is written next to dst and renamed into place so that
+// dst is never left half-written. It returns the sha256 and size of what it
+// wrote. The permissions of src are kept.
+func WriteFile(ctx context.Context, src, dst string) (string, int64, error) {
+	return PlaceFile(ctx, src, dst, Copy)
+}
+
+// PlaceFile is WriteFile with the given method. If the method doesn't work
+// (e.g., src and dst are on different filesystems) it copies instead. During
+// a dry run dst is left alone and only src is hashed.
+func PlaceFile(ctx context.Context, src, dst string, m Method) (string, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return "", 0, err
+	}
+	if dryrun.Enabled() {
+		dryrun.Note("%s %s to %s", m, src, dst)
+		return HashFile(src)
+	}
+
+	in, err := blobstore.OpenRead(src)
+	if err != nil {
+		return "", 0, err
+	}
+	defer in.Close()
+
+	st, err := in.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+
+	dir := filepath.Dir(dst)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", 0, fmt.Errorf("create %s: %w", dir, err)
+	}
+
+	if m != Copy {
+		if sha, n, err := link(in, src, dst, st.Mode().Perm()|0o200, m); err == nil {
+			return sha, n, nil
+		}
+		// copy it after all
+		if _, err := in.Seek(0, io.SeekStart); err != nil {
+			return "", 0, err
+		}
+	}
+
+	tmp, err := os.CreateTemp(dir, ".modctl-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName) // no-op after the rename
+	}()
+
+	h := sha256.New()
+	n, err := blobstore.CopyWithContext(ctx, io.MultiWriter(tmp, h), in, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("write %s: %w", dst, err)
+	}
+	if err := tmp.Chmod(st.Mode().Perm() | 0o200); err != nil {
+		return "", 0, fmt.Errorf("chmod %s: %w", dst, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", 0, fmt.Errorf("fsync %s: %w", dst, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, fmt.Errorf("close %s: %w", dst, err)
+	}
+
+	if err := prepareReplace(dst); err != nil {
+		return "", 0, fmt.Errorf("make %s writable: %w", dst, err)
+	}
+	if err := os.Rename(tmpName, dst); err != nil {
+		return "", 0, fmt.Errorf("rename into place: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// link puts src at dst with a reflink, a hardlink or a symlink (see Method)
+// and returns its sha256 and size. It leaves dst alone if that doesn't work.
+func link(in *os.File, src, dst string, mode fs.FileMode, m Method) (string, int64, error) {
+	stop := perf.Track(perf.Hashing)
+	h := sha256.New()
+	n, err := blobstore.CopyWithContext(context.Background(), h, in, nil)
+	stop()
+	if err != nil {
+		return "", 0, fmt.Errorf("hash %s: %w", src, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".modctl-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	_ = tmp.Close()
+	defer os.Remove(tmpName) // no-op after the rename
+
+	switch m {
+	case Reflink:
+		err = fscaps.Clone(src, tmpName)
+		if err == nil {
+			err = os.Chmod(tmpName, mode)
+		}
+		if err == nil {
+			err = syncFile(tmpName)
+		}
+	case Hardlink:
+		// the link takes the name of the temp file
+		err = os.Remove(tmpName)
+		if err == nil {
+			err = os.Link(src, tmpName)
+		}
+		if err == nil {
+			err = os.Chmod(tmpName, mode)
+		}
+	case Symlink:
+		err = os.Remove(tmpName)
+		if err == nil {
+			src, err = filepath.Abs(src)
+		}
+		if err == nil {
+			err = os.Symlink(src, tmpName)
+		}
+	default:
+		err = fmt.Errorf("can't link with %s", m)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := prepareReplace(dst); err != nil {
+		return "", 0, fmt.Errorf("make %s writable: %w", dst, err)
+	}
+	if err := os.Rename(tmpName, dst); err != nil {
+		return "", 0, fmt.Errorf("rename into place: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func syncFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+// RemoveFile removes a file (that it's already gone is not an error) and
+// then every parent directory up to (but not including) root that is now
+// empty. If one of the parents is a symlink or a junction (Windows) it leaves
+// them all alone: the directories behind the link belong to whatever it
+// points into.
+func RemoveFile(path, root string) error {
+	if dryrun.Enabled() {
+		dryrun.Note("remove %s", path)
+		return nil
+	}
+
+	if err := prepareReplace(path); err != nil {
+		return fmt.Errorf("make %s writable: %w", path, err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove %s: %w", path, err)
+	}
+
+	root = filepath.Clean(root)
+	var dirs []string
+	for dir := filepath.Dir(path); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+		st, err := os.Lstat(dir)
+		if err != nil || st.Mode()&(fs.ModeSymlink|fs.ModeIrregular) != 0 {
+			return nil
+		}
+		dirs = append(dirs, dir)
+	}
+
+	for _, dir := range dirs {
+		// fails if the directory isn't empty, which is where we stop
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// ReadOnly reports whether a file is read-only: its owner can't write it
+// (on Windows: it has the read-only attribute).
+func ReadOnly(path string) (bool, error) {
+	st, err := os.Lstat(path)
+	if err != nil {
+		return false, err
+	}
+	return st.Mode().Perm()&0o200 == 0, nil
+}
+
+// SetReadOnly makes a file read-only (see ReadOnly), e.g., to restore a
+// backup of a file that was.
+func SetReadOnly(path string) error {
+	if dryrun.Enabled() {
+		dryrun.Note("make %s read-only", path)
+		return nil
+	}
+
+	st, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(path, st.Mode().Perm()&^0o222)
+}
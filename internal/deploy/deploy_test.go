@@ -0,0 +1,395 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o640))
+
+	dst := filepath.Join(dir, "out", "nested", "dst.txt")
+	sha, size, err := WriteFile(context.Background(), src, dst)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha)
+	assert.Equal(t, int64(5), size)
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+
+	st, err := os.Stat(dst)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), st.Mode().Perm())
+
+	hsha, hsize, err := HashFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, sha, hsha)
+	assert.Equal(t, size, hsize)
+
+	// overwrite in place, no temp files left behind
+	require.NoError(t, os.WriteFile(src, []byte("bye"), 0o640))
+	_, _, err = WriteFile(context.Background(), src, dst)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestPlaceFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o640))
+
+	linked := filepath.Join(dir, "out", "linked.txt")
+	sha, size, err := PlaceFile(context.Background(), src, linked, Hardlink)
+	require.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha)
+	assert.Equal(t, int64(5), size)
+
+	sst, err := os.Stat(src)
+	require.NoError(t, err)
+	lst, err := os.Stat(linked)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(sst, lst))
+
+	// reflinks aren't supported everywhere, but it always ends up with a
+	// (separate) copy
+	cloned := filepath.Join(dir, "out", "cloned.txt")
+	_, _, err = PlaceFile(context.Background(), src, cloned, Reflink)
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(cloned)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+	cst, err := os.Stat(cloned)
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(sst, cst))
+
+	entries, err := os.ReadDir(filepath.Dir(cloned))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestPlaceFileSymlink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o640))
+
+	// replaces whatever is there
+	dst := filepath.Join(dir, "out", "dst.txt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+	require.NoError(t, os.WriteFile(dst, []byte("old"), 0o644))
+
+	sha, size, err := PlaceFile(context.Background(), src, dst, Symlink)
+	require.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha)
+	assert.Equal(t, int64(5), size)
+
+	target, err := os.Readlink(dst)
+	require.NoError(t, err)
+	assert.Equal(t, src, target)
+
+	// removing the link leaves the source alone
+	require.NoError(t, RemoveFile(dst, dir))
+	assert.FileExists(t, src)
+	assert.NoDirExists(t, filepath.Dir(dst))
+}
+
+func TestRemoveFile(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	keep := filepath.Join(root, "a", "keep.txt")
+	gone := filepath.Join(root, "a", "b", "c", "gone.txt")
+
+	for _, p := range []string{keep, gone} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte("x"), 0o644))
+	}
+
+	require.NoError(t, RemoveFile(gone, root))
+
+	assert.NoFileExists(t, gone)
+	assert.NoDirExists(t, filepath.Join(root, "a", "b"))
+	assert.FileExists(t, keep)
+
+	// already removed
+	require.NoError(t, RemoveFile(gone, root))
+
+	require.NoError(t, RemoveFile(keep, root))
+	assert.NoDirExists(t, filepath.Join(root, "a"))
+	assert.DirExists(t, root)
+}
+
+func TestRemoveFileLinkedDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	elsewhere := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(elsewhere, "empty"), 0o755))
+	require.NoError(t, os.Symlink(elsewhere, filepath.Join(root, "link")))
+
+	gone := filepath.Join(root, "link", "empty", "gone.txt")
+	require.NoError(t, os.WriteFile(gone, []byte("x"), 0o644))
+
+	require.NoError(t, RemoveFile(gone, root))
+
+	assert.NoFileExists(t, gone)
+	// the directory behind the link isn't ours to clean up
+	assert.DirExists(t, filepath.Join(elsewhere, "empty"))
+	assert.DirExists(t, filepath.Join(root, "link", "empty"))
+}
+
+// not parallel: dry-run mode is global
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0o640))
+	dst := filepath.Join(dir, "game", "dst.txt")
+	deployed := filepath.Join(dir, "game", "deployed.txt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(deployed), 0o755))
+	require.NoError(t, os.WriteFile(deployed, []byte("x"), 0o644))
+
+	dryrun.Enable()
+	t.Cleanup(dryrun.Reset)
+
+	sha, size, err := PlaceFile(context.Background(), src, dst, Hardlink)
+	require.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha)
+	assert.Equal(t, int64(5), size)
+	assert.NoFileExists(t, dst)
+
+	require.NoError(t, SetReadOnly(deployed))
+	ro, err := ReadOnly(deployed)
+	require.NoError(t, err)
+	assert.False(t, ro)
+
+	require.NoError(t, RemoveFile(deployed, dir))
+	assert.FileExists(t, deployed)
+
+	assert.Equal(t, []string{
+		"hardlink " + src + " to " + dst,
+		"make " + deployed + " read-only",
+		"remove " + deployed,
+	}, dryrun.Notes())
+}
+
+func TestReadOnly(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	dst := filepath.Join(dir, "dst.txt")
+	require.NoError(t, os.WriteFile(src, []byte("new"), 0o644))
+	require.NoError(t, os.WriteFile(dst, []byte("old"), 0o644))
+
+	ro, err := ReadOnly(dst)
+	require.NoError(t, err)
+	assert.False(t, ro)
+
+	require.NoError(t, SetReadOnly(dst))
+	ro, err = ReadOnly(dst)
+	require.NoError(t, err)
+	assert.True(t, ro)
+
+	// a read-only file can still be replaced
+	_, _, err = WriteFile(context.Background(), src, dst)
+	require.NoError(t, err)
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	ro, err = ReadOnly(dst)
+	require.NoError(t, err)
+	assert.False(t, ro)
+}
+
+func TestStagedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"Data/plugin.esp":       "esp",
+		"Data/Textures/a.dds":   "dds",
+		`Data\Meshes\weird.nif`: "nif",
+		"readme.txt":            "txt",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	require.NoError(t, os.Symlink("readme.txt", filepath.Join(dir, "link.txt")))
+
+	got, warnings, err := stagedFiles(dir)
+	require.NoError(t, err)
+
+	assert.Len(t, got, 4)
+	assert.Equal(t, filepath.Join(dir, "Data", "plugin.esp"), got["Data/plugin.esp"])
+	assert.Contains(t, got, "Data/Textures/a.dds")
+	assert.Contains(t, got, "Data/Meshes/weird.nif")
+	assert.Contains(t, got, "readme.txt")
+	assert.NotContains(t, got, "link.txt")
+	assert.Len(t, warnings, 1)
+}
+
+func TestStagedFilesSameName(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt ", "a.txt", "b.txt."} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644))
+	}
+
+	got, warnings, err := stagedFiles(dir)
+	require.NoError(t, err)
+
+	assert.Empty(t, warnings)
+	assert.Equal(t, map[string]string{
+		"a.txt": filepath.Join(dir, "a.txt"),
+		"b.txt": filepath.Join(dir, "b.txt."),
+	}, got)
+}
+
+func TestCopyTree(t *testing.T) {
+	t.Parallel()
+
+	src := filepath.Join(t.TempDir(), "game")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "bin", "x64"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "bin", "x64", "game.exe"), []byte("exe"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "data.pak"), []byte("data"), 0o644))
+	require.NoError(t, os.Symlink("data.pak", filepath.Join(src, "link.pak")))
+
+	for _, m := range []Method{Copy, Reflink, Hardlink} {
+		t.Run(m.String(), func(t *testing.T) {
+			t.Parallel()
+
+			dst := filepath.Join(t.TempDir(), "copy")
+			var done, total int64
+			st, err := CopyTree(context.Background(), src, dst, m, func(d, tot int64) { done, total = d, tot })
+			require.NoError(t, err)
+
+			assert.Equal(t, 2, st.Files)
+			assert.Equal(t, int64(7), st.Bytes)
+			assert.Equal(t, 1, st.Symlinks)
+			assert.Equal(t, 2, st.Reflinked+st.Hardlinked+st.Copied)
+			assert.Equal(t, int64(7), done)
+			assert.Equal(t, int64(7), total)
+
+			got, err := os.ReadFile(filepath.Join(dst, "bin", "x64", "game.exe"))
+			require.NoError(t, err)
+			assert.Equal(t, "exe", string(got))
+			info, err := os.Stat(filepath.Join(dst, "bin", "x64", "game.exe"))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+			link, err := os.Readlink(filepath.Join(dst, "link.pak"))
+			require.NoError(t, err)
+			assert.Equal(t, "data.pak", link)
+
+			_, err = CopyTree(context.Background(), src, dst, m, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+type mapHashCache struct {
+	hashes map[string]string
+	keys   map[string]FileKey
+	hits   int
+}
+
+func (c *mapHashCache) Get(ctx context.Context, path string, key FileKey) (string, bool, error) {
+	if c.keys[path] != key {
+		return "", false, nil
+	}
+	c.hits++
+	return c.hashes[path], true, nil
+}
+
+func (c *mapHashCache) Put(ctx context.Context, path string, key FileKey, sha string) error {
+	c.hashes[path] = sha
+	c.keys[path] = key
+	return nil
+}
+
+func TestHashFileCached(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &mapHashCache{hashes: map[string]string{}, keys: map[string]FileKey{}}
+
+	p := filepath.Join(t.TempDir(), "a.txt")
+	require.NoError(t, os.WriteFile(p, []byte("hello"), 0o644))
+	want, _, err := HashFile(p)
+	require.NoError(t, err)
+
+	sha, n, err := HashFileCached(ctx, c, p)
+	require.NoError(t, err)
+	assert.Equal(t, want, sha)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, 0, c.hits)
+
+	sha, _, err = HashFileCached(ctx, c, p)
+	require.NoError(t, err)
+	assert.Equal(t, want, sha)
+	assert.Equal(t, 1, c.hits)
+
+	// a lie in the cache is believed while the file stays the same...
+	c.hashes[p] = "cached"
+	sha, _, err = HashFileCached(ctx, c, p)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", sha)
+
+	// ...but not once the file changes
+	require.NoError(t, os.WriteFile(p, []byte("world!"), 0o644))
+	want, _, err = HashFile(p)
+	require.NoError(t, err)
+	sha, n, err = HashFileCached(ctx, c, p)
+	require.NoError(t, err)
+	assert.Equal(t, want, sha)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, 2, c.hits)
+
+	sha, _, err = HashFileCached(ctx, nil, p)
+	require.NoError(t, err)
+	assert.Equal(t, want, sha)
+
+	_, _, err = HashFileCached(ctx, c, filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
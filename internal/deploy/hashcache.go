@@ -0,0 +1,154 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/perf"
+)
+
+// FileKey identifies the contents of a file without reading it: if the file
+// is written to (or replaced) at least one of its fields changes.
+type FileKey struct {
+	Device  uint64
+	Inode   uint64
+	Size    int64
+	MtimeNS int64
+}
+
+// HashCache remembers the hashes of files (by their absolute path) so that
+// files that didn't change since they were last hashed don't have to be read
+// again. A hash is only valid for the same FileKey.
+type HashCache interface {
+	Get(ctx context.Context, path string, key FileKey) (string, bool, error)
+	Put(ctx context.Context, path string, key FileKey, sha string) error
+}
+
+// HashFileCached is like HashFile but it asks the cache first (and records
+// the hash in it). With a nil cache, or on platforms without inode numbers,
+// it always hashes the file.
+func HashFileCached(ctx context.Context, c HashCache, path string) (string, int64, error) {
+	if c == nil {
+		return HashFile(path)
+	}
+
+	path, err := absPath(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	st, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	key, ok := fileKey(st)
+	if !ok {
+		return HashFile(path)
+	}
+
+	sha, hit, err := c.Get(ctx, path, key)
+	if err != nil {
+		return "", 0, fmt.Errorf("hash cache: %w", err)
+	}
+	if hit {
+		return sha, key.Size, nil
+	}
+
+	sha, n, after, err := hashWithKey(path)
+	if err != nil {
+		return "", 0, err
+	}
+	// don't remember a hash of a file that changed while it was read
+	if after == key {
+		if err := c.Put(ctx, path, key, sha); err != nil {
+			return "", 0, fmt.Errorf("hash cache: %w", err)
+		}
+	}
+
+	return sha, n, nil
+}
+
+// RememberHash records the hash of a file that was just written (e.g., by
+// PlaceFile) so that it doesn't have to be read again to check it later.
+func RememberHash(ctx context.Context, c HashCache, path, sha string) error {
+	if c == nil || dryrun.Enabled() {
+		// nothing was written during a dry run
+		return nil
+	}
+
+	path, err := absPath(path)
+	if err != nil {
+		return err
+	}
+
+	st, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	key, ok := fileKey(st)
+	if !ok {
+		return nil
+	}
+
+	if err := c.Put(ctx, path, key, sha); err != nil {
+		return fmt.Errorf("hash cache: %w", err)
+	}
+	return nil
+}
+
+// hashWithKey hashes a file and returns its key after reading it.
+func hashWithKey(path string) (string, int64, FileKey, error) {
+	defer perf.Track(perf.Hashing)()
+
+	f, err := blobstore.OpenRead(path)
+	if err != nil {
+		return "", 0, FileKey{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := blobstore.CopyWithContext(context.Background(), h, f, nil)
+	if err != nil {
+		return "", 0, FileKey{}, fmt.Errorf("hash %s: %w", path, err)
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		return "", 0, FileKey{}, err
+	}
+	key, _ := fileKey(st)
+
+	return hex.EncodeToString(h.Sum(nil)), n, key, nil
+}
+
+func absPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", path, err)
+	}
+	return abs, nil
+}
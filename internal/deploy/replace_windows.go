@@ -0,0 +1,33 @@
+//go:build windows
+
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package deploy
+
+import "os"
+
+// prepareReplace makes the file at dst replaceable by a rename: Windows
+// refuses to replace a read-only file (even if the directory is writable).
+func prepareReplace(dst string) error {
+	st, err := os.Lstat(dst)
+	if err != nil || !st.Mode().IsRegular() || st.Mode().Perm()&0o200 != 0 {
+		return nil
+	}
+	return os.Chmod(dst, st.Mode().Perm()|0o200)
+}
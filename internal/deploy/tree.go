@@ -0,0 +1,172 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/internal/fscaps"
+)
+
+// TreeStats counts what CopyTree did.
+type TreeStats struct {
+	Files      int
+	Bytes      int64
+	Reflinked  int
+	Hardlinked int
+	Copied     int
+	Symlinks   int
+}
+
+// CopyTree copies the directory tree at src to dst (which must not exist
+// yet) with the given method, falling back to copying files that can't be
+// linked. Symlinks are recreated as they are and permissions are kept.
+// progress, if set, is called with the bytes done so far.
+//
+// Unlike PlaceFile this doesn't hash anything: a game install can be large
+// and cloning it should take as long as the filesystem needs.
+func CopyTree(ctx context.Context, src, dst string, m Method, progress func(done, total int64)) (TreeStats, error) {
+	var st TreeStats
+
+	var total int64
+	if progress != nil {
+		err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+			return nil
+		})
+		if err != nil {
+			return st, err
+		}
+	}
+
+	if _, err := os.Lstat(dst); err == nil {
+		return st, fmt.Errorf("%s: %w", dst, os.ErrExist)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return st, err
+	}
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			st.Symlinks++
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			// sockets, fifos, ... have no business in a game
+			return nil
+		}
+
+		used, err := copyTreeFile(path, target, info.Mode().Perm(), m)
+		if err != nil {
+			return err
+		}
+		switch used {
+		case Reflink:
+			st.Reflinked++
+		case Hardlink:
+			st.Hardlinked++
+		default:
+			st.Copied++
+		}
+		st.Files++
+		st.Bytes += info.Size()
+		if progress != nil {
+			progress(st.Bytes, total)
+		}
+		return nil
+	})
+
+	return st, err
+}
+
+// copyTreeFile puts src at dst (with the mode of src) and returns the
+// method that worked.
+func copyTreeFile(src, dst string, mode fs.FileMode, m Method) (Method, error) {
+	switch m {
+	case Reflink:
+		// the clone needs an empty file to go into
+		f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if err != nil {
+			return Copy, err
+		}
+		_ = f.Close()
+		if err := fscaps.Clone(src, dst); err == nil {
+			return Reflink, nil
+		}
+		if err := os.Remove(dst); err != nil {
+			return Copy, err
+		}
+	case Hardlink:
+		if err := os.Link(src, dst); err == nil {
+			return Hardlink, nil
+		}
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return Copy, err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return Copy, err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return Copy, fmt.Errorf("copy %s: %w", src, err)
+	}
+	return Copy, out.Close()
+}
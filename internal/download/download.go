@@ -0,0 +1,260 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package download paces downloads so that they don't saturate a shared
+// connection or hammer a host: how many run at the same time and how fast
+// they're read, overall and for every host.
+package download
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy is how politely files are downloaded (from a host). Zero values
+// don't limit anything.
+type Policy struct {
+	// bytes per second
+	LimitRate int64
+	// downloads at the same time
+	Concurrency int
+}
+
+// Manager paces downloads according to an overall policy and the policies of
+// hosts. A host policy applies to the host and its subdomains (e.g.,
+// "nexus-cdn.com" to "supporter-files.nexus-cdn.com"); the most specific one
+// wins, and all the hosts that it applies to share its limits.
+type Manager struct {
+	global *pacer
+
+	mu     sync.Mutex
+	hosts  map[string]Policy
+	pacers map[string]*pacer
+}
+
+// NewManager returns a manager with the overall policy and the policies of
+// the hosts.
+func NewManager(global Policy, hosts map[string]Policy) *Manager {
+	m := &Manager{
+		global: newPacer(global),
+		hosts:  map[string]Policy{},
+		pacers: map[string]*pacer{},
+	}
+	for h, p := range hosts {
+		m.hosts[NormalizeHost(h)] = p
+	}
+	return m
+}
+
+// Start waits until a download from host may start (there are fewer than the
+// allowed number running, overall and for the host) and returns a function
+// to call once it's done.
+func (m *Manager) Start(ctx context.Context, host string) (func(), error) {
+	hp := m.pacer(host)
+	if err := m.global.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := hp.acquire(ctx); err != nil {
+		m.global.release()
+		return nil, err
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			hp.release()
+			m.global.release()
+		})
+	}, nil
+}
+
+// Reader returns r, read no faster than the rate limits (overall and of
+// host) allow.
+func (m *Manager) Reader(ctx context.Context, host string, r io.Reader) io.Reader {
+	hp := m.pacer(host)
+	if m.global.bucket == nil && hp.bucket == nil {
+		return r
+	}
+	return &reader{ctx: ctx, r: r, buckets: []*bucket{m.global.bucket, hp.bucket}}
+}
+
+// Policy returns the policy of host, and the host (or domain) that it was
+// configured for ("" if none applies).
+func (m *Manager) Policy(host string) (Policy, string) {
+	host = NormalizeHost(host)
+	for h := host; h != ""; {
+		if p, ok := m.hosts[h]; ok {
+			return p, h
+		}
+		i := strings.IndexByte(h, '.')
+		if i < 0 {
+			break
+		}
+		h = h[i+1:]
+	}
+	return Policy{}, ""
+}
+
+// pacer returns the pacer that is shared by the hosts that the policy of host
+// applies to.
+func (m *Manager) pacer(host string) *pacer {
+	p, key := m.Policy(host)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pc, ok := m.pacers[key]
+	if !ok {
+		pc = newPacer(p)
+		m.pacers[key] = pc
+	}
+	return pc
+}
+
+// NormalizeHost returns host the way policies are keyed: lowercase and
+// without a trailing dot.
+func NormalizeHost(h string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(h)), ".")
+}
+
+// pacer enforces a policy: a semaphore for the concurrency, a token bucket
+// for the rate (either is nil if it isn't limited).
+type pacer struct {
+	slots  chan struct{}
+	bucket *bucket
+}
+
+func newPacer(p Policy) *pacer {
+	pc := &pacer{}
+	if p.Concurrency > 0 {
+		pc.slots = make(chan struct{}, p.Concurrency)
+	}
+	if p.LimitRate > 0 {
+		pc.bucket = newBucket(p.LimitRate)
+	}
+	return pc
+}
+
+func (pc *pacer) acquire(ctx context.Context) error {
+	if pc.slots == nil {
+		return nil
+	}
+	select {
+	case pc.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pc *pacer) release() {
+	if pc.slots != nil {
+		<-pc.slots
+	}
+}
+
+// bucket is a token bucket of bytes that fills up at rate bytes per second,
+// up to a second's worth.
+type bucket struct {
+	rate int64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	// for tests
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+func newBucket(rate int64) *bucket {
+	return &bucket{
+		rate:   rate,
+		tokens: float64(rate),
+		now:    time.Now,
+		sleep:  sleepCtx,
+	}
+}
+
+// take takes n bytes out of the bucket, waiting for them if it doesn't
+// have enough.
+func (b *bucket) take(ctx context.Context, n int) error {
+	b.mu.Lock()
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens = min(float64(b.rate), b.tokens+now.Sub(b.last).Seconds()*float64(b.rate))
+	}
+	b.last = now
+	b.tokens -= float64(n)
+	wait := time.Duration(0)
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / float64(b.rate) * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	return b.sleep(ctx, wait)
+}
+
+// chunk is how much is read at once, so that a read never needs more than
+// the bucket can hold and the pace is smooth.
+func (b *bucket) chunk() int {
+	return int(min(b.rate, 32<<10))
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reader reads no faster than its buckets allow.
+type reader struct {
+	ctx     context.Context
+	r       io.Reader
+	buckets []*bucket
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for _, b := range r.buckets {
+		if b != nil && len(p) > b.chunk() {
+			p = p[:b.chunk()]
+		}
+	}
+
+	n, err := r.r.Read(p)
+	if n > 0 {
+		for _, b := range r.buckets {
+			if b == nil {
+				continue
+			}
+			if werr := b.take(r.ctx, n); werr != nil {
+				return n, werr
+			}
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,153 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerPolicy(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager(Policy{}, map[string]Policy{
+		"Nexus-CDN.com":                 {LimitRate: 1 << 20},
+		"supporter-files.nexus-cdn.com": {Concurrency: 1},
+	})
+
+	p, host := m.Policy("cf-files.nexus-cdn.com")
+	assert.Equal(t, Policy{LimitRate: 1 << 20}, p)
+	assert.Equal(t, "nexus-cdn.com", host)
+
+	p, host = m.Policy("Supporter-Files.nexus-cdn.com.")
+	assert.Equal(t, Policy{Concurrency: 1}, p)
+	assert.Equal(t, "supporter-files.nexus-cdn.com", host)
+
+	p, host = m.Policy("example.com")
+	assert.Zero(t, p)
+	assert.Empty(t, host)
+
+	// a domain isn't a subdomain of a host that merely ends the same
+	_, host = m.Policy("evilnexus-cdn.com")
+	assert.Empty(t, host)
+}
+
+func TestManagerStart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewManager(Policy{Concurrency: 2}, map[string]Policy{
+		"slow.example": {Concurrency: 1},
+	})
+
+	done1, err := m.Start(ctx, "a.slow.example")
+	require.NoError(t, err)
+
+	// the host has no slot left, even for another of its subdomains
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	_, err = m.Start(short, "b.slow.example")
+	cancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the failed start gave back its overall slot
+	done2, err := m.Start(ctx, "fast.example")
+	require.NoError(t, err)
+
+	// both overall slots are taken
+	short, cancel = context.WithTimeout(ctx, 10*time.Millisecond)
+	_, err = m.Start(short, "other.example")
+	cancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done1()
+	done1() // only frees the slots once
+	done3, err := m.Start(ctx, "b.slow.example")
+	require.NoError(t, err)
+
+	done2()
+	done3()
+}
+
+func TestManagerReaderUnlimited(t *testing.T) {
+	t.Parallel()
+
+	r := strings.NewReader("data")
+	m := NewManager(Policy{Concurrency: 1}, nil)
+	assert.Same(t, io.Reader(r), m.Reader(context.Background(), "example.com", r))
+}
+
+func TestBucket(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	b := newBucket(1000)
+	b.now = func() time.Time { return now }
+	b.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		now = now.Add(d)
+		return nil
+	}
+
+	ctx := context.Background()
+	// a second's worth is there right away
+	require.NoError(t, b.take(ctx, 1000))
+	assert.Empty(t, slept)
+
+	require.NoError(t, b.take(ctx, 500))
+	assert.Equal(t, []time.Duration{500 * time.Millisecond}, slept)
+
+	// idle time fills it up again, but only up to a second's worth
+	now = now.Add(time.Hour)
+	require.NoError(t, b.take(ctx, 1000))
+	assert.Len(t, slept, 1)
+
+	assert.Equal(t, 1000, b.chunk())
+	assert.Equal(t, 32<<10, newBucket(10<<20).chunk())
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager(Policy{LimitRate: 1 << 30}, map[string]Policy{"example.com": {LimitRate: 1000}})
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	hp := m.pacer("example.com")
+	hp.bucket.now = func() time.Time { return now }
+	hp.bucket.sleep = func(_ context.Context, d time.Duration) error {
+		slept += d
+		now = now.Add(d)
+		return nil
+	}
+
+	data := bytes.Repeat([]byte("x"), 3000)
+	got, err := io.ReadAll(m.Reader(context.Background(), "example.com", bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+	// the first 1000 bytes were in the bucket
+	assert.Equal(t, 2*time.Second, slept)
+}
@@ -0,0 +1,70 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/download"
+	"github.com/spf13/viper"
+)
+
+// DownloadPolicy returns the overall download limits (download_limit_rate and
+// download_concurrency).
+func DownloadPolicy() (download.Policy, error) {
+	rate, err := configBytes(viper.Get("download_limit_rate"))
+	if err != nil {
+		return download.Policy{}, fmt.Errorf("download_limit_rate: %w", err)
+	}
+	n := viper.GetInt("download_concurrency")
+	if n < 0 {
+		return download.Policy{}, fmt.Errorf("download_concurrency: must not be negative: %d", n)
+	}
+	return download.Policy{LimitRate: rate, Concurrency: n}, nil
+}
+
+// NewDownloadManager returns a download manager with the overall limits
+// from the config and the limits of the hosts recorded in the database.
+func NewDownloadManager(ctx context.Context, q *dbq.Queries) (*download.Manager, error) {
+	global, err := DownloadPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.ListDownloadHosts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list download hosts: %w", err)
+	}
+	hosts := make(map[string]download.Policy, len(rows))
+	for _, r := range rows {
+		hosts[r.Host] = hostPolicy(r)
+	}
+
+	return download.NewManager(global, hosts), nil
+}
+
+// hostPolicy returns the limits recorded for a host.
+func hostPolicy(r dbq.DownloadHost) download.Policy {
+	return download.Policy{
+		LimitRate:   r.LimitRate.Int64,
+		Concurrency: int(r.Concurrency.Int64),
+	}
+}
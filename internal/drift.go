@@ -0,0 +1,69 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"github.com/mfinelli/modctl/dbq"
+	"go.finelli.dev/util"
+)
+
+type MovedInstall struct {
+	Install dbq.GameInstall
+	OldRoot string
+}
+
+// InstallDrift describes how the discovered game installs changed during a
+// store refresh.
+type InstallDrift struct {
+	Added   []dbq.GameInstall // new, or present again
+	Removed []dbq.GameInstall // no longer present
+	Moved   []MovedInstall    // install root changed
+}
+
+func (d InstallDrift) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+// DiffGameInstalls compares snapshots of the game_installs table taken
+// before and after a refresh.
+func DiffGameInstalls(before, after []dbq.GameInstall) InstallDrift {
+	prev := make(map[int64]dbq.GameInstall, len(before))
+	for _, gi := range before {
+		prev[gi.ID] = gi
+	}
+
+	var d InstallDrift
+	for _, gi := range after {
+		old, existed := prev[gi.ID]
+		present := util.SqliteIntToBool(gi.IsPresent)
+
+		switch {
+		case !existed || !util.SqliteIntToBool(old.IsPresent):
+			if present {
+				d.Added = append(d.Added, gi)
+			}
+		case !present:
+			d.Removed = append(d.Removed, gi)
+		case old.InstallRoot != gi.InstallRoot:
+			d.Moved = append(d.Moved, MovedInstall{Install: gi, OldRoot: old.InstallRoot})
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,103 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffGameInstalls(t *testing.T) {
+	t.Parallel()
+
+	gi := func(id int64, root string, present bool) dbq.GameInstall {
+		p := int64(0)
+		if present {
+			p = 1
+		}
+		return dbq.GameInstall{ID: id, InstallRoot: root, IsPresent: p}
+	}
+
+	tests := []struct {
+		name        string
+		before      []dbq.GameInstall
+		after       []dbq.GameInstall
+		wantAdded   []int64
+		wantRemoved []int64
+		wantMoved   []int64
+	}{
+		{
+			name:   "no changes",
+			before: []dbq.GameInstall{gi(1, "/a", true), gi(2, "/b", false)},
+			after:  []dbq.GameInstall{gi(1, "/a", true), gi(2, "/b", false)},
+		},
+		{
+			name:      "new install",
+			before:    []dbq.GameInstall{gi(1, "/a", true)},
+			after:     []dbq.GameInstall{gi(1, "/a", true), gi(2, "/b", true)},
+			wantAdded: []int64{2},
+		},
+		{
+			name:      "install is back",
+			before:    []dbq.GameInstall{gi(1, "/a", false)},
+			after:     []dbq.GameInstall{gi(1, "/a", true)},
+			wantAdded: []int64{1},
+		},
+		{
+			name:        "install disappeared",
+			before:      []dbq.GameInstall{gi(1, "/a", true)},
+			after:       []dbq.GameInstall{gi(1, "/a", false)},
+			wantRemoved: []int64{1},
+		},
+		{
+			name:      "install moved",
+			before:    []dbq.GameInstall{gi(1, "/a", true)},
+			after:     []dbq.GameInstall{gi(1, "/c", true)},
+			wantMoved: []int64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := DiffGameInstalls(tt.before, tt.after)
+
+			ids := func(gis []dbq.GameInstall) []int64 {
+				var out []int64
+				for _, g := range gis {
+					out = append(out, g.ID)
+				}
+				return out
+			}
+			var moved []int64
+			for _, m := range d.Moved {
+				moved = append(moved, m.Install.ID)
+			}
+
+			assert.Equal(t, tt.wantAdded, ids(d.Added))
+			assert.Equal(t, tt.wantRemoved, ids(d.Removed))
+			assert.Equal(t, tt.wantMoved, moved)
+			assert.Equal(t, tt.wantAdded == nil && tt.wantRemoved == nil && tt.wantMoved == nil, d.Empty())
+		})
+	}
+}
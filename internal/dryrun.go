@@ -0,0 +1,118 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/spf13/viper"
+)
+
+// dryRunDB is the throwaway copy of the database that SetupDB opens during a
+// dry run.
+var dryRunDB string
+
+// dryRunCopy returns the dry-run copy of the database at path, making it
+// first if needed.
+func dryRunCopy(path string) (string, error) {
+	if dryRunDB != "" {
+		return dryRunDB, nil
+	}
+
+	dir, err := os.MkdirTemp("", "modctl-dry-run-")
+	if err != nil {
+		return "", fmt.Errorf("dry run: %w", err)
+	}
+	cp := filepath.Join(dir, "modctl.db")
+
+	db, err := SetupDBReadOnly()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("dry run: %w", err)
+	}
+	defer db.Close()
+
+	// unlike copying the file this includes what's still in the WAL
+	if _, err := db.Exec("VACUUM INTO ?", cp); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("dry run: copy database: %w", err)
+	}
+
+	dryRunDB = cp
+	return cp, nil
+}
+
+// FinishDryRun reports what a dry run would have changed, removes the copy of
+// the database, and turns dry-run mode off. It does nothing if dry-run mode
+// isn't on.
+func FinishDryRun(ctx context.Context, w io.Writer) error {
+	if !dryrun.Enabled() {
+		return nil
+	}
+
+	cp := dryRunDB
+	defer func() {
+		if cp != "" {
+			os.RemoveAll(filepath.Dir(cp))
+		}
+		dryRunDB = ""
+		dryrun.Reset()
+	}()
+
+	var changes []dryrun.TableChange
+	if cp != "" {
+		db, err := SetupDBReadOnly()
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		defer db.Close()
+
+		changes, err = dryrun.Diff(ctx, db, viper.GetString("database"))
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+	}
+	notes := dryrun.Notes()
+
+	fmt.Fprintln(w, "dry run: nothing was changed")
+	if len(changes) == 0 && len(notes) == 0 {
+		fmt.Fprintln(w, "  (there was nothing to change)")
+		return nil
+	}
+
+	if len(changes) > 0 {
+		fmt.Fprintln(w, "  database rows that would change:")
+		for _, c := range changes {
+			fmt.Fprintf(w, "    %s\n", c)
+		}
+	}
+	if len(notes) > 0 {
+		fmt.Fprintln(w, "  files that would change:")
+		for _, n := range notes {
+			fmt.Fprintf(w, "    %s\n", n)
+		}
+	}
+
+	return nil
+}
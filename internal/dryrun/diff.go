@@ -0,0 +1,156 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package dryrun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// TableChange counts the rows of a table that a dry run changed.
+type TableChange struct {
+	Table    string
+	Inserted int64
+	Updated  int64
+	Deleted  int64
+}
+
+func (c TableChange) String() string {
+	var parts []string
+	if c.Inserted > 0 {
+		parts = append(parts, fmt.Sprintf("%d inserted", c.Inserted))
+	}
+	if c.Updated > 0 {
+		parts = append(parts, fmt.Sprintf("%d updated", c.Updated))
+	}
+	if c.Deleted > 0 {
+		parts = append(parts, fmt.Sprintf("%d deleted", c.Deleted))
+	}
+	return fmt.Sprintf("%s: %s", c.Table, strings.Join(parts, ", "))
+}
+
+// Diff compares every table of db (the dry-run copy) with the same table of
+// the database at origPath and returns the tables whose rows differ, in
+// table order. Rows are matched by primary key (or rowid if the table
+// doesn't have one).
+func Diff(ctx context.Context, db *sql.DB, origPath string) ([]TableChange, error) {
+	// a single connection so that the attached database stays attached
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS orig", origPath); err != nil {
+		return nil, fmt.Errorf("attach %s: %w", origPath, err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "DETACH DATABASE orig")
+
+	tables, err := stringColumn(ctx, conn, `
+		SELECT name FROM main.sqlite_schema
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		  AND name IN (SELECT name FROM orig.sqlite_schema WHERE type = 'table')
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+
+	var changes []TableChange
+	for _, t := range tables {
+		c, err := diffTable(ctx, conn, t)
+		if err != nil {
+			return nil, fmt.Errorf("compare %s: %w", t, err)
+		}
+		if c.Inserted+c.Updated+c.Deleted > 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes, nil
+}
+
+func diffTable(ctx context.Context, conn *sql.Conn, table string) (TableChange, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT name, pk FROM pragma_table_info(?, 'orig')", table)
+	if err != nil {
+		return TableChange{}, err
+	}
+	var cols, keys []string
+	for rows.Next() {
+		var name string
+		var pk int
+		if err := rows.Scan(&name, &pk); err != nil {
+			rows.Close()
+			return TableChange{}, err
+		}
+		cols = append(cols, quoteIdent(name))
+		if pk > 0 {
+			keys = append(keys, quoteIdent(name))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return TableChange{}, err
+	}
+	if len(keys) == 0 {
+		keys = []string{"rowid"}
+	}
+
+	same := func(a, b string, cols []string) string {
+		conds := make([]string, len(cols))
+		for i, c := range cols {
+			conds[i] = fmt.Sprintf("%s.%s IS %s.%s", a, c, b, c)
+		}
+		return strings.Join(conds, " AND ")
+	}
+
+	t := quoteIdent(table)
+	query := fmt.Sprintf(`SELECT
+		(SELECT count(*) FROM main.%[1]s m WHERE NOT EXISTS (SELECT 1 FROM orig.%[1]s o WHERE %[2]s)),
+		(SELECT count(*) FROM main.%[1]s m JOIN orig.%[1]s o ON %[2]s WHERE NOT (%[3]s)),
+		(SELECT count(*) FROM orig.%[1]s o WHERE NOT EXISTS (SELECT 1 FROM main.%[1]s m WHERE %[2]s))`,
+		t, same("m", "o", keys), same("m", "o", cols))
+
+	c := TableChange{Table: table}
+	err = conn.QueryRowContext(ctx, query).Scan(&c.Inserted, &c.Updated, &c.Deleted)
+	return c, err
+}
+
+func stringColumn(ctx context.Context, conn *sql.Conn, query string) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,76 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package dryrun
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	orig := filepath.Join(dir, "orig.db")
+
+	db, err := sql.Open("sqlite3", orig)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE profiles (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+		CREATE TABLE blobs (sha256 TEXT PRIMARY KEY, size INTEGER) WITHOUT ROWID;
+		CREATE TABLE notes (body TEXT);
+		INSERT INTO profiles (id, name) VALUES (1, 'default'), (2, 'old'), (3, 'keep');
+		INSERT INTO blobs VALUES ('aa', 1), ('bb', 2);
+		INSERT INTO notes VALUES ('x');`)
+	require.NoError(t, err)
+
+	_, err = db.Exec("VACUUM INTO ?", filepath.Join(dir, "copy.db"))
+	require.NoError(t, err)
+
+	cp, err := sql.Open("sqlite3", filepath.Join(dir, "copy.db"))
+	require.NoError(t, err)
+	defer cp.Close()
+
+	changes, err := Diff(ctx, cp, orig)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = cp.Exec(`
+		UPDATE profiles SET name = 'main' WHERE id = 1;
+		DELETE FROM profiles WHERE id = 2;
+		INSERT INTO profiles (id, name) VALUES (4, 'new');
+		INSERT INTO blobs VALUES ('cc', 3);
+		UPDATE blobs SET size = NULL WHERE sha256 = 'aa';`)
+	require.NoError(t, err)
+
+	changes, err = Diff(ctx, cp, orig)
+	require.NoError(t, err)
+	assert.Equal(t, []TableChange{
+		{Table: "blobs", Inserted: 1, Updated: 1},
+		{Table: "profiles", Inserted: 1, Updated: 1, Deleted: 1},
+	}, changes)
+	assert.Equal(t, "profiles: 1 inserted, 1 updated, 1 deleted", changes[1].String())
+}
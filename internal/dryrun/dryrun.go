@@ -0,0 +1,70 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package dryrun implements the global --dry-run flag. Commands run against a
+// throwaway copy of the database (see internal.SetupDB) and the blob stores
+// only note what they would have written or removed; afterwards the changes
+// to the copy are summarized with Diff.
+package dryrun
+
+import (
+	"fmt"
+	"io"
+)
+
+var (
+	enabled bool
+	notes   []string
+)
+
+// Enable turns on dry-run mode for the rest of the command.
+func Enable() {
+	enabled = true
+}
+
+// Enabled reports whether changes should only be pretended.
+func Enabled() bool {
+	return enabled
+}
+
+// Note records a filesystem change that was skipped because of the dry run.
+func Note(format string, args ...any) {
+	notes = append(notes, fmt.Sprintf(format, args...))
+}
+
+// Notes returns the skipped filesystem changes in the order they were noted.
+func Notes() []string {
+	return notes
+}
+
+// Report writes what a command changed (done) or, in dry-run mode, what it
+// would have changed: nothing is saved then, so it mustn't claim that
+// anything was done.
+func Report(w io.Writer, done, would string) {
+	if enabled {
+		fmt.Fprintln(w, "dry run: would "+would)
+		return
+	}
+	fmt.Fprintln(w, done)
+}
+
+// Reset turns dry-run mode off again and forgets the notes.
+func Reset() {
+	enabled = false
+	notes = nil
+}
@@ -0,0 +1,203 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/deploy"
+)
+
+var instanceIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// DuplicateResult is what DuplicateGameInstall made.
+type DuplicateResult struct {
+	Install dbq.GameInstall
+	Stats   deploy.TreeStats
+	// targets of the original that the copy doesn't have because they're
+	// outside of its install root (e.g., in the proton prefix): applying to
+	// them would change the original's files
+	Skipped []string
+}
+
+// CopyOf returns the id of the game install that a game install is a copy
+// of, 0 if it isn't one.
+func CopyOf(gi dbq.GameInstall) int64 {
+	if !gi.Metadata.Valid {
+		return 0
+	}
+	var meta struct {
+		CopyOf int64 `json:"copy_of"`
+	}
+	if err := json.Unmarshal([]byte(gi.Metadata.String), &meta); err != nil {
+		return 0
+	}
+	return meta.CopyOf
+}
+
+// NextCopyInstanceID returns the first free instance id for a copy of a game
+// install: copy, copy_2, copy_3, ...
+func NextCopyInstanceID(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall) (string, error) {
+	for n := 1; ; n++ {
+		id := "copy"
+		if n > 1 {
+			id = fmt.Sprintf("copy_%d", n)
+		}
+		_, err := q.GetGameInstallBySelector(ctx, dbq.GetGameInstallBySelectorParams{
+			StoreID:     gi.StoreID,
+			StoreGameID: gi.StoreGameID,
+			InstanceID:  id,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return id, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("get game install: %w", err)
+		}
+	}
+}
+
+// DuplicateGameInstall copies the install root of a game install to dest
+// (with the given method, see deploy.CopyTree) and registers the copy as a
+// new instance of the same game, with its own default profile and the
+// targets below its install root. If anything fails the copy is removed
+// again.
+func DuplicateGameInstall(ctx context.Context, db *sql.DB, q *dbq.Queries, gi dbq.GameInstall, dest, instanceID string, m deploy.Method, progress func(done, total int64)) (res DuplicateResult, err error) {
+	if !instanceIDPattern.MatchString(instanceID) {
+		return res, fmt.Errorf("invalid instance %q (lowercase letters, digits, _ and -)", instanceID)
+	}
+	_, err = q.GetGameInstallBySelector(ctx, dbq.GetGameInstallBySelectorParams{
+		StoreID:     gi.StoreID,
+		StoreGameID: gi.StoreGameID,
+		InstanceID:  instanceID,
+	})
+	if err == nil {
+		return res, fmt.Errorf("%s already exists", FullSelector(gi.StoreID, gi.StoreGameID, instanceID))
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return res, fmt.Errorf("get game install: %w", err)
+	}
+
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return res, err
+	}
+	for _, pair := range [][2]string{{dest, gi.InstallRoot}, {gi.InstallRoot, dest}} {
+		if in, err := IsUnderDir(pair[0], pair[1]); err != nil {
+			return res, err
+		} else if in {
+			return res, fmt.Errorf("%s and %s can't be inside of each other", dest, gi.InstallRoot)
+		}
+	}
+
+	// never remove something that was there before
+	if _, err := os.Lstat(dest); err == nil {
+		return res, fmt.Errorf("%s already exists", dest)
+	}
+
+	res.Stats, err = deploy.CopyTree(ctx, gi.InstallRoot, dest, m, progress)
+	if err != nil {
+		_ = os.RemoveAll(dest)
+		return res, fmt.Errorf("copy %s: %w", gi.InstallRoot, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(dest)
+		}
+	}()
+
+	meta := map[string]any{}
+	if gi.Metadata.Valid {
+		_ = json.Unmarshal([]byte(gi.Metadata.String), &meta)
+	}
+	meta["copy_of"] = gi.ID
+	meta["copied_from"] = gi.InstallRoot
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return res, err
+	}
+
+	di := dbq.UpsertGameInstallParams{
+		StoreID:         gi.StoreID,
+		StoreGameID:     gi.StoreGameID,
+		InstanceID:      instanceID,
+		CanonicalGameID: gi.CanonicalGameID,
+		DisplayName:     gi.DisplayName,
+		InstallRoot:     dest,
+		Metadata:        sql.NullString{String: string(metaJSON), Valid: true},
+		LastSeenAt:      sql.NullString{String: nowISO8601Z(), Valid: true},
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	id, err := qtx.UpsertGameInstall(ctx, di)
+	if err != nil {
+		return res, fmt.Errorf("create game install: %w", err)
+	}
+	if err := upsertGameDirTarget(ctx, qtx, id, dest); err != nil {
+		return res, fmt.Errorf("create target dir: %w", err)
+	}
+	if err := upsertTemplateTargets(ctx, qtx, id, di, false); err != nil {
+		return res, fmt.Errorf("create default targets: %w", err)
+	}
+	if err := qtx.EnsureDefaultProfile(ctx, id); err != nil {
+		return res, fmt.Errorf("create default profile: %w", err)
+	}
+
+	orig, err := qtx.ListTargetsForGameInstall(ctx, gi.ID)
+	if err != nil {
+		return res, fmt.Errorf("list targets: %w", err)
+	}
+	copied, err := qtx.ListTargetsForGameInstall(ctx, id)
+	if err != nil {
+		return res, fmt.Errorf("list targets: %w", err)
+	}
+	have := map[string]bool{}
+	for _, t := range copied {
+		have[t.Name] = true
+	}
+	for _, t := range orig {
+		if !have[t.Name] {
+			res.Skipped = append(res.Skipped, t.Name)
+		}
+	}
+
+	res.Install, err = qtx.GetGameInstallByID(ctx, id)
+	if err != nil {
+		return res, fmt.Errorf("get game install: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, fmt.Errorf("commit: %w", err)
+	}
+	return res, nil
+}
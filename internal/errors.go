@@ -0,0 +1,180 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The kinds of errors that modctl knows how to get out of; check for them
+// with errors.Is. Errors of these kinds usually are a *HintError, which has
+// the commands to run.
+var (
+	ErrNoActiveGame    = errors.New("no active game selected")
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrBlobMissing     = errors.New("archive missing from the blob store")
+	ErrConflict        = errors.New("files changed on disk")
+)
+
+// Exit codes of the error kinds, so that scripts can tell them apart without
+// parsing the message. Every other error exits with 1.
+const (
+	ExitNoActiveGame    = 3
+	ExitProfileNotFound = 4
+	ExitBlobMissing     = 5
+	ExitConflict        = 6
+)
+
+var errorKinds = []struct {
+	kind error
+	code string
+	exit int
+}{
+	{ErrNoActiveGame, "no_active_game", ExitNoActiveGame},
+	{ErrProfileNotFound, "profile_not_found", ExitProfileNotFound},
+	{ErrBlobMissing, "blob_missing", ExitBlobMissing},
+	{ErrConflict, "conflict", ExitConflict},
+}
+
+// HintError is an error of one of the kinds above with what the user can do
+// about it.
+type HintError struct {
+	Kind error
+	// Msg replaces the message of Kind, if it's set
+	Msg string
+	// Err is the cause, if there is one
+	Err error
+	// e.g., "run `modctl games set-active <game>`"
+	Hints []string
+}
+
+func (e *HintError) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = e.Kind.Error()
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *HintError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// ErrorHints returns the Hints.
+func (e *HintError) ErrorHints() []string {
+	return e.Hints
+}
+
+// NoActiveGameError is returned when a command needs a game and there's
+// neither an active one nor one given with flag (e.g., "--game").
+func NoActiveGameError(flag string) error {
+	return &HintError{
+		Kind:  ErrNoActiveGame,
+		Hints: []string{"run `modctl games set-active <game>`", "or pass " + flag},
+	}
+}
+
+// ProfileNotFoundError is returned when the game has no profile with the
+// given name.
+func ProfileNotFoundError(name string) error {
+	return &HintError{
+		Kind:  ErrProfileNotFound,
+		Msg:   fmt.Sprintf("profile %q not found for this game", name),
+		Hints: []string{"run `modctl profiles list` to see the profiles of the game"},
+	}
+}
+
+// BlobMissingError is returned when the archive with the given sha256 can't
+// be used because it isn't in the blob store (anymore), or was quarantined
+// (err says which).
+func BlobMissingError(sha string, err error) error {
+	return &HintError{
+		Kind: ErrBlobMissing,
+		Msg:  "archive " + shortSHA(sha),
+		Err:  err,
+		Hints: []string{
+			"run `modctl mods repair` to download it again",
+			"or import it again with `modctl mods import`",
+			"or list a copy of the blob store in blob_mirrors to fetch it from",
+		},
+	}
+}
+
+// ErrorHints returns what the user can do about err, if anything is known:
+// the hints of the first error in its chain that has some.
+func ErrorHints(err error) []string {
+	var h interface{ ErrorHints() []string }
+	if errors.As(err, &h) {
+		return h.ErrorHints()
+	}
+	return nil
+}
+
+// ErrorCode is the kind of err for scripts, e.g., "no_active_game", or
+// "error" if it's not one of the known kinds.
+func ErrorCode(err error) string {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			return k.code
+		}
+	}
+	return "error"
+}
+
+// ExitCode is the code that modctl exits with when a command fails with err.
+func ExitCode(err error) int {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			return k.exit
+		}
+	}
+	return 1
+}
+
+// ErrorJSON is how a failed command reports its error when it was asked for
+// JSON output.
+type ErrorJSON struct {
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Hints    []string `json:"hints,omitempty"`
+	ExitCode int      `json:"exit_code"`
+}
+
+// WriteErrorJSON writes err as {"error": ErrorJSON}.
+func WriteErrorJSON(w io.Writer, err error) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Error ErrorJSON `json:"error"`
+	}{ErrorJSON{
+		Code:     ErrorCode(err),
+		Message:  err.Error(),
+		Hints:    ErrorHints(err),
+		ExitCode: ExitCode(err),
+	}})
+}
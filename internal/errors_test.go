@@ -0,0 +1,92 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHintError(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("rename: %w", ProfileNotFoundError("survival"))
+	assert.Equal(t, `rename: profile "survival" not found for this game`, err.Error())
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+	assert.NotErrorIs(t, err, ErrNoActiveGame)
+	assert.Equal(t, []string{"run `modctl profiles list` to see the profiles of the game"}, ErrorHints(err))
+
+	err = BlobMissingError("0123456789abcdef", os.ErrNotExist)
+	assert.Equal(t, "archive 0123456789ab: file does not exist", err.Error())
+	assert.ErrorIs(t, err, ErrBlobMissing)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	err = NoActiveGameError("--game")
+	assert.Equal(t, "no active game selected", err.Error())
+	assert.Equal(t, []string{"run `modctl games set-active <game>`", "or pass --game"}, ErrorHints(err))
+
+	assert.Nil(t, ErrorHints(errors.New("boom")))
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		code string
+		exit int
+	}{
+		{errors.New("boom"), "error", 1},
+		{NoActiveGameError("--game"), "no_active_game", ExitNoActiveGame},
+		{fmt.Errorf("lookup: %w", ProfileNotFoundError("x")), "profile_not_found", ExitProfileNotFound},
+		{BlobMissingError("abc", os.ErrNotExist), "blob_missing", ExitBlobMissing},
+		{fmt.Errorf("apply: %w", &DriftError{Paths: []string{"a"}}), "conflict", ExitConflict},
+		{&BackupConflictError{}, "conflict", ExitConflict},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, ErrorCode(tt.err), tt.err.Error())
+		assert.Equal(t, tt.exit, ExitCode(tt.err), tt.err.Error())
+	}
+}
+
+func TestWriteErrorJSON(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	require.NoError(t, WriteErrorJSON(&b, NoActiveGameError("--game")))
+
+	var got struct {
+		Error ErrorJSON `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &got))
+	assert.Equal(t, ErrorJSON{
+		Code:     "no_active_game",
+		Message:  "no active game selected",
+		Hints:    []string{"run `modctl games set-active <game>`", "or pass --game"},
+		ExitCode: ExitNoActiveGame,
+	}, got.Error)
+}
@@ -0,0 +1,181 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrApplyCancelled is returned by Apply when Deployer.Confirm declined a
+// long apply; nothing was changed.
+var ErrApplyCancelled = errors.New("apply cancelled")
+
+const (
+	// applies that are expected to take at least this long are long
+	longApplyDuration = time.Minute
+	// without past applies to go by, applies that extract at least this
+	// much are long
+	longApplyBytes = 2 << 30
+	// how many of the newest successful applies the throughput is
+	// estimated from
+	throughputSamples = 20
+)
+
+// ApplyEstimate is what an apply is about to do and about how long it takes,
+// so that a long one can be confirmed before anything is changed.
+type ApplyEstimate struct {
+	// the mods (versions) that the profile deploys, and the ones whose
+	// archives have to be extracted because their files aren't deployed
+	// yet (or changed)
+	Mods          int `json:"mods"`
+	ModsToExtract int `json:"mods_to_extract"`
+	// the files that are written
+	Files int `json:"files"`
+	// the size of the archives that are extracted and of what's in them
+	ArchiveBytes  int64 `json:"archive_bytes"`
+	UnpackedBytes int64 `json:"unpacked_bytes"`
+	// the size of the archives that are missing from the blob store (or
+	// were quarantined) and are fetched from the blob mirrors first; what's
+	// in them is only counted in UnpackedBytes if it's known already
+	DownloadBytes int64 `json:"download_bytes"`
+	// estimated from how fast the newest successful applies extracted and
+	// deployed their files; 0 if there aren't any to go by
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Long reports whether the apply is expected to take long enough to ask
+// before starting it. Past applies don't say how fast archives are
+// fetched, so downloading at least as much as is long to extract is long
+// too.
+func (e ApplyEstimate) Long() bool {
+	if e.DownloadBytes >= longApplyBytes {
+		return true
+	}
+	if e.Duration > 0 {
+		return e.Duration >= longApplyDuration
+	}
+	return e.UnpackedBytes >= longApplyBytes
+}
+
+// estimate estimates an apply that writes the pending desired files.
+func (d *Deployer) estimate(ctx context.Context, desired map[pathKey]*desiredFile, pending []pathKey) (ApplyEstimate, error) {
+	est := ApplyEstimate{Files: len(pending)}
+
+	mods := map[int64]bool{}
+	for _, f := range desired {
+		if f.archiveSHA != "" {
+			mods[f.versionID] = true
+		}
+	}
+	est.Mods = len(mods)
+
+	extract := map[int64]bool{}
+	archives := map[string]bool{}
+	for _, k := range pending {
+		f := desired[k]
+		if f.archiveSHA == "" {
+			continue
+		}
+		extract[f.versionID] = true
+		if archives[f.archiveSHA] {
+			continue
+		}
+		archives[f.archiveSHA] = true
+
+		b, err := d.Q.GetBlob(ctx, f.archiveSHA)
+		if err != nil {
+			return est, fmt.Errorf("get blob %s: %w", shortSHA(f.archiveSHA), err)
+		}
+		est.ArchiveBytes += b.SizeBytes
+
+		// there's nothing to list until it's fetched
+		missing, err := d.archiveMissing(b)
+		if err != nil {
+			return est, err
+		}
+		if missing {
+			if len(d.Mirrors) > 0 {
+				est.DownloadBytes += b.SizeBytes
+			}
+			if b.UnpackedBytes.Valid {
+				est.UnpackedBytes += b.UnpackedBytes.Int64
+			}
+			continue
+		}
+
+		n, err := UnpackedSize(ctx, d.Q, d.Blobs, d.Bsdtar, f.archiveSHA)
+		if err != nil {
+			return est, err
+		}
+		est.UnpackedBytes += n
+	}
+	est.ModsToExtract = len(extract)
+
+	meta, err := d.Q.ListApplyMetadata(ctx, throughputSamples)
+	if err != nil {
+		return est, fmt.Errorf("list past applies: %w", err)
+	}
+	if rate := applyThroughput(meta); rate > 0 {
+		est.Duration = time.Duration(float64(est.UnpackedBytes) / rate * float64(time.Second))
+	}
+
+	return est, nil
+}
+
+// applyThroughput returns how many bytes per second the applies with the
+// given results (as recorded in the journal) extracted and deployed, or 0 if
+// none of them extracted anything. Results that can't be decoded are skipped.
+func applyThroughput(meta []sql.NullString) float64 {
+	var bytes int64
+	var took time.Duration
+	for _, m := range meta {
+		var res DeployResult
+		if !m.Valid || json.Unmarshal([]byte(m.String), &res) != nil || res.ExtractedBytes == 0 {
+			continue
+		}
+		bytes += res.ExtractedBytes
+		for _, p := range res.Phases {
+			if p.Name == "extract" || p.Name == "deploy" {
+				took += p.Duration
+			}
+		}
+	}
+
+	if bytes == 0 || took <= 0 {
+		return 0
+	}
+	return float64(bytes) / took.Seconds()
+}
+
+// stagedBytes returns the size of the files that an archive was staged to.
+func stagedBytes(files map[string]string) int64 {
+	var n int64
+	for _, path := range files {
+		if st, err := os.Lstat(path); err == nil {
+			n += st.Size()
+		}
+	}
+	return n
+}
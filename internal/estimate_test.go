@@ -0,0 +1,109 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEstimateLong(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, ApplyEstimate{UnpackedBytes: 100 << 20}.Long())
+	assert.True(t, ApplyEstimate{UnpackedBytes: 3 << 30}.Long())
+
+	// past applies win over the size
+	assert.False(t, ApplyEstimate{UnpackedBytes: 3 << 30, Duration: 20 * time.Second}.Long())
+	assert.True(t, ApplyEstimate{UnpackedBytes: 100 << 20, Duration: 2 * time.Minute}.Long())
+
+	// fetching isn't timed
+	assert.True(t, ApplyEstimate{DownloadBytes: 3 << 30, Duration: 20 * time.Second}.Long())
+}
+
+func TestEstimateMissingArchives(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, _, _ := testDeployer(t)
+	d.Mirrors = []string{"https://mirror.example.com"}
+	target := &deployTarget{}
+
+	present := writeBlob(t, d, d.Blobs.ArchivesDir, blobstore.KindArchive, "present archive")
+	missing := writeBlob(t, d, d.Blobs.ArchivesDir, blobstore.KindArchive, "missing archive")
+	path, err := d.Blobs.PathFor(blobstore.KindArchive, missing)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(path))
+	for _, sha := range []string{present, missing} {
+		_, err := d.DB.Exec(`UPDATE blobs SET unpacked_bytes = 1000 WHERE sha256 = ?`, sha)
+		require.NoError(t, err)
+	}
+
+	desired := map[pathKey]*desiredFile{
+		{1, "a.esp"}: {target: target, relpath: "a.esp", versionID: 1, archiveSHA: present},
+		{1, "b.esp"}: {target: target, relpath: "b.esp", versionID: 2, archiveSHA: missing},
+	}
+	pending := []pathKey{{1, "a.esp"}, {1, "b.esp"}}
+
+	est, err := d.estimate(ctx, desired, pending)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("present archive")+len("missing archive")), est.ArchiveBytes)
+	assert.Equal(t, int64(len("missing archive")), est.DownloadBytes)
+	assert.Equal(t, int64(2000), est.UnpackedBytes)
+
+	// nothing is fetched without mirrors (the apply fails instead)
+	d.Mirrors = nil
+	est, err = d.estimate(ctx, desired, pending)
+	require.NoError(t, err)
+	assert.Zero(t, est.DownloadBytes)
+}
+
+func TestApplyThroughput(t *testing.T) {
+	t.Parallel()
+
+	meta := func(res DeployResult) sql.NullString {
+		b, err := json.Marshal(res)
+		require.NoError(t, err)
+		return sql.NullString{String: string(b), Valid: true}
+	}
+
+	assert.Zero(t, applyThroughput(nil))
+
+	rows := []sql.NullString{
+		meta(DeployResult{ExtractedBytes: 300, Phases: []Phase{
+			{Name: "prepare", Duration: time.Hour},
+			{Name: "extract", Duration: time.Second},
+			{Name: "deploy", Duration: 2 * time.Second},
+		}}),
+		// nothing was extracted, so it doesn't say anything
+		meta(DeployResult{Phases: []Phase{{Name: "deploy", Duration: time.Minute}}}),
+		meta(DeployResult{ExtractedBytes: 100, Phases: []Phase{{Name: "extract", Duration: time.Second}}}),
+		{},
+		{String: "{", Valid: true},
+	}
+	assert.InDelta(t, 100.0, applyThroughput(rows), 0.001)
+}
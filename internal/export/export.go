@@ -0,0 +1,234 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package export dumps the state in the database to JSON for backups,
+// diffing between machines, or dashboards. Rows are exported as they are
+// stored (one object per row, keyed by column name) so that the export
+// follows the schema without any mapping.
+package export
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Format identifies exports, Version changes when their shape does.
+const (
+	Format  = "modctl-export"
+	Version = 1
+)
+
+// Tables are the tables in an export, in an order where rows (mostly) only
+// reference rows of earlier tables.
+var Tables = []string{
+	"stores",
+	"game_installs",
+	"targets",
+	"blobs",
+	"mod_pages",
+	"mod_files",
+	"mod_file_versions",
+	"mod_attachments",
+	"remap_configs",
+	"remap_rules",
+	"profiles",
+	"profile_items",
+	"profile_item_hidden_files",
+	"profile_path_policies",
+	"plugin_orders",
+	"overrides",
+	"operations",
+	"operation_changes",
+	"operation_notes",
+	"installed_files",
+	"backups",
+	"baseline_files",
+	"settings",
+	"download_hosts",
+	"advisories",
+}
+
+// Meta describes an export.
+type Meta struct {
+	Format        string `json:"format"`
+	Version       int    `json:"version"`
+	SchemaVersion int64  `json:"schema_version"`
+	ExportedAt    string `json:"exported_at"`
+}
+
+// Row is a line of a JSONL export.
+type Row struct {
+	Table string         `json:"table"`
+	Row   map[string]any `json:"row"`
+}
+
+// Options are the settings of Write.
+type Options struct {
+	// one JSON document instead of JSON lines
+	JSON bool
+	// the tables to export (in the order of Tables); all of them if empty
+	Tables []string
+	// the goose version of the database
+	SchemaVersion int64
+	// when the export was made, now if zero
+	Now time.Time
+}
+
+// Write exports the tables of db to w. A JSON export is a single object
+// with the Meta fields and a "tables" object of table name to rows; a JSON
+// lines export is the Meta object followed by one Row per line. Rows are
+// ordered by primary key and the tables are read in a single transaction so
+// that the export is consistent.
+func Write(ctx context.Context, db *sql.DB, w io.Writer, opts Options) error {
+	tables := Tables
+	if len(opts.Tables) > 0 {
+		for _, t := range opts.Tables {
+			if !slices.Contains(Tables, t) {
+				return fmt.Errorf("unknown table %q (known: %s)", t, strings.Join(Tables, ", "))
+			}
+		}
+		tables = slices.DeleteFunc(slices.Clone(Tables), func(t string) bool {
+			return !slices.Contains(opts.Tables, t)
+		})
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	meta := Meta{
+		Format:        Format,
+		Version:       Version,
+		SchemaVersion: opts.SchemaVersion,
+		ExportedAt:    now.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if opts.JSON {
+		doc := struct {
+			Meta
+			Tables map[string][]map[string]any `json:"tables"`
+		}{Meta: meta, Tables: map[string][]map[string]any{}}
+
+		for _, t := range tables {
+			rows := []map[string]any{}
+			err := readTable(ctx, tx, t, func(row map[string]any) error {
+				rows = append(rows, row)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			doc.Tables[t] = rows
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(meta); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		err := readTable(ctx, tx, t, func(row map[string]any) error {
+			return enc.Encode(Row{Table: t, Row: row})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTable calls fn with every row of table, ordered by primary key.
+func readTable(ctx context.Context, tx *sql.Tx, table string, fn func(map[string]any) error) error {
+	var keys []string
+	pk, err := tx.QueryContext(ctx, "SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk", table)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", table, err)
+	}
+	for pk.Next() {
+		var k string
+		if err := pk.Scan(&k); err != nil {
+			pk.Close()
+			return fmt.Errorf("read %s: %w", table, err)
+		}
+		keys = append(keys, quoteIdent(k))
+	}
+	pk.Close()
+	if err := pk.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", table, err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"rowid"}
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s ORDER BY %s",
+		quoteIdent(table), strings.Join(keys, ", ")))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", table, err)
+	}
+
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return fmt.Errorf("read %s: %w", table, err)
+		}
+
+		row := make(map[string]any, len(cols))
+		for i, c := range cols {
+			row[c] = vals[i]
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", table, err)
+	}
+	return nil
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,109 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+t.TempDir()+"/test.db")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE stores (id TEXT PRIMARY KEY, enabled INTEGER) WITHOUT ROWID;
+		CREATE TABLE profiles (id INTEGER PRIMARY KEY, name TEXT NOT NULL, description TEXT);
+		INSERT INTO stores VALUES ('steam', 1), ('gog', 0);
+		INSERT INTO profiles (id, name, description) VALUES (2, 'modded', 'all of them'), (1, 'default', NULL);`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestWriteJSONL(t *testing.T) {
+	db := testDB(t)
+
+	var buf bytes.Buffer
+	err := Write(context.Background(), db, &buf, Options{
+		Tables:        []string{"profiles", "stores"},
+		SchemaVersion: 24,
+		Now:           time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"format":"modctl-export","version":1,"schema_version":24,"exported_at":"2026-03-01T12:00:00.000Z"}
+{"table":"stores","row":{"enabled":0,"id":"gog"}}
+{"table":"stores","row":{"enabled":1,"id":"steam"}}
+{"table":"profiles","row":{"description":null,"id":1,"name":"default"}}
+{"table":"profiles","row":{"description":"all of them","id":2,"name":"modded"}}
+`, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	db := testDB(t)
+
+	var buf bytes.Buffer
+	err := Write(context.Background(), db, &buf, Options{
+		JSON:   true,
+		Tables: []string{"profiles"},
+	})
+	require.NoError(t, err)
+
+	var doc struct {
+		Meta
+		Tables map[string][]map[string]any `json:"tables"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, Format, doc.Format)
+	assert.Equal(t, Version, doc.Version)
+	assert.Equal(t, []string{"profiles"}, keys(doc.Tables))
+	assert.Equal(t, []map[string]any{
+		{"id": float64(1), "name": "default", "description": nil},
+		{"id": float64(2), "name": "modded", "description": "all of them"},
+	}, doc.Tables["profiles"])
+}
+
+func TestWriteUnknownTable(t *testing.T) {
+	db := testDB(t)
+
+	err := Write(context.Background(), db, &strings.Builder{}, Options{Tables: []string{"nope"}})
+	assert.ErrorContains(t, err, `unknown table "nope"`)
+}
+
+func keys(m map[string][]map[string]any) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// FlatpakSteamID is the flatpak app id of (flathub's) steam.
+const FlatpakSteamID = "com.valvesoftware.Steam"
+
+// flatpakSteamDir returns the directory that flatpak keeps the data of
+// steam in (~/.var/app/com.valvesoftware.Steam).
+func flatpakSteamDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".var", "app", FlatpakSteamID)
+}
+
+// isFlatpakSteamRoot reports whether a (canonical) steam root belongs to
+// flatpak steam.
+func isFlatpakSteamRoot(root string) bool {
+	dir := flatpakSteamDir()
+	if dir == "" {
+		return false
+	}
+
+	for _, d := range []string{dir, mustCanonicalize(dir)} {
+		if ok, err := IsUnderDir(root, d); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func mustCanonicalize(p string) string {
+	c, err := canonicalizePathBestEffort(p)
+	if err != nil {
+		return p
+	}
+	return c
+}
+
+// SteamFlatpak reports whether a steam game was installed by flatpak steam,
+// i.e., the game runs in its sandbox and only sees the steam data directory,
+// its libraries, and whatever else was granted to it.
+func SteamFlatpak(gi dbq.GameInstall) bool {
+	if !RunsInSteam(gi) {
+		return false
+	}
+
+	if gi.Metadata.Valid {
+		var meta struct {
+			Flatpak bool `json:"flatpak"`
+		}
+		if err := json.Unmarshal([]byte(gi.Metadata.String), &meta); err == nil && meta.Flatpak {
+			return true
+		}
+	}
+
+	// installs discovered before we tagged them
+	return isFlatpakSteamRoot(gi.InstallRoot)
+}
+
+// FlatpakOverrideCommand returns the command that gives flatpak steam (and
+// the games that it runs) access to dir.
+func FlatpakOverrideCommand(dir string) string {
+	if strings.ContainsAny(dir, " \t'\"\\$`") {
+		dir = "'" + strings.ReplaceAll(dir, "'", `'\''`) + "'"
+	}
+	return fmt.Sprintf("flatpak override --user --filesystem=%s %s", dir, FlatpakSteamID)
+}
@@ -0,0 +1,41 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatpakSteam(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	steamRoot := filepath.Join(home, ".var", "app", FlatpakSteamID, "data", "Steam")
+	assert.True(t, isFlatpakSteamRoot(steamRoot))
+	assert.False(t, isFlatpakSteamRoot(filepath.Join(home, ".local", "share", "Steam")))
+	assert.False(t, isFlatpakSteamRoot(filepath.Join(home, ".var", "app", "com.example.Other")))
+
+	assert.Equal(t, "flatpak override --user --filesystem=/mnt/games com.valvesoftware.Steam",
+		FlatpakOverrideCommand("/mnt/games"))
+	assert.Equal(t, "flatpak override --user --filesystem='/mnt/my games' com.valvesoftware.Steam",
+		FlatpakOverrideCommand("/mnt/my games"))
+}
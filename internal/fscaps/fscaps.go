@@ -0,0 +1,163 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package fscaps finds out what a filesystem supports by trying it with
+// temporary files: hardlinks, reflinks (copy-on-write clones), symlinks, and
+// whether file names are case-sensitive. Applying a profile uses it to choose
+// how to put files in place.
+package fscaps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Caps are the capabilities of the filesystem of a directory.
+type Caps struct {
+	// files can be hardlinked (or reflinked) from the source directory
+	// that the probe was given, see Probe
+	Hardlinks bool `json:"hardlinks"`
+	Reflinks  bool `json:"reflinks"`
+
+	Symlinks      bool `json:"symlinks"`
+	CaseSensitive bool `json:"case_sensitive"`
+	// only names that are valid on Windows can be created (Windows itself,
+	// SMB shares of a Windows machine, vfat/exfat, ntfs-3g with
+	// windows_names)
+	WindowsNames bool `json:"windows_names"`
+}
+
+// ErrUnsupported is returned by Clone where reflinks aren't implemented.
+var ErrUnsupported = errors.New("reflinks are not supported on this platform")
+
+// Probe finds out what the filesystem of dir supports. Hardlinks and
+// reflinks are tried from a file in from into dir (pass dir itself to probe
+// within a filesystem). If dir doesn't exist (yet) its closest existing
+// parent is probed instead. The probe files are removed again.
+func Probe(dir, from string) (Caps, error) {
+	var c Caps
+
+	dir, err := ExistingParent(dir)
+	if err != nil {
+		return c, err
+	}
+	from, err = ExistingParent(from)
+	if err != nil {
+		return c, err
+	}
+
+	src, err := os.CreateTemp(from, ".modctl-probe-*")
+	if err != nil {
+		return c, fmt.Errorf("create probe file: %w", err)
+	}
+	srcName := src.Name()
+	defer os.Remove(srcName)
+	_, err = src.WriteString("modctl")
+	if cerr := src.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return c, fmt.Errorf("write probe file: %w", err)
+	}
+
+	// lowercase so that the uppercase name is a different one on
+	// case-sensitive filesystems
+	dst, err := os.CreateTemp(dir, ".modctl-probe-*")
+	if err != nil {
+		return c, fmt.Errorf("create probe file: %w", err)
+	}
+	dstName := dst.Name()
+	defer os.Remove(dstName)
+	_ = dst.Close()
+
+	c.CaseSensitive = caseSensitive(dstName)
+	c.Reflinks = Clone(srcName, dstName) == nil
+
+	link := dstName + "-link"
+	if err := os.Link(srcName, link); err == nil {
+		c.Hardlinks = true
+		_ = os.Remove(link)
+	}
+
+	if err := os.Symlink(filepath.Base(dstName), link); err == nil {
+		c.Symlinks = true
+		_ = os.Remove(link)
+	}
+
+	// not ':', which makes an alternate data stream on NTFS
+	invalid := dstName + "-<|>"
+	if f, err := os.Create(invalid); err == nil {
+		_ = f.Close()
+		_ = os.Remove(invalid)
+	} else {
+		c.WindowsNames = true
+	}
+
+	return c, nil
+}
+
+// Writable returns an error if files can't be created in dir (or in its
+// closest existing parent if it doesn't exist yet).
+func Writable(dir string) error {
+	dir, err := ExistingParent(dir)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".modctl-probe-*")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// caseSensitive reports whether the file at path (with a lowercase name)
+// can't also be found with an uppercase one.
+func caseSensitive(path string) bool {
+	upper := filepath.Join(filepath.Dir(path), strings.ToUpper(filepath.Base(path)))
+	_, err := os.Lstat(upper)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// ExistingParent returns dir if it exists, otherwise its closest parent that
+// does.
+func ExistingParent(dir string) (string, error) {
+	dir = filepath.Clean(dir)
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return "", fmt.Errorf("%s is not a directory", dir)
+			}
+			return dir, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", err
+		}
+		dir = parent
+	}
+}
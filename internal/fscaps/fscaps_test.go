@@ -0,0 +1,96 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package fscaps
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	caps, err := Probe(filepath.Join(dir, "not", "yet"), dir)
+	require.NoError(t, err)
+
+	assert.True(t, caps.Hardlinks)
+	if runtime.GOOS == "linux" {
+		assert.True(t, caps.Symlinks)
+		assert.True(t, caps.CaseSensitive)
+		assert.False(t, caps.WindowsNames)
+	}
+
+	// the probe files are gone again
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestNetworkFS(t *testing.T) {
+	t.Parallel()
+
+	// the temp dir is on a local disk (or tmpfs)
+	assert.Empty(t, NetworkFS(t.TempDir()))
+	assert.Empty(t, NetworkFS(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestExistingParent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "a")
+	require.NoError(t, os.Mkdir(sub, 0o755))
+	file := filepath.Join(dir, "f")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0o644))
+
+	got, err := ExistingParent(sub)
+	require.NoError(t, err)
+	assert.Equal(t, sub, got)
+
+	got, err = ExistingParent(filepath.Join(sub, "b", "c"))
+	require.NoError(t, err)
+	assert.Equal(t, sub, got)
+
+	_, err = ExistingParent(filepath.Join(file, "b"))
+	assert.Error(t, err)
+}
+
+func TestWritable(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, Writable(filepath.Join(dir, "not", "yet")))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		return
+	}
+	ro := filepath.Join(dir, "ro")
+	require.NoError(t, os.Mkdir(ro, 0o555))
+	assert.Error(t, Writable(filepath.Join(ro, "mods")))
+}
@@ -0,0 +1,48 @@
+//go:build linux
+
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package fscaps
+
+import "golang.org/x/sys/unix"
+
+// networkFSTypes are the statfs(2) magic numbers of network filesystems.
+// FUSE filesystems (sshfs, rclone, gvfs) can't be told apart from local
+// ones and aren't included.
+var networkFSTypes = map[uint32]string{
+	unix.NFS_SUPER_MAGIC:  "nfs",
+	unix.SMB_SUPER_MAGIC:  "smb",
+	unix.SMB2_SUPER_MAGIC: "smb",
+	unix.CIFS_SUPER_MAGIC: "smb",
+	unix.AFS_SUPER_MAGIC:  "afs",
+	unix.V9FS_MAGIC:       "9p",
+	unix.CEPH_SUPER_MAGIC: "ceph",
+}
+
+// NetworkFS returns the type of the filesystem that path is on if it's a
+// network one (e.g., "nfs" or "smb"), and "" otherwise or if that can't be
+// found out.
+func NetworkFS(path string) string {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return ""
+	}
+	// the magic numbers are 32 bits, Type is signed on some platforms
+	return networkFSTypes[uint32(st.Type)]
+}
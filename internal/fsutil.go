@@ -19,6 +19,9 @@
 package internal
 
 import (
+	"errors"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
 )
@@ -76,3 +79,34 @@ func IsUnderDir(path, dir string) (bool, error) {
 
 	return true, nil
 }
+
+// DirSize returns the total size of the regular files under dir (it doesn't
+// follow symlinks). A missing directory has size zero.
+func DirSize(dir string) (int64, error) {
+	var total int64
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil // removed while we were walking
+			}
+			return err
+		}
+		total += info.Size()
+
+		return nil
+	})
+
+	return total, err
+}
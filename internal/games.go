@@ -21,11 +21,18 @@ package internal
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/mfinelli/modctl/internal/steam"
+	"github.com/spf13/viper"
 )
 
 func ResolveGameInstallArg(ctx context.Context, q *dbq.Queries, arg string) (dbq.GameInstall, error) {
@@ -35,6 +42,14 @@ func ResolveGameInstallArg(ctx context.Context, q *dbq.Queries, arg string) (dbq
 		gi, err := q.GetGameInstallByID(ctx, id)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
+				if a, _ := state.LoadActive(); a.ActiveGameInstallID == id {
+					return dbq.GameInstall{}, &HintError{
+						Kind: ErrNoActiveGame,
+						Msg: fmt.Sprintf("the active game install %d (%s) no longer exists",
+							id, a.ActiveGameInstallSelector),
+						Hints: []string{"run `modctl context repair`", "or `modctl games set-active <game>`"},
+					}
+				}
 				return dbq.GameInstall{}, fmt.Errorf("no game install with id %d", id)
 			}
 			return dbq.GameInstall{}, fmt.Errorf("get game install by id: %w", err)
@@ -107,3 +122,96 @@ func ResolveGameInstallArg(ctx context.Context, q *dbq.Queries, arg string) (dbq
 	}
 	return dbq.GameInstall{}, errors.New(b.String())
 }
+
+// SteamShortcutStore is the store of the non-steam games that were added to
+// steam (see steam.Shortcut); steam runs them with proton like its own games.
+const SteamShortcutStore = "steam-shortcut"
+
+// RunsInSteam reports whether steam runs a game, i.e., whether it's a steam
+// game or a non-steam game added to steam.
+func RunsInSteam(gi dbq.GameInstall) bool {
+	return gi.StoreID == "steam" || gi.StoreID == SteamShortcutStore
+}
+
+// SteamProtonPrefix returns the proton (wine) prefix of a steam game install:
+// <library>/steamapps/compatdata/<appid>/pfx. The prefix only exists once the
+// game has been run with proton at least once. Copies of a game install (see
+// DuplicateGameInstall) don't have one: it would be the original's.
+func SteamProtonPrefix(gi dbq.GameInstall) (string, error) {
+	if CopyOf(gi) != 0 {
+		return "", fmt.Errorf("%s#%s is a copy and doesn't have a proton prefix of its own",
+			gi.DisplayName, gi.InstanceID)
+	}
+
+	steamapps, err := SteamappsDir(gi)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(steamapps, "compatdata", gi.StoreGameID, "pfx"), nil
+}
+
+// SteamappsDir returns the steamapps directory of the steam library that a
+// steam game is installed in (for a non-steam game: the library of steam
+// itself, where its proton prefix is).
+func SteamappsDir(gi dbq.GameInstall) (string, error) {
+	if !RunsInSteam(gi) {
+		return "", fmt.Errorf("%s is not a steam game", gi.DisplayName)
+	}
+
+	// prefer what we recorded during discovery, otherwise install_root is
+	// <library>/steamapps/common/<installdir>
+	steamapps := filepath.Dir(filepath.Dir(gi.InstallRoot))
+	if gi.Metadata.Valid {
+		var meta struct {
+			SteamappsRoot string `json:"steamapps_root"`
+		}
+		if err := json.Unmarshal([]byte(gi.Metadata.String), &meta); err == nil && meta.SteamappsRoot != "" {
+			steamapps = meta.SteamappsRoot
+		}
+	}
+
+	return steamapps, nil
+}
+
+// SteamAccount returns the steam account whose userdata a steam game uses
+// (see steam.PickAccount), from the steam installation that its library
+// belongs to, and the account that steam installed the game for (0 if we
+// don't know). The steam_account config option picks one explicitly, e.g.,
+// for a game shared with Family Sharing.
+func SteamAccount(gi dbq.GameInstall) (steam.Account, uint64, error) {
+	if !RunsInSteam(gi) {
+		return steam.Account{}, 0, fmt.Errorf("%s is not a steam game", gi.DisplayName)
+	}
+
+	var meta struct {
+		SteamRoot string `json:"steam_root"`
+		LastOwner string `json:"last_owner"`
+	}
+	if gi.Metadata.Valid {
+		_ = json.Unmarshal([]byte(gi.Metadata.String), &meta)
+	}
+	owner, _ := strconv.ParseUint(meta.LastOwner, 10, 64)
+
+	// installs discovered before we recorded the steam root
+	root := meta.SteamRoot
+	if root == "" {
+		for _, r := range candidateSteamRoots() {
+			if st, err := os.Stat(filepath.Join(r, "userdata")); err == nil && st.IsDir() {
+				root = r
+				break
+			}
+		}
+	}
+	if root == "" {
+		return steam.Account{}, owner, errors.New("steam installation not found")
+	}
+
+	accounts, err := steam.LoadAccounts(root)
+	if err != nil {
+		return steam.Account{}, owner, err
+	}
+
+	a, err := steam.PickAccount(accounts, owner, viper.GetString("steam_account"))
+	return a, owner, err
+}
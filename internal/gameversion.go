@@ -0,0 +1,71 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/steam"
+)
+
+// GameVersion returns the version of a game install that is installed right
+// now, as far as modctl can tell: the build id of steam games. It's "" for
+// games whose version can't be detected, including copies of a game install
+// (see DuplicateGameInstall): steam doesn't update those.
+func GameVersion(gi dbq.GameInstall) (string, error) {
+	if gi.StoreID != "steam" || CopyOf(gi) != 0 {
+		return "", nil
+	}
+
+	steamapps, err := SteamappsDir(gi)
+	if err != nil {
+		return "", err
+	}
+
+	build, err := steam.AppBuildID(filepath.Join(steamapps, "appmanifest_"+gi.StoreGameID+".acf"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return build, err
+}
+
+// gameVersionWarning returns a warning if a profile was built against another
+// version of the game than the one that is installed, "" otherwise.
+func gameVersionWarning(gi dbq.GameInstall, p dbq.Profile) string {
+	if !p.GameVersion.Valid {
+		return ""
+	}
+
+	installed, err := GameVersion(gi)
+	if err != nil {
+		return fmt.Sprintf("couldn't detect the game version to compare with the profile's (%s): %v",
+			p.GameVersion.String, err)
+	}
+	if installed == "" || installed == p.GameVersion.String {
+		return ""
+	}
+
+	return fmt.Sprintf("profile %q was built against game version %s but version %s is installed; "+
+		"mods often break when a game is patched (run `modctl profiles game-version %s --current` once it works)",
+		p.Name, p.GameVersion.String, installed, p.Name)
+}
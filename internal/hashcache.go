@@ -0,0 +1,68 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/readonly"
+)
+
+// HashCache is the hash cache (see deploy.HashCache) in the database. With
+// --read-only it's only read.
+type HashCache struct {
+	Q *dbq.Queries
+}
+
+func (c HashCache) Get(ctx context.Context, path string, key deploy.FileKey) (string, bool, error) {
+	sha, err := c.Q.GetFileHash(ctx, dbq.GetFileHashParams{
+		Path: path,
+		// sqlite only has signed integers, but the bits are the same
+		Device:    int64(key.Device),
+		Inode:     int64(key.Inode),
+		SizeBytes: key.Size,
+		MtimeNs:   key.MtimeNS,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return sha, true, nil
+}
+
+func (c HashCache) Put(ctx context.Context, path string, key deploy.FileKey, sha string) error {
+	if readonly.Enabled() {
+		return nil
+	}
+
+	return c.Q.UpsertFileHash(ctx, dbq.UpsertFileHashParams{
+		Path:      path,
+		Device:    int64(key.Device),
+		Inode:     int64(key.Inode),
+		SizeBytes: key.Size,
+		MtimeNs:   key.MtimeNS,
+		Sha256:    sha,
+	})
+}
@@ -19,8 +19,10 @@
 package internal
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func ParseInt64(s string) (int64, bool) {
@@ -31,3 +33,106 @@ func ParseInt64(s string) (int64, bool) {
 	n, err := strconv.ParseInt(s, 10, 64)
 	return n, err == nil
 }
+
+// FormatBytes formats a size for humans using binary units (e.g., 1.5 MiB).
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 5; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseBytes parses a size in bytes, optionally with a binary unit (e.g.,
+// "4096", "512KiB", or "1 MiB"; K, M, and G are short for KiB, MiB, and
+// GiB).
+func ParseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	num, unit := s, ""
+	if i >= 0 {
+		num, unit = strings.TrimSpace(s[:i]), strings.ToLower(strings.TrimSpace(s[i:]))
+	}
+
+	mult := int64(1)
+	switch unit {
+	case "", "b":
+	case "k", "kib":
+		mult = 1 << 10
+	case "m", "mib":
+		mult = 1 << 20
+	case "g", "gib":
+		mult = 1 << 30
+	default:
+		return 0, fmt.Errorf("invalid size %q (unknown unit %q)", s, unit)
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(f * float64(mult)), nil
+}
+
+// LikePrefixPattern turns user input into a LIKE pattern (with ESCAPE '\')
+// that matches values starting with it.
+func LikePrefixPattern(s string) string {
+	// Escape LIKE wildcards so user input is treated literally.
+	// Then append % for prefix match.
+	repl := strings.NewReplacer(
+		`\`, `\\`,
+		`%`, `\%`,
+		`_`, `\_`,
+	)
+	return repl.Replace(s) + `%`
+}
+
+// GlobPrefixPattern turns user input into a GLOB pattern that matches values
+// starting with it. Unlike LIKE, GLOB is case-sensitive so sqlite can answer
+// it with a regular index (e.g., for sha256 prefixes).
+func GlobPrefixPattern(s string) string {
+	// GLOB has no escape character but a wildcard in brackets is literal.
+	repl := strings.NewReplacer(
+		`*`, `[*]`,
+		`?`, `[?]`,
+		`[`, `[[]`,
+	)
+	return repl.Replace(s) + `*`
+}
+
+// ParseUserTimestamp parses a user-supplied point in time and returns it in
+// the format that we use for timestamps in the database. It accepts RFC 3339 timestamps, "YYYY-MM-DD HH:MM:SS",
+// plain dates (interpreted as midnight UTC), and unix timestamps.
+func ParseUserTimestamp(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false
+	}
+
+	if n, ok := ParseInt64(s); ok {
+		if n <= 0 {
+			return "", false
+		}
+		return time.Unix(n, 0).UTC().Format("2006-01-02T15:04:05.000Z"), true
+	}
+
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format("2006-01-02T15:04:05.000Z"), true
+		}
+	}
+
+	return "", false
+}
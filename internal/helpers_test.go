@@ -109,3 +109,173 @@ func TestParseInt64(t *testing.T) {
 		})
 	}
 }
+
+func TestParseUserTimestamp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "rfc3339 utc",
+			input:  "2024-03-01T12:30:00Z",
+			want:   "2024-03-01T12:30:00.000Z",
+			wantOK: true,
+		},
+		{
+			name:   "rfc3339 with offset",
+			input:  "2024-03-01T12:30:00+02:00",
+			want:   "2024-03-01T10:30:00.000Z",
+			wantOK: true,
+		},
+		{
+			name:   "date and time with space",
+			input:  "2024-03-01 12:30:00",
+			want:   "2024-03-01T12:30:00.000Z",
+			wantOK: true,
+		},
+		{
+			name:   "date only",
+			input:  "2024-03-01",
+			want:   "2024-03-01T00:00:00.000Z",
+			wantOK: true,
+		},
+		{
+			name:   "unix timestamp",
+			input:  "1699999999",
+			want:   "2023-11-14T22:13:19.000Z",
+			wantOK: true,
+		},
+		{
+			name:   "trims whitespace",
+			input:  "  2024-03-01  ",
+			want:   "2024-03-01T00:00:00.000Z",
+			wantOK: true,
+		},
+		{
+			name:   "empty string",
+			input:  "",
+			wantOK: false,
+		},
+		{
+			name:   "negative unix timestamp",
+			input:  "-5",
+			wantOK: false,
+		},
+		{
+			name:   "garbage",
+			input:  "last tuesday",
+			wantOK: false,
+		},
+		{
+			name:   "invalid date",
+			input:  "2024-13-01",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ParseUserTimestamp(tt.input)
+
+			assert.Equal(t, tt.wantOK, ok)
+
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 * 1024 * 1024 * 1024 / 2, "1.5 GiB"},
+		{2 * 1024 * 1024 * 1024 * 1024, "2.0 TiB"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, FormatBytes(tt.input))
+		})
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"4096", 4096, false},
+		{"512B", 512, false},
+		{"64K", 64 * 1024, false},
+		{"64 KiB", 64 * 1024, false},
+		{"1MiB", 1024 * 1024, false},
+		{"1.5m", 3 * 1024 * 1024 / 2, false},
+		{"2GiB", 2 * 1024 * 1024 * 1024, false},
+		{"", 0, true},
+		{"MiB", 0, true},
+		{"1 TB", 0, true},
+		{"-1M", 0, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseBytes(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGlobPrefixPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "*"},
+		{"abc123", "abc123*"},
+		{"a*b", "a[*]b*"},
+		{"a?b[c]", "a[?]b[[]c]*"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, GlobPrefixPattern(tt.input))
+		})
+	}
+}
@@ -0,0 +1,295 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/readonly"
+)
+
+// FromCacheHeader is set on responses that were served from the cache without
+// contacting the server. Revalidated responses (304 from the server) don't
+// have it because they still count against API rate limits.
+const FromCacheHeader = "X-Modctl-From-Cache"
+
+// request headers that identify the caller; responses for different API keys
+// are cached separately
+var varyHeaders = []string{"Apikey", "Authorization"}
+
+// Transport is an http.RoundTripper that caches successful GET responses on
+// disk.
+//
+// Cached responses are served without contacting the server until their TTL
+// expires. After that, the request is revalidated with If-None-Match and
+// If-Modified-Since (when the server sent an ETag or Last-Modified header) so
+// that unchanged resources don't have to be downloaded again.
+//
+// Requests with "Cache-Control: no-cache" always go to the server (but can
+// still be revalidated), requests with "Cache-Control: no-store" bypass the
+// cache completely, and requests with "Cache-Control: only-if-cached" never go
+// to the server: they get the cached response even if it's stale, or a 504 if
+// there is none.
+type Transport struct {
+	// Dir is where the cache entries are stored
+	Dir string
+
+	// TTL returns how long the response to the given request is considered
+	// fresh. A TTL <= 0 disables caching for the request. If nil,
+	// DefaultTTL is used for all requests.
+	TTL func(*http.Request) time.Duration
+
+	// DefaultTTL is used when TTL is nil
+	DefaultTTL time.Duration
+
+	// Base is the underlying transport (http.DefaultTransport if nil)
+	Base http.RoundTripper
+}
+
+type entry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) ttl(req *http.Request) time.Duration {
+	if t.TTL != nil {
+		return t.TTL(req)
+	}
+	return t.DefaultTTL
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cc := strings.ToLower(req.Header.Get("Cache-Control"))
+	ttl := t.ttl(req)
+
+	if req.Method != http.MethodGet || t.Dir == "" || ttl <= 0 ||
+		strings.Contains(cc, "no-store") {
+		// never cached, so there's nothing to serve without the server
+		if strings.Contains(cc, "only-if-cached") {
+			return notCached(req), nil
+		}
+		return t.base().RoundTrip(req)
+	}
+
+	path := t.pathFor(req)
+	cached, err := readEntry(path)
+	if err != nil {
+		// a corrupt entry is just a cache miss
+		cached = nil
+	}
+
+	now := time.Now()
+	if cached != nil && !strings.Contains(cc, "no-cache") && now.Before(cached.ExpiresAt) {
+		resp := cached.response(req)
+		resp.Header.Set(FromCacheHeader, "1")
+		return resp, nil
+	}
+
+	// the caller can't (or doesn't want to) contact the server: a stale
+	// response is better than nothing
+	if strings.Contains(cc, "only-if-cached") {
+		if cached == nil {
+			return notCached(req), nil
+		}
+		resp := cached.response(req)
+		resp.Header.Set(FromCacheHeader, "1")
+		return resp, nil
+	}
+
+	// don't modify the caller's request
+	out := req.Clone(req.Context())
+	if cached != nil {
+		if etag := cached.Header.Get("Etag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lm := cached.Header.Get("Last-Modified"); lm != "" {
+			out.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+
+		// keep the fresh headers (e.g., rate limits) but the cached body
+		for k, v := range resp.Header {
+			cached.Header[k] = v
+		}
+		cached.StoredAt = now
+		cached.ExpiresAt = now.Add(ttl)
+		_ = writeEntry(path, cached)
+
+		return cached.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	e := &entry{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   now,
+		ExpiresAt:  now.Add(ttl),
+	}
+
+	// caching is best-effort: a failed write shouldn't fail the request
+	_ = writeEntry(path, e)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// notCached is the response to an only-if-cached request that the cache
+// can't answer.
+func notCached(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "504 Gateway Timeout",
+		StatusCode: http.StatusGatewayTimeout,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{FromCacheHeader: {"1"}},
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}
+}
+
+// Clear removes all cached responses.
+func Clear(dir string) error {
+	if err := readonly.Check("clear the cache"); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove %s: %w", dir, err)
+	}
+	return nil
+}
+
+// <dir>/ab/<sha256 of method + url + vary headers>.json
+func (t *Transport) pathFor(req *http.Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", req.Method, req.URL.String())
+	for _, name := range varyHeaders {
+		fmt.Fprintf(h, "%s: %s\n", name, req.Header.Get(name))
+	}
+	key := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(t.Dir, key[:2], key+".json")
+}
+
+func (e *entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func readEntry(path string) (*entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var e entry
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(&e); err != nil {
+		return nil, fmt.Errorf("decode cache entry %s: %w", path, err)
+	}
+	if e.Header == nil {
+		e.Header = http.Header{}
+	}
+	return &e, nil
+}
+
+func writeEntry(path string, e *entry) error {
+	// the cache is only read with --read-only
+	if readonly.Enabled() {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
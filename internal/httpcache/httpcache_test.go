@@ -0,0 +1,155 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		reqHeader   http.Header
+		wantHits    int32
+		wantFromHit string
+	}{
+		{
+			name:        "fresh entry is served from the cache",
+			ttl:         time.Hour,
+			wantHits:    1,
+			wantFromHit: "1",
+		},
+		{
+			name:     "stale entry is revalidated",
+			ttl:      time.Nanosecond,
+			wantHits: 2,
+		},
+		{
+			name:      "no-cache forces revalidation",
+			ttl:       time.Hour,
+			reqHeader: http.Header{"Cache-Control": {"no-cache"}},
+			wantHits:  2,
+		},
+		{
+			name:      "no-store bypasses the cache",
+			ttl:       time.Hour,
+			reqHeader: http.Header{"Cache-Control": {"no-store"}},
+			wantHits:  2,
+		},
+		{
+			name:        "only-if-cached serves stale entries",
+			ttl:         time.Nanosecond,
+			reqHeader:   http.Header{"Cache-Control": {"only-if-cached"}},
+			wantHits:    1,
+			wantFromHit: "1",
+		},
+		{
+			name:     "zero ttl disables caching",
+			ttl:      0,
+			wantHits: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var hits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				io.WriteString(w, "hello")
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: &Transport{
+				Dir:        t.TempDir(),
+				DefaultTTL: tt.ttl,
+			}}
+
+			var last *http.Response
+			for i := 0; i < 2; i++ {
+				req, err := http.NewRequest(http.MethodGet, srv.URL+"/x", nil)
+				require.NoError(t, err)
+				// the first request always primes the cache
+				if i > 0 {
+					for k, v := range tt.reqHeader {
+						req.Header[k] = v
+					}
+				}
+
+				resp, err := client.Do(req)
+				require.NoError(t, err)
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				require.NoError(t, err)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "hello", string(body))
+				last = resp
+			}
+
+			assert.Equal(t, tt.wantHits, hits.Load())
+			assert.Equal(t, tt.wantFromHit, last.Header.Get(FromCacheHeader))
+		})
+	}
+}
+
+func TestTransportOnlyIfCachedMiss(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		io.WriteString(w, "hello")
+	}))
+	defer srv.Close()
+
+	for _, ttl := range []time.Duration{time.Hour, 0} {
+		client := &http.Client{Transport: &Transport{Dir: t.TempDir(), DefaultTTL: ttl}}
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/x", nil)
+		require.NoError(t, err)
+		req.Header.Set("Cache-Control", "only-if-cached")
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		// even requests that are never cached don't go to the server
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode, "ttl %s", ttl)
+		assert.Equal(t, "1", resp.Header.Get(FromCacheHeader))
+	}
+	assert.Zero(t, hits.Load())
+}
@@ -22,11 +22,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/advisory"
+	"github.com/mfinelli/modctl/internal/archive"
 	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/scan"
 )
 
 type ImportOptions struct {
@@ -36,17 +42,66 @@ type ImportOptions struct {
 	NexusURL        *string // optional nexus link
 	NexusGameDomain *string
 	NexusModID      *int64
+	NexusFileID     *int64  // optional mod_files/mod_file_versions.nexus_file_id
+	FileCategory    *string // optional mod_files.category (e.g., "main")
 
 	PageID    *int64  // optional attach to existing mod_page
 	ModName   *string // optional override for mod_pages.name
 	FileLabel *string // optional override for mod_files.label
 
+	VersionString  *string // optional mod_file_versions.version_string
+	UploadedAt     *string // optional mod_file_versions.uploaded_at
+	VersionGuessed bool    // version/uploaded_at were guessed from the filename
+
 	Wrapped     bool
 	WrappedFrom string
 	MemberName  string
 
+	// readme/changelog/license files found in the archive
+	Docs []archive.Doc
+
 	// what to store into blobs.original_name / mod_file_versions.original_name
 	OriginalBasename string
+
+	// import the archive again even if it's already attached to a mod of
+	// this game install
+	AllowDuplicate bool
+
+	// archives that the advisory feed knows to be malicious are refused
+	// with an *advisory.BlockedError unless IgnoreAdvisories is set;
+	// OnAdvisory is called with the warnings about the others
+	IgnoreAdvisories bool
+	OnAdvisory       func(warning string)
+
+	// ScanCommand scans archives that weren't in the store yet (see package
+	// scan) and records the verdict with the blob; a detection refuses the
+	// import with a *scan.DetectedError unless ForceScan is set, and a scan
+	// that failed with a *scan.FailedError unless AllowUnscanned is set
+	ScanCommand    []string
+	ForceScan      bool
+	AllowUnscanned bool
+}
+
+// DuplicateError is returned by ImportArchive when the archive has already
+// been imported for the game install (and AllowDuplicate wasn't set). It
+// describes the existing mod file version.
+type DuplicateError struct {
+	PageID    int64
+	FileID    int64
+	VersionID int64
+	SHA256    string
+	ModName   string
+	FileLabel string
+	Version   string
+
+	// the archive was missing from the store (e.g., it was quarantined) and
+	// the import put it back
+	Restored bool
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("archive %s is already imported as v%d (%s / %s)",
+		e.SHA256[:12], e.VersionID, e.ModName, e.FileLabel)
 }
 
 func ImportArchive(
@@ -70,6 +125,78 @@ func ImportArchive(
 	sha = res.SHA256Hex
 	size = res.SizeBytes
 
+	// Check the advisory feed before anything refers to the archive (a
+	// refused one doesn't stay in the store unless it already was there)
+	name := filepath.Base(opts.ArchivePath)
+	if opts.ModName != nil && *opts.ModName != "" {
+		name = *opts.ModName
+	}
+	notices, err := advisory.ForArchive(ctx, q, sha, opts.NexusGameDomain, opts.NexusModID, opts.NexusFileID, name)
+	if err != nil {
+		return 0, 0, 0, "", 0, err
+	}
+	warnings, err := advisory.Check(notices, opts.IgnoreAdvisories)
+	for _, w := range warnings {
+		if opts.OnAdvisory != nil {
+			opts.OnAdvisory(w)
+		}
+	}
+	if err != nil {
+		if !res.Existed {
+			bs.Remove(blobstore.KindArchive, sha)
+		}
+		return 0, 0, 0, "", 0, err
+	}
+
+	// The source file has the same content as the stored blob and exists
+	// under --dry-run too, where nothing is stored
+	var scanned *scan.Result
+	if len(opts.ScanCommand) > 0 && !res.Existed {
+		r := scan.Run(ctx, opts.ScanCommand, opts.ArchivePath)
+		if err := scan.Check(r, name, opts.AllowUnscanned, opts.ForceScan); err != nil {
+			if !dryrun.Enabled() {
+				bs.Remove(blobstore.KindArchive, sha)
+			}
+			return 0, 0, 0, "", 0, err
+		}
+		scanned = &r
+	}
+
+	// The archive might already be attached to a mod for this game; report
+	// that instead of creating another page/file/version chain for the same
+	// archive. Usually its blob was already in the store then, unless it was
+	// quarantined (or went missing) and this import just put it back.
+	if !opts.AllowDuplicate {
+		existing, err := q.FindModFileVersionByArchiveForGame(ctx, dbq.FindModFileVersionByArchiveForGameParams{
+			ArchiveSha256: sha,
+			GameInstallID: opts.GameInstallID,
+		})
+		if err == nil {
+			restored := !res.Existed
+			if restored {
+				base := filepath.Base(opts.ArchivePath)
+				if err := blobstore.EnsureBlobRecorded(ctx, q, sha, string(blobstore.KindArchive), size, &base); err != nil {
+					return 0, 0, 0, "", 0, err
+				}
+				if err := recordScan(ctx, q, sha, scanned); err != nil {
+					return 0, 0, 0, "", 0, err
+				}
+			}
+			return 0, 0, 0, sha, size, &DuplicateError{
+				PageID:    existing.ModPageID,
+				FileID:    existing.ModFileID,
+				VersionID: existing.ID,
+				SHA256:    sha,
+				ModName:   existing.ModName,
+				FileLabel: existing.ModFileLabel,
+				Version:   existing.VersionString.String,
+				Restored:  restored,
+			}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, 0, "", 0, fmt.Errorf("lookup existing archive: %w", err)
+		}
+	}
+
 	// Derive original filename
 	base := filepath.Base(opts.ArchivePath)
 
@@ -92,6 +219,9 @@ func ImportArchive(
 	); err != nil {
 		return 0, 0, 0, "", 0, err
 	}
+	if err := recordScan(ctx, qtx, sha, scanned); err != nil {
+		return 0, 0, 0, "", 0, err
+	}
 
 	// 4) Determine mod page name
 	pageName := base
@@ -160,13 +290,36 @@ func ImportArchive(
 		label = *opts.FileLabel
 	}
 
-	// Decide mod_file_id by label (find-or-create)
-	mf, err := qtx.GetModFileByLabel(ctx, dbq.GetModFileByLabelParams{
-		ModPageID: pageID,
-		Label:     label,
-	})
+	// Decide mod_file_id by nexus file id, then by label (find-or-create)
+	hasNexusFile := false
+	err = sql.ErrNoRows
+	if opts.NexusFileID != nil {
+		var mf dbq.GetModFileByNexusFileIDRow
+		mf, err = qtx.GetModFileByNexusFileID(ctx, dbq.GetModFileByNexusFileIDParams{
+			ModPageID:   pageID,
+			NexusFileID: nullInt64(opts.NexusFileID),
+		})
+		fileID, hasNexusFile = mf.ID, true
+	}
+	if err == sql.ErrNoRows {
+		var mf dbq.GetModFileByLabelRow
+		mf, err = qtx.GetModFileByLabel(ctx, dbq.GetModFileByLabelParams{
+			ModPageID: pageID,
+			Label:     label,
+		})
+		fileID, hasNexusFile = mf.ID, mf.NexusFileID.Valid
+	}
 	if err == nil {
-		fileID = mf.ID
+		// remember which nexus file a file that was imported by hand is
+		if opts.NexusFileID != nil && !hasNexusFile {
+			if err := qtx.SetModFileNexus(ctx, dbq.SetModFileNexusParams{
+				NexusFileID: nullInt64(opts.NexusFileID),
+				Category:    nullString(opts.FileCategory),
+				ID:          fileID,
+			}); err != nil {
+				return 0, 0, 0, "", 0, fmt.Errorf("set mod_file nexus file: %w", err)
+			}
+		}
 	} else if err != sql.ErrNoRows {
 		return 0, 0, 0, "", 0, fmt.Errorf("lookup mod_file: %w", err)
 	} else {
@@ -184,7 +337,8 @@ func ImportArchive(
 			ModPageID:   pageID,
 			Label:       label,
 			IsPrimary:   isPrimary,
-			NexusFileID: sql.NullInt64{Valid: false}, // we don't have file_id from nexus-url
+			NexusFileID: nullInt64(opts.NexusFileID),
+			Category:    nullString(opts.FileCategory),
 			SourceUrl:   nullString(opts.NexusURL),
 			Metadata:    sql.NullString{Valid: false},
 		})
@@ -193,18 +347,26 @@ func ImportArchive(
 		}
 	}
 
-	var m sql.NullString
+	meta := map[string]any{}
 	if opts.Wrapped {
-		meta := map[string]any{
-			"wrapped":             true,
-			"wrapped_from":        opts.WrappedFrom,
-			"wrapped_member_name": opts.MemberName,
-		}
+		meta["wrapped"] = true
+		meta["wrapped_from"] = opts.WrappedFrom
+		meta["wrapped_member_name"] = opts.MemberName
+	}
+	if opts.VersionGuessed {
+		meta["version_source"] = "filename"
+	}
+	if len(opts.Docs) > 0 {
+		meta["docs"] = opts.Docs
+	}
+
+	var m sql.NullString
+	if len(meta) > 0 {
 		b, jerr := json.Marshal(meta)
-		m = sql.NullString{String: string(b), Valid: true}
 		if jerr != nil {
-			return 0, 0, 0, "", 0, fmt.Errorf("creating wrapped json: %w", err)
+			return 0, 0, 0, "", 0, fmt.Errorf("creating version metadata json: %w", jerr)
 		}
+		m = sql.NullString{String: string(b), Valid: true}
 	}
 
 	// 7) Create mod_file_version
@@ -212,11 +374,12 @@ func ImportArchive(
 		ModFileID:     fileID,
 		ArchiveSha256: sha,
 		OriginalName:  nullString(&opts.OriginalBasename),
-		VersionString: sql.NullString{Valid: false},
-		UploadedAt:    sql.NullString{Valid: false},
+		VersionString: nullString(opts.VersionString),
+		UploadedAt:    nullString(opts.UploadedAt),
 		UpstreamNotes: sql.NullString{Valid: false},
 		Notes:         sql.NullString{Valid: false},
 		Metadata:      m,
+		NexusFileID:   nullInt64(opts.NexusFileID),
 	})
 	if err != nil {
 		return 0, 0, 0, "", 0, fmt.Errorf("create mod_file_version: %w", err)
@@ -230,6 +393,24 @@ func ImportArchive(
 	return pageID, fileID, versionID, sha, size, nil
 }
 
+// recordScan records the verdict of a scan with the blob, if it was
+// scanned.
+func recordScan(ctx context.Context, q *dbq.Queries, sha string, r *scan.Result) error {
+	if r == nil {
+		return nil
+	}
+	err := q.SetBlobScan(ctx, dbq.SetBlobScanParams{
+		ScanVerdict: sql.NullString{String: r.Verdict, Valid: true},
+		ScanDetail:  sql.NullString{String: r.Detail, Valid: r.Detail != ""},
+		ScannedAt:   sql.NullString{String: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"), Valid: true},
+		Sha256:      sha,
+	})
+	if err != nil {
+		return fmt.Errorf("record scan of %s: %w", sha[:12], err)
+	}
+	return nil
+}
+
 func nullString(s *string) sql.NullString {
 	if s == nil || *s == "" {
 		return sql.NullString{Valid: false}
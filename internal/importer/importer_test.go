@@ -0,0 +1,93 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package importer_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/importer"
+	"github.com/mfinelli/modctl/internal/scan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportArchiveScanDryRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "modctl.db")+internal.DB_PRAGMAS)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	p, err := internal.GooseProvider(db)
+	require.NoError(t, err)
+	_, err = p.Up(ctx)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO game_installs (id, store_id, store_game_id, display_name, install_root)
+		VALUES (1, 'steam', '489830', 'Skyrim', ?)`, dir)
+	require.NoError(t, err)
+
+	bs := blobstore.Store{
+		ArchivesDir: filepath.Join(dir, "archives"),
+		TmpDir:      filepath.Join(dir, "tmp"),
+	}
+	src := filepath.Join(dir, "SkyUI.7z")
+	require.NoError(t, os.WriteFile(src, []byte("not really an archive"), 0o644))
+
+	dryrun.Enable()
+	t.Cleanup(dryrun.Reset)
+
+	// the scanner only passes files that exist
+	opts := importer.ImportOptions{
+		GameInstallID: 1,
+		ArchivePath:   src,
+		ScanCommand:   []string{"sh", "-c", `test -f "$1" || exit 2`, "sh", "${file}"},
+	}
+	_, _, versionID, sha, _, err := importer.ImportArchive(ctx, db, dbq.New(db), bs, opts)
+	require.NoError(t, err)
+	assert.NotZero(t, versionID)
+
+	var verdict string
+	require.NoError(t, db.QueryRow(`SELECT scan_verdict FROM blobs WHERE sha256 = ?`, sha).Scan(&verdict))
+	assert.Equal(t, scan.Clean, verdict)
+
+	// a detection refuses it, and nothing was stored either way
+	opts.ScanCommand = []string{"sh", "-c", "exit 1"}
+	opts.AllowDuplicate = true
+	_, _, _, _, _, err = importer.ImportArchive(ctx, db, dbq.New(db), bs, opts)
+	var detected *scan.DetectedError
+	assert.ErrorAs(t, err, &detected)
+
+	_, err = os.Stat(bs.ArchivesDir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	for _, n := range dryrun.Notes() {
+		assert.NotContains(t, n, "remove")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// incrementalFixture is a game install with two deployed mod files (from
+// versions 10 and 20) and an applied revision that has both versions.
+func incrementalFixture(t *testing.T) (dbq.GameInstall, *deployTarget, []dbq.InstalledFile, map[pathKey]dbq.InstalledFile) {
+	t.Helper()
+
+	root := t.TempDir()
+	target := &deployTarget{row: dbq.Target{ID: 1, Name: "game_dir"}, root: root}
+
+	rev, err := json.Marshal(Revision{ProfileID: 1, Items: []RevisionItem{
+		{VersionID: 10},
+		{VersionID: 20, Rules: []RevisionRule{{Type: "strip", Int: 1}}},
+	}})
+	require.NoError(t, err)
+	gi := dbq.GameInstall{
+		ID:               1,
+		AppliedProfileID: sql.NullInt64{Int64: 1, Valid: true},
+		AppliedRevision:  sql.NullString{String: string(rev), Valid: true},
+	}
+
+	var installed []dbq.InstalledFile
+	byKey := map[pathKey]dbq.InstalledFile{}
+	for _, f := range []struct {
+		relpath string
+		version int64
+	}{{"a.esp", 10}, {"b.esp", 20}} {
+		path := filepath.Join(root, f.relpath)
+		require.NoError(t, os.WriteFile(path, []byte("content of "+f.relpath), 0o644))
+		sha, size, err := deploy.HashFile(path)
+		require.NoError(t, err)
+
+		row := dbq.InstalledFile{
+			TargetID:              1,
+			Relpath:               f.relpath,
+			ContentSha256:         sha,
+			SizeBytes:             size,
+			OwnerModFileVersionID: sql.NullInt64{Int64: f.version, Valid: true},
+		}
+		installed = append(installed, row)
+		byKey[pathKey{1, f.relpath}] = row
+	}
+
+	return gi, target, installed, byKey
+}
+
+func TestChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	strip := []RevisionRule{{Type: "strip", Int: 1}}
+
+	tests := []struct {
+		name string
+		// changes to the desired files and the disk before the split
+		setup         func(t *testing.T, gi *dbq.GameInstall, root string, desired map[pathKey]*desiredFile)
+		full          bool
+		wantUnchanged []string
+		wantTouched   []string
+		wantPending   []string
+	}{
+		{
+			name:          "same versions and rules",
+			wantUnchanged: []string{"a.esp", "b.esp"},
+		},
+		{
+			name:        "full redeploys everything",
+			full:        true,
+			wantTouched: []string{"a.esp", "b.esp"},
+			wantPending: []string{"a.esp", "b.esp"},
+		},
+		{
+			name: "new version",
+			setup: func(t *testing.T, gi *dbq.GameInstall, root string, desired map[pathKey]*desiredFile) {
+				desired[pathKey{1, "a.esp"}].versionID = 11
+			},
+			wantUnchanged: []string{"b.esp"},
+			wantTouched:   []string{"a.esp"},
+			wantPending:   []string{"a.esp"},
+		},
+		{
+			name: "changed rules",
+			setup: func(t *testing.T, gi *dbq.GameInstall, root string, desired map[pathKey]*desiredFile) {
+				desired[pathKey{1, "b.esp"}].rules = nil
+			},
+			wantUnchanged: []string{"a.esp"},
+			wantTouched:   []string{"b.esp"},
+			wantPending:   []string{"b.esp"},
+		},
+		{
+			name: "changed symlink policy",
+			setup: func(t *testing.T, gi *dbq.GameInstall, root string, desired map[pathKey]*desiredFile) {
+				desired[pathKey{1, "a.esp"}].symlinks = "skip"
+			},
+			wantUnchanged: []string{"b.esp"},
+			wantTouched:   []string{"a.esp"},
+			wantPending:   []string{"a.esp"},
+		},
+		{
+			name: "size changed on disk",
+			setup: func(t *testing.T, gi *dbq.GameInstall, root string, desired map[pathKey]*desiredFile) {
+				require.NoError(t, os.WriteFile(filepath.Join(root, "a.esp"), []byte("x"), 0o644))
+			},
+			wantUnchanged: []string{"b.esp"},
+			wantTouched:   []string{"a.esp"},
+			wantPending:   []string{"a.esp"},
+		},
+		{
+			name: "file removed from the profile",
+			setup: func(t *testing.T, gi *dbq.GameInstall, root string, desired map[pathKey]*desiredFile) {
+				delete(desired, pathKey{1, "b.esp"})
+			},
+			wantUnchanged: []string{"a.esp"},
+			wantTouched:   []string{"b.esp"},
+		},
+		{
+			name: "nothing applied",
+			setup: func(t *testing.T, gi *dbq.GameInstall, root string, desired map[pathKey]*desiredFile) {
+				gi.AppliedRevision = sql.NullString{}
+			},
+			wantTouched: []string{"a.esp", "b.esp"},
+			wantPending: []string{"a.esp", "b.esp"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gi, target, installed, byKey := incrementalFixture(t)
+			desired := map[pathKey]*desiredFile{
+				{1, "a.esp"}: {target: target, relpath: "a.esp", versionID: 10},
+				{1, "b.esp"}: {target: target, relpath: "b.esp", versionID: 20, rules: strip},
+			}
+			if tt.setup != nil {
+				tt.setup(t, &gi, target.root, desired)
+			}
+
+			unchanged, touched, pending := changedFiles(gi, desired, installed, byKey, tt.full)
+
+			var gotUnchanged, gotTouched, gotPending []string
+			for k := range unchanged {
+				gotUnchanged = append(gotUnchanged, k.relpath)
+			}
+			for _, row := range touched {
+				gotTouched = append(gotTouched, row.Relpath)
+			}
+			for _, k := range pending {
+				gotPending = append(gotPending, k.relpath)
+			}
+			sort.Strings(gotUnchanged)
+			sort.Strings(gotPending)
+
+			assert.Equal(t, tt.wantUnchanged, gotUnchanged)
+			assert.Equal(t, tt.wantTouched, gotTouched)
+			assert.Equal(t, tt.wantPending, gotPending)
+		})
+	}
+}
+
+func TestChangedFilesDriftOnlyTouched(t *testing.T) {
+	t.Parallel()
+
+	gi, target, installed, byKey := incrementalFixture(t)
+	targets := map[int64]*deployTarget{1: target}
+	desired := map[pathKey]*desiredFile{
+		{1, "a.esp"}: {target: target, relpath: "a.esp", versionID: 10},
+		{1, "b.esp"}: {target: target, relpath: "b.esp", versionID: 21},
+	}
+
+	// same size, different content: the file that stays is modified
+	require.NoError(t, os.WriteFile(filepath.Join(target.root, "a.esp"), []byte("CONTENT OF a.esp"), 0o644))
+
+	d := &Deployer{}
+	_, touched, _ := changedFiles(gi, desired, installed, byKey, false)
+	assert.NoError(t, d.checkDrift(context.Background(), touched, targets),
+		"a file that isn't replaced isn't checked")
+
+	// the file that is replaced is modified
+	require.NoError(t, os.WriteFile(filepath.Join(target.root, "b.esp"), []byte("CONTENT OF b.esp"), 0o644))
+	_, touched, _ = changedFiles(gi, desired, installed, byKey, false)
+	var de *DriftError
+	require.ErrorAs(t, d.checkDrift(context.Background(), touched, targets), &de)
+	assert.Equal(t, []string{"game_dir/b.esp"}, de.Paths)
+
+	// with full everything is deployed again and so checked
+	_, touched, _ = changedFiles(gi, desired, installed, byKey, true)
+	require.ErrorAs(t, d.checkDrift(context.Background(), touched, targets), &de)
+	assert.Equal(t, []string{"game_dir/a.esp", "game_dir/b.esp"}, de.Paths)
+}
@@ -0,0 +1,68 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/readonly"
+)
+
+// UnpackedSize returns how much space the files of an archive take up once
+// they're extracted, i.e., about what a mod version takes up in the game when
+// it's deployed (by copying). It's computed from the archive listing the
+// first time and recorded with the blob.
+func UnpackedSize(ctx context.Context, q *dbq.Queries, bs blobstore.Store, bsdtar, sha string) (int64, error) {
+	b, err := q.GetBlob(ctx, sha)
+	if err != nil {
+		return 0, fmt.Errorf("get blob %s: %w", shortSHA(sha), err)
+	}
+	if b.UnpackedBytes.Valid {
+		return b.UnpackedBytes.Int64, nil
+	}
+
+	path, err := bs.Locate(blobstore.KindArchive, sha)
+	if err != nil {
+		return 0, err
+	}
+	sizes, err := archive.Sizes(ctx, bsdtar, path)
+	if err != nil {
+		return 0, fmt.Errorf("archive %s: %w", shortSHA(sha), err)
+	}
+
+	var total int64
+	for _, n := range sizes {
+		total += n
+	}
+
+	if !readonly.Enabled() {
+		if err := q.SetBlobUnpackedBytes(ctx, dbq.SetBlobUnpackedBytesParams{
+			UnpackedBytes: sql.NullInt64{Int64: total, Valid: true},
+			Sha256:        sha,
+		}); err != nil {
+			return 0, fmt.Errorf("record size of archive %s: %w", shortSHA(sha), err)
+		}
+	}
+
+	return total, nil
+}
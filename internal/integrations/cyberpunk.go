@@ -0,0 +1,231 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+const CyberpunkSteamAppID = "1091500"
+
+// the top-level directories of the game that mods install into
+var cyberpunkRoots = map[string]bool{
+	"archive": true,
+	"bin":     true,
+	"engine":  true,
+	"mods":    true,
+	"r6":      true,
+	"red4ext": true,
+}
+
+// Cyberpunk handles Cyberpunk 2077 mods. It tells REDmod mods (a folder with
+// an info.json that goes into mods/) apart from legacy archive mods (.archive
+// files that go into archive/pc/mod/) and puts both where the game expects
+// them even if they weren't packaged relative to the game directory.
+type Cyberpunk struct{}
+
+func (Cyberpunk) Name() string { return "cyberpunk2077" }
+
+func (Cyberpunk) Map(item *plan.Item, members []string) ([]plan.Mapped, string) {
+	if len(members) == 0 {
+		return nil, "empty"
+	}
+
+	// packaged relative to the game directory, possibly wrapped in a
+	// single extra directory
+	for _, strip := range []int{0, 1} {
+		if stripped, ok := stripCommon(members, strip); ok && allUnderRoots(stripped) {
+			return remapped(members, stripped, ""), cyberpunkLayout(stripped)
+		}
+	}
+
+	// a bare REDmod: <name>/info.json or info.json at the top
+	if dir, ok := redmodDir(members); ok {
+		if dir == "" {
+			name := sanitizeModDirName(item.ModName)
+			return plan.Identity(members, "mods/"+name), "redmod"
+		}
+		return plan.Identity(members, "mods"), "redmod"
+	}
+
+	// loose .archive (and ArchiveXL .xl) files
+	if allArchives(members) {
+		out := make([]plan.Mapped, 0, len(members))
+		for _, m := range members {
+			out = append(out, plan.Mapped{
+				Member:  m,
+				Target:  plan.DefaultTarget,
+				RelPath: "archive/pc/mod/" + path.Base(m),
+			})
+		}
+		return out, "legacy archive"
+	}
+
+	return plan.Identity(members, ""), "unknown (as-is)"
+}
+
+// IsRedmodPath reports whether a relpath in the game directory is REDmod
+// content (i.e., whether changing it requires a redmod deploy).
+func IsRedmodPath(rel string) bool {
+	return strings.HasPrefix(strings.ToLower(rel), "mods/")
+}
+
+func cyberpunkLayout(rels []string) string {
+	redmod, legacy := false, false
+	for _, r := range rels {
+		if IsRedmodPath(r) {
+			redmod = true
+		} else {
+			legacy = true
+		}
+	}
+
+	switch {
+	case redmod && legacy:
+		return "redmod + legacy"
+	case redmod:
+		return "redmod"
+	default:
+		return "legacy"
+	}
+}
+
+func allUnderRoots(rels []string) bool {
+	for _, r := range rels {
+		first, _, ok := strings.Cut(r, "/")
+		if !ok || !cyberpunkRoots[strings.ToLower(first)] {
+			return false
+		}
+	}
+	return true
+}
+
+// stripCommon removes the first n path segments of every member, which must
+// all be the same.
+func stripCommon(members []string, n int) ([]string, bool) {
+	if n == 0 {
+		return members, true
+	}
+
+	out := make([]string, 0, len(members))
+	var first string
+	for i, m := range members {
+		parts := strings.SplitN(m, "/", n+1)
+		if len(parts) <= n {
+			return nil, false
+		}
+		prefix := strings.Join(parts[:n], "/")
+		if i == 0 {
+			first = prefix
+		} else if prefix != first {
+			return nil, false
+		}
+		out = append(out, parts[n])
+	}
+
+	return out, true
+}
+
+func remapped(members, rels []string, prefix string) []plan.Mapped {
+	out := make([]plan.Mapped, 0, len(members))
+	for i, m := range members {
+		out = append(out, plan.Mapped{Member: m, Target: plan.DefaultTarget, RelPath: prefix + rels[i]})
+	}
+	return out
+}
+
+// redmodDir returns the directory that contains the REDmod info.json if
+// every member is inside of it ("" if info.json is at the top level).
+func redmodDir(members []string) (string, bool) {
+	dir, found := "", false
+	for _, m := range members {
+		if strings.EqualFold(path.Base(m), "info.json") && strings.Count(m, "/") <= 1 {
+			dir, found = path.Dir(m), true
+			break
+		}
+	}
+	if !found {
+		return "", false
+	}
+
+	if dir == "." {
+		return "", true
+	}
+	for _, m := range members {
+		if !strings.HasPrefix(m, dir+"/") {
+			return "", false
+		}
+	}
+	return dir, true
+}
+
+func allArchives(members []string) bool {
+	for _, m := range members {
+		ext := strings.ToLower(path.Ext(m))
+		if ext != ".archive" && ext != ".xl" {
+			return false
+		}
+	}
+	return true
+}
+
+var unsafeDirChars = regexp.MustCompile(`[^A-Za-z0-9 ._()-]+`)
+
+func sanitizeModDirName(name string) string {
+	name = strings.TrimSpace(unsafeDirChars.ReplaceAllString(name, ""))
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return "modctl"
+	}
+	return name
+}
+
+// RedmodDeploy runs REDmod's deploy step (through proton) which compiles the
+// REDmod mods in the game directory. It has to run whenever REDmod content
+// changes.
+func RedmodDeploy(ctx context.Context, gi dbq.GameInstall) (string, error) {
+	exe := filepath.Join(gi.InstallRoot, "tools", "redmod", "bin", "redMod.exe")
+	if _, err := os.Stat(exe); errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%s not found; install the REDmod DLC (it's free) from steam", exe)
+	}
+
+	cmd, err := internal.ProtonCommand(ctx, gi, exe, "deploy",
+		"-root="+internal.WindowsPath(gi.InstallRoot))
+	if err != nil {
+		return "", err
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("redmod deploy failed: %w", err)
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,97 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package integrations
+
+import (
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCyberpunkMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		members    []string
+		want       []string
+		wantLayout string
+	}{
+		{
+			name:       "legacy relative to the game dir",
+			members:    []string{"archive/pc/mod/cool.archive"},
+			want:       []string{"archive/pc/mod/cool.archive"},
+			wantLayout: "legacy",
+		},
+		{
+			name:       "redmod relative to the game dir",
+			members:    []string{"mods/Cool/info.json", "mods/Cool/archives/cool.archive"},
+			want:       []string{"mods/Cool/info.json", "mods/Cool/archives/cool.archive"},
+			wantLayout: "redmod",
+		},
+		{
+			name:       "wrapped in an extra directory",
+			members:    []string{"Cool v1.2/r6/scripts/cool.reds", "Cool v1.2/archive/pc/mod/cool.archive"},
+			want:       []string{"r6/scripts/cool.reds", "archive/pc/mod/cool.archive"},
+			wantLayout: "legacy",
+		},
+		{
+			name:       "bare redmod folder",
+			members:    []string{"Cool/info.json", "Cool/tweaks/base/cool.yaml"},
+			want:       []string{"mods/Cool/info.json", "mods/Cool/tweaks/base/cool.yaml"},
+			wantLayout: "redmod",
+		},
+		{
+			name:       "redmod contents at the top",
+			members:    []string{"info.json", "archives/cool.archive"},
+			want:       []string{"mods/Cool Mod/info.json", "mods/Cool Mod/archives/cool.archive"},
+			wantLayout: "redmod",
+		},
+		{
+			name:       "loose archive files",
+			members:    []string{"Cool/cool.archive", "cool.xl"},
+			want:       []string{"archive/pc/mod/cool.archive", "archive/pc/mod/cool.xl"},
+			wantLayout: "legacy archive",
+		},
+		{
+			name:       "unknown",
+			members:    []string{"something/else.txt"},
+			want:       []string{"something/else.txt"},
+			wantLayout: "unknown (as-is)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &plan.Item{ModName: "Cool: Mod"}
+			got, layout := Cyberpunk{}.Map(item, tt.members)
+
+			rels := make([]string, 0, len(got))
+			for _, m := range got {
+				rels = append(rels, m.RelPath)
+			}
+			assert.Equal(t, tt.want, rels)
+			assert.Equal(t, tt.wantLayout, layout)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package integrations has the game-specific planning and deployment logic
+// for games that need more than "extract the archive into the game
+// directory".
+package integrations
+
+import (
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// IsWitcher3 reports whether a game install is The Witcher 3.
+func IsWitcher3(gi dbq.GameInstall) bool {
+	return gi.StoreID == "steam" && witcher3SteamAppIDs[gi.StoreGameID]
+}
+
+// For returns the planning handler for a game install.
+func For(gi dbq.GameInstall) plan.Handler {
+	if gi.StoreID == "steam" {
+		switch {
+		case gi.StoreGameID == CyberpunkSteamAppID:
+			return Cyberpunk{}
+		case witcher3SteamAppIDs[gi.StoreGameID]:
+			return Witcher3{}
+		}
+	}
+
+	if u, ok := DetectUnreal(gi.InstallRoot); ok {
+		return u
+	}
+
+	return plan.Generic{}
+}
@@ -0,0 +1,257 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+package integrations
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// the files of a pak mod: the pak itself and, for io store mods, its .ucas
+// and .utoc (and sometimes a .sig)
+var unrealPakExts = map[string]bool{
+	".pak":  true,
+	".sig":  true,
+	".ucas": true,
+	".utoc": true,
+}
+
+// Unreal handles Unreal Engine games. It puts pak mods into
+// <Project>/Content/Paks/~mods/ with a prefix derived from the priority of
+// the item (the engine mounts paks in name order and the last one wins) and
+// UE4SS script mods into the UE4SS Mods/ directory.
+type Unreal struct {
+	Project   string // e.g., "Pal"
+	Paks      string // <Project>/Content/Paks as it is on disk
+	UE4SSMods string // where UE4SS loads mods from
+}
+
+// DetectUnreal reports whether an install root has an Unreal Engine game,
+// i.e., a <Project>/Content/Paks directory (other than the engine's own).
+func DetectUnreal(installRoot string) (Unreal, bool) {
+	entries, err := os.ReadDir(installRoot)
+	if err != nil {
+		return Unreal{}, false
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() || strings.EqualFold(e.Name(), "Engine") {
+			continue
+		}
+		paks, ok := internal.FindDir(installRoot, path.Join(e.Name(), "Content", "Paks"))
+		if !ok {
+			continue
+		}
+		return Unreal{
+			Project:   e.Name(),
+			Paks:      paks,
+			UE4SSMods: ue4ssModsDir(installRoot, e.Name()),
+		}, true
+	}
+
+	return Unreal{}, false
+}
+
+// ue4ssModsDir returns the directory that UE4SS loads mods from: since 3.0
+// it lives in its own ue4ss/ directory next to the game executable, before
+// that it was installed right next to it.
+func ue4ssModsDir(installRoot, project string) string {
+	for _, platform := range []string{"Win64", "WinGDK"} {
+		bin, ok := internal.FindDir(installRoot, path.Join(project, "Binaries", platform))
+		if !ok {
+			continue
+		}
+		if dir, ok := internal.FindDir(installRoot, path.Join(bin, "ue4ss")); ok {
+			bin = dir
+		}
+		if dir, ok := internal.FindDir(installRoot, path.Join(bin, "Mods")); ok {
+			return dir
+		}
+		return path.Join(bin, "Mods")
+	}
+	return path.Join(project, "Binaries", "Win64", "Mods")
+}
+
+func (Unreal) Name() string { return "unreal" }
+
+func (u Unreal) Map(item *plan.Item, members []string) ([]plan.Mapped, string) {
+	if len(members) == 0 {
+		return nil, "empty"
+	}
+
+	// packaged relative to the game directory, possibly wrapped in a
+	// single extra directory
+	for _, strip := range []int{0, 1} {
+		if stripped, ok := stripCommon(members, strip); ok && u.allUnderProject(stripped) {
+			return remapped(members, stripped, ""), "as-is"
+		}
+	}
+
+	roots := ue4ssRoots(members)
+	prefix := UnrealPakPrefix(item.Priority)
+
+	var out []plan.Mapped
+	paks, scripts, skipped := false, false, 0
+	for _, m := range members {
+		// UE4SS doesn't load paks from its own directories
+		if unrealPakExts[strings.ToLower(path.Ext(m))] {
+			out = append(out, plan.Mapped{
+				Member:  m,
+				Target:  plan.DefaultTarget,
+				RelPath: path.Join(u.Paks, "~mods", prefix+path.Base(m)),
+			})
+			paks = true
+			continue
+		}
+
+		if root, ok := ue4ssRoot(roots, m); ok {
+			name, rest := path.Base(root), strings.TrimPrefix(m, root+"/")
+			if root == "." {
+				name, rest = sanitizeModDirName(item.ModName), m
+			}
+			out = append(out, plan.Mapped{
+				Member:  m,
+				Target:  plan.DefaultTarget,
+				RelPath: path.Join(u.UE4SSMods, name, rest),
+			})
+			scripts = true
+			continue
+		}
+
+		skipped++
+	}
+
+	if len(out) == 0 {
+		return plan.Identity(members, ""), "unknown (as-is)"
+	}
+
+	var kinds []string
+	if paks {
+		kinds = append(kinds, "pak")
+	}
+	if scripts {
+		kinds = append(kinds, "ue4ss")
+	}
+	layout := strings.Join(kinds, " + ")
+	if skipped > 0 {
+		// readmes, screenshots, etc. that would otherwise end up in the
+		// game directory
+		layout += fmt.Sprintf(" (%d other files skipped)", skipped)
+	}
+	return out, layout
+}
+
+// UnrealPakPrefix returns the prefix of the paks of an item with the given
+// priority so that higher priority paks sort (and are mounted) last.
+func UnrealPakPrefix(priority int64) string {
+	return fmt.Sprintf("%04d_", max(priority, 0))
+}
+
+func (u Unreal) allUnderProject(rels []string) bool {
+	for _, r := range rels {
+		first, _, ok := strings.Cut(r, "/")
+		if !ok || (!strings.EqualFold(first, u.Project) && !strings.EqualFold(first, "Engine")) {
+			return false
+		}
+	}
+	return true
+}
+
+// ue4ssRoots returns the directories of the UE4SS mods in an archive, i.e.,
+// the ones with a Scripts/main.lua or a dlls/main.dll ("." if they're at the
+// top level).
+func ue4ssRoots(members []string) []string {
+	seen := map[string]bool{}
+	var roots []string
+	for _, m := range members {
+		lower := strings.ToLower(m)
+		if lower != "scripts/main.lua" && lower != "dlls/main.dll" &&
+			!strings.HasSuffix(lower, "/scripts/main.lua") && !strings.HasSuffix(lower, "/dlls/main.dll") {
+			continue
+		}
+		root := path.Dir(path.Dir(m))
+		if !seen[root] {
+			seen[root] = true
+			roots = append(roots, root)
+		}
+	}
+
+	// the deepest one wins if they're nested
+	sort.Slice(roots, func(i, j int) bool { return len(roots[i]) > len(roots[j]) })
+	return roots
+}
+
+func ue4ssRoot(roots []string, member string) (string, bool) {
+	for _, r := range roots {
+		if r == "." || strings.HasPrefix(member, r+"/") {
+			return r, true
+		}
+	}
+	return "", false
+}
+
+// Analyze reports the UE4SS mods that UE4SS won't load on its own.
+func (u Unreal) Analyze(p *plan.Plan) []plan.Note {
+	type mod struct {
+		dir     string
+		enabled bool
+		sources []plan.Source
+	}
+	mods := map[string]*mod{}
+
+	for _, f := range p.Files {
+		if len(f.RelPath) <= len(u.UE4SSMods) || !strings.EqualFold(f.RelPath[:len(u.UE4SSMods)+1], u.UE4SSMods+"/") {
+			continue
+		}
+		name, file, ok := strings.Cut(f.RelPath[len(u.UE4SSMods)+1:], "/")
+		if !ok {
+			continue
+		}
+		m, ok := mods[strings.ToLower(name)]
+		if !ok {
+			m = &mod{dir: path.Join(u.UE4SSMods, name)}
+			mods[strings.ToLower(name)] = m
+		}
+		m.enabled = m.enabled || strings.EqualFold(file, "enabled.txt")
+		m.sources = append(m.sources, f.Winner)
+	}
+
+	var notes []plan.Note
+	for name, m := range mods {
+		// shared/ has the libraries of the other mods
+		if m.enabled || name == "shared" {
+			continue
+		}
+		notes = append(notes, plan.Note{
+			Kind: "ue4ss_enable",
+			Key:  m.dir,
+			Message: fmt.Sprintf("UE4SS only loads it if it's listed in %s/mods.txt or has an enabled.txt",
+				u.UE4SSMods),
+			Sources: m.sources,
+		})
+	}
+
+	sort.Slice(notes, func(i, j int) bool { return notes[i].Key < notes[j].Key })
+	return notes
+}
@@ -0,0 +1,158 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectUnreal(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, d := range []string{
+		"Engine/Content/Paks",
+		"Pal/Content/Paks",
+		"Pal/Binaries/Win64/ue4ss/Mods",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, d), 0o755))
+	}
+
+	u, ok := DetectUnreal(root)
+	require.True(t, ok)
+	assert.Equal(t, Unreal{
+		Project:   "Pal",
+		Paks:      "Pal/Content/Paks",
+		UE4SSMods: "Pal/Binaries/Win64/ue4ss/Mods",
+	}, u)
+
+	// ue4ss isn't installed (yet)
+	root = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "Game", "content", "paks"), 0o755))
+	u, ok = DetectUnreal(root)
+	require.True(t, ok)
+	assert.Equal(t, "Game/content/paks", u.Paks)
+	assert.Equal(t, "Game/Binaries/Win64/Mods", u.UE4SSMods)
+
+	_, ok = DetectUnreal(t.TempDir())
+	assert.False(t, ok)
+}
+
+func TestUnrealMap(t *testing.T) {
+	t.Parallel()
+
+	u := Unreal{Project: "Pal", Paks: "Pal/Content/Paks", UE4SSMods: "Pal/Binaries/Win64/ue4ss/Mods"}
+
+	tests := []struct {
+		name       string
+		members    []string
+		want       []string
+		wantLayout string
+	}{
+		{
+			name:       "relative to the game dir",
+			members:    []string{"Cool/Pal/Content/Paks/~mods/cool_P.pak"},
+			want:       []string{"Pal/Content/Paks/~mods/cool_P.pak"},
+			wantLayout: "as-is",
+		},
+		{
+			name:    "io store pak",
+			members: []string{"Cool/cool_P.pak", "Cool/cool_P.ucas", "Cool/cool_P.utoc", "Cool/readme.txt"},
+			want: []string{
+				"Pal/Content/Paks/~mods/0007_cool_P.pak",
+				"Pal/Content/Paks/~mods/0007_cool_P.ucas",
+				"Pal/Content/Paks/~mods/0007_cool_P.utoc",
+			},
+			wantLayout: "pak (1 other files skipped)",
+		},
+		{
+			name:    "ue4ss mod directory",
+			members: []string{"Mods/CoolMod/Scripts/main.lua", "Mods/CoolMod/enabled.txt"},
+			want: []string{
+				"Pal/Binaries/Win64/ue4ss/Mods/CoolMod/Scripts/main.lua",
+				"Pal/Binaries/Win64/ue4ss/Mods/CoolMod/enabled.txt",
+			},
+			wantLayout: "ue4ss",
+		},
+		{
+			name:    "ue4ss mod contents with a pak",
+			members: []string{"Scripts/main.lua", "Scripts/lib/util.lua", "cool_P.pak"},
+			want: []string{
+				"Pal/Binaries/Win64/ue4ss/Mods/Cool Mod/Scripts/main.lua",
+				"Pal/Binaries/Win64/ue4ss/Mods/Cool Mod/Scripts/lib/util.lua",
+				"Pal/Content/Paks/~mods/0007_cool_P.pak",
+			},
+			wantLayout: "pak + ue4ss",
+		},
+		{
+			name:       "unknown",
+			members:    []string{"something/else.txt"},
+			want:       []string{"something/else.txt"},
+			wantLayout: "unknown (as-is)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &plan.Item{ModName: "Cool: Mod", Priority: 7}
+			got, layout := u.Map(item, tt.members)
+
+			rels := make([]string, 0, len(got))
+			for _, m := range got {
+				rels = append(rels, m.RelPath)
+			}
+			assert.Equal(t, tt.want, rels)
+			assert.Equal(t, tt.wantLayout, layout)
+		})
+	}
+}
+
+func TestUnrealPakPrefix(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0000_", UnrealPakPrefix(-1))
+	assert.Equal(t, "0012_", UnrealPakPrefix(12))
+	assert.Less(t, UnrealPakPrefix(9)+"a.pak", UnrealPakPrefix(10)+"a.pak")
+}
+
+func TestUnrealAnalyze(t *testing.T) {
+	t.Parallel()
+
+	u := Unreal{Project: "Pal", Paks: "Pal/Content/Paks", UE4SSMods: "Pal/Binaries/Win64/ue4ss/Mods"}
+	p := &plan.Plan{Files: []plan.File{
+		{RelPath: "Pal/Binaries/Win64/ue4ss/Mods/Cool/Scripts/main.lua"},
+		{RelPath: "Pal/Binaries/Win64/ue4ss/Mods/Enabled/Scripts/main.lua"},
+		{RelPath: "Pal/Binaries/Win64/ue4ss/Mods/Enabled/enabled.txt"},
+		{RelPath: "Pal/Binaries/Win64/ue4ss/Mods/shared/lib.lua"},
+		{RelPath: "Pal/Content/Paks/~mods/0001_cool_P.pak"},
+	}}
+
+	notes := u.Analyze(p)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "ue4ss_enable", notes[0].Kind)
+	assert.Equal(t, "Pal/Binaries/Win64/ue4ss/Mods/Cool", notes[0].Key)
+}
@@ -0,0 +1,195 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package integrations
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// steam appids of The Witcher 3 (the regular and GOTY editions)
+var witcher3SteamAppIDs = map[string]bool{
+	"292030": true,
+	"499450": true,
+}
+
+// Witcher3MergedDir is the mod directory that holds merged scripts. The game
+// gives mods whose directory name sorts first precedence, which is why
+// Script Merger uses this name too.
+const Witcher3MergedDir = "mod0000_MergedFiles"
+
+// the top-level directories of the game that mods install into
+var witcher3Roots = map[string]bool{
+	"bin":     true,
+	"content": true,
+	"dlc":     true,
+	"mods":    true,
+}
+
+// Witcher3 handles The Witcher 3 mods: it puts mod directories (modFoo/)
+// into mods/, and it finds script (.ws) files that more than one mod changes.
+// Those don't conflict by path (every mod has its own directory) but the game
+// only loads one of them so they need to be merged.
+type Witcher3 struct{}
+
+func (Witcher3) Name() string { return "witcher3" }
+
+func (Witcher3) Map(item *plan.Item, members []string) ([]plan.Mapped, string) {
+	if len(members) == 0 {
+		return nil, "empty"
+	}
+
+	// just the contents of a mod directory
+	if allUnderW3Content(members) {
+		name := "mod" + strings.ReplaceAll(sanitizeModDirName(item.ModName), " ", "")
+		return plan.Identity(members, "mods/"+name), "mod"
+	}
+
+	for _, strip := range []int{0, 1} {
+		stripped, ok := stripCommon(members, strip)
+		if !ok {
+			continue
+		}
+
+		if allUnderW3Roots(stripped) {
+			return remapped(members, stripped, ""), "as-is"
+		}
+		if allUnderW3ModDirs(stripped, "mod") {
+			return remapped(members, stripped, "mods/"), "mod"
+		}
+		if allUnderW3ModDirs(stripped, "dlc") {
+			return remapped(members, stripped, "dlc/"), "dlc"
+		}
+	}
+
+	return plan.Identity(members, ""), "unknown (as-is)"
+}
+
+func allUnderW3Roots(rels []string) bool {
+	for _, r := range rels {
+		first, _, ok := strings.Cut(r, "/")
+		if !ok || !witcher3Roots[strings.ToLower(first)] {
+			return false
+		}
+	}
+	return true
+}
+
+// allUnderW3ModDirs reports whether every file is in a <prefix>Something/
+// directory that looks like a mod (or dlc), i.e., has a content directory.
+func allUnderW3ModDirs(rels []string, prefix string) bool {
+	for _, r := range rels {
+		parts := strings.SplitN(r, "/", 3)
+		if len(parts) < 3 || !strings.HasPrefix(strings.ToLower(parts[0]), prefix) ||
+			!strings.EqualFold(parts[1], "content") {
+			return false
+		}
+	}
+	return true
+}
+
+// allUnderW3Content reports whether every file is in content/ but not in one
+// of the game's own content directories (content/content0/, content/patch0/,
+// ...), which a mod only includes if it replaces game files directly.
+func allUnderW3Content(rels []string) bool {
+	for _, r := range rels {
+		parts := strings.SplitN(strings.ToLower(r), "/", 3)
+		if len(parts) < 2 || parts[0] != "content" {
+			return false
+		}
+		if len(parts) == 3 && (strings.HasPrefix(parts[1], "content") || strings.HasPrefix(parts[1], "patch")) {
+			return false
+		}
+	}
+	return true
+}
+
+// Witcher3Script splits the relpath of a script in a mod directory
+// (mods/<mod>/content/scripts/<script>.ws) into the mod directory and the
+// script path relative to content/.
+func Witcher3Script(rel string) (string, string, bool) {
+	parts := strings.SplitN(rel, "/", 4)
+	if len(parts) < 4 || !strings.EqualFold(parts[0], "mods") ||
+		!strings.EqualFold(parts[2], "content") {
+		return "", "", false
+	}
+	if !strings.HasPrefix(strings.ToLower(parts[3]), "scripts/") ||
+		!strings.EqualFold(path.Ext(parts[3]), ".ws") {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+// Analyze reports the scripts that more than one mod directory provides.
+func (Witcher3) Analyze(p *plan.Plan) []plan.Note {
+	type group struct {
+		script  string
+		mods    []string
+		sources []plan.Source
+	}
+	groups := map[string]*group{}
+
+	for _, f := range p.Files {
+		modDir, script, ok := Witcher3Script(f.RelPath)
+		if !ok || strings.EqualFold(modDir, Witcher3MergedDir) {
+			continue
+		}
+
+		k := strings.ToLower(script)
+		g, ok := groups[k]
+		if !ok {
+			g = &group{script: script}
+			groups[k] = g
+		}
+		g.mods = append(g.mods, modDir)
+		g.sources = append(g.sources, f.Winner)
+	}
+
+	var notes []plan.Note
+	for _, g := range groups {
+		if len(g.mods) < 2 {
+			continue
+		}
+		notes = append(notes, plan.Note{
+			Kind: "script_merge",
+			Key:  g.script,
+			Message: fmt.Sprintf("requires script merging (%s); otherwise the game only loads the one from %s",
+				strings.Join(g.mods, ", "), firstW3Mod(g.mods)),
+			Sources: g.sources,
+		})
+	}
+
+	sort.Slice(notes, func(i, j int) bool { return notes[i].Key < notes[j].Key })
+	return notes
+}
+
+// firstW3Mod returns the mod directory that the game gives precedence.
+func firstW3Mod(mods []string) string {
+	first := mods[0]
+	for _, m := range mods[1:] {
+		if strings.ToLower(m) < strings.ToLower(first) {
+			first = m
+		}
+	}
+	return first
+}
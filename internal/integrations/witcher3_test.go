@@ -0,0 +1,121 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package integrations
+
+import (
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWitcher3Map(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		members    []string
+		want       []string
+		wantLayout string
+	}{
+		{
+			name:       "relative to the game dir",
+			members:    []string{"mods/modCool/content/scripts/game/a.ws", "bin/config/cool.xml"},
+			want:       []string{"mods/modCool/content/scripts/game/a.ws", "bin/config/cool.xml"},
+			wantLayout: "as-is",
+		},
+		{
+			name:       "bare mod directory",
+			members:    []string{"modCool/content/blob0.bundle", "modCool/content/scripts/game/a.ws"},
+			want:       []string{"mods/modCool/content/blob0.bundle", "mods/modCool/content/scripts/game/a.ws"},
+			wantLayout: "mod",
+		},
+		{
+			name:       "wrapped mod directory",
+			members:    []string{"Cool 1.0/modCool/content/blob0.bundle"},
+			want:       []string{"mods/modCool/content/blob0.bundle"},
+			wantLayout: "mod",
+		},
+		{
+			name:       "dlc directory",
+			members:    []string{"dlcCool/content/blob0.bundle"},
+			want:       []string{"dlc/dlcCool/content/blob0.bundle"},
+			wantLayout: "dlc",
+		},
+		{
+			name:       "contents of a mod directory",
+			members:    []string{"content/scripts/game/a.ws"},
+			want:       []string{"mods/modCoolMod/content/scripts/game/a.ws"},
+			wantLayout: "mod",
+		},
+		{
+			name:       "unknown",
+			members:    []string{"readme.txt"},
+			want:       []string{"readme.txt"},
+			wantLayout: "unknown (as-is)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &plan.Item{ModName: "Cool: Mod"}
+			got, layout := Witcher3{}.Map(item, tt.members)
+
+			rels := make([]string, 0, len(got))
+			for _, m := range got {
+				rels = append(rels, m.RelPath)
+			}
+			assert.Equal(t, tt.want, rels)
+			assert.Equal(t, tt.wantLayout, layout)
+		})
+	}
+}
+
+func TestWitcher3Analyze(t *testing.T) {
+	t.Parallel()
+
+	a := &plan.Item{ModName: "A"}
+	b := &plan.Item{ModName: "B"}
+	file := func(rel string, item *plan.Item) plan.File {
+		return plan.File{
+			Target:  plan.DefaultTarget,
+			RelPath: rel,
+			Winner:  plan.Source{Item: item, Member: rel},
+		}
+	}
+
+	p := &plan.Plan{Files: []plan.File{
+		file("mods/modB/content/scripts/game/player.ws", b),
+		file("mods/modA/content/scripts/game/player.ws", a),
+		file("mods/modA/content/scripts/game/only.ws", a),
+		file("mods/modB/content/blob0.bundle", b),
+		file("mods/mod0000_MergedFiles/content/scripts/game/player.ws", a),
+	}}
+
+	notes := Witcher3{}.Analyze(p)
+	if assert.Len(t, notes, 1) {
+		assert.Equal(t, "script_merge", notes[0].Kind)
+		assert.Equal(t, "scripts/game/player.ws", notes[0].Key)
+		assert.Contains(t, notes[0].Message, "only loads the one from modA")
+		assert.Len(t, notes[0].Sources, 2)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/blobstore"
+)
+
+// IOBenchResult is how fast a file was read and hashed with a tuning.
+type IOBenchResult struct {
+	Tuning   blobstore.Tuning `json:"tuning"`
+	Bytes    int64            `json:"bytes"`
+	Duration time.Duration    `json:"duration"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// Throughput is in bytes per second.
+func (r IOBenchResult) Throughput() float64 {
+	if r.Duration <= 0 || r.Error != "" {
+		return 0
+	}
+	return float64(r.Bytes) / r.Duration.Seconds()
+}
+
+// ioBenchBufferSizes are the buffer sizes that BenchIO tries.
+var ioBenchBufferSizes = []int{64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20}
+
+// ioBenchReadAheads are the read ahead windows that BenchIO tries.
+var ioBenchReadAheads = []int64{0, 8 << 20, 32 << 20}
+
+// BenchIO reads and hashes the file at path the way the blob store does,
+// first with base and every buffer size, then with the fastest buffer size
+// and every other setting changed one at a time (O_DIRECT, each fadvise
+// hint, each read ahead window). The file is dropped from the page cache
+// before every run (where the kernel allows it) so that it's read from the
+// disk every time. done is called after every run.
+func BenchIO(ctx context.Context, path string, base blobstore.Tuning, done func(IOBenchResult)) ([]IOBenchResult, error) {
+	prev := blobstore.CurrentTuning()
+	defer blobstore.SetTuning(prev)
+
+	var results []IOBenchResult
+	run := func(t blobstore.Tuning) error {
+		for _, r := range results {
+			if r.Tuning == t {
+				return nil
+			}
+		}
+		r := benchRead(ctx, path, t)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		results = append(results, r)
+		if done != nil {
+			done(r)
+		}
+		return nil
+	}
+
+	if err := run(base); err != nil {
+		return results, err
+	}
+	for _, size := range ioBenchBufferSizes {
+		t := base
+		t.BufferSize = size
+		if err := run(t); err != nil {
+			return results, err
+		}
+	}
+
+	best := base
+	if r, ok := FastestIOBench(results); ok {
+		best.BufferSize = r.Tuning.BufferSize
+	}
+
+	var variants []blobstore.Tuning
+	t := best
+	t.Direct = !t.Direct
+	variants = append(variants, t)
+	for _, mode := range blobstore.FadviseModes {
+		t := best
+		t.Fadvise = mode
+		variants = append(variants, t)
+	}
+	for _, ra := range ioBenchReadAheads {
+		t := best
+		t.ReadAhead = ra
+		variants = append(variants, t)
+	}
+	for _, t := range variants {
+		if err := run(t); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// FastestIOBench returns the result (of BenchIO) with the highest
+// throughput.
+func FastestIOBench(results []IOBenchResult) (IOBenchResult, bool) {
+	if len(results) == 0 {
+		return IOBenchResult{}, false
+	}
+	return slices.MaxFunc(results, func(a, b IOBenchResult) int {
+		switch {
+		case a.Throughput() < b.Throughput():
+			return -1
+		case a.Throughput() > b.Throughput():
+			return 1
+		default:
+			return 0
+		}
+	}), true
+}
+
+func benchRead(ctx context.Context, path string, t blobstore.Tuning) IOBenchResult {
+	r := IOBenchResult{Tuning: t}
+
+	blobstore.SetTuning(t)
+	blobstore.DropCache(path)
+
+	start := time.Now()
+	f, err := blobstore.OpenRead(path)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	defer f.Close()
+
+	r.Bytes, err = blobstore.CopyWithContext(ctx, sha256.New(), f, nil)
+	r.Duration = time.Since(start)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// WriteIOBenchFile writes size random bytes to a new file in dir for
+// BenchIO and returns its path.
+func WriteIOBenchFile(ctx context.Context, dir string, size int64) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, "bench-io-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := blobstore.CopyWithContext(ctx, f, io.LimitReader(rand.Reader, size), nil); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("write test file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
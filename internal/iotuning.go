@@ -0,0 +1,71 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/spf13/viper"
+)
+
+// IOTuningFromConfig returns how blobs are read as configured by the io_*
+// options (see blobstore.Tuning).
+func IOTuningFromConfig() (blobstore.Tuning, error) {
+	t := blobstore.Tuning{
+		Direct:  viper.GetBool("io_direct"),
+		Fadvise: viper.GetString("io_fadvise"),
+	}
+
+	size, err := configBytes(viper.Get("io_buffer_size"))
+	if err != nil {
+		return t, fmt.Errorf("io_buffer_size: %w", err)
+	}
+	t.BufferSize = int(size)
+
+	if t.ReadAhead, err = configBytes(viper.Get("io_readahead")); err != nil {
+		return t, fmt.Errorf("io_readahead: %w", err)
+	}
+
+	return t, nil
+}
+
+// ApplyIOConfig makes the blob store read files as configured.
+func ApplyIOConfig() error {
+	t, err := IOTuningFromConfig()
+	if err != nil {
+		return err
+	}
+	blobstore.SetTuning(t)
+	return nil
+}
+
+// configBytes is the value of a size option in bytes.
+func configBytes(v any) (int64, error) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case string:
+		return ParseBytes(n)
+	default:
+		return 0, fmt.Errorf("not a size: %v", v)
+	}
+}
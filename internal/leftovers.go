@@ -0,0 +1,262 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// the mod managers whose leftovers ScanLeftovers finds
+const (
+	ManagerVortex = "vortex"
+	ManagerMO2    = "mo2"
+)
+
+// leftoverQuickDepth is how deep QuickScan looks below every target root:
+// enough for a mod folder (e.g., Data) or a portable MO2 instance inside of
+// the game directory.
+const leftoverQuickDepth = 2
+
+// Leftover is something that another mod manager left in a target.
+type Leftover struct {
+	Manager string `json:"manager"`
+	// e.g., "deployment manifest"
+	Kind    string `json:"kind"`
+	Target  string `json:"target"`
+	RelPath string `json:"relpath"`
+	Path    string `json:"path"`
+	// what's in it (e.g., how many files a manifest lists)
+	Detail string `json:"detail,omitempty"`
+	// how to clean it up or import it into modctl
+	Suggestion string `json:"suggestion"`
+}
+
+func (l Leftover) String() string {
+	return l.Target + "/" + l.RelPath
+}
+
+// vortexManifest is the part of a vortex.deployment*.json that matters: the
+// files that Vortex deployed, relative to the directory of the manifest, and
+// the mod (in its staging folder) that each one came from.
+type vortexManifest struct {
+	StagingPath string `json:"stagingPath"`
+	Files       []struct {
+		RelPath string `json:"relPath"`
+		Source  string `json:"source"`
+	} `json:"files"`
+}
+
+// ScanLeftovers looks for what other mod managers left in the targets of a
+// game install: Vortex deployment manifests and backups (*.vortex_backup),
+// portable Mod Organizer 2 instances with their mods and overwrite
+// directories, and files that MO2 hid (*.mohidden). With quick it only looks
+// a couple of directories deep (see leftoverQuickDepth).
+func ScanLeftovers(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall, quick bool) ([]Leftover, error) {
+	targets, _, err := gameScanTargets(ctx, q, gi)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := 0
+	if quick {
+		depth = leftoverQuickDepth
+	}
+	return scanLeftovers(ctx, targets, depth)
+}
+
+// scanLeftovers walks the target roots (at most depth directories deep, 0
+// means everything). Like scanTargets, directories below the root of a more
+// specific target belong to that one.
+func scanLeftovers(ctx context.Context, targets []scanTarget, depth int) ([]Leftover, error) {
+	roots := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		roots[filepath.Clean(t.root)] = true
+	}
+
+	var out []Leftover
+	for _, t := range targets {
+		root := filepath.Clean(t.root)
+		if st, err := os.Stat(root); err != nil || !st.IsDir() {
+			continue
+		}
+
+		add := func(path, manager, kind, detail, suggestion string) {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				rel = path
+			}
+			out = append(out, Leftover{
+				Manager:    manager,
+				Kind:       kind,
+				Target:     t.name,
+				RelPath:    filepath.ToSlash(rel),
+				Path:       path,
+				Detail:     detail,
+				Suggestion: suggestion,
+			})
+		}
+
+		err := filepath.WalkDir(root, func(path string, e fs.DirEntry, err error) error {
+			if err != nil {
+				// unreadable directories aren't worth failing the scan over
+				if e != nil && e.IsDir() && path != root {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if e.IsDir() {
+				if path != root && roots[path] {
+					return filepath.SkipDir
+				}
+				if depth > 0 && path != root {
+					rel, _ := filepath.Rel(root, path)
+					if strings.Count(rel, string(filepath.Separator))+1 > depth {
+						return filepath.SkipDir
+					}
+				}
+				if _, err := os.Stat(filepath.Join(path, "ModOrganizer.ini")); err == nil {
+					scanMO2Instance(path, add)
+					// its mods are MO2's business, not leftovers
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			name := e.Name()
+			lower := strings.ToLower(name)
+			switch {
+			case strings.HasPrefix(lower, "vortex.deployment") && strings.HasSuffix(lower, ".json"):
+				add(path, ManagerVortex, "deployment manifest", vortexManifestDetail(path),
+					"purge the mods in Vortex (or delete the files that the manifest lists) before applying a profile; "+
+						"import the mods from Vortex's staging folder with `modctl mods pack <dir>`")
+			case name == "__vortex_staging_folder":
+				add(filepath.Dir(path), ManagerVortex, "staging folder", "",
+					"import the mods in it with `modctl mods pack <dir>`, then delete it")
+			case strings.HasSuffix(lower, ".vortex_backup"):
+				add(path, ManagerVortex, "backup", "the original of "+strings.TrimSuffix(name, filepath.Ext(name)),
+					"restore it over the modded file (or verify the game files in Steam) and delete it")
+			case strings.HasSuffix(lower, ".mohidden"):
+				add(path, ManagerMO2, "hidden file", "hidden "+strings.TrimSuffix(name, filepath.Ext(name)),
+					"rename it back if the game needs it, otherwise delete it")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scan target %s: %w", t.name, err)
+		}
+	}
+
+	// targets can overlap without being nested (e.g., the same directory
+	// twice), only report everything once
+	seen := map[string]bool{}
+	deduped := out[:0]
+	for _, l := range out {
+		if !seen[l.Path] {
+			seen[l.Path] = true
+			deduped = append(deduped, l)
+		}
+	}
+
+	sort.SliceStable(deduped, func(i, j int) bool { return deduped[i].Path < deduped[j].Path })
+	return deduped, nil
+}
+
+// vortexManifestDetail summarizes a deployment manifest: how many of the
+// files it lists are still there, and from how many mods.
+func vortexManifestDetail(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var m vortexManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return "unreadable: " + err.Error()
+	}
+
+	dir := filepath.Dir(path)
+	mods := map[string]bool{}
+	present := 0
+	for _, f := range m.Files {
+		mods[f.Source] = true
+		rel := filepath.FromSlash(strings.ReplaceAll(f.RelPath, `\`, "/"))
+		if _, err := os.Lstat(filepath.Join(dir, rel)); err == nil {
+			present++
+		}
+	}
+
+	detail := fmt.Sprintf("%d of %d deployed files still there, from %d mods", present, len(m.Files), len(mods))
+	if m.StagingPath != "" {
+		detail += " (staging folder " + m.StagingPath + ")"
+	}
+	return detail
+}
+
+// scanMO2Instance reports a portable MO2 instance, its mods (that can be
+// imported), and its overwrite directory if there's anything in it.
+func scanMO2Instance(dir string, add func(path, manager, kind, detail, suggestion string)) {
+	mods, _ := os.ReadDir(filepath.Join(dir, "mods"))
+	var names []string
+	for _, e := range mods {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	detail := fmt.Sprintf("%d mods", len(names))
+	add(dir, ManagerMO2, "instance", detail,
+		"import its mods with `modctl mods pack "+filepath.Join(dir, "mods", "<mod>")+"`, then remove the instance")
+
+	overwrite := filepath.Join(dir, "overwrite")
+	n, err := countFiles(overwrite)
+	if err == nil && n > 0 {
+		add(overwrite, ManagerMO2, "overwrite directory", fmt.Sprintf("%d file(s) written by tools run from MO2", n),
+			"import them as a mod with `modctl mods pack "+overwrite+"`")
+	}
+}
+
+// countFiles counts the regular files below dir.
+func countFiles(dir string) (int, error) {
+	n := 0
+	err := filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.Type().IsRegular() {
+			n++
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	return n, err
+}
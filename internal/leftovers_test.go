@@ -0,0 +1,97 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanLeftovers(t *testing.T) {
+	t.Parallel()
+
+	game := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(game, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+
+	write("Data/vortex.deployment.json", `{"stagingPath":"C:\\Vortex\\skyrimse","files":[
+		{"relPath":"textures\\a.dds","source":"A"},
+		{"relPath":"b.esp","source":"B"},
+		{"relPath":"c.esp","source":"B"}]}`)
+	write("Data/textures/a.dds", "a")
+	write("Data/b.esp", "b")
+	write("Data/Skyrim.esm.vortex_backup", "vanilla")
+	write("Data/meshes/actors/deep/x.nif.mohidden", "x")
+	write("MO2/ModOrganizer.ini", "[General]")
+	write("MO2/mods/SkyUI/SkyUI.esp", "s")
+	write("MO2/mods/USSEP/USSEP.esp", "u")
+	write("MO2/mods/USSEP/meta.ini.mohidden", "ignored: inside the instance")
+	write("MO2/overwrite/SKSE/log.txt", "l")
+	write("skse.log", "not a leftover")
+
+	targets := []scanTarget{
+		{id: 1, name: "game_dir", root: game},
+		{id: 2, name: "data", root: filepath.Join(game, "Data")},
+	}
+
+	byPath := func(ls []Leftover) map[string]Leftover {
+		m := map[string]Leftover{}
+		for _, l := range ls {
+			m[l.String()] = l
+		}
+		return m
+	}
+
+	all, err := scanLeftovers(context.Background(), targets, 0)
+	require.NoError(t, err)
+	found := byPath(all)
+	assert.Len(t, found, 5)
+
+	manifest := found["data/vortex.deployment.json"]
+	assert.Equal(t, ManagerVortex, manifest.Manager)
+	assert.Equal(t, `2 of 3 deployed files still there, from 2 mods (staging folder C:\Vortex\skyrimse)`, manifest.Detail)
+
+	assert.Equal(t, "backup", found["data/Skyrim.esm.vortex_backup"].Kind)
+	assert.Equal(t, "the original of Skyrim.esm", found["data/Skyrim.esm.vortex_backup"].Detail)
+	assert.Equal(t, ManagerMO2, found["data/meshes/actors/deep/x.nif.mohidden"].Manager)
+	assert.Equal(t, "2 mods", found["game_dir/MO2"].Detail)
+	assert.Equal(t, "overwrite directory", found["game_dir/MO2/overwrite"].Kind)
+
+	// a quick scan doesn't go as deep
+	quick, err := scanLeftovers(context.Background(), targets, leftoverQuickDepth)
+	require.NoError(t, err)
+	found = byPath(quick)
+	assert.Len(t, found, 4)
+	assert.NotContains(t, found, "data/meshes/actors/deep/x.nif.mohidden")
+
+	none, err := scanLeftovers(context.Background(), []scanTarget{
+		{id: 1, name: "missing", root: filepath.Join(game, "missing")},
+	}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
@@ -0,0 +1,70 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/lock"
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/mfinelli/modctl/internal/state"
+)
+
+// LockState takes the lock that serializes commands that mutate the state
+// directory, the database, or game installs. command (e.g.,
+// "modctl mods import") is reported to other invocations while the lock is
+// held. Read-only commands should not take the lock.
+//
+// Taking the lock also removes stale files from the tmp dir (see
+// tmp_max_age): nobody else can be using them.
+func LockState(command string) (*lock.Lock, error) {
+	// every command that changes something takes the lock
+	if err := readonly.Check(command); err != nil {
+		return nil, err
+	}
+
+	path, err := state.Path("modctl.lock")
+	if err != nil {
+		return nil, fmt.Errorf("locate lock file: %w", err)
+	}
+
+	l, err := lock.Acquire(path, command)
+	if err != nil {
+		return nil, err
+	}
+
+	reapTmp()
+	return l, nil
+}
+
+// LockWatch takes the lock that makes sure that only one `modctl watch`
+// watches a game install. It's separate from the state lock (see LockState)
+// so that the watcher doesn't stop other commands from running.
+func LockWatch(command string, gameInstallID int64) (*lock.Lock, error) {
+	if err := readonly.Check(command); err != nil {
+		return nil, err
+	}
+
+	path, err := state.Path(fmt.Sprintf("watch-%d.lock", gameInstallID))
+	if err != nil {
+		return nil, fmt.Errorf("locate lock file: %w", err)
+	}
+
+	return lock.Acquire(path, command)
+}
@@ -0,0 +1,123 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package lock implements the advisory lock that serializes modctl commands
+// that mutate the state directory, the database, or game installs.
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Holder describes the process holding the lock. It is written into the lock
+// file so that other invocations can say who they are waiting on.
+type Holder struct {
+	PID       int    `json:"pid"`
+	Command   string `json:"command"`
+	Hostname  string `json:"hostname,omitempty"`
+	StartedAt string `json:"started_at"`
+}
+
+// HeldError is returned by Acquire when another process holds the lock.
+type HeldError struct {
+	Path   string
+	Holder Holder // zero if the lock file couldn't be read
+}
+
+func (e *HeldError) Error() string {
+	if e.Holder.PID == 0 {
+		return fmt.Sprintf("another modctl command is running (lock: %s)", e.Path)
+	}
+	return fmt.Sprintf("another modctl command is running: pid %d (%s) since %s (lock: %s)",
+		e.Holder.PID, e.Holder.Command, e.Holder.StartedAt, e.Path)
+}
+
+// Lock is a held lock; call Release when done.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire takes the lock at path without blocking. command is recorded as
+// the holder so that it can be reported to anyone else that tries to take
+// the lock. The lock is tied to the open file so it's released by the
+// operating system if the process dies.
+func Acquire(path, command string) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	if err := tryLock(f); err != nil {
+		defer f.Close()
+		if errors.Is(err, errWouldBlock) {
+			held := &HeldError{Path: path}
+			if b, rerr := io.ReadAll(f); rerr == nil {
+				_ = json.Unmarshal(b, &held.Holder)
+			}
+			return nil, held
+		}
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+
+	host, _ := os.Hostname()
+	b, err := json.Marshal(Holder{
+		PID:       os.Getpid(),
+		Command:   command,
+		Hostname:  host,
+		StartedAt: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	})
+	if err != nil {
+		unlock(f)
+		f.Close()
+		return nil, fmt.Errorf("marshal lock holder: %w", err)
+	}
+
+	if err := f.Truncate(0); err == nil {
+		_, err = f.WriteAt(append(b, '\n'), 0)
+	}
+	if err != nil {
+		unlock(f)
+		f.Close()
+		return nil, fmt.Errorf("write lock file: %w", err)
+	}
+
+	return &Lock{f: f}, nil
+}
+
+// Release clears the holder information and releases the lock. The lock
+// file itself is left in place: removing it would race with other processes
+// that have already opened it.
+func (l *Lock) Release() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+
+	_ = l.f.Truncate(0)
+	err := unlock(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+
+	return err
+}
@@ -0,0 +1,55 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "modctl.lock")
+
+	l, err := Acquire(path, "modctl mods import")
+	require.NoError(t, err)
+
+	// a second acquisition (separate open file description) must fail and
+	// report the holder
+	_, err = Acquire(path, "modctl games refresh")
+	var held *HeldError
+	require.ErrorAs(t, err, &held)
+	assert.Equal(t, os.Getpid(), held.Holder.PID)
+	assert.Equal(t, "modctl mods import", held.Holder.Command)
+
+	require.NoError(t, l.Release())
+
+	// released: can be taken again
+	l, err = Acquire(path, "modctl games refresh")
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+
+	// releasing twice is harmless
+	require.NoError(t, l.Release())
+}
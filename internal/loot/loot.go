@@ -0,0 +1,92 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package loot integrates with LOOT (the Load Order Optimisation Tool) to sort
+// the plugins of Bethesda games.
+package loot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/perf"
+)
+
+// Game describes where a game keeps its plugin load order and how LOOT
+// refers to it.
+type Game struct {
+	// LOOT's name for the game (the --game argument)
+	LootID string
+	// the directory under %LOCALAPPDATA% that has plugins.txt
+	LocalFolder string
+}
+
+// steamGames are the games that we know how to sort, by steam appid. They
+// all use the "asterisk" plugins.txt format where the order of the lines is
+// the load order and active plugins are prefixed with a "*".
+var steamGames = map[string]Game{
+	"489830":  {LootID: "SkyrimSE", LocalFolder: "Skyrim Special Edition"},
+	"611670":  {LootID: "Skyrim VR", LocalFolder: "Skyrim VR"},
+	"377160":  {LootID: "Fallout4", LocalFolder: "Fallout4"},
+	"611660":  {LootID: "Fallout4VR", LocalFolder: "Fallout4VR"},
+	"1716740": {LootID: "Starfield", LocalFolder: "Starfield"},
+}
+
+// ForSteamApp returns the game with the given steam appid, if it has a
+// plugin load order that we can manage.
+func ForSteamApp(appid string) (Game, bool) {
+	g, ok := steamGames[appid]
+	return g, ok
+}
+
+// PluginsPath returns the path to the game's plugins.txt inside of the given
+// proton prefix.
+func (g Game) PluginsPath(prefix string) string {
+	return filepath.Join(prefix, "drive_c", "users", "steamuser",
+		"AppData", "Local", g.LocalFolder, "plugins.txt")
+}
+
+// DefaultCommand is the LOOT invocation used when none is configured. LOOT
+// sorts the load order, writes it to plugins.txt, and exits.
+var DefaultCommand = []string{
+	"loot", "--game", "${game}", "--game-path", "${game_path}", "--auto-sort",
+}
+
+// Run runs the (already expanded) LOOT command. LOOT's output is only
+// returned (as part of the error) if it fails.
+func Run(ctx context.Context, command []string) error {
+	defer perf.Track(perf.Commands)()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%s failed: %w\n%s", command[0], err, msg)
+		}
+		return fmt.Errorf("%s failed: %w", command[0], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package loot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlugins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []Plugin
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name: "asterisk format",
+			input: "# This file is used by the game to keep track of your downloaded content.\r\n" +
+				"*Unofficial Skyrim Special Edition Patch.esp\r\n" +
+				"SkyUI_SE.esp\r\n" +
+				"*Alternate Start - Live Another Life.esp\r\n",
+			want: []Plugin{
+				{Name: "Unofficial Skyrim Special Edition Patch.esp", Enabled: true},
+				{Name: "SkyUI_SE.esp", Enabled: false},
+				{Name: "Alternate Start - Live Another Life.esp", Enabled: true},
+			},
+		},
+		{
+			name:  "bom, blank lines, and duplicates",
+			input: "\uFEFF*A.esp\n\n  *B.esm  \nb.esm\n*\n",
+			want: []Plugin{
+				{Name: "A.esp", Enabled: true},
+				{Name: "B.esm", Enabled: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePlugins(strings.NewReader(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package loot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Plugin is a single entry of the load order.
+type Plugin struct {
+	Name    string
+	Enabled bool
+}
+
+// ParsePlugins reads a plugins.txt in the "asterisk" format: one plugin per
+// line in load order, active plugins prefixed with "*", and "#" comments.
+// Duplicate entries (ignoring case, like the game does) keep their first
+// position.
+func ParsePlugins(r io.Reader) ([]Plugin, error) {
+	var plugins []Plugin
+	seen := map[string]bool{}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(sc.Text(), "\uFEFF"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := Plugin{Name: line}
+		if strings.HasPrefix(line, "*") {
+			p.Name = strings.TrimSpace(line[1:])
+			p.Enabled = true
+		}
+		if p.Name == "" {
+			continue
+		}
+
+		key := strings.ToLower(p.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		plugins = append(plugins, p)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return plugins, nil
+}
+
+// ReadPlugins reads the plugins.txt at path.
+func ReadPlugins(path string) ([]Plugin, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParsePlugins(f)
+}
+
+// WritePlugins atomically replaces the plugins.txt at path with the given
+// load order.
+func WritePlugins(path string, plugins []Plugin) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".plugins-*.txt")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op after a successful rename
+
+	// the game itself writes CRLF line endings
+	w := bufio.NewWriter(tmp)
+	fmt.Fprint(w, "# This file was written by modctl\r\n")
+	for _, p := range plugins {
+		if p.Enabled {
+			fmt.Fprint(w, "*")
+		}
+		fmt.Fprintf(w, "%s\r\n", p.Name)
+	}
+
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", tmpName, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync %s: %w", tmpName, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", tmpName, err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	return nil
+}
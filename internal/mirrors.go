@@ -0,0 +1,277 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/download"
+	"github.com/mfinelli/modctl/internal/offline"
+	"github.com/spf13/viper"
+)
+
+// errNotOnMirror is returned (wrapped) when a mirror doesn't have a blob.
+var errNotOnMirror = errors.New("not on the mirror")
+
+// BlobMirrors returns the configured blob mirrors (blob_mirrors).
+func BlobMirrors() []string {
+	return viper.GetStringSlice("blob_mirrors")
+}
+
+// isRemoteMirror reports whether a mirror is an http(s) url (and not a
+// local directory).
+func isRemoteMirror(m string) bool {
+	return strings.HasPrefix(m, "http://") || strings.HasPrefix(m, "https://")
+}
+
+// mirrorBlobPath returns where a local mirror keeps the archive with the
+// given sha256. The mirror is either a modctl data dir (which keeps its
+// archives in archives/) or an archives dir itself: both use the layout of
+// the blob store, <ab>/<sha256>.
+func mirrorBlobPath(m, sha string) string {
+	root := m
+	if st, err := os.Stat(filepath.Join(m, "archives")); err == nil && st.IsDir() {
+		root = filepath.Join(m, "archives")
+	}
+	return filepath.Join(root, sha[:2], sha)
+}
+
+// mirrorBlobURL returns where a remote mirror serves the archive with the
+// given sha256, <url>/<ab>/<sha256>.
+func mirrorBlobURL(m, sha string) (string, error) {
+	u, err := url.Parse(m)
+	if err != nil {
+		return "", err
+	}
+	return u.JoinPath(sha[:2], sha).String(), nil
+}
+
+// mirrorFetcher fetches archives from the blob mirrors into a blob store.
+type mirrorFetcher struct {
+	Q       *dbq.Queries
+	Blobs   blobstore.Store
+	Mirrors []string
+
+	// downloads from remote mirrors respect the download limits, the
+	// manager is only set up once one is needed
+	downloads *download.Manager
+}
+
+// Fetch copies the archive with the given sha256 into the blob store from
+// the first mirror that has it (checking that its hash matches), records
+// that the blob is there (again, if it was quarantined) and which mirror
+// it came from, and returns the mirror. The error of every mirror that was
+// tried is returned if none had it.
+func (f *mirrorFetcher) Fetch(ctx context.Context, sha string, size int64) (string, error) {
+	var errs []error
+	for _, m := range f.Mirrors {
+		err := f.fetchFrom(ctx, m, sha, size)
+		if err == nil {
+			now := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+			if err := blobstore.EnsureBlobRecorded(ctx, f.Q, sha, string(blobstore.KindArchive), size, nil); err != nil {
+				return m, err
+			}
+			if err := f.Q.SetBlobFetchedFrom(ctx, dbq.SetBlobFetchedFromParams{
+				FetchedFrom: sql.NullString{String: m, Valid: true},
+				FetchedAt:   sql.NullString{String: now, Valid: true},
+				Sha256:      sha,
+			}); err != nil {
+				return m, fmt.Errorf("record blob provenance: %w", err)
+			}
+			return m, nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", m, err))
+	}
+	return "", errors.Join(errs...)
+}
+
+// fetchFrom copies an archive from a single mirror into the blob store.
+func (f *mirrorFetcher) fetchFrom(ctx context.Context, m, sha string, size int64) error {
+	src := mirrorBlobPath(m, sha)
+	if isRemoteMirror(m) {
+		tmp, err := f.download(ctx, m, sha)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp)
+		src = tmp
+	} else {
+		st, err := os.Stat(src)
+		if errors.Is(err, os.ErrNotExist) {
+			return errNotOnMirror
+		}
+		if err != nil {
+			return err
+		}
+		if st.Size() != size {
+			return fmt.Errorf("%w: size %d, expected %d", blobstore.ErrCorrupt, st.Size(), size)
+		}
+	}
+
+	res, err := f.Blobs.IngestFile(ctx, blobstore.KindArchive, src)
+	if err != nil {
+		return err
+	}
+	if res.SHA256Hex != sha {
+		// it went into the store under the hash it really has
+		if !res.Existed {
+			_ = f.Blobs.Remove(blobstore.KindArchive, res.SHA256Hex)
+		}
+		return fmt.Errorf("%w: sha256 %s, expected %s", blobstore.ErrCorrupt, shortSHA(res.SHA256Hex), shortSHA(sha))
+	}
+	return nil
+}
+
+// download downloads an archive from a remote mirror to a temporary file
+// and returns its path.
+func (f *mirrorFetcher) download(ctx context.Context, m, sha string) (string, error) {
+	if err := offline.Check("fetch from blob mirror"); err != nil {
+		return "", err
+	}
+
+	uri, err := mirrorBlobURL(m, sha)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "modctl")
+
+	if f.downloads == nil {
+		if f.downloads, err = NewDownloadManager(ctx, f.Q); err != nil {
+			return "", err
+		}
+	}
+	done, err := f.downloads.Start(ctx, req.URL.Hostname())
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", errNotOnMirror
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download: %s", resp.Status)
+	}
+
+	if err := os.MkdirAll(f.Blobs.TmpDir, 0o755); err != nil {
+		return "", fmt.Errorf("create tmp dir: %w", err)
+	}
+	out, err := os.CreateTemp(f.Blobs.TmpDir, "mirror-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, f.downloads.Reader(ctx, req.URL.Hostname(), resp.Body)); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", fmt.Errorf("download: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
+
+// archiveMissing reports whether an archive has to be fetched from the blob
+// mirrors before it can be extracted: it's not in the blob store or it was
+// quarantined.
+func (d *Deployer) archiveMissing(blob dbq.Blob) (bool, error) {
+	if blob.CorruptedAt.Valid {
+		return true, nil
+	}
+	ap, err := d.Blobs.Locate(blobstore.KindArchive, blob.Sha256)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(ap)
+	return errors.Is(err, os.ErrNotExist), nil
+}
+
+// fetchMissingArchives fetches the archives of the pending files that are
+// missing from the blob store (or were quarantined) from the blob mirrors,
+// before anything reads them. If no mirror has one the apply fails with a
+// BlobMissingError (like it does without mirrors), with what every mirror
+// answered.
+func (d *Deployer) fetchMissingArchives(ctx context.Context, desired map[pathKey]*desiredFile, keys []pathKey, res *DeployResult) error {
+	if len(d.Mirrors) == 0 {
+		return nil
+	}
+	f := &mirrorFetcher{Q: d.Q, Blobs: d.Blobs, Mirrors: d.Mirrors}
+
+	seen := map[string]bool{}
+	for _, k := range keys {
+		sha := desired[k].archiveSHA
+		if sha == "" || seen[sha] {
+			continue
+		}
+		seen[sha] = true
+
+		blob, err := d.Q.GetBlob(ctx, sha)
+		if err != nil {
+			return fmt.Errorf("lookup blob %s: %w", shortSHA(sha), err)
+		}
+		missing, err := d.archiveMissing(blob)
+		if err != nil {
+			return err
+		}
+		if !missing {
+			continue
+		}
+
+		m, err := f.Fetch(ctx, sha, blob.SizeBytes)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			what := "it's not in the blob store"
+			if blob.CorruptedAt.Valid {
+				what = "it was quarantined"
+			}
+			return BlobMissingError(sha, fmt.Errorf("%s and no blob mirror has it: %w", what, err))
+		}
+		res.Warnings = append(res.Warnings, fmt.Sprintf(
+			"archive %s was missing, fetched it from %s", shortSHA(sha), m))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mirrorTestSHA = "ab0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcd"
+
+func TestMirrorBlobPath(t *testing.T) {
+	t.Parallel()
+
+	// an archives dir (e.g., a NAS export)
+	dir := t.TempDir()
+	assert.Equal(t, filepath.Join(dir, "ab", mirrorTestSHA), mirrorBlobPath(dir, mirrorTestSHA))
+
+	// a modctl data dir
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "archives"), 0o755))
+	assert.Equal(t, filepath.Join(dir, "archives", "ab", mirrorTestSHA), mirrorBlobPath(dir, mirrorTestSHA))
+}
+
+func TestMirrorBlobURL(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range []string{"https://example.com/modctl", "https://example.com/modctl/"} {
+		u, err := mirrorBlobURL(m, mirrorTestSHA)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/modctl/ab/"+mirrorTestSHA, u)
+	}
+
+	assert.True(t, isRemoteMirror("http://nas.local/archives"))
+	assert.False(t, isRemoteMirror("/mnt/nas/modctl"))
+}
+
+func TestCheckBlobMirrors(t *testing.T) {
+	t.Parallel()
+
+	opt := configSchema["blob_mirrors"]
+	assert.NoError(t, checkConfigValue(opt, []any{}))
+	assert.NoError(t, checkConfigValue(opt, []any{"/mnt/nas/modctl", "https://example.com/archives"}))
+	assert.Error(t, checkConfigValue(opt, []any{"nas/modctl"}))
+	assert.Error(t, checkConfigValue(opt, []any{"ftp://example.com/archives"}))
+	assert.Error(t, checkConfigValue(opt, []any{""}))
+	assert.Error(t, checkConfigValue(opt, "/mnt/nas/modctl"))
+}
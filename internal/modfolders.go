@@ -0,0 +1,227 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// ModFolder is a conventional mod directory in a game install that could be
+// a target.
+type ModFolder struct {
+	Target  string `json:"target"`
+	RelPath string `json:"relpath"` // below the install root
+	Reason  string `json:"reason"`
+	// the directory doesn't exist yet (the game or its mod loader only
+	// looks for it)
+	Missing bool `json:"missing,omitempty"`
+}
+
+// DetectModFolders looks for the directories that mod loaders and engines
+// conventionally load mods from (e.g., BepInEx/plugins or custom/ of source
+// engine games) in an install root. Unreal Engine games aren't included:
+// their handler already puts pak mods into Paks/~mods. Names are matched
+// regardless of case but the results have them as they are on disk.
+func DetectModFolders(installRoot string) ([]ModFolder, error) {
+	var out []ModFolder
+	add := func(target, rel, reason string, missing bool) {
+		out = append(out, ModFolder{Target: target, RelPath: rel, Reason: reason, Missing: missing})
+	}
+
+	if rel, ok := FindDir(installRoot, "mods"); ok {
+		add("mods", rel, "a mods directory", false)
+	}
+
+	if bepinex, ok := FindDir(installRoot, "BepInEx"); ok {
+		for _, sub := range []string{"plugins", "patchers"} {
+			if rel, ok := FindDir(installRoot, path.Join(bepinex, sub)); ok {
+				add("bepinex_"+sub, rel, "BepInEx is installed", false)
+			}
+		}
+	}
+
+	if _, ok := FindDir(installRoot, "MelonLoader"); ok {
+		rel, ok := FindDir(installRoot, "Plugins")
+		if !ok {
+			rel = "Plugins"
+		}
+		add("melonloader_plugins", rel, "MelonLoader is installed", !ok)
+	}
+
+	entries, err := os.ReadDir(installRoot)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", installRoot, err)
+	}
+	var source []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.EqualFold(e.Name(), "Engine") {
+			continue
+		}
+		// source engine: <game>/gameinfo.txt next to addons/ and custom/
+		if _, err := os.Stat(filepath.Join(installRoot, e.Name(), "gameinfo.txt")); err == nil {
+			source = append(source, e.Name())
+		}
+	}
+
+	for i, dir := range source {
+		for _, sub := range []string{"addons", "custom"} {
+			if rel, ok := FindDir(installRoot, path.Join(dir, sub)); ok {
+				add(numbered(sub, i), rel, "source engine game", false)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func numbered(name string, i int) string {
+	if i == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s_%d", name, i+1)
+}
+
+// FindDir finds the directory rel (slash-separated, compared without regard
+// to case) below root and returns its actual name.
+func FindDir(root, rel string) (string, bool) {
+	var found []string
+	dir := root
+	for _, part := range strings.Split(rel, "/") {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", false
+		}
+
+		var match string
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			if e.Name() == part {
+				match = part
+				break
+			}
+			// the first one by name wins if several only differ in case
+			if match == "" && strings.EqualFold(e.Name(), part) {
+				match = e.Name()
+			}
+		}
+		if match == "" {
+			return "", false
+		}
+
+		found = append(found, match)
+		dir = filepath.Join(dir, match)
+	}
+
+	return strings.Join(found, "/"), true
+}
+
+// ProposeTargets returns the mod folders of a game install that aren't
+// targets yet. Games with default targets get them from `games refresh`
+// instead.
+func ProposeTargets(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall) ([]ModFolder, error) {
+	templates, err := TargetTemplates(gi.StoreID, gi.StoreGameID, gi.CanonicalGameID)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) > 0 {
+		return nil, fmt.Errorf("%s has default targets: they are created by `modctl games refresh` (see the target_templates config option)", gi.DisplayName)
+	}
+
+	folders, err := DetectModFolders(gi.InstallRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := q.ListTargetsForGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list targets: %w", err)
+	}
+	names := map[string]bool{}
+	roots := map[string]bool{}
+	for _, t := range targets {
+		names[t.Name] = true
+		if root, err := TargetRoot(gi, t); err == nil {
+			roots[filepath.Clean(root)] = true
+		}
+	}
+
+	var out []ModFolder
+	for _, f := range folders {
+		root := filepath.Join(gi.InstallRoot, filepath.FromSlash(f.RelPath))
+		if names[f.Target] || roots[root] {
+			continue
+		}
+		out = append(out, f)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+
+	return out, nil
+}
+
+// CreateDetectedTarget creates a target for a mod folder (see
+// ProposeTargets). Like the default targets it's relative to the install
+// root, so it follows the game when it moves.
+func CreateDetectedTarget(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall, f ModFolder) error {
+	rel, err := plan.NormalizeRelPath(f.RelPath)
+	if err != nil {
+		return fmt.Errorf("target %s: %w", f.Target, err)
+	}
+	if !targetNamePattern.MatchString(f.Target) || f.Target == plan.DefaultTarget {
+		return fmt.Errorf("invalid target name %q", f.Target)
+	}
+
+	meta, err := json.Marshal(map[string]string{"template": rel, "detected": f.Reason})
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.GetTargetByName(ctx, dbq.GetTargetByNameParams{
+		GameInstallID: gi.ID,
+		Name:          f.Target,
+	}); err == nil {
+		return fmt.Errorf("target %s already exists", f.Target)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("get target %s: %w", f.Target, err)
+	}
+
+	if err := q.UpsertDiscoveredTarget(ctx, dbq.UpsertDiscoveredTargetParams{
+		GameInstallID: gi.ID,
+		Name:          f.Target,
+		RootPath:      filepath.Join(gi.InstallRoot, filepath.FromSlash(rel)),
+		Metadata:      sql.NullString{String: string(meta), Valid: true},
+	}); err != nil {
+		return fmt.Errorf("create target %s: %w", f.Target, err)
+	}
+
+	return nil
+}
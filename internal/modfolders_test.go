@@ -0,0 +1,79 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectModFolders(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, d := range []string{
+		"mods",
+		"BepInEx/plugins",
+		"BepInEx/config",
+		"MelonLoader",
+		"tf/custom",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, d), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "tf", "gameinfo.txt"), nil, 0o644))
+	// not a directory
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Plugins"), nil, 0o644))
+
+	got, err := DetectModFolders(root)
+	require.NoError(t, err)
+	assert.Equal(t, []ModFolder{
+		{Target: "mods", RelPath: "mods", Reason: "a mods directory"},
+		{Target: "bepinex_plugins", RelPath: "BepInEx/plugins", Reason: "BepInEx is installed"},
+		{Target: "melonloader_plugins", RelPath: "Plugins", Reason: "MelonLoader is installed", Missing: true},
+		{Target: "custom", RelPath: "tf/custom", Reason: "source engine game"},
+	}, got)
+
+	got, err = DetectModFolders(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestFindDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "BepInEx", "Plugins"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bepinex"), 0o755))
+
+	rel, ok := FindDir(root, "BepInEx/plugins")
+	assert.True(t, ok)
+	assert.Equal(t, "BepInEx/Plugins", rel)
+
+	// an exact match wins
+	rel, ok = FindDir(root, "bepinex")
+	assert.True(t, ok)
+	assert.Equal(t, "bepinex", rel)
+
+	_, ok = FindDir(root, "BepInEx/patchers")
+	assert.False(t, ok)
+}
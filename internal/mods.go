@@ -0,0 +1,100 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// archive hashes can be abbreviated like git commits
+var shaPrefixPattern = regexp.MustCompile(`^[0-9a-fA-F]{8,64}$`)
+
+// ResolveModPageArg resolves a mod page of the game install from a mod page
+// id, a mod name (case-insensitive), or (a prefix of at least eight
+// characters of) the sha256 of one of its archives.
+func ResolveModPageArg(ctx context.Context, q *dbq.Queries, gameInstallID int64, arg string) (dbq.ModPage, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return dbq.ModPage{}, errors.New("empty mod selector")
+	}
+
+	var ids []int64
+
+	if id, ok := ParseInt64(arg); ok {
+		if _, err := q.GetModPageForGame(ctx, dbq.GetModPageForGameParams{
+			ID:            id,
+			GameInstallID: gameInstallID,
+		}); err == nil {
+			ids = append(ids, id)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return dbq.ModPage{}, fmt.Errorf("get mod page: %w", err)
+		}
+	}
+
+	if len(ids) == 0 {
+		rows, err := q.ListModPagesByNameForGame(ctx, dbq.ListModPagesByNameForGameParams{
+			GameInstallID: gameInstallID,
+			Name:          arg,
+		})
+		if err != nil {
+			return dbq.ModPage{}, fmt.Errorf("lookup mod page by name: %w", err)
+		}
+		for _, r := range rows {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	if len(ids) == 0 && shaPrefixPattern.MatchString(arg) {
+		rows, err := q.ListModPagesByArchivePrefix(ctx, dbq.ListModPagesByArchivePrefixParams{
+			GameInstallID: gameInstallID,
+			Pattern:       GlobPrefixPattern(strings.ToLower(arg)),
+		})
+		if err != nil {
+			return dbq.ModPage{}, fmt.Errorf("lookup mod page by archive: %w", err)
+		}
+		for _, r := range rows {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return dbq.ModPage{}, fmt.Errorf("no mod matching %q for this game", arg)
+	case 1:
+		p, err := q.GetModPageDetails(ctx, ids[0])
+		if err != nil {
+			return dbq.ModPage{}, fmt.Errorf("get mod page: %w", err)
+		}
+		return p, nil
+	default:
+		strs := make([]string, 0, len(ids))
+		for _, id := range ids {
+			strs = append(strs, fmt.Sprintf("%d", id))
+		}
+		return dbq.ModPage{}, fmt.Errorf("%q matches multiple mods (ids %s); use the id instead",
+			arg, strings.Join(strs, ", "))
+	}
+}
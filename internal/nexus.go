@@ -0,0 +1,157 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/mfinelli/modctl/internal/secrets"
+	"github.com/spf13/viper"
+)
+
+// NewNexusClient returns a Nexus API client using the API key from the
+// configured secrets provider and the on-disk HTTP cache (unless disabled
+// with http_cache = false). The client's rate limiter starts from the last
+// limits recorded in the database and records new ones as they come in;
+// downloads are paced by the configured limits (see NewDownloadManager).
+func NewNexusClient(ctx context.Context, q *dbq.Queries, appVersion string) (*nexus.Client, error) {
+	p, err := secrets.New(viper.GetString("secrets_provider"))
+	if err != nil {
+		return nil, err
+	}
+
+	key, _, err := secrets.Lookup(p, secrets.NexusAPIKey)
+	if err != nil {
+		if errors.Is(err, secrets.ErrNotFound) {
+			return nil, errors.New("no nexus api key configured; run `modctl auth login`")
+		}
+		return nil, err
+	}
+
+	cacheDir := ""
+	if viper.GetBool("http_cache") {
+		cacheDir = viper.GetString("http_cache_dir")
+	}
+
+	last, err := LoadNexusRateLimits(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	c := nexus.NewClient(key, appVersion, cacheDir)
+	if u := viper.GetString("nexus_api_url"); u != "" {
+		c.BaseURL = u
+	}
+	c.Limiter = nexus.NewLimiter(
+		viper.GetInt64("nexus_rate_limit_reserve"),
+		viper.GetDuration("nexus_rate_limit_max_wait"),
+		last,
+	)
+	c.Limiter.OnUpdate = func(rl nexus.RateLimits) {
+		// best-effort: losing an update just means that we start from
+		// slightly older numbers next time
+		_ = SaveNexusRateLimits(context.Background(), q, rl)
+	}
+
+	if c.Downloads, err = NewDownloadManager(ctx, q); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// LoadNexusRateLimits returns the last recorded rate limits, or nil if we've
+// never seen any.
+func LoadNexusRateLimits(ctx context.Context, q *dbq.Queries) (*nexus.RateLimits, error) {
+	row, err := q.GetAPIRateLimits(ctx, "nexus")
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &nexus.RateLimits{
+		HourlyLimit:     row.HourlyLimit.Int64,
+		HourlyRemaining: row.HourlyRemaining.Int64,
+		HourlyReset:     parseDBTime(row.HourlyResetAt),
+		DailyLimit:      row.DailyLimit.Int64,
+		DailyRemaining:  row.DailyRemaining.Int64,
+		DailyReset:      parseDBTime(row.DailyResetAt),
+		ObservedAt:      parseDBTime(sql.NullString{String: row.ObservedAt, Valid: true}),
+	}, nil
+}
+
+func SaveNexusRateLimits(ctx context.Context, q *dbq.Queries, rl nexus.RateLimits) error {
+	return q.UpsertAPIRateLimits(ctx, dbq.UpsertAPIRateLimitsParams{
+		Api:             "nexus",
+		HourlyLimit:     sql.NullInt64{Int64: rl.HourlyLimit, Valid: true},
+		HourlyRemaining: sql.NullInt64{Int64: rl.HourlyRemaining, Valid: true},
+		HourlyResetAt:   formatDBTime(rl.HourlyReset),
+		DailyLimit:      sql.NullInt64{Int64: rl.DailyLimit, Valid: true},
+		DailyRemaining:  sql.NullInt64{Int64: rl.DailyRemaining, Valid: true},
+		DailyResetAt:    formatDBTime(rl.DailyReset),
+		ObservedAt:      rl.ObservedAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+	})
+}
+
+// SaveNexusFileUpdates records which files of a mod replaced which (see
+// nexus.LatestFile).
+func SaveNexusFileUpdates(ctx context.Context, q *dbq.Queries, gameDomain string, modID int64, updates []nexus.FileUpdate) error {
+	for _, u := range updates {
+		var uploadedAt time.Time
+		if u.UploadedTimestamp > 0 {
+			uploadedAt = time.Unix(u.UploadedTimestamp, 0)
+		}
+		if err := q.UpsertNexusFileUpdate(ctx, dbq.UpsertNexusFileUpdateParams{
+			NexusGameDomain: gameDomain,
+			NexusModID:      modID,
+			OldFileID:       u.OldFileID,
+			NewFileID:       u.NewFileID,
+			UploadedAt:      formatDBTime(uploadedAt),
+		}); err != nil {
+			return fmt.Errorf("record nexus file update %d -> %d: %w", u.OldFileID, u.NewFileID, err)
+		}
+	}
+	return nil
+}
+
+func parseDBTime(ns sql.NullString) time.Time {
+	if !ns.Valid {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02T15:04:05.000Z", ns.String)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func formatDBTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{String: t.UTC().Format("2006-01-02T15:04:05.000Z"), Valid: true}
+}
@@ -0,0 +1,332 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/download"
+	"github.com/mfinelli/modctl/internal/httpcache"
+	"github.com/mfinelli/modctl/internal/offline"
+)
+
+const DefaultBaseURL = "https://api.nexusmods.com"
+
+// Client is a minimal client for the Nexus Mods public API (v1).
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	AppVersion string
+	HTTP       *http.Client
+
+	// Limiter, if set, paces requests according to the api rate limits
+	Limiter *Limiter
+
+	// Downloads, if set, paces downloads (how many run at the same time
+	// and how fast they're read, see download.Manager)
+	Downloads *download.Manager
+}
+
+// APIError is returned for non-2xx responses from the API.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("nexus api: %d %s: %s",
+			e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("nexus api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// NewClient returns a client for the given API key. If cacheDir is not empty,
+// GET responses are cached on disk (see CacheTTL).
+func NewClient(apiKey, appVersion, cacheDir string) *Client {
+	var rt http.RoundTripper = http.DefaultTransport
+	if cacheDir != "" {
+		rt = &httpcache.Transport{Dir: cacheDir, TTL: CacheTTL}
+	}
+
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		APIKey:     apiKey,
+		AppVersion: appVersion,
+		HTTP:       &http.Client{Transport: rt, Timeout: 30 * time.Second},
+	}
+}
+
+// CacheTTL decides how long an API response stays fresh in the cache.
+// Mod metadata changes rarely but we still want to notice new uploads within
+// a reasonable time; user/account data is never cached.
+func CacheTTL(req *http.Request) time.Duration {
+	p := req.URL.Path
+	switch {
+	case strings.HasPrefix(p, "/v1/users/"):
+		return 0
+	case strings.HasSuffix(p, "/download_link.json"):
+		// the links expire
+		return 0
+	case strings.HasSuffix(p, "/changelogs.json"):
+		return 6 * time.Hour
+	case strings.HasSuffix(p, "/files.json"):
+		return time.Hour
+	case strings.Contains(p, "/mods/"):
+		return time.Hour
+	default:
+		return 15 * time.Minute
+	}
+}
+
+// get performs a GET request against the API and decodes the JSON response
+// into out.
+func (c *Client) get(ctx context.Context, path string, out any) (*http.Response, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("no nexus api key configured; run `modctl auth login`")
+	}
+
+	u, err := url.JoinPath(c.BaseURL, path)
+	if err != nil {
+		return nil, fmt.Errorf("build url: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Apikey", c.APIKey)
+	req.Header.Set("Application-Name", "modctl")
+	req.Header.Set("Application-Version", c.AppVersion)
+	req.Header.Set("User-Agent", "modctl/"+c.AppVersion)
+
+	// Offline (or if we're out of requests) we can still answer from the
+	// cache (even with a stale response)
+	isOffline := offline.Enabled()
+	var limitErr *RateLimitError
+	if isOffline {
+		if _, ok := c.HTTP.Transport.(*httpcache.Transport); !ok {
+			return nil, offline.Check("nexus api request (the response cache is off)")
+		}
+		req.Header.Set("Cache-Control", "only-if-cached")
+	} else if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			if !errors.As(err, &limitErr) {
+				return nil, err
+			}
+			req.Header.Set("Cache-Control", "only-if-cached")
+		}
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("nexus api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if isOffline && resp.StatusCode == http.StatusGatewayTimeout {
+		return resp, offline.Check("nexus api request (not cached)")
+	}
+	if limitErr != nil && resp.StatusCode == http.StatusGatewayTimeout {
+		return resp, limitErr
+	}
+
+	if c.Limiter != nil && resp.Header.Get(httpcache.FromCacheHeader) == "" {
+		if rl, ok := ParseRateLimits(resp.Header, time.Now()); ok {
+			c.Limiter.Update(rl)
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rlErr := &RateLimitError{}
+		if c.Limiter != nil {
+			if rl, ok := c.Limiter.Limits(); ok {
+				rlErr.ResetAt = rl.NextReset(time.Now())
+			}
+		}
+		return resp, rlErr
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var body struct {
+			Message string `json:"message"`
+		}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(b, &body) == nil {
+			apiErr.Message = body.Message
+		}
+		return resp, apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("decode nexus api response: %w", err)
+	}
+
+	return resp, nil
+}
+
+type User struct {
+	UserID      int64  `json:"user_id"`
+	Name        string `json:"name"`
+	IsPremium   bool   `json:"is_premium"`
+	IsSupporter bool   `json:"is_supporter"`
+}
+
+// ValidateKey checks the API key and returns the user that it belongs to.
+func (c *Client) ValidateKey(ctx context.Context) (User, error) {
+	var u User
+	_, err := c.get(ctx, "/v1/users/validate.json", &u)
+	return u, err
+}
+
+type Mod struct {
+	ModID            int64  `json:"mod_id"`
+	GameDomain       string `json:"domain_name"`
+	Name             string `json:"name"`
+	Summary          string `json:"summary"`
+	Version          string `json:"version"`
+	Author           string `json:"author"`
+	Available        bool   `json:"available"`
+	Status           string `json:"status"`
+	UpdatedTimestamp int64  `json:"updated_timestamp"`
+}
+
+// GetMod returns the metadata of a mod page.
+func (c *Client) GetMod(ctx context.Context, gameDomain string, modID int64) (Mod, error) {
+	var m Mod
+	_, err := c.get(ctx, fmt.Sprintf("/v1/games/%s/mods/%d.json",
+		url.PathEscape(gameDomain), modID), &m)
+	return m, err
+}
+
+// GetChangelogs returns the changelogs of a mod, keyed by version.
+func (c *Client) GetChangelogs(ctx context.Context, gameDomain string, modID int64) (map[string][]string, error) {
+	logs := map[string][]string{}
+	_, err := c.get(ctx, fmt.Sprintf("/v1/games/%s/mods/%d/changelogs.json",
+		url.PathEscape(gameDomain), modID), &logs)
+	return logs, err
+}
+
+type File struct {
+	FileID            int64  `json:"file_id"`
+	Name              string `json:"name"`
+	Version           string `json:"version"`
+	CategoryName      string `json:"category_name"`
+	FileName          string `json:"file_name"`
+	SizeInBytes       int64  `json:"size_in_bytes"`
+	UploadedTimestamp int64  `json:"uploaded_timestamp"`
+}
+
+// FileUpdate records that a file of a mod was replaced by a newer upload.
+type FileUpdate struct {
+	OldFileID         int64  `json:"old_file_id"`
+	NewFileID         int64  `json:"new_file_id"`
+	OldFileName       string `json:"old_file_name"`
+	NewFileName       string `json:"new_file_name"`
+	UploadedTimestamp int64  `json:"uploaded_timestamp"`
+}
+
+type FileList struct {
+	Files   []File       `json:"files"`
+	Updates []FileUpdate `json:"file_updates"`
+}
+
+// GetFileList returns the files of a mod, including old versions that are
+// still available, and which of them replaced which.
+func (c *Client) GetFileList(ctx context.Context, gameDomain string, modID int64) (FileList, error) {
+	var out FileList
+	_, err := c.get(ctx, fmt.Sprintf("/v1/games/%s/mods/%d/files.json",
+		url.PathEscape(gameDomain), modID), &out)
+	return out, err
+}
+
+// GetFiles returns the files of a mod, including old versions that are
+// still available.
+func (c *Client) GetFiles(ctx context.Context, gameDomain string, modID int64) ([]File, error) {
+	l, err := c.GetFileList(ctx, gameDomain, modID)
+	return l.Files, err
+}
+
+type DownloadLink struct {
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	URI       string `json:"URI"`
+}
+
+// GetDownloadLinks returns where a file can be downloaded from (one link per
+// CDN location). The API only hands them out like this to premium members.
+func (c *Client) GetDownloadLinks(ctx context.Context, gameDomain string, modID, fileID int64) ([]DownloadLink, error) {
+	var links []DownloadLink
+	_, err := c.get(ctx, fmt.Sprintf("/v1/games/%s/mods/%d/files/%d/download_link.json",
+		url.PathEscape(gameDomain), modID, fileID), &links)
+	return links, err
+}
+
+// Download writes the file behind a download link to w. Unlike the API
+// requests it doesn't go through the cache and isn't limited to 30 seconds
+// (cancel ctx instead). With Downloads it waits for its turn first; offline
+// it fails with offline.ErrOffline.
+func (c *Client) Download(ctx context.Context, uri string, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("User-Agent", "modctl/"+c.AppVersion)
+
+	if err := offline.Check("download"); err != nil {
+		return 0, err
+	}
+	if c.Downloads != nil {
+		done, err := c.Downloads.Start(ctx, req.URL.Hostname())
+		if err != nil {
+			return 0, fmt.Errorf("download: %w", err)
+		}
+		defer done()
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("download: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var r io.Reader = resp.Body
+	if c.Downloads != nil {
+		r = c.Downloads.Reader(ctx, req.URL.Hostname(), r)
+	}
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return n, fmt.Errorf("download: %w", err)
+	}
+	return n, nil
+}
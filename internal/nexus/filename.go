@@ -0,0 +1,155 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownloadName is the information that we can recover from the filename that
+// Nexus Mods gives to a downloaded archive.
+type DownloadName struct {
+	ModName    string
+	ModID      int64
+	Version    string
+	UploadedAt time.Time
+}
+
+// browsers append " (1)", " (2)", etc. when downloading the same file twice
+var duplicateSuffix = regexp.MustCompile(`\s+\(\d+\)$`)
+
+// <name>-<mod_id>-<version parts>-<unix timestamp>
+var downloadNamePattern = regexp.MustCompile(
+	`^(.+?)-(\d+)-([0-9A-Za-z]+(?:-[0-9A-Za-z]+)*)-(\d{9,10})$`)
+
+// ParseDownloadFilename extracts the mod id, version, and upload time from a
+// Nexus Mods download filename.
+//
+// Nexus names downloads like:
+//
+//	SomeMod-1234-2-0-1-1699999999.7z
+//
+// where 1234 is the mod id, 2-0-1 is the version with the dots replaced by
+// dashes, and 1699999999 is the unix timestamp of when the file was uploaded.
+// N.B. the filename does _not_ contain the nexus file id.
+//
+// This is a best-effort guess: mod names can themselves contain dashes and
+// numbers so callers should cross-check the mod id when they have one from
+// another source. It returns false if the name doesn't look like a Nexus
+// download.
+func ParseDownloadFilename(name string) (DownloadName, bool) {
+	name = strings.TrimSpace(filepath.Base(name))
+
+	ext := filepath.Ext(name)
+	if ext == "" {
+		return DownloadName{}, false
+	}
+	name = strings.TrimSuffix(name, ext)
+	if strings.EqualFold(filepath.Ext(name), ".tar") {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	name = duplicateSuffix.ReplaceAllString(name, "")
+
+	m := downloadNamePattern.FindStringSubmatch(name)
+	if m == nil {
+		return DownloadName{}, false
+	}
+
+	modID, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil || modID <= 0 {
+		return DownloadName{}, false
+	}
+
+	ts, err := strconv.ParseInt(m[4], 10, 64)
+	if err != nil {
+		return DownloadName{}, false
+	}
+
+	// Nexus has been around since 2001; anything outside of that range is
+	// more likely to be part of a version or the mod name
+	uploaded := time.Unix(ts, 0).UTC()
+	if uploaded.Year() < 2001 || uploaded.After(time.Now().Add(24*time.Hour)) {
+		return DownloadName{}, false
+	}
+
+	return DownloadName{
+		ModName:    strings.TrimSpace(m[1]),
+		ModID:      modID,
+		Version:    strings.ReplaceAll(m[3], "-", "."),
+		UploadedAt: uploaded,
+	}, true
+}
+
+// MatchDownloadFile finds the file of a mod (see Client.GetFileList) that an
+// archive with the given filename was downloaded as, to recover the file id
+// that the filename doesn't contain. The filename has to be the file_name of
+// the file (ignoring case and the suffix that browsers add to duplicates) or,
+// for a Nexus download name, its upload timestamp has to be the one of exactly
+// one file.
+func MatchDownloadFile(files []File, name string) (File, bool) {
+	base := downloadBase(name)
+	if base == "" {
+		return File{}, false
+	}
+
+	for _, f := range files {
+		if strings.EqualFold(downloadBase(f.FileName), base) {
+			return f, true
+		}
+	}
+
+	dn, ok := ParseDownloadFilename(name)
+	if !ok {
+		return File{}, false
+	}
+
+	var match File
+	n := 0
+	for _, f := range files {
+		if f.UploadedTimestamp == dn.UploadedAt.Unix() {
+			match = f
+			n++
+		}
+	}
+	if n != 1 {
+		return File{}, false
+	}
+	return match, true
+}
+
+// downloadBase returns the filename without the directory and the duplicate
+// suffix (e.g., "SomeMod (1).7z" is "SomeMod.7z").
+func downloadBase(name string) string {
+	name = strings.TrimSpace(filepath.Base(name))
+	if name == "." || name == string(filepath.Separator) {
+		return ""
+	}
+
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if strings.EqualFold(filepath.Ext(stem), ".tar") {
+		ext = filepath.Ext(stem) + ext
+		stem = strings.TrimSuffix(stem, filepath.Ext(stem))
+	}
+	return duplicateSuffix.ReplaceAllString(stem, "") + ext
+}
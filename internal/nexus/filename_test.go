@@ -0,0 +1,177 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDownloadFilename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		wantOK      bool
+		wantName    string
+		wantModID   int64
+		wantVersion string
+		wantUpload  time.Time
+	}{
+		{
+			name:        "simple",
+			input:       "SomeMod-1234-2-0-1-1699999999.7z",
+			wantOK:      true,
+			wantName:    "SomeMod",
+			wantModID:   1234,
+			wantVersion: "2.0.1",
+			wantUpload:  time.Unix(1699999999, 0).UTC(),
+		},
+		{
+			name:        "spaces in name and letters in version",
+			input:       "Unofficial Skyrim Special Edition Patch-266-4-2-5a-1616426767.7z",
+			wantOK:      true,
+			wantName:    "Unofficial Skyrim Special Edition Patch",
+			wantModID:   266,
+			wantVersion: "4.2.5a",
+			wantUpload:  time.Unix(1616426767, 0).UTC(),
+		},
+		{
+			name:        "single version component",
+			input:       "Cool Mod-42-3-1600000000.zip",
+			wantOK:      true,
+			wantName:    "Cool Mod",
+			wantModID:   42,
+			wantVersion: "3",
+			wantUpload:  time.Unix(1600000000, 0).UTC(),
+		},
+		{
+			name:        "browser duplicate suffix",
+			input:       "SomeMod-1234-1-0-1699999999 (1).7z",
+			wantOK:      true,
+			wantName:    "SomeMod",
+			wantModID:   1234,
+			wantVersion: "1.0",
+			wantUpload:  time.Unix(1699999999, 0).UTC(),
+		},
+		{
+			name:        "tar.gz extension",
+			input:       "SomeMod-1234-1-0-1699999999.tar.gz",
+			wantOK:      true,
+			wantName:    "SomeMod",
+			wantModID:   1234,
+			wantVersion: "1.0",
+			wantUpload:  time.Unix(1699999999, 0).UTC(),
+		},
+		{
+			name:        "full path",
+			input:       "/home/user/Downloads/SomeMod-1234-1-0-1699999999.rar",
+			wantOK:      true,
+			wantName:    "SomeMod",
+			wantModID:   1234,
+			wantVersion: "1.0",
+			wantUpload:  time.Unix(1699999999, 0).UTC(),
+		},
+		{
+			name:   "no extension",
+			input:  "SomeMod-1234-1-0-1699999999",
+			wantOK: false,
+		},
+		{
+			name:   "plain archive name",
+			input:  "my-cool-mod.zip",
+			wantOK: false,
+		},
+		{
+			name:   "missing version",
+			input:  "SomeMod-1234-1699999999.7z",
+			wantOK: false,
+		},
+		{
+			name:   "timestamp too short",
+			input:  "SomeMod-1234-1-0-12345.7z",
+			wantOK: false,
+		},
+		{
+			name:   "timestamp in the future",
+			input:  "SomeMod-1234-1-0-9999999999.7z",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ParseDownloadFilename(tt.input)
+
+			assert.Equal(t, tt.wantOK, ok)
+
+			if tt.wantOK {
+				assert.Equal(t, tt.wantName, got.ModName)
+				assert.Equal(t, tt.wantModID, got.ModID)
+				assert.Equal(t, tt.wantVersion, got.Version)
+				assert.Equal(t, tt.wantUpload, got.UploadedAt)
+			}
+		})
+	}
+}
+
+func TestMatchDownloadFile(t *testing.T) {
+	t.Parallel()
+
+	files := []File{
+		{FileID: 1, FileName: "SomeMod-1234-1-0-1600000000.7z", UploadedTimestamp: 1600000000},
+		{FileID: 2, FileName: "SomeMod-1234-2-0-1-1699999999.7z", UploadedTimestamp: 1699999999},
+		{FileID: 3, FileName: "SomeMod Optional-1234-2-0-1-1699999999.7z", UploadedTimestamp: 1699999999},
+		{FileID: 4, FileName: "Textures.tar.gz", UploadedTimestamp: 1650000000},
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		wantID int64
+	}{
+		{name: "file name", input: "SomeMod-1234-1-0-1600000000.7z", wantID: 1},
+		{name: "directory and case", input: "/home/me/Downloads/somemod-1234-1-0-1600000000.7Z", wantID: 1},
+		{name: "duplicate download", input: "SomeMod-1234-1-0-1600000000 (1).7z", wantID: 1},
+		{name: "same upload time", input: "SomeMod-1234-2-0-1-1699999999.7z", wantID: 2},
+		{name: "renamed", input: "Some Mod-1234-1-0-1600000000.7z", wantID: 1},
+		{name: "ambiguous upload time", input: "Renamed-1234-2-0-1-1699999999.7z"},
+		{name: "tar suffix", input: "Textures (2).tar.gz", wantID: 4},
+		{name: "unknown", input: "Other-99-1-0-1500000000.7z"},
+		{name: "not a download name", input: "mod.zip"},
+		{name: "empty", input: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, ok := MatchDownloadFile(files, tt.input)
+			assert.Equal(t, tt.wantID != 0, ok)
+			assert.Equal(t, tt.wantID, f.FileID)
+		})
+	}
+}
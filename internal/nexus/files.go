@@ -0,0 +1,141 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"sort"
+	"strings"
+)
+
+// fileCategories are the categories of the files of a mod in the order that
+// the files tab of the mod page shows them.
+var fileCategories = []struct {
+	name  string
+	label string
+}{
+	{"main", "Main files"},
+	{"update", "Updates"},
+	{"optional", "Optional files"},
+	{"miscellaneous", "Miscellaneous files"},
+	{"old_version", "Old files"},
+	{"archived", "Archived files"},
+}
+
+// Category returns the category of the file the way that modctl records it
+// (mod_files.category), e.g., "main" or "old_version"; "" if Nexus didn't say.
+func (f File) Category() string {
+	return strings.ToLower(strings.TrimSpace(f.CategoryName))
+}
+
+// FileGroup is the files of a mod in one category.
+type FileGroup struct {
+	Category string
+	Label    string
+	Files    []File
+}
+
+// GroupFiles groups the files of a mod by category, in the order of the files
+// tab (categories that modctl doesn't know come last), with the newest upload
+// first in every group.
+func GroupFiles(files []File) []FileGroup {
+	order := map[string]int{}
+	for i, c := range fileCategories {
+		order[c.name] = i
+	}
+
+	byCategory := map[string][]File{}
+	var categories []string
+	for _, f := range files {
+		c := f.Category()
+		if _, ok := byCategory[c]; !ok {
+			categories = append(categories, c)
+		}
+		byCategory[c] = append(byCategory[c], f)
+	}
+
+	rank := func(c string) int {
+		if i, ok := order[c]; ok {
+			return i
+		}
+		return len(fileCategories)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		ri, rj := rank(categories[i]), rank(categories[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return categories[i] < categories[j]
+	})
+
+	out := make([]FileGroup, 0, len(categories))
+	for _, c := range categories {
+		fs := byCategory[c]
+		sort.SliceStable(fs, func(i, j int) bool {
+			return fs[i].UploadedTimestamp > fs[j].UploadedTimestamp
+		})
+		out = append(out, FileGroup{Category: c, Label: categoryLabel(c), Files: fs})
+	}
+
+	return out
+}
+
+// IsCurrent reports whether the file is in a category of files that are
+// meant to be downloaded (i.e., not an old version or archived).
+func (f File) IsCurrent() bool {
+	switch f.Category() {
+	case "main", "update", "optional", "miscellaneous":
+		return true
+	default:
+		return false
+	}
+}
+
+// LatestFile follows the updates from a file to the newest file that
+// (transitively) replaced it; it returns fileID if nothing did. If a file was
+// replaced more than once the newest upload wins.
+func LatestFile(updates []FileUpdate, fileID int64) int64 {
+	next := map[int64]FileUpdate{}
+	for _, u := range updates {
+		if cur, ok := next[u.OldFileID]; !ok || u.UploadedTimestamp > cur.UploadedTimestamp {
+			next[u.OldFileID] = u
+		}
+	}
+
+	seen := map[int64]bool{fileID: true}
+	for {
+		u, ok := next[fileID]
+		if !ok || seen[u.NewFileID] {
+			return fileID
+		}
+		fileID = u.NewFileID
+		seen[fileID] = true
+	}
+}
+
+func categoryLabel(c string) string {
+	for _, fc := range fileCategories {
+		if fc.name == c {
+			return fc.label
+		}
+	}
+	if c == "" {
+		return "Uncategorized files"
+	}
+	return strings.ReplaceAll(strings.ToUpper(c[:1])+c[1:], "_", " ") + " files"
+}
@@ -0,0 +1,81 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupFiles(t *testing.T) {
+	t.Parallel()
+
+	files := []File{
+		{FileID: 1, CategoryName: "OLD_VERSION", UploadedTimestamp: 100},
+		{FileID: 2, CategoryName: "MAIN", UploadedTimestamp: 200},
+		{FileID: 3, CategoryName: "OPTIONAL", UploadedTimestamp: 150},
+		{FileID: 4, CategoryName: "MAIN", UploadedTimestamp: 300},
+		{FileID: 5, CategoryName: "", UploadedTimestamp: 50},
+		{FileID: 6, CategoryName: "DELETED", UploadedTimestamp: 60},
+		{FileID: 7, CategoryName: "MISCELLANEOUS", UploadedTimestamp: 70},
+	}
+
+	groups := GroupFiles(files)
+
+	var got []string
+	ids := map[string][]int64{}
+	for _, g := range groups {
+		got = append(got, g.Category)
+		for _, f := range g.Files {
+			ids[g.Category] = append(ids[g.Category], f.FileID)
+		}
+	}
+
+	assert.Equal(t, []string{"main", "optional", "miscellaneous", "old_version", "", "deleted"}, got)
+	assert.Equal(t, []int64{4, 2}, ids["main"], "newest first")
+	assert.Equal(t, "Main files", groups[0].Label)
+	assert.Equal(t, "Uncategorized files", groups[4].Label)
+	assert.Equal(t, "Deleted files", groups[5].Label)
+}
+
+func TestLatestFile(t *testing.T) {
+	t.Parallel()
+
+	updates := []FileUpdate{
+		{OldFileID: 1, NewFileID: 2, UploadedTimestamp: 100},
+		{OldFileID: 2, NewFileID: 3, UploadedTimestamp: 200},
+		{OldFileID: 2, NewFileID: 4, UploadedTimestamp: 300},
+		{OldFileID: 10, NewFileID: 11, UploadedTimestamp: 100},
+		{OldFileID: 11, NewFileID: 10, UploadedTimestamp: 200},
+	}
+
+	assert.Equal(t, int64(4), LatestFile(updates, 1))
+	assert.Equal(t, int64(4), LatestFile(updates, 2))
+	assert.Equal(t, int64(4), LatestFile(updates, 4), "not replaced")
+	assert.Equal(t, int64(99), LatestFile(updates, 99), "unknown file")
+	assert.Equal(t, int64(11), LatestFile(updates, 10), "cycles end")
+	assert.Equal(t, int64(5), LatestFile(nil, 5))
+}
+
+func TestGroupFilesEmpty(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, GroupFiles(nil))
+}
@@ -79,3 +79,15 @@ func ParseModURL(raw string) (ModRef, error) {
 
 	return ModRef{}, fmt.Errorf("invalid nexus url: missing /mods/<id> in path %q", u.Path)
 }
+
+// FilesURL returns the files tab of the mod page, where its archives can be
+// downloaded.
+func (r ModRef) FilesURL() string {
+	return fmt.Sprintf("https://www.nexusmods.com/%s/mods/%d?tab=files", r.GameDomain, r.ModID)
+}
+
+// FileURL returns the files tab of the mod page with one of its files
+// selected.
+func (r ModRef) FileURL(fileID int64) string {
+	return fmt.Sprintf("%s&file_id=%d", r.FilesURL(), fileID)
+}
@@ -0,0 +1,211 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimits is the rate limit state reported by the Nexus API in the
+// X-RL-* response headers.
+//
+// Nexus allows a number of requests per day; once those are used up a
+// (smaller) number of requests per hour is still allowed. So a request can be
+// made as long as either of the two windows has requests remaining.
+type RateLimits struct {
+	HourlyLimit     int64
+	HourlyRemaining int64
+	HourlyReset     time.Time
+
+	DailyLimit     int64
+	DailyRemaining int64
+	DailyReset     time.Time
+
+	ObservedAt time.Time
+}
+
+// RateLimitError is returned when a request can't be made (or was rejected
+// by the API) because the rate limits have been used up.
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.ResetAt.IsZero() {
+		return "nexus api rate limit reached"
+	}
+	return fmt.Sprintf("nexus api rate limit reached; try again after %s",
+		e.ResetAt.Local().Format("2006-01-02 15:04:05"))
+}
+
+// ParseRateLimits extracts the rate limits from the response headers. It
+// returns false if the headers are missing.
+func ParseRateLimits(h http.Header, observedAt time.Time) (RateLimits, bool) {
+	var rl RateLimits
+	var ok bool
+
+	parseInt := func(name string, dst *int64) {
+		if n, err := strconv.ParseInt(h.Get(name), 10, 64); err == nil && n >= 0 {
+			*dst = n
+			ok = true
+		}
+	}
+	parseTime := func(name string, dst *time.Time) {
+		v := h.Get(name)
+		for _, layout := range []string{"2006-01-02 15:04:05 -0700", time.RFC3339} {
+			if t, err := time.Parse(layout, v); err == nil {
+				*dst = t.UTC()
+				return
+			}
+		}
+	}
+
+	parseInt("X-RL-Hourly-Limit", &rl.HourlyLimit)
+	parseInt("X-RL-Hourly-Remaining", &rl.HourlyRemaining)
+	parseTime("X-RL-Hourly-Reset", &rl.HourlyReset)
+	parseInt("X-RL-Daily-Limit", &rl.DailyLimit)
+	parseInt("X-RL-Daily-Remaining", &rl.DailyRemaining)
+	parseTime("X-RL-Daily-Reset", &rl.DailyReset)
+
+	rl.ObservedAt = observedAt.UTC()
+	return rl, ok
+}
+
+// Remaining returns how many requests can still be made at the given time.
+// Windows whose reset time has passed count as fully replenished.
+func (rl RateLimits) Remaining(now time.Time) int64 {
+	hourly := rl.HourlyRemaining
+	if !rl.HourlyReset.IsZero() && !now.Before(rl.HourlyReset) {
+		hourly = rl.HourlyLimit
+	}
+
+	daily := rl.DailyRemaining
+	if !rl.DailyReset.IsZero() && !now.Before(rl.DailyReset) {
+		daily = rl.DailyLimit
+	}
+
+	return max(hourly, daily)
+}
+
+// NextReset returns the earliest time after now at which more requests
+// become available (or the zero time if unknown).
+func (rl RateLimits) NextReset(now time.Time) time.Time {
+	var next time.Time
+	for _, t := range []time.Time{rl.HourlyReset, rl.DailyReset} {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// Limiter keeps track of the rate limits across requests and decides whether
+// a request can be made right away, should wait for the limits to reset, or
+// should be deferred (by returning a RateLimitError).
+type Limiter struct {
+	// Reserve is the number of requests that we always leave available so
+	// that one bulk operation can't lock the user out of the API
+	Reserve int64
+
+	// MaxWait is how long a request is allowed to wait for the limits to
+	// reset before giving up. Zero means never wait.
+	MaxWait time.Duration
+
+	// OnUpdate, if set, is called whenever new limits are observed (e.g.,
+	// to persist them)
+	OnUpdate func(RateLimits)
+
+	mu     sync.Mutex
+	limits RateLimits
+	known  bool
+}
+
+// NewLimiter returns a limiter; last are the last known limits (if any) so
+// that the state survives across runs.
+func NewLimiter(reserve int64, maxWait time.Duration, last *RateLimits) *Limiter {
+	l := &Limiter{Reserve: reserve, MaxWait: maxWait}
+	if last != nil {
+		l.limits = *last
+		l.known = true
+	}
+	return l
+}
+
+// Limits returns the last observed limits.
+func (l *Limiter) Limits() (RateLimits, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limits, l.known
+}
+
+// Update records the rate limits from a response.
+func (l *Limiter) Update(rl RateLimits) {
+	l.mu.Lock()
+	l.limits = rl
+	l.known = true
+	cb := l.OnUpdate
+	l.mu.Unlock()
+
+	if cb != nil {
+		cb(rl)
+	}
+}
+
+// Wait blocks until a request can be made. It returns a RateLimitError if
+// that would take longer than MaxWait.
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	rl, known := l.limits, l.known
+	l.mu.Unlock()
+
+	if !known {
+		return nil
+	}
+
+	now := time.Now()
+	if rl.Remaining(now) > l.Reserve {
+		return nil
+	}
+
+	// we don't know when the limits reset: let the api decide
+	reset := rl.NextReset(now)
+	if reset.IsZero() {
+		return nil
+	}
+
+	wait := reset.Sub(now)
+	if wait > l.MaxWait {
+		return &RateLimitError{ResetAt: reset}
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	t.Parallel()
+
+	observed := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   RateLimits
+		wantOK bool
+	}{
+		{
+			name: "all headers",
+			header: http.Header{
+				"X-Rl-Hourly-Limit":     {"500"},
+				"X-Rl-Hourly-Remaining": {"499"},
+				"X-Rl-Hourly-Reset":     {"2024-03-01 11:00:00 +0000"},
+				"X-Rl-Daily-Limit":      {"20000"},
+				"X-Rl-Daily-Remaining":  {"19876"},
+				"X-Rl-Daily-Reset":      {"2024-03-02 00:00:00 +0000"},
+			},
+			want: RateLimits{
+				HourlyLimit:     500,
+				HourlyRemaining: 499,
+				HourlyReset:     time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC),
+				DailyLimit:      20000,
+				DailyRemaining:  19876,
+				DailyReset:      time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+				ObservedAt:      observed,
+			},
+			wantOK: true,
+		},
+		{
+			name: "rfc3339 reset with offset",
+			header: http.Header{
+				"X-Rl-Hourly-Remaining": {"10"},
+				"X-Rl-Hourly-Reset":     {"2024-03-01T12:00:00+01:00"},
+			},
+			want: RateLimits{
+				HourlyRemaining: 10,
+				HourlyReset:     time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC),
+				ObservedAt:      observed,
+			},
+			wantOK: true,
+		},
+		{
+			name:   "no headers",
+			header: http.Header{},
+			wantOK: false,
+		},
+		{
+			name: "garbage values",
+			header: http.Header{
+				"X-Rl-Hourly-Remaining": {"lots"},
+				"X-Rl-Daily-Remaining":  {"-1"},
+			},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ParseRateLimits(tt.header, observed)
+
+			assert.Equal(t, tt.wantOK, ok)
+
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRateLimitsRemaining(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		limits    RateLimits
+		want      int64
+		wantReset time.Time
+	}{
+		{
+			name: "daily quota available",
+			limits: RateLimits{
+				HourlyLimit: 500, HourlyRemaining: 500, HourlyReset: now.Add(30 * time.Minute),
+				DailyLimit: 20000, DailyRemaining: 1234, DailyReset: now.Add(13 * time.Hour),
+			},
+			want:      1234,
+			wantReset: now.Add(30 * time.Minute),
+		},
+		{
+			name: "daily exhausted, hourly left",
+			limits: RateLimits{
+				HourlyLimit: 500, HourlyRemaining: 42, HourlyReset: now.Add(30 * time.Minute),
+				DailyLimit: 20000, DailyRemaining: 0, DailyReset: now.Add(13 * time.Hour),
+			},
+			want:      42,
+			wantReset: now.Add(30 * time.Minute),
+		},
+		{
+			name: "hourly window already reset",
+			limits: RateLimits{
+				HourlyLimit: 500, HourlyRemaining: 0, HourlyReset: now.Add(-time.Minute),
+				DailyLimit: 20000, DailyRemaining: 0, DailyReset: now.Add(13 * time.Hour),
+			},
+			want:      500,
+			wantReset: now.Add(13 * time.Hour),
+		},
+		{
+			name:   "unknown resets",
+			limits: RateLimits{HourlyRemaining: 3},
+			want:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.limits.Remaining(now))
+			assert.Equal(t, tt.wantReset, tt.limits.NextReset(now))
+		})
+	}
+}
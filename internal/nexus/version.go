@@ -0,0 +1,131 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CompareVersions compares two free-form version strings as they are used on
+// Nexus (e.g., "1.2", "v1.10.3", "4.2.5a", "2.0-beta"). It returns -1 if a is
+// older than b, 1 if it's newer and 0 if they're the same.
+//
+// Versions are split into runs of digits and letters; digit runs are
+// compared numerically, letter runs case-insensitively, and a digit run is
+// considered newer than a letter run. Missing trailing components count as
+// older (so "1.0a" is newer than "1.0"). This isn't semver, but mod authors
+// don't use semver consistently either.
+func CompareVersions(a, b string) int {
+	ta := versionTokens(a)
+	tb := versionTokens(b)
+
+	for i := 0; i < len(ta) || i < len(tb); i++ {
+		switch {
+		case i >= len(ta):
+			if isZero(tb[i:]) {
+				return 0
+			}
+			return -1
+		case i >= len(tb):
+			if isZero(ta[i:]) {
+				return 0
+			}
+			return 1
+		}
+
+		if c := compareToken(ta[i], tb[i]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func versionTokens(v string) []string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	v = strings.TrimPrefix(v, "v")
+
+	var tokens []string
+	var cur strings.Builder
+	var curDigit bool
+
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range v {
+		switch {
+		case unicode.IsDigit(r):
+			if cur.Len() > 0 && !curDigit {
+				flush()
+			}
+			curDigit = true
+			cur.WriteRune(r)
+		case unicode.IsLetter(r):
+			if cur.Len() > 0 && curDigit {
+				flush()
+			}
+			curDigit = false
+			cur.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func compareToken(a, b string) int {
+	na, aErr := strconv.ParseUint(a, 10, 64)
+	nb, bErr := strconv.ParseUint(b, 10, 64)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return 1
+	case bErr == nil:
+		return -1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// trailing "0" components don't make a version newer: 1.0.0 == 1.0
+func isZero(tokens []string) bool {
+	for _, t := range tokens {
+		n, err := strconv.ParseUint(t, 10, 64)
+		if err != nil || n != 0 {
+			return false
+		}
+	}
+	return true
+}
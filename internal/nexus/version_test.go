@@ -0,0 +1,59 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package nexus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{name: "equal", a: "1.2.3", b: "1.2.3", want: 0},
+		{name: "older patch", a: "1.2.3", b: "1.2.4", want: -1},
+		{name: "numeric not lexical", a: "1.10", b: "1.9", want: 1},
+		{name: "v prefix", a: "v2.0", b: "2.0", want: 0},
+		{name: "trailing zeros", a: "1.0.0", b: "1", want: 0},
+		{name: "extra component", a: "1.0.1", b: "1.0", want: 1},
+		{name: "letter suffix is newer", a: "4.2.5a", b: "4.2.5", want: 1},
+		{name: "letter suffixes", a: "4.2.5a", b: "4.2.5b", want: -1},
+		{name: "case insensitive", a: "1.0A", b: "1.0a", want: 0},
+		{name: "digits beat letters", a: "1.1", b: "1.beta", want: 1},
+		{name: "dash separators", a: "2-0-1", b: "2.0.1", want: 0},
+		{name: "both empty", a: "", b: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareVersions(tt.b, tt.a))
+		})
+	}
+}
@@ -0,0 +1,248 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/dryrun"
+)
+
+// NukeResult is what Nuke did to return a game install to stock.
+type NukeResult struct {
+	// removing the deployed files and restoring their backups
+	Unapply DeployResult
+	// backups of files that weren't deployed anymore (e.g., after an
+	// interrupted apply) that were put back
+	Restored []ChangedPath
+	// whether there was a baseline to compare the game files to
+	Verified bool
+	// files that changed since the baseline was recorded
+	Changed []ChangedPath
+	// files of the baseline that are gone
+	Missing []ChangedPath
+	// files that were created after the baseline was recorded (e.g., saves
+	// or configs written by the game), they're left alone
+	Added int
+	// the records that were removed
+	Profiles int64
+	Mods     int
+}
+
+// BackupConflictError is returned by Nuke when files that have a backup were
+// changed since the backup was made: putting the backups back would lose
+// the changes.
+type BackupConflictError struct {
+	Paths []ChangedPath
+}
+
+func (e *BackupConflictError) Error() string {
+	paths := make([]string, len(e.Paths))
+	for i, p := range e.Paths {
+		paths[i] = p.String()
+	}
+	return fmt.Sprintf("%d file(s) with a backup were changed since: %s",
+		len(e.Paths), strings.Join(paths, ", "))
+}
+
+func (e *BackupConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Nuke returns a game install to stock and makes modctl forget about it:
+// it removes everything that was deployed, puts back every backup, compares
+// the game files to the baseline (if there is one), and removes the
+// records of the game install (its profiles, mods, targets, and history).
+// The archives and backups stay in their stores.
+//
+// Nothing is removed from the database if the files can't be put back: with
+// the deployer's Force changed files are replaced anyway.
+func Nuke(ctx context.Context, d *Deployer, gi dbq.GameInstall) (res NukeResult, err error) {
+	res.Unapply, err = d.Unapply(ctx, gi)
+	if err != nil {
+		return res, err
+	}
+
+	if err := d.restoreBackups(ctx, gi, &res); err != nil {
+		return res, err
+	}
+
+	// nothing was put back during a dry run
+	if !dryrun.Enabled() {
+		if err := verifyBaseline(ctx, d.Q, gi, &res); err != nil {
+			return res, err
+		}
+	}
+
+	mods, err := d.Q.ListModsByGameInstall(ctx, gi.ID)
+	if err != nil {
+		return res, fmt.Errorf("list mods: %w", err)
+	}
+	res.Mods = len(mods)
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return res, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := d.Q.WithTx(tx)
+
+	if res.Profiles, err = qtx.DeleteProfilesForGameInstall(ctx, gi.ID); err != nil {
+		return res, fmt.Errorf("delete profiles: %w", err)
+	}
+	if err := qtx.DeleteGameInstall(ctx, gi.ID); err != nil {
+		return res, fmt.Errorf("delete game install: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, err
+	}
+
+	AutoOptimize(ctx, d.DB, int64(len(mods))+res.Profiles)
+	return res, nil
+}
+
+// restoreBackups puts back the backups that are left after unapplying: it
+// restores every file that has a backup, normally there's none left.
+func (d *Deployer) restoreBackups(ctx context.Context, gi dbq.GameInstall, res *NukeResult) error {
+	backups, err := d.Q.ListBackupsForGame(ctx, gi.ID)
+	if err != nil {
+		return fmt.Errorf("list backups: %w", err)
+	}
+	if len(backups) == 0 {
+		return nil
+	}
+
+	targets, err := d.resolveTargets(ctx, gi)
+	if err != nil {
+		return err
+	}
+
+	type restore struct {
+		path     ChangedPath
+		src      string
+		dst      string
+		readOnly bool
+	}
+
+	var restores []restore
+	var conflicts []ChangedPath
+	for _, b := range backups {
+		t, ok := targets[b.TargetID]
+		if !ok {
+			continue
+		}
+		cp := ChangedPath{b.TargetName, b.Relpath}
+		dst := filepath.Join(t.root, filepath.FromSlash(b.Relpath))
+
+		sha, _, err := deploy.HashFileCached(ctx, d.Hashes, dst)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("hash %s: %w", cp, err)
+		case sha == b.BackupBlobSha256:
+			// it's already there
+			continue
+		case !d.Force:
+			conflicts = append(conflicts, cp)
+			continue
+		}
+
+		src, err := d.Blobs.Locate(blobstore.Kind(b.BlobKind), b.BackupBlobSha256)
+		if err != nil {
+			return err
+		}
+		restores = append(restores, restore{path: cp, src: src, dst: dst, readOnly: b.ReadOnly != 0})
+	}
+
+	// check everything before changing anything
+	if len(conflicts) > 0 {
+		return &BackupConflictError{Paths: conflicts}
+	}
+
+	for _, r := range restores {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, _, err := deploy.WriteFile(ctx, r.src, r.dst); err != nil {
+			return fmt.Errorf("restore %s: %w", r.path, err)
+		}
+		if r.readOnly {
+			if err := deploy.SetReadOnly(r.dst); err != nil {
+				return fmt.Errorf("restore %s: %w", r.path, err)
+			}
+		}
+		res.Restored = append(res.Restored, r.path)
+	}
+
+	return nil
+}
+
+// verifyBaseline compares the game files to the baseline, if there is one.
+func verifyBaseline(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall, res *NukeResult) error {
+	if !gi.BaselineScannedAt.Valid {
+		return nil
+	}
+	res.Verified = true
+
+	files, err := ClassifyFiles(ctx, q, gi, false)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(files))
+	for _, f := range files {
+		present[f.String()] = true
+		switch f.Provenance {
+		case ProvenanceUser:
+			res.Added++
+		case ProvenanceUnknown, ProvenanceMod:
+			res.Changed = append(res.Changed, ChangedPath{f.Target, f.RelPath})
+		}
+	}
+
+	targets, err := q.ListTargetsForGameInstall(ctx, gi.ID)
+	if err != nil {
+		return fmt.Errorf("list targets: %w", err)
+	}
+	names := make(map[int64]string, len(targets))
+	for _, t := range targets {
+		names[t.ID] = t.Name
+	}
+
+	baseline, err := q.ListBaselineFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return fmt.Errorf("list baseline: %w", err)
+	}
+	for _, row := range baseline {
+		cp := ChangedPath{names[row.TargetID], row.Relpath}
+		if !present[cp.String()] {
+			res.Missing = append(res.Missing, cp)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leftoverBackup records a backup of relpath (in the game_dir target) that
+// no deployed file refers to anymore, e.g., after an interrupted apply.
+func leftoverBackup(t *testing.T, d *Deployer, relpath, content string, readOnly bool) {
+	t.Helper()
+
+	sha := writeBlob(t, d, d.Blobs.BackupsDir, blobstore.KindBackup, content)
+	ro := 0
+	if readOnly {
+		ro = 1
+	}
+	_, err := d.DB.Exec(`
+		INSERT INTO backups (game_install_id, target_id, relpath, backup_blob_sha256, size_bytes, read_only)
+		VALUES (1, 1, ?, ?, ?, ?)`, relpath, sha, len(content), ro)
+	require.NoError(t, err)
+}
+
+func TestNukeRestoresBackups(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	_, err := d.DB.Exec(`INSERT INTO profiles (id, game_install_id, name) VALUES (1, 1, 'default')`)
+	require.NoError(t, err)
+	leftoverBackup(t, d, "data/a.esp", "the original file", false)
+
+	res, err := Nuke(ctx, d, gi)
+	require.NoError(t, err)
+	assert.Equal(t, []ChangedPath{{"game_dir", "data/a.esp"}}, res.Restored)
+	assert.Equal(t, int64(1), res.Profiles)
+	assert.False(t, res.Verified)
+
+	got, err := os.ReadFile(filepath.Join(root, "data", "a.esp"))
+	require.NoError(t, err)
+	assert.Equal(t, "the original file", string(got))
+
+	// modctl forgot about the game, but the backup stays in its store
+	_, err = d.Q.GetGameInstallByID(ctx, gi.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	backups, err := d.Q.ListBackupsForGame(ctx, gi.ID)
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+	assert.Len(t, storedBlobs(t, d.Blobs.BackupsDir), 1)
+}
+
+func TestNukeBackupConflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	leftoverBackup(t, d, "a.ini", "the original file", false)
+	path := filepath.Join(root, "a.ini")
+	require.NoError(t, os.WriteFile(path, []byte("changed since"), 0o644))
+
+	_, err := Nuke(ctx, d, gi)
+	var conflict *BackupConflictError
+	require.True(t, errors.As(err, &conflict), "%v", err)
+	assert.Equal(t, []ChangedPath{{"game_dir", "a.ini"}}, conflict.Paths)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	// nothing was changed or forgotten
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "changed since", string(got))
+	_, err = d.Q.GetGameInstallByID(ctx, gi.ID)
+	require.NoError(t, err)
+
+	d.Force = true
+	res, err := Nuke(ctx, d, gi)
+	require.NoError(t, err)
+	assert.Equal(t, []ChangedPath{{"game_dir", "a.ini"}}, res.Restored)
+	got, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "the original file", string(got))
+}
+
+func TestNukeRestoresReadOnly(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	leftoverBackup(t, d, "a.ini", "the original file", true)
+
+	_, err := Nuke(ctx, d, gi)
+	require.NoError(t, err)
+
+	st, err := os.Stat(filepath.Join(root, "a.ini"))
+	require.NoError(t, err)
+	assert.Zero(t, st.Mode().Perm()&0o222, "%v", st.Mode())
+}
+
+func TestNukeReportsBaselineMismatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+	for name, content := range map[string]string{"a.esp": "stock a", "b.esp": "stock b"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0o644))
+	}
+	_, err := RecordBaseline(ctx, d.DB, d.Q, gi)
+	require.NoError(t, err)
+	gi, err = d.Q.GetGameInstallByID(ctx, gi.ID)
+	require.NoError(t, err)
+
+	// changed, deleted, and created since
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.esp"), []byte("not stock"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(root, "b.esp")))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "save.ess"), []byte("a save"), 0o644))
+
+	res, err := Nuke(ctx, d, gi)
+	require.NoError(t, err)
+	assert.True(t, res.Verified)
+	assert.Equal(t, []ChangedPath{{"game_dir", "a.esp"}}, res.Changed)
+	assert.Equal(t, []ChangedPath{{"game_dir", "b.esp"}}, res.Missing)
+	assert.Equal(t, 1, res.Added)
+}
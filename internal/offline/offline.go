@@ -0,0 +1,59 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package offline implements the global --offline flag (and the offline
+// config option): nothing goes over the network. Nexus API requests are
+// answered from the response cache, even if it's stale, and everything else
+// that would need the network (downloads, fetching the advisory feed) fails
+// with ErrOffline, e.g., on a Steam Deck while traveling or on an air-gapped
+// machine.
+package offline
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOffline is returned for what needs the network in offline mode.
+var ErrOffline = errors.New("not available offline")
+
+var enabled bool
+
+// Enable turns on offline mode for the rest of the command.
+func Enable() {
+	enabled = true
+}
+
+// Enabled reports whether the network must not be used.
+func Enabled() bool {
+	return enabled
+}
+
+// Reset turns offline mode off again.
+func Reset() {
+	enabled = false
+}
+
+// Check returns ErrOffline (for what, e.g., "download nexus file 123") if
+// offline mode is on.
+func Check(what string) error {
+	if enabled {
+		return fmt.Errorf("%s: %w", what, ErrOffline)
+	}
+	return nil
+}
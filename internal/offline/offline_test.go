@@ -0,0 +1,39 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package offline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Cleanup(Reset)
+
+	assert.NoError(t, Check("fetch advisory feed"))
+
+	Enable()
+	err := Check("fetch advisory feed")
+	assert.ErrorIs(t, err, ErrOffline)
+	assert.EqualError(t, err, "fetch advisory feed: not available offline")
+
+	Reset()
+	assert.NoError(t, Check("fetch advisory feed"))
+}
@@ -0,0 +1,173 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// The statuses of an operation note: whether the applied revision worked.
+const (
+	NoteStatusPass = "pass"
+	NoteStatusFail = "fail"
+)
+
+// AddOperationNote records what testing the revision that an apply
+// deployed found (e.g., "crashes in Whiterun") and, unless status is empty,
+// whether it worked.
+func AddOperationNote(ctx context.Context, q *dbq.Queries, opID int64, status, note string) error {
+	note = strings.TrimSpace(note)
+	if status != "" && status != NoteStatusPass && status != NoteStatusFail {
+		return fmt.Errorf("invalid status %q (pass or fail)", status)
+	}
+	if note == "" && status == "" {
+		return errors.New("a note needs a text or a status")
+	}
+
+	op, err := q.GetOperation(ctx, opID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no operation with id %d", opID)
+	}
+	if err != nil {
+		return fmt.Errorf("get operation: %w", err)
+	}
+	if op.OpType != "apply" {
+		return fmt.Errorf("operation %d is an %s, only applies deployed a revision to take notes about", opID, op.OpType)
+	}
+
+	if err := q.AddOperationNote(ctx, dbq.AddOperationNoteParams{
+		OperationID: opID,
+		Status:      sql.NullString{String: status, Valid: status != ""},
+		Note:        note,
+	}); err != nil {
+		return fmt.Errorf("add note: %w", err)
+	}
+	return nil
+}
+
+// OperationLogEntry is an operation in the log, with its notes.
+type OperationLogEntry struct {
+	ID         int64           `json:"id"`
+	Game       string          `json:"game"`
+	Profile    string          `json:"profile,omitempty"`
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	StartedAt  string          `json:"started_at"`
+	FinishedAt string          `json:"finished_at,omitempty"`
+	Mods       []string        `json:"mods,omitempty"`
+	Notes      []OperationNote `json:"notes,omitempty"`
+}
+
+// OperationNote is a note about an operation.
+type OperationNote struct {
+	Status    string `json:"status,omitempty"`
+	Note      string `json:"note,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Verdict returns the status of the newest note that has one ("" if
+// none does).
+func (e OperationLogEntry) Verdict() string {
+	for i := len(e.Notes) - 1; i >= 0; i-- {
+		if e.Notes[i].Status != "" {
+			return e.Notes[i].Status
+		}
+	}
+	return ""
+}
+
+// OperationLogFilter selects the operations of OperationLog.
+type OperationLogFilter struct {
+	// only the operations of this game install (0: of every game)
+	GameInstallID int64
+	// only the operations with notes
+	NotedOnly bool
+	// only the operations with a note that contains this (ignoring case)
+	Search string
+	// how many operations (0: all of them)
+	Limit int64
+}
+
+// OperationLog returns the newest operations (newest first) with their
+// notes and, for applies, the mods of the revision that they deployed (as
+// "<mod> / <file>", highest priority first).
+func OperationLog(ctx context.Context, q *dbq.Queries, f OperationLogFilter) ([]OperationLogEntry, error) {
+	noted := int64(0)
+	if f.NotedOnly {
+		noted = 1
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = -1 // no limit
+	}
+	rows, err := q.ListOperationLog(ctx, dbq.ListOperationLogParams{
+		GameInstallID: sql.NullInt64{Int64: f.GameInstallID, Valid: f.GameInstallID != 0},
+		NotedOnly:     noted,
+		Search:        f.Search,
+		RowLimit:      limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list operations: %w", err)
+	}
+
+	out := make([]OperationLogEntry, 0, len(rows))
+	for _, r := range rows {
+		e := OperationLogEntry{
+			ID:         r.ID,
+			Game:       r.GameName,
+			Profile:    r.ProfileName.String,
+			Type:       r.OpType,
+			Status:     r.Status,
+			StartedAt:  r.StartedAt,
+			FinishedAt: r.FinishedAt.String,
+		}
+
+		if r.Revision.Valid {
+			var rev Revision
+			if err := json.Unmarshal([]byte(r.Revision.String), &rev); err != nil {
+				return nil, fmt.Errorf("parse revision of operation %d: %w", r.ID, err)
+			}
+			for _, it := range rev.Items {
+				e.Mods = append(e.Mods, it.ModName+" / "+it.FileLabel)
+			}
+		}
+
+		notes, err := q.ListOperationNotes(ctx, r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list notes: %w", err)
+		}
+		for _, n := range notes {
+			e.Notes = append(e.Notes, OperationNote{
+				Status:    n.Status.String,
+				Note:      n.Note,
+				CreatedAt: n.CreatedAt,
+			})
+		}
+
+		out = append(out, e)
+	}
+	return out, nil
+}
@@ -0,0 +1,47 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationLogEntryVerdict(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, OperationLogEntry{}.Verdict())
+
+	e := OperationLogEntry{Notes: []OperationNote{
+		{Status: NoteStatusFail, Note: "crashes in Whiterun"},
+		{Status: NoteStatusPass, Note: "fixed by the patch"},
+		{Note: "textures are a bit blurry"},
+	}}
+	assert.Equal(t, NoteStatusPass, e.Verdict())
+}
+
+func TestAddOperationNoteInvalid(t *testing.T) {
+	t.Parallel()
+
+	// checked before the database is looked at
+	assert.ErrorContains(t, AddOperationNote(context.Background(), nil, 1, "maybe", "x"), "invalid status")
+	assert.ErrorContains(t, AddOperationNote(context.Background(), nil, 1, "", "  "), "needs a text or a status")
+}
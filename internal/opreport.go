@@ -0,0 +1,284 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// Phase is a step of an apply and how long it took.
+type Phase struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// phaseTimer records the phases of an operation into its result.
+type phaseTimer struct {
+	res   *DeployResult
+	name  string
+	start time.Time
+}
+
+// next ends the current phase (if any) and starts the named one.
+func (t *phaseTimer) next(name string) {
+	t.stop()
+	t.name, t.start = name, time.Now()
+}
+
+// stop ends the current phase.
+func (t *phaseTimer) stop() {
+	if t.name == "" {
+		return
+	}
+	t.res.Phases = append(t.res.Phases, Phase{Name: t.name, Duration: time.Since(t.start)})
+	t.name = ""
+}
+
+// OperationReport describes what an operation (e.g., an apply) did.
+type OperationReport struct {
+	OperationID int64  `json:"operation_id"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+	Game        string `json:"game"`
+	Profile     string `json:"profile,omitempty"`
+	StartedAt   string `json:"started_at"`
+	FinishedAt  string `json:"finished_at,omitempty"`
+
+	Result  DeployResult   `json:"result"`
+	Changes []ReportChange `json:"changes"`
+}
+
+// ReportChange is a file that an operation changed.
+type ReportChange struct {
+	Target  string `json:"target"`
+	RelPath string `json:"relpath"`
+	// write, overwrite, remove, or restore_backup
+	Action   string `json:"action"`
+	Mod      string `json:"mod,omitempty"`
+	BackedUp bool   `json:"backed_up,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// BuildOperationReport puts together the report of an operation from what
+// the database has about it.
+func BuildOperationReport(ctx context.Context, q *dbq.Queries, opID int64) (OperationReport, error) {
+	op, err := q.GetOperation(ctx, opID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return OperationReport{}, fmt.Errorf("operation %d not found", opID)
+	}
+	if err != nil {
+		return OperationReport{}, fmt.Errorf("get operation %d: %w", opID, err)
+	}
+
+	r := OperationReport{
+		OperationID: op.ID,
+		Type:        op.OpType,
+		Status:      op.Status,
+		Message:     op.Message.String,
+		Game:        op.GameName,
+		Profile:     op.ProfileName.String,
+		StartedAt:   op.StartedAt,
+		FinishedAt:  op.FinishedAt.String,
+		Changes:     []ReportChange{},
+	}
+	if op.Metadata.Valid {
+		// older operations might not have everything, that's fine
+		_ = json.Unmarshal([]byte(op.Metadata.String), &r.Result)
+	}
+	r.Result.OperationID = op.ID
+
+	changes, err := q.ListOperationChanges(ctx, opID)
+	if err != nil {
+		return OperationReport{}, fmt.Errorf("list changes of operation %d: %w", opID, err)
+	}
+	for _, c := range changes {
+		rc := ReportChange{
+			Target:   c.TargetName,
+			RelPath:  c.Relpath,
+			Action:   c.Action,
+			BackedUp: c.BackupBlobSha256.Valid && c.Action != "restore_backup",
+			Notes:    c.Notes.String,
+		}
+		if c.ModName.Valid {
+			rc.Mod = fmt.Sprintf("%s / %s (v%d)", c.ModName.String, c.FileLabel.String, c.ModFileVersionID.Int64)
+		}
+		r.Changes = append(r.Changes, rc)
+	}
+
+	return r, nil
+}
+
+// Text renders the report for humans.
+func (r OperationReport) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Operation %d: %s", r.OperationID, r.Type)
+	if r.Profile != "" {
+		fmt.Fprintf(&b, " of profile %q", r.Profile)
+	}
+	fmt.Fprintf(&b, " (%s)\n", r.Game)
+	fmt.Fprintf(&b, "Status:   %s\n", r.Status)
+	if r.Message != "" {
+		fmt.Fprintf(&b, "Message:  %s\n", r.Message)
+	}
+	fmt.Fprintf(&b, "Started:  %s\n", r.StartedAt)
+	if r.FinishedAt != "" {
+		fmt.Fprintf(&b, "Finished: %s\n", r.FinishedAt)
+	}
+
+	res := r.Result
+	b.WriteString("\nSummary\n")
+	fmt.Fprintf(&b, "  %d written, %d replaced, %d removed, %d restored, %d unchanged (%d backed up",
+		res.Written, res.Overwritten, res.Removed, res.Restored, res.Unchanged, res.BackedUp)
+	if res.Vanilla > 0 {
+		fmt.Fprintf(&b, ", %d vanilla not backed up", res.Vanilla)
+	}
+	if res.NotBackedUp > 0 {
+		fmt.Fprintf(&b, ", %d not backed up by target policy", res.NotBackedUp)
+	}
+	b.WriteString(")\n")
+
+	if len(res.Phases) > 0 {
+		b.WriteString("\nPhases\n")
+		var total time.Duration
+		for _, p := range res.Phases {
+			fmt.Fprintf(&b, "  %-10s %s\n", p.Name, p.Duration.Round(time.Millisecond))
+			total += p.Duration
+		}
+		fmt.Fprintf(&b, "  %-10s %s\n", "total", total.Round(time.Millisecond))
+	}
+
+	sections := []struct {
+		title  string
+		action string
+	}{
+		{"Deployed", "write"},
+		{"Overwritten", "overwrite"},
+		{"Removed", "remove"},
+		{"Restored from backup", "restore_backup"},
+	}
+	for _, s := range sections {
+		var lines []string
+		for _, c := range r.Changes {
+			if c.Action != s.action {
+				continue
+			}
+			line := "  " + c.Target + "/" + c.RelPath
+			var notes []string
+			if c.Mod != "" {
+				notes = append(notes, c.Mod)
+			}
+			if c.BackedUp {
+				notes = append(notes, "backed up")
+			}
+			if c.Notes != "" {
+				notes = append(notes, c.Notes)
+			}
+			if len(notes) > 0 {
+				line += "  (" + strings.Join(notes, "; ") + ")"
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d)\n", s.title, len(lines))
+		b.WriteString(strings.Join(lines, "\n") + "\n")
+	}
+
+	if res.Unchanged > 0 || res.Hidden > 0 || res.Shadowed > 0 {
+		b.WriteString("\nSkipped\n")
+		if res.Unchanged > 0 {
+			fmt.Fprintf(&b, "  %d files were already deployed and were left alone\n", res.Unchanged)
+		}
+		if res.Hidden > 0 {
+			fmt.Fprintf(&b, "  %d files are hidden by the profile\n", res.Hidden)
+		}
+		if res.Shadowed > 0 {
+			fmt.Fprintf(&b, "  %d files lost a conflict to a higher priority mod\n", res.Shadowed)
+		}
+	}
+
+	for _, w := range []struct {
+		title    string
+		warnings []string
+	}{
+		{"Plan warnings", res.PlanWarnings},
+		{"Warnings", res.Warnings},
+	} {
+		if len(w.warnings) == 0 {
+			continue
+		}
+		b.WriteString("\n" + w.title + "\n")
+		for _, s := range w.warnings {
+			b.WriteString("  " + s + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// reportPath returns the path of the report of an operation (without the
+// extension).
+func reportPath(dir string, opID int64) string {
+	return filepath.Join(dir, strconv.FormatInt(opID, 10))
+}
+
+// WriteOperationReport writes the report of an operation into dir as text
+// (<id>.txt) and JSON (<id>.json). It returns the path of the text one.
+func WriteOperationReport(dir string, r OperationReport) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create reports dir: %w", err)
+	}
+
+	js, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode report: %w", err)
+	}
+
+	base := reportPath(dir, r.OperationID)
+	if err := os.WriteFile(base+".json", append(js, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("write report: %w", err)
+	}
+	if err := os.WriteFile(base+".txt", []byte(r.Text()), 0o644); err != nil {
+		return "", fmt.Errorf("write report: %w", err)
+	}
+	return base + ".txt", nil
+}
+
+// ReadOperationReport reads the report of an operation that
+// WriteOperationReport wrote: the text one or, with asJSON, the JSON one.
+func ReadOperationReport(dir string, opID int64, asJSON bool) ([]byte, error) {
+	ext := ".txt"
+	if asJSON {
+		ext = ".json"
+	}
+	return os.ReadFile(reportPath(dir, opID) + ext)
+}
@@ -0,0 +1,91 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationReportText(t *testing.T) {
+	t.Parallel()
+
+	r := OperationReport{
+		OperationID: 42,
+		Type:        "apply",
+		Status:      "succeeded",
+		Game:        "Skyrim Special Edition",
+		Profile:     "default",
+		StartedAt:   "2026-01-02T03:04:05.000Z",
+		FinishedAt:  "2026-01-02T03:04:07.000Z",
+		Result: DeployResult{
+			Written:      1,
+			Overwritten:  1,
+			Unchanged:    3,
+			BackedUp:     1,
+			Hidden:       2,
+			PlanWarnings: []string{`skipping "../evil.esp": unsafe path`},
+			Phases: []Phase{
+				{Name: "prepare", Duration: 1500 * time.Millisecond},
+				{Name: "deploy", Duration: 500 * time.Millisecond},
+			},
+		},
+		Changes: []ReportChange{
+			{Target: "game_dir", RelPath: "Data/a.esp", Action: "write", Mod: "Cool Mod"},
+			{Target: "game_dir", RelPath: "Data/b.esp", Action: "overwrite", Mod: "Cool Mod", BackedUp: true},
+		},
+	}
+
+	txt := r.Text()
+	assert.Contains(t, txt, `Operation 42: apply of profile "default" (Skyrim Special Edition)`)
+	assert.Contains(t, txt, "1 written, 1 replaced, 0 removed, 0 restored, 3 unchanged (1 backed up)")
+	assert.Contains(t, txt, "  prepare    1.5s\n")
+	assert.Contains(t, txt, "  total      2s\n")
+	assert.Contains(t, txt, "Deployed (1)\n  game_dir/Data/a.esp  (Cool Mod)\n")
+	assert.Contains(t, txt, "Overwritten (1)\n  game_dir/Data/b.esp  (Cool Mod; backed up)\n")
+	assert.NotContains(t, txt, "Removed")
+	assert.Contains(t, txt, "  2 files are hidden by the profile\n")
+	assert.Contains(t, txt, "Plan warnings\n  skipping \"../evil.esp\": unsafe path\n")
+}
+
+func TestWriteOperationReport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	r := OperationReport{OperationID: 7, Type: "apply", Status: "succeeded", Game: "Game"}
+
+	path, err := WriteOperationReport(dir, r)
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	txt, err := ReadOperationReport(dir, 7, false)
+	require.NoError(t, err)
+	assert.Equal(t, r.Text(), string(txt))
+
+	js, err := ReadOperationReport(dir, 7, true)
+	require.NoError(t, err)
+	assert.Contains(t, string(js), `"operation_id": 7`)
+
+	_, err = ReadOperationReport(dir, 8, false)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
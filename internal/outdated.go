@@ -0,0 +1,297 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/mfinelli/modctl/internal/offline"
+)
+
+type UpdateStatus string
+
+const (
+	UpdateStatusUpToDate UpdateStatus = "up_to_date"
+	UpdateStatusOutdated UpdateStatus = "outdated"
+	UpdateStatusNewFiles UpdateStatus = "new_files" // up to date, but with new files upstream
+	UpdateStatusUnknown  UpdateStatus = "unknown"   // no imported version string
+	UpdateStatusDeferred UpdateStatus = "deferred"  // out of api requests
+	UpdateStatusError    UpdateStatus = "error"
+)
+
+type UpdateCheck struct {
+	ModPageID  int64
+	ModName    string
+	GameDomain string
+	NexusModID int64
+
+	// newest version_string across all imported archives of the page
+	ImportedVersion string
+	LatestVersion   string
+
+	// imported files that a newer upload replaced, and files that were
+	// uploaded since the last import that aren't updates of imported ones
+	Superseded []SupersededFile
+	NewFiles   []nexus.File
+
+	Status UpdateStatus
+	Err    error
+}
+
+// SupersededFile is a mod file whose (newest imported) nexus file was
+// replaced upstream.
+type SupersededFile struct {
+	ModFileID int64
+	Label     string
+	FileID    int64
+
+	// the file that replaced it (the end of the chain of updates); zero if
+	// nexus just moved it to the old files without saying what replaced it
+	NewFileID int64
+	NewFile   nexus.File
+}
+
+// importedNexusFile is the newest imported nexus file of a mod file.
+type importedNexusFile struct {
+	ModFileID  int64
+	Label      string
+	FileID     int64
+	ImportedAt time.Time
+}
+
+// CheckNexusUpdates compares the newest imported version of every
+// Nexus-linked mod page of the game install with the version on Nexus.
+//
+// A failure to check a single mod doesn't abort the whole check: the error is
+// recorded in the result instead. Once the API rate limit is reached, the
+// remaining mods are marked as deferred, like the ones that offline mode has
+// no cached responses for.
+func CheckNexusUpdates(ctx context.Context, q *dbq.Queries, c *nexus.Client, gameInstallID int64) ([]UpdateCheck, error) {
+	pages, err := q.ListNexusModPagesByGameInstall(ctx, gameInstallID)
+	if err != nil {
+		return nil, fmt.Errorf("list nexus mods: %w", err)
+	}
+
+	checks := make([]UpdateCheck, 0, len(pages))
+	for _, p := range pages {
+		if err := ctx.Err(); err != nil {
+			return checks, err
+		}
+
+		uc := UpdateCheck{
+			ModPageID:  p.ID,
+			ModName:    p.Name,
+			GameDomain: p.NexusGameDomain.String,
+			NexusModID: p.NexusModID.Int64,
+		}
+
+		versions, err := q.ListVersionStringsForPage(ctx, p.ID)
+		if err != nil {
+			return checks, fmt.Errorf("list versions (page_id=%d): %w", p.ID, err)
+		}
+		for _, v := range versions {
+			if uc.ImportedVersion == "" || nexus.CompareVersions(v.String, uc.ImportedVersion) > 0 {
+				uc.ImportedVersion = v.String
+			}
+		}
+
+		mod, err := c.GetMod(ctx, uc.GameDomain, uc.NexusModID)
+		var list nexus.FileList
+		if err == nil {
+			list, err = c.GetFileList(ctx, uc.GameDomain, uc.NexusModID)
+		}
+		var rlErr *nexus.RateLimitError
+		switch {
+		case errors.As(err, &rlErr) || errors.Is(err, offline.ErrOffline):
+			uc.Status = UpdateStatusDeferred
+			uc.Err = err
+		case err != nil:
+			uc.Status = UpdateStatusError
+			uc.Err = err
+		default:
+			uc.LatestVersion = mod.Version
+			switch {
+			case uc.ImportedVersion == "":
+				uc.Status = UpdateStatusUnknown
+			case nexus.CompareVersions(uc.ImportedVersion, mod.Version) < 0:
+				uc.Status = UpdateStatusOutdated
+			default:
+				uc.Status = UpdateStatusUpToDate
+			}
+
+			if err := SaveNexusFileUpdates(ctx, q, uc.GameDomain, uc.NexusModID, list.Updates); err != nil {
+				return checks, err
+			}
+			imported, known, err := importedNexusFiles(ctx, q, p.ID)
+			if err != nil {
+				return checks, err
+			}
+			uc.Superseded, uc.NewFiles = classifyNexusFiles(imported, known, list)
+
+			// a replaced file is an update even if the version string
+			// (of the mod page) didn't change or isn't known
+			switch {
+			case len(uc.Superseded) > 0:
+				uc.Status = UpdateStatusOutdated
+			case uc.Status == UpdateStatusUpToDate && len(uc.NewFiles) > 0:
+				uc.Status = UpdateStatusNewFiles
+			}
+		}
+
+		checks = append(checks, uc)
+	}
+
+	return checks, nil
+}
+
+// importedNexusFiles returns the newest imported nexus file of every mod file
+// of a page, and every nexus file that was ever imported for it.
+func importedNexusFiles(ctx context.Context, q *dbq.Queries, pageID int64) ([]importedNexusFile, map[int64]bool, error) {
+	rows, err := q.ListNexusFilesForPage(ctx, pageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list nexus files (page_id=%d): %w", pageID, err)
+	}
+	ids, err := q.ListImportedNexusFileIDsForPage(ctx, pageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list imported nexus files (page_id=%d): %w", pageID, err)
+	}
+
+	imported := make([]importedNexusFile, 0, len(rows))
+	for _, r := range rows {
+		imported = append(imported, importedNexusFile{
+			ModFileID:  r.ID,
+			Label:      r.Label,
+			FileID:     r.NexusFileID,
+			ImportedAt: parseDBTime(sql.NullString{String: r.ImportedAt, Valid: true}),
+		})
+	}
+
+	known := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+
+	return imported, known, nil
+}
+
+// classifyNexusFiles compares the imported files of a mod with its files on
+// Nexus. An imported file is superseded if the chain of updates leads from it
+// to a file that wasn't imported (yet), or if Nexus moved it to the old files.
+// New files are current files that were uploaded after the last import and
+// that aren't imported or the update of an imported file. If none of the
+// imported files is known to be a nexus file there are no new files: every
+// file would be new.
+func classifyNexusFiles(imported []importedNexusFile, known map[int64]bool, list nexus.FileList) ([]SupersededFile, []nexus.File) {
+	byID := map[int64]nexus.File{}
+	for _, f := range list.Files {
+		byID[f.FileID] = f
+	}
+
+	var superseded []SupersededFile
+	replacements := map[int64]bool{}
+	var lastImport time.Time
+	for _, imp := range imported {
+		if imp.ImportedAt.After(lastImport) {
+			lastImport = imp.ImportedAt
+		}
+
+		latest := nexus.LatestFile(list.Updates, imp.FileID)
+		if latest != imp.FileID {
+			replacements[latest] = true
+			if known[latest] {
+				continue
+			}
+			superseded = append(superseded, SupersededFile{
+				ModFileID: imp.ModFileID,
+				Label:     imp.Label,
+				FileID:    imp.FileID,
+				NewFileID: latest,
+				NewFile:   byID[latest],
+			})
+			continue
+		}
+
+		if f, ok := byID[imp.FileID]; ok && f.Category() == "old_version" {
+			superseded = append(superseded, SupersededFile{
+				ModFileID: imp.ModFileID,
+				Label:     imp.Label,
+				FileID:    imp.FileID,
+			})
+		}
+	}
+
+	if len(imported) == 0 {
+		return superseded, nil
+	}
+
+	var newFiles []nexus.File
+	for _, f := range list.Files {
+		if !f.IsCurrent() || known[f.FileID] || replacements[f.FileID] {
+			continue
+		}
+		if f.UploadedTimestamp <= lastImport.Unix() {
+			continue
+		}
+		newFiles = append(newFiles, f)
+	}
+	sort.SliceStable(newFiles, func(i, j int) bool {
+		return newFiles[i].UploadedTimestamp > newFiles[j].UploadedTimestamp
+	})
+
+	return superseded, newFiles
+}
+
+// ChangelogEntry is the changelog of a single version.
+type ChangelogEntry struct {
+	Version string
+	Changes []string
+}
+
+// ChangelogBetween returns the changelog entries newer than from and up to
+// (and including) to, oldest first. If from is empty, only the entry for to
+// is returned.
+func ChangelogBetween(logs map[string][]string, from, to string) []ChangelogEntry {
+	entries := []ChangelogEntry{}
+	for v, changes := range logs {
+		if nexus.CompareVersions(v, to) > 0 {
+			continue
+		}
+		if from == "" {
+			if nexus.CompareVersions(v, to) != 0 {
+				continue
+			}
+		} else if nexus.CompareVersions(v, from) <= 0 {
+			continue
+		}
+		entries = append(entries, ChangelogEntry{Version: v, Changes: changes})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return nexus.CompareVersions(entries[i].Version, entries[j].Version) < 0
+	})
+
+	return entries
+}
@@ -0,0 +1,141 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangelogBetween(t *testing.T) {
+	t.Parallel()
+
+	logs := map[string][]string{
+		"1.0":   {"initial release"},
+		"1.1":   {"fixed a crash"},
+		"1.2":   {"new feature", "updated translations"},
+		"1.10":  {"rewrite"},
+		"2.0b1": {"beta"},
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		wantVers []string
+	}{
+		{
+			name:     "range",
+			from:     "1.0",
+			to:       "1.2",
+			wantVers: []string{"1.1", "1.2"},
+		},
+		{
+			name:     "numeric ordering",
+			from:     "1.1",
+			to:       "1.10",
+			wantVers: []string{"1.2", "1.10"},
+		},
+		{
+			name:     "unknown imported version",
+			from:     "",
+			to:       "1.2",
+			wantVers: []string{"1.2"},
+		},
+		{
+			name:     "already up to date",
+			from:     "1.10",
+			to:       "1.10",
+			wantVers: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ChangelogBetween(logs, tt.from, tt.to)
+
+			vers := []string{}
+			for _, e := range got {
+				vers = append(vers, e.Version)
+			}
+			assert.Equal(t, tt.wantVers, vers)
+		})
+	}
+}
+
+func TestClassifyNexusFiles(t *testing.T) {
+	t.Parallel()
+
+	imported := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := imported.Add(-time.Hour).Unix()
+	after := imported.Add(time.Hour).Unix()
+
+	list := nexus.FileList{
+		Files: []nexus.File{
+			{FileID: 1, Name: "Main", CategoryName: "OLD_VERSION", UploadedTimestamp: before},
+			{FileID: 2, Name: "Main", CategoryName: "MAIN", UploadedTimestamp: after},
+			{FileID: 3, Name: "Textures", CategoryName: "OPTIONAL", UploadedTimestamp: before},
+			{FileID: 4, Name: "Patch", CategoryName: "OPTIONAL", UploadedTimestamp: after},
+			{FileID: 5, Name: "Old patch", CategoryName: "OLD_VERSION", UploadedTimestamp: after},
+			{FileID: 6, Name: "Legacy", CategoryName: "OLD_VERSION", UploadedTimestamp: before},
+			{FileID: 7, Name: "Extra", CategoryName: "MISCELLANEOUS", UploadedTimestamp: before},
+		},
+		Updates: []nexus.FileUpdate{
+			{OldFileID: 1, NewFileID: 2, UploadedTimestamp: after},
+		},
+	}
+
+	files := []importedNexusFile{
+		{ModFileID: 10, Label: "Main File", FileID: 1, ImportedAt: imported},
+		{ModFileID: 11, Label: "Textures", FileID: 3, ImportedAt: imported.Add(-24 * time.Hour)},
+		{ModFileID: 12, Label: "Legacy", FileID: 6, ImportedAt: imported.Add(-24 * time.Hour)},
+	}
+	known := map[int64]bool{1: true, 3: true, 6: true}
+
+	superseded, newFiles := classifyNexusFiles(files, known, list)
+
+	assert.Equal(t, []SupersededFile{
+		{ModFileID: 10, Label: "Main File", FileID: 1, NewFileID: 2, NewFile: list.Files[1]},
+		{ModFileID: 12, Label: "Legacy", FileID: 6},
+	}, superseded)
+
+	var newIDs []int64
+	for _, f := range newFiles {
+		newIDs = append(newIDs, f.FileID)
+	}
+	assert.Equal(t, []int64{4}, newIDs, "only current files uploaded after the last import")
+
+	// the replacement was imported already
+	known[2] = true
+	superseded, _ = classifyNexusFiles(files, known, list)
+	if assert.Len(t, superseded, 1) {
+		assert.Equal(t, int64(6), superseded[0].FileID)
+	}
+
+	// without anything known to be from nexus, nothing is new
+	superseded, newFiles = classifyNexusFiles(nil, map[int64]bool{}, list)
+	assert.Empty(t, superseded)
+	assert.Empty(t, newFiles)
+}
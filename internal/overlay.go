@@ -0,0 +1,134 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/vars"
+	"github.com/spf13/viper"
+)
+
+// ErrOverlayUnsupported is returned when an overlay would be mounted or
+// unmounted on a platform without them.
+var ErrOverlayUnsupported = errors.New("overlays are only supported on linux")
+
+// OverlayDir returns the directory of the layers of an overlay target (see
+// DeployMethodOverlay): files has the files that are deployed to it, upper
+// (and work) what is written to the target while the overlay is mounted.
+func OverlayDir(t dbq.Target) string {
+	return filepath.Join(viper.GetString("overlays_dir"), strconv.FormatInt(t.ID, 10))
+}
+
+// DeployedRoot returns the directory that the files of a target are written
+// to: its root (see TargetRoot), or the layer of an overlay target that is
+// mounted over it.
+func DeployedRoot(gi dbq.GameInstall, t dbq.Target) (string, error) {
+	if t.DeployMethod == DeployMethodOverlay {
+		return filepath.Join(OverlayDir(t), "files"), nil
+	}
+	return TargetRoot(gi, t)
+}
+
+// MountOverlay mounts the layer of an overlay target over its root with
+// overlay_mount_command, unless something is mounted there already.
+func MountOverlay(ctx context.Context, gi dbq.GameInstall, t dbq.Target) error {
+	root, mounted, err := overlayRoot(gi, t)
+	if err != nil || mounted {
+		return err
+	}
+
+	dir := OverlayDir(t)
+	if dryrun.Enabled() {
+		dryrun.Note("mount the overlay %s over %s", dir, root)
+		return nil
+	}
+	for _, sub := range []string{"files", "upper", "work"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return fmt.Errorf("create overlay: %w", err)
+		}
+	}
+
+	if err := runOverlayCommand(ctx, "overlay_mount_command", map[string]string{
+		"root":  root,
+		"files": filepath.Join(dir, "files"),
+		"upper": filepath.Join(dir, "upper"),
+		"work":  filepath.Join(dir, "work"),
+	}); err != nil {
+		return fmt.Errorf("mount the overlay of target %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+// UnmountOverlay unmounts the overlay of a target with
+// overlay_unmount_command, if it's mounted.
+func UnmountOverlay(ctx context.Context, gi dbq.GameInstall, t dbq.Target) error {
+	root, mounted, err := overlayRoot(gi, t)
+	if err != nil || !mounted {
+		return err
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Note("unmount the overlay over %s", root)
+		return nil
+	}
+	if err := runOverlayCommand(ctx, "overlay_unmount_command", map[string]string{"root": root}); err != nil {
+		return fmt.Errorf("unmount the overlay of target %s (is the game still running?): %w", t.Name, err)
+	}
+	return nil
+}
+
+// overlayRoot returns the root of an overlay target and whether something is
+// mounted on it.
+func overlayRoot(gi dbq.GameInstall, t dbq.Target) (string, bool, error) {
+	root, err := TargetRoot(gi, t)
+	if err != nil {
+		return "", false, err
+	}
+	mounted, err := mountPoint(root)
+	if err != nil {
+		return "", false, fmt.Errorf("target %s: %w", t.Name, err)
+	}
+	return root, mounted, nil
+}
+
+func runOverlayCommand(ctx context.Context, key string, tv map[string]string) error {
+	command, err := vars.ExpandCommand(viper.GetStringSlice(key), tv)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	out, err := exec.CommandContext(ctx, command[0], command[1:]...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s failed: %w: %s", command[0], err, msg)
+		}
+		return fmt.Errorf("%s failed: %w", command[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMountedOn(t *testing.T) {
+	t.Parallel()
+
+	mountinfo := []byte(`22 1 0:21 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+61 22 0:52 / /home/user/Steam\040Library/common/Skyrim rw,nosuid,nodev shared:30 - fuse.fuse-overlayfs fuse-overlayfs rw
+`)
+
+	assert.True(t, mountedOn(mountinfo, "/"))
+	assert.True(t, mountedOn(mountinfo, "/home/user/Steam Library/common/Skyrim"))
+	assert.False(t, mountedOn(mountinfo, "/home/user/Steam Library/common"))
+	assert.False(t, mountedOn([]byte("garbage\n"), "/"))
+}
@@ -0,0 +1,289 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/spf13/viper"
+)
+
+var overrideVarPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// overrideBuiltinVars are the variables that modctl provides to override
+// templates (when they exist for the install), on top of the ones from the
+// override_vars config option.
+var overrideBuiltinVars = append(slices.Clone(targetVarNames),
+	"game_version", "install_root_windows", "profile", "instance")
+
+// SetOverride stores the file at srcPath as the (full file) override of
+// relpath in the given target for a profile, replacing any previous
+// override of the same path. The ${name} variables of a template override
+// are expanded for the install whenever it's deployed (see OverrideVars).
+func SetOverride(
+	ctx context.Context,
+	db *sql.DB,
+	q *dbq.Queries,
+	bs blobstore.Store,
+	profileID, targetID int64,
+	relpath, srcPath, notes string,
+	template bool,
+) (string, error) {
+	var isTemplate int64
+	if template {
+		isTemplate = 1
+		content, err := os.ReadFile(srcPath)
+		if err != nil {
+			return "", err
+		}
+		if !utf8.Valid(content) {
+			return "", fmt.Errorf("%s: a template has to be a text file", srcPath)
+		}
+		if _, err := expandOverride(content, func(string) (string, bool) { return "", true }); err != nil {
+			return "", fmt.Errorf("%s: %w", srcPath, err)
+		}
+	}
+
+	// filesystem first: an unreferenced override blob is harmless
+	res, err := bs.IngestFile(ctx, blobstore.KindOverride, srcPath)
+	if err != nil {
+		return "", fmt.Errorf("ingest override: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	base := filepath.Base(relpath)
+	if err := blobstore.EnsureBlobRecorded(ctx, qtx, res.SHA256Hex,
+		string(blobstore.KindOverride), res.SizeBytes, &base); err != nil {
+		return "", err
+	}
+
+	if err := qtx.UpsertOverride(ctx, dbq.UpsertOverrideParams{
+		ProfileID:  profileID,
+		TargetID:   targetID,
+		Relpath:    relpath,
+		BlobSha256: res.SHA256Hex,
+		Notes:      sql.NullString{String: notes, Valid: notes != ""},
+		IsTemplate: isTemplate,
+	}); err != nil {
+		return "", fmt.Errorf("store override: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit: %w", err)
+	}
+
+	return res.SHA256Hex, nil
+}
+
+// UnsetOverride removes the override of relpath in the given target from a
+// profile, and its blob unless something else uses it. Overrides that are
+// deployed can't be removed (the installed files still belong to them).
+func UnsetOverride(
+	ctx context.Context,
+	db *sql.DB,
+	q *dbq.Queries,
+	bs blobstore.Store,
+	profileID, targetID int64,
+	relpath string,
+) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	o, err := qtx.GetOverrideForPath(ctx, dbq.GetOverrideForPathParams{
+		ProfileID: profileID,
+		TargetID:  targetID,
+		Relpath:   relpath,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s has no override", relpath)
+		}
+		return fmt.Errorf("get override: %w", err)
+	}
+
+	deployed, err := qtx.CountInstalledFilesForOverride(ctx, sql.NullInt64{Int64: o.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("count installed files: %w", err)
+	}
+	if deployed > 0 {
+		return fmt.Errorf("the override of %s is deployed: unapply the profile first (`modctl profiles unapply`)", relpath)
+	}
+
+	if err := qtx.DeleteOverride(ctx, o.ID); err != nil {
+		return fmt.Errorf("delete override: %w", err)
+	}
+
+	refs, err := qtx.CountBlobReferences(ctx, o.BlobSha256)
+	if err != nil {
+		return fmt.Errorf("count blob references: %w", err)
+	}
+	if refs == 0 {
+		if err := qtx.DeleteBlob(ctx, o.BlobSha256); err != nil {
+			return fmt.Errorf("delete blob: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	// filesystem last: if this fails the file is just unreferenced
+	if refs == 0 {
+		if err := bs.Remove(blobstore.KindOverride, o.BlobSha256); err != nil {
+			return fmt.Errorf("remove override blob: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// OverrideVars returns the variables of the override templates of a profile
+// deployed to a game install: the target variables (see TargetVars), the
+// profile name, the install instance, the install root as a windows path
+// for proton games, and whatever the override_vars config option sets, e.g.:
+//
+//	[override_vars]
+//	resolution = "1920x1080"
+//
+//	[override_vars."steam:489830"]
+//	resolution = "2560x1440"
+//
+//	[override_vars."steam:489830#deck"]
+//	resolution = "1280x800"
+//
+// The most specific table wins.
+func OverrideVars(gi dbq.GameInstall, p dbq.Profile) (map[string]string, error) {
+	v := TargetVars(gi)
+	v["profile"] = p.Name
+	v["instance"] = gi.InstanceID
+	if _, ok := v["prefix"]; ok {
+		v["install_root_windows"] = WindowsPath(gi.InstallRoot)
+	}
+
+	configured := viper.GetStringMap("override_vars")
+	tables := []map[string]any{configured}
+	for _, k := range []string{
+		strings.ToLower(gi.StoreID + ":" + gi.StoreGameID),
+		strings.ToLower(FullSelector(gi.StoreID, gi.StoreGameID, gi.InstanceID)),
+	} {
+		if m, ok := configured[k].(map[string]any); ok {
+			tables = append(tables, m)
+		}
+	}
+
+	for _, t := range tables {
+		if err := overrideVarsTable(t, v); err != nil {
+			return nil, fmt.Errorf("override_vars: %w", err)
+		}
+	}
+
+	return v, nil
+}
+
+// overrideVarsTable adds the variables of an override_vars table to v,
+// skipping the (per game) tables in it.
+func overrideVarsTable(t map[string]any, v map[string]string) error {
+	for name, val := range t {
+		if _, ok := val.(map[string]any); ok {
+			continue
+		}
+		s, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", name)
+		}
+		if slices.Contains(overrideBuiltinVars, name) || !overrideVarPattern.MatchString(name) {
+			return fmt.Errorf("invalid variable name %q", name)
+		}
+		v[name] = s
+	}
+	return nil
+}
+
+// RenderOverride expands the ${name} variables of an override template.
+// $${ is a literal ${. Unknown variables are an error so that a template
+// never deploys half expanded.
+func RenderOverride(content []byte, vars map[string]string) ([]byte, error) {
+	return expandOverride(content, func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+}
+
+func expandOverride(content []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	var b bytes.Buffer
+	rest := content
+	line := 1
+	for {
+		i := bytes.Index(rest, []byte("${"))
+		if i < 0 {
+			b.Write(rest)
+			break
+		}
+
+		line += bytes.Count(rest[:i], []byte("\n"))
+		if i > 0 && rest[i-1] == '$' {
+			// escaped: drop one of the dollar signs
+			b.Write(rest[:i-1])
+			b.WriteString("${")
+			rest = rest[i+2:]
+			continue
+		}
+
+		j := bytes.IndexByte(rest[i:], '}')
+		if j < 0 {
+			return nil, fmt.Errorf("line %d: unterminated variable", line)
+		}
+		name := string(rest[i+2 : i+j])
+		if !overrideVarPattern.MatchString(name) {
+			return nil, fmt.Errorf("line %d: invalid variable ${%s} (use $${ for a literal ${)", line, name)
+		}
+		val, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown variable ${%s}", line, name)
+		}
+
+		b.Write(rest[:i])
+		b.WriteString(val)
+		rest = rest[i+j+1:]
+	}
+
+	return b.Bytes(), nil
+}
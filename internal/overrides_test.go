@@ -0,0 +1,78 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderOverride(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]string{
+		"install_root": "/games/Skyrim",
+		"resolution":   "1920x1080",
+	}
+
+	out, err := RenderOverride([]byte("[Display]\nres=${resolution}\npath=${install_root}/Data\nkeep=$${resolution}\n$$x\n"), vars)
+	require.NoError(t, err)
+	assert.Equal(t, "[Display]\nres=1920x1080\npath=/games/Skyrim/Data\nkeep=${resolution}\n$$x\n", string(out))
+
+	out, err = RenderOverride([]byte("no variables"), vars)
+	require.NoError(t, err)
+	assert.Equal(t, "no variables", string(out))
+
+	_, err = RenderOverride([]byte("a\nb\nres=${nope}\n"), vars)
+	assert.EqualError(t, err, "line 3: unknown variable ${nope}")
+
+	_, err = RenderOverride([]byte("a\nres=${screen size}\n"), vars)
+	assert.EqualError(t, err, "line 2: invalid variable ${screen size} (use $${ for a literal ${)")
+
+	_, err = RenderOverride([]byte("res=${resolution"), vars)
+	assert.EqualError(t, err, "line 1: unterminated variable")
+}
+
+func TestCheckOverrideVars(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkOverrideVars(map[string]any{
+		"resolution": "1920x1080",
+		"steam:489830": map[string]any{
+			"resolution": "2560x1440",
+		},
+		"steam:489830#deck": map[string]any{
+			"resolution": "1280x800",
+		},
+	}))
+
+	for _, m := range []map[string]any{
+		{"resolution": 1080},
+		{"profile": "x"},
+		{"install_root": "/games"},
+		{"bad-name": "x"},
+		{"game": map[string]any{"resolution": "1280x800"}},
+		{"steam:489830": map[string]any{"resolution": 1080}},
+		{"steam:489830": map[string]any{"nested": map[string]any{}}},
+	} {
+		assert.Error(t, checkOverrideVars(m), m)
+	}
+}
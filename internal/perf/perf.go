@@ -0,0 +1,154 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package perf implements the global --profile-perf flag: while it's enabled
+// the time spent in the database, hashing files, extracting archives, and
+// running other external commands is added up so that it can be reported
+// once the command is done.
+package perf
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Category is something that a command spends time on.
+type Category string
+
+const (
+	DB         Category = "database"
+	Hashing    Category = "hashing"
+	Extraction Category = "extraction"
+	Commands   Category = "external commands"
+)
+
+// categories is the order of the report.
+var categories = []Category{DB, Hashing, Extraction, Commands}
+
+// Stat is the time spent on a category.
+type Stat struct {
+	Category Category
+	Calls    int
+	Total    time.Duration
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	started time.Time
+	stats   map[Category]*Stat
+)
+
+// Enable starts timing (again) for the rest of the command.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	enabled = true
+	started = time.Now()
+	stats = map[Category]*Stat{}
+}
+
+// Enabled reports whether time is being tracked.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Add records that d was spent on c.
+func Add(c Category, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !enabled {
+		return
+	}
+	s, ok := stats[c]
+	if !ok {
+		s = &Stat{Category: c}
+		stats[c] = s
+	}
+	s.Calls++
+	s.Total += d
+}
+
+// Track starts timing something in c and returns the function that stops
+// it, e.g., defer perf.Track(perf.Hashing)().
+func Track(c Category) func() {
+	if !Enabled() {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { Add(c, time.Since(start)) }
+}
+
+// Report writes where the time went since Enable to w and turns timing off
+// again. It doesn't do anything if timing isn't enabled.
+func Report(w io.Writer) {
+	mu.Lock()
+	if !enabled {
+		mu.Unlock()
+		return
+	}
+	total := time.Since(started)
+	var ss []Stat
+	for _, c := range categories {
+		if s, ok := stats[c]; ok {
+			ss = append(ss, *s)
+		}
+	}
+	enabled = false
+	stats = nil
+	mu.Unlock()
+
+	Write(w, total, ss)
+}
+
+// Write writes the report of a command that took total.
+func Write(w io.Writer, total time.Duration, stats []Stat) {
+	fmt.Fprintf(w, "perf: %s total\n", round(total))
+
+	var tracked time.Duration
+	for _, s := range stats {
+		tracked += s.Total
+		fmt.Fprintf(w, "  %-18s %10s  %5.1f%%  (%d calls)\n", s.Category,
+			round(s.Total), percent(s.Total, total), s.Calls)
+	}
+
+	// the categories can overlap (e.g., hashing a file while reading
+	// the rows of a query) so there might not be anything left
+	other := max(total-tracked, 0)
+	fmt.Fprintf(w, "  %-18s %10s  %5.1f%%\n", "other", round(other), percent(other, total))
+}
+
+func round(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+func percent(d, total time.Duration) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(d) / float64(total) * 100
+}
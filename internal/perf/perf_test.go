@@ -0,0 +1,78 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package perf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	Write(&buf, 2*time.Second, []Stat{
+		{Category: DB, Calls: 120, Total: 500 * time.Millisecond},
+		{Category: Hashing, Calls: 3, Total: 1 * time.Second},
+	})
+
+	assert.Equal(t, `perf: 2s total
+  database                500ms   25.0%  (120 calls)
+  hashing                    1s   50.0%  (3 calls)
+  other                   500ms   25.0%
+`, buf.String())
+}
+
+func TestWriteOverlapping(t *testing.T) {
+	var buf bytes.Buffer
+	Write(&buf, time.Second, []Stat{
+		{Category: DB, Calls: 1, Total: 700 * time.Millisecond},
+		{Category: Hashing, Calls: 1, Total: 700 * time.Millisecond},
+	})
+
+	assert.Contains(t, buf.String(), "  other                      0s    0.0%\n")
+}
+
+func TestTrack(t *testing.T) {
+	t.Cleanup(func() { enabled = false; stats = nil })
+
+	// nothing is recorded while disabled
+	Track(DB)()
+	Add(Hashing, time.Second)
+	assert.Nil(t, stats)
+
+	Enable()
+	Track(DB)()
+	Add(Hashing, time.Second)
+	Add(Hashing, time.Second)
+
+	assert.Equal(t, 1, stats[DB].Calls)
+	assert.Equal(t, 2, stats[Hashing].Calls)
+	assert.Equal(t, 2*time.Second, stats[Hashing].Total)
+
+	var buf bytes.Buffer
+	Report(&buf)
+	assert.Contains(t, buf.String(), "perf: ")
+	assert.False(t, Enabled())
+
+	buf.Reset()
+	Report(&buf)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,125 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// How the symlinks in an archive are deployed (see Item.Symlinks).
+const (
+	// SymlinksCopy deploys a copy of the target of links to files in the
+	// same archive; links that point elsewhere are skipped with a warning.
+	SymlinksCopy = "copy"
+	// SymlinksSkip doesn't deploy symlinks at all.
+	SymlinksSkip = "skip"
+)
+
+// maxLinkHops is how many links a link can go through before we give up on
+// resolving it (e.g., because of a cycle).
+const maxLinkHops = 16
+
+// resolveLinks applies the symlink policy to the (normalized) members of an
+// archive. It returns the members that are left and, for the links among
+// them, the member that they resolve to. links has the targets of the links
+// by their raw name.
+func resolveLinks(members []string, links map[string]string, policy string) ([]string, map[string]string, []string) {
+	if len(links) == 0 {
+		return members, nil, nil
+	}
+
+	targets := map[string]string{}
+	for raw, target := range links {
+		m, err := NormalizeRelPath(raw)
+		if err != nil {
+			// normalizeMembers already skipped it
+			continue
+		}
+		targets[m] = target
+	}
+
+	isFile := map[string]bool{}
+	dirs := map[string]bool{}
+	for _, m := range members {
+		if _, ok := targets[m]; ok {
+			continue
+		}
+		isFile[m] = true
+		for d := path.Dir(m); d != "."; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+
+	var out, warnings []string
+	sources := map[string]string{}
+	for _, m := range members {
+		if _, ok := targets[m]; !ok {
+			out = append(out, m)
+			continue
+		}
+		if policy == SymlinksSkip {
+			continue
+		}
+
+		src, err := resolveLink(m, targets, isFile, dirs)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipping symlink %q: %v", m, err))
+			continue
+		}
+		sources[m] = src
+		out = append(out, m)
+	}
+
+	return out, sources, warnings
+}
+
+func resolveLink(m string, targets map[string]string, isFile, dirs map[string]bool) (string, error) {
+	for range maxLinkHops {
+		target := strings.ReplaceAll(targets[m], `\`, "/")
+		if strings.HasPrefix(target, "/") || (len(target) >= 2 && target[1] == ':') {
+			return "", fmt.Errorf("it points outside of the archive (%s)", targets[m])
+		}
+
+		joined := path.Join(path.Dir(m), target)
+		if joined == ".." || strings.HasPrefix(joined, "../") {
+			return "", fmt.Errorf("it points outside of the archive (%s)", targets[m])
+		}
+
+		next, err := NormalizeRelPath(joined)
+		if err != nil {
+			return "", fmt.Errorf("invalid target %q: %w", targets[m], err)
+		}
+
+		switch {
+		case isFile[next]:
+			return next, nil
+		case dirs[next]:
+			return "", fmt.Errorf("it points to a directory (%s)", targets[m])
+		}
+		if _, ok := targets[next]; !ok {
+			return "", fmt.Errorf("its target %s isn't in the archive", targets[m])
+		}
+		m = next
+	}
+
+	return "", errors.New("too many levels of symlinks")
+}
@@ -0,0 +1,156 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeRelPath cleans a path from an archive (or a remap rule) into a
+// relative, slash-separated path and rejects anything that could escape the
+// target root.
+//
+// Every part of the path is made canonical too, so that archives made on
+// other systems deploy the same way everywhere: names are NFC-normalized
+// (macOS writes NFD), invalid UTF-8 is replaced with U+FFFD, and trailing
+// spaces and dots are removed (like Windows does, so mod authors never saw
+// them). Names with control characters are rejected.
+func NormalizeRelPath(p string) (string, error) {
+	p = strings.ReplaceAll(p, `\`, "/")
+
+	if strings.HasPrefix(p, "/") {
+		return "", errors.New("absolute path")
+	}
+	if len(p) >= 2 && p[1] == ':' {
+		return "", errors.New("absolute path (drive letter)")
+	}
+
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." {
+			return "", errors.New("path traversal")
+		}
+	}
+
+	p = path.Clean(p)
+	p = strings.TrimPrefix(p, "./")
+	if p == "." || p == "" {
+		return "", errors.New("empty path")
+	}
+
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		name, err := canonicalName(part)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = name
+	}
+
+	return strings.Join(parts, "/"), nil
+}
+
+func canonicalName(name string) (string, error) {
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return "", errors.New("control character in name")
+		}
+	}
+
+	name = strings.ToValidUTF8(name, "\uFFFD")
+	name = norm.NFC.String(name)
+
+	// only dots (e.g., "...") would be a different directory
+	trimmed := strings.TrimRight(name, " .")
+	if trimmed == "" {
+		return "", fmt.Errorf("invalid name %q", name)
+	}
+
+	return trimmed, nil
+}
+
+// windowsReserved are the device names that can't be used as file names on
+// Windows (with any extension).
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// NameWarnings reports what NormalizeRelPath changes about a path from an
+// archive (other than the NFC normalization, which is invisible) and the
+// names in it that are reserved on Windows, where (and under Proton) games
+// can't open them.
+func NameWarnings(p string) []string {
+	var warnings []string
+
+	if !utf8.ValidString(p) {
+		warnings = append(warnings, fmt.Sprintf("%q: invalid UTF-8 replaced with U+FFFD", p))
+	}
+
+	for _, part := range strings.Split(strings.ReplaceAll(p, `\`, "/"), "/") {
+		if part == "" || part == "." || part == ".." {
+			continue
+		}
+		if strings.TrimRight(part, " .") != part {
+			warnings = append(warnings, fmt.Sprintf("%q: trailing spaces or dots removed from %q", p, part))
+		}
+		if reservedName(part) {
+			warnings = append(warnings, fmt.Sprintf("%q: %q is a reserved name on Windows", p, part))
+		}
+	}
+
+	return warnings
+}
+
+// WindowsNameError returns an error if a (normalized) relpath can't be
+// created on Windows (or on a filesystem with its rules, like an SMB share
+// or vfat): one of its names is reserved or has a character that isn't
+// allowed in names.
+func WindowsNameError(relpath string) error {
+	for _, part := range strings.Split(relpath, "/") {
+		if reservedName(part) {
+			return fmt.Errorf("%q is a reserved name on Windows", part)
+		}
+		if i := strings.IndexFunc(part, func(r rune) bool {
+			return r < 0x20 || strings.ContainsRune(`<>:"|?*`, r)
+		}); i >= 0 {
+			return fmt.Errorf("%q has a character that isn't allowed on Windows (%q)", part, part[i])
+		}
+	}
+	return nil
+}
+
+// reservedName reports whether a name is one of the windowsReserved ones,
+// with or without an extension.
+func reservedName(part string) bool {
+	base, _, _ := strings.Cut(strings.TrimRight(part, " ."), ".")
+	return windowsReserved[strings.ToUpper(strings.TrimRight(base, " "))]
+}
+
+func isDirEntry(name string) bool {
+	return strings.HasSuffix(name, "/") || strings.HasSuffix(name, `\`)
+}
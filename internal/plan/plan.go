@@ -0,0 +1,366 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package plan computes the desired state of a game install from a profile:
+// which file (from which mod) ends up at every destination path.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultTarget is the target that files are deployed into unless something
+// says otherwise.
+const DefaultTarget = "game_dir"
+
+// Item is an enabled profile item to deploy.
+type Item struct {
+	ProfileItemID int64
+	VersionID     int64
+	ArchiveSHA256 string
+	Priority      int64
+	ModName       string
+	FileLabel     string
+
+	// remap rules configured for the item (in order); if there are none the
+	// game's Handler decides where the files go
+	Rules []Rule
+
+	// destination paths (normalized) of files that the item doesn't deploy
+	// so that lower priority items win them
+	Hidden []string
+
+	// what to do with the symlinks in the archive (SymlinksCopy if empty)
+	Symlinks string
+}
+
+// Source is a file that an item provides.
+type Source struct {
+	Item   *Item
+	Member string // path inside of the archive
+	// for a symlink that is deployed as a copy: the member that it points
+	// to (Member is the link itself)
+	LinkTarget string
+}
+
+// File returns the member that has the contents of the source, i.e., the
+// target of a symlink.
+func (s Source) File() string {
+	if s.LinkTarget != "" {
+		return s.LinkTarget
+	}
+	return s.Member
+}
+
+// File is a destination path and the item that wins it.
+type File struct {
+	Target  string
+	RelPath string
+	Winner  Source
+	// lower priority items that provide the same path (highest first)
+	Shadowed []Source
+}
+
+// ItemResult describes how the files of an item were mapped.
+type ItemResult struct {
+	Item   *Item
+	Layout string // how the handler classified the archive (or "remap")
+	Files  int    // files that the item provides
+	Won    int    // files that the item wins
+	Hidden int    // files that are hidden
+}
+
+// Note is a problem with the plan that a game handler found, e.g., files
+// that conflict even though they are deployed to different paths.
+type Note struct {
+	Kind    string // e.g., "script_merge"
+	Key     string // what the note is about (e.g., the conflicting script)
+	Message string
+	Sources []Source
+}
+
+// Plan is the result of planning a profile.
+type Plan struct {
+	Files    []File
+	Items    []ItemResult
+	Notes    []Note
+	Warnings []string
+}
+
+// Conflicts returns the files that more than one item provides.
+func (p *Plan) Conflicts() []File {
+	var out []File
+	for _, f := range p.Files {
+		if len(f.Shadowed) > 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ConflictMatrix is how many files the items that conflict win over each
+// other: Won[i][j] is the number of files that Items[i] provides and wins
+// over Items[j], which provides them too.
+type ConflictMatrix struct {
+	Items []*Item // highest priority first
+	Won   [][]int
+}
+
+// Lost returns the number of files that Items[i] provides but loses to other
+// items.
+func (m ConflictMatrix) Lost(i int) int {
+	n := 0
+	for j := range m.Items {
+		n += m.Won[j][i]
+	}
+	return n
+}
+
+// ConflictMatrix returns which items win how many files over which other
+// items. Only the items that take part in a conflict are in it.
+func (p *Plan) ConflictMatrix() ConflictMatrix {
+	var m ConflictMatrix
+	index := map[*Item]int{}
+	add := func(it *Item) {
+		if _, ok := index[it]; !ok {
+			index[it] = len(m.Items)
+			m.Items = append(m.Items, it)
+		}
+	}
+
+	type pair struct{ winner, loser *Item }
+	won := map[pair]int{}
+	for _, f := range p.Files {
+		for _, s := range f.Shadowed {
+			if s.Item == f.Winner.Item {
+				continue
+			}
+			add(f.Winner.Item)
+			add(s.Item)
+			won[pair{f.Winner.Item, s.Item}]++
+		}
+	}
+
+	sort.SliceStable(m.Items, func(i, j int) bool {
+		return m.Items[i].Priority > m.Items[j].Priority
+	})
+	for i, it := range m.Items {
+		index[it] = i
+	}
+
+	m.Won = make([][]int, len(m.Items))
+	for i := range m.Won {
+		m.Won[i] = make([]int, len(m.Items))
+	}
+	for pr, n := range won {
+		m.Won[index[pr.winner]][index[pr.loser]] = n
+	}
+
+	return m
+}
+
+// Mapped is an archive member and where it should be deployed.
+type Mapped struct {
+	Member  string
+	Target  string
+	RelPath string
+}
+
+// Handler customizes planning for a specific game.
+type Handler interface {
+	// Name identifies the handler (e.g., "generic", "cyberpunk2077").
+	Name() string
+
+	// Map decides where the files of an item without remap rules go.
+	// members are the (normalized) files in the item's archive. It returns
+	// the mapped files and a short description of the detected layout.
+	Map(item *Item, members []string) ([]Mapped, string)
+}
+
+// Analyzer is implemented by handlers that know about conflicts (or other
+// problems) that planning by path alone can't see.
+type Analyzer interface {
+	Analyze(p *Plan) []Note
+}
+
+// Generic deploys archives as-is into the game directory.
+type Generic struct{}
+
+func (Generic) Name() string { return "generic" }
+
+func (Generic) Map(item *Item, members []string) ([]Mapped, string) {
+	return Identity(members, ""), "as-is"
+}
+
+// Identity maps every member to the same relpath in the default target,
+// optionally under a prefix.
+func Identity(members []string, prefix string) []Mapped {
+	out := make([]Mapped, 0, len(members))
+	for _, m := range members {
+		rel := m
+		if prefix != "" {
+			rel = prefix + "/" + m
+		}
+		out = append(out, Mapped{Member: m, Target: DefaultTarget, RelPath: rel})
+	}
+	return out
+}
+
+// ListFunc returns the member names of an archive (by sha256) and the targets
+// of the symlinks among them.
+type ListFunc func(ctx context.Context, sha256 string) ([]string, map[string]string, error)
+
+// Build computes the plan for the given items. Items with a higher priority
+// win conflicts.
+func Build(ctx context.Context, items []Item, list ListFunc, h Handler) (*Plan, error) {
+	if h == nil {
+		h = Generic{}
+	}
+
+	ordered := make([]*Item, 0, len(items))
+	for i := range items {
+		ordered = append(ordered, &items[i])
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority > ordered[j].Priority
+	})
+
+	type key struct{ target, relpath string }
+	files := map[key]*File{}
+	p := &Plan{}
+
+	for _, it := range ordered {
+		raw, links, err := list(ctx, it.ArchiveSHA256)
+		if err != nil {
+			return nil, fmt.Errorf("list archive of %s / %s (v%d): %w",
+				it.ModName, it.FileLabel, it.VersionID, err)
+		}
+
+		members, warnings := normalizeMembers(raw)
+		members, linkTargets, linkWarnings := resolveLinks(members, links, it.Symlinks)
+		for _, w := range append(warnings, linkWarnings...) {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("v%d: %s", it.VersionID, w))
+		}
+
+		var mapped []Mapped
+		layout := "remap"
+		if len(it.Rules) > 0 {
+			mapped, err = ApplyRules(it.Rules, members)
+			if err != nil {
+				return nil, fmt.Errorf("remap rules of v%d: %w", it.VersionID, err)
+			}
+		} else {
+			mapped, layout = h.Map(it, members)
+		}
+
+		hidden := map[string]bool{}
+		for _, h := range it.Hidden {
+			hidden[h] = true
+		}
+
+		res := ItemResult{Item: it, Layout: layout}
+		for _, m := range mapped {
+			rel, err := NormalizeRelPath(m.RelPath)
+			if err != nil {
+				p.Warnings = append(p.Warnings, fmt.Sprintf("v%d: skipping %q: %v", it.VersionID, m.Member, err))
+				continue
+			}
+			if hidden[rel] {
+				res.Hidden++
+				continue
+			}
+
+			target := m.Target
+			if target == "" {
+				target = DefaultTarget
+			}
+
+			res.Files++
+			k := key{target, rel}
+			src := Source{Item: it, Member: m.Member, LinkTarget: linkTargets[m.Member]}
+			if f, ok := files[k]; ok {
+				if f.Winner.Item == it {
+					// the same item maps two members to one path:
+					// keep the first one
+					p.Warnings = append(p.Warnings, fmt.Sprintf("v%d: %q and %q both map to %s",
+						it.VersionID, f.Winner.Member, m.Member, rel))
+					res.Files--
+					continue
+				}
+				f.Shadowed = append(f.Shadowed, src)
+				continue
+			}
+			files[k] = &File{Target: target, RelPath: rel, Winner: src}
+			res.Won++
+		}
+
+		p.Items = append(p.Items, res)
+	}
+
+	p.Files = make([]File, 0, len(files))
+	for _, f := range files {
+		p.Files = append(p.Files, *f)
+	}
+	sort.Slice(p.Files, func(i, j int) bool {
+		if p.Files[i].Target != p.Files[j].Target {
+			return p.Files[i].Target < p.Files[j].Target
+		}
+		return p.Files[i].RelPath < p.Files[j].RelPath
+	})
+
+	if a, ok := h.(Analyzer); ok {
+		p.Notes = a.Analyze(p)
+	}
+
+	return p, nil
+}
+
+// normalizeMembers drops directories and unsafe paths from an archive
+// listing and normalizes the rest.
+func normalizeMembers(raw []string) ([]string, []string) {
+	var members, warnings []string
+	seen := map[string]string{}
+
+	for _, r := range raw {
+		if isDirEntry(r) {
+			continue
+		}
+		m, err := NormalizeRelPath(r)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipping %q: %v", r, err))
+			continue
+		}
+		warnings = append(warnings, NameWarnings(r)...)
+		if first, ok := seen[m]; ok {
+			// e.g., "a.txt" and "./a.txt" are the same file but "A.txt"
+			// and "A.txt " only are once they're normalized
+			if strings.TrimPrefix(first, "./") != strings.TrimPrefix(r, "./") {
+				warnings = append(warnings, fmt.Sprintf("%q and %q are both %s once normalized (the first one by name is used)",
+					first, r, m))
+			}
+			continue
+		}
+		seen[m] = r
+		members = append(members, m)
+	}
+
+	return members, warnings
+}
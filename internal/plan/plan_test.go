@@ -0,0 +1,349 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRelPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"Data/textures/a.dds", "Data/textures/a.dds", false},
+		{"./Data//meshes/b.nif", "Data/meshes/b.nif", false},
+		{`Data\scripts\c.pex`, "Data/scripts/c.pex", false},
+		{"/etc/passwd", "", true},
+		{`C:\Windows\x.dll`, "", true},
+		{"../escape.txt", "", true},
+		{"Data/../../escape.txt", "", true},
+		{"Data/./ok.txt", "Data/ok.txt", false},
+		{".", "", true},
+		{"", "", true},
+		// canonical names
+		{"Data/Cafe\u0301.esp", "Data/Caf\u00e9.esp", false},
+		{"Data/bad\xff.dds", "Data/bad\ufffd.dds", false},
+		{"Data /textures./a.dds ", "Data/textures/a.dds", false},
+		{"Data/.../a.dds", "", true},
+		{"Data/a\tb.dds", "", true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NormalizeRelPath(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNameWarnings(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, NameWarnings("Data/textures/a.dds"))
+	assert.Empty(t, NameWarnings("Data/Cafe\u0301.esp"))
+	assert.Empty(t, NameWarnings("Data/console.txt"))
+	assert.Empty(t, NameWarnings("./Data/a.dds"))
+
+	assert.Len(t, NameWarnings("Data/bad\xff.dds"), 1)
+	assert.Len(t, NameWarnings("Data /a.dds."), 2)
+	assert.Len(t, NameWarnings(`Data\aux.ini`), 1)
+	assert.Len(t, NameWarnings("Data/COM1"), 1)
+}
+
+func TestWindowsNameError(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, WindowsNameError("Data/textures/a.dds"))
+	assert.NoError(t, WindowsNameError("Data/console.txt"))
+
+	assert.Error(t, WindowsNameError("Data/aux.ini"))
+	assert.Error(t, WindowsNameError("LPT1/a.txt"))
+	assert.Error(t, WindowsNameError("Data/what?.txt"))
+	assert.Error(t, WindowsNameError("Data/a:b.txt"))
+	assert.Error(t, WindowsNameError("Data/tab\there.txt"))
+}
+
+func TestNormalizeMembers(t *testing.T) {
+	t.Parallel()
+
+	members, warnings := normalizeMembers([]string{
+		"Data/", "Data/a.esp", "./Data/a.esp", "Data/b.esp", "Data/b.esp ",
+	})
+
+	assert.Equal(t, []string{"Data/a.esp", "Data/b.esp"}, members)
+	// the trailing space and the two names for Data/b.esp
+	assert.Len(t, warnings, 2)
+}
+
+func TestApplyRules(t *testing.T) {
+	t.Parallel()
+
+	members := []string{
+		"MyMod/Data/a.esp",
+		"MyMod/Data/textures/b.dds",
+		"MyMod/readme.txt",
+	}
+
+	tests := []struct {
+		name    string
+		rules   []Rule
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "no rules",
+			rules: nil,
+			want:  members,
+		},
+		{
+			name:  "strip components",
+			rules: []Rule{{Type: RuleStripComponents, Int: 1}},
+			want:  []string{"Data/a.esp", "Data/textures/b.dds", "readme.txt"},
+		},
+		{
+			name:  "strip drops shallow files",
+			rules: []Rule{{Type: RuleStripComponents, Int: 2}},
+			want:  []string{"a.esp", "textures/b.dds"},
+		},
+		{
+			name:  "select subdir (case-insensitive)",
+			rules: []Rule{{Type: RuleSelectSubdir, Text: "mymod/data"}},
+			want:  []string{"a.esp", "textures/b.dds"},
+		},
+		{
+			name: "select then prefix",
+			rules: []Rule{
+				{Type: RuleSelectSubdir, Text: "MyMod/Data"},
+				{Type: RuleDestPrefix, Text: "Data"},
+			},
+			want: []string{"Data/a.esp", "Data/textures/b.dds"},
+		},
+		{
+			name:  "exclude by file name",
+			rules: []Rule{{Type: RuleExcludeGlob, Text: "*.TXT"}},
+			want:  []string{"MyMod/Data/a.esp", "MyMod/Data/textures/b.dds"},
+		},
+		{
+			name:  "include by path",
+			rules: []Rule{{Type: RuleIncludeGlob, Text: "MyMod/Data/*"}},
+			want:  []string{"MyMod/Data/a.esp"},
+		},
+		{
+			name:    "traversal in prefix",
+			rules:   []Rule{{Type: RuleDestPrefix, Text: "../x"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown rule",
+			rules:   []Rule{{Type: "bogus"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ApplyRules(tt.rules, members)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			rels := make([]string, 0, len(got))
+			for _, m := range got {
+				rels = append(rels, m.RelPath)
+			}
+			assert.Equal(t, tt.want, rels)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	archives := map[string][]string{
+		"low":  {"Data/", "Data/a.esp", "Data/shared.dds", "../evil.txt"},
+		"high": {"Data/shared.dds", "Data/b.esp"},
+	}
+	list := func(ctx context.Context, sha string) ([]string, map[string]string, error) {
+		return archives[sha], nil, nil
+	}
+
+	items := []Item{
+		{VersionID: 1, ArchiveSHA256: "low", Priority: 1, ModName: "Low"},
+		{VersionID: 2, ArchiveSHA256: "high", Priority: 2, ModName: "High"},
+	}
+
+	p, err := Build(context.Background(), items, list, nil)
+	assert.NoError(t, err)
+
+	rels := []string{}
+	for _, f := range p.Files {
+		rels = append(rels, f.RelPath)
+	}
+	assert.Equal(t, []string{"Data/a.esp", "Data/b.esp", "Data/shared.dds"}, rels)
+
+	conflicts := p.Conflicts()
+	if assert.Len(t, conflicts, 1) {
+		assert.Equal(t, "Data/shared.dds", conflicts[0].RelPath)
+		assert.Equal(t, int64(2), conflicts[0].Winner.Item.VersionID)
+		assert.Equal(t, int64(1), conflicts[0].Shadowed[0].Item.VersionID)
+	}
+
+	// highest priority first
+	assert.Equal(t, int64(2), p.Items[0].Item.VersionID)
+	assert.Equal(t, 2, p.Items[1].Files)
+	assert.Equal(t, 1, p.Items[1].Won)
+
+	assert.Len(t, p.Warnings, 1)
+}
+
+func TestResolveLinks(t *testing.T) {
+	t.Parallel()
+
+	members := []string{
+		"Data/a.esp", "Data/textures/b.dds",
+		"Data/copy.esp", "Data/chain.esp", "Data/tex.dds", "Data/dir",
+		"Data/abs", "Data/up", "Data/dangling", "Data/loop",
+	}
+	links := map[string]string{
+		"Data/copy.esp":    "a.esp",
+		"./Data/chain.esp": "copy.esp",
+		"Data/tex.dds":     `textures\b.dds`,
+		"Data/dir":         "textures",
+		"Data/abs":         "/etc/passwd",
+		"Data/up":          "../../outside.txt",
+		"Data/dangling":    "missing.esp",
+		"Data/loop":        "loop",
+	}
+
+	out, sources, warnings := resolveLinks(members, links, SymlinksCopy)
+	assert.Equal(t, []string{
+		"Data/a.esp", "Data/textures/b.dds",
+		"Data/copy.esp", "Data/chain.esp", "Data/tex.dds",
+	}, out)
+	assert.Equal(t, map[string]string{
+		"Data/copy.esp":  "Data/a.esp",
+		"Data/chain.esp": "Data/a.esp",
+		"Data/tex.dds":   "Data/textures/b.dds",
+	}, sources)
+	assert.Len(t, warnings, 5)
+
+	out, sources, warnings = resolveLinks(members, links, SymlinksSkip)
+	assert.Equal(t, []string{"Data/a.esp", "Data/textures/b.dds"}, out)
+	assert.Empty(t, sources)
+	assert.Empty(t, warnings)
+
+	out, _, _ = resolveLinks(members[:2], nil, "")
+	assert.Equal(t, members[:2], out)
+}
+
+func TestBuildHidden(t *testing.T) {
+	t.Parallel()
+
+	archives := map[string][]string{
+		"low":  {"Data/shared.dds", "Data/a.esp"},
+		"high": {"Data/shared.dds", "Data/b.esp"},
+	}
+	list := func(ctx context.Context, sha string) ([]string, map[string]string, error) {
+		return archives[sha], nil, nil
+	}
+
+	items := []Item{
+		{VersionID: 1, ArchiveSHA256: "low", Priority: 1},
+		{VersionID: 2, ArchiveSHA256: "high", Priority: 2, Hidden: []string{"Data/shared.dds"}},
+	}
+
+	p, err := Build(context.Background(), items, list, nil)
+	assert.NoError(t, err)
+
+	assert.Len(t, p.Files, 3)
+	assert.Empty(t, p.Conflicts())
+	for _, f := range p.Files {
+		if f.RelPath == "Data/shared.dds" {
+			assert.Equal(t, int64(1), f.Winner.Item.VersionID)
+		}
+	}
+
+	assert.Equal(t, 1, p.Items[0].Files)
+	assert.Equal(t, 1, p.Items[0].Hidden)
+}
+
+func TestConflictMatrix(t *testing.T) {
+	t.Parallel()
+
+	archives := map[string][]string{
+		"low":  {"Data/a.dds", "Data/b.dds", "Data/c.dds", "Data/low.esp"},
+		"mid":  {"Data/a.dds", "Data/b.dds", "Data/mid.esp"},
+		"high": {"Data/a.dds", "Data/high.esp"},
+		"solo": {"Data/solo.esp"},
+	}
+	list := func(ctx context.Context, sha string) ([]string, map[string]string, error) {
+		return archives[sha], nil, nil
+	}
+
+	items := []Item{
+		{VersionID: 1, ArchiveSHA256: "low", Priority: 1, ModName: "Low"},
+		{VersionID: 2, ArchiveSHA256: "mid", Priority: 2, ModName: "Mid"},
+		{VersionID: 3, ArchiveSHA256: "high", Priority: 3, ModName: "High"},
+		{VersionID: 4, ArchiveSHA256: "solo", Priority: 4, ModName: "Solo"},
+	}
+
+	p, err := Build(context.Background(), items, list, nil)
+	assert.NoError(t, err)
+
+	m := p.ConflictMatrix()
+
+	names := []string{}
+	for _, it := range m.Items {
+		names = append(names, it.ModName)
+	}
+	assert.Equal(t, []string{"High", "Mid", "Low"}, names)
+
+	// a.dds: high over mid and low; b.dds: mid over low
+	assert.Equal(t, [][]int{
+		{0, 1, 1},
+		{0, 0, 1},
+		{0, 0, 0},
+	}, m.Won)
+
+	assert.Equal(t, 0, m.Lost(0))
+	assert.Equal(t, 1, m.Lost(1))
+	assert.Equal(t, 2, m.Lost(2))
+}
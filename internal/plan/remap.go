@@ -0,0 +1,154 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package plan
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Remap rule types (see remap_rules).
+const (
+	RuleStripComponents = "strip_components"
+	RuleSelectSubdir    = "select_subdir"
+	RuleDestPrefix      = "dest_prefix"
+	RuleIncludeGlob     = "include_glob"
+	RuleExcludeGlob     = "exclude_glob"
+)
+
+// Rule is a single remap rule.
+type Rule struct {
+	Type string
+	Int  int64
+	Text string
+}
+
+// ApplyRules maps archive members to relpaths in the default target by
+// applying the rules in order:
+//
+//   - strip_components N: remove the first N path segments (members with
+//     fewer segments are dropped)
+//   - select_subdir dir: only keep members under dir, relative to it
+//   - dest_prefix dir: put everything under dir
+//   - include_glob pattern: only keep members matching the pattern
+//   - exclude_glob pattern: drop members matching the pattern
+//
+// Globs are matched (case-insensitively) against the whole path as it is at
+// that point and also against the file name, so "*.esp" matches at any
+// depth.
+func ApplyRules(rules []Rule, members []string) ([]Mapped, error) {
+	type cur struct{ member, rel string }
+
+	files := make([]cur, 0, len(members))
+	for _, m := range members {
+		files = append(files, cur{m, m})
+	}
+
+	for _, r := range rules {
+		next := files[:0]
+		switch r.Type {
+		case RuleStripComponents:
+			if r.Int < 0 {
+				return nil, fmt.Errorf("strip_components must not be negative")
+			}
+			for _, f := range files {
+				parts := strings.Split(f.rel, "/")
+				if int64(len(parts)) <= r.Int {
+					continue
+				}
+				f.rel = strings.Join(parts[r.Int:], "/")
+				next = append(next, f)
+			}
+
+		case RuleSelectSubdir:
+			dir, err := NormalizeRelPath(r.Text)
+			if err != nil {
+				return nil, fmt.Errorf("select_subdir %q: %w", r.Text, err)
+			}
+			for _, f := range files {
+				if rest, ok := cutDirPrefix(f.rel, dir); ok {
+					f.rel = rest
+					next = append(next, f)
+				}
+			}
+
+		case RuleDestPrefix:
+			dir, err := NormalizeRelPath(r.Text)
+			if err != nil {
+				return nil, fmt.Errorf("dest_prefix %q: %w", r.Text, err)
+			}
+			for _, f := range files {
+				f.rel = dir + "/" + f.rel
+				next = append(next, f)
+			}
+
+		case RuleIncludeGlob, RuleExcludeGlob:
+			if _, err := path.Match(strings.ToLower(r.Text), ""); err != nil {
+				return nil, fmt.Errorf("%s %q: %w", r.Type, r.Text, err)
+			}
+			include := r.Type == RuleIncludeGlob
+			for _, f := range files {
+				if GlobMatch(r.Text, f.rel) == include {
+					next = append(next, f)
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown remap rule %q", r.Type)
+		}
+		files = next
+	}
+
+	out := make([]Mapped, 0, len(files))
+	for _, f := range files {
+		out = append(out, Mapped{Member: f.member, Target: DefaultTarget, RelPath: f.rel})
+	}
+
+	return out, nil
+}
+
+// GlobMatch reports whether a relpath matches a glob pattern, either as a
+// whole or (for patterns without a "/") by its file name. Matching is
+// case-insensitive.
+func GlobMatch(pattern, rel string) bool {
+	pattern = strings.ToLower(pattern)
+	rel = strings.ToLower(rel)
+
+	if ok, _ := path.Match(pattern, rel); ok {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return false
+}
+
+// cutDirPrefix returns rel relative to dir (compared case-insensitively) if
+// rel is inside of dir.
+func cutDirPrefix(rel, dir string) (string, bool) {
+	if len(rel) <= len(dir)+1 || rel[len(dir)] != '/' {
+		return "", false
+	}
+	if !strings.EqualFold(rel[:len(dir)], dir) {
+		return "", false
+	}
+	return rel[len(dir)+1:], true
+}
@@ -0,0 +1,162 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/loot"
+	"github.com/mfinelli/modctl/internal/vars"
+	"github.com/spf13/viper"
+)
+
+// PluginsTxt returns the LOOT game and the path to the plugins.txt of a game
+// install, or an error if we don't manage the load order of the game.
+func PluginsTxt(gi dbq.GameInstall) (loot.Game, string, error) {
+	g, ok := loot.Game{}, false
+	if gi.StoreID == "steam" {
+		g, ok = loot.ForSteamApp(gi.StoreGameID)
+	}
+	if !ok {
+		return loot.Game{}, "", fmt.Errorf("%s doesn't have a plugin load order that modctl can manage", gi.DisplayName)
+	}
+
+	prefix, err := SteamProtonPrefix(gi)
+	if err != nil {
+		return loot.Game{}, "", err
+	}
+
+	return g, g.PluginsPath(prefix), nil
+}
+
+// LoadPluginOrder returns the stored load order of a profile (nil if it
+// doesn't have one yet).
+func LoadPluginOrder(ctx context.Context, q *dbq.Queries, profileID int64) ([]loot.Plugin, error) {
+	rows, err := q.ListPluginOrderForProfile(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("list plugin order: %w", err)
+	}
+
+	var plugins []loot.Plugin
+	for _, r := range rows {
+		plugins = append(plugins, loot.Plugin{Name: r.PluginName, Enabled: r.Enabled != 0})
+	}
+
+	return plugins, nil
+}
+
+// SavePluginOrder replaces the stored load order of a profile. It should be
+// called inside of a transaction.
+func SavePluginOrder(ctx context.Context, q *dbq.Queries, profileID int64, plugins []loot.Plugin, source string) error {
+	if err := q.DeletePluginOrderForProfile(ctx, profileID); err != nil {
+		return fmt.Errorf("delete plugin order: %w", err)
+	}
+
+	for i, p := range plugins {
+		enabled := int64(0)
+		if p.Enabled {
+			enabled = 1
+		}
+		if err := q.InsertPluginOrderEntry(ctx, dbq.InsertPluginOrderEntryParams{
+			ProfileID:  profileID,
+			Position:   int64(i),
+			PluginName: p.Name,
+			Enabled:    enabled,
+			Source:     source,
+		}); err != nil {
+			return fmt.Errorf("insert plugin %q: %w", p.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SortPlugins runs LOOT (the loot_command config option) on the load order
+// of the given profile and stores the result. It returns the load order from
+// before and after sorting.
+func SortPlugins(ctx context.Context, db *sql.DB, q *dbq.Queries, gi dbq.GameInstall, p dbq.Profile) (before, after []loot.Plugin, err error) {
+	g, pluginsTxt, err := PluginsTxt(gi)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefix, err := SteamProtonPrefix(gi)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := os.Stat(prefix); errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("proton prefix %s doesn't exist; run the game once first", prefix)
+	}
+
+	// LOOT sorts whatever is in plugins.txt: start it from our order
+	before, err = LoadPluginOrder(ctx, q, p.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(before) > 0 {
+		if err := loot.WritePlugins(pluginsTxt, before); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	command, err := vars.ExpandCommand(viper.GetStringSlice("loot_command"), map[string]string{
+		"game":        g.LootID,
+		"game_path":   gi.InstallRoot,
+		"local_path":  filepath.Dir(pluginsTxt),
+		"plugins_txt": pluginsTxt,
+		"prefix":      prefix,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("loot_command: %w", err)
+	}
+
+	if err := loot.Run(ctx, command); err != nil {
+		return nil, nil, err
+	}
+
+	sorted, err := loot.ReadPlugins(pluginsTxt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read sorted load order: %w", err)
+	}
+	if len(sorted) == 0 {
+		return nil, nil, fmt.Errorf("LOOT didn't write a load order to %s", pluginsTxt)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := SavePluginOrder(ctx, q.WithTx(tx), p.ID, sorted, "loot"); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return before, sorted, nil
+}
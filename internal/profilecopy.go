@@ -0,0 +1,349 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// ProfileCopyResult is what CopyProfile made.
+type ProfileCopyResult struct {
+	ProfileID int64
+	Items     int
+
+	// how the mod versions of the items were found on the other install: an
+	// imported version of the same archive, a new version under the mod page
+	// with the same nexus mod, or a new version under a new mod page
+	Reused  int
+	Linked  int
+	Created int
+
+	Overrides int
+	Plugins   int
+	// overrides of targets (target/relpath) that the other install doesn't
+	// have
+	SkippedOverrides []string
+}
+
+// SameGame reports whether two game installs are installs of the same game:
+// the same canonical game if both know it, the same store game otherwise.
+func SameGame(a, b dbq.GameInstall) bool {
+	if a.CanonicalGameID.Valid && b.CanonicalGameID.Valid {
+		return a.CanonicalGameID.String == b.CanonicalGameID.String
+	}
+	return a.StoreID == b.StoreID && a.StoreGameID == b.StoreGameID
+}
+
+// CopyProfile copies a profile to another install of the same game (see
+// SameGame) under the given name, keeping the order, state, remap rules, and
+// hidden files of its items, its plugin order, and its overrides.
+//
+// The archives are in the blob store already, only the mods need to exist on
+// the other install: an item uses the version of the other install with the
+// same archive if there is one; otherwise a version of the archive is created
+// there, under the mod page with the same nexus mod (and the file with the
+// same nexus file or label) if it has one, or under a copy of the mod page.
+// Everything happens in a single transaction.
+func CopyProfile(ctx context.Context, db *sql.DB, q *dbq.Queries, from dbq.GameInstall, p dbq.Profile, to dbq.GameInstall, name string) (res ProfileCopyResult, err error) {
+	if from.ID == to.ID {
+		return res, fmt.Errorf("%s is the install that the profile is on", to.DisplayName)
+	}
+	if !SameGame(from, to) {
+		return res, fmt.Errorf("%s and %s aren't installs of the same game",
+			from.DisplayName, to.DisplayName)
+	}
+
+	items, err := q.ListProfileItemsForCopy(ctx, p.ID)
+	if err != nil {
+		return res, fmt.Errorf("list profile items: %w", err)
+	}
+	hidden, err := q.ListHiddenFilesForProfile(ctx, p.ID)
+	if err != nil {
+		return res, fmt.Errorf("list hidden files: %w", err)
+	}
+	plugins, err := q.ListPluginOrderForProfile(ctx, p.ID)
+	if err != nil {
+		return res, fmt.Errorf("list plugin order: %w", err)
+	}
+	overrides, err := q.ListOverridesForProfile(ctx, p.ID)
+	if err != nil {
+		return res, fmt.Errorf("list overrides: %w", err)
+	}
+	targets, err := q.ListTargetsForGameInstall(ctx, to.ID)
+	if err != nil {
+		return res, fmt.Errorf("list targets: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	// the game version is only comparable on the same store (a steam build id
+	// means nothing to a gog copy)
+	gameVersion := p.GameVersion
+	if from.StoreID != to.StoreID {
+		gameVersion = sql.NullString{}
+	}
+	res.ProfileID, err = qtx.CreateProfile(ctx, dbq.CreateProfileParams{
+		GameInstallID: to.ID,
+		Name:          name,
+		Description:   p.Description,
+		GameVersion:   gameVersion,
+	})
+	if err != nil {
+		var se sqlite3.Error
+		if errors.As(err, &se) && se.Code == sqlite3.ErrConstraint && se.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return res, fmt.Errorf("profile %q already exists for %s", name, to.DisplayName)
+		}
+		return res, fmt.Errorf("create profile: %w", err)
+	}
+
+	pages := map[int64]copiedPage{}
+	newItems := map[int64]int64{}
+	for _, it := range items {
+		versionID, how, err := copyModFileVersion(ctx, qtx, to.ID, it, pages)
+		if err != nil {
+			return res, fmt.Errorf("copy %s / %s: %w", it.ModName, it.FileLabel, err)
+		}
+		switch how {
+		case copyReused:
+			res.Reused++
+		case copyLinked:
+			res.Linked++
+		default:
+			res.Created++
+		}
+
+		var remap sql.NullInt64
+		if it.RemapConfigID.Valid {
+			id, err := qtx.CreateRemapConfig(ctx)
+			if err != nil {
+				return res, fmt.Errorf("create remap config: %w", err)
+			}
+			if err := qtx.CopyRemapRules(ctx, dbq.CopyRemapRulesParams{
+				NewRemapConfigID: id,
+				RemapConfigID:    it.RemapConfigID.Int64,
+			}); err != nil {
+				return res, fmt.Errorf("copy remap rules: %w", err)
+			}
+			remap = sql.NullInt64{Int64: id, Valid: true}
+		}
+
+		newItems[it.ID], err = qtx.CopyProfileItem(ctx, dbq.CopyProfileItemParams{
+			ProfileID:        res.ProfileID,
+			ModFileVersionID: versionID,
+			Enabled:          it.Enabled,
+			Priority:         it.Priority,
+			Symlinks:         it.Symlinks,
+			RemapConfigID:    remap,
+			Notes:            it.Notes,
+		})
+		if err != nil {
+			return res, fmt.Errorf("add %s / %s: %w", it.ModName, it.FileLabel, err)
+		}
+		res.Items++
+	}
+
+	for _, h := range hidden {
+		if _, err := qtx.HideProfileItemFile(ctx, dbq.HideProfileItemFileParams{
+			ProfileItemID: newItems[h.ProfileItemID],
+			Relpath:       h.Relpath,
+		}); err != nil {
+			return res, fmt.Errorf("hide %s: %w", h.Relpath, err)
+		}
+	}
+
+	for _, pl := range plugins {
+		if err := qtx.InsertPluginOrderEntry(ctx, dbq.InsertPluginOrderEntryParams{
+			ProfileID:  res.ProfileID,
+			Position:   pl.Position,
+			PluginName: pl.PluginName,
+			Enabled:    pl.Enabled,
+			Source:     pl.Source,
+		}); err != nil {
+			return res, fmt.Errorf("insert plugin %q: %w", pl.PluginName, err)
+		}
+		res.Plugins++
+	}
+
+	targetIDs := map[string]int64{}
+	for _, t := range targets {
+		targetIDs[t.Name] = t.ID
+	}
+	for _, o := range overrides {
+		tid, ok := targetIDs[o.TargetName]
+		if !ok {
+			res.SkippedOverrides = append(res.SkippedOverrides, o.TargetName+"/"+o.Relpath)
+			continue
+		}
+		if err := qtx.UpsertOverride(ctx, dbq.UpsertOverrideParams{
+			ProfileID:  res.ProfileID,
+			TargetID:   tid,
+			Relpath:    o.Relpath,
+			BlobSha256: o.BlobSha256,
+			Notes:      o.Notes,
+			IsTemplate: o.IsTemplate,
+		}); err != nil {
+			return res, fmt.Errorf("copy override %s/%s: %w", o.TargetName, o.Relpath, err)
+		}
+		res.Overrides++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, fmt.Errorf("commit: %w", err)
+	}
+	return res, nil
+}
+
+// copiedPage is the mod page of the other install for a mod page of the
+// profile.
+type copiedPage struct {
+	ID      int64
+	Created bool
+}
+
+type copyMatch int
+
+const (
+	copyReused copyMatch = iota
+	copyLinked
+	copyCreated
+)
+
+// copyModFileVersion returns the mod version of the game install for the
+// archive of a profile item, creating it (and its mod page and file) if
+// needed. pages remembers the mod pages that were found or created already.
+func copyModFileVersion(ctx context.Context, qtx *dbq.Queries, gameInstallID int64, it dbq.ListProfileItemsForCopyRow, pages map[int64]copiedPage) (int64, copyMatch, error) {
+	v, err := qtx.FindModFileVersionByArchiveForGame(ctx, dbq.FindModFileVersionByArchiveForGameParams{
+		ArchiveSha256: it.ArchiveSha256,
+		GameInstallID: gameInstallID,
+	})
+	if err == nil {
+		return v.ID, copyReused, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, fmt.Errorf("find archive: %w", err)
+	}
+
+	page, ok := pages[it.ModPageID]
+	if !ok && it.SourceKind == "nexus" && it.NexusGameDomain.Valid && it.NexusModID.Valid {
+		mp, err := qtx.GetModPageByNexus(ctx, dbq.GetModPageByNexusParams{
+			GameInstallID:   gameInstallID,
+			NexusGameDomain: it.NexusGameDomain,
+			NexusModID:      it.NexusModID,
+		})
+		switch {
+		case err == nil:
+			page, ok = copiedPage{ID: mp.ID}, true
+		case !errors.Is(err, sql.ErrNoRows):
+			return 0, 0, fmt.Errorf("find nexus mod: %w", err)
+		}
+	}
+	if !ok {
+		page.Created = true
+		page.ID, err = qtx.CreateModPage(ctx, dbq.CreateModPageParams{
+			GameInstallID:   gameInstallID,
+			Name:            it.ModName,
+			SourceKind:      it.SourceKind,
+			SourceUrl:       it.SourceUrl,
+			SourceRef:       it.SourceRef,
+			NexusGameDomain: it.NexusGameDomain,
+			NexusModID:      it.NexusModID,
+			Notes:           it.PageNotes,
+			Metadata:        it.PageMetadata,
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("create mod_page: %w", err)
+		}
+	}
+	pages[it.ModPageID] = page
+	pageID := page.ID
+
+	var fileID int64
+	err = sql.ErrNoRows
+	if it.NexusFileID.Valid {
+		var mf dbq.GetModFileByNexusFileIDRow
+		mf, err = qtx.GetModFileByNexusFileID(ctx, dbq.GetModFileByNexusFileIDParams{
+			ModPageID:   pageID,
+			NexusFileID: it.NexusFileID,
+		})
+		fileID = mf.ID
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		var mf dbq.GetModFileByLabelRow
+		mf, err = qtx.GetModFileByLabel(ctx, dbq.GetModFileByLabelParams{
+			ModPageID: pageID,
+			Label:     it.FileLabel,
+		})
+		fileID = mf.ID
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		// the first file of a page is its primary one, like on import
+		cnt, err := qtx.CountModFilesForPage(ctx, pageID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("count mod_files: %w", err)
+		}
+		isPrimary := int64(0)
+		if cnt == 0 {
+			isPrimary = 1
+		}
+		fileID, err = qtx.CreateModFile(ctx, dbq.CreateModFileParams{
+			ModPageID:   pageID,
+			Label:       it.FileLabel,
+			IsPrimary:   isPrimary,
+			NexusFileID: it.NexusFileID,
+			Category:    it.Category,
+			SourceUrl:   it.FileSourceUrl,
+			Metadata:    it.FileMetadata,
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("create mod_file: %w", err)
+		}
+	} else if err != nil {
+		return 0, 0, fmt.Errorf("find mod_file: %w", err)
+	}
+
+	id, err := qtx.CreateModFileVersion(ctx, dbq.CreateModFileVersionParams{
+		ModFileID:     fileID,
+		ArchiveSha256: it.ArchiveSha256,
+		OriginalName:  it.OriginalName,
+		VersionString: it.VersionString,
+		UploadedAt:    it.UploadedAt,
+		UpstreamNotes: it.UpstreamNotes,
+		Notes:         it.VersionNotes,
+		Metadata:      it.VersionMetadata,
+		NexusFileID:   it.VersionNexusFileID,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("create mod_file_version: %w", err)
+	}
+	if page.Created {
+		return id, copyCreated, nil
+	}
+	return id, copyLinked, nil
+}
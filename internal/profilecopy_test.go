@@ -0,0 +1,80 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameGame(t *testing.T) {
+	t.Parallel()
+
+	canonical := func(s string) sql.NullString {
+		return sql.NullString{String: s, Valid: true}
+	}
+
+	tests := []struct {
+		name string
+		a, b dbq.GameInstall
+		want bool
+	}{
+		{
+			name: "another steam library",
+			a:    dbq.GameInstall{ID: 1, StoreID: "steam", StoreGameID: "489830"},
+			b:    dbq.GameInstall{ID: 2, StoreID: "steam", StoreGameID: "489830"},
+			want: true,
+		},
+		{
+			name: "another game",
+			a:    dbq.GameInstall{ID: 1, StoreID: "steam", StoreGameID: "489830"},
+			b:    dbq.GameInstall{ID: 2, StoreID: "steam", StoreGameID: "377160"},
+			want: false,
+		},
+		{
+			name: "another store, same canonical game",
+			a:    dbq.GameInstall{ID: 1, StoreID: "steam", StoreGameID: "489830", CanonicalGameID: canonical("skyrimse")},
+			b:    dbq.GameInstall{ID: 2, StoreID: "gog", StoreGameID: "1711230643", CanonicalGameID: canonical("skyrimse")},
+			want: true,
+		},
+		{
+			name: "another store, canonical game unknown",
+			a:    dbq.GameInstall{ID: 1, StoreID: "steam", StoreGameID: "489830", CanonicalGameID: canonical("skyrimse")},
+			b:    dbq.GameInstall{ID: 2, StoreID: "gog", StoreGameID: "1711230643"},
+			want: false,
+		},
+		{
+			name: "same store game, different canonical game",
+			a:    dbq.GameInstall{ID: 1, StoreID: "steam", StoreGameID: "489830", CanonicalGameID: canonical("skyrimse")},
+			b:    dbq.GameInstall{ID: 2, StoreID: "steam", StoreGameID: "489830", CanonicalGameID: canonical("skyrimae")},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, SameGame(tt.a, tt.b))
+		})
+	}
+}
@@ -25,6 +25,10 @@ import (
 	"fmt"
 
 	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/spf13/viper"
 )
 
 func ResolveProfileArg(ctx context.Context, q *dbq.Queries, gi *dbq.GameInstall, arg string) (dbq.Profile, error) {
@@ -35,7 +39,7 @@ func ResolveProfileArg(ctx context.Context, q *dbq.Queries, gi *dbq.GameInstall,
 		})
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				return dbq.Profile{}, fmt.Errorf("profile %q not found for this game", arg)
+				return dbq.Profile{}, ProfileNotFoundError(arg)
 			}
 			return dbq.Profile{}, fmt.Errorf("lookup profile: %w", err)
 		}
@@ -44,7 +48,11 @@ func ResolveProfileArg(ctx context.Context, q *dbq.Queries, gi *dbq.GameInstall,
 		p, err := q.GetActiveProfileForGame(ctx, gi.ID)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				return dbq.Profile{}, fmt.Errorf("no active profile set; run `modctl profiles set-active <name>` or pass --profile")
+				return dbq.Profile{}, &HintError{
+					Kind:  ErrProfileNotFound,
+					Msg:   "no active profile set",
+					Hints: []string{"run `modctl profiles set-active <name>`", "or pass --profile"},
+				}
 			}
 			return dbq.Profile{}, fmt.Errorf("get active profile: %w", err)
 		}
@@ -52,46 +60,97 @@ func ResolveProfileArg(ctx context.Context, q *dbq.Queries, gi *dbq.GameInstall,
 	}
 }
 
-func SetProfileItemEnabled(ctx context.Context, profile *dbq.Profile, q *dbq.Queries, versionID int64, enabled bool) error {
-	// Find the profile item row for this version.
-	item, err := q.GetProfileItemByVersion(ctx, dbq.GetProfileItemByVersionParams{
-		ProfileID:        profile.ID,
-		ModFileVersionID: versionID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("version %d is not in profile %q", versionID, profile.Name)
-		}
-		return fmt.Errorf("lookup profile item: %w", err)
+// CheckProfileUnlocked returns an error if the profile is locked (see modctl
+// profiles lock) and so its mods can't be changed.
+func CheckProfileUnlocked(profile *dbq.Profile) error {
+	if profile.LockedAt.Valid {
+		return fmt.Errorf("profile %q is locked (since %s); run `modctl profiles unlock %s` first",
+			profile.Name, profile.LockedAt.String, profile.Name)
 	}
+	return nil
+}
 
-	want := int64(0)
-	if enabled {
-		want = 1
+// ActivateProfile makes the named profile the active profile of a game.
+func ActivateProfile(ctx context.Context, db *sql.DB, q *dbq.Queries, gameInstallID int64, name string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if item.Enabled == want {
-		// Idempotent.
-		if enabled {
-			fmt.Printf("Version %d is already enabled in profile %q\n", versionID, profile.Name)
-		} else {
-			fmt.Printf("Version %d is already disabled in profile %q\n", versionID, profile.Name)
-		}
-		return nil
+	qtx := q.WithTx(tx)
+
+	if err := qtx.DeactivateProfilesForGame(ctx, gameInstallID); err != nil {
+		return fmt.Errorf("deactivate existing active profile: %w", err)
 	}
 
-	if err := q.SetProfileItemEnabled(ctx, dbq.SetProfileItemEnabledParams{
-		Enabled: want,
-		ID:      item.ID,
+	if err := qtx.ActivateProfileByName(ctx, dbq.ActivateProfileByNameParams{
+		GameInstallID: gameInstallID,
+		Name:          name,
 	}); err != nil {
-		return fmt.Errorf("update enabled: %w", err)
+		return fmt.Errorf("activate profile: %w", err)
 	}
 
-	if enabled {
-		fmt.Printf("Enabled version %d in profile %q\n", versionID, profile.Name)
-	} else {
-		fmt.Printf("Disabled version %d in profile %q\n", versionID, profile.Name)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
 	}
 
 	return nil
 }
+
+// BuildProfilePlan computes the plan for the enabled items of a profile.
+func BuildProfilePlan(ctx context.Context, q *dbq.Queries, profileID int64, h plan.Handler) (*plan.Plan, error) {
+	rows, err := q.ListEnabledProfileItemsForPlan(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("list profile items: %w", err)
+	}
+
+	rules, err := q.ListRemapRulesForProfile(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("list remap rules: %w", err)
+	}
+	rulesByItem := map[int64][]plan.Rule{}
+	for _, r := range rules {
+		rulesByItem[r.ProfileItemID] = append(rulesByItem[r.ProfileItemID], plan.Rule{
+			Type: r.RuleType,
+			Int:  r.IntValue.Int64,
+			Text: r.TextValue.String,
+		})
+	}
+
+	hidden, err := q.ListHiddenFilesForProfile(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("list hidden files: %w", err)
+	}
+	hiddenByItem := map[int64][]string{}
+	for _, h := range hidden {
+		hiddenByItem[h.ProfileItemID] = append(hiddenByItem[h.ProfileItemID], h.Relpath)
+	}
+
+	items := make([]plan.Item, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, plan.Item{
+			ProfileItemID: r.ID,
+			VersionID:     r.ModFileVersionID,
+			ArchiveSHA256: r.ArchiveSha256,
+			Priority:      r.Priority,
+			ModName:       r.ModName,
+			FileLabel:     r.FileLabel,
+			Rules:         rulesByItem[r.ID],
+			Hidden:        hiddenByItem[r.ID],
+			Symlinks:      r.Symlinks,
+		})
+	}
+
+	bs := BlobStoreFromConfig()
+	bsdtar := viper.GetString("bsdtar")
+	list := func(ctx context.Context, sha string) ([]string, map[string]string, error) {
+		p, err := bs.Locate(blobstore.KindArchive, sha)
+		if err != nil {
+			return nil, nil, err
+		}
+		return archive.ListWithLinks(ctx, bsdtar, p)
+	}
+
+	return plan.Build(ctx, items, list, h)
+}
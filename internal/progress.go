@@ -0,0 +1,88 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ProgressBar draws the progress of a long copy (e.g., ingesting a large
+// archive) on a single line: bytes done of the total, throughput, and an
+// estimate of the time left. It only makes sense on a terminal.
+type ProgressBar struct {
+	w     io.Writer
+	label string
+	total int64
+
+	start time.Time
+	drawn time.Time
+	now   func() time.Time
+}
+
+// progressWidth is the number of cells of the bar itself.
+const progressWidth = 30
+
+// NewProgressBar returns a bar for total bytes that draws to w.
+func NewProgressBar(w io.Writer, label string, total int64) *ProgressBar {
+	b := &ProgressBar{w: w, label: label, total: total, now: time.Now}
+	b.start = b.now()
+	return b
+}
+
+// Update redraws the bar with done bytes, at most ten times a second.
+func (b *ProgressBar) Update(done int64) {
+	now := b.now()
+	if done < b.total && now.Sub(b.drawn) < 100*time.Millisecond {
+		return
+	}
+	b.drawn = now
+	fmt.Fprint(b.w, "\r"+b.line(done, now))
+}
+
+// Finish ends the line of the bar.
+func (b *ProgressBar) Finish() {
+	fmt.Fprintln(b.w)
+}
+
+func (b *ProgressBar) line(done int64, now time.Time) string {
+	frac := 1.0
+	if b.total > 0 {
+		frac = min(float64(done)/float64(b.total), 1)
+	}
+	filled := int(frac * progressWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+
+	line := fmt.Sprintf("%s [%s] %3.0f%% %s/%s", b.label, bar, frac*100,
+		FormatBytes(done), FormatBytes(b.total))
+
+	if elapsed := now.Sub(b.start).Seconds(); elapsed > 0 && done > 0 {
+		rate := float64(done) / elapsed
+		line += fmt.Sprintf("  %s/s", FormatBytes(int64(rate)))
+		if done < b.total {
+			eta := time.Duration(float64(b.total-done) / rate * float64(time.Second))
+			line += "  ETA " + eta.Round(time.Second).String()
+		}
+	}
+
+	// pad so that a shorter line overwrites a longer one
+	return line + "   "
+}
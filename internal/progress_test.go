@@ -0,0 +1,55 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressBar(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	var buf bytes.Buffer
+	b := NewProgressBar(&buf, "mod.zip", 4*1024*1024)
+	b.now = func() time.Time { return now }
+	b.start = start
+
+	assert.Equal(t, "mod.zip [                              ]   0% 0 B/4.0 MiB   ",
+		b.line(0, now))
+
+	now = start.Add(2 * time.Second)
+	assert.Equal(t, "mod.zip [===============               ]  50% 2.0 MiB/4.0 MiB  1.0 MiB/s  ETA 2s   ",
+		b.line(2*1024*1024, now))
+	assert.Equal(t, "mod.zip [==============================] 100% 4.0 MiB/4.0 MiB  2.0 MiB/s   ",
+		b.line(4*1024*1024, now))
+
+	// throttled, but the last update is always drawn
+	b.Update(1024 * 1024)
+	b.Update(2 * 1024 * 1024)
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\r")))
+	b.Update(4 * 1024 * 1024)
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\r")))
+}
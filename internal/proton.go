@@ -0,0 +1,117 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/spf13/viper"
+)
+
+// ProtonCommand returns a command that runs a windows executable inside of a
+// steam game's proton prefix. The proton script comes from the proton config
+// option, otherwise we use the newest Proton installed in any steam library.
+func ProtonCommand(ctx context.Context, gi dbq.GameInstall, exe string, args ...string) (*exec.Cmd, error) {
+	prefix, err := SteamProtonPrefix(gi)
+	if err != nil {
+		return nil, err
+	}
+	compatdata := filepath.Dir(prefix)
+	if _, err := os.Stat(compatdata); err != nil {
+		return nil, fmt.Errorf("proton prefix %s not found; run the game once first", compatdata)
+	}
+
+	proton := viper.GetString("proton")
+	if proton == "" {
+		proton, err = findProton(filepath.Dir(filepath.Dir(compatdata)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	steamRoot := ""
+	for _, r := range candidateSteamRoots() {
+		if st, err := os.Stat(r); err == nil && st.IsDir() {
+			steamRoot = r
+			break
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, proton, append([]string{"run", exe}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"STEAM_COMPAT_DATA_PATH="+compatdata,
+		"STEAM_COMPAT_CLIENT_INSTALL_PATH="+steamRoot,
+	)
+
+	return cmd, nil
+}
+
+// findProton looks for the newest proton in the steam library of the game
+// (steamapps/common/Proton*/proton).
+func findProton(steamapps string) (string, error) {
+	candidates, _ := filepath.Glob(filepath.Join(steamapps, "common", "Proton*", "proton"))
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no proton found in %s; set the proton config option", steamapps)
+	}
+
+	// prefer the newest numbered release over "Proton - Experimental"
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return compareProtonVersions(protonVersion(candidates[i]), protonVersion(candidates[j])) > 0
+	})
+
+	return candidates[0], nil
+}
+
+// protonVersion extracts the version numbers from a proton install path
+// (e.g., ".../Proton 9.0 (Beta)/proton" is [9 0]).
+func protonVersion(p string) []int {
+	name := strings.TrimPrefix(filepath.Base(filepath.Dir(p)), "Proton")
+	var v []int
+	for _, f := range strings.FieldsFunc(name, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			break
+		}
+		v = append(v, n)
+	}
+	return v
+}
+
+func compareProtonVersions(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
+
+// WindowsPath returns how a (linux) path looks like inside of a wine prefix,
+// where Z: is mapped to the root of the filesystem.
+func WindowsPath(p string) string {
+	return `Z:` + strings.ReplaceAll(filepath.Clean(p), "/", `\`)
+}
@@ -0,0 +1,355 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/deploy"
+)
+
+// Provenance is where a file in the targets of a game install came from.
+type Provenance string
+
+const (
+	// ProvenanceVanilla files were there when the baseline was recorded and
+	// haven't changed since.
+	ProvenanceVanilla Provenance = "vanilla"
+	// ProvenanceMod files were deployed by modctl and haven't changed since.
+	ProvenanceMod Provenance = "mod"
+	// ProvenanceUser files were created after the baseline was recorded
+	// (e.g., by the user, the game, or another tool).
+	ProvenanceUser Provenance = "user"
+	// ProvenanceUnknown files changed since they were recorded or deployed,
+	// or there is no baseline to compare them to.
+	ProvenanceUnknown Provenance = "unknown"
+)
+
+// scanTarget is a target root to scan.
+type scanTarget struct {
+	id   int64
+	name string
+	root string
+}
+
+// scannedFile is a regular file found in a target.
+type scannedFile struct {
+	targetID int64
+	target   string
+	relpath  string
+	path     string
+	size     int64
+	mtimeNS  int64
+}
+
+// scanTargets lists the regular files below the target roots (that exist).
+// Files below the root of a more specific target (e.g., Data inside of the
+// game directory) belong to that one. Symlinks aren't followed.
+func scanTargets(ctx context.Context, targets []scanTarget) ([]scannedFile, error) {
+	roots := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		roots[filepath.Clean(t.root)] = true
+	}
+
+	var out []scannedFile
+	for _, t := range targets {
+		root := filepath.Clean(t.root)
+		if st, err := os.Stat(root); err != nil || !st.IsDir() {
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, e fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if e.IsDir() {
+				if path != root && roots[path] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !e.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := e.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			out = append(out, scannedFile{
+				targetID: t.id,
+				target:   t.name,
+				relpath:  filepath.ToSlash(rel),
+				path:     path,
+				size:     info.Size(),
+				mtimeNS:  info.ModTime().UnixNano(),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scan target %s: %w", t.name, err)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].path < out[j].path })
+	return out, nil
+}
+
+// knownFile is what modctl knows about a path: what the baseline recorded
+// or what it deployed there.
+type knownFile struct {
+	sha     string
+	size    int64
+	mtimeNS int64 // 0 if unknown
+}
+
+// classify decides the provenance of a file from what was deployed at its
+// path (if anything) and what the baseline recorded there. Unless verify is
+// set, files with the same size and modification time as recorded aren't
+// hashed again.
+func classify(f scannedFile, installed, baseline *knownFile, hasBaseline, verify bool) (Provenance, error) {
+	same := func(k *knownFile) (bool, error) {
+		if f.size != k.size {
+			return false, nil
+		}
+		if !verify && k.mtimeNS != 0 && k.mtimeNS == f.mtimeNS {
+			return true, nil
+		}
+		sha, _, err := deploy.HashFile(f.path)
+		if err != nil {
+			return false, err
+		}
+		return sha == k.sha, nil
+	}
+
+	switch {
+	case installed != nil:
+		ok, err := same(installed)
+		if err != nil || !ok {
+			return ProvenanceUnknown, err
+		}
+		return ProvenanceMod, nil
+	case baseline != nil:
+		ok, err := same(baseline)
+		if err != nil || !ok {
+			return ProvenanceUnknown, err
+		}
+		return ProvenanceVanilla, nil
+	case hasBaseline:
+		return ProvenanceUser, nil
+	default:
+		return ProvenanceUnknown, nil
+	}
+}
+
+// ClassifiedFile is a file in the targets of a game install and where it
+// came from.
+type ClassifiedFile struct {
+	Target     string
+	RelPath    string
+	Size       int64
+	Provenance Provenance
+}
+
+// String returns the file as <target>/<relpath>.
+func (f ClassifiedFile) String() string {
+	return f.Target + "/" + f.RelPath
+}
+
+func gameScanTargets(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall) ([]scanTarget, map[int64]string, error) {
+	rows, err := q.ListTargetsForGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list targets: %w", err)
+	}
+
+	targets := make([]scanTarget, 0, len(rows))
+	roots := make(map[int64]string, len(rows))
+	for _, t := range rows {
+		root, err := TargetRoot(gi, t)
+		if err != nil {
+			return nil, nil, err
+		}
+		targets = append(targets, scanTarget{id: t.ID, name: t.Name, root: root})
+		roots[t.ID] = filepath.Clean(root)
+	}
+	return targets, roots, nil
+}
+
+// RecordBaseline records what is in the targets of a game install, replacing
+// the previous baseline. Files that modctl deployed are recorded as what
+// they replaced (their backup), or not at all if they didn't replace
+// anything. It returns the number of files recorded.
+func RecordBaseline(ctx context.Context, db *sql.DB, q *dbq.Queries, gi dbq.GameInstall) (int, error) {
+	targets, roots, err := gameScanTargets(ctx, q, gi)
+	if err != nil {
+		return 0, err
+	}
+
+	files, err := scanTargets(ctx, targets)
+	if err != nil {
+		return 0, err
+	}
+
+	installed, err := q.ListInstalledFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return 0, fmt.Errorf("list installed files: %w", err)
+	}
+	deployed := map[string]bool{}
+	for _, row := range installed {
+		if root, ok := roots[row.TargetID]; ok {
+			deployed[filepath.Join(root, filepath.FromSlash(row.Relpath))] = true
+		}
+	}
+
+	backups, err := q.ListBackupsForGame(ctx, gi.ID)
+	if err != nil {
+		return 0, fmt.Errorf("list backups: %w", err)
+	}
+
+	var rows []dbq.InsertBaselineFileParams
+	for _, f := range files {
+		if deployed[f.path] {
+			continue
+		}
+		sha, size, err := deploy.HashFile(f.path)
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, dbq.InsertBaselineFileParams{
+			GameInstallID: gi.ID,
+			TargetID:      f.targetID,
+			Relpath:       f.relpath,
+			ContentSha256: sha,
+			SizeBytes:     size,
+			MtimeNs:       sql.NullInt64{Int64: f.mtimeNS, Valid: true},
+		})
+	}
+	for _, b := range backups {
+		rows = append(rows, dbq.InsertBaselineFileParams{
+			GameInstallID: gi.ID,
+			TargetID:      b.TargetID,
+			Relpath:       b.Relpath,
+			ContentSha256: b.BackupBlobSha256,
+			SizeBytes:     b.SizeBytes,
+		})
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+
+	if err := qtx.DeleteBaselineForGame(ctx, gi.ID); err != nil {
+		return 0, fmt.Errorf("delete baseline: %w", err)
+	}
+	for _, r := range rows {
+		if err := qtx.InsertBaselineFile(ctx, r); err != nil {
+			return 0, fmt.Errorf("record %s: %w", r.Relpath, err)
+		}
+	}
+	if err := qtx.SetBaselineScanned(ctx, gi.ID); err != nil {
+		return 0, fmt.Errorf("record baseline: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	AutoOptimize(ctx, db, int64(len(rows)))
+	return len(rows), nil
+}
+
+// ClassifyFiles returns every file in the targets of a game install with its
+// provenance. Set verify to hash every file instead of trusting unchanged
+// sizes and modification times.
+func ClassifyFiles(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall, verify bool) ([]ClassifiedFile, error) {
+	targets, roots, err := gameScanTargets(ctx, q, gi)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := scanTargets(ctx, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := q.ListInstalledFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list installed files: %w", err)
+	}
+	deployed := map[string]*knownFile{}
+	for _, row := range installed {
+		if root, ok := roots[row.TargetID]; ok {
+			deployed[filepath.Join(root, filepath.FromSlash(row.Relpath))] = &knownFile{
+				sha:  row.ContentSha256,
+				size: row.SizeBytes,
+			}
+		}
+	}
+
+	baselineRows, err := q.ListBaselineFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list baseline: %w", err)
+	}
+	baseline := map[string]*knownFile{}
+	for _, row := range baselineRows {
+		if root, ok := roots[row.TargetID]; ok {
+			baseline[filepath.Join(root, filepath.FromSlash(row.Relpath))] = &knownFile{
+				sha:     row.ContentSha256,
+				size:    row.SizeBytes,
+				mtimeNS: row.MtimeNs.Int64,
+			}
+		}
+	}
+	hasBaseline := gi.BaselineScannedAt.Valid
+
+	out := make([]ClassifiedFile, 0, len(files))
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := classify(f, deployed[f.path], baseline[f.path], hasBaseline, verify)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ClassifiedFile{
+			Target:     f.target,
+			RelPath:    f.relpath,
+			Size:       f.size,
+			Provenance: p,
+		})
+	}
+	return out, nil
+}
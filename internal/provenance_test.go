@@ -0,0 +1,100 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTargets(t *testing.T) {
+	t.Parallel()
+
+	game := t.TempDir()
+	write := func(rel, content string) {
+		p := filepath.Join(game, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	write("game.exe", "exe")
+	write("Data/plugin.esp", "esp")
+	write("bin/x64/a.dll", "dll")
+	require.NoError(t, os.Symlink("game.exe", filepath.Join(game, "link.exe")))
+
+	files, err := scanTargets(context.Background(), []scanTarget{
+		{id: 1, name: "game_dir", root: game},
+		{id: 2, name: "data", root: filepath.Join(game, "Data")},
+		{id: 3, name: "missing", root: filepath.Join(game, "nope")},
+	})
+	require.NoError(t, err)
+
+	var got []string
+	for _, f := range files {
+		got = append(got, f.target+"/"+f.relpath)
+	}
+	assert.ElementsMatch(t, []string{"game_dir/game.exe", "game_dir/bin/x64/a.dll", "data/plugin.esp"}, got)
+}
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(p, []byte("hello"), 0o644))
+	mtime := time.Unix(1700000000, 0)
+	require.NoError(t, os.Chtimes(p, mtime, mtime))
+
+	// sha256("hello")
+	const sha = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	f := scannedFile{path: p, size: 5, mtimeNS: mtime.UnixNano()}
+
+	tests := []struct {
+		name        string
+		installed   *knownFile
+		baseline    *knownFile
+		hasBaseline bool
+		verify      bool
+		expected    Provenance
+	}{
+		{"deployed", &knownFile{sha: sha, size: 5}, nil, true, false, ProvenanceMod},
+		{"deployed and changed", &knownFile{sha: "x", size: 5}, nil, true, false, ProvenanceUnknown},
+		{"vanilla", nil, &knownFile{sha: sha, size: 5}, true, false, ProvenanceVanilla},
+		{"vanilla same mtime", nil, &knownFile{sha: "x", size: 5, mtimeNS: mtime.UnixNano()}, true, false, ProvenanceVanilla},
+		{"vanilla same mtime verified", nil, &knownFile{sha: "x", size: 5, mtimeNS: mtime.UnixNano()}, true, true, ProvenanceUnknown},
+		{"vanilla and changed", nil, &knownFile{sha: sha, size: 6}, true, false, ProvenanceUnknown},
+		{"created later", nil, nil, true, false, ProvenanceUser},
+		{"no baseline", nil, nil, false, false, ProvenanceUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := classify(f, tt.installed, tt.baseline, tt.hasBaseline, tt.verify)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
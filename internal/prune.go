@@ -0,0 +1,131 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// PruneCandidate is a mod file version beyond the newest ones of its mod
+// file (see ListPruneCandidates) with the profiles that have it.
+type PruneCandidate struct {
+	dbq.ListPruneCandidatesRow
+
+	// the names of the profiles that have the version, and of the locked
+	// ones among them
+	Profiles []string
+	Locked   []string
+}
+
+// PruneOptions are the settings of PlanPrune.
+type PruneOptions struct {
+	// keep the versions that are in any profile instead of removing them
+	// from their profiles
+	UnreferencedOnly bool
+	// only remove the versions whose nexus file was replaced by a newer
+	// upload
+	SupersededOnly bool
+	// only report what would be removed
+	DryRun bool
+}
+
+// PruneVersion is a candidate and whether it's kept.
+type PruneVersion struct {
+	PruneCandidate
+
+	// why it's kept (e.g., "installed"), empty if it's removed
+	Kept string
+}
+
+// PrunePlan is what prune removes.
+type PrunePlan struct {
+	// every candidate, in order
+	Versions []PruneVersion
+
+	// the versions that are (or with DryRun would be) removed and the
+	// archives that nothing refers to anymore once they are, with their
+	// size
+	Removed  int
+	Orphaned int
+	Freed    int64
+
+	// what to delete: the ids of the versions and the sha256 of the
+	// archives, both empty with DryRun
+	DeleteVersions []int64
+	DeleteArchives []string
+}
+
+// Kept returns the number of candidates that are kept.
+func (p PrunePlan) Kept() int {
+	return len(p.Versions) - p.Removed
+}
+
+// PlanPrune decides which of the candidates are removed. Installed versions
+// and the versions in a locked profile are always kept. An archive is only
+// deleted if every version that uses it is removed and nothing else refers
+// to it (see ArchiveRefs: attachments, overrides, and backups can share the
+// blob).
+func PlanPrune(candidates []PruneCandidate, opts PruneOptions) PrunePlan {
+	var plan PrunePlan
+
+	// archives become unreferenced once every version that uses them is
+	// removed (versions of other mods/games might share the archive)
+	removing := map[string]int64{}
+	for _, c := range candidates {
+		v := PruneVersion{PruneCandidate: c}
+		switch {
+		case c.Installed:
+			v.Kept = "installed"
+		case len(c.Locked) > 0:
+			v.Kept = "in locked profiles " + strings.Join(c.Locked, ", ")
+		case opts.SupersededOnly && !c.Superseded:
+			v.Kept = "not superseded upstream"
+		case len(c.Profiles) > 0 && opts.UnreferencedOnly:
+			v.Kept = "in profiles " + strings.Join(c.Profiles, ", ")
+		default:
+			plan.Removed++
+			removing[c.ArchiveSha256]++
+			if !opts.DryRun {
+				plan.DeleteVersions = append(plan.DeleteVersions, c.ID)
+			}
+		}
+		plan.Versions = append(plan.Versions, v)
+	}
+
+	seen := map[string]bool{}
+	for _, v := range plan.Versions {
+		sha := v.ArchiveSha256
+		if v.Kept != "" || seen[sha] {
+			continue
+		}
+		seen[sha] = true
+		if v.ArchiveRefs > removing[sha] {
+			continue
+		}
+		plan.Orphaned++
+		plan.Freed += v.SizeBytes
+		if !opts.DryRun {
+			plan.DeleteArchives = append(plan.DeleteArchives, sha)
+		}
+	}
+
+	return plan
+}
@@ -0,0 +1,140 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanPrune(t *testing.T) {
+	t.Parallel()
+
+	candidate := func(id int64, sha string, refs int64) PruneCandidate {
+		return PruneCandidate{ListPruneCandidatesRow: dbq.ListPruneCandidatesRow{
+			ID: id, ArchiveSha256: sha, ArchiveRefs: refs, SizeBytes: 100,
+		}}
+	}
+
+	installed := candidate(1, "installed", 1)
+	installed.Installed = true
+	locked := candidate(2, "locked", 1)
+	locked.Profiles = []string{"default", "pinned"}
+	locked.Locked = []string{"pinned"}
+	inProfile := candidate(3, "in-profile", 1)
+	inProfile.Profiles = []string{"default"}
+	candidates := []PruneCandidate{
+		installed,
+		locked,
+		inProfile,
+		// a backup or an override shares the archive's blob
+		candidate(4, "backed-up", 2),
+		candidate(5, "overridden", 2),
+		candidate(6, "unused", 1),
+		// two versions of the same archive
+		candidate(7, "shared", 2),
+		candidate(8, "shared", 2),
+	}
+
+	plan := PlanPrune(candidates, PruneOptions{})
+	require.Len(t, plan.Versions, len(candidates))
+	assert.Equal(t, "installed", plan.Versions[0].Kept)
+	assert.Equal(t, "in locked profiles pinned", plan.Versions[1].Kept)
+	assert.Empty(t, plan.Versions[2].Kept)
+	assert.Equal(t, []int64{3, 4, 5, 6, 7, 8}, plan.DeleteVersions)
+	assert.Equal(t, []string{"in-profile", "unused", "shared"}, plan.DeleteArchives)
+	assert.Equal(t, 6, plan.Removed)
+	assert.Equal(t, 2, plan.Kept())
+	assert.Equal(t, 3, plan.Orphaned)
+	assert.Equal(t, int64(300), plan.Freed)
+
+	plan = PlanPrune(candidates, PruneOptions{UnreferencedOnly: true})
+	assert.Equal(t, "in profiles default", plan.Versions[2].Kept)
+	assert.Equal(t, []int64{4, 5, 6, 7, 8}, plan.DeleteVersions)
+
+	superseded := candidate(9, "superseded", 1)
+	superseded.Superseded = true
+	plan = PlanPrune([]PruneCandidate{candidate(6, "unused", 1), superseded}, PruneOptions{SupersededOnly: true})
+	assert.Equal(t, "not superseded upstream", plan.Versions[0].Kept)
+	assert.Equal(t, []int64{9}, plan.DeleteVersions)
+
+	// a dry run reports the same but deletes nothing
+	plan = PlanPrune(candidates, PruneOptions{DryRun: true})
+	assert.Empty(t, plan.DeleteVersions)
+	assert.Empty(t, plan.DeleteArchives)
+	assert.Equal(t, 6, plan.Removed)
+	assert.Equal(t, 3, plan.Orphaned)
+	assert.Equal(t, int64(300), plan.Freed)
+}
+
+func TestListPruneCandidates(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d, gi, root := testDeployer(t)
+
+	// four versions of a mod file, v4 is the newest
+	var archives []string
+	for i := 1; i <= 4; i++ {
+		sha := writeBlob(t, d, d.Blobs.ArchivesDir, blobstore.KindArchive, fmt.Sprintf("archive %d", i))
+		archives = append(archives, sha)
+		if i == 1 {
+			installModFile(t, d, root, "a.esp", "installed", sha)
+			continue
+		}
+		_, err := d.DB.Exec(`INSERT INTO mod_file_versions (id, mod_file_id, archive_sha256, created_at)
+			VALUES (?, 1, ?, ?)`, i, sha, fmt.Sprintf("2026-01-0%dT00:00:00.000Z", i))
+		require.NoError(t, err)
+	}
+	_, err := d.DB.Exec(`UPDATE mod_file_versions SET created_at = '2026-01-01T00:00:00.000Z' WHERE id = 1`)
+	require.NoError(t, err)
+
+	// a backup shares the archive of v2
+	_, err = d.DB.Exec(`INSERT INTO backups (game_install_id, target_id, relpath, backup_blob_sha256, size_bytes)
+		VALUES (1, 1, 'b.esp', ?, 9)`, archives[1])
+	require.NoError(t, err)
+
+	rows, err := d.Q.ListPruneCandidates(ctx, dbq.ListPruneCandidatesParams{
+		GameInstallID: gi.ID,
+		KeepLatest:    2,
+	})
+	require.NoError(t, err)
+
+	// the two newest are kept
+	candidates := make([]PruneCandidate, len(rows))
+	ids := make([]int64, len(rows))
+	for i, r := range rows {
+		candidates[i] = PruneCandidate{ListPruneCandidatesRow: r}
+		ids[i] = r.ID
+	}
+	assert.Equal(t, []int64{2, 1}, ids)
+	assert.Equal(t, int64(2), rows[0].ArchiveRefs)
+	assert.True(t, rows[1].Installed)
+
+	// v1 is installed and v2's archive is still the backup's
+	plan := PlanPrune(candidates, PruneOptions{})
+	assert.Equal(t, []int64{2}, plan.DeleteVersions)
+	assert.Empty(t, plan.DeleteArchives)
+}
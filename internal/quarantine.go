@@ -0,0 +1,83 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/blobstore"
+)
+
+// QuarantineReport describes a corrupted blob that was quarantined and, for
+// archives, what uses it.
+type QuarantineReport struct {
+	SHA256 string
+	Kind   blobstore.Kind
+	// where the file went
+	Path string
+	// what was wrong with it (set by the caller)
+	Problem string
+
+	Mods     []dbq.ListModsUsingArchiveRow
+	Profiles []dbq.ListProfilesUsingArchiveRow
+}
+
+// QuarantineBlob marks a blob as corrupted and moves its file into the
+// quarantine directory. Importing the archive again puts it back (see
+// importer.ImportArchive). The caller holds the state lock (see LockState).
+func QuarantineBlob(ctx context.Context, q *dbq.Queries, bs blobstore.Store, kind blobstore.Kind, sha string) (QuarantineReport, error) {
+	r := QuarantineReport{SHA256: sha, Kind: kind}
+
+	// the file goes first: a marked blob whose file is still in the store
+	// would be taken as repaired by the next ingest of it; if it can't be
+	// marked the file is put back, so that the store and the database agree
+	path, err := bs.Quarantine(ctx, kind, sha)
+	if err != nil {
+		return r, err
+	}
+	r.Path = path
+
+	if err := q.MarkBlobCorrupted(ctx, dbq.MarkBlobCorruptedParams{
+		CorruptedAt: sql.NullString{String: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"), Valid: true},
+		Sha256:      sha,
+	}); err != nil {
+		if uerr := bs.Unquarantine(ctx, kind, sha); uerr != nil {
+			return r, fmt.Errorf("mark blob corrupted: %w (and moving it back failed: %v)", err, uerr)
+		}
+		r.Path = ""
+		return r, fmt.Errorf("mark blob corrupted: %w", err)
+	}
+
+	if kind != blobstore.KindArchive {
+		return r, nil
+	}
+
+	if r.Mods, err = q.ListModsUsingArchive(ctx, sha); err != nil {
+		return r, fmt.Errorf("list mods: %w", err)
+	}
+	if r.Profiles, err = q.ListProfilesUsingArchive(ctx, sha); err != nil {
+		return r, fmt.Errorf("list profiles: %w", err)
+	}
+
+	return r, nil
+}
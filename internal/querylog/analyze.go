@@ -0,0 +1,127 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package querylog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stat summarizes the log entries of one query.
+type Stat struct {
+	// the sqlc name of the query if it has one
+	Name  string
+	Query string
+
+	Calls int
+	// calls that took at least the threshold given to Summarize
+	Slow   int
+	Errors int
+	Rows   int64
+
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Mean returns how long a call took on average.
+func (s Stat) Mean() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Calls)
+}
+
+// Read parses a query log.
+func Read(r io.Reader) ([]Entry, error) {
+	var out []Entry
+
+	sc := bufio.NewScanner(r)
+	// queries can be long
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	line := 0
+	for sc.Scan() {
+		line++
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		out = append(out, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+var sqlcName = regexp.MustCompile(`^-- name: (\w+) :\w+`)
+
+// Summarize groups the entries by query (ignoring whitespace differences)
+// and orders them by the time spent in them, most first. Calls that took at
+// least slow are counted as slow.
+func Summarize(entries []Entry, slow time.Duration) []Stat {
+	byQuery := map[string]*Stat{}
+	for _, e := range entries {
+		q := strings.Join(strings.Fields(e.Query), " ")
+		s, ok := byQuery[q]
+		if !ok {
+			s = &Stat{Query: q}
+			if m := sqlcName.FindStringSubmatch(q); m != nil {
+				s.Name = m[1]
+			}
+			byQuery[q] = s
+		}
+
+		d := e.Duration()
+		s.Calls++
+		s.Total += d
+		s.Rows += e.Rows
+		s.Max = max(s.Max, d)
+		if d >= slow {
+			s.Slow++
+		}
+		if e.Error != "" {
+			s.Errors++
+		}
+	}
+
+	out := make([]Stat, 0, len(byQuery))
+	for _, s := range byQuery {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Query < out[j].Query
+	})
+
+	return out
+}
@@ -0,0 +1,265 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package querylog records how long every database query takes so that the
+// slow ones can be found on huge libraries (see `modctl db analyze`). It
+// wraps the sqlite driver; each query (once its rows have been read) becomes
+// one JSON line in the log.
+package querylog
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Entry is one line of the query log.
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Query string    `json:"query"`
+	// how long the query took in milliseconds, including reading its rows
+	Millis float64 `json:"ms"`
+	// rows read by a query or changed by a statement
+	Rows  int64  `json:"rows"`
+	Error string `json:"error,omitempty"`
+}
+
+// Duration returns how long the query took.
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.Millis * float64(time.Millisecond))
+}
+
+// Logger writes log entries to w.
+type Logger struct {
+	mu      sync.Mutex
+	w       io.Writer
+	observe func(time.Duration)
+}
+
+// NewLogger returns a Logger that writes to w; w can be nil if the queries
+// only need to be observed (see Observe).
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{w: w}
+}
+
+// Observe makes the logger call fn with how long each query took (e.g., for
+// --profile-perf).
+func (l *Logger) Observe(fn func(time.Duration)) *Logger {
+	l.observe = fn
+	return l
+}
+
+func (l *Logger) log(query string, start time.Time, rows int64, err error) {
+	d := time.Since(start)
+	if l.observe != nil {
+		l.observe(d)
+	}
+	if l.w == nil {
+		return
+	}
+
+	e := Entry{
+		Time:   start.UTC(),
+		Query:  query,
+		Millis: float64(d) / float64(time.Millisecond),
+		Rows:   rows,
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		e.Error = err.Error()
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// it's a debugging aid: losing an entry mustn't fail the query
+	_, _ = l.w.Write(append(b, '\n'))
+}
+
+// Driver wraps next so that the queries of all of its connections are
+// logged to l. The connections of next have to support contexts, like the
+// ones of mattn/go-sqlite3 do.
+func Driver(next driver.Driver, l *Logger) driver.Driver {
+	return &logDriver{next: next, l: l}
+}
+
+type logDriver struct {
+	next driver.Driver
+	l    *Logger
+}
+
+func (d *logDriver) Open(dsn string) (driver.Conn, error) {
+	c, err := d.next.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: c, l: d.l}, nil
+}
+
+type conn struct {
+	driver.Conn
+	l *Logger
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	p, ok := c.Conn.(driver.ConnPrepareContext)
+	if !ok {
+		return nil, unsupported(c.Conn)
+	}
+
+	s, err := p.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: s, query: query, l: c.l}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	b, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return nil, unsupported(c.Conn)
+	}
+	return b.BeginTx(ctx, opts)
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	if errors.Is(err, driver.ErrSkip) {
+		return nil, err
+	}
+	c.l.log(query, start, affected(res, err), err)
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	r, err := q.QueryContext(ctx, query, args)
+	if errors.Is(err, driver.ErrSkip) {
+		return nil, err
+	}
+	if err != nil {
+		c.l.log(query, start, 0, err)
+		return nil, err
+	}
+	return &rows{Rows: r, query: query, start: start, l: c.l}, nil
+}
+
+type stmt struct {
+	driver.Stmt
+	query string
+	l     *Logger
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := s.Stmt.(driver.StmtExecContext)
+	if !ok {
+		return nil, unsupported(s.Stmt)
+	}
+
+	start := time.Now()
+	res, err := e.ExecContext(ctx, args)
+	s.l.log(s.query, start, affected(res, err), err)
+	return res, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := s.Stmt.(driver.StmtQueryContext)
+	if !ok {
+		return nil, unsupported(s.Stmt)
+	}
+
+	start := time.Now()
+	r, err := q.QueryContext(ctx, args)
+	if err != nil {
+		s.l.log(s.query, start, 0, err)
+		return nil, err
+	}
+	return &rows{Rows: r, query: s.query, start: start, l: s.l}, nil
+}
+
+// rows logs the query when it's closed: sqlite only does most of the work
+// while the rows are read.
+type rows struct {
+	driver.Rows
+	query string
+	start time.Time
+	n     int64
+	err   error
+	l     *Logger
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.n++
+	} else {
+		r.err = err
+	}
+	return err
+}
+
+func (r *rows) Close() error {
+	err := r.Rows.Close()
+	r.l.log(r.query, r.start, r.n, r.err)
+	return err
+}
+
+func affected(res driver.Result, err error) int64 {
+	if err != nil {
+		return 0
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func unsupported(v any) error {
+	return fmt.Errorf("querylog: %T doesn't support contexts", v)
+}
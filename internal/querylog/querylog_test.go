@@ -0,0 +1,121 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package querylog
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver(t *testing.T) {
+	var buf bytes.Buffer
+	sql.Register("sqlite3_querylog_test", Driver(&sqlite3.SQLiteDriver{}, NewLogger(&buf)))
+
+	db, err := sql.Open("sqlite3_querylog_test", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE mods (id INTEGER PRIMARY KEY, name TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	stmt, err := db.Prepare("INSERT INTO mods (name) VALUES (?)")
+	require.NoError(t, err)
+	for _, name := range []string{"a", "b", "c"} {
+		_, err = stmt.Exec(name)
+		require.NoError(t, err)
+	}
+	require.NoError(t, stmt.Close())
+
+	var names []string
+	rows, err := db.Query("-- name: ListMods :many\nSELECT name FROM mods ORDER BY id")
+	require.NoError(t, err)
+	for rows.Next() {
+		var n string
+		require.NoError(t, rows.Scan(&n))
+		names = append(names, n)
+	}
+	require.NoError(t, rows.Close())
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	_, err = db.Exec("SELECT * FROM missing")
+	require.Error(t, err)
+
+	entries, err := Read(&buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 6)
+
+	assert.Equal(t, "INSERT INTO mods (name) VALUES (?)", entries[1].Query)
+	assert.EqualValues(t, 1, entries[1].Rows)
+	assert.Equal(t, "-- name: ListMods :many\nSELECT name FROM mods ORDER BY id", entries[4].Query)
+	assert.EqualValues(t, 3, entries[4].Rows)
+	assert.Empty(t, entries[4].Error)
+	assert.Contains(t, entries[5].Error, "no such table")
+	for _, e := range entries {
+		assert.False(t, e.Time.IsZero())
+		assert.GreaterOrEqual(t, e.Millis, 0.0)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	log := strings.Join([]string{
+		`{"time":"2026-01-01T00:00:00Z","query":"-- name: ListMods :many\nSELECT 1","ms":80,"rows":10}`,
+		`{"time":"2026-01-01T00:00:01Z","query":"-- name: ListMods :many\n  SELECT   1","ms":20,"rows":10}`,
+		``,
+		`{"time":"2026-01-01T00:00:02Z","query":"PRAGMA optimize","ms":5}`,
+		`{"time":"2026-01-01T00:00:03Z","query":"SELECT * FROM missing","ms":1,"error":"no such table"}`,
+	}, "\n")
+
+	entries, err := Read(strings.NewReader(log))
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	stats := Summarize(entries, 50*time.Millisecond)
+	require.Len(t, stats, 3)
+
+	assert.Equal(t, "ListMods", stats[0].Name)
+	assert.Equal(t, 2, stats[0].Calls)
+	assert.Equal(t, 1, stats[0].Slow)
+	assert.EqualValues(t, 20, stats[0].Rows)
+	assert.Equal(t, 100*time.Millisecond, stats[0].Total)
+	assert.Equal(t, 80*time.Millisecond, stats[0].Max)
+	assert.Equal(t, 50*time.Millisecond, stats[0].Mean())
+
+	assert.Equal(t, "", stats[1].Name)
+	assert.Equal(t, "PRAGMA optimize", stats[1].Query)
+	assert.Equal(t, 0, stats[1].Slow)
+
+	assert.Equal(t, 1, stats[2].Errors)
+}
+
+func TestReadInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Read(strings.NewReader("{\"query\":\"x\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
@@ -0,0 +1,57 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package readonly implements the global --read-only flag: the database is
+// opened read-only (see internal.SetupDB) and everything that would change
+// the state directory, the blob stores, the keyring, or a game install fails
+// with ErrReadOnly instead, e.g., to inspect a backup copy of the state.
+package readonly
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly is returned for changes that --read-only forbids.
+var ErrReadOnly = errors.New("not allowed with --read-only")
+
+var enabled bool
+
+// Enable turns on read-only mode for the rest of the command.
+func Enable() {
+	enabled = true
+}
+
+// Enabled reports whether nothing may be changed.
+func Enabled() bool {
+	return enabled
+}
+
+// Reset turns read-only mode off again.
+func Reset() {
+	enabled = false
+}
+
+// Check returns ErrReadOnly (for what, e.g., "modctl mods import") if
+// read-only mode is on.
+func Check(what string) error {
+	if enabled {
+		return fmt.Errorf("%s: %w", what, ErrReadOnly)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+// Redacted replaces secret values in reports meant to be shared.
+const Redacted = "[redacted]"
+
+// secret-looking setting names; matched as suffixes of the lowercase key
+var secretKeySuffixes = []string{"key", "password", "secret", "token"}
+
+// IsSecretKey reports whether a setting or environment variable name looks
+// like it holds a credential.
+func IsSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, s := range secretKeySuffixes {
+		if strings.HasSuffix(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactSettings returns a copy of settings (e.g., viper.AllSettings()) with
+// the values of secret-looking keys replaced, descending into nested tables.
+func RedactSettings(settings map[string]any) map[string]any {
+	out := make(map[string]any, len(settings))
+	for k, v := range settings {
+		switch {
+		case IsSecretKey(k):
+			out[k] = Redacted
+		default:
+			if m, ok := v.(map[string]any); ok {
+				out[k] = RedactSettings(m)
+			} else {
+				out[k] = v
+			}
+		}
+	}
+	return out
+}
+
+// RedactEnviron returns the sorted NAME=value pairs of environ whose names
+// start with prefix, with secret values redacted.
+func RedactEnviron(environ []string, prefix string) []string {
+	var out []string
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if IsSecretKey(name) {
+			value = Redacted
+		}
+		out = append(out, name+"="+value)
+	}
+	sort.Strings(out)
+	return out
+}
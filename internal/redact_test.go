@@ -0,0 +1,71 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSettings(t *testing.T) {
+	t.Parallel()
+
+	in := map[string]any{
+		"database":         "/tmp/modctl.db",
+		"nexus_api_key":    "hunter2",
+		"secrets_provider": "keyring",
+		"nexus": map[string]any{
+			"token": "abc",
+			"url":   "https://api.nexusmods.com",
+		},
+	}
+
+	got := RedactSettings(in)
+
+	assert.Equal(t, map[string]any{
+		"database":         "/tmp/modctl.db",
+		"nexus_api_key":    Redacted,
+		"secrets_provider": "keyring",
+		"nexus": map[string]any{
+			"token": Redacted,
+			"url":   "https://api.nexusmods.com",
+		},
+	}, got)
+
+	// the input is left alone
+	assert.Equal(t, "hunter2", in["nexus_api_key"])
+}
+
+func TestRedactEnviron(t *testing.T) {
+	t.Parallel()
+
+	got := RedactEnviron([]string{
+		"PATH=/usr/bin",
+		"MODCTL_NEXUS_API_KEY=hunter2",
+		"MODCTL_DATABASE=/tmp/modctl.db",
+		"MODCTL_EMPTY",
+	}, "MODCTL_")
+
+	assert.Equal(t, []string{
+		"MODCTL_DATABASE=/tmp/modctl.db",
+		"MODCTL_EMPTY=",
+		"MODCTL_NEXUS_API_KEY=" + Redacted,
+	}, got)
+}
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -34,9 +35,12 @@ import (
 	"github.com/adrg/xdg"
 	"github.com/andygrunwald/vdf"
 	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/steam"
 )
 
-func ScanStores(ctx context.Context, db *sql.DB) error {
+// ScanStores refreshes the game installs of all enabled stores. Non-fatal
+// problems are written to w.
+func ScanStores(ctx context.Context, db *sql.DB, w io.Writer) error {
 	q := dbq.New(db)
 	stores, err := q.ListEnabledStores(ctx)
 	if err != nil {
@@ -46,12 +50,16 @@ func ScanStores(ctx context.Context, db *sql.DB) error {
 	for _, store := range stores {
 		switch store.Implementation {
 		case "steam":
-			if err := refreshSteam(ctx, db, q); err != nil {
+			if err := refreshSteam(ctx, db, q, w); err != nil {
+				return err
+			}
+		case SteamShortcutStore:
+			if err := refreshSteamShortcuts(ctx, db, q, w); err != nil {
 				return err
 			}
 		default:
 			// TODO: make this pretty (WARN)
-			fmt.Printf("Implementation %s isn't currently implemented\n",
+			fmt.Fprintf(w, "Implementation %s isn't currently implemented\n",
 				store.Implementation)
 		}
 	}
@@ -59,11 +67,11 @@ func ScanStores(ctx context.Context, db *sql.DB) error {
 	return nil
 }
 
-func refreshSteam(ctx context.Context, db *sql.DB, q *dbq.Queries) error {
-	libs, didScan, warns, err := discoverSteamLibraries()
-	for _, w := range warns {
+func refreshSteam(ctx context.Context, db *sql.DB, q *dbq.Queries, w io.Writer) error {
+	libs, rootByLib, didScan, warns, err := discoverSteamLibraries()
+	for _, warn := range warns {
 		// TODO make this pretty
-		fmt.Printf("WARNING: %s", w)
+		fmt.Fprintf(w, "WARNING: %s\n", warn)
 	}
 	if err != nil {
 		return fmt.Errorf("error scanning for steam libraries: %w", err)
@@ -74,15 +82,44 @@ func refreshSteam(ctx context.Context, db *sql.DB, q *dbq.Queries) error {
 	}
 
 	instanceByLib := assignSteamInstanceIDs(libs)
-	installs, warns, err := discoverSteamInstalls(libs, instanceByLib)
-	for _, w := range warns {
+	installs, warns, err := discoverSteamInstalls(libs, instanceByLib, rootByLib)
+	for _, warn := range warns {
 		// TODO make this pretty
-		fmt.Printf("WARNING: %s", w)
+		fmt.Fprintf(w, "WARNING: %s\n", warn)
 	}
 	if err != nil {
 		return fmt.Errorf("error enumerating steam installs: %w", err)
 	}
 
+	return replaceStoreInstalls(ctx, db, q, "steam", installs)
+}
+
+// refreshSteamShortcuts registers the non-steam games that were added to the
+// steam libraries of the accounts on this machine (see steam.Shortcut).
+func refreshSteamShortcuts(ctx context.Context, db *sql.DB, q *dbq.Queries, w io.Writer) error {
+	// refreshing the steam store already warns about the libraries
+	_, rootByLib, didScan, _, err := discoverSteamLibraries()
+	if err != nil {
+		return fmt.Errorf("error scanning for steam libraries: %w", err)
+	}
+	if !didScan {
+		// discovery did not meaningfully run -> do NOT mark installs missing
+		return nil
+	}
+
+	installs, warns := discoverSteamShortcuts(rootByLib)
+	for _, warn := range warns {
+		// TODO make this pretty
+		fmt.Fprintf(w, "WARNING: %s\n", warn)
+	}
+
+	return replaceStoreInstalls(ctx, db, q, SteamShortcutStore, installs)
+}
+
+// replaceStoreInstalls records the installs that a refresh of a store found
+// (and their default targets and profile); the other installs of the store
+// are marked as not present.
+func replaceStoreInstalls(ctx context.Context, db *sql.DB, q *dbq.Queries, storeID string, installs []dbq.UpsertGameInstallParams) error {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error starting transaction: %w", err)
@@ -90,8 +127,30 @@ func refreshSteam(ctx context.Context, db *sql.DB, q *dbq.Queries) error {
 	defer tx.Rollback()
 	qtx := q.WithTx(tx)
 
-	if err := qtx.MarkStoreInstallsNotPresent(ctx, "steam"); err != nil {
-		return fmt.Errorf("error marking steam installs not present: %w", err)
+	if err := qtx.MarkStoreInstallsNotPresent(ctx, storeID); err != nil {
+		return fmt.Errorf("error marking %s installs not present: %w", storeID, err)
+	}
+
+	// copies (see DuplicateGameInstall) are present as long as their
+	// directory is
+	copies, err := qtx.ListStoreInstallCopies(ctx, storeID)
+	if err != nil {
+		return fmt.Errorf("error listing copies of %s installs: %w", storeID, err)
+	}
+	for _, c := range copies {
+		var present int64
+		if st, err := os.Stat(c.InstallRoot); err == nil && st.IsDir() {
+			present = 1
+		}
+		if present == c.IsPresent {
+			continue
+		}
+		if err := qtx.SetGameInstallPresent(ctx, dbq.SetGameInstallPresentParams{
+			IsPresent: present,
+			ID:        c.ID,
+		}); err != nil {
+			return fmt.Errorf("error updating install_id=%d: %w", c.ID, err)
+		}
 	}
 
 	for _, di := range installs {
@@ -105,6 +164,10 @@ func refreshSteam(ctx context.Context, db *sql.DB, q *dbq.Queries) error {
 			return fmt.Errorf("error upserting target dir: %w", err)
 		}
 
+		if err := upsertTemplateTargets(ctx, qtx, id, di, true); err != nil {
+			return fmt.Errorf("error upserting default targets: %w", err)
+		}
+
 		if err := qtx.EnsureDefaultProfile(ctx, id); err != nil {
 			return fmt.Errorf("error ensuring default profile for install_id=%d: %w", id, err)
 		}
@@ -122,9 +185,10 @@ func refreshSteam(ctx context.Context, db *sql.DB, q *dbq.Queries) error {
 //
 // Returns:
 // - libs: canonicalized, deduped library root paths
+// - rootByLib: the steam root whose libraryfolders.vdf lists each library
 // - didScan: true if at least one libraryfolders.vdf was successfully parsed
 // - warnings: non-fatal issues (missing files, parse errors, etc.)
-func discoverSteamLibraries() ([]string, bool, []string, error) {
+func discoverSteamLibraries() ([]string, map[string]string, bool, []string, error) {
 	roots := candidateSteamRoots()
 	seenRoots := make(map[string]struct{}, len(roots))
 
@@ -149,7 +213,7 @@ func discoverSteamLibraries() ([]string, bool, []string, error) {
 	}
 
 	// Parse libraryfolders.vdf from any root that has it
-	libSet := make(map[string]struct{})
+	libSet := make(map[string]string)
 	for _, root := range uniqRoots {
 		vdfPath := filepath.Join(root, "steamapps", "libraryfolders.vdf")
 		st, statErr := os.Stat(vdfPath)
@@ -196,7 +260,10 @@ func discoverSteamLibraries() ([]string, bool, []string, error) {
 				warnings = append(warnings, fmt.Sprintf("library path canonicalize failed (%s): %v", p, cerr))
 				canon = filepath.Clean(p)
 			}
-			libSet[canon] = struct{}{}
+			// the first steam root that lists a library owns it
+			if _, ok := libSet[canon]; !ok {
+				libSet[canon] = root
+			}
 		}
 	}
 
@@ -207,7 +274,7 @@ func discoverSteamLibraries() ([]string, bool, []string, error) {
 	}
 	sort.Strings(libs)
 
-	return libs, didScan, warnings, nil
+	return libs, libSet, didScan, warnings, nil
 }
 
 func assignSteamInstanceIDs(libs []string) map[string]string {
@@ -238,6 +305,7 @@ func assignSteamInstanceIDs(libs []string) map[string]string {
 func discoverSteamInstalls(
 	libraryRoots []string, // canonical library roots
 	instanceByLib map[string]string, // canonical lib root -> instance_id
+	rootByLib map[string]string, // canonical lib root -> steam root
 ) ([]dbq.UpsertGameInstallParams, []string, error) {
 	// for each lib:
 	// - list steamapps/appmanifest_*.acf
@@ -245,7 +313,8 @@ func discoverSteamInstalls(
 	// - get appid, name, installdir
 	// - installRaw = <lib>/steamapps/common/<installdir>
 	// - installCanon = canonicalizePathBestEffort(installRaw)
-	// - metadata: include install_root_raw + library_root (+ manifest_path)
+	// - metadata: include install_root_raw + library_root (+ manifest_path,
+	//   steam_root, last_owner, flatpak)
 	warnings := []string{}
 	installs := []dbq.UpsertGameInstallParams{}
 
@@ -310,6 +379,15 @@ func discoverSteamInstalls(
 				"manifest_path":    manifestPath,
 				"steamapps_root":   steamapps,
 			}
+			if root := rootByLib[libRoot]; root != "" {
+				meta["steam_root"] = root
+				if isFlatpakSteamRoot(root) {
+					meta["flatpak"] = true
+				}
+			}
+			if owner, err := steam.AppOwner(manifestPath); err == nil && owner != 0 {
+				meta["last_owner"] = strconv.FormatUint(owner, 10)
+			}
 			metaJSON, merr := json.Marshal(meta)
 			if merr != nil {
 				// should never happen, but don't fail discovery over it
@@ -340,6 +418,119 @@ func discoverSteamInstalls(
 	return installs, warnings, nil
 }
 
+// discoverSteamShortcuts reads the shortcuts.vdf of every account of the
+// steam roots that own the given libraries. Shortcuts to programs of the
+// system (e.g., `flatpak run ...`) aren't games that we can mod and are
+// skipped, as are ones whose directory is gone.
+func discoverSteamShortcuts(rootByLib map[string]string) ([]dbq.UpsertGameInstallParams, []string) {
+	var roots []string
+	seenRoot := map[string]bool{}
+	for _, r := range rootByLib {
+		if !seenRoot[r] {
+			seenRoot[r] = true
+			roots = append(roots, r)
+		}
+	}
+	sort.Strings(roots)
+
+	warnings := []string{}
+	installs := []dbq.UpsertGameInstallParams{}
+	seen := map[string]bool{}
+
+	for _, root := range roots {
+		accounts, err := steam.LoadAccounts(root)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("steam accounts of %s: %v", root, err))
+			continue
+		}
+
+		for _, a := range accounts {
+			if a.Userdata == "" {
+				continue
+			}
+			path := filepath.Join(a.Userdata, "config", "shortcuts.vdf")
+			shortcuts, err := steam.ReadShortcuts(path)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					warnings = append(warnings, err.Error())
+				}
+				continue
+			}
+
+			for _, s := range shortcuts {
+				appid := strconv.FormatUint(uint64(s.AppID), 10)
+				// the same shortcut (same exe and name) in several
+				// accounts has the same id
+				if seen[appid] {
+					continue
+				}
+
+				dir := strings.TrimSpace(s.StartDir)
+				if dir == "" {
+					dir = filepath.Dir(s.Exe)
+				}
+				if dir == "" || !filepath.IsAbs(dir) {
+					continue
+				}
+				installRoot, err := canonicalizePathBestEffort(dir)
+				if err != nil {
+					installRoot = filepath.Clean(dir)
+				}
+				if st, err := os.Stat(installRoot); err != nil || !st.IsDir() || isSystemDir(installRoot) {
+					continue
+				}
+				seen[appid] = true
+
+				display := strings.TrimSpace(s.Name)
+				if display == "" {
+					display = filepath.Base(s.Exe)
+				}
+
+				meta := map[string]any{
+					"exe":            s.Exe,
+					"launch_options": s.LaunchOptions,
+					"shortcuts_path": path,
+					"steam_root":     root,
+					// steam puts the proton prefixes of shortcuts
+					// in its own library
+					"steamapps_root": filepath.Join(root, "steamapps"),
+					"last_owner":     strconv.FormatUint(a.SteamID64, 10),
+				}
+				if isFlatpakSteamRoot(root) {
+					meta["flatpak"] = true
+				}
+				metaJSON, merr := json.Marshal(meta)
+				if merr != nil {
+					warnings = append(warnings, fmt.Sprintf("metadata marshal failed (%s): %v", path, merr))
+				}
+
+				installs = append(installs, dbq.UpsertGameInstallParams{
+					StoreID:     SteamShortcutStore,
+					StoreGameID: appid,
+					InstanceID:  "default",
+					DisplayName: display,
+					InstallRoot: installRoot,
+					Metadata:    nullStringFromBytes(metaJSON),
+					LastSeenAt:  sql.NullString{String: nowISO8601Z(), Valid: true},
+				})
+			}
+		}
+	}
+
+	return installs, warnings
+}
+
+// isSystemDir reports whether dir belongs to the operating system rather
+// than to a game.
+func isSystemDir(dir string) bool {
+	for _, sys := range []string{"/usr", "/bin", "/sbin", "/lib", "/etc", "/app"} {
+		if ok, err := IsUnderDir(dir, sys); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
 func upsertGameDirTarget(ctx context.Context, q *dbq.Queries, gameInstallID int64, installRoot string) error {
 	const targetName = "game_dir"
 
@@ -0,0 +1,179 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/nexus"
+)
+
+// NexusArchive is an archive (that's missing from the store or was
+// quarantined) with what's needed to find it on Nexus again.
+type NexusArchive struct {
+	SHA256       string
+	SizeBytes    int64
+	OriginalName string
+
+	GameDomain string
+	ModID      int64
+	// the nexus file id of its mod file, 0 if it isn't known
+	FileID int64
+}
+
+// ErrNotOnNexus is returned by FetchNexusArchive if none of the files of the
+// mod on Nexus is the archive (e.g., because it was deleted).
+var ErrNotOnNexus = errors.New("archive not found on nexus")
+
+// errWrongFile means that a downloaded file isn't the archive.
+var errWrongFile = errors.New("not the archive")
+
+// FetchNexusArchive downloads the files of the mod of an archive that could
+// be it (see nexusCandidates) into dir until one of them has its sha256, and
+// returns the path of that download: the caller ingests it and removes it.
+// progress, if set, is called while downloading.
+func FetchNexusArchive(ctx context.Context, c *nexus.Client, dir string, a NexusArchive, progress func(done, total int64)) (string, error) {
+	files, err := c.GetFiles(ctx, a.GameDomain, a.ModID)
+	if err != nil {
+		return "", fmt.Errorf("list nexus files: %w", err)
+	}
+
+	for _, f := range nexusCandidates(files, a) {
+		path, err := fetchNexusFile(ctx, c, dir, a, f, progress)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, errWrongFile) {
+			return "", err
+		}
+	}
+
+	return "", ErrNotOnNexus
+}
+
+// nexusCandidates returns the files of a mod that could be the archive, most
+// likely first: the file of its mod file, then files with its name, then
+// other files of the same size. A file with a different size can't be it, and
+// files that Nexus doesn't know the size of are only tried if the id or the
+// name matches.
+func nexusCandidates(files []nexus.File, a NexusArchive) []nexus.File {
+	rank := func(f nexus.File) int {
+		switch {
+		case a.FileID != 0 && f.FileID == a.FileID:
+			return 0
+		case a.OriginalName != "" && f.FileName == a.OriginalName:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	var out []nexus.File
+	for _, f := range files {
+		if f.SizeInBytes > 0 && f.SizeInBytes != a.SizeBytes {
+			continue
+		}
+		if f.SizeInBytes == 0 && rank(f) == 2 {
+			continue
+		}
+		out = append(out, f)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return rank(out[i]) < rank(out[j]) })
+
+	return out
+}
+
+func fetchNexusFile(ctx context.Context, c *nexus.Client, dir string, a NexusArchive, f nexus.File, progress func(done, total int64)) (string, error) {
+	if f.SizeInBytes == 0 {
+		f.SizeInBytes = a.SizeBytes
+	}
+	path, sum, err := downloadNexusFile(ctx, c, dir, "repair-*", a.GameDomain, a.ModID, f, progress)
+	if err != nil {
+		return "", err
+	}
+
+	if sum != a.SHA256 {
+		_ = os.Remove(path)
+		return "", errWrongFile
+	}
+
+	return path, nil
+}
+
+// DownloadNexusFile downloads a file of a mod into dir and returns the path
+// and the sha256 of the download: the caller imports it and removes it.
+// progress, if set, is called while downloading.
+func DownloadNexusFile(ctx context.Context, c *nexus.Client, dir, gameDomain string, modID int64, f nexus.File, progress func(done, total int64)) (string, string, error) {
+	return downloadNexusFile(ctx, c, dir, "nexus-*", gameDomain, modID, f, progress)
+}
+
+func downloadNexusFile(ctx context.Context, c *nexus.Client, dir, pattern, gameDomain string, modID int64, f nexus.File, progress func(done, total int64)) (string, string, error) {
+	links, err := c.GetDownloadLinks(ctx, gameDomain, modID, f.FileID)
+	if err != nil {
+		var apiErr *nexus.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+			return "", "", fmt.Errorf("%w (downloading through the api needs a premium account)", err)
+		}
+		return "", "", fmt.Errorf("get download link of nexus file %d: %w", f.FileID, err)
+	}
+	if len(links) == 0 {
+		return "", "", fmt.Errorf("nexus file %d has no download links", f.FileID)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+	tmp, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", "", fmt.Errorf("create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	keep := false
+	defer func() {
+		_ = tmp.Close()
+		if !keep {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	h := sha256.New()
+	w := io.MultiWriter(tmp, h)
+	if progress != nil {
+		w = blobstore.NewProgressWriter(w, f.SizeInBytes, progress)
+	}
+
+	if _, err := c.Download(ctx, links[0].URI, w); err != nil {
+		return "", "", fmt.Errorf("nexus file %d: %w", f.FileID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", "", err
+	}
+
+	keep = true
+	return tmpName, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,107 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfinelli/modctl/internal/nexus"
+)
+
+func TestNexusCandidates(t *testing.T) {
+	t.Parallel()
+
+	files := []nexus.File{
+		{FileID: 1, FileName: "other.zip", SizeInBytes: 10},
+		{FileID: 2, FileName: "mod.zip", SizeInBytes: 10},
+		{FileID: 3, FileName: "big.zip", SizeInBytes: 99},
+		{FileID: 4, FileName: "unknown.zip"},
+		{FileID: 5, FileName: "mine.zip"},
+	}
+	a := NexusArchive{SizeBytes: 10, OriginalName: "mod.zip", FileID: 5}
+
+	var ids []int64
+	for _, f := range nexusCandidates(files, a) {
+		ids = append(ids, f.FileID)
+	}
+	assert.Equal(t, []int64{5, 2, 1}, ids)
+}
+
+func TestFetchNexusArchive(t *testing.T) {
+	t.Parallel()
+
+	want := []byte("the real archive")
+	sum := sha256.Sum256(want)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/games/skyrim/mods/7/files.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"files":[{"file_id":1,"file_name":"mod.zip","size_in_bytes":%d},{"file_id":2,"file_name":"mod-old.zip","size_in_bytes":%d}]}`,
+			len(want), len(want))
+	})
+	var srv *httptest.Server
+	mux.HandleFunc("/v1/games/skyrim/mods/7/files/{id}/download_link.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"name":"cdn","short_name":"cdn","URI":"%s/cdn/%s"}]`, srv.URL, r.PathValue("id"))
+	})
+	mux.HandleFunc("/cdn/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("a different file"))
+	})
+	mux.HandleFunc("/cdn/2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(want)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := nexus.NewClient("key", "test", "")
+	c.BaseURL = srv.URL
+
+	dir := t.TempDir()
+	a := NexusArchive{
+		SHA256:       hex.EncodeToString(sum[:]),
+		SizeBytes:    int64(len(want)),
+		OriginalName: "mod.zip",
+		GameDomain:   "skyrim",
+		ModID:        7,
+	}
+
+	path, err := FetchNexusArchive(context.Background(), c, dir, a, nil)
+	require.NoError(t, err)
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// the wrong download was cleaned up
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	a.SHA256 = "00"
+	_, err = FetchNexusArchive(context.Background(), c, dir, a, nil)
+	assert.True(t, errors.Is(err, ErrNotOnNexus))
+}
@@ -0,0 +1,264 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// Revision is a snapshot of a profile: exactly what applying it deploys.
+// The revision of the applied profile is stored with the game install so
+// that changes made to the profile since can be shown as pending.
+type Revision struct {
+	ProfileID int64              `json:"profile_id"`
+	Items     []RevisionItem     `json:"items"`
+	Overrides []RevisionOverride `json:"overrides,omitempty"`
+}
+
+// RevisionItem is an enabled profile item.
+type RevisionItem struct {
+	ProfileItemID int64          `json:"profile_item_id"`
+	VersionID     int64          `json:"version_id"`
+	ArchiveSHA256 string         `json:"archive_sha256"`
+	Priority      int64          `json:"priority"`
+	ModName       string         `json:"mod_name"`
+	FileLabel     string         `json:"file_label"`
+	Rules         []RevisionRule `json:"rules,omitempty"`
+	Hidden        []string       `json:"hidden,omitempty"`
+	// the symlink policy (see plan.Item.Symlinks), empty in the revisions
+	// that were applied before there was one
+	Symlinks string `json:"symlinks,omitempty"`
+}
+
+// symlinkPolicy returns a symlink policy, plan.SymlinksCopy if there's none.
+func symlinkPolicy(policy string) string {
+	if policy == "" {
+		return plan.SymlinksCopy
+	}
+	return policy
+}
+
+// RevisionRule is a remap rule of an item.
+type RevisionRule struct {
+	Type string `json:"type"`
+	Int  int64  `json:"int,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// RevisionOverride is an override of the profile.
+type RevisionOverride struct {
+	TargetID   int64  `json:"target_id"`
+	TargetName string `json:"target_name"`
+	RelPath    string `json:"relpath"`
+	BlobSHA256 string `json:"blob_sha256"`
+	Template   bool   `json:"template,omitempty"`
+}
+
+// CurrentRevision takes a snapshot of a profile as it is now.
+func CurrentRevision(ctx context.Context, q *dbq.Queries, profileID int64) (Revision, error) {
+	rev := Revision{ProfileID: profileID, Items: []RevisionItem{}}
+
+	rows, err := q.ListEnabledProfileItemsForPlan(ctx, profileID)
+	if err != nil {
+		return rev, fmt.Errorf("list profile items: %w", err)
+	}
+
+	rules, err := q.ListRemapRulesForProfile(ctx, profileID)
+	if err != nil {
+		return rev, fmt.Errorf("list remap rules: %w", err)
+	}
+	rulesByItem := map[int64][]RevisionRule{}
+	for _, r := range rules {
+		rulesByItem[r.ProfileItemID] = append(rulesByItem[r.ProfileItemID], RevisionRule{
+			Type: r.RuleType,
+			Int:  r.IntValue.Int64,
+			Text: r.TextValue.String,
+		})
+	}
+
+	hidden, err := q.ListHiddenFilesForProfile(ctx, profileID)
+	if err != nil {
+		return rev, fmt.Errorf("list hidden files: %w", err)
+	}
+	hiddenByItem := map[int64][]string{}
+	for _, h := range hidden {
+		hiddenByItem[h.ProfileItemID] = append(hiddenByItem[h.ProfileItemID], h.Relpath)
+	}
+
+	for _, r := range rows {
+		rev.Items = append(rev.Items, RevisionItem{
+			ProfileItemID: r.ID,
+			VersionID:     r.ModFileVersionID,
+			ArchiveSHA256: r.ArchiveSha256,
+			Priority:      r.Priority,
+			ModName:       r.ModName,
+			FileLabel:     r.FileLabel,
+			Rules:         rulesByItem[r.ID],
+			Hidden:        hiddenByItem[r.ID],
+			Symlinks:      r.Symlinks,
+		})
+	}
+
+	overrides, err := q.ListOverridesForProfile(ctx, profileID)
+	if err != nil {
+		return rev, fmt.Errorf("list overrides: %w", err)
+	}
+	for _, o := range overrides {
+		rev.Overrides = append(rev.Overrides, RevisionOverride{
+			TargetID:   o.TargetID,
+			TargetName: o.TargetName,
+			RelPath:    o.Relpath,
+			BlobSHA256: o.BlobSha256,
+			Template:   o.IsTemplate != 0,
+		})
+	}
+
+	return rev, nil
+}
+
+// AppliedRevision returns the revision that was stored when the profile
+// was applied to a game install. It returns false if nothing is applied or
+// if the profile was applied before modctl kept track of revisions.
+func AppliedRevision(gi dbq.GameInstall) (Revision, bool, error) {
+	var rev Revision
+	if !gi.AppliedProfileID.Valid || !gi.AppliedRevision.Valid {
+		return rev, false, nil
+	}
+
+	if err := json.Unmarshal([]byte(gi.AppliedRevision.String), &rev); err != nil {
+		return rev, false, fmt.Errorf("parse applied revision: %w", err)
+	}
+	return rev, true, nil
+}
+
+// PendingChange is a difference between the applied revision and the
+// current state of a profile.
+type PendingChange struct {
+	Kind    string `json:"kind"` // added, removed, version, priority, rules, hidden, symlinks, override
+	Message string `json:"message"`
+}
+
+// DiffRevisions returns what changed between the applied revision and the
+// current one (i.e., what applying the profile again would change).
+func DiffRevisions(applied, current Revision) []PendingChange {
+	var out []PendingChange
+
+	if applied.ProfileID != current.ProfileID {
+		out = append(out, PendingChange{Kind: "profile",
+			Message: "a different profile is applied"})
+	}
+
+	label := func(it RevisionItem) string {
+		return fmt.Sprintf("%s / %s", it.ModName, it.FileLabel)
+	}
+
+	// items are matched by profile item (or, across profiles, by version)
+	key := func(it RevisionItem) int64 {
+		if applied.ProfileID != current.ProfileID {
+			return it.VersionID
+		}
+		return it.ProfileItemID
+	}
+
+	before := map[int64]RevisionItem{}
+	for _, it := range applied.Items {
+		before[key(it)] = it
+	}
+	seen := map[int64]bool{}
+
+	for _, it := range current.Items {
+		k := key(it)
+		seen[k] = true
+
+		old, ok := before[k]
+		if !ok {
+			out = append(out, PendingChange{Kind: "added",
+				Message: fmt.Sprintf("%s (v%d) enabled", label(it), it.VersionID)})
+			continue
+		}
+		if old.VersionID != it.VersionID || old.ArchiveSHA256 != it.ArchiveSHA256 {
+			out = append(out, PendingChange{Kind: "version",
+				Message: fmt.Sprintf("%s: v%d → v%d", label(it), old.VersionID, it.VersionID)})
+		}
+		if old.Priority != it.Priority {
+			out = append(out, PendingChange{Kind: "priority",
+				Message: fmt.Sprintf("%s: priority %d → %d", label(it), old.Priority, it.Priority)})
+		}
+		if !slices.Equal(old.Rules, it.Rules) {
+			out = append(out, PendingChange{Kind: "rules",
+				Message: fmt.Sprintf("%s: remap rules changed", label(it))})
+		}
+		if !slices.Equal(old.Hidden, it.Hidden) {
+			out = append(out, PendingChange{Kind: "hidden",
+				Message: fmt.Sprintf("%s: hidden files changed", label(it))})
+		}
+		if from, to := symlinkPolicy(old.Symlinks), symlinkPolicy(it.Symlinks); from != to {
+			out = append(out, PendingChange{Kind: "symlinks",
+				Message: fmt.Sprintf("%s: symlinks %s → %s", label(it), from, to)})
+		}
+	}
+
+	for _, it := range applied.Items {
+		if !seen[key(it)] {
+			out = append(out, PendingChange{Kind: "removed",
+				Message: fmt.Sprintf("%s (v%d) disabled or removed", label(it), it.VersionID)})
+		}
+	}
+
+	type okey struct {
+		target  int64
+		relpath string
+	}
+	oldOverrides := map[okey]RevisionOverride{}
+	for _, o := range applied.Overrides {
+		oldOverrides[okey{o.TargetID, o.RelPath}] = o
+	}
+	var overrideChanges []PendingChange
+	for _, o := range current.Overrides {
+		k := okey{o.TargetID, o.RelPath}
+		old, ok := oldOverrides[k]
+		delete(oldOverrides, k)
+		switch {
+		case !ok:
+			overrideChanges = append(overrideChanges, PendingChange{Kind: "override",
+				Message: fmt.Sprintf("override %s/%s added", o.TargetName, o.RelPath)})
+		case old.BlobSHA256 != o.BlobSHA256 || old.Template != o.Template:
+			overrideChanges = append(overrideChanges, PendingChange{Kind: "override",
+				Message: fmt.Sprintf("override %s/%s changed", o.TargetName, o.RelPath)})
+		}
+	}
+	for _, o := range applied.Overrides {
+		if _, ok := oldOverrides[okey{o.TargetID, o.RelPath}]; ok {
+			overrideChanges = append(overrideChanges, PendingChange{Kind: "override",
+				Message: fmt.Sprintf("override %s/%s removed", o.TargetName, o.RelPath)})
+		}
+	}
+	sort.Slice(overrideChanges, func(i, j int) bool {
+		return overrideChanges[i].Message < overrideChanges[j].Message
+	})
+
+	return append(out, overrideChanges...)
+}
@@ -0,0 +1,155 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	t.Parallel()
+
+	item := func(id, version, priority int64) RevisionItem {
+		return RevisionItem{
+			ProfileItemID: id,
+			VersionID:     version,
+			ArchiveSHA256: "sha",
+			Priority:      priority,
+			ModName:       "Mod",
+			FileLabel:     "Main",
+		}
+	}
+	base := Revision{
+		ProfileID: 1,
+		Items:     []RevisionItem{item(1, 10, 2), item(2, 20, 1)},
+		Overrides: []RevisionOverride{{TargetID: 1, TargetName: "game_dir", RelPath: "a.ini", BlobSHA256: "x"}},
+	}
+
+	kinds := func(changes []PendingChange) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, c.Kind)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		current  func(r Revision) Revision
+		expected []string
+	}{
+		{
+			name:    "unchanged",
+			current: func(r Revision) Revision { return r },
+		},
+		{
+			name: "added and removed",
+			current: func(r Revision) Revision {
+				r.Items = []RevisionItem{item(1, 10, 2), item(3, 30, 1)}
+				return r
+			},
+			expected: []string{"added", "removed"},
+		},
+		{
+			name: "new version and priority",
+			current: func(r Revision) Revision {
+				r.Items = []RevisionItem{item(1, 11, 5), item(2, 20, 1)}
+				return r
+			},
+			expected: []string{"version", "priority"},
+		},
+		{
+			name: "rules",
+			current: func(r Revision) Revision {
+				it := item(2, 20, 1)
+				it.Rules = []RevisionRule{{Type: "strip_components", Int: 1}}
+				r.Items = []RevisionItem{item(1, 10, 2), it}
+				return r
+			},
+			expected: []string{"rules"},
+		},
+		{
+			name: "hidden",
+			current: func(r Revision) Revision {
+				it := item(1, 10, 2)
+				it.Hidden = []string{"Data/a.dds"}
+				r.Items = []RevisionItem{it, item(2, 20, 1)}
+				return r
+			},
+			expected: []string{"hidden"},
+		},
+		{
+			name: "symlink policy",
+			current: func(r Revision) Revision {
+				it := item(2, 20, 1)
+				it.Symlinks = "skip"
+				r.Items = []RevisionItem{item(1, 10, 2), it}
+				return r
+			},
+			expected: []string{"symlinks"},
+		},
+		{
+			// revisions applied before there was a policy don't have one
+			name: "default symlink policy",
+			current: func(r Revision) Revision {
+				it := item(2, 20, 1)
+				it.Symlinks = "copy"
+				r.Items = []RevisionItem{item(1, 10, 2), it}
+				return r
+			},
+		},
+		{
+			name: "overrides",
+			current: func(r Revision) Revision {
+				r.Overrides = []RevisionOverride{
+					{TargetID: 1, TargetName: "game_dir", RelPath: "a.ini", BlobSHA256: "y"},
+					{TargetID: 1, TargetName: "game_dir", RelPath: "b.ini", BlobSHA256: "z"},
+				}
+				return r
+			},
+			expected: []string{"override", "override"},
+		},
+		{
+			name: "other profile with the same mods",
+			current: func(r Revision) Revision {
+				return Revision{
+					ProfileID: 2,
+					Items:     []RevisionItem{item(7, 10, 2), item(8, 20, 1)},
+					Overrides: r.Overrides,
+				}
+			},
+			expected: []string{"profile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			current := tt.current(Revision{
+				ProfileID: base.ProfileID,
+				Items:     append([]RevisionItem(nil), base.Items...),
+				Overrides: append([]RevisionOverride(nil), base.Overrides...),
+			})
+			assert.Equal(t, tt.expected, kinds(DiffRevisions(base, current)))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package scan runs a virus scanner (the scan_command config option, e.g.,
+// clamscan) on archives when they're imported.
+package scan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/perf"
+	"github.com/mfinelli/modctl/internal/vars"
+)
+
+// The verdicts of a scan.
+const (
+	Clean    = "clean"
+	Detected = "detected"
+	// the scanner couldn't scan the file
+	Failed = "error"
+)
+
+// ClamAV is the suggested scan_command.
+var ClamAV = []string{"clamscan", "--no-summary", "--infected", "${file}"}
+
+// Result is the verdict of a scan, and what the scanner printed if it
+// isn't clean.
+type Result struct {
+	Verdict string
+	Detail  string
+}
+
+// Run scans the file at path with the command template (${file} is
+// replaced with the path). Like clamscan the command exits with 0 if the
+// file is clean and with 1 if something was detected; anything else (or a
+// command that can't be run) is a failed scan.
+func Run(ctx context.Context, command []string, path string) Result {
+	defer perf.Track(perf.Commands)()
+
+	args, err := vars.ExpandCommand(command, map[string]string{"file": path})
+	if err != nil {
+		return Result{Verdict: Failed, Detail: fmt.Sprintf("scan_command: %v", err)}
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err = cmd.Run()
+	msg := strings.TrimSpace(out.String())
+
+	var exit *exec.ExitError
+	switch {
+	case err == nil:
+		return Result{Verdict: Clean}
+	case errors.As(err, &exit) && exit.ExitCode() == 1:
+		return Result{Verdict: Detected, Detail: msg}
+	case msg != "":
+		return Result{Verdict: Failed, Detail: fmt.Sprintf("%s failed: %v\n%s", args[0], err, msg)}
+	default:
+		return Result{Verdict: Failed, Detail: fmt.Sprintf("%s failed: %v", args[0], err)}
+	}
+}
+
+// DetectedError is returned when the scanner detected something in an
+// archive that was about to be imported.
+type DetectedError struct {
+	Name   string
+	Detail string
+}
+
+func (e *DetectedError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("the virus scanner detected something in %s", e.Name)
+	}
+	return fmt.Sprintf("the virus scanner detected something in %s:\n%s", e.Name, e.Detail)
+}
+
+// ErrorHints returns what the user can do about it.
+func (e *DetectedError) ErrorHints() []string {
+	return []string{"pass --force if you're sure that it's a false positive"}
+}
+
+// FailedError is returned when an archive that was about to be imported
+// couldn't be scanned.
+type FailedError struct {
+	Name   string
+	Detail string
+}
+
+func (e *FailedError) Error() string {
+	return fmt.Sprintf("couldn't scan %s: %s", e.Name, e.Detail)
+}
+
+// ErrorHints returns what the user can do about it.
+func (e *FailedError) ErrorHints() []string {
+	return []string{
+		"check the scan_command config option (see `modctl doctor`)",
+		"or pass --allow-unscanned to import it without a scan",
+	}
+}
+
+// Check returns the error for a result that refuses the import: a detection
+// unless force is set, and a failed scan unless allowUnscanned is set.
+func Check(r Result, name string, allowUnscanned, force bool) error {
+	switch {
+	case r.Verdict == Detected && !force:
+		return &DetectedError{Name: name, Detail: r.Detail}
+	case r.Verdict == Failed && !allowUnscanned:
+		return &FailedError{Name: name, Detail: r.Detail}
+	}
+	return nil
+}
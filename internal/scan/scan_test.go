@@ -0,0 +1,94 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scan
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+
+	tests := map[string]struct {
+		command []string
+		verdict string
+		detail  string
+	}{
+		"clean": {
+			command: []string{"sh", "-c", `test "$1" = /tmp/mod.7z`, "sh", "${file}"},
+			verdict: Clean,
+		},
+		"detected": {
+			command: []string{"sh", "-c", `echo "$1: Eicar-Signature FOUND"; exit 1`, "sh", "${file}"},
+			verdict: Detected,
+			detail:  "/tmp/mod.7z: Eicar-Signature FOUND",
+		},
+		"failed": {
+			command: []string{"sh", "-c", "echo no database >&2; exit 2"},
+			verdict: Failed,
+			detail:  "sh failed: exit status 2\nno database",
+		},
+		"missing": {
+			command: []string{"modctl-no-such-scanner", "${file}"},
+			verdict: Failed,
+		},
+		"unknown variable": {
+			command: []string{"clamscan", "${path}"},
+			verdict: Failed,
+			detail:  `scan_command: unknown variable ${path} in "${path}"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := Run(context.Background(), tt.command, "/tmp/mod.7z")
+			assert.Equal(t, tt.verdict, r.Verdict)
+			if tt.detail != "" {
+				assert.Equal(t, tt.detail, r.Detail)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	detected := Result{Verdict: Detected, Detail: "FOUND"}
+	failed := Result{Verdict: Failed, Detail: "no database"}
+
+	var derr *DetectedError
+	assert.ErrorAs(t, Check(detected, "mod.7z", true, false), &derr)
+	assert.NoError(t, Check(detected, "mod.7z", false, true))
+
+	var ferr *FailedError
+	assert.ErrorAs(t, Check(failed, "mod.7z", false, true), &ferr)
+	assert.NoError(t, Check(failed, "mod.7z", true, false))
+
+	assert.NoError(t, Check(Result{Verdict: Clean}, "mod.7z", false, false))
+}
@@ -0,0 +1,140 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/zalando/go-keyring"
+)
+
+// keyring service name that all of our secrets are stored under
+const service = "modctl"
+
+// Known secrets. The key is used as the keyring "user" and to derive the
+// environment variable override (e.g., MODCTL_NEXUS_API_KEY).
+const (
+	NexusAPIKey = "nexus_api_key"
+)
+
+var (
+	ErrNotFound = errors.New("secret not found")
+	ErrReadOnly = errors.New("secrets provider is read-only")
+)
+
+type Provider interface {
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// New returns the secrets provider with the given name (from the
+// secrets_provider config option).
+func New(name string) (Provider, error) {
+	switch name {
+	case "", "keyring":
+		return keyringProvider{}, nil
+	case "env":
+		return envProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown secrets provider: %q (expected keyring or env)", name)
+	}
+}
+
+// EnvVar returns the name of the environment variable that overrides the
+// given secret.
+func EnvVar(key string) string {
+	return "MODCTL_" + strings.ToUpper(key)
+}
+
+// Lookup returns the value of a secret and where it came from. The
+// environment variable always wins over the provider so that the key can be
+// supplied in headless environments without touching the keyring.
+func Lookup(p Provider, key string) (value, source string, err error) {
+	if v := os.Getenv(EnvVar(key)); v != "" {
+		return v, "env:" + EnvVar(key), nil
+	}
+
+	v, err := p.Get(key)
+	if err != nil {
+		return "", "", err
+	}
+	return v, p.Name(), nil
+}
+
+// keyringProvider stores secrets in the OS keyring: the Secret Service on
+// Linux (e.g., gnome-keyring, KWallet) or the keychain on macOS
+type keyringProvider struct{}
+
+func (keyringProvider) Name() string { return "keyring" }
+
+func (keyringProvider) Get(key string) (string, error) {
+	v, err := keyring.Get(service, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", fmt.Errorf("read %s from keyring: %w", key, err)
+	}
+	return v, nil
+}
+
+func (keyringProvider) Set(key, value string) error {
+	if err := readonly.Check("write " + key + " to keyring"); err != nil {
+		return err
+	}
+	if err := keyring.Set(service, key, value); err != nil {
+		return fmt.Errorf("write %s to keyring: %w", key, err)
+	}
+	return nil
+}
+
+func (keyringProvider) Delete(key string) error {
+	if err := readonly.Check("delete " + key + " from keyring"); err != nil {
+		return err
+	}
+	err := keyring.Delete(service, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("delete %s from keyring: %w", key, err)
+	}
+	return nil
+}
+
+// envProvider only reads secrets from the environment; it's meant for systems
+// without a keyring daemon
+type envProvider struct{}
+
+func (envProvider) Name() string { return "env" }
+
+func (envProvider) Get(key string) (string, error) {
+	if v := os.Getenv(EnvVar(key)); v != "" {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
+
+func (envProvider) Set(key, value string) error { return ErrReadOnly }
+
+func (envProvider) Delete(key string) error { return ErrReadOnly }
@@ -0,0 +1,183 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package secrets
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "", want: "keyring"},
+		{name: "keyring", want: "keyring"},
+		{name: "env", want: "env"},
+		{name: "vault", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p.Name())
+		})
+	}
+}
+
+func TestEnvVar(t *testing.T) {
+	assert.Equal(t, "MODCTL_NEXUS_API_KEY", EnvVar(NexusAPIKey))
+}
+
+func TestKeyringProvider(t *testing.T) {
+	keyring.MockInit()
+	t.Cleanup(readonly.Reset)
+
+	p := keyringProvider{}
+
+	_, err := p.Get(NexusAPIKey)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, p.Delete(NexusAPIKey), ErrNotFound)
+
+	require.NoError(t, p.Set(NexusAPIKey, "abc"))
+	v, err := p.Get(NexusAPIKey)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", v)
+
+	readonly.Enable()
+	assert.ErrorIs(t, p.Set(NexusAPIKey, "def"), readonly.ErrReadOnly)
+	assert.ErrorIs(t, p.Delete(NexusAPIKey), readonly.ErrReadOnly)
+	readonly.Reset()
+
+	v, err = p.Get(NexusAPIKey)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", v)
+
+	require.NoError(t, p.Delete(NexusAPIKey))
+	_, err = p.Get(NexusAPIKey)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestKeyringProviderError(t *testing.T) {
+	keyring.MockInitWithError(errors.New("no secret service"))
+	t.Cleanup(keyring.MockInit)
+
+	p := keyringProvider{}
+
+	_, err := p.Get(NexusAPIKey)
+	assert.EqualError(t, err, "read nexus_api_key from keyring: no secret service")
+	assert.NotErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, p.Set(NexusAPIKey, "abc"), "write nexus_api_key to keyring: no secret service")
+	assert.EqualError(t, p.Delete(NexusAPIKey), "delete nexus_api_key from keyring: no secret service")
+}
+
+func TestEnvProvider(t *testing.T) {
+	p := envProvider{}
+
+	t.Setenv(EnvVar(NexusAPIKey), "")
+	_, err := p.Get(NexusAPIKey)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	t.Setenv(EnvVar(NexusAPIKey), "abc")
+	v, err := p.Get(NexusAPIKey)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", v)
+
+	assert.ErrorIs(t, p.Set(NexusAPIKey, "def"), ErrReadOnly)
+	assert.ErrorIs(t, p.Delete(NexusAPIKey), ErrReadOnly)
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		keyring    string
+		wantValue  string
+		wantSource string
+		wantErr    error
+	}{
+		{
+			name:       "env wins",
+			env:        "from-env",
+			keyring:    "from-keyring",
+			wantValue:  "from-env",
+			wantSource: "env:MODCTL_NEXUS_API_KEY",
+		},
+		{
+			name:       "keyring fallback",
+			keyring:    "from-keyring",
+			wantValue:  "from-keyring",
+			wantSource: "keyring",
+		},
+		{
+			name:       "env only",
+			env:        "from-env",
+			wantValue:  "from-env",
+			wantSource: "env:MODCTL_NEXUS_API_KEY",
+		},
+		{name: "neither", wantErr: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring.MockInit()
+			t.Setenv(EnvVar(NexusAPIKey), tt.env)
+			if tt.keyring != "" {
+				require.NoError(t, keyring.Set(service, NexusAPIKey, tt.keyring))
+			}
+
+			v, source, err := Lookup(keyringProvider{}, NexusAPIKey)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValue, v)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestLookupProviderError(t *testing.T) {
+	keyring.MockInitWithError(errors.New("no secret service"))
+	t.Cleanup(keyring.MockInit)
+	t.Setenv(EnvVar(NexusAPIKey), "")
+
+	_, _, err := Lookup(keyringProvider{}, NexusAPIKey)
+	assert.EqualError(t, err, "read nexus_api_key from keyring: no secret service")
+
+	// the environment variable still works without a keyring
+	t.Setenv(EnvVar(NexusAPIKey), "abc")
+	v, source, err := Lookup(keyringProvider{}, NexusAPIKey)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", v)
+	assert.Equal(t, "env:MODCTL_NEXUS_API_KEY", source)
+}
@@ -0,0 +1,95 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"errors"
+	"strings"
+)
+
+// SplitArgs splits a command line into its arguments like a POSIX shell
+// does, without any expansion: arguments are separated by whitespace, single
+// quotes keep everything literally, double quotes keep everything but
+// backslash escapes of `"` and `\`, and a backslash outside of quotes escapes
+// the next character. A # at the start of an argument starts a comment that
+// runs until the end of the line.
+func SplitArgs(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+
+	rs := []rune(line)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		case r == '#' && !inArg:
+			return args, nil
+		case r == '\\':
+			if i+1 == len(rs) {
+				return nil, errors.New("unterminated backslash escape")
+			}
+			i++
+			cur.WriteRune(rs[i])
+			inArg = true
+		case r == '\'':
+			end := indexRune(rs, i+1, '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			cur.WriteString(string(rs[i+1 : end]))
+			i = end
+			inArg = true
+		case r == '"':
+			i++
+			for ; i < len(rs) && rs[i] != '"'; i++ {
+				if rs[i] == '\\' && i+1 < len(rs) && (rs[i+1] == '"' || rs[i+1] == '\\') {
+					i++
+				}
+				cur.WriteRune(rs[i])
+			}
+			if i == len(rs) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inArg = true
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+func indexRune(rs []rune, from int, r rune) int {
+	for i := from; i < len(rs); i++ {
+		if rs[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,66 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{name: "empty", line: "   ", want: nil},
+		{name: "plain", line: "profiles apply  --full", want: []string{"profiles", "apply", "--full"}},
+		{name: "single quotes", line: `mods import 'my mod.zip'`, want: []string{"mods", "import", "my mod.zip"}},
+		{name: "single quotes are literal", line: `a 'b\"c'`, want: []string{"a", `b\"c`}},
+		{name: "double quotes", line: `a "b \"c\" \\ \n"`, want: []string{"a", `b "c" \ \n`}},
+		{name: "backslash", line: `my\ mod.zip`, want: []string{"my mod.zip"}},
+		{name: "adjacent quotes", line: `a'b'"c"d`, want: []string{"abcd"}},
+		{name: "empty quotes", line: `a ''`, want: []string{"a", ""}},
+		{name: "comment", line: "profiles list # all of them", want: []string{"profiles", "list"}},
+		{name: "hash inside argument", line: "games info steam:1#2", want: []string{"games", "info", "steam:1#2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SplitArgs(tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitArgsUnterminated(t *testing.T) {
+	t.Parallel()
+
+	for _, line := range []string{`a 'b`, `a "b`, `a \`} {
+		_, err := SplitArgs(line)
+		assert.Error(t, err, line)
+	}
+}
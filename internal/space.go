@@ -0,0 +1,230 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/fscaps"
+)
+
+// SpaceShortage is a filesystem that doesn't have enough free space for an
+// apply.
+type SpaceShortage struct {
+	// the first directory on the filesystem that needs the space
+	Path   string
+	Needed int64
+	Free   int64
+}
+
+// InsufficientSpaceError is returned by Apply when the files that it would
+// write don't fit. Nothing was changed.
+type InsufficientSpaceError struct {
+	Shortages []SpaceShortage
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	parts := make([]string, len(e.Shortages))
+	for i, s := range e.Shortages {
+		parts[i] = fmt.Sprintf("%s needs %s but only %s are free",
+			s.Path, FormatBytes(s.Needed), FormatBytes(s.Free))
+	}
+	return "not enough free space: " + strings.Join(parts, "; ")
+}
+
+// spaceNeeds adds up how much space an apply needs on every filesystem. It
+// can go down too: files that are replaced free their space.
+type spaceNeeds struct {
+	byDevice map[uint64]*SpaceShortage
+	// every filesystem in the order that it was first seen
+	order []uint64
+}
+
+func (n *spaceNeeds) add(dev uint64, path string, size int64) {
+	if n.byDevice == nil {
+		n.byDevice = map[uint64]*SpaceShortage{}
+	}
+	s, ok := n.byDevice[dev]
+	if !ok {
+		s = &SpaceShortage{Path: path}
+		n.byDevice[dev] = s
+		n.order = append(n.order, dev)
+	}
+	s.Needed += size
+}
+
+// shortages returns the filesystems that need more space than they have.
+func (n *spaceNeeds) shortages(free func(path string) (uint64, error)) ([]SpaceShortage, error) {
+	var out []SpaceShortage
+	for _, dev := range n.order {
+		s := n.byDevice[dev]
+		if s.Needed <= 0 {
+			continue
+		}
+		f, err := free(s.Path)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(s.Needed) > f {
+			out = append(out, SpaceShortage{Path: s.Path, Needed: s.Needed, Free: int64(f)})
+		}
+	}
+	return out, nil
+}
+
+// checkDiskSpace makes sure that every filesystem that an apply writes to has
+// enough free space before anything is changed: the archives are extracted
+// to the tmp dir, the files are written to the targets (hardlinks and
+// reflinks don't take up space of their own), and the files that they
+// replace are backed up. Removing the stale files first usually frees some
+// space as well, that isn't counted. If the space can't be determined it
+// only warns.
+func (d *Deployer) checkDiskSpace(ctx context.Context, desired map[pathKey]*desiredFile, keys []pathKey, byKey map[pathKey]dbq.InstalledFile, res *DeployResult) error {
+	var needs spaceNeeds
+	devices := map[string]uint64{}
+	add := func(dir string, size int64) error {
+		dev, ok := devices[dir]
+		if !ok {
+			p, err := fscaps.ExistingParent(dir)
+			if err != nil {
+				return err
+			}
+			if dev, err = fscaps.Device(p); err != nil {
+				return err
+			}
+			devices[dir] = dev
+		}
+		needs.add(dev, dir, size)
+		return nil
+	}
+
+	warn := func(err error) error {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("can't check the free disk space: %v", err))
+		return nil
+	}
+
+	// the archives are extracted completely
+	members := map[string]map[string]int64{}
+	for _, k := range keys {
+		f := desired[k]
+		if f.archiveSHA == "" || members[f.archiveSHA] != nil {
+			continue
+		}
+		ap, err := d.Blobs.Locate(blobstore.KindArchive, f.archiveSHA)
+		if err != nil {
+			return err
+		}
+		sizes, err := archive.Sizes(ctx, d.Bsdtar, ap)
+		if err != nil {
+			return warn(fmt.Errorf("archive %s: %w", shortSHA(f.archiveSHA), err))
+		}
+		members[f.archiveSHA] = sizes
+
+		var total int64
+		for _, n := range sizes {
+			total += n
+		}
+		if err := add(d.Blobs.TmpDir, total); err != nil {
+			return warn(err)
+		}
+	}
+
+	linked := map[string]bool{}
+	for _, k := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		f := desired[k]
+		dst := filepath.Join(f.target.root, filepath.FromSlash(f.relpath))
+
+		// like Apply: extracted files can be hardlinked once, and blobs
+		// are only cloned or copied
+		var size int64
+		m := f.target.method(false)
+		if f.rendered != nil {
+			size = int64(len(f.rendered))
+		} else if f.overrideID != 0 {
+			src, err := d.Blobs.PathFor(blobstore.KindOverride, f.blobSHA)
+			if err != nil {
+				return err
+			}
+			st, err := os.Stat(src)
+			if err != nil {
+				return warn(err)
+			}
+			size = st.Size()
+		} else {
+			src := f.archiveSHA + "/" + f.member
+			size = members[f.archiveSHA][f.member]
+			if !linked[src] {
+				m = f.target.method(true)
+			}
+			if m == deploy.Hardlink {
+				linked[src] = true
+			}
+		}
+		if m == deploy.Copy {
+			if err := add(f.target.root, size); err != nil {
+				return warn(err)
+			}
+		}
+
+		// whatever is there now is replaced, and backed up unless modctl
+		// deployed it, Steam can restore it, or the target says not to
+		st, err := os.Lstat(dst)
+		if err != nil || !st.Mode().IsRegular() {
+			continue
+		}
+		if err := add(f.target.root, -st.Size()); err != nil {
+			return warn(err)
+		}
+		if _, owned := byKey[k]; owned {
+			continue
+		}
+		if vf, ok := f.target.vanilla.Lookup(f.relpath); ok && vf.Restorable() && vf.Size == st.Size() {
+			continue
+		}
+		if f.target.row.BackupPolicy == BackupPolicyNone {
+			continue
+		}
+		if err := add(d.Blobs.BackupsDir, st.Size()); err != nil {
+			return warn(err)
+		}
+	}
+
+	shortages, err := needs.shortages(fscaps.Free)
+	if err != nil {
+		return warn(err)
+	}
+	if len(shortages) > 0 {
+		sort.Slice(shortages, func(i, j int) bool { return shortages[i].Path < shortages[j].Path })
+		return &InsufficientSpaceError{Shortages: shortages}
+	}
+	return nil
+}
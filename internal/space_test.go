@@ -0,0 +1,48 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpaceNeeds(t *testing.T) {
+	t.Parallel()
+
+	var n spaceNeeds
+	n.add(1, "/tmp/modctl", 300)
+	n.add(2, "/games/skyrim", 500)
+	// the files that are replaced free their space
+	n.add(2, "/games/skyrim", -200)
+	n.add(1, "/data/backups", 200)
+	n.add(3, "/home/user/.config", -100)
+
+	free := map[string]uint64{"/tmp/modctl": 400, "/games/skyrim": 1000}
+	shortages, err := n.shortages(func(path string) (uint64, error) {
+		return free[path], nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []SpaceShortage{{Path: "/tmp/modctl", Needed: 500, Free: 400}}, shortages)
+
+	err = &InsufficientSpaceError{Shortages: shortages}
+	assert.Equal(t, "not enough free space: /tmp/modctl needs 500 B but only 400 B are free", err.Error())
+}
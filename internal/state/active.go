@@ -22,47 +22,137 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
+	"sync"
 	"time"
 
-	"github.com/adrg/xdg"
+	"github.com/mfinelli/modctl/internal/readonly"
 )
 
+// ActiveVersion is the version of the active.json format. Files written
+// before it was versioned are version 0 (they're read the same way, but
+// might lack the selector and store of the active game).
+const ActiveVersion = 1
+
 type Active struct {
+	Version                   int    `json:"version"`
 	ActiveStoreID             string `json:"active_store_id,omitempty"`
 	ActiveGameInstallID       int64  `json:"active_game_install_id,omitempty"`
 	ActiveGameInstallSelector string `json:"active_game_install_selector,omitempty"`
 	UpdatedAt                 string `json:"updated_at,omitempty"`
 }
 
+var warnActiveOnce sync.Once
+
+// LoadActive returns the active selection. A missing active.json is an
+// empty selection, and so is one that can't be used (it's corrupt or was
+// written by a newer modctl): a warning is printed instead of failing every
+// command, `modctl context repair` resets it.
 func LoadActive() (Active, error) {
-	p, err := xdg.StateFile(filepath.Join("modctl", "active.json"))
+	a, problem, err := ReadActive()
 	if err != nil {
 		return Active{}, err
 	}
+	if problem != nil {
+		warnActiveOnce.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring the active selection: %v "+
+				"(run `modctl context repair` to reset it)\n", problem)
+		})
+		return Active{}, nil
+	}
+	return a, nil
+}
+
+// ReadActive reads active.json, upgrading older versions of it. If it can't
+// be used, problem says why (err is only returned if it can't be read).
+func ReadActive() (a Active, problem error, err error) {
+	p, err := activePath()
+	if err != nil {
+		return Active{}, nil, err
+	}
 
 	b, err := os.ReadFile(p)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return Active{}, nil
+			return Active{Version: ActiveVersion}, nil, nil
 		}
-		return Active{}, fmt.Errorf("read %s: %w", p, err)
+		return Active{}, nil, fmt.Errorf("read %s: %w", p, err)
 	}
 
+	a, problem = parseActive(b)
+	if problem != nil {
+		return Active{}, fmt.Errorf("%s: %w", p, problem), nil
+	}
+	return a, nil, nil
+}
+
+func parseActive(b []byte) (Active, error) {
 	var a Active
 	if err := json.Unmarshal(b, &a); err != nil {
-		return Active{}, fmt.Errorf("parse %s: %w", p, err)
+		return Active{}, fmt.Errorf("parse: %w", err)
+	}
+
+	switch {
+	case a.Version > ActiveVersion:
+		return Active{}, fmt.Errorf("version %d is newer than this modctl supports (%d)",
+			a.Version, ActiveVersion)
+	case a.Version < 0:
+		return Active{}, fmt.Errorf("invalid version %d", a.Version)
+	case a.ActiveGameInstallID < 0:
+		return Active{}, fmt.Errorf("invalid game install id %d", a.ActiveGameInstallID)
 	}
+
+	// version 0 has the same fields, the selector and store are filled in
+	// by `modctl context repair` or the next set-active
+	a.Version = ActiveVersion
 	return a, nil
 }
 
+// ResetActive removes active.json, leaving nothing selected.
+func ResetActive() error {
+	if err := readonly.Check("reset the active selection"); err != nil {
+		return err
+	}
+
+	p, err := activePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %s: %w", p, err)
+	}
+	return nil
+}
+
+func activePath() (string, error) {
+	return Path("active.json")
+}
+
+// Timestamp is the current time in the format of Active.UpdatedAt (which
+// is the one of the timestamps in the database).
+func Timestamp() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// SaveActive writes a new active selection to active.json. Use
+// internal.SaveActive to record it in the database as well.
 func SaveActive(a Active) error {
-	p, err := xdg.StateFile(filepath.Join("modctl", "active.json"))
+	a.UpdatedAt = Timestamp()
+	return WriteActive(a)
+}
+
+// WriteActive writes an active selection to active.json as it is (i.e.,
+// with its UpdatedAt), e.g., one that was copied from the database.
+func WriteActive(a Active) error {
+	if err := readonly.Check("save the active selection"); err != nil {
+		return err
+	}
+
+	p, err := activePath()
 	if err != nil {
 		return err
 	}
 
-	a.UpdatedAt = time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	a.Version = ActiveVersion
 
 	b, err := json.MarshalIndent(a, "", "  ")
 	if err != nil {
@@ -0,0 +1,66 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Active
+		wantErr bool
+	}{
+		{
+			name:  "current",
+			input: `{"version": 1, "active_store_id": "steam", "active_game_install_id": 3, "active_game_install_selector": "steam:100#default"}`,
+			want:  Active{Version: 1, ActiveStoreID: "steam", ActiveGameInstallID: 3, ActiveGameInstallSelector: "steam:100#default"},
+		},
+		{
+			name:  "unversioned",
+			input: `{"active_game_install_id": 3}`,
+			want:  Active{Version: ActiveVersion, ActiveGameInstallID: 3},
+		},
+		{name: "corrupt", input: `{"active_game_install_id": 3`, wantErr: true},
+		{name: "wrong type", input: `{"active_game_install_id": "3"}`, wantErr: true},
+		{name: "newer", input: `{"version": 99}`, wantErr: true},
+		{name: "negative id", input: `{"active_game_install_id": -1}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseActive([]byte(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
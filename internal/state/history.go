@@ -0,0 +1,112 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package state
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/mfinelli/modctl/internal/readonly"
+)
+
+// History is the command history of `modctl shell`. It keeps the last max
+// lines in memory and appends new lines to the history file.
+type History struct {
+	path  string
+	max   int
+	lines []string
+}
+
+// LoadHistory reads the last max lines of the shell history.
+func LoadHistory(max int) (*History, error) {
+	p, err := Path("shell_history")
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{path: p, max: max}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", p, err)
+	}
+	defer f.Close()
+
+	total := 0
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		h.push(s.Text())
+		total++
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", p, err)
+	}
+
+	// don't let the file grow forever
+	if total > 2*max && !readonly.Enabled() {
+		var b []byte
+		for _, l := range h.lines {
+			b = append(b, l...)
+			b = append(b, '\n')
+		}
+		_ = os.WriteFile(p, b, 0o600)
+	}
+
+	return h, nil
+}
+
+// Add records a line; it's best-effort: a history file that can't be
+// written only means that the line is forgotten when the shell exits.
+func (h *History) Add(line string) {
+	if line == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return
+	}
+	h.push(line)
+	if readonly.Enabled() {
+		return
+	}
+
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// Len returns the number of lines in the history.
+func (h *History) Len() int {
+	return len(h.lines)
+}
+
+// At returns a line of the history, 0 being the most recent one.
+func (h *History) At(idx int) string {
+	return h.lines[len(h.lines)-1-idx]
+}
+
+func (h *History) push(line string) {
+	h.lines = append(h.lines, line)
+	if len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+}
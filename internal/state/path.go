@@ -0,0 +1,48 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/spf13/viper"
+)
+
+// Path returns the path of a file in the state directory of the data
+// directory (state_dir), creating the directory if it doesn't exist. With
+// --read-only it isn't created: the file may not exist then.
+func Path(name string) (string, error) {
+	dir := viper.GetString("state_dir")
+	if dir == "" {
+		// the config wasn't loaded
+		dir = filepath.Join(xdg.StateHome, "modctl")
+	}
+
+	if readonly.Enabled() {
+		return filepath.Join(dir, name), nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create state dir: %w", err)
+	}
+	return filepath.Join(dir, name), nil
+}
@@ -0,0 +1,48 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/readonly"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	viper.Set("state_dir", dir)
+	t.Cleanup(func() { viper.Set("state_dir", nil) })
+	t.Cleanup(readonly.Reset)
+
+	readonly.Enable()
+	p, err := Path("active.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "active.json"), p)
+	assert.NoDirExists(t, dir, "read-only runs must not create the state dir")
+
+	readonly.Reset()
+	p, err = Path("active.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "active.json"), p)
+	assert.DirExists(t, dir)
+}
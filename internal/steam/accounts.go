@@ -0,0 +1,199 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/vdf"
+)
+
+// steamID64Base is the 64-bit steam id of account id 0 (individual account
+// in the public universe); the account id is the lower 32 bits.
+const steamID64Base = 76561197960265728
+
+// Account is a steam account that has logged in on this machine.
+type Account struct {
+	SteamID64   uint64
+	AccountName string // login name
+	PersonaName string // display name
+	MostRecent  bool   // the account that logged in last
+	Userdata    string // <steam>/userdata/<account id>, empty if it doesn't exist
+}
+
+// AccountID returns the 32-bit account id, which is what the userdata
+// directories are named after.
+func (a Account) AccountID() uint32 {
+	return uint32(a.SteamID64 - steamID64Base)
+}
+
+// String returns the persona name and the login name of the account, or
+// its id if we don't know them.
+func (a Account) String() string {
+	switch {
+	case a.PersonaName != "" && a.AccountName != "":
+		return fmt.Sprintf("%s (%s)", a.PersonaName, a.AccountName)
+	case a.AccountName != "":
+		return a.AccountName
+	default:
+		return strconv.FormatUint(uint64(a.AccountID()), 10)
+	}
+}
+
+// LoadAccounts returns the accounts of a steam installation: the ones in
+// config/loginusers.vdf and the ones that only have a userdata directory
+// (e.g., after steam forgot a login), ordered by account id.
+func LoadAccounts(steamRoot string) ([]Account, error) {
+	byID := map[uint64]*Account{}
+
+	users, err := readLoginUsers(filepath.Join(steamRoot, "config", "loginusers.vdf"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for i := range users {
+		byID[users[i].SteamID64] = &users[i]
+	}
+
+	entries, err := os.ReadDir(filepath.Join(steamRoot, "userdata"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		id, err := strconv.ParseUint(e.Name(), 10, 32)
+		// 0 is the anonymous user
+		if err != nil || id == 0 || !e.IsDir() {
+			continue
+		}
+		sid := steamID64Base + id
+		if _, ok := byID[sid]; !ok {
+			byID[sid] = &Account{SteamID64: sid}
+		}
+		byID[sid].Userdata = filepath.Join(steamRoot, "userdata", e.Name())
+	}
+
+	out := make([]Account, 0, len(byID))
+	for _, a := range byID {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SteamID64 < out[j].SteamID64 })
+
+	return out, nil
+}
+
+func readLoginUsers(path string) ([]Account, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	parsed, err := vdf.NewParser(f).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	users, ok := parsed["users"].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+
+	var out []Account
+	for k, v := range users {
+		sid, err := strconv.ParseUint(k, 10, 64)
+		if err != nil || sid <= steamID64Base {
+			continue
+		}
+		u, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		a := Account{SteamID64: sid}
+		a.AccountName, _ = u["AccountName"].(string)
+		a.PersonaName, _ = u["PersonaName"].(string)
+		mostRecent, _ := u["MostRecent"].(string)
+		a.MostRecent = mostRecent == "1"
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// AppOwner returns the 64-bit steam id of the account that steam installed
+// or last updated a game for (LastOwner in its appmanifest_<appid>.acf), 0 if
+// the manifest doesn't say. With Family Sharing this can be an account that
+// borrows the game rather than the one that bought it.
+func AppOwner(appManifest string) (uint64, error) {
+	appState, err := readAppState(appManifest)
+	if err != nil {
+		return 0, err
+	}
+
+	s, _ := appState["LastOwner"].(string)
+	owner, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil || owner <= steamID64Base {
+		return 0, nil
+	}
+	return owner, nil
+}
+
+// PickAccount chooses the account whose userdata belongs to a game: the one
+// given by want (its login name, persona name, account id, or 64-bit steam
+// id) if it isn't empty, otherwise the owner from the appmanifest (see
+// AppOwner) if it's one of the accounts, otherwise the account that logged in
+// last, otherwise the only account.
+func PickAccount(accounts []Account, owner uint64, want string) (Account, error) {
+	if want = strings.TrimSpace(want); want != "" {
+		for _, a := range accounts {
+			if strings.EqualFold(a.AccountName, want) || strings.EqualFold(a.PersonaName, want) ||
+				strconv.FormatUint(a.SteamID64, 10) == want ||
+				strconv.FormatUint(uint64(a.AccountID()), 10) == want {
+				return a, nil
+			}
+		}
+		return Account{}, fmt.Errorf("no steam account %q on this machine", want)
+	}
+
+	if owner != 0 {
+		for _, a := range accounts {
+			if a.SteamID64 == owner {
+				return a, nil
+			}
+		}
+	}
+
+	for _, a := range accounts {
+		if a.MostRecent {
+			return a, nil
+		}
+	}
+
+	if len(accounts) == 1 {
+		return accounts[0], nil
+	}
+	if len(accounts) == 0 {
+		return Account{}, errors.New("no steam accounts found")
+	}
+	return Account{}, errors.New("several steam accounts and none logged in last; set steam_account")
+}
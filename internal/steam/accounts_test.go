@@ -0,0 +1,119 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLoginUsers = `"users"
+{
+	"76561197960265729"
+	{
+		"AccountName"		"alice"
+		"PersonaName"		"Alice"
+		"MostRecent"		"0"
+	}
+	"76561197960265730"
+	{
+		"AccountName"		"bob"
+		"PersonaName"		"Bobby"
+		"MostRecent"		"1"
+	}
+}
+`
+
+func TestLoadAccounts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config", "loginusers.vdf"),
+		[]byte(testLoginUsers), 0o644))
+	for _, d := range []string{"0", "1", "3"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "userdata", d), 0o755))
+	}
+
+	accounts, err := LoadAccounts(root)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+
+	assert.Equal(t, "Alice (alice)", accounts[0].String())
+	assert.Equal(t, uint32(1), accounts[0].AccountID())
+	assert.Equal(t, filepath.Join(root, "userdata", "1"), accounts[0].Userdata)
+	assert.True(t, accounts[1].MostRecent)
+	assert.Empty(t, accounts[1].Userdata)
+	assert.Equal(t, "3", accounts[2].String())
+
+	tests := []struct {
+		name  string
+		owner uint64
+		want  string
+		id    uint32
+		err   bool
+	}{
+		{name: "most recent", id: 2},
+		{name: "owner", owner: 76561197960265729, id: 1},
+		{name: "unknown owner", owner: 76561197960265799, id: 2},
+		{name: "login name", want: "ALICE", id: 1},
+		{name: "persona name", want: "bobby", id: 2},
+		{name: "account id", want: "3", id: 3},
+		{name: "steam id", want: "76561197960265729", id: 1},
+		{name: "no such account", want: "carol", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := PickAccount(accounts, tt.owner, tt.want)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, a.AccountID())
+		})
+	}
+}
+
+func TestAppOwner(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	acf := filepath.Join(dir, "appmanifest_1091500.acf")
+	require.NoError(t, os.WriteFile(acf, []byte(testAppManifest), 0o644))
+
+	owner, err := AppOwner(acf)
+	require.NoError(t, err)
+	assert.Zero(t, owner)
+
+	withOwner := `"AppState"
+{
+	"appid"		"1091500"
+	"LastOwner"		"76561197960265730"
+}
+`
+	require.NoError(t, os.WriteFile(acf, []byte(withOwner), 0o644))
+	owner, err = AppOwner(acf)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(76561197960265730), owner)
+}
@@ -0,0 +1,144 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/vdf"
+)
+
+// readAppState returns the AppState of an appmanifest_<appid>.acf.
+func readAppState(appManifest string) (map[string]any, error) {
+	f, err := os.Open(appManifest)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	parsed, err := vdf.NewParser(f).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", appManifest, err)
+	}
+
+	appStateAny, ok := parsed["AppState"]
+	if !ok {
+		appStateAny = parsed["appstate"]
+	}
+	appState, ok := appStateAny.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: missing AppState", appManifest)
+	}
+
+	return appState, nil
+}
+
+// AppBuildID returns the build of a game that Steam has installed (buildid in
+// its appmanifest_<appid>.acf; every update of a game is a new build), "" if
+// the appmanifest doesn't say.
+func AppBuildID(appManifest string) (string, error) {
+	appState, err := readAppState(appManifest)
+	if err != nil {
+		return "", err
+	}
+
+	s, _ := appState["buildid"].(string)
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return "", nil
+	}
+	return s, nil
+}
+
+// The bits of StateFlags in an appmanifest_<appid>.acf (EAppState) that say
+// what Steam is doing with the files of an app.
+const (
+	StateUpdateRequired = 1 << 1
+	StateFullyInstalled = 1 << 2
+	StateUpdateRunning  = 1 << 8
+	StateUpdatePaused   = 1 << 9
+	StateUpdateStarted  = 1 << 10
+	StateUninstalling   = 1 << 11
+	StateBackupRunning  = 1 << 12
+	StateReconfiguring  = 1 << 16
+	StateValidating     = 1 << 17
+	StateAddingFiles    = 1 << 18
+	StatePreallocating  = 1 << 19
+	StateDownloading    = 1 << 20
+	StateStaging        = 1 << 21
+	StateCommitting     = 1 << 22
+	StateUpdateStopping = 1 << 23
+)
+
+// AppStateFlags returns the StateFlags of an appmanifest_<appid>.acf (0 if
+// it doesn't have any).
+func AppStateFlags(appManifest string) (uint64, error) {
+	appState, err := readAppState(appManifest)
+	if err != nil {
+		return 0, err
+	}
+
+	s, _ := appState["StateFlags"].(string)
+	if s == "" {
+		s, _ = appState["stateflags"].(string)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	flags, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid StateFlags %q", appManifest, s)
+	}
+	return flags, nil
+}
+
+// BusyReason describes what Steam is doing with the files of an app
+// according to its StateFlags (e.g., "downloading an update"), "" if it
+// leaves them alone. A queued update doesn't touch them yet but a paused one
+// has already started (and left files half updated, or is about to).
+func BusyReason(flags uint64) string {
+	for _, s := range []struct {
+		flag   uint64
+		reason string
+	}{
+		{StateCommitting, "installing an update"},
+		{StateStaging, "installing an update"},
+		{StateDownloading, "downloading an update"},
+		{StatePreallocating, "preparing an update"},
+		{StateAddingFiles, "installing files"},
+		{StateValidating, "verifying the game files"},
+		{StateReconfiguring, "reconfiguring the game"},
+		{StateBackupRunning, "backing up the game"},
+		{StateUninstalling, "uninstalling the game"},
+		{StateUpdateStopping, "stopping an update"},
+		{StateUpdatePaused, "an update is paused"},
+		{StateUpdateRunning, "updating the game"},
+		{StateUpdateStarted, "updating the game"},
+	} {
+		if flags&s.flag != 0 {
+			return s.reason
+		}
+	}
+	return ""
+}
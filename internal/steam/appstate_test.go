@@ -0,0 +1,98 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppBuildID(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	acf := filepath.Join(dir, "appmanifest_1091500.acf")
+
+	require.NoError(t, os.WriteFile(acf, []byte(testAppManifest), 0o644))
+	build, err := AppBuildID(acf)
+	require.NoError(t, err)
+	assert.Empty(t, build)
+
+	withBuild := `"AppState"
+{
+	"appid"		"1091500"
+	"buildid"		"15466421"
+}
+`
+	require.NoError(t, os.WriteFile(acf, []byte(withBuild), 0o644))
+	build, err = AppBuildID(acf)
+	require.NoError(t, err)
+	assert.Equal(t, "15466421", build)
+
+	_, err = AppBuildID(filepath.Join(dir, "missing.acf"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(acf, []byte(`"Other" { }`), 0o644))
+	_, err = AppBuildID(acf)
+	assert.Error(t, err)
+}
+
+func TestAppStateFlags(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	acf := filepath.Join(dir, "appmanifest_1091500.acf")
+
+	require.NoError(t, os.WriteFile(acf, []byte(`"AppState"
+{
+	"appid"		"1091500"
+	"StateFlags"		"1030"
+}
+`), 0o644))
+	flags, err := AppStateFlags(acf)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(StateUpdateRequired|StateFullyInstalled|StateUpdateStarted), flags)
+
+	require.NoError(t, os.WriteFile(acf, []byte(testAppManifest), 0o644))
+	flags, err = AppStateFlags(acf)
+	require.NoError(t, err)
+	assert.Zero(t, flags)
+
+	require.NoError(t, os.WriteFile(acf, []byte(`"AppState" { "StateFlags" "lots" }`), 0o644))
+	_, err = AppStateFlags(acf)
+	assert.Error(t, err)
+}
+
+func TestBusyReason(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, BusyReason(StateFullyInstalled))
+	// queued, not started
+	assert.Empty(t, BusyReason(StateFullyInstalled|StateUpdateRequired))
+	assert.Equal(t, "updating the game", BusyReason(StateFullyInstalled|StateUpdateRequired|StateUpdateStarted))
+	assert.Equal(t, "downloading an update",
+		BusyReason(StateFullyInstalled|StateUpdateRequired|StateUpdateRunning|StateUpdateStarted|StateDownloading))
+	assert.Equal(t, "an update is paused",
+		BusyReason(StateFullyInstalled|StateUpdateRequired|StateUpdatePaused|StateUpdateStarted))
+	assert.Equal(t, "verifying the game files", BusyReason(StateFullyInstalled|StateValidating))
+}
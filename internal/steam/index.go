@@ -0,0 +1,235 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/andygrunwald/vdf"
+)
+
+// Index knows the vanilla files of a game install, from the depot manifests
+// of its installed depots.
+type Index struct {
+	files  map[string]File
+	folded map[string]string // lower-case name -> name
+}
+
+// NewIndex builds an index of the files of the given manifests; later
+// manifests win if several list the same file.
+func NewIndex(manifests ...*Manifest) *Index {
+	ix := &Index{files: map[string]File{}, folded: map[string]string{}}
+	for _, m := range manifests {
+		for _, f := range m.Files {
+			if f.Name == "" {
+				continue
+			}
+			ix.files[f.Name] = f
+			ix.folded[strings.ToLower(f.Name)] = f.Name
+		}
+	}
+	return ix
+}
+
+// Len returns the number of files and directories in the index.
+func (ix *Index) Len() int {
+	if ix == nil {
+		return 0
+	}
+	return len(ix.files)
+}
+
+// Files returns the files of the index that verifying the game files in
+// Steam puts back (see File.Restorable), sorted by name.
+func (ix *Index) Files() []File {
+	if ix == nil {
+		return nil
+	}
+	var out []File
+	for _, f := range ix.files {
+		if f.Restorable() {
+			out = append(out, f)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Lookup returns the vanilla file at relpath. Windows games don't care about
+// case, so a file whose case differs is found too.
+func (ix *Index) Lookup(relpath string) (File, bool) {
+	if ix == nil {
+		return File{}, false
+	}
+	if f, ok := ix.files[relpath]; ok {
+		return f, true
+	}
+	if name, ok := ix.folded[strings.ToLower(relpath)]; ok {
+		return ix.files[name], true
+	}
+	return File{}, false
+}
+
+// Matches reports whether the file at path (the file at relpath of the game
+// install) is the vanilla one that Steam can restore.
+func (ix *Index) Matches(relpath, path string) (bool, error) {
+	f, ok := ix.Lookup(relpath)
+	if !ok || !f.Restorable() || f.SHA1 == "" {
+		return false, nil
+	}
+
+	st, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !st.Mode().IsRegular() || st.Size() != f.Size {
+		return false, nil
+	}
+
+	sum, err := sha1File(path)
+	if err != nil {
+		return false, err
+	}
+	return sum == f.SHA1, nil
+}
+
+func sha1File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// InstalledDepots returns the depots (depot id -> manifest id) that an
+// appmanifest_<appid>.acf lists as installed.
+func InstalledDepots(appManifest string) (map[string]string, error) {
+	f, err := os.Open(appManifest)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	parsed, err := vdf.NewParser(f).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", appManifest, err)
+	}
+
+	appStateAny, ok := parsed["AppState"]
+	if !ok {
+		appStateAny = parsed["appstate"]
+	}
+	appState, ok := appStateAny.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: missing AppState", appManifest)
+	}
+	depots, _ := appState["InstalledDepots"].(map[string]any)
+
+	out := make(map[string]string, len(depots))
+	for id, v := range depots {
+		d, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if gid, ok := d["manifest"].(string); ok && gid != "" {
+			out[id] = gid
+		}
+	}
+	return out, nil
+}
+
+// LoadIndex builds the index of a game from the depots listed in its
+// appmanifest, looking for their manifests (<depot>_<manifest>.manifest) in
+// the given depotcache directories. Depots whose manifest can't be found or
+// read are skipped with a warning.
+func LoadIndex(appManifest string, depotcaches []string) (*Index, []string, error) {
+	depots, err := InstalledDepots(appManifest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]string, 0, len(depots))
+	for id := range depots {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var warnings []string
+	var manifests []*Manifest
+	for _, id := range ids {
+		name := id + "_" + depots[id] + ".manifest"
+
+		path := ""
+		for _, dir := range depotcaches {
+			p := filepath.Join(dir, name)
+			if st, err := os.Stat(p); err == nil && st.Mode().IsRegular() {
+				path = p
+				break
+			}
+		}
+		if path == "" {
+			warnings = append(warnings, fmt.Sprintf("depot %s: manifest %s is not cached", id, depots[id]))
+			continue
+		}
+
+		m, err := readManifest(path)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("depot %s: %v", id, err))
+			continue
+		}
+		if m.FilenamesEncrypted {
+			warnings = append(warnings, fmt.Sprintf("depot %s: manifest has encrypted file names", id))
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+
+	return NewIndex(manifests...), warnings, nil
+}
+
+func readManifest(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := ParseManifest(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return m, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAppManifest = `"AppState"
+{
+	"appid"		"1091500"
+	"name"		"Cyberpunk 2077"
+	"installdir"		"Cyberpunk 2077"
+	"InstalledDepots"
+	{
+		"1091501"
+		{
+			"manifest"		"111"
+			"size"		"100"
+		}
+		"1091502"
+		{
+			"manifest"		"222"
+			"size"		"100"
+		}
+		"1091503"
+		{
+			"manifest"		"333"
+			"size"		"100"
+		}
+	}
+}
+`
+
+func TestLoadIndex(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	acf := filepath.Join(dir, "appmanifest_1091500.acf")
+	require.NoError(t, os.WriteFile(acf, []byte(testAppManifest), 0o644))
+
+	cache := filepath.Join(dir, "depotcache")
+	require.NoError(t, os.MkdirAll(cache, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(cache, "1091501_111.manifest"),
+		buildManifest(1091501, 111, false,
+			testFile{name: "bin\\x64\\game.exe", content: "exe"},
+			testFile{name: "settings.ini", content: "cfg", flags: FlagUserConfig},
+		), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(cache, "1091502_222.manifest"),
+		buildManifest(1091502, 222, true, testFile{name: "encrypted", content: "x"}), 0o644))
+
+	ix, warnings, err := LoadIndex(acf, []string{filepath.Join(dir, "missing"), cache})
+	require.NoError(t, err)
+	assert.Len(t, warnings, 2) // encrypted, and 1091503 isn't cached
+	assert.Equal(t, 2, ix.Len())
+
+	// not the user config
+	files := ix.Files()
+	require.Len(t, files, 1)
+	assert.Equal(t, "bin/x64/game.exe", files[0].Name)
+
+	f, ok := ix.Lookup("BIN/x64/Game.exe")
+	require.True(t, ok)
+	assert.Equal(t, "bin/x64/game.exe", f.Name)
+
+	game := filepath.Join(dir, "game")
+	exe := filepath.Join(game, "bin", "x64", "game.exe")
+	require.NoError(t, os.MkdirAll(filepath.Dir(exe), 0o755))
+
+	require.NoError(t, os.WriteFile(exe, []byte("exe"), 0o644))
+	ok, err = ix.Matches("bin/x64/game.exe", exe)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, os.WriteFile(exe, []byte("mod"), 0o644))
+	ok, err = ix.Matches("bin/x64/game.exe", exe)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// steam doesn't restore user config
+	ini := filepath.Join(game, "settings.ini")
+	require.NoError(t, os.WriteFile(ini, []byte("cfg"), 0o644))
+	ok, err = ix.Matches("settings.ini", ini)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = ix.Matches("bin/x64/missing.dll", filepath.Join(game, "missing.dll"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
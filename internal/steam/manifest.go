@@ -0,0 +1,244 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package steam reads Steam's local depot manifests to find out which files
+// of a game install are vanilla, i.e. the ones that Steam itself installed
+// and can restore by verifying the integrity of the game files, and the
+// accounts that use Steam on this machine.
+package steam
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// the sections of a depot manifest, each is a little-endian magic and
+// length followed by a protobuf message
+const (
+	magicPayload  = 0x71F617D0
+	magicMetadata = 0x1F4812BE
+	magicSig      = 0x1B81B817
+	magicEnd      = 0x32C415AB
+)
+
+// File flags (EDepotFileFlag) that we care about.
+const (
+	FlagUserConfig          = 1
+	FlagVersionedUserConfig = 2
+	FlagDirectory           = 64
+	FlagSymlink             = 512
+)
+
+// Manifest is a parsed depot manifest.
+type Manifest struct {
+	DepotID            uint32
+	ManifestID         uint64
+	FilenamesEncrypted bool
+	Files              []File
+}
+
+// File is a file (or directory) of a depot.
+type File struct {
+	Name  string // relative to the install dir, with forward slashes
+	Size  int64
+	Flags uint32
+	SHA1  string // hex sha1 of the whole file
+}
+
+func (f File) IsDir() bool {
+	return f.Flags&FlagDirectory != 0
+}
+
+// Restorable reports whether verifying the game files in Steam puts this
+// file back: Steam leaves (versioned) user config files alone once they
+// exist.
+func (f File) Restorable() bool {
+	return f.Flags&(FlagDirectory|FlagSymlink|FlagUserConfig|FlagVersionedUserConfig) == 0
+}
+
+// ParseManifest reads a depot manifest as stored in Steam's depotcache.
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	br := bufio.NewReader(r)
+	m := &Manifest{}
+	sawPayload := false
+
+	for {
+		var magic uint32
+		if err := binary.Read(br, binary.LittleEndian, &magic); err != nil {
+			if errors.Is(err, io.EOF) && sawPayload {
+				return m, nil
+			}
+			return nil, fmt.Errorf("read section: %w", err)
+		}
+		if magic == magicEnd {
+			break
+		}
+
+		var n uint32
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return nil, fmt.Errorf("read section length: %w", err)
+		}
+		// no manifest comes anywhere close to this
+		if n > 1<<30 {
+			return nil, fmt.Errorf("section too large: %d bytes", n)
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, fmt.Errorf("read section: %w", err)
+		}
+
+		switch magic {
+		case magicPayload:
+			if err := m.parsePayload(buf); err != nil {
+				return nil, fmt.Errorf("payload: %w", err)
+			}
+			sawPayload = true
+		case magicMetadata:
+			if err := m.parseMetadata(buf); err != nil {
+				return nil, fmt.Errorf("metadata: %w", err)
+			}
+		case magicSig:
+			// not verified
+		default:
+			return nil, fmt.Errorf("unknown section %#x (not a depot manifest?)", magic)
+		}
+	}
+
+	if !sawPayload {
+		return nil, fmt.Errorf("no file list (not a depot manifest?)")
+	}
+	return m, nil
+}
+
+// ContentManifestPayload: repeated FileMapping mappings = 1
+func (m *Manifest) parsePayload(b []byte) error {
+	return eachField(b, func(num int, wire int, v uint64, data []byte) error {
+		if num != 1 || wire != wireBytes {
+			return nil
+		}
+		f, err := parseFileMapping(data)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, f)
+		return nil
+	})
+}
+
+// ContentManifestMetadata: depot_id = 1, gid_manifest = 2,
+// filenames_encrypted = 4
+func (m *Manifest) parseMetadata(b []byte) error {
+	return eachField(b, func(num int, wire int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			m.DepotID = uint32(v)
+		case 2:
+			m.ManifestID = v
+		case 4:
+			m.FilenamesEncrypted = v != 0
+		}
+		return nil
+	})
+}
+
+// FileMapping: filename = 1, size = 2, flags = 3, sha_content = 5
+func parseFileMapping(b []byte) (File, error) {
+	var f File
+	err := eachField(b, func(num int, wire int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			f.Name = normalizeName(string(data))
+		case 2:
+			f.Size = int64(v)
+		case 3:
+			f.Flags = uint32(v)
+		case 5:
+			f.SHA1 = hex.EncodeToString(data)
+		}
+		return nil
+	})
+	return f, err
+}
+
+// depots of windows games use backslashes
+func normalizeName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	return strings.Trim(strings.TrimRight(name, "\x00"), "/")
+}
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// eachField calls fn for every field of a protobuf message with either its
+// (varint or fixed) value or its bytes.
+func eachField(b []byte, fn func(num int, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return fmt.Errorf("bad field key")
+		}
+		b = b[n:]
+
+		num, wire := int(key>>3), int(key&7)
+		var v uint64
+		var data []byte
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return fmt.Errorf("field %d: bad varint", num)
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return fmt.Errorf("field %d: truncated", num)
+			}
+			v = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return fmt.Errorf("field %d: truncated", num)
+			}
+			v = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return fmt.Errorf("field %d: truncated", num)
+			}
+			data = b[n : n+int(l)]
+			b = b[n+int(l):]
+		default:
+			return fmt.Errorf("field %d: unsupported wire type %d", num, wire)
+		}
+
+		if err := fn(num, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
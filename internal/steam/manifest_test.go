@@ -0,0 +1,135 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// protobuf encoding, just enough to write test manifests
+
+func pbVarint(b []byte, num int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(num<<3|wireVarint))
+	return binary.AppendUvarint(b, v)
+}
+
+func pbBytes(b []byte, num int, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(num<<3|wireBytes))
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func section(b []byte, magic uint32, data []byte) []byte {
+	b = binary.LittleEndian.AppendUint32(b, magic)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+type testFile struct {
+	name    string
+	content string
+	flags   uint32
+}
+
+func buildManifest(depot uint32, gid uint64, encrypted bool, files ...testFile) []byte {
+	var payload []byte
+	for _, f := range files {
+		sum := sha1.Sum([]byte(f.content))
+		var fm []byte
+		fm = pbBytes(fm, 1, []byte(f.name))
+		fm = pbVarint(fm, 2, uint64(len(f.content)))
+		fm = pbVarint(fm, 3, uint64(f.flags))
+		fm = pbBytes(fm, 4, []byte("ignored"))
+		fm = pbBytes(fm, 5, sum[:])
+		// a chunk, which should be skipped
+		fm = pbBytes(fm, 6, pbVarint(nil, 3, 42))
+		payload = pbBytes(payload, 1, fm)
+	}
+
+	var meta []byte
+	meta = pbVarint(meta, 1, uint64(depot))
+	meta = pbVarint(meta, 2, gid)
+	meta = pbVarint(meta, 3, 1700000000)
+	if encrypted {
+		meta = pbVarint(meta, 4, 1)
+	}
+
+	var b []byte
+	b = section(b, magicPayload, payload)
+	b = section(b, magicMetadata, meta)
+	b = section(b, magicSig, []byte("signature"))
+	return binary.LittleEndian.AppendUint32(b, magicEnd)
+}
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	raw := buildManifest(1091501, 1234567890123, false,
+		testFile{name: "bin\\x64\\Cyberpunk2077.exe", content: "exe", flags: 32},
+		testFile{name: "bin\\x64", flags: FlagDirectory},
+		testFile{name: "engine\\config\\settings.ini", content: "cfg", flags: FlagUserConfig},
+	)
+
+	m, err := ParseManifest(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(1091501), m.DepotID)
+	assert.Equal(t, uint64(1234567890123), m.ManifestID)
+	assert.False(t, m.FilenamesEncrypted)
+	require.Len(t, m.Files, 3)
+
+	sum := sha1.Sum([]byte("exe"))
+	assert.Equal(t, File{
+		Name:  "bin/x64/Cyberpunk2077.exe",
+		Size:  3,
+		Flags: 32,
+		SHA1:  hex.EncodeToString(sum[:]),
+	}, m.Files[0])
+	assert.True(t, m.Files[0].Restorable())
+
+	assert.True(t, m.Files[1].IsDir())
+	assert.False(t, m.Files[1].Restorable())
+	assert.False(t, m.Files[2].Restorable())
+}
+
+func TestParseManifestInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseManifest(bytes.NewReader([]byte("PK\x03\x04 not a manifest")))
+	assert.Error(t, err)
+
+	// truncated
+	raw := buildManifest(1, 2, false, testFile{name: "a", content: "a"})
+	_, err = ParseManifest(bytes.NewReader(raw[:20]))
+	assert.Error(t, err)
+
+	// metadata only
+	var b []byte
+	b = section(b, magicMetadata, pbVarint(nil, 1, 1))
+	b = binary.LittleEndian.AppendUint32(b, magicEnd)
+	_, err = ParseManifest(bytes.NewReader(b))
+	assert.Error(t, err)
+}
@@ -0,0 +1,211 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Shortcut is a non-steam game that was added to a steam library (they're
+// kept in userdata/<account id>/config/shortcuts.vdf).
+type Shortcut struct {
+	AppID         uint32 // also the name of its compatdata directory
+	Name          string
+	Exe           string
+	StartDir      string
+	LaunchOptions string
+	Hidden        bool
+}
+
+// the value types of binary vdf
+const (
+	binMap     = 0x00
+	binString  = 0x01
+	binInt32   = 0x02
+	binFloat32 = 0x03
+	binPointer = 0x04
+	binColor   = 0x06
+	binUint64  = 0x07
+	binEnd     = 0x08
+	binInt64   = 0x0a
+)
+
+// ReadShortcuts reads the shortcuts.vdf at path.
+func ReadShortcuts(path string) ([]Shortcut, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := ParseShortcuts(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// ParseShortcuts parses a shortcuts.vdf, which unlike the other vdf files is
+// binary. The shortcuts come in the order that steam numbered them.
+func ParseShortcuts(r io.Reader) ([]Shortcut, error) {
+	root, err := parseBinaryVDF(bufio.NewReader(r), true)
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := lookupFold(root, "shortcuts").(map[string]any)
+	if !ok {
+		return nil, errors.New("no shortcuts (not a shortcuts.vdf?)")
+	}
+
+	keys := make([]string, 0, len(list))
+	for k := range list {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, _ := strconv.Atoi(keys[i])
+		b, _ := strconv.Atoi(keys[j])
+		return a < b
+	})
+
+	var out []Shortcut
+	for _, k := range keys {
+		m, ok := list[k].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		str := func(key string) string {
+			s, _ := lookupFold(m, key).(string)
+			return s
+		}
+		exe := str("exe")
+		s := Shortcut{
+			Name:          str("appname"),
+			Exe:           unquote(exe),
+			StartDir:      unquote(str("startdir")),
+			LaunchOptions: str("launchoptions"),
+		}
+		hidden, _ := lookupFold(m, "ishidden").(uint32)
+		s.Hidden = hidden != 0
+
+		if id, ok := lookupFold(m, "appid").(uint32); ok && id != 0 {
+			s.AppID = id
+		} else {
+			// older versions of steam didn't store it
+			s.AppID = crc32.ChecksumIEEE([]byte(exe+s.Name)) | 0x80000000
+		}
+
+		out = append(out, s)
+	}
+
+	return out, nil
+}
+
+// parseBinaryVDF reads the entries of a map up to its end marker (or the end
+// of the file for the top level). Numbers are returned as uint32 or uint64.
+func parseBinaryVDF(br *bufio.Reader, top bool) (map[string]any, error) {
+	m := map[string]any{}
+	for {
+		t, err := br.ReadByte()
+		if err != nil {
+			if top && errors.Is(err, io.EOF) {
+				return m, nil
+			}
+			return nil, fmt.Errorf("read type: %w", err)
+		}
+		if t == binEnd {
+			return m, nil
+		}
+
+		key, err := readCString(br)
+		if err != nil {
+			return nil, fmt.Errorf("read key: %w", err)
+		}
+
+		switch t {
+		case binMap:
+			v, err := parseBinaryVDF(br, false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			m[key] = v
+		case binString:
+			v, err := readCString(br)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			m[key] = v
+		case binInt32, binFloat32, binPointer, binColor:
+			var v uint32
+			if err := binary.Read(br, binary.LittleEndian, &v); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			m[key] = v
+		case binUint64, binInt64:
+			var v uint64
+			if err := binary.Read(br, binary.LittleEndian, &v); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			m[key] = v
+		default:
+			return nil, fmt.Errorf("%s: unsupported type %#x", key, t)
+		}
+	}
+}
+
+func readCString(br *bufio.Reader) (string, error) {
+	s, err := br.ReadString(0)
+	if err != nil {
+		return "", err
+	}
+	return s[:len(s)-1], nil
+}
+
+// lookupFold returns the value of a key regardless of its case: steam has
+// written both "AppName" and "appname" over the years.
+func lookupFold(m map[string]any, key string) any {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+// steam quotes the paths of shortcuts
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package steam
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// binary vdf builders
+func vdfMap(key string, entries ...[]byte) []byte {
+	b := append([]byte{binMap}, key...)
+	b = append(b, 0)
+	for _, e := range entries {
+		b = append(b, e...)
+	}
+	return append(b, binEnd)
+}
+
+func vdfString(key, v string) []byte {
+	b := append([]byte{binString}, key...)
+	b = append(b, 0)
+	b = append(b, v...)
+	return append(b, 0)
+}
+
+func vdfInt(key string, v uint32) []byte {
+	b := append([]byte{binInt32}, key...)
+	b = append(b, 0)
+	return binary.LittleEndian.AppendUint32(b, v)
+}
+
+func TestParseShortcuts(t *testing.T) {
+	t.Parallel()
+
+	file := vdfMap("shortcuts",
+		vdfMap("1",
+			vdfString("AppName", "Old Game"),
+			vdfString("Exe", `"/games/old/old.exe"`),
+			vdfString("StartDir", `"/games/old/"`),
+			vdfInt("IsHidden", 1),
+		),
+		vdfMap("0",
+			vdfInt("appid", 3123456789),
+			vdfString("appname", "Some Game"),
+			vdfString("exe", `"/games/some game/game.exe"`),
+			vdfString("StartDir", `"/games/some game/"`),
+			vdfString("LaunchOptions", "-windowed"),
+			vdfMap("tags", vdfString("0", "favorite")),
+		),
+	)
+	file = append(file, binEnd)
+
+	got, err := ParseShortcuts(bytes.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, Shortcut{
+		AppID:         3123456789,
+		Name:          "Some Game",
+		Exe:           "/games/some game/game.exe",
+		StartDir:      "/games/some game/",
+		LaunchOptions: "-windowed",
+	}, got[0])
+
+	assert.Equal(t, "Old Game", got[1].Name)
+	assert.True(t, got[1].Hidden)
+	assert.Equal(t, crc32.ChecksumIEEE([]byte(`"/games/old/old.exe"Old Game`))|0x80000000, got[1].AppID)
+}
+
+func TestParseShortcutsInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseShortcuts(bytes.NewReader(vdfMap("something", vdfString("a", "b"))))
+	assert.Error(t, err)
+
+	// truncated
+	file := vdfMap("shortcuts", vdfMap("0", vdfString("appname", "x")))
+	_, err = ParseShortcuts(bytes.NewReader(file[:len(file)-4]))
+	assert.Error(t, err)
+}
@@ -0,0 +1,162 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/steam"
+)
+
+const (
+	// files that steam wrote into downloading/<appid> this recently mean
+	// that a download is running
+	steamDownloadQuiet = time.Minute
+	// how often WaitForSteam looks again
+	steamPollInterval = 5 * time.Second
+)
+
+// SteamBusyError is returned by apply and unapply while Steam is downloading
+// or updating a game: modctl and Steam would race over the same files.
+type SteamBusyError struct {
+	Game   string
+	Reason string
+}
+
+func (e *SteamBusyError) Error() string {
+	return fmt.Sprintf("steam is busy with %s (%s); try again once it's done", e.Game, e.Reason)
+}
+
+// SteamActivity returns what Steam is doing with the files of a game install
+// right now ("" if nothing, or if it isn't a steam game) and whether an
+// update is queued (it doesn't touch them until it starts).
+func SteamActivity(gi dbq.GameInstall) (string, bool, error) {
+	if gi.StoreID != "steam" || CopyOf(gi) != 0 {
+		return "", false, nil
+	}
+
+	steamapps, err := SteamappsDir(gi)
+	if err != nil {
+		return "", false, err
+	}
+
+	flags, err := steam.AppStateFlags(filepath.Join(steamapps, "appmanifest_"+gi.StoreGameID+".acf"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if reason := steam.BusyReason(flags); reason != "" {
+		return reason, false, nil
+	}
+
+	// steam only writes the appmanifest when it starts and finishes
+	// something, so look at what it's downloading as well
+	active, err := recentlyWritten(filepath.Join(steamapps, "downloading", gi.StoreGameID), steamDownloadQuiet)
+	if err != nil {
+		return "", false, fmt.Errorf("check steam downloads: %w", err)
+	}
+	if active {
+		return "downloading an update", false, nil
+	}
+
+	return "", flags&steam.StateUpdateRequired != 0, nil
+}
+
+// recentlyWritten reports whether anything in dir was modified within the
+// last within (false if there is no dir).
+func recentlyWritten(dir string, within time.Duration) (bool, error) {
+	cutoff := time.Now().Add(-within)
+	found := false
+
+	err := filepath.WalkDir(dir, func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		info, err := de.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info.ModTime().After(cutoff) {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+
+	return found, err
+}
+
+// WaitForSteam waits up to timeout for Steam to leave the files of a game
+// install alone and returns a *SteamBusyError if it doesn't (a timeout of 0
+// doesn't wait at all). waiting, if set, is called with what Steam is doing
+// whenever that changes.
+func WaitForSteam(ctx context.Context, gi dbq.GameInstall, timeout time.Duration, waiting func(reason string)) error {
+	deadline := time.Now().Add(timeout)
+	last := ""
+
+	for {
+		reason, _, err := SteamActivity(gi)
+		if err != nil {
+			return err
+		}
+		if reason == "" {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return &SteamBusyError{Game: gi.DisplayName, Reason: reason}
+		}
+
+		if reason != last && waiting != nil {
+			waiting(reason)
+		}
+		last = reason
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(steamPollInterval):
+		}
+	}
+}
+
+// steamUpdateWarning returns a warning if Steam has an update of a game
+// queued, "" otherwise.
+func steamUpdateWarning(gi dbq.GameInstall) string {
+	_, queued, err := SteamActivity(gi)
+	if err != nil || !queued {
+		return ""
+	}
+	return fmt.Sprintf("steam has an update of %s queued; it may replace deployed files when it's installed "+
+		"(apply the profile again afterwards)", gi.DisplayName)
+}
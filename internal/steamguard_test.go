@@ -0,0 +1,57 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentlyWritten(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "downloading", "1091500")
+
+	active, err := recentlyWritten(dir, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	old := time.Now().Add(-time.Hour)
+	p := filepath.Join(dir, "archive", "pc", "content", "basegame_1.archive")
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte("chunk"), 0o644))
+	for _, q := range []string{p, filepath.Dir(p), filepath.Join(dir, "archive", "pc"), filepath.Join(dir, "archive"), dir} {
+		require.NoError(t, os.Chtimes(q, old, old))
+	}
+
+	// left over from an earlier download
+	active, err = recentlyWritten(dir, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	require.NoError(t, os.WriteFile(p, []byte("another chunk"), 0o644))
+	active, err = recentlyWritten(dir, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, active)
+}
@@ -0,0 +1,50 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfinelli/modctl/dbq"
+)
+
+// KnownStores are the store integrations built into modctl. New stores start
+// out enabled only if we can actually scan them.
+var KnownStores = []dbq.EnsureStoreParams{
+	{ID: "steam", DisplayName: "Steam", Implementation: "steam", Enabled: 1},
+	{ID: SteamShortcutStore, DisplayName: "Steam (non-Steam games)", Implementation: SteamShortcutStore, Enabled: 1},
+}
+
+// SeedStores makes sure that every known store has a row in the stores table
+// without touching existing ones (so user choices like enabled are kept). It
+// returns the ids of the stores that were added.
+func SeedStores(ctx context.Context, q *dbq.Queries) ([]string, error) {
+	added := []string{}
+	for _, s := range KnownStores {
+		n, err := q.EnsureStore(ctx, s)
+		if err != nil {
+			return added, fmt.Errorf("seed store %s: %w", s.ID, err)
+		}
+		if n > 0 {
+			added = append(added, s.ID)
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,418 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/mfinelli/modctl/internal/vars"
+	"github.com/spf13/viper"
+)
+
+// targets.json maps a game (store:game selector, or canonical game id) to the
+// targets, other than game_dir, that most of its mods install into: target
+// name -> path relative to the install root, or a path that starts with one of
+// the targetVarNames variables for targets that live outside of it
+//
+//go:embed targets.json
+var defaultTargetTemplatesJSON []byte
+
+var targetNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// targetVarNames are the variables that a target template can start with.
+// Not all of them exist for every install (e.g., only steam games have a
+// proton prefix). Templates can also have a ${game_version} directory
+// anywhere, see IsVersionedTemplate.
+var targetVarNames = []string{
+	"install_root",
+	"home",
+	"config",
+	"data",
+	"compatdata",
+	"prefix",
+	"documents",
+	"appdata_local",
+	"appdata_roaming",
+	"userdata",
+}
+
+// TargetTemplates returns the default targets of a game: the embedded ones
+// extended (or overridden) by the target_templates config option, e.g.:
+//
+//	[target_templates."steam:413150"]
+//	mods = "Mods"
+//	saves = "${appdata_roaming}/StardewValley/Saves"
+//	versioned = "mods/${game_version}"
+//
+// Setting a target to the empty string removes it.
+func TargetTemplates(storeID, storeGameID string, canonicalGameID sql.NullString) (map[string]string, error) {
+	var embedded map[string]map[string]string
+	if err := json.Unmarshal(defaultTargetTemplatesJSON, &embedded); err != nil {
+		return nil, fmt.Errorf("parse embedded target templates: %w", err)
+	}
+
+	keys := []string{strings.ToLower(storeID + ":" + storeGameID)}
+	if canonicalGameID.Valid && canonicalGameID.String != "" {
+		keys = append(keys, strings.ToLower(canonicalGameID.String))
+	}
+
+	configured := viper.GetStringMap("target_templates")
+
+	templates := map[string]string{}
+	for _, k := range keys {
+		for name, rel := range embedded[k] {
+			templates[name] = rel
+		}
+
+		v, ok := configured[k]
+		if !ok {
+			continue
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("target_templates: %q must be a table of target = \"path\"", k)
+		}
+		for name, rel := range m {
+			s, ok := rel.(string)
+			if !ok {
+				return nil, fmt.Errorf("target_templates: %s.%s must be a string", k, name)
+			}
+			templates[strings.ToLower(name)] = s
+		}
+	}
+
+	for name, rel := range templates {
+		if rel == "" {
+			delete(templates, name)
+			continue
+		}
+		if name == plan.DefaultTarget || !targetNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("target_templates: invalid target name %q", name)
+		}
+		if err := validateTargetTemplate(rel); err != nil {
+			return nil, fmt.Errorf("target_templates: %s: %w", name, err)
+		}
+	}
+
+	return templates, nil
+}
+
+// isForeignTemplate reports whether a target template is rooted at a variable
+// instead of the install root.
+func isForeignTemplate(tmpl string) bool {
+	return strings.HasPrefix(tmpl, "${") && !strings.HasPrefix(tmpl, gameVersionVar)
+}
+
+// gameVersionVar is the variable of the game version in versioned target
+// templates.
+const gameVersionVar = "${game_version}"
+
+// IsVersionedTemplate reports whether a target template has a game version
+// component: games that keep the mods of every version apart (e.g.,
+// mods/1.20.1/) get a new directory after every update. The version is
+// resolved when a profile is applied.
+func IsVersionedTemplate(tmpl string) bool {
+	return strings.Contains(tmpl, gameVersionVar)
+}
+
+// IsVersionedTarget reports whether a target was created from a versioned
+// template (and not changed by the user since).
+func IsVersionedTarget(t dbq.Target) bool {
+	return t.Origin != "user_override" && IsVersionedTemplate(TargetTemplate(t))
+}
+
+func validateTargetTemplate(tmpl string) error {
+	if !isForeignTemplate(tmpl) {
+		rel, err := vars.Expand(tmpl, map[string]string{"game_version": "1.0"})
+		if err != nil {
+			return err
+		}
+		_, err = plan.NormalizeRelPath(rel)
+		return err
+	}
+
+	known := make(map[string]string, len(targetVarNames)+1)
+	for _, name := range targetVarNames {
+		known[name] = "/"
+	}
+	known["game_version"] = "1.0"
+	if _, err := vars.Expand(tmpl, known); err != nil {
+		return err
+	}
+
+	return checkTemplateTraversal(tmpl)
+}
+
+// checkGameVersion makes sure that a game version can be a directory name.
+func checkGameVersion(v string) error {
+	if v == "" || v == "." || v == ".." || strings.ContainsAny(v, `/\`) {
+		return fmt.Errorf("game version %q can't be used as a directory name", v)
+	}
+	return nil
+}
+
+func checkTemplateTraversal(tmpl string) error {
+	for _, seg := range strings.Split(filepath.ToSlash(tmpl), "/") {
+		if seg == ".." {
+			return fmt.Errorf("path traversal is not allowed in %q", tmpl)
+		}
+	}
+	return nil
+}
+
+// TargetVars returns the variables that target templates of a game install
+// can use. game_version is only set if the installed version can be
+// detected.
+func TargetVars(gi dbq.GameInstall) map[string]string {
+	v := map[string]string{
+		"install_root": gi.InstallRoot,
+		"config":       xdg.ConfigHome,
+		"data":         xdg.DataHome,
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		v["home"] = home
+	}
+
+	if prefix, err := SteamProtonPrefix(gi); err == nil {
+		user := filepath.Join(prefix, "drive_c", "users", "steamuser")
+		v["compatdata"] = filepath.Dir(prefix)
+		v["prefix"] = prefix
+		v["documents"] = filepath.Join(user, "Documents")
+		v["appdata_local"] = filepath.Join(user, "AppData", "Local")
+		v["appdata_roaming"] = filepath.Join(user, "AppData", "Roaming")
+	}
+
+	// steam cloud saves and per-game configs of the account that plays it
+	if a, _, err := SteamAccount(gi); err == nil && a.Userdata != "" {
+		v["userdata"] = filepath.Join(a.Userdata, gi.StoreGameID)
+	}
+
+	if version, err := GameVersion(gi); err == nil && version != "" {
+		v["game_version"] = version
+	}
+
+	return v
+}
+
+// ExpandTargetTemplate turns a target template into an absolute path:
+// relative templates are joined to the install root, the others have their
+// variables expanded.
+func ExpandTargetTemplate(tmpl string, tv map[string]string) (string, error) {
+	if IsVersionedTemplate(tmpl) {
+		version, ok := tv["game_version"]
+		if !ok {
+			return "", fmt.Errorf("%q needs the game version but it isn't known", tmpl)
+		}
+		if err := checkGameVersion(version); err != nil {
+			return "", err
+		}
+	}
+
+	if !isForeignTemplate(tmpl) {
+		rel, err := vars.Expand(tmpl, map[string]string{"game_version": tv["game_version"]})
+		if err != nil {
+			return "", err
+		}
+		rel, err = plan.NormalizeRelPath(rel)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(tv["install_root"], filepath.FromSlash(rel)), nil
+	}
+
+	if err := checkTemplateTraversal(tmpl); err != nil {
+		return "", err
+	}
+
+	p, err := vars.Expand(tmpl, tv)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(p) {
+		return "", fmt.Errorf("%q does not expand to an absolute path (%s)", tmpl, p)
+	}
+
+	return filepath.Clean(p), nil
+}
+
+// TargetTemplate returns the template that a target was created from, if
+// any.
+func TargetTemplate(t dbq.Target) string {
+	if !t.Metadata.Valid {
+		return ""
+	}
+
+	var meta struct {
+		Template string `json:"template"`
+	}
+	if err := json.Unmarshal([]byte(t.Metadata.String), &meta); err != nil {
+		return ""
+	}
+	return meta.Template
+}
+
+// TargetRoot returns the directory that files of a target are deployed into.
+// Templated targets are expanded again for the install at apply time so that
+// they follow it when, e.g., the proton prefix or steam library moves.
+// Versioned targets are the exception: their files stay in the directory of
+// the version that they were deployed for (see VersionedTargetRoot).
+func TargetRoot(gi dbq.GameInstall, t dbq.Target) (string, error) {
+	tmpl := TargetTemplate(t)
+	if tmpl == "" || t.Origin == "user_override" || IsVersionedTemplate(tmpl) {
+		return t.RootPath, nil
+	}
+
+	root, err := ExpandTargetTemplate(tmpl, TargetVars(gi))
+	if err != nil {
+		return "", fmt.Errorf("target %s: %w", t.Name, err)
+	}
+	return root, nil
+}
+
+// VersionedTargetRoot returns the directory of a versioned target for the
+// given game version.
+func VersionedTargetRoot(gi dbq.GameInstall, t dbq.Target, version string) (string, error) {
+	tv := TargetVars(gi)
+	tv["game_version"] = version
+
+	root, err := ExpandTargetTemplate(TargetTemplate(t), tv)
+	if err != nil {
+		return "", fmt.Errorf("target %s: %w", t.Name, err)
+	}
+	return root, nil
+}
+
+// ProfileGameVersion returns the game version that the versioned targets of
+// a profile resolve to: the one that it was declared for (see `modctl
+// profiles game-version`) or else the installed one. It's "" if neither is
+// known.
+func ProfileGameVersion(gi dbq.GameInstall, p dbq.Profile) (string, error) {
+	if p.GameVersion.Valid {
+		return p.GameVersion.String, nil
+	}
+	return GameVersion(gi)
+}
+
+// expandVersionless expands a versioned template up to its game version
+// component, i.e., to the directory that has the ones of every version.
+func expandVersionless(tmpl string, tv map[string]string) (string, error) {
+	segs := strings.Split(tmpl, "/")
+	for i, seg := range segs {
+		if strings.Contains(seg, gameVersionVar) {
+			segs = segs[:i]
+			break
+		}
+	}
+
+	if len(segs) == 0 && !isForeignTemplate(tmpl) {
+		return tv["install_root"], nil
+	}
+	return ExpandTargetTemplate(strings.Join(segs, "/"), tv)
+}
+
+// upsertTemplateTargets creates (or updates) the default targets of a game
+// install, below its install root or wherever their template points to.
+// Targets that the user changed are left alone, and templates that use a
+// variable the install doesn't have (e.g., no proton prefix) are skipped, as
+// are the ones outside of the install root unless foreign is set.
+func upsertTemplateTargets(ctx context.Context, q *dbq.Queries, gameInstallID int64, di dbq.UpsertGameInstallParams, foreign bool) error {
+	templates, err := TargetTemplates(di.StoreID, di.StoreGameID, di.CanonicalGameID)
+	if err != nil {
+		return err
+	}
+
+	tv := TargetVars(dbq.GameInstall{
+		StoreID:     di.StoreID,
+		StoreGameID: di.StoreGameID,
+		InstallRoot: di.InstallRoot,
+		Metadata:    di.Metadata,
+	})
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tmpl := templates[name]
+		if isForeignTemplate(tmpl) && !foreign {
+			continue
+		}
+		if !isForeignTemplate(tmpl) {
+			tmpl, _ = plan.NormalizeRelPath(tmpl)
+		}
+
+		var root string
+		if _, known := tv["game_version"]; IsVersionedTemplate(tmpl) && !known {
+			// the directory of every version until a profile is applied
+			// for one of them
+			root, err = expandVersionless(tmpl, tv)
+		} else {
+			root, err = ExpandTargetTemplate(tmpl, tv)
+		}
+		if err != nil {
+			continue
+		}
+
+		t, err := q.GetTargetByName(ctx, dbq.GetTargetByNameParams{
+			GameInstallID: gameInstallID,
+			Name:          name,
+		})
+		if err == nil && t.Origin == "user_override" {
+			continue
+		} else if err == nil && IsVersionedTemplate(tmpl) {
+			// the files are where the last apply put them; the next one
+			// moves them if the version changed
+			root = t.RootPath
+		} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("get target %s for install_id=%d: %w", name, gameInstallID, err)
+		}
+
+		meta, err := json.Marshal(map[string]string{"template": tmpl})
+		if err != nil {
+			return err
+		}
+
+		if err := q.UpsertDiscoveredTarget(ctx, dbq.UpsertDiscoveredTargetParams{
+			GameInstallID: gameInstallID,
+			Name:          name,
+			RootPath:      root,
+			Metadata:      sql.NullString{String: string(meta), Valid: true},
+		}); err != nil {
+			return fmt.Errorf("upsert target %s for install_id=%d: %w", name, gameInstallID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedTargetTemplates(t *testing.T) {
+	t.Parallel()
+
+	var embedded map[string]map[string]string
+	require.NoError(t, json.Unmarshal(defaultTargetTemplatesJSON, &embedded))
+
+	for k, want := range embedded {
+		storeID, storeGameID, ok := strings.Cut(k, ":")
+		require.True(t, ok, k)
+
+		got, err := TargetTemplates(storeID, storeGameID, sql.NullString{})
+		assert.NoError(t, err, k)
+		assert.Equal(t, want, got, k)
+	}
+}
+
+func TestExpandTargetTemplate(t *testing.T) {
+	t.Parallel()
+
+	tv := map[string]string{
+		"install_root": "/games/skyrim",
+		"documents":    "/compat/489830/pfx/drive_c/users/steamuser/Documents",
+	}
+
+	tests := []struct {
+		tmpl     string
+		expected string
+		err      bool
+	}{
+		{tmpl: "Data", expected: "/games/skyrim/Data"},
+		{tmpl: "BepInEx/plugins", expected: "/games/skyrim/BepInEx/plugins"},
+		{
+			tmpl:     "${documents}/My Games/Skyrim Special Edition",
+			expected: "/compat/489830/pfx/drive_c/users/steamuser/Documents/My Games/Skyrim Special Edition",
+		},
+		{tmpl: "${install_root}/../Data", err: true},
+		{tmpl: "${compatdata}/pfx", err: true},
+		{tmpl: "../outside", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExpandTargetTemplate(tt.tmpl, tv)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestValidateTargetTemplate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validateTargetTemplate("Data"))
+	assert.NoError(t, validateTargetTemplate("${home}/.config/game"))
+	assert.NoError(t, validateTargetTemplate("${appdata_local}/Game"))
+	assert.Error(t, validateTargetTemplate("${nope}/Game"))
+	assert.Error(t, validateTargetTemplate("${documents}/../../Game"))
+	assert.Error(t, validateTargetTemplate("/absolute"))
+	assert.NoError(t, validateTargetTemplate("mods/${game_version}"))
+	assert.NoError(t, validateTargetTemplate("${documents}/Game/${game_version}/mods"))
+	assert.Error(t, validateTargetTemplate("mods/${nope}"))
+}
+
+func TestExpandVersionedTargetTemplate(t *testing.T) {
+	t.Parallel()
+
+	tv := map[string]string{
+		"install_root": "/games/craft",
+		"documents":    "/docs",
+	}
+
+	_, err := ExpandTargetTemplate("mods/${game_version}", tv)
+	assert.Error(t, err)
+
+	got, err := expandVersionless("mods/${game_version}", tv)
+	require.NoError(t, err)
+	assert.Equal(t, "/games/craft/mods", got)
+
+	got, err = expandVersionless("${game_version}/mods", tv)
+	require.NoError(t, err)
+	assert.Equal(t, "/games/craft", got)
+
+	got, err = expandVersionless("${documents}/Craft/${game_version}", tv)
+	require.NoError(t, err)
+	assert.Equal(t, "/docs/Craft", got)
+
+	tv["game_version"] = "1.20.1"
+	got, err = ExpandTargetTemplate("mods/${game_version}", tv)
+	require.NoError(t, err)
+	assert.Equal(t, "/games/craft/mods/1.20.1", got)
+
+	got, err = ExpandTargetTemplate("${documents}/Craft/${game_version}/mods", tv)
+	require.NoError(t, err)
+	assert.Equal(t, "/docs/Craft/1.20.1/mods", got)
+
+	got, err = ExpandTargetTemplate("${game_version}/mods", tv)
+	require.NoError(t, err)
+	assert.Equal(t, "/games/craft/1.20.1/mods", got)
+
+	for _, v := range []string{"..", "1/2", `1\2`, ""} {
+		tv["game_version"] = v
+		_, err = ExpandTargetTemplate("mods/${game_version}", tv)
+		assert.Error(t, err, v)
+	}
+
+	assert.True(t, IsVersionedTemplate("mods/${game_version}"))
+	assert.False(t, IsVersionedTemplate("mods"))
+}
@@ -0,0 +1,137 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/fscaps"
+)
+
+// How files are put in place on a target (targets.deploy_method).
+const (
+	DeployMethodAuto     = "auto" // reflink, else hardlink, else copy
+	DeployMethodCopy     = "copy"
+	DeployMethodReflink  = "reflink"
+	DeployMethodHardlink = "hardlink"
+	// symlinks into the extracted store (blobstore.KindExtracted) or to the
+	// stored override, else like auto
+	DeployMethodSymlink = "symlink"
+	// a layer that is mounted over the target (see MountOverlay): nothing
+	// in the target itself is changed
+	DeployMethodOverlay = "overlay"
+)
+
+// What happens to the files on a target that modctl didn't deploy when they
+// are replaced (targets.backup_policy).
+const (
+	BackupPolicyBackup = "backup" // stored, and restored by unapply
+	BackupPolicyNone   = "none"   // replaced without a backup
+)
+
+var (
+	DeployMethods = []string{DeployMethodAuto, DeployMethodCopy, DeployMethodReflink, DeployMethodHardlink,
+		DeployMethodSymlink, DeployMethodOverlay}
+	BackupPolicies = []string{BackupPolicyBackup, BackupPolicyNone}
+)
+
+// CheckTargetStrategy returns an error if method isn't a deploy method or
+// policy isn't a backup policy.
+func CheckTargetStrategy(method, policy string) error {
+	if !slices.Contains(DeployMethods, method) {
+		return fmt.Errorf("invalid deploy method %q (%s)", method, strings.Join(DeployMethods, ", "))
+	}
+	if !slices.Contains(BackupPolicies, policy) {
+		return fmt.Errorf("invalid backup policy %q (%s)", policy, strings.Join(BackupPolicies, ", "))
+	}
+	return nil
+}
+
+// CheckTargetMethodChange returns an error if the deploy method of a target
+// can't be changed to method. An overlay has the files of a target in its
+// own directory: switching to or from it while files are deployed would lose
+// track of them, and versioned targets move to a new directory with every
+// game version, which the layer can't follow.
+func CheckTargetMethodChange(ctx context.Context, q *dbq.Queries, t dbq.Target, method string) error {
+	if method == t.DeployMethod {
+		return nil
+	}
+	if method == DeployMethodOverlay && IsVersionedTarget(t) {
+		return fmt.Errorf("target %s is per game version and can't be an overlay", t.Name)
+	}
+	if method != DeployMethodOverlay && t.DeployMethod != DeployMethodOverlay {
+		return nil
+	}
+
+	n, err := q.CountInstalledFilesForTarget(ctx, t.ID)
+	if err != nil {
+		return fmt.Errorf("count installed files: %w", err)
+	}
+	if n == 0 {
+		return nil
+	}
+	dir := "from"
+	if method == DeployMethodOverlay {
+		dir = "to"
+	}
+	return fmt.Errorf("target %s has %d deployed file(s): unapply the profile (`modctl profiles unapply`) before switching %s an overlay",
+		t.Name, n, dir)
+}
+
+// targetMethod returns how to put a file in place on a target with the given
+// deploy method and filesystem capabilities. Only files that were extracted
+// for this apply can be hardlinked; the others fall back to a reflink or a
+// copy. The methods that the filesystem doesn't support fall back to a copy.
+// Symlink and overlay targets only use it for what isn't linked or in the
+// layer (e.g., restored backups), like auto.
+func targetMethod(method string, caps fscaps.Caps, extracted bool) deploy.Method {
+	reflink := caps.Reflinks
+	hardlink := extracted && caps.Hardlinks
+
+	switch method {
+	case DeployMethodCopy:
+		return deploy.Copy
+	case DeployMethodReflink:
+		if reflink {
+			return deploy.Reflink
+		}
+		return deploy.Copy
+	case DeployMethodHardlink:
+		if hardlink {
+			return deploy.Hardlink
+		}
+		if reflink {
+			return deploy.Reflink
+		}
+		return deploy.Copy
+	default:
+		if reflink {
+			return deploy.Reflink
+		}
+		if hardlink {
+			return deploy.Hardlink
+		}
+		return deploy.Copy
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/fscaps"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetMethod(t *testing.T) {
+	t.Parallel()
+
+	both := fscaps.Caps{Reflinks: true, Hardlinks: true}
+	hardlinks := fscaps.Caps{Hardlinks: true}
+	none := fscaps.Caps{}
+
+	tests := []struct {
+		method    string
+		caps      fscaps.Caps
+		extracted bool
+		want      deploy.Method
+	}{
+		{DeployMethodAuto, both, true, deploy.Reflink},
+		{DeployMethodAuto, hardlinks, true, deploy.Hardlink},
+		{DeployMethodAuto, hardlinks, false, deploy.Copy},
+		{DeployMethodAuto, none, true, deploy.Copy},
+		{DeployMethodCopy, both, true, deploy.Copy},
+		{DeployMethodReflink, both, true, deploy.Reflink},
+		{DeployMethodReflink, hardlinks, true, deploy.Copy},
+		{DeployMethodHardlink, both, true, deploy.Hardlink},
+		{DeployMethodHardlink, both, false, deploy.Reflink},
+		{DeployMethodHardlink, hardlinks, false, deploy.Copy},
+		{DeployMethodHardlink, none, true, deploy.Copy},
+		// what isn't linked or in the layer, like auto
+		{DeployMethodSymlink, both, true, deploy.Reflink},
+		{DeployMethodSymlink, hardlinks, false, deploy.Copy},
+		{DeployMethodOverlay, hardlinks, true, deploy.Hardlink},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, targetMethod(tt.method, tt.caps, tt.extracted),
+			"%s (caps %+v, extracted %v)", tt.method, tt.caps, tt.extracted)
+	}
+}
+
+func TestCheckTargetStrategy(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckTargetStrategy(DeployMethodHardlink, BackupPolicyNone))
+	assert.NoError(t, CheckTargetStrategy(DeployMethodOverlay, BackupPolicyBackup))
+	assert.ErrorContains(t, CheckTargetStrategy("junction", BackupPolicyBackup), "invalid deploy method")
+	assert.ErrorContains(t, CheckTargetStrategy(DeployMethodCopy, "sometimes"), "invalid backup policy")
+}
@@ -0,0 +1,140 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/spf13/viper"
+)
+
+// tmpPatterns match what modctl creates in the tmp dir (relative to it; the
+// temporary names end in random digits). Nothing else is ever removed: the
+// tmp dir could be shared (e.g., /tmp).
+var tmpPatterns = []string{
+	"apply-[0-9]*",              // staged archives
+	"pack-[0-9]*",               // mods pack
+	"repair-[0-9]*",             // downloads of mods repair
+	"nexus-[0-9]*",              // downloads of nexus files
+	"modctl-wrap-[0-9]*",        // wrapped imports
+	"modctl-w3merge-[0-9]*",     // witcher 3 merges
+	".modctl-probe-[0-9]*",      // filesystem probes
+	".modctl-doctor-write-test", // doctor's writability check
+	"incoming/.ingest-[0-9]*",   // blobs that are being ingested
+}
+
+// TmpEntry is something that modctl left in the tmp dir.
+type TmpEntry struct {
+	Path string
+	// of the regular files in it
+	Size int64
+	// the last time that anything in it changed
+	ModTime time.Time
+}
+
+// TmpEntries returns what modctl left in the tmp dir that didn't change for
+// at least minAge (everything if it's zero), oldest first. A missing tmp dir
+// has no entries.
+func TmpEntries(dir string, minAge time.Duration) ([]TmpEntry, error) {
+	var out []TmpEntry
+	now := time.Now()
+	for _, pattern := range tmpPatterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			e, err := tmpEntry(m)
+			if errors.Is(err, os.ErrNotExist) {
+				continue // removed in the meantime
+			}
+			if err != nil {
+				return nil, err
+			}
+			if now.Sub(e.ModTime) >= minAge {
+				out = append(out, e)
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ModTime.Before(out[j].ModTime) })
+	return out, nil
+}
+
+func tmpEntry(path string) (TmpEntry, error) {
+	e := TmpEntry{Path: path}
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(e.ModTime) {
+			e.ModTime = info.ModTime()
+		}
+		if info.Mode().IsRegular() {
+			e.Size += info.Size()
+		}
+		return nil
+	})
+	return e, err
+}
+
+// RemoveTmpEntries removes entries of the tmp dir and returns how much space
+// that freed. It keeps going if an entry can't be removed and returns the
+// first error.
+func RemoveTmpEntries(entries []TmpEntry) (int64, error) {
+	var freed int64
+	var firstErr error
+	for _, e := range entries {
+		if err := os.RemoveAll(e.Path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		freed += e.Size
+	}
+	return freed, firstErr
+}
+
+// reapTmp removes what modctl left in the tmp dir that is older than
+// tmp_max_age (e.g., after a crash). It's called with the state lock held so
+// no other command can still be using it. It's best effort: failing to clean
+// up shouldn't stop the command.
+func reapTmp() {
+	maxAge := viper.GetDuration("tmp_max_age")
+	if maxAge <= 0 || dryrun.Enabled() {
+		return
+	}
+
+	entries, err := TmpEntries(viper.GetString("tmp_dir"), maxAge)
+	if err != nil {
+		return
+	}
+	_, _ = RemoveTmpEntries(entries)
+}
@@ -0,0 +1,79 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTmpEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+
+	write := func(rel string, size int, mtime time.Time) {
+		p := filepath.Join(dir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, make([]byte, size), 0o644))
+		require.NoError(t, os.Chtimes(p, mtime, mtime))
+	}
+
+	write("apply-123/abc/Data/a.esp", 100, old)
+	write("apply-123/abc/Data/b.esp", 50, old)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "apply-123", "abc", "Data"), old, old))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "apply-123", "abc"), old, old))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "apply-123"), old, old))
+	write("incoming/.ingest-456", 10, old)
+	// still in use
+	write("pack-789/mod.txt", 1, time.Now())
+	// not modctl's
+	write("apply-notes.txt", 1, old)
+	write("other", 1, old)
+
+	entries, err := TmpEntries(dir, 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, filepath.Join(dir, "apply-123"), entries[0].Path)
+	assert.Equal(t, int64(150), entries[0].Size)
+	assert.Equal(t, filepath.Join(dir, "incoming", ".ingest-456"), entries[1].Path)
+
+	all, err := TmpEntries(dir, 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	freed, err := RemoveTmpEntries(entries)
+	require.NoError(t, err)
+	assert.Equal(t, int64(160), freed)
+	assert.NoDirExists(t, filepath.Join(dir, "apply-123"))
+	assert.FileExists(t, filepath.Join(dir, "pack-789", "mod.txt"))
+	assert.FileExists(t, filepath.Join(dir, "apply-notes.txt"))
+	assert.DirExists(t, filepath.Join(dir, "incoming"))
+
+	// a missing tmp dir is empty
+	entries, err = TmpEntries(filepath.Join(dir, "missing"), 0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
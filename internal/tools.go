@@ -0,0 +1,153 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"os/exec"
+	"runtime"
+	"slices"
+
+	"github.com/mfinelli/modctl/internal/scan"
+	"github.com/spf13/viper"
+)
+
+// OptionalTool is an external program that some features need; bsdtar is
+// the only one that modctl can't do without.
+type OptionalTool struct {
+	Name string
+	// commands to look for in $PATH, the first one that's found is used
+	Commands func() []string
+	// config option that sets the command, if any
+	Config string
+	// what doesn't work without it
+	Features []string
+	// operating systems that it's relevant on, all if empty
+	GOOS []string
+}
+
+// ToolStatus is whether an optional tool is available.
+type ToolStatus struct {
+	Name     string   `json:"name"`
+	Command  string   `json:"command"`
+	Found    bool     `json:"found"`
+	Path     string   `json:"path,omitempty"`
+	Config   string   `json:"config,omitempty"`
+	Features []string `json:"features"`
+}
+
+func commands(names ...string) func() []string {
+	return func() []string { return names }
+}
+
+// configuredCommand returns the program of a command config option (a list
+// of arguments).
+func configuredCommand(key string) func() []string {
+	return func() []string {
+		c := viper.GetStringSlice(key)
+		if len(c) == 0 {
+			return nil
+		}
+		return c[:1]
+	}
+}
+
+// OptionalTools are the external programs that doctor checks for.
+var OptionalTools = []OptionalTool{
+	{
+		Name:     "LOOT",
+		Commands: configuredCommand("loot_command"),
+		Config:   "loot_command",
+		Features: []string{"sorting plugins (`modctl plugins sort`)"},
+	},
+	{
+		Name:     "merge tool",
+		Commands: configuredCommand("witcher3_merge_command"),
+		Config:   "witcher3_merge_command",
+		Features: []string{"merging witcher 3 scripts (`modctl witcher3 merge`)"},
+	},
+	{
+		Name: "virus scanner",
+		Commands: func() []string {
+			if c := configuredCommand("scan_command")(); c != nil {
+				return c
+			}
+			return commands(scan.ClamAV[0])()
+		},
+		Config:   "scan_command",
+		Features: []string{"scanning archives when they're imported (set scan_command)"},
+	},
+	{
+		Name:     "7-Zip",
+		Commands: commands("7z", "7zz", "7za"),
+		Features: []string{"extracting 7z archives that bsdtar can't read (e.g., BCJ2) by hand, to import them with `modctl mods pack`"},
+	},
+	{
+		Name:     "steam",
+		Commands: commands("steam"),
+		Features: []string{"opening steam:// links, e.g., to verify the game files after `modctl profiles unapply`"},
+	},
+	{
+		Name:     "xdg-open",
+		Commands: commands("xdg-open"),
+		Features: []string{"opening the links that modctl prints (nexus pages, steam:// links) from the terminal"},
+		GOOS:     []string{"linux", "freebsd", "openbsd", "netbsd"},
+	},
+	{
+		Name:     "notify-send",
+		Commands: commands("notify-send"),
+		Features: []string{"desktop notifications (`modctl cron --notify`)"},
+		GOOS:     []string{"linux", "freebsd", "openbsd", "netbsd"},
+	},
+	{
+		Name:     "fuse-overlayfs",
+		Commands: configuredCommand("overlay_mount_command"),
+		Config:   "overlay_mount_command",
+		Features: []string{"targets with the overlay deploy method (`modctl games target-strategy --method overlay`)"},
+		GOOS:     []string{"linux"},
+	},
+}
+
+// CheckTools looks for the optional tools that are relevant on this
+// operating system.
+func CheckTools() []ToolStatus {
+	var out []ToolStatus
+	for _, t := range OptionalTools {
+		if len(t.GOOS) > 0 && !slices.Contains(t.GOOS, runtime.GOOS) {
+			continue
+		}
+		out = append(out, checkTool(t))
+	}
+	return out
+}
+
+func checkTool(t OptionalTool) ToolStatus {
+	s := ToolStatus{Name: t.Name, Config: t.Config, Features: t.Features}
+
+	cmds := t.Commands()
+	for _, c := range cmds {
+		if path, err := exec.LookPath(c); err == nil {
+			s.Command, s.Path, s.Found = c, path, true
+			return s
+		}
+	}
+	if len(cmds) > 0 {
+		s.Command = cmds[0]
+	}
+	return s
+}
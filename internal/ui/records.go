@@ -0,0 +1,70 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// The formats that list commands print their output in: text for people,
+// csv and tsv (one record per line with a header, see WriteRecords) for
+// spreadsheets and scripts.
+const (
+	FormatText = "text"
+	FormatCSV  = "csv"
+	FormatTSV  = "tsv"
+)
+
+// Formats are the formats that list commands accept for --format.
+var Formats = []string{FormatText, FormatCSV, FormatTSV}
+
+// CheckFormat returns an error for a format that isn't one of Formats.
+func CheckFormat(format string) error {
+	for _, f := range Formats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q (want text, csv, or tsv)", format)
+}
+
+// WriteRecords writes a header and records as csv or tsv. Fields are quoted
+// as RFC 4180 wants (when they contain the separator, a quote, or a line
+// break), so that spreadsheets read names and paths with commas or tabs in
+// them back as they are.
+func WriteRecords(w io.Writer, format string, header []string, records [][]string) error {
+	cw := csv.NewWriter(w)
+	switch format {
+	case FormatCSV:
+	case FormatTSV:
+		cw.Comma = '\t'
+	default:
+		return fmt.Errorf("can't write records as %q", format)
+	}
+
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("write %s: %w", format, err)
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("write %s: %w", format, err)
+	}
+	return nil
+}
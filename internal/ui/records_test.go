@@ -0,0 +1,64 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRecords(t *testing.T) {
+	t.Parallel()
+
+	header := []string{"name", "path", "size_bytes"}
+	records := [][]string{
+		{"SkyUI", "/games/Skyrim Special Edition", "1024"},
+		{`Cloaks, "Capes"`, "C:\\Games\tSkyrim", "2048"},
+		{"Notes", "line\nbreak", ""},
+	}
+
+	var b strings.Builder
+	require.NoError(t, WriteRecords(&b, FormatCSV, header, records))
+	assert.Equal(t, "name,path,size_bytes\n"+
+		"SkyUI,/games/Skyrim Special Edition,1024\n"+
+		"\"Cloaks, \"\"Capes\"\"\",C:\\Games\tSkyrim,2048\n"+
+		"Notes,\"line\nbreak\",\n", b.String())
+
+	b.Reset()
+	require.NoError(t, WriteRecords(&b, FormatTSV, header, records))
+	assert.Equal(t, "name\tpath\tsize_bytes\n"+
+		"SkyUI\t/games/Skyrim Special Edition\t1024\n"+
+		"\"Cloaks, \"\"Capes\"\"\"\t\"C:\\Games\tSkyrim\"\t2048\n"+
+		"Notes\t\"line\nbreak\"\t\n", b.String())
+
+	assert.Error(t, WriteRecords(&b, FormatText, header, records))
+}
+
+func TestCheckFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range Formats {
+		assert.NoError(t, CheckFormat(f))
+	}
+	assert.Error(t, CheckFormat("json"))
+	assert.Error(t, CheckFormat(""))
+}
@@ -0,0 +1,62 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package ui has the styles that the commands render their output with, so
+// that every command looks (and reports errors) the same.
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	// Header is for titles, e.g., the game and profile that the output is
+	// about.
+	Header = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
+	// Subtle is for details and hints.
+	Subtle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	// OK is for what worked.
+	OK = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	// Warn is for what needs attention but didn't fail.
+	Warn = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	// Err is for what failed.
+	Err = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+)
+
+// ErrorPrefix is what errors are printed after (see PrintError).
+func ErrorPrefix() string {
+	return Err.Render("Error:")
+}
+
+// PrintError prints an error the way that failed commands are reported,
+// followed by what the user can do about it if the error knows (see
+// internal.HintError).
+func PrintError(w io.Writer, err error) {
+	fmt.Fprintln(w, ErrorPrefix(), err)
+
+	var h interface{ ErrorHints() []string }
+	if errors.As(err, &h) {
+		for _, hint := range h.ErrorHints() {
+			fmt.Fprintln(w, Subtle.Render("  "+hint))
+		}
+	}
+}
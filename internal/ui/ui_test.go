@@ -0,0 +1,53 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintError(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	PrintError(&b, errors.New("no active game selected"))
+	assert.True(t, strings.HasSuffix(b.String(), " no active game selected\n"))
+	assert.Contains(t, b.String(), "Error:")
+}
+
+type hintedError struct{}
+
+func (hintedError) Error() string        { return "profile not found" }
+func (hintedError) ErrorHints() []string { return []string{"run `modctl profiles list`"} }
+
+func TestPrintErrorHints(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	PrintError(&b, fmt.Errorf("switch: %w", hintedError{}))
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasSuffix(lines[0], " switch: profile not found"))
+	assert.Contains(t, lines[1], "  run `modctl profiles list`")
+}
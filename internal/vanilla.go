@@ -0,0 +1,57 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/steam"
+)
+
+// SteamVanilla returns the vanilla files of a steam game install according
+// to the depot manifests that Steam keeps in its depotcache.
+func SteamVanilla(gi dbq.GameInstall) (*steam.Index, []string, error) {
+	steamapps, err := SteamappsDir(gi)
+	if err != nil {
+		return nil, nil, err
+	}
+	acf := filepath.Join(steamapps, "appmanifest_"+gi.StoreGameID+".acf")
+
+	// the depotcache is usually in the steam root, but libraries can
+	// have their own
+	dirs := []string{
+		filepath.Join(steamapps, "depotcache"),
+		filepath.Join(filepath.Dir(steamapps), "depotcache"),
+	}
+	for _, root := range candidateSteamRoots() {
+		dirs = append(dirs, filepath.Join(root, "depotcache"))
+	}
+
+	seen := map[string]bool{}
+	unique := dirs[:0]
+	for _, d := range dirs {
+		if !seen[d] {
+			seen[d] = true
+			unique = append(unique, d)
+		}
+	}
+
+	return steam.LoadIndex(acf, unique)
+}
@@ -0,0 +1,75 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package vars expands ${name} variables in user-provided strings (command
+// templates, paths, etc.).
+package vars
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expand replaces ${name} variables in s. Unknown variables are an error so
+// that typos in the config don't silently turn into empty strings.
+func Expand(s string, vars map[string]string) (string, error) {
+	var b strings.Builder
+	rest := s
+	for {
+		i := strings.Index(rest, "${")
+		if i < 0 {
+			b.WriteString(rest)
+			break
+		}
+		j := strings.Index(rest[i:], "}")
+		if j < 0 {
+			return "", fmt.Errorf("unterminated variable in %q", s)
+		}
+
+		name := rest[i+2 : i+j]
+		val, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("unknown variable ${%s} in %q", name, s)
+		}
+
+		b.WriteString(rest[:i])
+		b.WriteString(val)
+		rest = rest[i+j+1:]
+	}
+
+	return b.String(), nil
+}
+
+// ExpandCommand expands the variables in every argument of a command
+// template.
+func ExpandCommand(tmpl []string, vars map[string]string) ([]string, error) {
+	if len(tmpl) == 0 || strings.TrimSpace(tmpl[0]) == "" {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	out := make([]string, 0, len(tmpl))
+	for _, arg := range tmpl {
+		a, err := Expand(arg, vars)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package vars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandCommand(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]string{
+		"game":      "SkyrimSE",
+		"game_path": "/games/Skyrim Special Edition",
+	}
+
+	tests := []struct {
+		name    string
+		input   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "default",
+			input: []string{"loot", "--game", "${game}", "--game-path", "${game_path}", "--auto-sort"},
+			want: []string{"loot", "--game", "SkyrimSE", "--game-path",
+				"/games/Skyrim Special Edition", "--auto-sort"},
+		},
+		{
+			name:  "embedded variables",
+			input: []string{"flatpak", "run", "io.github.loot.loot", "--game=${game}"},
+			want:  []string{"flatpak", "run", "io.github.loot.loot", "--game=SkyrimSE"},
+		},
+		{
+			name:    "unknown variable",
+			input:   []string{"loot", "--game=${gmae}"},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated variable",
+			input:   []string{"loot", "--game=${game"},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			input:   nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExpandCommand(tt.input, vars)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
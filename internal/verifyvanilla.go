@@ -0,0 +1,262 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// ErrNothingToVerify is returned by VerifyVanilla for a game install that
+// has neither depot manifests nor a baseline to compare its files to.
+var ErrNothingToVerify = errors.New("nothing to compare the game files to")
+
+// VanillaReport is what VerifyVanilla found.
+type VanillaReport struct {
+	// files that modctl deployed (that a profile is applied)
+	Deployed int
+	// vanilla files checked against Steam's depot manifests (0 if they
+	// weren't used)
+	SteamFiles int
+	// the files were compared to the baseline
+	Baseline bool
+
+	// vanilla files that are different or gone
+	Changed []ChangedPath
+	Missing []ChangedPath
+	// files that aren't part of the game: not in the depot manifests or
+	// created after the baseline was recorded (manual installs, files that
+	// the game wrote); they don't make the game any less vanilla
+	Extra []ChangedPath
+
+	Warnings []string
+}
+
+// Pristine reports whether the game install is vanilla: nothing is
+// deployed and every vanilla file is as it should be.
+func (r VanillaReport) Pristine() bool {
+	return r.Deployed == 0 && len(r.Changed) == 0 && len(r.Missing) == 0
+}
+
+// VerifyVanilla checks that a game install is vanilla: that modctl deployed
+// nothing to it and that its files are the ones that Steam's depot manifests
+// list (steam games) and that the baseline recorded. Every file of the game
+// is hashed.
+func VerifyVanilla(ctx context.Context, q *dbq.Queries, gi dbq.GameInstall) (VanillaReport, error) {
+	var r VanillaReport
+
+	installed, err := q.ListInstalledFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return r, fmt.Errorf("list installed files: %w", err)
+	}
+	r.Deployed = len(installed)
+
+	targets, _, err := gameScanTargets(ctx, q, gi)
+	if err != nil {
+		return r, err
+	}
+	files, err := scanTargets(ctx, targets)
+	if err != nil {
+		return r, err
+	}
+
+	changed := map[ChangedPath]bool{}
+	missing := map[ChangedPath]bool{}
+	extra := map[ChangedPath]bool{}
+	// what the baseline knows as vanilla
+	vanilla := map[ChangedPath]bool{}
+
+	if gi.StoreID == "steam" {
+		if err := verifySteamVanilla(ctx, gi, targets, files, &r, changed, missing, extra); err != nil {
+			return r, err
+		}
+	}
+
+	if gi.BaselineScannedAt.Valid {
+		r.Baseline = true
+		classified, err := ClassifyFiles(ctx, q, gi, true)
+		if err != nil {
+			return r, err
+		}
+		present := make(map[ChangedPath]bool, len(classified))
+		for _, f := range classified {
+			cp := ChangedPath{f.Target, f.RelPath}
+			present[cp] = true
+			switch f.Provenance {
+			case ProvenanceVanilla:
+				vanilla[cp] = true
+			case ProvenanceUnknown:
+				changed[cp] = true
+			case ProvenanceUser:
+				extra[cp] = true
+			}
+		}
+
+		baseline, err := q.ListBaselineFilesForGame(ctx, gi.ID)
+		if err != nil {
+			return r, fmt.Errorf("list baseline: %w", err)
+		}
+		for _, row := range baseline {
+			t := scanTargetByID(targets, row.TargetID)
+			if t == nil {
+				continue
+			}
+			if cp := (ChangedPath{t.name, row.Relpath}); !present[cp] {
+				missing[cp] = true
+			}
+		}
+	}
+
+	if r.SteamFiles == 0 && !r.Baseline {
+		return r, ErrNothingToVerify
+	}
+
+	// files that modctl deployed are counted as such, and a file that the
+	// depot manifests don't know about is fine if the baseline does
+	for _, row := range installed {
+		if t := scanTargetByID(targets, row.TargetID); t != nil {
+			delete(extra, ChangedPath{t.name, row.Relpath})
+		}
+	}
+	for cp := range extra {
+		if vanilla[cp] || changed[cp] || missing[cp] {
+			delete(extra, cp)
+		}
+	}
+	r.Changed = sortedPaths(changed)
+	r.Missing = sortedPaths(missing)
+	r.Extra = sortedPaths(extra)
+	return r, nil
+}
+
+// verifySteamVanilla compares the game directory to the depot manifests.
+// The files are reported for the most specific target that they're in
+// (like scanTargets does).
+func verifySteamVanilla(ctx context.Context, gi dbq.GameInstall, targets []scanTarget, files []scannedFile, r *VanillaReport, changed, missing, extra map[ChangedPath]bool) error {
+	ix, warnings, err := SteamVanilla(gi)
+	if err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("steam depot manifests: %v", err))
+		return nil
+	}
+	for _, w := range warnings {
+		r.Warnings = append(r.Warnings, "steam depot manifests: "+w)
+	}
+
+	installRoot := filepath.Clean(gi.InstallRoot)
+	for _, f := range ix.Files() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if f.SHA1 == "" {
+			continue
+		}
+		r.SteamFiles++
+
+		path := filepath.Join(installRoot, filepath.FromSlash(f.Name))
+		cp := targetPath(targets, path)
+		if cp.Target == "" {
+			cp = ChangedPath{plan.DefaultTarget, f.Name}
+		}
+		ok, err := ix.Matches(f.Name, path)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", cp, err)
+		}
+		if ok {
+			continue
+		}
+		if _, err := os.Lstat(path); err == nil {
+			changed[cp] = true
+		} else {
+			missing[cp] = true
+		}
+	}
+	if r.SteamFiles == 0 {
+		return nil
+	}
+
+	// the depot manifests only know about the game directory
+	for _, f := range files {
+		rel, err := filepath.Rel(installRoot, f.path)
+		if err != nil || !filepath.IsLocal(rel) {
+			continue
+		}
+		if _, ok := ix.Lookup(filepath.ToSlash(rel)); !ok {
+			extra[ChangedPath{f.target, f.relpath}] = true
+		}
+	}
+
+	return nil
+}
+
+// targetPath returns a path as <target>/<relpath> of the most specific
+// target whose root it's in (a zero ChangedPath if there's none).
+func targetPath(targets []scanTarget, path string) ChangedPath {
+	var best ChangedPath
+	bestLen := -1
+	for _, t := range targets {
+		root := filepath.Clean(t.root)
+		rel, err := filepath.Rel(root, path)
+		if err != nil || !filepath.IsLocal(rel) || len(root) <= bestLen {
+			continue
+		}
+		best, bestLen = ChangedPath{t.name, filepath.ToSlash(rel)}, len(root)
+	}
+	return best
+}
+
+func scanTargetByID(targets []scanTarget, id int64) *scanTarget {
+	for i := range targets {
+		if targets[i].id == id {
+			return &targets[i]
+		}
+	}
+	return nil
+}
+
+// RecordVerifiedVanilla notes that a game install was found pristine and
+// records its baseline if it doesn't have one yet, so that later scans (and
+// nuke) compare to the verified files.
+func RecordVerifiedVanilla(ctx context.Context, db *sql.DB, q *dbq.Queries, gi dbq.GameInstall) error {
+	if !gi.BaselineScannedAt.Valid {
+		if _, err := RecordBaseline(ctx, db, q, gi); err != nil {
+			return fmt.Errorf("record baseline: %w", err)
+		}
+	}
+	if err := q.SetVerifiedVanilla(ctx, gi.ID); err != nil {
+		return fmt.Errorf("record verified vanilla: %w", err)
+	}
+	return nil
+}
+
+func sortedPaths(m map[ChangedPath]bool) []ChangedPath {
+	out := make([]ChangedPath, 0, len(m))
+	for cp := range m {
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
@@ -0,0 +1,56 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetPath(t *testing.T) {
+	t.Parallel()
+
+	game := t.TempDir()
+	targets := []scanTarget{
+		{id: 1, name: "game_dir", root: game},
+		{id: 2, name: "data", root: filepath.Join(game, "Data")},
+	}
+
+	assert.Equal(t, ChangedPath{"game_dir", "bin/game.exe"},
+		targetPath(targets, filepath.Join(game, "bin", "game.exe")))
+	assert.Equal(t, ChangedPath{"data", "textures/a.dds"},
+		targetPath(targets, filepath.Join(game, "Data", "textures", "a.dds")))
+	assert.Equal(t, ChangedPath{}, targetPath(targets, filepath.Join(filepath.Dir(game), "elsewhere")))
+
+	assert.Equal(t, "data", scanTargetByID(targets, 2).name)
+	assert.Nil(t, scanTargetByID(targets, 3))
+}
+
+func TestVanillaReportPristine(t *testing.T) {
+	t.Parallel()
+
+	// extra files don't count
+	assert.True(t, VanillaReport{Extra: []ChangedPath{{"game_dir", "log.txt"}}}.Pristine())
+
+	assert.False(t, VanillaReport{Deployed: 1}.Pristine())
+	assert.False(t, VanillaReport{Changed: []ChangedPath{{"game_dir", "game.exe"}}}.Pristine())
+	assert.False(t, VanillaReport{Missing: []ChangedPath{{"game_dir", "game.exe"}}}.Pristine())
+}
@@ -0,0 +1,248 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/lock"
+)
+
+// ErrWatchUnsupported is returned by Watcher.Run on platforms without
+// inotify.
+var ErrWatchUnsupported = errors.New("watching files is not supported on this platform")
+
+// the kinds of drift of a deployed file
+const (
+	DriftModified = "modified"
+	DriftMissing  = "missing"
+)
+
+// DriftEvent is a change of a deployed file that the watcher noticed.
+type DriftEvent struct {
+	Target  string
+	RelPath string
+	// DriftModified, DriftMissing, or "" when the file is back to what was
+	// deployed
+	Kind string
+	At   time.Time
+}
+
+func (e DriftEvent) String() string {
+	kind := e.Kind
+	if kind == "" {
+		kind = "restored"
+	}
+	return kind + ": " + e.Target + "/" + e.RelPath
+}
+
+// watchedFile is a deployed file and where it is on disk.
+type watchedFile struct {
+	row    dbq.InstalledFile
+	target string
+}
+
+// Watcher detects changes to the files that modctl deployed into a game
+// install and records them as drift in the database (see `modctl watch`).
+//
+// It only holds the state lock (see Lock) while it records a change so that
+// applies can run while it watches. Drift is only recorded if the installed
+// file still expects the content the file was compared to (an apply that
+// deployed it again in the meantime wins) and the watcher reloads the
+// installed files when that happens.
+type Watcher struct {
+	Q           *dbq.Queries
+	Hashes      deploy.HashCache
+	GameInstall dbq.GameInstall
+	// takes the state lock (see LockState) for recording a change; if
+	// another command holds it (a *lock.HeldError) the file is checked
+	// again later (nil: don't lock)
+	Lock func() (*lock.Lock, error)
+
+	// by absolute path
+	files map[string]*watchedFile
+	stale bool
+}
+
+// Load (re)reads the deployed files of the game install.
+func (w *Watcher) Load(ctx context.Context) error {
+	rows, err := w.Q.ListTargetsForGameInstall(ctx, w.GameInstall.ID)
+	if err != nil {
+		return fmt.Errorf("list targets: %w", err)
+	}
+	roots := make(map[int64]dbq.Target, len(rows))
+	for _, t := range rows {
+		roots[t.ID] = t
+	}
+
+	installed, err := w.Q.ListInstalledFilesForGame(ctx, w.GameInstall.ID)
+	if err != nil {
+		return fmt.Errorf("list installed files: %w", err)
+	}
+
+	files := make(map[string]*watchedFile, len(installed))
+	for _, row := range installed {
+		t, ok := roots[row.TargetID]
+		if !ok {
+			return fmt.Errorf("installed file %s: target %d not found", row.Relpath, row.TargetID)
+		}
+		root, err := DeployedRoot(w.GameInstall, t)
+		if err != nil {
+			return err
+		}
+		files[filepath.Join(root, filepath.FromSlash(row.Relpath))] = &watchedFile{row: row, target: t.Name}
+	}
+
+	w.files, w.stale = files, false
+	return nil
+}
+
+// Files returns how many deployed files are watched.
+func (w *Watcher) Files() int {
+	return len(w.files)
+}
+
+// Paths returns the paths of the watched files.
+func (w *Watcher) Paths() []string {
+	paths := make([]string, 0, len(w.files))
+	for p := range w.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Dirs returns the directories that have to be watched: the ones that the
+// deployed files are in.
+func (w *Watcher) Dirs() []string {
+	return watchDirs(w.Paths())
+}
+
+func watchDirs(paths []string) []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, p := range paths {
+		d := filepath.Dir(p)
+		if !seen[d] {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// Check compares the file at path with what was deployed there and records
+// the difference. It returns the event if the drift of the file changed;
+// paths that modctl didn't deploy are ignored.
+func (w *Watcher) Check(ctx context.Context, path string) (DriftEvent, bool, error) {
+	f, ok := w.files[path]
+	if !ok {
+		return DriftEvent{}, false, nil
+	}
+
+	kind, err := driftKind(ctx, w.Hashes, path, f.row.ContentSha256)
+	if err != nil {
+		return DriftEvent{}, false, err
+	}
+	if kind == f.row.DriftKind.String {
+		return DriftEvent{}, false, nil
+	}
+
+	if w.Lock != nil {
+		l, err := w.Lock()
+		if err != nil {
+			return DriftEvent{}, false, err
+		}
+		defer l.Release()
+	}
+
+	now := time.Now().UTC()
+	var n int64
+	if kind == "" {
+		n, err = w.Q.ClearInstalledFileDrift(ctx, f.row.ID)
+	} else {
+		n, err = w.Q.MarkInstalledFileDrifted(ctx, dbq.MarkInstalledFileDriftedParams{
+			DriftedAt:     sql.NullString{String: now.Format("2006-01-02T15:04:05.000Z"), Valid: true},
+			DriftKind:     sql.NullString{String: kind, Valid: true},
+			ID:            f.row.ID,
+			ContentSha256: f.row.ContentSha256,
+		})
+	}
+	if err != nil {
+		return DriftEvent{}, false, fmt.Errorf("record drift of %s/%s: %w", f.target, f.row.Relpath, err)
+	}
+	if n == 0 {
+		// deployed again (or removed) by an apply
+		w.stale = true
+		return DriftEvent{}, false, nil
+	}
+
+	f.row.DriftKind = sql.NullString{String: kind, Valid: kind != ""}
+	return DriftEvent{Target: f.target, RelPath: f.row.Relpath, Kind: kind, At: now}, true, nil
+}
+
+// CheckAll checks every deployed file (e.g., for what changed while nobody
+// was watching).
+func (w *Watcher) CheckAll(ctx context.Context, emit func(DriftEvent)) error {
+	for _, p := range w.Paths() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ev, ok, err := w.Check(ctx, p)
+		if err != nil {
+			return err
+		}
+		if ok {
+			emit(ev)
+		}
+	}
+	return nil
+}
+
+// Stale reports whether the deployed files changed in the database since
+// they were loaded.
+func (w *Watcher) Stale() bool {
+	return w.stale
+}
+
+// driftKind compares the file at path with the content that was deployed
+// there.
+func driftKind(ctx context.Context, c deploy.HashCache, path, want string) (string, error) {
+	sha, _, err := deploy.HashFileCached(ctx, c, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DriftMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if sha != want {
+		return DriftModified, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,240 @@
+//go:build linux
+
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/lock"
+	"golang.org/x/sys/unix"
+)
+
+// the directories that deployed files are in are watched (inotify isn't
+// recursive, and watching whole game installs would be too much)
+const watchMask = unix.IN_CLOSE_WRITE | unix.IN_MODIFY | unix.IN_CREATE | unix.IN_DELETE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF | unix.IN_ONLYDIR
+
+type inotifyEvent struct {
+	wd   int32
+	mask uint32
+	name string
+}
+
+// parseInotifyEvents decodes what was read from an inotify file descriptor.
+func parseInotifyEvents(b []byte) []inotifyEvent {
+	var evs []inotifyEvent
+	for len(b) >= unix.SizeofInotifyEvent {
+		end := unix.SizeofInotifyEvent + int(binary.NativeEndian.Uint32(b[12:16]))
+		if end > len(b) {
+			break
+		}
+		evs = append(evs, inotifyEvent{
+			wd:   int32(binary.NativeEndian.Uint32(b[0:4])),
+			mask: binary.NativeEndian.Uint32(b[4:8]),
+			// the name is padded with NULs
+			name: strings.TrimRight(string(b[unix.SizeofInotifyEvent:end]), "\x00"),
+		})
+		b = b[end:]
+	}
+	return evs
+}
+
+// Run watches the deployed files (with inotify) until ctx is done and calls
+// emit for every change of their drift. A file is checked once it was quiet
+// for debounce (games and tools write files in many small pieces); every
+// rescan, and right away after an apply changed the deployed files, they
+// are loaded again. A file whose change couldn't be recorded because another
+// command held the state lock is checked again after debounce. Errors that
+// only affect single files are passed to warn.
+func (w *Watcher) Run(ctx context.Context, debounce, rescan time.Duration, emit func(DriftEvent), warn func(error)) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("init inotify: %w", err)
+	}
+	// non-blocking so that reads go through the runtime poller and closing
+	// the file stops them
+	f := os.NewFile(uintptr(fd), "inotify")
+	defer f.Close()
+
+	wds := map[int32]string{}
+	byDir := map[string]int32{}
+	syncWatches := func() {
+		want := map[string]bool{}
+		for _, d := range w.Dirs() {
+			want[d] = true
+			if _, ok := byDir[d]; ok {
+				continue
+			}
+			wd, err := unix.InotifyAddWatch(fd, d, watchMask)
+			if err != nil {
+				// the files of missing directories are missing too; the
+				// directory is watched once it's back after a rescan
+				if !errors.Is(err, unix.ENOENT) && !errors.Is(err, unix.ENOTDIR) {
+					warn(fmt.Errorf("watch %s: %w", d, err))
+				}
+				continue
+			}
+			wds[int32(wd)], byDir[d] = d, int32(wd)
+		}
+		for d, wd := range byDir {
+			if !want[d] {
+				_, _ = unix.InotifyRmWatch(fd, uint32(wd))
+				delete(wds, wd)
+				delete(byDir, d)
+			}
+		}
+	}
+
+	// the files that changed since they were checked, or that couldn't be
+	// recorded because another command held the state lock
+	pending := map[string]bool{}
+	all := false
+	quiet := time.NewTimer(debounce)
+	quiet.Stop()
+	defer quiet.Stop()
+
+	check := func(paths []string) {
+		for _, p := range paths {
+			if ctx.Err() != nil {
+				return
+			}
+			ev, ok, err := w.Check(ctx, p)
+			var held *lock.HeldError
+			if errors.As(err, &held) {
+				pending[p] = true
+				quiet.Reset(debounce)
+				continue
+			}
+			if err != nil {
+				warn(err)
+				continue
+			}
+			if ok {
+				emit(ev)
+			}
+		}
+	}
+
+	reload := func() error {
+		if err := w.Load(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+		syncWatches()
+		check(w.Paths())
+		return nil
+	}
+
+	if err := reload(); err != nil {
+		return err
+	}
+
+	raw := make(chan []byte)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case raw <- append([]byte(nil), buf[:n]...):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	tick := time.NewTicker(rescan)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-readErr:
+			return fmt.Errorf("read inotify events: %w", err)
+
+		case b := <-raw:
+			for _, ev := range parseInotifyEvents(b) {
+				if ev.mask&unix.IN_Q_OVERFLOW != 0 {
+					all = true
+					continue
+				}
+				dir, ok := wds[ev.wd]
+				if !ok {
+					continue
+				}
+				if ev.mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF) != 0 {
+					// everything that was in it is gone
+					_, _ = unix.InotifyRmWatch(fd, uint32(ev.wd))
+					delete(wds, ev.wd)
+					delete(byDir, dir)
+					for _, p := range w.Paths() {
+						if filepath.Dir(p) == dir {
+							pending[p] = true
+						}
+					}
+					continue
+				}
+				if ev.name != "" {
+					pending[filepath.Join(dir, ev.name)] = true
+				}
+			}
+			quiet.Reset(debounce)
+
+		case <-quiet.C:
+			paths := w.Paths()
+			if !all {
+				paths = make([]string, 0, len(pending))
+				for p := range pending {
+					paths = append(paths, p)
+				}
+				sort.Strings(paths)
+			}
+			pending, all = map[string]bool{}, false
+			check(paths)
+
+			if w.Stale() {
+				if err := reload(); err != nil {
+					return err
+				}
+			}
+
+		case <-tick.C:
+			if err := reload(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestParseInotifyEvents(t *testing.T) {
+	t.Parallel()
+
+	event := func(wd int32, mask uint32, name string) []byte {
+		// names are padded with NULs
+		n := 0
+		if name != "" {
+			n = (len(name)/16 + 1) * 16
+		}
+		b := make([]byte, unix.SizeofInotifyEvent+n)
+		binary.NativeEndian.PutUint32(b[0:4], uint32(wd))
+		binary.NativeEndian.PutUint32(b[4:8], mask)
+		binary.NativeEndian.PutUint32(b[12:16], uint32(n))
+		copy(b[unix.SizeofInotifyEvent:], name)
+		return b
+	}
+
+	var b []byte
+	b = append(b, event(1, unix.IN_CLOSE_WRITE, "a.esp")...)
+	b = append(b, event(2, unix.IN_DELETE_SELF, "")...)
+	b = append(b, event(1, unix.IN_MOVED_TO, "a_very_long_plugin_name.esp")...)
+	// truncated
+	b = append(b, event(3, unix.IN_DELETE, "b.esp")[:20]...)
+
+	assert.Equal(t, []inotifyEvent{
+		{wd: 1, mask: unix.IN_CLOSE_WRITE, name: "a.esp"},
+		{wd: 2, mask: unix.IN_DELETE_SELF},
+		{wd: 1, mask: unix.IN_MOVED_TO, name: "a_very_long_plugin_name.esp"},
+	}, parseInotifyEvents(b))
+}
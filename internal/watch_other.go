@@ -0,0 +1,32 @@
+//go:build !linux
+
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"time"
+)
+
+// Run watches the deployed files until ctx is done.
+// TODO: use FSEvents on darwin and ReadDirectoryChangesW on windows
+func (w *Watcher) Run(ctx context.Context, debounce, rescan time.Duration, emit func(DriftEvent), warn func(error)) error {
+	return ErrWatchUnsupported
+}
@@ -0,0 +1,67 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchDirs(t *testing.T) {
+	t.Parallel()
+
+	dirs := watchDirs([]string{
+		"/game/Data/b.esp",
+		"/game/Data/a.esp",
+		"/game/Data/textures/x.dds",
+		"/game/dinput8.dll",
+	})
+	assert.Equal(t, []string{"/game", "/game/Data", "/game/Data/textures"}, dirs)
+	assert.Empty(t, watchDirs(nil))
+}
+
+func TestDriftKind(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	p := filepath.Join(t.TempDir(), "a.esp")
+	require.NoError(t, os.WriteFile(p, []byte("deployed"), 0o644))
+	want, _, err := deploy.HashFile(p)
+	require.NoError(t, err)
+
+	kind, err := driftKind(ctx, nil, p, want)
+	require.NoError(t, err)
+	assert.Equal(t, "", kind)
+
+	require.NoError(t, os.WriteFile(p, []byte("changed"), 0o644))
+	kind, err = driftKind(ctx, nil, p, want)
+	require.NoError(t, err)
+	assert.Equal(t, DriftModified, kind)
+
+	require.NoError(t, os.Remove(p))
+	kind, err = driftKind(ctx, nil, p, want)
+	require.NoError(t, err)
+	assert.Equal(t, DriftMissing, kind)
+}
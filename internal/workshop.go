@@ -0,0 +1,228 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/vdf"
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// WorkshopItem is a Steam Workshop item that steam downloaded for a game.
+type WorkshopItem struct {
+	ID          string
+	Dir         string    // steamapps/workshop/content/<appid>/<id>
+	Files       []string  // relative to Dir, using forward slashes
+	SizeBytes   int64     // as reported by steam (0 if unknown)
+	TimeUpdated time.Time // zero if unknown
+	Subscribed  bool      // listed in the manifest (not only on disk)
+}
+
+// workshopManifestItem is what we read from appworkshop_<appid>.acf
+type workshopManifestItem struct {
+	size        int64
+	timeUpdated time.Time
+}
+
+// ListWorkshopItems scans steamapps/workshop/content/<appid> for the Workshop
+// items of a steam game. It returns no items (and no error) if the game
+// doesn't have any Workshop content.
+func ListWorkshopItems(gi dbq.GameInstall) ([]WorkshopItem, error) {
+	steamapps, err := SteamappsDir(gi)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := map[string]workshopManifestItem{}
+	mp := filepath.Join(steamapps, "workshop", "appworkshop_"+gi.StoreGameID+".acf")
+	if f, err := os.Open(mp); err == nil {
+		manifest, err = parseWorkshopManifest(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", mp, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	root := filepath.Join(steamapps, "workshop", "content", gi.StoreGameID)
+	entries, err := os.ReadDir(root)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var items []WorkshopItem
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		item := WorkshopItem{ID: e.Name(), Dir: filepath.Join(root, e.Name())}
+		if m, ok := manifest[item.ID]; ok {
+			item.Subscribed = true
+			item.SizeBytes = m.size
+			item.TimeUpdated = m.timeUpdated
+		}
+
+		var size int64
+		err := filepath.WalkDir(item.Dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(item.Dir, p)
+			if err != nil {
+				return err
+			}
+			item.Files = append(item.Files, filepath.ToSlash(rel))
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scan workshop item %s: %w", item.ID, err)
+		}
+		sort.Strings(item.Files)
+		if item.SizeBytes == 0 {
+			item.SizeBytes = size
+		}
+
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return workshopIDLess(items[i].ID, items[j].ID)
+	})
+
+	return items, nil
+}
+
+// parseWorkshopManifest reads the WorkshopItemsInstalled section of an
+// appworkshop_<appid>.acf file:
+//
+//	"AppWorkshop" { "WorkshopItemsInstalled" { "<id>" { "size" "..." "timeupdated" "..." } } }
+func parseWorkshopManifest(r io.Reader) (map[string]workshopManifestItem, error) {
+	parsed, err := vdf.NewParser(r).Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	items := map[string]workshopManifestItem{}
+
+	aw, ok := parsed["AppWorkshop"].(map[string]any)
+	if !ok {
+		return items, nil
+	}
+	installed, ok := aw["WorkshopItemsInstalled"].(map[string]any)
+	if !ok {
+		return items, nil
+	}
+
+	for id, v := range installed {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		var item workshopManifestItem
+		if s, ok := m["size"].(string); ok {
+			item.size, _ = strconv.ParseInt(s, 10, 64)
+		}
+		if s, ok := m["timeupdated"].(string); ok {
+			if ts, err := strconv.ParseInt(s, 10, 64); err == nil && ts > 0 {
+				item.timeUpdated = time.Unix(ts, 0).UTC()
+			}
+		}
+		items[id] = item
+	}
+
+	return items, nil
+}
+
+// workshop ids are numbers; sort them as such
+func workshopIDLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// WorkshopCollisions reports the planned files that a Workshop item also
+// provides. Workshop content isn't installed into the game directory but
+// games that load it treat it as if it was, so an item and a profile item
+// that ship the same relative path fight over which one the game uses.
+func WorkshopCollisions(items []WorkshopItem, p *plan.Plan) []plan.Note {
+	byPath := map[string][]string{}
+	for _, item := range items {
+		for _, f := range item.Files {
+			k := strings.ToLower(f)
+			byPath[k] = append(byPath[k], item.ID)
+		}
+	}
+
+	var notes []plan.Note
+	for _, f := range p.Files {
+		if f.Target != plan.DefaultTarget {
+			continue
+		}
+		ids, ok := byPath[strings.ToLower(f.RelPath)]
+		if !ok {
+			continue
+		}
+
+		notes = append(notes, plan.Note{
+			Kind: "workshop",
+			Key:  f.RelPath,
+			Message: fmt.Sprintf("also provided by Steam Workshop item %s",
+				strings.Join(ids, ", ")),
+			Sources: []plan.Source{f.Winner},
+		})
+	}
+
+	return notes
+}
+
+// AddWorkshopNotes adds the files that Steam Workshop items also provide to
+// the notes of a plan.
+func AddWorkshopNotes(gi dbq.GameInstall, pl *plan.Plan) {
+	if gi.StoreID != "steam" {
+		return
+	}
+
+	items, err := ListWorkshopItems(gi)
+	if err != nil {
+		pl.Warnings = append(pl.Warnings, fmt.Sprintf("scan steam workshop: %v", err))
+		return
+	}
+	pl.Notes = append(pl.Notes, WorkshopCollisions(items, pl)...)
+}
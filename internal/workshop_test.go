@@ -0,0 +1,82 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkshopManifest(t *testing.T) {
+	t.Parallel()
+
+	acf := `"AppWorkshop"
+{
+	"appid"		"294100"
+	"WorkshopItemsInstalled"
+	{
+		"818773962"
+		{
+			"size"		"1048576"
+			"timeupdated"		"1699999999"
+			"manifest"		"123"
+		}
+		"2009463077"
+		{
+			"size"		"42"
+		}
+	}
+}
+`
+
+	items, err := parseWorkshopManifest(strings.NewReader(acf))
+	require.NoError(t, err)
+
+	assert.Len(t, items, 2)
+	assert.Equal(t, int64(1048576), items["818773962"].size)
+	assert.Equal(t, time.Unix(1699999999, 0).UTC(), items["818773962"].timeUpdated)
+	assert.Equal(t, int64(42), items["2009463077"].size)
+	assert.True(t, items["2009463077"].timeUpdated.IsZero())
+}
+
+func TestWorkshopCollisions(t *testing.T) {
+	t.Parallel()
+
+	item := &plan.Item{ModName: "A"}
+	p := &plan.Plan{Files: []plan.File{
+		{Target: plan.DefaultTarget, RelPath: "Data/textures/a.dds", Winner: plan.Source{Item: item}},
+		{Target: plan.DefaultTarget, RelPath: "Data/b.esp", Winner: plan.Source{Item: item}},
+	}}
+
+	notes := WorkshopCollisions([]WorkshopItem{
+		{ID: "1", Files: []string{"data/Textures/A.dds"}},
+		{ID: "2", Files: []string{"Data/textures/a.dds", "Data/c.esp"}},
+	}, p)
+
+	if assert.Len(t, notes, 1) {
+		assert.Equal(t, "workshop", notes[0].Kind)
+		assert.Equal(t, "Data/textures/a.dds", notes[0].Key)
+		assert.Equal(t, "also provided by Steam Workshop item 1, 2", notes[0].Message)
+	}
+}
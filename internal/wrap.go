@@ -0,0 +1,163 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/perf"
+	"github.com/spf13/viper"
+)
+
+// PreparedArchive is an input file that is ready to be imported: the file
+// itself if it's an archive, or else an archive that contains it.
+type PreparedArchive struct {
+	PathToImport string
+	Wrapped      bool
+	WrappedFrom  string // e.g. "pdf" (without dot), or "" if unknown
+	MemberName   string // tar member name (basename of input)
+	Cleanup      func()
+}
+
+// ArchiveBuilderFromConfig returns an archive builder using the configured
+// compression and SOURCE_DATE_EPOCH.
+func ArchiveBuilderFromConfig() (archive.Builder, error) {
+	c, err := archive.ParseCompression(viper.GetString("archive_compression"))
+	if err != nil {
+		return archive.Builder{}, fmt.Errorf("archive_compression: %w", err)
+	}
+	epoch, err := archive.SourceDateEpoch()
+	if err != nil {
+		return archive.Builder{}, err
+	}
+	return archive.Builder{
+		Bsdtar:      viper.GetString("bsdtar"),
+		Compression: c,
+		Epoch:       epoch,
+	}, nil
+}
+
+// PrepareArchive validates inputPath as an archive by listing it (within
+// listTimeout, 0 for no limit), or wraps it into one if bsdtar can't read
+// it. Call Cleanup once the archive was imported.
+func PrepareArchive(ctx context.Context, inputPath string, listTimeout time.Duration) (PreparedArchive, error) {
+	// First, try to validate as an archive with bsdtar -t
+	ctxT, cancel := ListContext(ctx, listTimeout)
+	defer cancel()
+
+	if err := CheckArchive(ctxT, inputPath); err == nil {
+		return PreparedArchive{PathToImport: inputPath, Wrapped: false, Cleanup: func() {}}, nil
+	}
+
+	// Not an archive (or bsdtar couldn't list it) -- wrap it into one.
+	tmpDir := viper.GetString("tmp_dir")
+	wrapped, cleanup, err := wrapIntoArchive(ctx, tmpDir, inputPath)
+	if err != nil {
+		return PreparedArchive{}, err
+	}
+
+	// Validate the wrapped archive too (should succeed unless we wrote a bad archive)
+	ctxT2, cancel2 := ListContext(ctx, listTimeout)
+	defer cancel2()
+	if err := CheckArchive(ctxT2, wrapped); err != nil {
+		cleanup()
+		return PreparedArchive{}, fmt.Errorf("wrapped archive failed bsdtar validation: %w", err)
+	}
+
+	return PreparedArchive{PathToImport: wrapped, Wrapped: true, Cleanup: cleanup}, nil
+}
+
+// ListContext returns ctx with the timeout of listing an archive (none if
+// it's 0).
+func ListContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// CheckArchive reports whether bsdtar can list archivePath, i.e., whether
+// it's an archive that modctl can extract.
+func CheckArchive(ctx context.Context, archivePath string) error {
+	defer perf.Track(perf.Commands)()
+
+	// Keep output quiet on success; capture stderr for failure message.
+	cmd := exec.CommandContext(ctx, viper.GetString("bsdtar"), "-t", "-f", archivePath)
+	var stderr bytes.Buffer
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return fmt.Errorf("bsdtar -t failed: %s", msg)
+		}
+		return fmt.Errorf("bsdtar -t failed: %w", err)
+	}
+	return nil
+}
+
+// wrapIntoArchive writes an archive containing just srcPath (named as its
+// basename) using the configured archive builder, so that wrapping the same
+// file again produces the same archive.
+func wrapIntoArchive(ctx context.Context, tmpDir, srcPath string) (wrappedPath string, cleanup func(), err error) {
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return "", nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return "", nil, fmt.Errorf("cannot wrap non-regular file: %s", srcPath)
+	}
+
+	base := filepath.Base(srcPath)
+	if base == "" || base == "." || base == ".." {
+		return "", nil, fmt.Errorf("invalid input filename: %q", base)
+	}
+
+	builder, err := ArchiveBuilderFromConfig()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Reserve a name; the builder renames the finished archive over it
+	f, err := os.CreateTemp(tmpDir, "modctl-wrap-*"+builder.Compression.Extension())
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp archive: %w", err)
+	}
+	tmpName := f.Name()
+	_ = f.Close()
+
+	cleanup = func() { _ = os.Remove(tmpName) }
+
+	if err := builder.Build(ctx, tmpName, []archive.Entry{{Name: base, Path: srcPath}}); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("wrap input: %w", err)
+	}
+
+	return tmpName, cleanup, nil
+}
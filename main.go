@@ -19,21 +19,16 @@
 package main
 
 import (
-	"embed"
+	_ "embed"
 
 	"github.com/mfinelli/modctl/cmd"
-	"github.com/mfinelli/modctl/internal"
 )
 
-//go:embed migrations/*.sql
-var migrations embed.FS
-
 //go:embed sample.tar.gz
 var sampleTarGz []byte
 
 func main() {
 	cmd.SampleTarGz = sampleTarGz
-	internal.Migrations = migrations
 
 	cmd.Execute()
 }
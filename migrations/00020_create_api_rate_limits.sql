@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE api_rate_limits
+-- last observed rate limit state of external APIs (from response headers)
+(
+  -- which api: 'nexus'
+  api TEXT PRIMARY KEY CHECK (LENGTH(api) > 0),
+
+  hourly_limit INTEGER CHECK (hourly_limit >= 0),
+  hourly_remaining INTEGER CHECK (hourly_remaining >= 0),
+  hourly_reset_at TEXT,
+
+  daily_limit INTEGER CHECK (daily_limit >= 0),
+  daily_remaining INTEGER CHECK (daily_remaining >= 0),
+  daily_reset_at TEXT,
+
+  -- when we last saw the headers
+  observed_at TEXT NOT NULL,
+
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+  updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+) STRICT, WITHOUT ROWID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_rate_limits;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE plugin_orders
+-- plugin_orders: per-profile plugin load order (for games that have one,
+-- e.g., Bethesda games)
+--
+-- Notes:
+-- - this is the source of truth: the game's plugins.txt is written from it
+--   and the results of external sorters (LOOT) are read back into it.
+-- - position is 0-based and dense; the whole order is rewritten at once.
+(
+  id INTEGER PRIMARY KEY,
+  profile_id INTEGER NOT NULL REFERENCES profiles(id) ON UPDATE CASCADE ON DELETE CASCADE,
+  position INTEGER NOT NULL CHECK (position >= 0),
+  plugin_name TEXT NOT NULL CHECK (LENGTH(plugin_name) > 0),
+  enabled INTEGER NOT NULL DEFAULT TRUE CHECK (enabled IN (TRUE, FALSE)),
+
+  -- where the order came from: 'loot' or 'plugins_txt' (read back from the
+  -- game without sorting)
+  source TEXT NOT NULL CHECK (source IN ('loot', 'plugins_txt')),
+
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+  updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+  UNIQUE (profile_id, position),
+  -- plugin names are case-insensitive (windows filesystem semantics)
+  UNIQUE (profile_id, plugin_name COLLATE NOCASE)
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_plugin_orders_profile ON plugin_orders(profile_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_plugin_orders_profile;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE plugin_orders;
+-- +goose StatementEnd
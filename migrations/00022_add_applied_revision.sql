@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- applied_revision: snapshot (json) of the applied profile at apply time
+-- (enabled versions, priorities, remap rules, and overrides) so that changes
+-- to the profile since it was applied can be detected
+ALTER TABLE game_installs ADD COLUMN applied_revision TEXT
+  CHECK (applied_revision IS NULL OR json_valid(applied_revision));
+-- +goose StatementEnd
+
+-- +goose Down
+-- TODO: rebuild the game_installs table without the column we added
+-- https://stackoverflow.com/a/66399224
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE profile_item_hidden_files
+-- profile_item_hidden_files: files of a profile item that aren't deployed
+--
+-- Notes:
+-- - relpath is the (normalized) destination path of the file, as shown by
+--   `modctl profiles conflicts`, in whichever target the item deploys it.
+-- - hiding a file lets a lower priority mod win it without reordering the
+--   whole mod (like "hiding" a file in other mod managers).
+(
+  id INTEGER PRIMARY KEY,
+  profile_item_id INTEGER NOT NULL REFERENCES profile_items(id) ON UPDATE CASCADE ON DELETE CASCADE,
+  relpath TEXT NOT NULL CHECK (LENGTH(relpath) > 0),
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+  UNIQUE (profile_item_id, relpath)
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_profile_item_hidden_files_item ON profile_item_hidden_files(profile_item_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_profile_item_hidden_files_item;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE profile_item_hidden_files;
+-- +goose StatementEnd
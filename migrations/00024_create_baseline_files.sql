@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE baseline_files
+-- baseline_files: what was in the targets of a game install when modctl
+-- adopted it (before it deployed anything)
+--
+-- One row per (game_install, target, relpath) that existed at scan time.
+--
+-- Notes:
+-- - files that modctl had already deployed are recorded with the content of
+--   their backup (what was there before), or not at all if there was nothing.
+-- - mtime_ns lets a later scan skip hashing files that weren't touched; it's
+--   NULL for files that were recorded from a backup.
+-- - a file under the root of a more specific target (e.g., Data inside the
+--   game directory) is recorded for that target.
+(
+  id INTEGER PRIMARY KEY,
+  game_install_id INTEGER NOT NULL REFERENCES game_installs(id) ON UPDATE CASCADE ON DELETE CASCADE,
+  target_id INTEGER NOT NULL REFERENCES targets(id) ON UPDATE CASCADE ON DELETE CASCADE,
+  relpath TEXT NOT NULL CHECK (LENGTH(relpath) > 0),
+  content_sha256 TEXT NOT NULL CHECK (LENGTH(content_sha256) = 64 AND content_sha256 GLOB '[0-9a-f]*'),
+  size_bytes INTEGER NOT NULL CHECK (size_bytes >= 0),
+  mtime_ns INTEGER,
+  scanned_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+  UNIQUE (game_install_id, target_id, relpath)
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_baseline_files_target ON baseline_files(target_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- baseline_scanned_at: when the baseline of the install was recorded (NULL
+-- if it never was, so every file that modctl didn't deploy is unknown)
+ALTER TABLE game_installs ADD COLUMN baseline_scanned_at TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- TODO: rebuild the game_installs table without the column we added
+-- https://stackoverflow.com/a/66399224
+
+-- +goose StatementBegin
+DROP INDEX idx_baseline_files_target;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE baseline_files;
+-- +goose StatementEnd
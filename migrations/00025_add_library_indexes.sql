@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- mods list orders by name and shell completion searches names by prefix
+-- (LIKE is case-insensitive so it can only use a NOCASE index for that)
+CREATE INDEX idx_mod_pages_game_name
+  ON mod_pages(game_install_id, name COLLATE NOCASE, id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- finding the latest version of a mod without sorting all of them
+CREATE INDEX idx_mod_file_versions_file_created
+  ON mod_file_versions(mod_file_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- give the query planner statistics to choose between the new indexes and
+-- the old ones
+ANALYZE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_mod_file_versions_file_created;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX idx_mod_pages_game_name;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- corrupted_at: when the blob was found to not match its hash anymore (its
+-- file was moved to the quarantine directory then); NULL if it's fine
+ALTER TABLE blobs ADD COLUMN corrupted_at TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- TODO: rebuild the blobs table without the column we added
+-- https://stackoverflow.com/a/66399224
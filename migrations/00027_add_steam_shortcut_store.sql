@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- non-steam games that were added to steam (shortcuts.vdf); `modctl init`
+-- seeds it too but existing databases need it before the next refresh
+INSERT INTO stores (id, display_name, implementation, enabled)
+VALUES ('steam-shortcut', 'Steam (non-Steam games)', 'steam-shortcut', TRUE)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- fails (ON DELETE RESTRICT) while there are installs of the store
+DELETE FROM stores WHERE id = 'steam-shortcut';
+-- +goose StatementEnd
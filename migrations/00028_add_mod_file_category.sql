@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- category: the category of the file on its Nexus mod page, lowercase (e.g.,
+-- "main", "optional", "miscellaneous", or "old_version"); NULL if it isn't
+-- known or the file isn't from Nexus
+ALTER TABLE mod_files ADD COLUMN category TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_mod_files_nexus ON mod_files(mod_page_id, nexus_file_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_mod_files_nexus;
+-- +goose StatementEnd
+
+-- TODO: rebuild the mod_files table without the column we added
+-- https://stackoverflow.com/a/66399224
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE nexus_file_updates
+-- nexus_file_updates: which file of a Nexus mod replaced which (the
+-- "file_updates" of the files list); following old_file_id -> new_file_id
+-- from an imported file leads to the file that supersedes it
+--
+-- Notes:
+-- - this mirrors upstream (it isn't per game install) and is refreshed
+--   whenever modctl lists the files of a mod
+(
+  nexus_game_domain TEXT NOT NULL CHECK (LENGTH(nexus_game_domain) > 0),
+  nexus_mod_id INTEGER NOT NULL,
+  old_file_id INTEGER NOT NULL,
+  new_file_id INTEGER NOT NULL,
+
+  -- when the new file was uploaded (if nexus said)
+  uploaded_at TEXT,
+
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+  PRIMARY KEY (nexus_game_domain, nexus_mod_id, old_file_id, new_file_id)
+) STRICT, WITHOUT ROWID;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- nexus_file_id: the file on the Nexus mod page that the archive was
+-- downloaded as (every upload of a new version is a new file there); NULL if
+-- it isn't known
+ALTER TABLE mod_file_versions ADD COLUMN nexus_file_id INTEGER;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- a mod file with a single version was that nexus file
+UPDATE mod_file_versions
+SET nexus_file_id = (
+  SELECT f.nexus_file_id FROM mod_files f WHERE f.id = mod_file_versions.mod_file_id
+)
+WHERE (
+  SELECT COUNT(*) FROM mod_file_versions v2
+  WHERE v2.mod_file_id = mod_file_versions.mod_file_id
+) = 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE nexus_file_updates;
+-- +goose StatementEnd
+
+-- TODO: rebuild the mod_file_versions table without the column we added
+-- https://stackoverflow.com/a/66399224
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- game_version: the version of the game that the profile was built against
+-- (for steam games the build id); applying it to another version warns since
+-- mods often break when a game is patched. NULL if it wasn't declared.
+ALTER TABLE profiles ADD COLUMN game_version TEXT CHECK (game_version IS NULL OR LENGTH(game_version) > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- TODO: rebuild the profiles table without the column we added
+-- https://stackoverflow.com/a/66399224
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE mod_attachments
+-- mod_attachments: supplementary files of a mod page (patches, custom INIs,
+-- screenshots, notes) that are kept in the blob store with its archives
+--
+-- Notes:
+-- - archives are stored as archive blobs, other files as override blobs; a
+--   file that is already in the store keeps the kind of its blob.
+-- - attachments are never deployed: they're only kept (and exported) with
+--   the mod.
+(
+  id INTEGER PRIMARY KEY,
+  mod_page_id INTEGER NOT NULL REFERENCES mod_pages(id) ON UPDATE CASCADE ON DELETE CASCADE,
+
+  -- human label, unique per mod page (defaults to the file name)
+  label TEXT NOT NULL CHECK (LENGTH(label) > 0),
+
+  blob_sha256 TEXT NOT NULL REFERENCES blobs(sha256) ON UPDATE CASCADE ON DELETE RESTRICT,
+
+  -- the name of the file that was attached
+  original_name TEXT NOT NULL CHECK (LENGTH(original_name) > 0),
+
+  notes TEXT,
+
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+  UNIQUE (mod_page_id, label)
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_mod_attachments_blob ON mod_attachments(blob_sha256);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_mod_attachments_blob;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE mod_attachments;
+-- +goose StatementEnd
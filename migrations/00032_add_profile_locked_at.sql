@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- locked_at: when the profile was locked (see modctl profiles lock); a locked
+-- profile can still be applied but its mods can't be added, removed, enabled,
+-- disabled, hidden, or reordered. NULL if it isn't locked.
+ALTER TABLE profiles ADD COLUMN locked_at TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- TODO: rebuild the profiles table without the column we added
+-- https://stackoverflow.com/a/66399224
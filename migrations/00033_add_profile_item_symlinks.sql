@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- symlinks: what to do with the symlinks in the archive of the item; 'copy'
+-- deploys a copy of the file that a link points to if it's in the archive as
+-- well (links that point elsewhere are skipped with a warning) and 'skip'
+-- doesn't deploy any of them.
+ALTER TABLE profile_items ADD COLUMN symlinks TEXT NOT NULL DEFAULT 'copy'
+  CHECK (symlinks IN ('copy', 'skip'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- TODO: rebuild the profile_items table without the column we added
+-- https://stackoverflow.com/a/66399224
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE file_hashes
+-- file_hashes: the hash cache, i.e., the sha256 of files in the game
+-- directories by their stat identity so that checking deployed files for
+-- changes doesn't have to read the ones that weren't touched
+--
+-- Notes:
+-- - a row is only used while the device, inode, size, and modification time
+--   (in nanoseconds) of the file are the same; otherwise the file is hashed
+--   again and the row is replaced.
+-- - it's only a cache: any of its rows can be deleted (and it isn't part of
+--   exports).
+(
+  -- absolute path
+  path TEXT PRIMARY KEY CHECK (LENGTH(path) > 0),
+
+  device INTEGER NOT NULL,
+  inode INTEGER NOT NULL,
+  size_bytes INTEGER NOT NULL CHECK (size_bytes >= 0),
+  mtime_ns INTEGER NOT NULL,
+
+  sha256 TEXT NOT NULL CHECK (LENGTH(sha256) = 64 AND sha256 GLOB '[0-9a-f]*'),
+
+  hashed_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE file_hashes;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- unpacked_bytes: the size of the regular files in an archive once it's
+-- extracted (NULL until it's needed the first time; the contents of a blob
+-- never change so neither does this)
+ALTER TABLE blobs ADD COLUMN unpacked_bytes INTEGER
+  CHECK (unpacked_bytes IS NULL OR unpacked_bytes >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE blobs DROP COLUMN unpacked_bytes;
+-- +goose StatementEnd
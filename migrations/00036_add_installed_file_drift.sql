@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- drifted_at/drift_kind: set by `modctl watch` when a deployed file was
+-- changed (modified) or deleted (missing) by something else; cleared when
+-- the file is back to what was deployed or deployed again
+ALTER TABLE installed_files ADD COLUMN drifted_at TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE installed_files ADD COLUMN drift_kind TEXT
+  CHECK (drift_kind IS NULL OR drift_kind IN ('modified', 'missing'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE installed_files DROP COLUMN drift_kind;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE installed_files DROP COLUMN drifted_at;
+-- +goose StatementEnd
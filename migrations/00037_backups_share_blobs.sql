@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+-- backups are deduplicated by content across targets and game installs: a
+-- backup can use any blob with the same content, e.g., one that is stored
+-- as an override already, instead of only kind=backup ones
+DROP TRIGGER trg_backups_blob_kind_ins;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER trg_backups_blob_kind_upd;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_backups_blob_exists_ins
+BEFORE INSERT ON backups
+FOR EACH ROW
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+  END;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_backups_blob_exists_upd
+BEFORE UPDATE OF backup_blob_sha256 ON backups
+FOR EACH ROW
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+  END;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER trg_backups_blob_exists_upd;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER trg_backups_blob_exists_ins;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_backups_blob_kind_ins
+BEFORE INSERT ON backups
+FOR EACH ROW
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) <> 'backup'
+      THEN RAISE(ABORT, 'backup_blob_sha256 must reference a blob with kind=backup')
+  END;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_backups_blob_kind_upd
+BEFORE UPDATE OF backup_blob_sha256 ON backups
+FOR EACH ROW
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) <> 'backup'
+      THEN RAISE(ABORT, 'backup_blob_sha256 must reference a blob with kind=backup')
+  END;
+END;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- is_template: the override has ${name} placeholders (the install root, the
+-- profile name, override_vars from the config, ...) that are expanded when
+-- it's deployed, so the same override works in every game install
+ALTER TABLE overrides ADD COLUMN is_template INTEGER NOT NULL DEFAULT 0
+  CHECK (is_template IN (0, 1));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE overrides DROP COLUMN is_template;
+-- +goose StatementEnd
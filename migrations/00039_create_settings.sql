@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE settings
+-- settings: small pieces of state kept with the database instead of (or as
+-- well as) the XDG state directory, e.g., the active selection, so that a
+-- database that's synced between machines carries them along
+--
+-- Notes:
+-- - values are text; an empty value is an unset setting that was changed at
+--   updated_at (which is compared with the copy in the state directory to
+--   find the newer one).
+(
+  key TEXT PRIMARY KEY CHECK (LENGTH(key) > 0),
+  value TEXT NOT NULL,
+  updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE settings;
+-- +goose StatementEnd
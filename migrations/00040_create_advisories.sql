@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE advisories
+-- advisories: mod files that are known to be malicious or broken, from the
+-- advisory feed (see `modctl advisories update`); import and apply check
+-- the archives against them
+--
+-- Notes:
+-- - an advisory matches an archive by its sha256, or by its nexus mod (and
+--   file, unless nexus_file_id is NULL: then every file of the mod)
+-- - the table is replaced with the feed on every update; where it came
+--   from is in settings (advisory_feed)
+(
+  id INTEGER PRIMARY KEY,
+  advisory_id TEXT NOT NULL UNIQUE CHECK (LENGTH(advisory_id) > 0),
+  severity TEXT NOT NULL CHECK (severity IN ('malicious', 'broken')),
+  summary TEXT NOT NULL,
+  url TEXT,
+  published_at TEXT,
+
+  archive_sha256 TEXT CHECK (archive_sha256 IS NULL OR LENGTH(archive_sha256) = 64),
+  nexus_game_domain TEXT,
+  nexus_mod_id INTEGER,
+  nexus_file_id INTEGER,
+
+  CHECK (archive_sha256 IS NOT NULL OR
+    (nexus_game_domain IS NOT NULL AND nexus_mod_id IS NOT NULL)),
+  CHECK (nexus_mod_id IS NULL OR nexus_game_domain IS NOT NULL),
+  CHECK (nexus_file_id IS NULL OR nexus_mod_id IS NOT NULL)
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_advisories_archive ON advisories(archive_sha256);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_advisories_nexus ON advisories(nexus_game_domain, nexus_mod_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE advisories;
+-- +goose StatementEnd
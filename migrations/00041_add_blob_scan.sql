@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- scan_verdict: what the scanner (scan_command) said about an archive when
+-- it was imported: clean, detected, or error (the scan couldn't run, and
+-- the archive was imported anyway); NULL if it wasn't scanned.
+-- scan_detail has the output of the scanner for the last two.
+ALTER TABLE blobs ADD COLUMN scan_verdict TEXT
+  CHECK (scan_verdict IS NULL OR scan_verdict IN ('clean', 'detected', 'error'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE blobs ADD COLUMN scan_detail TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE blobs ADD COLUMN scanned_at TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE blobs DROP COLUMN scanned_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE blobs DROP COLUMN scan_detail;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE blobs DROP COLUMN scan_verdict;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deploy_method: how files are put in place on the target: auto (reflink,
+-- else hardlink, else copy, depending on what the filesystem supports),
+-- copy, reflink, or hardlink (both falling back to a copy)
+ALTER TABLE targets ADD COLUMN deploy_method TEXT NOT NULL DEFAULT 'auto'
+  CHECK (deploy_method IN ('auto', 'copy', 'reflink', 'hardlink'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- backup_policy: whether files that modctl didn't deploy are backed up
+-- before they're replaced (backup) or not (none, e.g., for caches or configs
+-- that the game writes again)
+ALTER TABLE targets ADD COLUMN backup_policy TEXT NOT NULL DEFAULT 'backup'
+  CHECK (backup_policy IN ('backup', 'none'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE targets DROP COLUMN backup_policy;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE targets DROP COLUMN deploy_method;
+-- +goose StatementEnd
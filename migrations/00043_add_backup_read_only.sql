@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- read_only: the original file was read-only (on Windows: had the read-only
+-- attribute), so it's made read-only again when it's restored
+ALTER TABLE backups ADD COLUMN read_only INTEGER NOT NULL DEFAULT 0
+  CHECK (read_only IN (0, 1));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE backups DROP COLUMN read_only;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- verified_vanilla_at: when `games verify-vanilla` last found the install
+-- pristine (nothing deployed, every vanilla file as Steam's depot manifests or
+-- the baseline say); NULL if it never did
+ALTER TABLE game_installs ADD COLUMN verified_vanilla_at TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE game_installs DROP COLUMN verified_vanilla_at;
+-- +goose StatementEnd
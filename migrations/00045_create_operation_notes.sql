@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- revision: snapshot (json) of the profile that an apply deployed, like
+-- game_installs.applied_revision, so that notes about the operation (see
+-- below) say which mods they were about
+ALTER TABLE operations ADD COLUMN revision TEXT
+  CHECK (revision IS NULL OR json_valid(revision));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE operation_notes
+-- operation_notes: what testing an applied revision found (e.g., "crashes in
+-- Whiterun") and whether it worked
+--
+-- Notes:
+-- - status is pass or fail, or NULL for a note that doesn't say either way;
+--   a note without a text has to have a status.
+(
+  id INTEGER PRIMARY KEY,
+  operation_id INTEGER NOT NULL REFERENCES operations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+  status TEXT CHECK (status IS NULL OR status IN ('pass', 'fail')),
+  note TEXT NOT NULL DEFAULT '',
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+  CHECK (note <> '' OR status IS NOT NULL)
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_operation_notes_operation ON operation_notes(operation_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_operation_notes_operation;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE operation_notes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE operations DROP COLUMN revision;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE download_hosts
+-- download_hosts: how politely files are downloaded from a host (and its
+-- subdomains), on top of download_limit_rate and download_concurrency
+--
+-- Notes:
+-- - host is lowercase, without a trailing dot.
+-- - limit_rate is in bytes per second; NULL (like concurrency) doesn't limit
+--   the host beyond the overall limits.
+(
+  host TEXT PRIMARY KEY,
+  limit_rate INTEGER CHECK (limit_rate IS NULL OR limit_rate > 0),
+  concurrency INTEGER CHECK (concurrency IS NULL OR concurrency > 0),
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+  updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+  CHECK (host <> '' AND host = lower(host))
+) STRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE download_hosts;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- fetched_from: the blob mirror (see blob_mirrors) that a missing archive was
+-- fetched from during an apply, and fetched_at when; both NULL for blobs that
+-- were imported or downloaded as usual
+ALTER TABLE blobs ADD COLUMN fetched_from TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE blobs ADD COLUMN fetched_at TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE blobs DROP COLUMN fetched_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE blobs DROP COLUMN fetched_from;
+-- +goose StatementEnd
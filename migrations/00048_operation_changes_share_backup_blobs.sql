@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the backup of an operation change is a backup (see 00037), which can use a
+-- blob of any kind with the same content
+DROP TRIGGER trg_opchg_backup_blob_kind_ins;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER trg_opchg_backup_blob_kind_upd;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_opchg_backup_blob_exists_ins
+BEFORE INSERT ON operation_changes
+FOR EACH ROW
+WHEN NEW.backup_blob_sha256 IS NOT NULL
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+  END;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_opchg_backup_blob_exists_upd
+BEFORE UPDATE OF backup_blob_sha256 ON operation_changes
+FOR EACH ROW
+WHEN NEW.backup_blob_sha256 IS NOT NULL
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+  END;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER trg_opchg_backup_blob_exists_upd;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER trg_opchg_backup_blob_exists_ins;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_opchg_backup_blob_kind_ins
+BEFORE INSERT ON operation_changes
+FOR EACH ROW
+WHEN NEW.backup_blob_sha256 IS NOT NULL
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) <> 'backup'
+      THEN RAISE(ABORT, 'backup_blob_sha256 must reference a blob with kind=backup')
+  END;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_opchg_backup_blob_kind_upd
+BEFORE UPDATE OF backup_blob_sha256 ON operation_changes
+FOR EACH ROW
+WHEN NEW.backup_blob_sha256 IS NOT NULL
+BEGIN
+  SELECT
+  CASE
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) IS NULL
+      THEN RAISE(ABORT, 'backup_blob_sha256 does not reference an existing blob')
+    WHEN (SELECT kind FROM blobs WHERE sha256 = NEW.backup_blob_sha256) <> 'backup'
+      THEN RAISE(ABORT, 'backup_blob_sha256 must reference a blob with kind=backup')
+  END;
+END;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deploy_method can also be symlink (link the files to their copies in the
+-- blob stores) or overlay (deploy into a layer that is mounted over the
+-- target); sqlite can't change the CHECK of a column, so it's replaced
+ALTER TABLE targets ADD COLUMN deploy_method_new TEXT NOT NULL DEFAULT 'auto'
+  CHECK (deploy_method_new IN ('auto', 'copy', 'reflink', 'hardlink', 'symlink', 'overlay'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE targets SET deploy_method_new = deploy_method;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE targets DROP COLUMN deploy_method;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE targets RENAME COLUMN deploy_method_new TO deploy_method;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE targets ADD COLUMN deploy_method_old TEXT NOT NULL DEFAULT 'auto'
+  CHECK (deploy_method_old IN ('auto', 'copy', 'reflink', 'hardlink'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE targets SET deploy_method_old = deploy_method
+WHERE deploy_method IN ('auto', 'copy', 'reflink', 'hardlink');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE targets DROP COLUMN deploy_method;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE targets RENAME COLUMN deploy_method_old TO deploy_method;
+-- +goose StatementEnd
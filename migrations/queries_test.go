@@ -0,0 +1,142 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migratedDB returns a database with every migration applied.
+func migratedDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+t.TempDir()+"/test.db?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	p, err := goose.NewProvider(goose.DialectSQLite3, db, os.DirFS("."))
+	require.NoError(t, err)
+	_, err = p.Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+// namedQuery returns a query of queries.sql as sqlite runs it: the
+// arguments (sqlc.arg(...) or ?) of the queries used here are all the same
+// one, ?1.
+func namedQuery(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile("../queries.sql")
+	require.NoError(t, err)
+
+	for _, block := range strings.Split(string(data), "-- name: ")[1:] {
+		if !strings.HasPrefix(block, name+" ") {
+			continue
+		}
+		query := block[strings.Index(block, "\n")+1:]
+		query = query[:strings.Index(query, ";\n")+1]
+		query = strings.ReplaceAll(query, "= ?", "= ?1")
+		return regexp.MustCompile(`sqlc\.n?arg\(\w+\)`).ReplaceAllString(query, "?1")
+	}
+	t.Fatalf("no query %s in queries.sql", name)
+	return ""
+}
+
+func TestListModsByGameInstall(t *testing.T) {
+	t.Parallel()
+
+	db := migratedDB(t)
+	_, err := db.Exec(`
+		INSERT INTO game_installs (id, store_id, store_game_id, display_name, install_root)
+		VALUES (1, 'steam', '489830', 'Skyrim', '/games/skyrim');
+		INSERT INTO blobs (sha256, kind, size_bytes) VALUES
+		  ('aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 'archive', 1),
+		  ('bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 'archive', 1),
+		  ('cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc', 'archive', 1);
+		INSERT INTO mod_pages (id, game_install_id, name, source_kind) VALUES
+		  (1, 1, 'SkyUI', 'manual'), (2, 1, 'Unused', 'manual');
+		INSERT INTO mod_files (id, mod_page_id, label) VALUES (1, 1, 'main'), (2, 1, 'patch');
+		INSERT INTO mod_file_versions (id, mod_file_id, archive_sha256, created_at) VALUES
+		  (1, 1, 'aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', '2026-01-01T00:00:00.000Z'),
+		  (2, 1, 'bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', '2026-02-01T00:00:00.000Z'),
+		  (3, 2, 'cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc', '2026-01-15T00:00:00.000Z');`)
+	require.NoError(t, err)
+
+	// the way files_count used to be counted doesn't even run
+	_, err = db.Exec(`SELECT COUNT(DISTINCT id) OVER (PARTITION BY mod_page_id) FROM mod_files`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DISTINCT is not supported for window functions")
+
+	rows, err := db.Query(namedQuery(t, "ListModsByGameInstall"), 1)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	type page struct {
+		Name          string
+		Files         int64
+		Versions      int64
+		LatestVersion sql.NullInt64
+	}
+	var got []page
+	cols, err := rows.Columns()
+	require.NoError(t, err)
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		require.NoError(t, rows.Scan(ptrs...))
+
+		var p page
+		for i, c := range cols {
+			switch c {
+			case "mod_name":
+				p.Name = vals[i].(string)
+			case "files_count":
+				p.Files = vals[i].(int64)
+			case "versions_count":
+				p.Versions = vals[i].(int64)
+			case "mod_file_version_id":
+				if vals[i] != nil {
+					p.LatestVersion = sql.NullInt64{Int64: vals[i].(int64), Valid: true}
+				}
+			}
+		}
+		got = append(got, p)
+	}
+	require.NoError(t, rows.Err())
+
+	assert.Equal(t, []page{
+		{Name: "SkyUI", Files: 2, Versions: 3, LatestVersion: sql.NullInt64{Int64: 2, Valid: true}},
+		{Name: "Unused"},
+	}, got)
+}
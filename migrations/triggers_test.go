@@ -0,0 +1,70 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupsShareBlobs(t *testing.T) {
+	t.Parallel()
+
+	db := migratedDB(t)
+	_, err := db.Exec(`
+		INSERT INTO game_installs (id, store_id, store_game_id, display_name, install_root)
+		VALUES (1, 'steam', '489830', 'Skyrim', '/games/skyrim');
+		INSERT INTO targets (id, game_install_id, name, root_path, origin)
+		VALUES (1, 1, 'game_dir', '/games/skyrim', 'user_override');
+		INSERT INTO operations (id, game_install_id, op_type, status) VALUES (1, 1, 'apply', 'running');
+		INSERT INTO blobs (sha256, kind, size_bytes) VALUES
+		  ('aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 'override', 1),
+		  ('bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 'archive', 1);`)
+	require.NoError(t, err)
+
+	// a backup can use a blob of any kind with its content
+	for relpath, sha := range map[string]string{
+		"a.ini": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"b.ini": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+	} {
+		_, err = db.Exec(`INSERT INTO backups (game_install_id, target_id, relpath, backup_blob_sha256, size_bytes)
+			VALUES (1, 1, ?, ?, 1)`, relpath, sha)
+		require.NoError(t, err, relpath)
+		_, err = db.Exec(`INSERT INTO operation_changes (operation_id, game_install_id, target_id, relpath, action, backup_blob_sha256)
+			VALUES (1, 1, 1, ?, 'overwrite', ?)`, relpath, sha)
+		require.NoError(t, err, relpath)
+	}
+	_, err = db.Exec(`UPDATE backups SET backup_blob_sha256 = 'bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb'
+		WHERE relpath = 'a.ini'`)
+	require.NoError(t, err)
+
+	// but the blob has to exist
+	missing := "cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	_, err = db.Exec(`INSERT INTO backups (game_install_id, target_id, relpath, backup_blob_sha256, size_bytes)
+		VALUES (1, 1, 'c.ini', ?, 1)`, missing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not reference an existing blob")
+	_, err = db.Exec(`UPDATE backups SET backup_blob_sha256 = ? WHERE relpath = 'a.ini'`, missing)
+	require.Error(t, err)
+	_, err = db.Exec(`INSERT INTO operation_changes (operation_id, game_install_id, target_id, relpath, action, backup_blob_sha256)
+		VALUES (1, 1, 1, 'c.ini', 'overwrite', ?)`, missing)
+	require.Error(t, err)
+}
@@ -0,0 +1,152 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/advisory"
+)
+
+// The severities of advisories: malicious archives are refused, broken ones
+// are warned about.
+const (
+	SeverityMalicious = advisory.SeverityMalicious
+	SeverityBroken    = advisory.SeverityBroken
+)
+
+// AdvisoryFeed is the feed that the advisories were last updated from.
+type AdvisoryFeed struct {
+	Source    string
+	UpdatedAt string
+}
+
+// Advisory is a mod file that is known to be malicious or broken: an
+// archive (by sha256), or the files of a nexus mod (every file of it unless
+// NexusFileID is set), or both.
+type Advisory struct {
+	ID            string
+	Severity      string
+	Summary       string
+	URL           string
+	PublishedAt   string
+	ArchiveSHA256 string
+	// empty and 0 if it isn't about a nexus mod
+	NexusDomain string
+	NexusModID  int64
+	NexusFileID int64
+}
+
+// AdvisoryMatch is an imported mod file version that an advisory is about.
+type AdvisoryMatch struct {
+	AdvisoryID string
+	Severity   string
+	Summary    string
+	URL        string
+
+	GameInstallID int64
+	Game          string
+	ModName       string
+	FileLabel     string
+	VersionID     int64
+	Version       string
+}
+
+// AdvisoryFeed returns the feed that the advisories were last updated from,
+// or nil if they were never updated.
+func (c *Client) AdvisoryFeed(ctx context.Context) (*AdvisoryFeed, error) {
+	s, err := c.q.GetSetting(ctx, advisory.SettingFeed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get advisory feed: %w", err)
+	}
+	return &AdvisoryFeed{Source: s.Value, UpdatedAt: s.UpdatedAt}, nil
+}
+
+// Advisories returns every advisory of the feed.
+func (c *Client) Advisories(ctx context.Context) ([]Advisory, error) {
+	rows, err := c.q.ListAdvisories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list advisories: %w", err)
+	}
+
+	out := make([]Advisory, 0, len(rows))
+	for _, a := range rows {
+		out = append(out, Advisory{
+			ID:            a.AdvisoryID,
+			Severity:      a.Severity,
+			Summary:       a.Summary,
+			URL:           a.Url.String,
+			PublishedAt:   a.PublishedAt.String,
+			ArchiveSHA256: a.ArchiveSha256.String,
+			NexusDomain:   a.NexusGameDomain.String,
+			NexusModID:    a.NexusModID.Int64,
+			NexusFileID:   a.NexusFileID.Int64,
+		})
+	}
+	return out, nil
+}
+
+// AdvisoryMatches returns the imported mod file versions (of every game
+// install) that advisories are about.
+func (c *Client) AdvisoryMatches(ctx context.Context) ([]AdvisoryMatch, error) {
+	rows, err := c.q.ListAdvisoryMatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list advisory matches: %w", err)
+	}
+
+	out := make([]AdvisoryMatch, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, AdvisoryMatch{
+			AdvisoryID:    m.AdvisoryID,
+			Severity:      m.Severity,
+			Summary:       m.Summary,
+			URL:           m.Url.String,
+			GameInstallID: m.GameInstallID,
+			Game:          m.DisplayName,
+			ModName:       m.ModName,
+			FileLabel:     m.FileLabel,
+			VersionID:     m.ModFileVersionID,
+			Version:       m.VersionString.String,
+		})
+	}
+	return out, nil
+}
+
+// UpdateAdvisories replaces the advisories with those of the feed at source
+// (a URL or a file). It returns the number of advisories.
+func (c *Client) UpdateAdvisories(ctx context.Context, source string) (int, error) {
+	data, err := advisory.Fetch(ctx, source, Version)
+	if err != nil {
+		return 0, err
+	}
+	feed, err := advisory.Parse(data)
+	if err != nil {
+		return 0, err
+	}
+	if err := advisory.Replace(ctx, c.db, c.q, source, feed); err != nil {
+		return 0, err
+	}
+	return len(feed.Advisories), nil
+}
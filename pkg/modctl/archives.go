@@ -0,0 +1,486 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/nexus"
+)
+
+// ErrNoNexusMetadata is returned by RepairArchive for the archives of mods
+// that weren't imported from Nexus Mods; they have to be imported again.
+var ErrNoNexusMetadata = errors.New("the mod has no nexus metadata; import the archive again")
+
+// ErrCorrupt is what's wrong with a blob whose hash isn't the one that was
+// recorded (see VerifyBlob).
+var ErrCorrupt = blobstore.ErrCorrupt
+
+// Quarantine describes a corrupted blob that was moved to the quarantine
+// directory (quarantine_dir) and, for archives, what uses it. Importing the
+// archive again (or RepairArchive) puts it back.
+type Quarantine struct {
+	SHA256 string
+	Kind   string
+	// where the file went
+	Path string
+	// what was wrong with it
+	Problem string
+
+	Mods     []ArchiveUser
+	Profiles []ArchiveProfile
+}
+
+// ArchiveUser is a mod file version whose archive was quarantined.
+type ArchiveUser struct {
+	VersionID int64
+	PageID    int64
+	ModName   string
+	FileLabel string
+	Version   string
+	GameName  string
+	// the files page of the mod on Nexus Mods, to download the archive
+	// again (empty if the mod isn't from nexus)
+	NexusFilesURL string
+}
+
+// ArchiveProfile is a profile that has a mod file version whose archive was
+// quarantined.
+type ArchiveProfile struct {
+	Name     string
+	GameName string
+}
+
+func quarantineFrom(r internal.QuarantineReport, problem string) *Quarantine {
+	out := &Quarantine{
+		SHA256:  r.SHA256,
+		Kind:    string(r.Kind),
+		Path:    r.Path,
+		Problem: problem,
+	}
+	for _, m := range r.Mods {
+		u := ArchiveUser{
+			VersionID: m.ID,
+			PageID:    m.ModPageID,
+			ModName:   m.ModName,
+			FileLabel: m.FileLabel,
+			Version:   m.VersionString.String,
+			GameName:  m.GameName,
+		}
+		if m.NexusGameDomain.Valid && m.NexusModID.Valid {
+			ref := nexus.ModRef{GameDomain: m.NexusGameDomain.String, ModID: m.NexusModID.Int64}
+			u.NexusFilesURL = ref.FilesURL()
+		}
+		out.Mods = append(out.Mods, u)
+	}
+	for _, p := range r.Profiles {
+		out.Profiles = append(out.Profiles, ArchiveProfile{Name: p.Name, GameName: p.GameName})
+	}
+	return out
+}
+
+// checkBlob hashes a blob again. It returns what's wrong with it (nil if
+// nothing) and, if it's corrupted, where it was quarantined; err is only set
+// if the check itself failed. A blob in the shared archive store can't be
+// quarantined, that's only added to the problem.
+func (c *Client) checkBlob(ctx context.Context, bs blobstore.Store, kind blobstore.Kind, sha string, size int64) (problem error, q *Quarantine, err error) {
+	problem = bs.Verify(ctx, kind, sha, size)
+	if errors.Is(problem, context.Canceled) {
+		return nil, nil, problem
+	}
+
+	if errors.Is(problem, blobstore.ErrCorrupt) {
+		r, err := internal.QuarantineBlob(ctx, c.q, bs, kind, sha)
+		switch {
+		case errors.Is(err, blobstore.ErrShared):
+			// only reported: whoever manages the shared store has to replace it
+			return fmt.Errorf("%w (%v)", problem, err), nil, nil
+		case err != nil:
+			return nil, nil, fmt.Errorf("quarantine blob kind=%s sha=%s: %w", kind, sha, err)
+		}
+		return problem, quarantineFrom(r, problem.Error()), nil
+	}
+
+	if problem == nil {
+		now := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+		if err := c.q.TouchBlobVerifiedAt(ctx, dbq.TouchBlobVerifiedAtParams{
+			VerifiedAt: sql.NullString{String: now, Valid: true},
+			Sha256:     sha,
+		}); err != nil {
+			return nil, nil, fmt.Errorf("update verified_at sha=%s: %w", sha, err)
+		}
+	}
+	return problem, nil, nil
+}
+
+// ArchiveVersion is a mod file version and its archive, see ModArchives.
+type ArchiveVersion struct {
+	ID        int64
+	FileID    int64
+	FileLabel string
+	Version   string
+
+	SHA256 string
+	// false if the archive isn't in the blobs table
+	Recorded bool
+	Size     int64
+	// when the archive was quarantined (empty if it wasn't)
+	QuarantinedAt string
+}
+
+// ModArchives returns the versions of the files of a mod with their
+// archives.
+func (c *Client) ModArchives(ctx context.Context, p ModPage) ([]ArchiveVersion, error) {
+	rows, err := c.q.ListArchivesForModPage(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list versions: %w", err)
+	}
+
+	out := make([]ArchiveVersion, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, ArchiveVersion{
+			ID:            r.ID,
+			FileID:        r.ModFileID,
+			FileLabel:     r.FileLabel,
+			Version:       r.VersionString.String,
+			SHA256:        r.ArchiveSha256,
+			Recorded:      r.SizeBytes.Valid,
+			Size:          r.SizeBytes.Int64,
+			QuarantinedAt: r.CorruptedAt.String,
+		})
+	}
+	return out, nil
+}
+
+// VerifyArchive hashes the archive of a mod file version again and compares
+// it with what was recorded when it was imported. It returns what's wrong
+// with the archive (nil if nothing; os.ErrNotExist if it's missing) and, if
+// it turned out to be corrupted, where it was quarantined. Progress, if set,
+// is called while the archive is read.
+//
+// The caller holds the state lock (see LockState): a corrupted archive is
+// quarantined.
+func (c *Client) VerifyArchive(ctx context.Context, v ArchiveVersion, progress func(done, total int64)) (problem error, q *Quarantine, err error) {
+	switch {
+	case !v.Recorded:
+		return errors.New("archive is not recorded in the blob store"), nil, nil
+	case v.QuarantinedAt != "":
+		return fmt.Errorf("%w: quarantined on %s", blobstore.ErrCorrupt, v.QuarantinedAt), nil, nil
+	}
+
+	bs := internal.BlobStoreFromConfig()
+	bs.Progress = progress
+	return c.checkBlob(ctx, bs, blobstore.KindArchive, v.SHA256, v.Size)
+}
+
+// BrokenArchive is an archive of a mod that is missing from the blob store or
+// was quarantined, see BrokenArchives.
+type BrokenArchive struct {
+	SHA256       string
+	Size         int64
+	OriginalName string
+	Quarantined  bool
+
+	PageID    int64
+	ModName   string
+	FileLabel string
+	// the nexus page and file of the mod (empty and 0 if they aren't known)
+	NexusDomain string
+	NexusModID  int64
+	NexusFileID int64
+}
+
+// BrokenArchives returns the archives of the mods of a game install (or of
+// one mod, if p isn't nil) that are missing or were quarantined. Versions
+// can share an archive, each is only returned once.
+func (c *Client) BrokenArchives(ctx context.Context, gi Game, p *ModPage) ([]BrokenArchive, error) {
+	rows, err := c.q.ListArchivesForGame(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list archives: %w", err)
+	}
+
+	bs := internal.BlobStoreFromConfig()
+
+	seen := map[string]bool{}
+	var broken []BrokenArchive
+	for _, r := range rows {
+		if p != nil && r.ModPageID != p.ID {
+			continue
+		}
+		if seen[r.ArchiveSha256] {
+			continue
+		}
+		seen[r.ArchiveSha256] = true
+
+		if !r.CorruptedAt.Valid {
+			path, err := bs.Locate(blobstore.KindArchive, r.ArchiveSha256)
+			if err != nil {
+				return nil, err
+			}
+			_, err = os.Stat(path)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("stat archive sha=%s: %w", r.ArchiveSha256, err)
+			}
+		}
+		broken = append(broken, BrokenArchive{
+			SHA256:       r.ArchiveSha256,
+			Size:         r.SizeBytes,
+			OriginalName: r.OriginalName.String,
+			Quarantined:  r.CorruptedAt.Valid,
+			PageID:       r.ModPageID,
+			ModName:      r.ModName,
+			FileLabel:    r.FileLabel,
+			NexusDomain:  r.NexusGameDomain.String,
+			NexusModID:   r.NexusModID.Int64,
+			NexusFileID:  r.NexusFileID.Int64,
+		})
+	}
+	return broken, nil
+}
+
+// RepairArchive downloads a broken archive from Nexus Mods again and puts it
+// back in the blob store, which also lifts its quarantine. A download only
+// replaces the archive if its sha256 is exactly the one that was imported.
+// Progress, if set, is called while the archive is downloaded.
+func (c *Client) RepairArchive(ctx context.Context, a BrokenArchive, progress func(done, total int64)) error {
+	if a.NexusDomain == "" || a.NexusModID == 0 {
+		return ErrNoNexusMetadata
+	}
+
+	client, err := c.nexusClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	bs := internal.BlobStoreFromConfig()
+	path, err := internal.FetchNexusArchive(ctx, client, bs.TmpDir, internal.NexusArchive{
+		SHA256:       a.SHA256,
+		SizeBytes:    a.Size,
+		OriginalName: a.OriginalName,
+		GameDomain:   a.NexusDomain,
+		ModID:        a.NexusModID,
+		FileID:       a.NexusFileID,
+	}, progress)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	res, err := bs.IngestFile(ctx, blobstore.KindArchive, path)
+	if err != nil {
+		return fmt.Errorf("ingest archive: %w", err)
+	}
+
+	var name *string
+	if a.OriginalName != "" {
+		name = &a.OriginalName
+	}
+	return blobstore.EnsureBlobRecorded(ctx, c.q, res.SHA256Hex, string(blobstore.KindArchive), res.SizeBytes, name)
+}
+
+// PruneOptions are the settings of PlanPrune.
+type PruneOptions struct {
+	// how many versions of every mod file are kept (at least 1)
+	KeepLatest int64
+	// keep the versions that are in any profile instead of removing them
+	// from their profiles
+	UnreferencedOnly bool
+	// only remove the versions whose nexus file was replaced by a newer
+	// upload (as recorded by the last update check)
+	SupersededOnly bool
+}
+
+// PruneVersion is an old version of a mod file and whether it's kept.
+type PruneVersion struct {
+	ID         int64
+	FileID     int64
+	ModName    string
+	FileLabel  string
+	Version    string
+	ImportedAt string
+	SHA256     string
+	Size       int64
+	// the profiles that have it (and that it's removed from)
+	Profiles []string
+
+	// why it's kept (e.g., "installed"), empty if it's removed
+	Kept string
+}
+
+// PrunePlan is what Prune removes, see PlanPrune.
+type PrunePlan struct {
+	// the old versions, by mod file
+	Versions []PruneVersion
+
+	// the versions that are removed and the archives that nothing refers
+	// to anymore once they are, with their size
+	Removed  int
+	Orphaned int
+	Freed    int64
+
+	deleteVersions []int64
+	deleteArchives []string
+}
+
+// Kept returns the number of old versions that are kept.
+func (p PrunePlan) Kept() int {
+	return len(p.Versions) - p.Removed
+}
+
+// PlanPrune decides which of the old versions of the mod files of a game
+// install (all but the KeepLatest newest ones, by import time) are removed.
+// Versions whose files are installed or that are in a locked profile are
+// always kept.
+func (c *Client) PlanPrune(ctx context.Context, gi Game, opts PruneOptions) (PrunePlan, error) {
+	if opts.KeepLatest < 1 {
+		return PrunePlan{}, errors.New("keep at least 1 version")
+	}
+
+	candidates, err := c.q.ListPruneCandidates(ctx, dbq.ListPruneCandidatesParams{
+		GameInstallID: gi.ID,
+		KeepLatest:    opts.KeepLatest,
+	})
+	if err != nil {
+		return PrunePlan{}, fmt.Errorf("list prune candidates: %w", err)
+	}
+
+	pruneCandidates := make([]internal.PruneCandidate, 0, len(candidates))
+	for _, cand := range candidates {
+		rows, err := c.q.ListProfileNamesForVersion(ctx, cand.ID)
+		if err != nil {
+			return PrunePlan{}, fmt.Errorf("list profiles for version %d: %w", cand.ID, err)
+		}
+		pc := internal.PruneCandidate{ListPruneCandidatesRow: cand}
+		for _, r := range rows {
+			pc.Profiles = append(pc.Profiles, r.Name)
+			if r.Locked != 0 {
+				pc.Locked = append(pc.Locked, r.Name)
+			}
+		}
+		pruneCandidates = append(pruneCandidates, pc)
+	}
+
+	p := internal.PlanPrune(pruneCandidates, internal.PruneOptions{
+		UnreferencedOnly: opts.UnreferencedOnly,
+		SupersededOnly:   opts.SupersededOnly,
+	})
+
+	plan := PrunePlan{
+		Removed:        p.Removed,
+		Orphaned:       p.Orphaned,
+		Freed:          p.Freed,
+		deleteVersions: p.DeleteVersions,
+		deleteArchives: p.DeleteArchives,
+	}
+	for _, v := range p.Versions {
+		plan.Versions = append(plan.Versions, PruneVersion{
+			ID:         v.ID,
+			FileID:     v.ModFileID,
+			ModName:    v.ModName,
+			FileLabel:  v.FileLabel,
+			Version:    v.VersionString.String,
+			ImportedAt: v.CreatedAt,
+			SHA256:     v.ArchiveSha256,
+			Size:       v.SizeBytes,
+			Profiles:   v.Profiles,
+			Kept:       v.Kept,
+		})
+	}
+	return plan, nil
+}
+
+// PruneResult is what Prune removed.
+type PruneResult struct {
+	Versions int
+	Archives int
+	Freed    int64
+
+	// archives that were deleted from the database but whose files couldn't
+	// be removed (they're just unreferenced)
+	Warnings []string
+}
+
+// Prune removes the versions of a plan (from their profiles as well) and the
+// archives that nothing refers to anymore.
+func (c *Client) Prune(ctx context.Context, plan PrunePlan) (PruneResult, error) {
+	res := PruneResult{Freed: plan.Freed}
+	if len(plan.deleteVersions) == 0 {
+		return res, nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.q.WithTx(tx)
+
+	for _, id := range plan.deleteVersions {
+		if err := qtx.DeleteProfileItemsForVersion(ctx, id); err != nil {
+			return res, fmt.Errorf("remove version %d from profiles: %w", id, err)
+		}
+		if err := qtx.DeleteModFileVersion(ctx, id); err != nil {
+			return res, fmt.Errorf("delete version %d: %w", id, err)
+		}
+	}
+
+	// double check the references now that the versions are gone
+	var deleteBlobs []string
+	for _, sha := range plan.deleteArchives {
+		refs, err := qtx.CountBlobReferences(ctx, sha)
+		if err != nil {
+			return res, fmt.Errorf("count blob references: %w", err)
+		}
+		if refs > 0 {
+			continue
+		}
+		if err := qtx.DeleteBlob(ctx, sha); err != nil {
+			return res, fmt.Errorf("delete blob %s: %w", sha, err)
+		}
+		deleteBlobs = append(deleteBlobs, sha)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, fmt.Errorf("commit: %w", err)
+	}
+	internal.AutoOptimize(ctx, c.db, int64(len(plan.deleteVersions)))
+	res.Versions = len(plan.deleteVersions)
+	res.Archives = len(deleteBlobs)
+
+	// filesystem last: if this fails the files are just unreferenced
+	bs := internal.BlobStoreFromConfig()
+	for _, sha := range deleteBlobs {
+		if err := bs.Remove(blobstore.KindArchive, sha); err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("archive %s: %v", sha[:12], err))
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,149 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/blobstore"
+)
+
+// Attachment is a supplementary file (e.g., a patch, a customized INI, or
+// some notes) that is kept with a mod. Attachments are never deployed.
+type Attachment struct {
+	Label string
+	// the blob store that the file is in: archives are kept with the mod
+	// archives and anything else with the overrides
+	Kind         string
+	SHA256       string
+	Size         int64
+	OriginalName string
+	Notes        string
+	AttachedAt   string
+}
+
+func attachmentFromRow(a dbq.GetModAttachmentByLabelRow) Attachment {
+	return Attachment{
+		Label:        a.Label,
+		Kind:         a.Kind,
+		SHA256:       a.BlobSha256,
+		Size:         a.SizeBytes,
+		OriginalName: a.OriginalName,
+		Notes:        a.Notes.String,
+		AttachedAt:   a.CreatedAt,
+	}
+}
+
+// Attachments returns the attachments of a mod.
+func (c *Client) Attachments(ctx context.Context, p ModPage) ([]Attachment, error) {
+	rows, err := c.q.ListModAttachmentsForPage(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+
+	out := make([]Attachment, 0, len(rows))
+	for _, a := range rows {
+		out = append(out, attachmentFromRow(dbq.GetModAttachmentByLabelRow(a)))
+	}
+	return out, nil
+}
+
+func (c *Client) attachmentRow(ctx context.Context, p ModPage, label string) (dbq.GetModAttachmentByLabelRow, error) {
+	a, err := c.q.GetModAttachmentByLabel(ctx, dbq.GetModAttachmentByLabelParams{
+		ModPageID: p.ID,
+		Label:     label,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, fmt.Errorf("%s has no attachment labeled %q", p.Name, label)
+	}
+	if err != nil {
+		return a, fmt.Errorf("get attachment: %w", err)
+	}
+	return a, nil
+}
+
+// Attachment returns the attachment of a mod with the given label.
+func (c *Client) Attachment(ctx context.Context, p ModPage, label string) (Attachment, error) {
+	a, err := c.attachmentRow(ctx, p, label)
+	if err != nil {
+		return Attachment{}, err
+	}
+	return attachmentFromRow(a), nil
+}
+
+// Attach copies a file into the blob store and attaches it to a mod under
+// label, which must be unique within the mod. It returns the sha256 of the
+// file.
+func (c *Client) Attach(ctx context.Context, p ModPage, label, path, notes string) (string, error) {
+	ctxT, cancel := context.WithTimeout(ctx, 60*time.Second)
+	isArchive := internal.CheckArchive(ctxT, path) == nil
+	cancel()
+
+	return internal.AttachFile(ctx, c.db, c.q, internal.BlobStoreFromConfig(),
+		p.ID, label, path, notes, isArchive)
+}
+
+// Detach removes an attachment from a mod, and its file from the blob store
+// unless something else still uses it. Failing to remove the file doesn't
+// fail the detach; it's returned as a warning instead.
+func (c *Client) Detach(ctx context.Context, p ModPage, label string) (Attachment, []string, error) {
+	a, err := c.attachmentRow(ctx, p, label)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+
+	var warnings []string
+	removed, err := internal.DetachFile(ctx, c.db, c.q, internal.BlobStoreFromConfig(), a)
+	if err != nil {
+		if !removed {
+			return Attachment{}, nil, err
+		}
+		warnings = append(warnings, fmt.Sprintf("%s %s: %v", a.Kind, a.BlobSha256[:12], err))
+	}
+
+	return attachmentFromRow(a), warnings, nil
+}
+
+// WriteAttachment writes the file of an attachment to w.
+func (c *Client) WriteAttachment(ctx context.Context, a Attachment, w io.Writer) error {
+	path, err := internal.BlobStoreFromConfig().Locate(blobstore.Kind(a.Kind), a.SHA256)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open attachment: %w", err)
+	}
+	defer src.Close()
+
+	buf := make([]byte, 1024*1024)
+	if _, err := blobstore.CopyWithContext(ctx, w, src, buf); err != nil {
+		return fmt.Errorf("write attachment: %w", err)
+	}
+	return nil
+}
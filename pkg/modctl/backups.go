@@ -0,0 +1,87 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+)
+
+// BackupExport is the result of ExportBackups.
+type BackupExport struct {
+	// the number of backed-up files and their size
+	Files int
+	Size  int64
+}
+
+// ExportBackups writes every backup of a game install (the original game
+// files that deployed mods replaced) as <target>/<relpath> into an archive
+// at out. The archive_compression config option picks the compression unless
+// the extension of out names another one. Nothing is written if the install
+// doesn't have any backups.
+func (c *Client) ExportBackups(ctx context.Context, gi Game, out string) (BackupExport, error) {
+	builder, err := internal.ArchiveBuilderFromConfig()
+	if err != nil {
+		return BackupExport{}, err
+	}
+	if comp, ok := archive.CompressionForName(out); ok && comp.Format != builder.Compression.Format {
+		builder.Compression = comp
+	}
+
+	backups, err := c.q.ListBackupsForGame(ctx, gi.ID)
+	if err != nil {
+		return BackupExport{}, fmt.Errorf("list backups: %w", err)
+	}
+	if len(backups) == 0 {
+		return BackupExport{}, nil
+	}
+
+	// a backup can share the blob of an override or archive with the same
+	// content
+	bs := internal.BlobStoreFromConfig()
+
+	entries := make([]archive.Entry, 0, len(backups))
+	var res BackupExport
+	for _, b := range backups {
+		p, err := bs.Locate(blobstore.Kind(b.BlobKind), b.BackupBlobSha256)
+		if err != nil {
+			return BackupExport{}, err
+		}
+		if _, err := os.Stat(p); err != nil {
+			return BackupExport{}, fmt.Errorf("backup of %s/%s: %w", b.TargetName, b.Relpath, err)
+		}
+		entries = append(entries, archive.Entry{
+			Name: path.Join(b.TargetName, b.Relpath),
+			Path: p,
+		})
+		res.Size += b.SizeBytes
+	}
+
+	if err := builder.Build(ctx, out, entries); err != nil {
+		return BackupExport{}, fmt.Errorf("write archive: %w", err)
+	}
+	res.Files = len(entries)
+	return res, nil
+}
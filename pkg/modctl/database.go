@@ -0,0 +1,137 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/export"
+	"github.com/spf13/viper"
+)
+
+// DBInit is what InitDB did.
+type DBInit struct {
+	// the database didn't exist before
+	Created bool
+	// how many migrations ran, and the schema version after them
+	Migrations int
+	Version    int64
+	// the built-in stores that were missing
+	StoresAdded []string
+}
+
+// InitDB creates the configured database (or upgrades an existing one) and
+// adds the built-in stores that it's missing. Open does the same except for
+// creating the database, which is left to `modctl init`.
+func InitDB(ctx context.Context) (DBInit, error) {
+	var res DBInit
+
+	path := viper.GetString("database")
+	_, statErr := os.Stat(path)
+	res.Created = statErr != nil
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o0755); err != nil {
+		return res, fmt.Errorf("error creating database directory: %w", err)
+	}
+
+	db, err := internal.SetupDB()
+	if err != nil {
+		return res, fmt.Errorf("error opening database: %w", err)
+	}
+	defer db.Close()
+
+	p, err := internal.GooseProvider(db)
+	if err != nil {
+		return res, fmt.Errorf("error setting up goose provider: %w", err)
+	}
+
+	results, err := p.Up(ctx)
+	if err != nil {
+		return res, fmt.Errorf("error migrating database: %w", err)
+	}
+	res.Migrations = len(results)
+
+	if res.Version, err = p.GetDBVersion(ctx); err != nil {
+		return res, fmt.Errorf("error reading schema version: %w", err)
+	}
+
+	res.StoresAdded, err = internal.SeedStores(ctx, dbq.New(db))
+	return res, err
+}
+
+// ExportTables are the tables that ExportDB can export, in the order that
+// it exports them.
+var ExportTables = export.Tables
+
+// ExportOptions are the optional settings of ExportDB.
+type ExportOptions struct {
+	// one JSON document instead of JSON lines
+	JSON bool
+	// the tables to export (see ExportTables); all of them if empty
+	Tables []string
+}
+
+// ExportDB writes the rows of the database to w, as JSON lines (a header
+// line with the schema version, then one line per row) or, with JSON, as a
+// single JSON document. The tables are read in a single transaction, so the
+// export is consistent.
+func (c *Client) ExportDB(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	version, _, err := c.Migrations(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading schema version: %w", err)
+	}
+
+	if err := export.Write(ctx, c.db, w, export.Options{
+		JSON:          opts.JSON,
+		Tables:        opts.Tables,
+		SchemaVersion: version,
+	}); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
+
+// OptimizeReport is the result of OptimizeDB.
+type OptimizeReport struct {
+	// size of the database and its WAL
+	SizeBefore int64
+	SizeAfter  int64
+
+	// what the integrity check found; empty if the database is fine
+	Problems []string
+
+	// the WAL couldn't be truncated because another process was using the
+	// database
+	Busy bool
+}
+
+// OptimizeDB checks the integrity of the database and then refreshes the
+// query planner statistics, rebuilds the database to reclaim free space (if
+// vacuum is set), and truncates the WAL. A database that fails the
+// integrity check is left alone.
+func (c *Client) OptimizeDB(ctx context.Context, vacuum bool) (OptimizeReport, error) {
+	rep, err := internal.OptimizeDB(ctx, c.db, viper.GetString("database"), vacuum)
+	return OptimizeReport(rep), err
+}
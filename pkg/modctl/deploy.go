@@ -0,0 +1,425 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// Plan is what applying a profile deploys, see Client.Plan.
+type Plan struct {
+	// the game integration that decides where the files of the mods go
+	// (e.g., "bethesda"; "generic" if the game has none)
+	Integration string
+
+	// the enabled mods, highest priority first
+	Mods  []PlanMod
+	Files []PlanFile
+	// conflicts that the game integration (or the Steam Workshop items of
+	// the game) found, e.g., scripts that have to be merged
+	Notes []PlanNote
+	// what planning warned about (e.g., unsafe paths that are skipped)
+	Warnings []string
+
+	p *plan.Plan
+}
+
+// PlanMod is an enabled mod of a plan.
+type PlanMod struct {
+	PlanSource
+	// how the game integration classified its archive (or "remap")
+	Layout string
+	// the files that it provides, wins, and hides
+	Files  int
+	Won    int
+	Hidden int
+}
+
+// PlanSource is a mod (file version) of a plan.
+type PlanSource struct {
+	VersionID int64
+	Priority  int64
+	ModName   string
+	FileLabel string
+	// the path of the file in its archive (empty for the mods of a plan)
+	Member string
+}
+
+func planSource(s plan.Source) PlanSource {
+	ps := planItem(s.Item)
+	ps.Member = s.Member
+	return ps
+}
+
+func planItem(it *plan.Item) PlanSource {
+	return PlanSource{
+		VersionID: it.VersionID,
+		Priority:  it.Priority,
+		ModName:   it.ModName,
+		FileLabel: it.FileLabel,
+	}
+}
+
+// PlanFile is a file that a plan deploys.
+type PlanFile struct {
+	Target  string
+	RelPath string
+	// the mod (file) that deploys it
+	Winner PlanSource
+	// the lower priority mods that provide the same path (highest first)
+	Shadowed []PlanSource
+}
+
+// PlanNote is a problem that the game integration found, e.g., files that
+// conflict even though they are deployed to different paths.
+type PlanNote struct {
+	// e.g., "script_merge"
+	Kind string
+	// what it's about (e.g., the conflicting script)
+	Key     string
+	Message string
+	Sources []PlanSource
+	// the conflict was resolved: the merged script is an override of the
+	// profile (see `modctl witcher3 merge`)
+	Merged bool
+}
+
+func planFrom(p *plan.Plan, integration string) *Plan {
+	pl := &Plan{Integration: integration, Warnings: p.Warnings, p: p}
+	for _, it := range p.Items {
+		pl.Mods = append(pl.Mods, PlanMod{
+			PlanSource: planItem(it.Item),
+			Layout:     it.Layout,
+			Files:      it.Files,
+			Won:        it.Won,
+			Hidden:     it.Hidden,
+		})
+	}
+	for _, f := range p.Files {
+		pf := PlanFile{
+			Target:  f.Target,
+			RelPath: f.RelPath,
+			Winner:  planSource(f.Winner),
+		}
+		for _, s := range f.Shadowed {
+			pf.Shadowed = append(pf.Shadowed, planSource(s))
+		}
+		pl.Files = append(pl.Files, pf)
+	}
+	for _, n := range p.Notes {
+		pn := PlanNote{Kind: n.Kind, Key: n.Key, Message: n.Message}
+		for _, s := range n.Sources {
+			pn.Sources = append(pn.Sources, planSource(s))
+		}
+		pl.Notes = append(pl.Notes, pn)
+	}
+	return pl
+}
+
+// Conflicts returns the files that more than one mod provides.
+func (p *Plan) Conflicts() []PlanFile {
+	var out []PlanFile
+	for _, f := range p.Files {
+		if len(f.Shadowed) > 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ConflictMatrix is how many files the mods that conflict win over each
+// other: Won[i][j] is the number of files that Mods[i] provides and wins over
+// Mods[j], which provides them too.
+type ConflictMatrix struct {
+	// highest priority first
+	Mods []PlanSource
+	Won  [][]int
+}
+
+// Lost returns the number of files that Mods[i] provides but loses to other
+// mods.
+func (m ConflictMatrix) Lost(i int) int {
+	n := 0
+	for j := range m.Mods {
+		n += m.Won[j][i]
+	}
+	return n
+}
+
+// ConflictMatrix returns which mods win how many files over which other
+// mods. Only the mods that take part in a conflict are in it.
+func (p *Plan) ConflictMatrix() ConflictMatrix {
+	m := p.p.ConflictMatrix()
+	out := ConflictMatrix{Won: m.Won}
+	for _, it := range m.Items {
+		out.Mods = append(out.Mods, planItem(it))
+	}
+	return out
+}
+
+// ChangedPath is a path that an apply or unapply changed.
+type ChangedPath struct {
+	Target  string
+	RelPath string
+}
+
+func (c ChangedPath) String() string {
+	return c.Target + "/" + c.RelPath
+}
+
+func changedPaths(paths []internal.ChangedPath) []ChangedPath {
+	if paths == nil {
+		return nil
+	}
+	out := make([]ChangedPath, len(paths))
+	for i, p := range paths {
+		out[i] = ChangedPath{Target: p.Target, RelPath: p.RelPath}
+	}
+	return out
+}
+
+// DeployResult summarizes an apply or unapply.
+type DeployResult struct {
+	// the operation that recorded it (0 if nothing was changed), see
+	// `modctl ops show`
+	OperationID int64
+	Written     int
+	Overwritten int
+	Removed     int
+	Restored    int
+	BackedUp    int
+	Unchanged   int
+	// vanilla files that were replaced without a backup
+	Vanilla int
+	// other files that were replaced without a backup because the backup
+	// policy of their target said so
+	NotBackedUp int
+
+	// files of the plan that aren't deployed: hidden ones and the ones that
+	// lost a conflict to a higher priority mod
+	Hidden   int
+	Shadowed int
+
+	Warnings []string
+	// what planning warned about (e.g., unsafe paths that are skipped)
+	PlanWarnings []string
+
+	Changed []ChangedPath
+	// vanilla files that were removed without a backup to restore: verifying
+	// the game files in Steam brings them back
+	SteamRestore []ChangedPath
+	// the report of an apply, if it was written
+	Report string
+
+	// the plugins of the load order that LOOT sorted after the apply (see
+	// the loot_after_apply config option), 0 if it didn't
+	PluginsSorted int
+	// whether REDmod's deploy step ran after the apply, or REDmod content
+	// changed but it didn't (it was turned off or failed) and still has to
+	RedmodDeployed bool
+	RedmodPending  bool
+}
+
+func deployResultFrom(r internal.DeployResult) DeployResult {
+	return DeployResult{
+		OperationID:  r.OperationID,
+		Written:      r.Written,
+		Overwritten:  r.Overwritten,
+		Removed:      r.Removed,
+		Restored:     r.Restored,
+		BackedUp:     r.BackedUp,
+		Unchanged:    r.Unchanged,
+		Vanilla:      r.Vanilla,
+		NotBackedUp:  r.NotBackedUp,
+		Hidden:       r.Hidden,
+		Shadowed:     r.Shadowed,
+		Warnings:     r.Warnings,
+		PlanWarnings: r.PlanWarnings,
+		Changed:      changedPaths(r.Changed),
+		SteamRestore: changedPaths(r.SteamRestore),
+		Report:       r.Report,
+	}
+}
+
+// ApplyEstimate is what an apply is about to do, see ApplyOptions.Confirm.
+type ApplyEstimate struct {
+	// the mods (versions) that the profile deploys, and the ones whose
+	// archives have to be extracted because their files aren't deployed
+	// yet (or changed)
+	Mods          int
+	ModsToExtract int
+	// the files that are written
+	Files int
+	// the size of the archives that are extracted and of what's in them
+	ArchiveBytes  int64
+	UnpackedBytes int64
+	// the size of the archives that are fetched from the blob mirrors
+	// first because they're missing from the blob store
+	DownloadBytes int64
+	// estimated from how fast the newest successful applies extracted and
+	// deployed their files; 0 if there aren't any to go by
+	Duration time.Duration
+}
+
+// Long reports whether the apply is expected to take long enough to ask
+// before starting it (which is when Confirm is called).
+func (e ApplyEstimate) Long() bool {
+	return internal.ApplyEstimate{
+		Duration:      e.Duration,
+		UnpackedBytes: e.UnpackedBytes,
+		DownloadBytes: e.DownloadBytes,
+	}.Long()
+}
+
+func estimateFrom(e internal.ApplyEstimate) ApplyEstimate {
+	return ApplyEstimate{
+		Mods:          e.Mods,
+		ModsToExtract: e.ModsToExtract,
+		Files:         e.Files,
+		ArchiveBytes:  e.ArchiveBytes,
+		UnpackedBytes: e.UnpackedBytes,
+		DownloadBytes: e.DownloadBytes,
+		Duration:      e.Duration,
+	}
+}
+
+// NukeResult summarizes a Nuke.
+type NukeResult struct {
+	// removing the deployed files and restoring their backups
+	Unapply DeployResult
+	// backups of files that weren't deployed anymore (e.g., after an
+	// interrupted apply) that were put back
+	Restored []ChangedPath
+	// whether there was a baseline to compare the game files to
+	Verified bool
+	// files that changed since the baseline was recorded
+	Changed []ChangedPath
+	// files of the baseline that are gone
+	Missing []ChangedPath
+	// files that were created after the baseline was recorded (e.g., saves
+	// or configs written by the game), they're left alone
+	Added int
+	// the records that were removed
+	Profiles int64
+	Mods     int
+}
+
+func nukeResultFrom(r internal.NukeResult) NukeResult {
+	return NukeResult{
+		Unapply:  deployResultFrom(r.Unapply),
+		Restored: changedPaths(r.Restored),
+		Verified: r.Verified,
+		Changed:  changedPaths(r.Changed),
+		Missing:  changedPaths(r.Missing),
+		Added:    r.Added,
+		Profiles: r.Profiles,
+		Mods:     r.Mods,
+	}
+}
+
+// DriftError is returned when files that modctl deployed were changed by
+// something else, see ApplyOptions.Force.
+type DriftError struct {
+	Paths []string
+	// the changed files that are vanilla game files again (e.g., because
+	// Steam verified or updated the game)
+	Vanilla []string
+
+	err error
+}
+
+func (e *DriftError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return (&internal.DriftError{Paths: e.Paths, Vanilla: e.Vanilla}).Error()
+}
+
+func (e *DriftError) Unwrap() error {
+	return e.err
+}
+
+// SteamBusyError is returned when Steam is downloading or updating the game,
+// see ApplyOptions.WaitForSteam.
+type SteamBusyError struct {
+	Game   string
+	Reason string
+
+	err error
+}
+
+func (e *SteamBusyError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return (&internal.SteamBusyError{Game: e.Game, Reason: e.Reason}).Error()
+}
+
+func (e *SteamBusyError) Unwrap() error {
+	return e.err
+}
+
+// BackupConflictError is returned by Nuke when files that have a backup
+// were changed since the backup was made: putting the backups back would
+// lose the changes (see ApplyOptions.Force).
+type BackupConflictError struct {
+	Paths []ChangedPath
+
+	err error
+}
+
+func (e *BackupConflictError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	paths := make([]internal.ChangedPath, len(e.Paths))
+	for i, p := range e.Paths {
+		paths[i] = internal.ChangedPath{Target: p.Target, RelPath: p.RelPath}
+	}
+	return (&internal.BackupConflictError{Paths: paths}).Error()
+}
+
+func (e *BackupConflictError) Unwrap() error {
+	return e.err
+}
+
+// apiError returns the errors of the deployer that callers act on as the
+// error types of this package; the message stays the same.
+func apiError(err error) error {
+	var drift *internal.DriftError
+	if errors.As(err, &drift) {
+		return &DriftError{Paths: drift.Paths, Vanilla: drift.Vanilla, err: err}
+	}
+
+	var busy *internal.SteamBusyError
+	if errors.As(err, &busy) {
+		return &SteamBusyError{Game: busy.Game, Reason: busy.Reason, err: err}
+	}
+
+	var conflict *internal.BackupConflictError
+	if errors.As(err, &conflict) {
+		return &BackupConflictError{Paths: changedPaths(conflict.Paths), err: err}
+	}
+
+	return err
+}
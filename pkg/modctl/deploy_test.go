@@ -0,0 +1,55 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	drift := fmt.Errorf("apply: %w", &internal.DriftError{Paths: []string{"game_dir/a.esp"}})
+	err := apiError(drift)
+	var derr *DriftError
+	if assert.True(t, errors.As(err, &derr)) {
+		assert.Equal(t, []string{"game_dir/a.esp"}, derr.Paths)
+	}
+	assert.Equal(t, drift.Error(), err.Error())
+	assert.ErrorIs(t, err, internal.ErrConflict)
+
+	err = apiError(&internal.SteamBusyError{Game: "Cyberpunk 2077", Reason: "updating"})
+	var busy *SteamBusyError
+	if assert.True(t, errors.As(err, &busy)) {
+		assert.Equal(t, "updating", busy.Reason)
+	}
+
+	err = apiError(&internal.BackupConflictError{Paths: []internal.ChangedPath{{Target: "game_dir", RelPath: "a.esp"}}})
+	var conflict *BackupConflictError
+	if assert.True(t, errors.As(err, &conflict)) {
+		assert.Equal(t, []ChangedPath{{Target: "game_dir", RelPath: "a.esp"}}, conflict.Paths)
+	}
+
+	other := errors.New("disk full")
+	assert.Same(t, other, apiError(other))
+	assert.NoError(t, apiError(nil))
+}
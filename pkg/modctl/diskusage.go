@@ -0,0 +1,146 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+)
+
+// BlobUsage is the disk space that the blobs of a kind (see BlobKinds) use,
+// and how much of it nothing references anymore.
+type BlobUsage struct {
+	Kind              string
+	Blobs             int64
+	Bytes             int64
+	UnreferencedBlobs int64
+	UnreferencedBytes int64
+}
+
+// DedupSavings is the disk space that deduplication saves: the blobs that
+// are referenced more than once are only stored once.
+type DedupSavings struct {
+	SharedBlobs int64
+	SavedBytes  int64
+}
+
+// GameUsage is the disk space that the blobs of a game install use. A blob
+// that is shared with other game installs counts for each of them.
+type GameUsage struct {
+	ID            int64
+	StoreID       string
+	StoreGameID   string
+	InstanceID    string
+	DisplayName   string
+	Mods          int64
+	ArchiveBytes  int64
+	BackupBytes   int64
+	OverrideBytes int64
+}
+
+// Selector returns the full selector of the game install.
+func (g GameUsage) Selector() string {
+	return internal.FullSelector(g.StoreID, g.StoreGameID, g.InstanceID)
+}
+
+// ModUsage is the disk space that the archives of a mod use. An archive that
+// is shared with other mods counts for each of them.
+type ModUsage struct {
+	PageID        int64
+	GameInstallID int64
+	Name          string
+	Game          string
+	Versions      int64
+	Bytes         int64
+}
+
+// BlobUsage returns the disk space that each kind of blobs uses.
+func (c *Client) BlobUsage(ctx context.Context) ([]BlobUsage, error) {
+	rows, err := c.q.DiskUsageByKind(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("disk usage by kind: %w", err)
+	}
+
+	out := make([]BlobUsage, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, BlobUsage(r))
+	}
+	return out, nil
+}
+
+// DedupSavings returns the disk space that deduplication saves.
+func (c *Client) DedupSavings(ctx context.Context) (DedupSavings, error) {
+	r, err := c.q.DiskUsageDedupSavings(ctx)
+	if err != nil {
+		return DedupSavings{}, fmt.Errorf("dedup savings: %w", err)
+	}
+	return DedupSavings(r), nil
+}
+
+// GameUsage returns the disk space that each game install uses.
+func (c *Client) GameUsage(ctx context.Context) ([]GameUsage, error) {
+	rows, err := c.q.DiskUsageByGame(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("disk usage by game: %w", err)
+	}
+
+	out := make([]GameUsage, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, GameUsage(r))
+	}
+	return out, nil
+}
+
+// ModUsage returns the disk space that the mods of a game install (of every
+// game install if gi is nil) use, largest first. limit is how many of them
+// (0: all).
+func (c *Client) ModUsage(ctx context.Context, gi *Game, limit int64) ([]ModUsage, error) {
+	var gameID sql.NullInt64
+	if gi != nil {
+		gameID = sql.NullInt64{Int64: gi.ID, Valid: true}
+	}
+	if limit <= 0 {
+		limit = -1 // no limit
+	}
+
+	rows, err := c.q.DiskUsageByModPage(ctx, dbq.DiskUsageByModPageParams{
+		GameInstallID: gameID,
+		RowLimit:      limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("disk usage by mod: %w", err)
+	}
+
+	out := make([]ModUsage, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, ModUsage{
+			PageID:        r.ID,
+			GameInstallID: r.GameInstallID,
+			Name:          r.Name,
+			Game:          r.GameName,
+			Versions:      r.Versions,
+			Bytes:         r.Bytes,
+		})
+	}
+	return out, nil
+}
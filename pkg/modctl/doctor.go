@@ -0,0 +1,405 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/fscaps"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/spf13/viper"
+)
+
+// The kinds of blobs, see Blobs.
+const (
+	BlobArchive  = string(blobstore.KindArchive)
+	BlobBackup   = string(blobstore.KindBackup)
+	BlobOverride = string(blobstore.KindOverride)
+)
+
+// BlobKinds are the kinds of blobs that the blob store keeps.
+var BlobKinds = []string{BlobArchive, BlobBackup, BlobOverride}
+
+// OpenExisting opens the configured database as it is: unlike Open it
+// doesn't migrate it, so that its state can be checked (see Migrations and
+// IntegrityProblems).
+func OpenExisting() (*Client, error) {
+	db, err := internal.SetupDB()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{db: db, q: dbq.New(db)}, nil
+}
+
+// Ping checks that the database can be queried at all.
+func (c *Client) Ping(ctx context.Context) error {
+	ctxT, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
+	var one int
+	if err := c.db.QueryRowContext(ctxT, "SELECT 1").Scan(&one); err != nil {
+		return err
+	}
+	if one != 1 {
+		return errors.New("SELECT 1 returned something else")
+	}
+	return nil
+}
+
+// Migrations returns the schema version of the database and the newest
+// one, which Open migrates it to.
+func (c *Client) Migrations(ctx context.Context) (current, target int64, err error) {
+	p, err := internal.GooseProvider(c.db)
+	if err != nil {
+		return 0, 0, err
+	}
+	return p.GetVersions(ctx)
+}
+
+// IntegrityProblems runs sqlite's quick_check (or with full its slower
+// integrity_check) and returns what it found wrong.
+func (c *Client) IntegrityProblems(ctx context.Context, full bool) ([]string, error) {
+	pragma := "PRAGMA quick_check;"
+	if full {
+		pragma = "PRAGMA integrity_check;"
+	}
+
+	rows, err := c.db.QueryContext(ctx, pragma)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, err
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// ForeignKeyViolations runs sqlite's foreign_key_check and returns the rows
+// that refer to rows that don't exist.
+func (c *Client) ForeignKeyViolations(ctx context.Context) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, "PRAGMA foreign_key_check;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var violations []string
+	for rows.Next() {
+		var table string
+		var rowid int64
+		var parent string
+		var fkid int64
+
+		if err := rows.Scan(&table, &rowid, &parent, &fkid); err != nil {
+			return nil, err
+		}
+
+		violations = append(violations,
+			fmt.Sprintf("table=%s rowid=%d parent=%s fkid=%d",
+				table, rowid, parent, fkid,
+			),
+		)
+	}
+	return violations, rows.Err()
+}
+
+// SQLiteVersion returns the version of sqlite that modctl was built with.
+func (c *Client) SQLiteVersion(ctx context.Context) (string, error) {
+	var v string
+	err := c.db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&v)
+	return v, err
+}
+
+// Blob is a file in the blob store.
+type Blob struct {
+	Kind   string
+	SHA256 string
+	Size   int64
+	// when it was found to be corrupted and quarantined (empty if it
+	// wasn't)
+	QuarantinedAt string
+}
+
+// Blobs returns the blobs of a kind (see BlobKinds) that are recorded in the
+// database.
+func (c *Client) Blobs(ctx context.Context, kind string) ([]Blob, error) {
+	rows, err := c.q.ListBlobsByKind(ctx, kind)
+	if err != nil {
+		return nil, fmt.Errorf("list blobs kind=%s: %w", kind, err)
+	}
+
+	out := make([]Blob, 0, len(rows))
+	for _, b := range rows {
+		out = append(out, Blob{
+			Kind:          b.Kind,
+			SHA256:        b.Sha256,
+			Size:          b.SizeBytes,
+			QuarantinedAt: b.CorruptedAt.String,
+		})
+	}
+	return out, nil
+}
+
+// BlobCounts is how many of the recorded blobs of a kind are missing from
+// the blob store or have the wrong size, see CountBlobs.
+type BlobCounts struct {
+	Kind         string
+	Recorded     int
+	Missing      int
+	Quarantined  int
+	SizeMismatch int
+}
+
+// Present returns how many of the blobs are in the blob store.
+func (b BlobCounts) Present() int {
+	return b.Recorded - b.Missing - b.Quarantined
+}
+
+// CountBlobs checks that the files of the recorded blobs of a kind are in
+// the blob store and have the right size. It doesn't hash them, see
+// VerifyBlob.
+func (c *Client) CountBlobs(ctx context.Context, kind string) (BlobCounts, error) {
+	counts := BlobCounts{Kind: kind}
+
+	blobs, err := c.Blobs(ctx, kind)
+	if err != nil {
+		return counts, err
+	}
+	counts.Recorded = len(blobs)
+
+	bs := internal.BlobStoreFromConfig()
+	for _, b := range blobs {
+		if b.QuarantinedAt != "" {
+			counts.Quarantined++
+			continue
+		}
+
+		path, err := bs.Locate(blobstore.Kind(kind), b.SHA256)
+		if err != nil {
+			return counts, fmt.Errorf("derive blob path kind=%s sha=%s: %w", kind, b.SHA256, err)
+		}
+
+		st, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				counts.Missing++
+				continue
+			}
+			return counts, fmt.Errorf("stat blob kind=%s sha=%s path=%s: %w", kind, b.SHA256, path, err)
+		}
+
+		// if it exists but the size differs, something is wrong
+		if st.Size() != b.Size {
+			counts.SizeMismatch++
+		}
+	}
+
+	return counts, nil
+}
+
+// VerifyBlob hashes a blob again. It returns what's wrong with it (nil if
+// nothing; os.ErrNotExist if it's missing) and, if it's corrupted, where it
+// was quarantined. A corrupted blob in the shared archive store
+// (shared_archives_dir) can't be quarantined: the problem says so and the
+// quarantine is nil. Progress, if set, is called while the blob is read.
+//
+// The caller holds the state lock (see LockState).
+func (c *Client) VerifyBlob(ctx context.Context, b Blob, progress func(done, total int64)) (problem error, q *Quarantine, err error) {
+	bs := internal.BlobStoreFromConfig()
+	bs.Progress = progress
+	return c.checkBlob(ctx, bs, blobstore.Kind(b.Kind), b.SHA256, b.Size)
+}
+
+// ConsistencyIssue is a problem that CheckConsistency found, with a hint on
+// how to fix it.
+type ConsistencyIssue struct {
+	Check   string `json:"check"`
+	Problem string `json:"problem"`
+	Hint    string `json:"hint"`
+}
+
+// CheckConsistency cross-checks the active selection (active.json), the
+// profiles, and their items against each other and the blob store: e.g.,
+// that the active game exists, that every game has one active profile, and
+// that no profile item needs a missing or quarantined archive.
+func (c *Client) CheckConsistency(ctx context.Context) ([]ConsistencyIssue, error) {
+	var snap internal.StateSnapshot
+	var err error
+
+	if snap.Active, snap.ActiveProblem, err = state.ReadActive(); err != nil {
+		return nil, err
+	}
+
+	// read in one transaction so that the rows agree with each other
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.q.WithTx(tx)
+
+	if snap.DBActive, snap.DBActiveOK, err = internal.LoadActiveFromDB(ctx, qtx); err != nil {
+		snap.DBActiveProblem = err
+	}
+	if snap.Installs, err = qtx.ListAllGameInstalls(ctx); err != nil {
+		return nil, fmt.Errorf("list game installs: %w", err)
+	}
+	if snap.Profiles, err = qtx.ListAllProfiles(ctx); err != nil {
+		return nil, fmt.Errorf("list profiles: %w", err)
+	}
+	if snap.ProfileItems, err = qtx.ListProfileItemArchives(ctx); err != nil {
+		return nil, fmt.Errorf("list profile items: %w", err)
+	}
+
+	bs := internal.BlobStoreFromConfig()
+	snap.BlobOnDisk = func(sha string) bool {
+		path, err := bs.Locate(blobstore.KindArchive, sha)
+		if err != nil {
+			return false
+		}
+		_, err = os.Stat(path)
+		return err == nil
+	}
+
+	issues := internal.CheckConsistency(snap)
+	out := make([]ConsistencyIssue, 0, len(issues))
+	for _, is := range issues {
+		out = append(out, ConsistencyIssue(is))
+	}
+	return out, nil
+}
+
+// FilesystemReport is what a filesystem supports, see ProbeFilesystems.
+type FilesystemReport struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+
+	// files can be hardlinked (or reflinked) into it from where they're
+	// extracted
+	Hardlinks     bool `json:"hardlinks"`
+	Reflinks      bool `json:"reflinks"`
+	Symlinks      bool `json:"symlinks"`
+	CaseSensitive bool `json:"case_sensitive"`
+	// only names that are valid on Windows can be created
+	WindowsNames bool `json:"windows_names"`
+
+	// why it couldn't be probed
+	Error string `json:"error,omitempty"`
+}
+
+// ProbeFilesystems probes what the filesystems of the blob store (within
+// itself) and of the targets of every game install (from tmp_dir, where
+// apply extracts archives) support by creating test files in them. A target
+// that can't be probed gets a report with an error; only failing to read the
+// database is an error.
+func (c *Client) ProbeFilesystems(ctx context.Context) ([]FilesystemReport, error) {
+	var reports []FilesystemReport
+	probe := func(name, path, from string) {
+		fr := FilesystemReport{Name: name, Path: path}
+		caps, err := fscaps.Probe(path, from)
+		if err != nil {
+			fr.Error = err.Error()
+		} else {
+			fr.Hardlinks = caps.Hardlinks
+			fr.Reflinks = caps.Reflinks
+			fr.Symlinks = caps.Symlinks
+			fr.CaseSensitive = caps.CaseSensitive
+			fr.WindowsNames = caps.WindowsNames
+		}
+		reports = append(reports, fr)
+	}
+
+	archives := viper.GetString("archives_dir")
+	probe("blob store", archives, archives)
+
+	installs, err := c.q.ListAllGameInstalls(ctx)
+	if err != nil {
+		return reports, fmt.Errorf("list game installs: %w", err)
+	}
+
+	tmp := viper.GetString("tmp_dir")
+	for _, gi := range installs {
+		targets, err := c.q.ListTargetsForGameInstall(ctx, gi.ID)
+		if err != nil {
+			return reports, fmt.Errorf("list targets: %w", err)
+		}
+
+		for _, t := range targets {
+			name := gi.DisplayName + ": " + t.Name
+			root, err := internal.TargetRoot(gi, t)
+			if err != nil {
+				reports = append(reports, FilesystemReport{Name: name, Error: err.Error()})
+				continue
+			}
+			probe(name, root, tmp)
+		}
+	}
+
+	return reports, nil
+}
+
+// GameLeftovers is what other mod managers left in the targets of a game
+// install, see ScanAllLeftovers.
+type GameLeftovers struct {
+	Game      string     `json:"game"`
+	Leftovers []Leftover `json:"leftovers,omitempty"`
+	// why it couldn't be scanned
+	Error string `json:"error,omitempty"`
+}
+
+// ScanAllLeftovers does a quick scan (see ScanLeftovers) of every game
+// install that is present. Only failing to list the installs is an error.
+func (c *Client) ScanAllLeftovers(ctx context.Context) ([]GameLeftovers, error) {
+	installs, err := c.Games(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []GameLeftovers
+	for _, gi := range installs {
+		if !gi.Present {
+			continue
+		}
+		lr := GameLeftovers{Game: gi.DisplayName}
+		lr.Leftovers, err = c.ScanLeftovers(ctx, gi, true)
+		if err != nil {
+			lr.Error = err.Error()
+		}
+		reports = append(reports, lr)
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,114 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/download"
+)
+
+// DownloadLimits are how fast (bytes per second) files are downloaded from a
+// host and how many of them at the same time, on top of the overall limits
+// (download_limit_rate and download_concurrency). 0 is no limit.
+type DownloadLimits struct {
+	// empty for the overall limits
+	Host        string
+	Rate        int64
+	Concurrency int64
+}
+
+// DownloadLimits returns the overall download limits and those of every
+// host that has some.
+func (c *Client) DownloadLimits(ctx context.Context) (DownloadLimits, []DownloadLimits, error) {
+	global, err := internal.DownloadPolicy()
+	if err != nil {
+		return DownloadLimits{}, nil, err
+	}
+	rows, err := c.q.ListDownloadHosts(ctx)
+	if err != nil {
+		return DownloadLimits{}, nil, fmt.Errorf("list download hosts: %w", err)
+	}
+
+	hosts := make([]DownloadLimits, 0, len(rows))
+	for _, r := range rows {
+		hosts = append(hosts, downloadLimitsFromRow(r))
+	}
+	return DownloadLimits{Rate: global.LimitRate, Concurrency: int64(global.Concurrency)}, hosts, nil
+}
+
+// HostDownloadLimits returns the download limits of a host (none if it
+// doesn't have any). The limits of a host also apply to its subdomains.
+func (c *Client) HostDownloadLimits(ctx context.Context, host string) (DownloadLimits, error) {
+	h := download.NormalizeHost(host)
+	if h == "" {
+		return DownloadLimits{}, fmt.Errorf("invalid host %q", host)
+	}
+
+	rows, err := c.q.ListDownloadHosts(ctx)
+	if err != nil {
+		return DownloadLimits{}, fmt.Errorf("list download hosts: %w", err)
+	}
+	for _, r := range rows {
+		if r.Host == h {
+			return downloadLimitsFromRow(r), nil
+		}
+	}
+	return DownloadLimits{Host: h}, nil
+}
+
+// SetDownloadLimits sets the download limits of l.Host; without limits the
+// host is removed.
+func (c *Client) SetDownloadLimits(ctx context.Context, l DownloadLimits) error {
+	h := download.NormalizeHost(l.Host)
+	if h == "" {
+		return fmt.Errorf("invalid host %q", l.Host)
+	}
+	if l.Rate < 0 || l.Concurrency < 0 {
+		return fmt.Errorf("download limits must not be negative")
+	}
+
+	if l.Rate == 0 && l.Concurrency == 0 {
+		if _, err := c.q.DeleteDownloadHost(ctx, h); err != nil {
+			return fmt.Errorf("remove download limits: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.q.UpsertDownloadHost(ctx, dbq.UpsertDownloadHostParams{
+		Host:        h,
+		LimitRate:   sql.NullInt64{Int64: l.Rate, Valid: l.Rate > 0},
+		Concurrency: sql.NullInt64{Int64: l.Concurrency, Valid: l.Concurrency > 0},
+	}); err != nil {
+		return fmt.Errorf("set download limits: %w", err)
+	}
+	return nil
+}
+
+func downloadLimitsFromRow(r dbq.DownloadHost) DownloadLimits {
+	return DownloadLimits{
+		Host:        r.Host,
+		Rate:        r.LimitRate.Int64,
+		Concurrency: r.Concurrency.Int64,
+	}
+}
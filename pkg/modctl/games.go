@@ -0,0 +1,348 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/deploy"
+	"github.com/mfinelli/modctl/internal/fscaps"
+	"github.com/mfinelli/modctl/internal/state"
+)
+
+// ErrNoActiveGame is returned by ActiveGame if no game was selected with
+// `modctl games set-active`.
+var ErrNoActiveGame = internal.ErrNoActiveGame
+
+// Game is a discovered game install.
+type Game struct {
+	ID int64
+	// the store and its id of the game (e.g., "steam" and "1091500"), and
+	// which install of it this is ("default" unless there are several)
+	StoreID     string
+	StoreGameID string
+	InstanceID  string
+
+	DisplayName string
+	InstallRoot string
+	// whether the last refresh found the game, and when a refresh last did
+	Present    bool
+	LastSeenAt string
+
+	// the profile that is deployed to the game (0: none) and when
+	AppliedProfileID int64
+	AppliedAt        string
+
+	// the game install that this one is a copy of (0: none, see
+	// DuplicateGame), and whether it's a steam game of the flatpak steam
+	CopyOf  int64
+	Flatpak bool
+	// whether it's a steam game (or a non-steam game that steam launches)
+	RunsInSteam bool
+	// when the baseline of the game files was recorded (see ScanGame) and
+	// when the game was last verified vanilla (empty: never)
+	BaselineScannedAt string
+	VerifiedVanillaAt string
+}
+
+// Selector returns the full selector of the game install (e.g.,
+// "steam:1091500#default").
+func (g Game) Selector() string {
+	return internal.FullSelector(g.StoreID, g.StoreGameID, g.InstanceID)
+}
+
+// ShortSelector returns the selector of the game install without the
+// instance if it's the default one.
+func (g Game) ShortSelector() string {
+	return internal.ShortSelector(g.StoreID, g.StoreGameID, g.InstanceID)
+}
+
+func gameFromRow(gi dbq.GameInstall) Game {
+	return Game{
+		ID:               gi.ID,
+		StoreID:          gi.StoreID,
+		StoreGameID:      gi.StoreGameID,
+		InstanceID:       gi.InstanceID,
+		DisplayName:      gi.DisplayName,
+		InstallRoot:      gi.InstallRoot,
+		Present:          gi.IsPresent != 0,
+		LastSeenAt:       gi.LastSeenAt.String,
+		AppliedProfileID: gi.AppliedProfileID.Int64,
+		AppliedAt:        gi.AppliedAt.String,
+
+		CopyOf:            internal.CopyOf(gi),
+		Flatpak:           internal.SteamFlatpak(gi),
+		RunsInSteam:       internal.RunsInSteam(gi),
+		BaselineScannedAt: gi.BaselineScannedAt.String,
+		VerifiedVanillaAt: gi.VerifiedVanillaAt.String,
+	}
+}
+
+// gameRow looks up the current row of a game install: the operations work
+// on what's in the database, not on the copy the caller has.
+func (c *Client) gameRow(ctx context.Context, gi Game) (dbq.GameInstall, error) {
+	row, err := c.q.GetGameInstallByID(ctx, gi.ID)
+	if err != nil {
+		return row, fmt.Errorf("lookup game install %d: %w", gi.ID, err)
+	}
+	return row, nil
+}
+
+// Games returns every discovered game install.
+func (c *Client) Games(ctx context.Context) ([]Game, error) {
+	rows, err := c.q.ListAllGameInstalls(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list game installs: %w", err)
+	}
+
+	games := make([]Game, 0, len(rows))
+	for _, gi := range rows {
+		games = append(games, gameFromRow(gi))
+	}
+	return games, nil
+}
+
+// Game returns the game install with the given id or selector (e.g.,
+// "steam:1091500" or "steam:1091500#default").
+func (c *Client) Game(ctx context.Context, selector string) (Game, error) {
+	gi, err := internal.ResolveGameInstallArg(ctx, c.q, selector)
+	if err != nil {
+		return Game{}, err
+	}
+	return gameFromRow(gi), nil
+}
+
+// ActiveGame returns the active game install.
+func (c *Client) ActiveGame(ctx context.Context) (Game, error) {
+	active, err := state.LoadActive()
+	if err != nil {
+		return Game{}, fmt.Errorf("load active selection: %w", err)
+	}
+	if active.ActiveGameInstallID == 0 {
+		return Game{}, ErrNoActiveGame
+	}
+	return c.Game(ctx, strconv.FormatInt(active.ActiveGameInstallID, 10))
+}
+
+// SetActiveGame makes a game install the active one, which the commands
+// (and ActiveGame) use unless they're given another.
+func (c *Client) SetActiveGame(ctx context.Context, gi Game) error {
+	a, err := state.LoadActive()
+	if err != nil {
+		return err
+	}
+
+	a.ActiveStoreID = gi.StoreID // keeps store context in sync
+	a.ActiveGameInstallID = gi.ID
+	a.ActiveGameInstallSelector = gi.Selector()
+
+	return internal.SaveActive(ctx, c.db, c.q, a)
+}
+
+// GameDrift is how the discovered game installs changed during a refresh.
+type GameDrift struct {
+	// new, or present again
+	Added []Game
+	// no longer present
+	Removed []Game
+	// their install root changed
+	Moved []MovedGame
+}
+
+// MovedGame is a game install whose install root changed.
+type MovedGame struct {
+	Game
+	OldRoot string
+}
+
+// Empty reports whether nothing changed.
+func (d GameDrift) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+// RefreshGames scans the stores for game installs, writing progress to w. It
+// returns how the game installs changed.
+func (c *Client) RefreshGames(ctx context.Context, w io.Writer) (GameDrift, error) {
+	before, err := c.q.ListAllGameInstalls(ctx)
+	if err != nil {
+		return GameDrift{}, fmt.Errorf("list game installs: %w", err)
+	}
+	if err := internal.ScanStores(ctx, c.db, w); err != nil {
+		return GameDrift{}, err
+	}
+	after, err := c.q.ListAllGameInstalls(ctx)
+	if err != nil {
+		return GameDrift{}, fmt.Errorf("list game installs: %w", err)
+	}
+
+	var d GameDrift
+	drift := internal.DiffGameInstalls(before, after)
+	for _, gi := range drift.Added {
+		d.Added = append(d.Added, gameFromRow(gi))
+	}
+	for _, gi := range drift.Removed {
+		d.Removed = append(d.Removed, gameFromRow(gi))
+	}
+	for _, m := range drift.Moved {
+		d.Moved = append(d.Moved, MovedGame{Game: gameFromRow(m.Install), OldRoot: m.OldRoot})
+	}
+	return d, nil
+}
+
+// SteamAccount is the steam account whose userdata (e.g., cloud saves) a
+// steam game uses.
+type SteamAccount struct {
+	SteamID64 uint64
+	// the 32-bit account id, which the userdata directories are named after
+	AccountID uint32
+	// e.g., "persona (login)"
+	Name string
+	// the userdata directory of the game, empty if the account has none
+	Userdata string
+
+	// the account that steam installed the game for (0 if we don't know),
+	// another one e.g. with Family Sharing
+	Owner uint64
+}
+
+// SteamAccount returns the steam account that a steam game uses (see the
+// steam_account config option). If it can't tell, the error comes with the
+// Owner that is known.
+func (c *Client) SteamAccount(ctx context.Context, gi Game) (SteamAccount, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return SteamAccount{}, err
+	}
+
+	a, owner, err := internal.SteamAccount(row)
+	if err != nil {
+		return SteamAccount{Owner: owner}, err
+	}
+
+	sa := SteamAccount{SteamID64: a.SteamID64, AccountID: a.AccountID(), Name: a.String(), Owner: owner}
+	if a.Userdata != "" {
+		sa.Userdata = filepath.Join(a.Userdata, gi.StoreGameID)
+	}
+	return sa, nil
+}
+
+// DuplicateOptions are the options of DuplicateGame.
+type DuplicateOptions struct {
+	// the instance id of the copy (empty: copy, copy_2, ...)
+	Instance string
+	// always copy files instead of cloning or hardlinking them
+	Copy bool
+	// called with the bytes copied so far, if set
+	Progress func(done, total int64)
+}
+
+// DuplicateResult is what DuplicateGame made.
+type DuplicateResult struct {
+	Game Game
+
+	Files      int
+	Bytes      int64
+	Reflinked  int
+	Hardlinked int
+	Copied     int
+
+	// files that modctl deployed to the original, which are copied as they
+	// are but aren't tracked for the copy
+	Untracked int
+	// targets of the original that the copy doesn't have because they're
+	// outside of its install root
+	Skipped []string
+}
+
+// DuplicateGame copies a game install to dest (which must not exist yet)
+// and registers the copy as another instance of the same game, with its own
+// default profile. Files are cloned where the filesystem supports it, else
+// hardlinked if dest is on the same filesystem, else copied.
+func (c *Client) DuplicateGame(ctx context.Context, gi Game, dest string, opts DuplicateOptions) (DuplicateResult, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return DuplicateResult{}, err
+	}
+	if row.IsPresent == 0 {
+		return DuplicateResult{}, fmt.Errorf("%s is not present at %s", row.DisplayName, row.InstallRoot)
+	}
+
+	instance := opts.Instance
+	if instance == "" {
+		instance, err = internal.NextCopyInstanceID(ctx, c.q, row)
+		if err != nil {
+			return DuplicateResult{}, err
+		}
+	}
+
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return DuplicateResult{}, err
+	}
+
+	m := deploy.Copy
+	if !opts.Copy {
+		caps, err := fscaps.Probe(dest, row.InstallRoot)
+		if err != nil {
+			return DuplicateResult{}, fmt.Errorf("probe %s: %w", dest, err)
+		}
+		switch {
+		case caps.Reflinks:
+			m = deploy.Reflink
+		case caps.Hardlinks:
+			m = deploy.Hardlink
+		}
+	}
+
+	installed, err := c.q.ListInstalledFilesForGame(ctx, row.ID)
+	if err != nil {
+		return DuplicateResult{}, fmt.Errorf("list installed files: %w", err)
+	}
+
+	res, err := internal.DuplicateGameInstall(ctx, c.db, c.q, row, dest, instance, m, opts.Progress)
+	if err != nil {
+		return DuplicateResult{}, err
+	}
+
+	return DuplicateResult{
+		Game:       gameFromRow(res.Install),
+		Files:      res.Stats.Files,
+		Bytes:      res.Stats.Bytes,
+		Reflinked:  res.Stats.Reflinked,
+		Hardlinked: res.Stats.Hardlinked,
+		Copied:     res.Stats.Copied,
+		Untracked:  len(installed),
+		Skipped:    res.Skipped,
+	}, nil
+}
+
+// RepairActive makes the active selection (of the game install and its
+// store, see SetActiveGame) usable again: a corrupt one is reset, a game
+// install that no longer exists is replaced by the install with the same
+// selector or unset, and, if the active game has no active profile but only
+// one profile, that one is activated. It returns what it fixed.
+func (c *Client) RepairActive(ctx context.Context) ([]string, error) {
+	return internal.RepairActive(ctx, c.db, c.q)
+}
@@ -0,0 +1,127 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package modctl is the Go API of modctl. It offers the games, mods, and
+// profiles operations of the command line tool to other Go programs (e.g.,
+// GUIs or bots) so that they don't have to shell out to it.
+//
+// The API covers everything that the command line tool does with the games,
+// mods, and profiles: discovering and scanning games and their targets,
+// importing, verifying, and repairing mods (and their Nexus Mods files and
+// attachments), editing, planning, and applying profiles (with their
+// overrides, plugins, and symlinks), and the maintenance of the database and
+// the blob store (backups, advisories, the operation log, doctor, and disk
+// usage). The commands are a thin layer on top of it that parses the flags
+// and prints the results, so that each operation has one implementation. Only
+// what's about the command line tool itself (its config file, the secrets
+// provider of the API keys, the shell, and the HTTP cache) is left out.
+//
+// modctl keeps its settings in viper's global instance, like the command
+// line tool: call LoadConfig (or set the options with viper.Set) before Open.
+//
+// Like the command line tool, callers should hold the state lock (see LockState)
+// while they change anything.
+package modctl
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/lock"
+	"github.com/mfinelli/modctl/internal/nexus"
+)
+
+// Version is the version of modctl (e.g., sent to Nexus Mods with every
+// request).
+const Version = "1.0.0"
+
+// Client is an open modctl database.
+type Client struct {
+	db *sql.DB
+	q  *dbq.Queries
+
+	// created on first use, see nexusClient
+	nexus *nexus.Client
+}
+
+// LoadConfig sets the defaults of every option and reads the config file at
+// path, or the default config file (if there is one) if path is empty.
+func LoadConfig(path string) error {
+	if err := internal.LoadConfig(path); err != nil {
+		return err
+	}
+	return internal.ApplyIOConfig()
+}
+
+// Open opens the configured database (which `modctl init` creates) and
+// migrates it to the current schema.
+func Open(ctx context.Context) (*Client, error) {
+	db, err := internal.OpenDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{db: db, q: dbq.New(db)}, nil
+}
+
+// Close closes the database.
+func (c *Client) Close() error {
+	return c.db.Close()
+}
+
+// nexusClient returns the client of the Nexus Mods API (which needs an API
+// key, see `modctl auth login`).
+func (c *Client) nexusClient(ctx context.Context) (*nexus.Client, error) {
+	if c.nexus == nil {
+		nc, err := internal.NewNexusClient(ctx, c.q, Version)
+		if err != nil {
+			return nil, err
+		}
+		c.nexus = nc
+	}
+	return c.nexus, nil
+}
+
+// LockState takes the lock that serializes changes to modctl's state (shared
+// with the command line tool). command describes the holder to other
+// processes that want the lock (e.g., "mygui apply"). Release it when done.
+func LockState(command string) (*Lock, error) {
+	l, err := internal.LockState(command)
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{l: l}, nil
+}
+
+// LockHeldError is returned by LockState when another process holds the
+// lock.
+type LockHeldError = lock.HeldError
+
+// Lock is the state lock, see LockState.
+type Lock struct {
+	l *lock.Lock
+}
+
+// Release releases the lock.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	return l.l.Release()
+}
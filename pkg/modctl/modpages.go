@@ -0,0 +1,517 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/spf13/viper"
+)
+
+// ModPage is a mod of a game install (a mod page, e.g., on Nexus Mods).
+type ModPage struct {
+	ID            int64
+	GameInstallID int64
+	Name          string
+	SourceKind    string
+	// the nexus page of the mod (empty and 0 if it isn't from nexus)
+	NexusDomain string
+	NexusModID  int64
+	SourceRef   string
+	SourceURL   string
+	Notes       string
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+func modPageFromRow(p dbq.ModPage) ModPage {
+	return ModPage{
+		ID:            p.ID,
+		GameInstallID: p.GameInstallID,
+		Name:          p.Name,
+		SourceKind:    p.SourceKind,
+		NexusDomain:   p.NexusGameDomain.String,
+		NexusModID:    p.NexusModID.Int64,
+		SourceRef:     p.SourceRef.String,
+		SourceURL:     p.SourceUrl.String,
+		Notes:         p.Notes.String,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// ModPage returns the mod of a game install given as its id, its name
+// (case-insensitive), or the sha256 (or a prefix of at least eight
+// characters) of one of its archives.
+func (c *Client) ModPage(ctx context.Context, gi Game, arg string) (ModPage, error) {
+	p, err := internal.ResolveModPageArg(ctx, c.q, gi.ID, arg)
+	if err != nil {
+		return ModPage{}, err
+	}
+	return modPageFromRow(p), nil
+}
+
+// ModInfo is everything modctl knows about a mod, see Client.ModInfo.
+type ModInfo struct {
+	Files       []ModInfoFile
+	Attachments []Attachment
+}
+
+// ModInfoFile is a file of a mod with the details of its versions.
+type ModInfoFile struct {
+	ID      int64
+	Label   string
+	Primary bool
+	// the nexus file id and category (0 and empty if they aren't known)
+	NexusFileID int64
+	Category    string
+
+	// newest first
+	Versions []ModInfoVersion
+}
+
+// ModInfoVersion is an imported archive of a mod file with where it came
+// from and the profiles that use it.
+type ModInfoVersion struct {
+	ModVersion
+
+	OriginalName string
+	// the blob mirror that the archive was fetched from, and when (empty if
+	// it was imported)
+	FetchedFrom string
+	FetchedAt   string
+	Notes       string
+
+	Profiles []VersionUse
+}
+
+// VersionUse is a profile that has a mod file version.
+type VersionUse struct {
+	Profile  string
+	Enabled  bool
+	Priority int64
+}
+
+// ModInfo returns the files, versions, and attachments of a mod.
+func (c *Client) ModInfo(ctx context.Context, p ModPage) (ModInfo, error) {
+	var info ModInfo
+
+	files, err := c.q.ListModFilesByPage(ctx, p.ID)
+	if err != nil {
+		return info, fmt.Errorf("list mod files: %w", err)
+	}
+
+	items, err := c.q.ListProfileItemsForModPage(ctx, p.ID)
+	if err != nil {
+		return info, fmt.Errorf("list profile items: %w", err)
+	}
+	usage := map[int64][]VersionUse{}
+	for _, it := range items {
+		usage[it.ModFileVersionID] = append(usage[it.ModFileVersionID], VersionUse{
+			Profile:  it.ProfileName,
+			Enabled:  it.Enabled != 0,
+			Priority: it.Priority,
+		})
+	}
+
+	info.Attachments, err = c.Attachments(ctx, p)
+	if err != nil {
+		return info, err
+	}
+
+	for _, f := range files {
+		mf := ModInfoFile{
+			ID:          f.ID,
+			Label:       f.Label,
+			Primary:     f.IsPrimary != 0,
+			NexusFileID: f.NexusFileID.Int64,
+			Category:    f.Category.String,
+		}
+
+		vers, err := c.q.ListModFileVersionDetailsByFile(ctx, f.ID)
+		if err != nil {
+			return info, fmt.Errorf("list versions (file_id=%d): %w", f.ID, err)
+		}
+		for _, v := range vers {
+			mf.Versions = append(mf.Versions, ModInfoVersion{
+				ModVersion: ModVersion{
+					ID:            v.ID,
+					Version:       v.VersionString.String,
+					UploadedAt:    v.UploadedAt.String,
+					ImportedAt:    v.CreatedAt,
+					ArchiveSHA256: v.ArchiveSha256,
+					SizeBytes:     v.SizeBytes.Int64,
+				},
+				OriginalName: v.OriginalName.String,
+				FetchedFrom:  v.FetchedFrom.String,
+				FetchedAt:    v.FetchedAt.String,
+				Notes:        v.Notes.String,
+				Profiles:     usage[v.ID],
+			})
+		}
+		info.Files = append(info.Files, mf)
+	}
+
+	return info, nil
+}
+
+// Doc kinds, see Doc.
+const (
+	DocReadme    = string(archive.DocReadme)
+	DocChangelog = string(archive.DocChangelog)
+	DocLicense   = string(archive.DocLicense)
+)
+
+// MaxDocBytes is how much of a documentation file is kept.
+const MaxDocBytes = archive.MaxDocBytes
+
+// Doc is a documentation file (readme, changelog, or license) of a mod
+// archive.
+type Doc struct {
+	Kind string
+	Path string
+	Text string
+	// only the first MaxDocBytes were kept
+	Truncated bool
+}
+
+// Docs are the documentation files of a mod file version, see Client.Docs.
+type Docs struct {
+	VersionID int64
+	FileLabel string
+	Version   string
+
+	Docs []Doc
+}
+
+// Docs returns the documentation files that were found in the archive of
+// a mod file version when it was imported: of versionID, or of the newest
+// version of the primary file if it's 0. Archives that were imported before
+// modctl kept them are read again.
+func (c *Client) Docs(ctx context.Context, p ModPage, versionID int64) (Docs, error) {
+	vers, err := c.q.ListModFileVersionMetadataForPage(ctx, p.ID)
+	if err != nil {
+		return Docs{}, fmt.Errorf("list versions: %w", err)
+	}
+	if len(vers) == 0 {
+		return Docs{}, fmt.Errorf("mod %d (%s) has no imported versions", p.ID, p.Name)
+	}
+
+	// the query returns the newest version of the primary file first
+	v := vers[0]
+	if versionID != 0 {
+		found := false
+		for _, cand := range vers {
+			if cand.ID == versionID {
+				v, found = cand, true
+				break
+			}
+		}
+		if !found {
+			return Docs{}, fmt.Errorf("mod file version %d does not belong to mod %d (%s)",
+				versionID, p.ID, p.Name)
+		}
+	}
+
+	docs, stored, err := versionDocs(v.Metadata.String)
+	if err != nil {
+		return Docs{}, fmt.Errorf("read version %d metadata: %w", v.ID, err)
+	}
+	if !stored {
+		bs := internal.BlobStoreFromConfig()
+		archivePath, err := bs.Locate(blobstore.KindArchive, v.ArchiveSha256)
+		if err != nil {
+			return Docs{}, err
+		}
+
+		ctxT, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		docs, err = archive.FindDocs(ctxT, viper.GetString("bsdtar"), archivePath)
+		cancel()
+		if err != nil {
+			return Docs{}, fmt.Errorf("read documentation from archive %s: %w", v.ArchiveSha256[:12], err)
+		}
+	}
+
+	out := Docs{VersionID: v.ID, FileLabel: v.FileLabel, Version: v.VersionString.String}
+	for _, d := range docs {
+		out.Docs = append(out.Docs, Doc{
+			Kind:      string(d.Kind),
+			Path:      d.Path,
+			Text:      d.Text,
+			Truncated: d.Truncated,
+		})
+	}
+	return out, nil
+}
+
+// versionDocs returns the documentation files stored in the metadata of a mod
+// file version. The second return value reports whether the metadata has a
+// docs entry at all (it doesn't for archives imported before we kept them).
+func versionDocs(metadata string) ([]archive.Doc, bool, error) {
+	if strings.TrimSpace(metadata) == "" {
+		return nil, false, nil
+	}
+
+	var meta struct {
+		Docs *[]archive.Doc `json:"docs"`
+	}
+	if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
+		return nil, false, err
+	}
+	if meta.Docs == nil {
+		return nil, false, nil
+	}
+
+	return *meta.Docs, true, nil
+}
+
+// FileRef is a mod file of a game install, see Client.ModFile.
+type FileRef struct {
+	ID      int64
+	PageID  int64
+	ModName string
+	Label   string
+	Primary bool
+}
+
+// ModFile returns the mod file of a game install with the given id.
+func (c *Client) ModFile(ctx context.Context, gi Game, id int64) (FileRef, error) {
+	f, err := c.q.GetModFileForGame(ctx, dbq.GetModFileForGameParams{
+		ID:            id,
+		GameInstallID: gi.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return FileRef{}, fmt.Errorf("mod file %d not found for this game", id)
+	}
+	if err != nil {
+		return FileRef{}, fmt.Errorf("lookup mod file: %w", err)
+	}
+	return FileRef{
+		ID:      f.ID,
+		PageID:  f.ModPageID,
+		ModName: f.ModName,
+		Label:   f.Label,
+		Primary: f.IsPrimary != 0,
+	}, nil
+}
+
+// SetFileLabel renames a mod file. Labels are unique within a mod.
+func (c *Client) SetFileLabel(ctx context.Context, f FileRef, label string) error {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return errors.New("label cannot be empty")
+	}
+
+	if err := c.q.SetModFileLabel(ctx, dbq.SetModFileLabelParams{
+		Label: label,
+		ID:    f.ID,
+	}); err != nil {
+		var se sqlite3.Error
+		if errors.As(err, &se) && se.Code == sqlite3.ErrConstraint && se.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s already has a file labeled %q", f.ModName, label)
+		}
+		return fmt.Errorf("set label: %w", err)
+	}
+	return nil
+}
+
+// SetPrimaryFile makes a mod file the primary file of its mod (the other
+// files of the mod lose the flag), or with primary false removes the flag
+// from it without choosing another one.
+func (c *Client) SetPrimaryFile(ctx context.Context, f FileRef, primary bool) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.q.WithTx(tx)
+
+	// clear first: the unique index only allows one primary per page
+	if err := qtx.ClearPrimaryModFileForPage(ctx, f.PageID); err != nil {
+		return fmt.Errorf("clear primary file: %w", err)
+	}
+	if primary {
+		if err := qtx.SetModFilePrimary(ctx, f.ID); err != nil {
+			return fmt.Errorf("set primary file: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// VersionRef is a mod file version of a game install, see
+// Client.ModFileVersion.
+type VersionRef struct {
+	ID         int64
+	FileID     int64
+	ModName    string
+	FileLabel  string
+	Version    string
+	UploadedAt string
+}
+
+// ModFileVersion returns the mod file version of a game install with the
+// given id.
+func (c *Client) ModFileVersion(ctx context.Context, gi Game, id int64) (VersionRef, error) {
+	v, err := c.q.GetModFileVersionForGame(ctx, dbq.GetModFileVersionForGameParams{
+		ID:            id,
+		GameInstallID: gi.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return VersionRef{}, fmt.Errorf("mod file version %d not found for this game", id)
+	}
+	if err != nil {
+		return VersionRef{}, fmt.Errorf("lookup mod file version: %w", err)
+	}
+	return VersionRef{
+		ID:         v.ID,
+		FileID:     v.ModFileID,
+		ModName:    v.ModName,
+		FileLabel:  v.ModFileLabel,
+		Version:    v.VersionString.String,
+		UploadedAt: v.UploadedAt.String,
+	}, nil
+}
+
+// VersionUpdate is what SetVersion changes: the fields that are nil stay
+// as they are, empty strings clear them.
+type VersionUpdate struct {
+	Version *string
+	// RFC 3339, "YYYY-MM-DD HH:MM:SS", a date, or a unix timestamp
+	UploadedAt *string
+}
+
+// SetVersion corrects the version string and/or the upload time of a mod
+// file version.
+func (c *Client) SetVersion(ctx context.Context, v VersionRef, u VersionUpdate) error {
+	var uploadedAt sql.NullString
+	if u.UploadedAt != nil && *u.UploadedAt != "" {
+		ts, ok := internal.ParseUserTimestamp(*u.UploadedAt)
+		if !ok {
+			return fmt.Errorf("invalid upload time %q", *u.UploadedAt)
+		}
+		uploadedAt = sql.NullString{String: ts, Valid: true}
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.q.WithTx(tx)
+
+	if u.Version != nil {
+		if err := qtx.SetModFileVersionString(ctx, dbq.SetModFileVersionStringParams{
+			VersionString: sql.NullString{String: *u.Version, Valid: *u.Version != ""},
+			ID:            v.ID,
+		}); err != nil {
+			return fmt.Errorf("set version string: %w", err)
+		}
+	}
+
+	if u.UploadedAt != nil {
+		if err := qtx.SetModFileVersionUploadedAt(ctx, dbq.SetModFileVersionUploadedAtParams{
+			UploadedAt: uploadedAt,
+			ID:         v.ID,
+		}); err != nil {
+			return fmt.Errorf("set uploaded at: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// GuessedVersion is a version string that BackfillVersions read from the
+// name of an archive.
+type GuessedVersion struct {
+	VersionID    int64
+	ModName      string
+	OriginalName string
+	Version      string
+}
+
+// BackfillVersions fills in the missing version strings (and upload times)
+// of the mod file versions of a game install from the names of their
+// archives, if they look like Nexus Mods downloads (e.g.,
+// SomeMod-1234-2-0-1-1699999999.7z) of the mod. It returns what it filled
+// in and how many versions it skipped because their name isn't one.
+func (c *Client) BackfillVersions(ctx context.Context, gi Game) ([]GuessedVersion, int, error) {
+	rows, err := c.q.ListModFileVersionsMissingVersion(ctx, gi.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list versions: %w", err)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.q.WithTx(tx)
+
+	var guessed []GuessedVersion
+	skipped := 0
+	for _, r := range rows {
+		dn, ok := nexus.ParseDownloadFilename(r.OriginalName.String)
+		if !ok || (r.NexusModID.Valid && r.NexusModID.Int64 != dn.ModID) {
+			skipped++
+			continue
+		}
+
+		if err := qtx.SetGuessedModFileVersion(ctx, dbq.SetGuessedModFileVersionParams{
+			VersionString: sql.NullString{String: dn.Version, Valid: true},
+			UploadedAt: sql.NullString{
+				String: dn.UploadedAt.Format("2006-01-02T15:04:05.000Z"),
+				Valid:  true,
+			},
+			ID: r.ID,
+		}); err != nil {
+			return nil, 0, fmt.Errorf("update version %d: %w", r.ID, err)
+		}
+		guessed = append(guessed, GuessedVersion{
+			VersionID:    r.ID,
+			ModName:      r.ModName,
+			OriginalName: r.OriginalName.String,
+			Version:      dn.Version,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("commit: %w", err)
+	}
+	return guessed, skipped, nil
+}
@@ -0,0 +1,536 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/importer"
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/spf13/viper"
+)
+
+// DuplicateError is returned by ImportMod when the archive was already
+// imported for the game install (and AllowDuplicate wasn't set).
+type DuplicateError struct {
+	// the mod version that it was imported as
+	PageID    int64
+	FileID    int64
+	VersionID int64
+	SHA256    string
+	ModName   string
+	FileLabel string
+	Version   string
+
+	// the archive was missing from the store (e.g., it was quarantined) and
+	// the import put it back
+	Restored bool
+
+	err error
+}
+
+func (e *DuplicateError) Error() string {
+	return e.err.Error()
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return e.err
+}
+
+// ImportOptions are the optional settings of ImportMod.
+type ImportOptions struct {
+	// add the archive as a new version of this mod page (by id) instead of
+	// creating a new mod
+	PageID int64
+	// defaults to the archive's name
+	ModName   string
+	FileLabel string
+	Version   string
+	// the nexus page of the mod (e.g., https://www.nexusmods.com/skyrim/mods/1234)
+	NexusURL string
+
+	// guess the version and upload time from the name of a nexus download
+	// (e.g., SomeMod-1234-2-0-1-1699999999.7z) if Version isn't set
+	GuessVersion bool
+	// wrap a file that isn't an archive into one (compressed as configured
+	// by archive_compression) instead of refusing it
+	Wrap bool
+	// how long listing the archive may take (0: no limit)
+	ListTimeout time.Duration
+
+	// import the archive even if it was already imported for the game
+	AllowDuplicate bool
+	// import archives that the advisory feed knows to be malicious instead
+	// of refusing them (see advisory.BlockedError)
+	IgnoreAdvisories bool
+	// import the archive even if the virus scanner (scan_command) detected
+	// something, or couldn't scan it
+	ForceScan      bool
+	AllowUnscanned bool
+
+	// Progress, if set, is called while the archive is copied into the
+	// store
+	Progress func(done, total int64)
+	// OnWarning, if set, is called with each of the warnings of the result
+	// as soon as it comes up
+	OnWarning func(warning string)
+}
+
+// ImportResult identifies an imported archive.
+type ImportResult struct {
+	PageID    int64
+	FileID    int64
+	VersionID int64
+	SHA256    string
+	Size      int64
+
+	// the input wasn't an archive, so an archive with it was imported
+	Wrapped bool
+	// the version that was guessed from the name of the input (see
+	// GuessVersion), empty if it wasn't
+	GuessedVersion string
+	// the readme/changelog/license files that were found in the archive
+	Docs []string
+	// e.g., what the advisory feed knows about the archive
+	Warnings []string
+}
+
+// Mod is a mod page of a game install with its latest file version.
+type Mod struct {
+	PageID     int64
+	Name       string
+	SourceKind string
+	// the nexus page of the mod (empty and 0 if it isn't from nexus)
+	NexusDomain string
+	NexusModID  int64
+
+	Files    int64
+	Versions int64
+
+	// the newest version of the mod (zero if it has none)
+	LatestFileID        int64
+	LatestFileLabel     string
+	LatestVersionID     int64
+	LatestVersion       string
+	LatestArchiveSHA256 string
+	LatestImportedAt    string
+}
+
+func modFromRow(r dbq.ListModsByGameInstallRow) Mod {
+	return Mod{
+		PageID:              r.ModPageID,
+		Name:                r.ModName,
+		SourceKind:          r.SourceKind,
+		NexusDomain:         r.NexusGameDomain.String,
+		NexusModID:          r.NexusModID.Int64,
+		Files:               r.FilesCount,
+		Versions:            r.VersionsCount,
+		LatestFileID:        r.ModFileID.Int64,
+		LatestFileLabel:     r.ModFileLabel.String,
+		LatestVersionID:     r.ModFileVersionID.Int64,
+		LatestVersion:       r.VersionString.String,
+		LatestArchiveSHA256: r.ArchiveSha256.String,
+		LatestImportedAt:    r.ImportedAt.String,
+	}
+}
+
+// Mods returns the mods of a game install.
+func (c *Client) Mods(ctx context.Context, gi Game) ([]Mod, error) {
+	rows, err := c.q.ListModsByGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list mods: %w", err)
+	}
+
+	mods := make([]Mod, 0, len(rows))
+	for _, r := range rows {
+		mods = append(mods, modFromRow(r))
+	}
+	return mods, nil
+}
+
+// ModFile is a file of a mod page (e.g., the main file and its optional
+// files) with its versions.
+type ModFile struct {
+	ID      int64
+	PageID  int64
+	Label   string
+	Primary bool
+	// the nexus file id (0 if it isn't known)
+	NexusFileID int64
+
+	// newest first
+	Versions []ModVersion
+}
+
+// ModVersion is an imported archive of a mod file.
+type ModVersion struct {
+	ID      int64
+	Version string
+	// when nexus says it was uploaded (empty if it isn't known) and when it
+	// was imported
+	UploadedAt    string
+	ImportedAt    string
+	ArchiveSHA256 string
+	// 0 if the archive isn't in the blobs table
+	SizeBytes int64
+}
+
+// ModFiles returns the files of the mods of a game install, in the order of
+// their mod page, the primary file first.
+func (c *Client) ModFiles(ctx context.Context, gi Game) ([]ModFile, error) {
+	files, err := c.q.ListModFilesByGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list mod files: %w", err)
+	}
+	versions, err := c.q.ListModFileVersionsByGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list versions: %w", err)
+	}
+
+	// load the files and versions of all mods at once rather than with
+	// (many) queries per mod
+	versionsByFile := map[int64][]ModVersion{}
+	for _, v := range versions {
+		versionsByFile[v.ModFileID] = append(versionsByFile[v.ModFileID], ModVersion{
+			ID:            v.ID,
+			Version:       v.VersionString.String,
+			UploadedAt:    v.UploadedAt.String,
+			ImportedAt:    v.CreatedAt,
+			ArchiveSHA256: v.ArchiveSha256,
+			SizeBytes:     v.SizeBytes.Int64,
+		})
+	}
+
+	out := make([]ModFile, 0, len(files))
+	for _, f := range files {
+		out = append(out, ModFile{
+			ID:          f.ID,
+			PageID:      f.ModPageID,
+			Label:       f.Label,
+			Primary:     f.IsPrimary != 0,
+			NexusFileID: f.NexusFileID.Int64,
+			Versions:    versionsByFile[f.ID],
+		})
+	}
+	return out, nil
+}
+
+// InstallSize returns the space that the files of an archive take up once
+// they're extracted. It's read from the archive the first time and
+// remembered.
+func (c *Client) InstallSize(ctx context.Context, sha256 string) (int64, error) {
+	return internal.UnpackedSize(ctx, c.q, internal.BlobStoreFromConfig(), viper.GetString("bsdtar"), sha256)
+}
+
+// WorkshopItem is an item that steam downloaded from the Steam Workshop for
+// a game. modctl doesn't manage them, but it takes them into account when
+// looking for conflicts.
+type WorkshopItem struct {
+	ID  string
+	Dir string // steamapps/workshop/content/<appid>/<id>
+	// relative to Dir, using forward slashes
+	Files []string
+	// as reported by steam (0 and zero if unknown)
+	SizeBytes   int64
+	TimeUpdated time.Time
+	// listed in the workshop manifest (not only on disk)
+	Subscribed bool
+}
+
+// WorkshopItems returns the Steam Workshop items of a game install (none if
+// it isn't a steam game).
+func (c *Client) WorkshopItems(ctx context.Context, gi Game) ([]WorkshopItem, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, err
+	}
+	if row.StoreID != "steam" {
+		return nil, nil
+	}
+
+	items, err := internal.ListWorkshopItems(row)
+	if err != nil {
+		return nil, fmt.Errorf("scan steam workshop: %w", err)
+	}
+
+	out := make([]WorkshopItem, 0, len(items))
+	for _, it := range items {
+		out = append(out, WorkshopItem{
+			ID:          it.ID,
+			Dir:         it.Dir,
+			Files:       it.Files,
+			SizeBytes:   it.SizeBytes,
+			TimeUpdated: it.TimeUpdated,
+			Subscribed:  it.Subscribed,
+		})
+	}
+	return out, nil
+}
+
+// ImportMod copies a mod archive (anything that bsdtar can read) into the
+// archive store and records it as a mod of the game install.
+func (c *Client) ImportMod(ctx context.Context, gi Game, path string, opts ImportOptions) (ImportResult, error) {
+	var res ImportResult
+	warn := func(w string) {
+		res.Warnings = append(res.Warnings, w)
+		if opts.OnWarning != nil {
+			opts.OnWarning(w)
+		}
+	}
+
+	iopts := importer.ImportOptions{
+		GameInstallID:    gi.ID,
+		OriginalBasename: filepath.Base(path),
+		AllowDuplicate:   opts.AllowDuplicate,
+		IgnoreAdvisories: opts.IgnoreAdvisories,
+		OnAdvisory:       warn,
+		ScanCommand:      viper.GetStringSlice("scan_command"),
+		ForceScan:        opts.ForceScan,
+		AllowUnscanned:   opts.AllowUnscanned,
+	}
+	if opts.NexusURL != "" {
+		ref, err := nexus.ParseModURL(opts.NexusURL)
+		if err != nil {
+			return res, fmt.Errorf("parse nexus url: %w", err)
+		}
+		iopts.NexusURL = &opts.NexusURL
+		iopts.NexusGameDomain = &ref.GameDomain
+		iopts.NexusModID = &ref.ModID
+	}
+	if opts.PageID != 0 {
+		iopts.PageID = &opts.PageID
+	}
+	if opts.ModName != "" {
+		iopts.ModName = &opts.ModName
+	}
+	if opts.FileLabel != "" {
+		iopts.FileLabel = &opts.FileLabel
+	}
+	if opts.Version != "" {
+		iopts.VersionString = &opts.Version
+	} else if opts.GuessVersion {
+		// if the nexus page is known then the filename has to agree or
+		// it's probably not a nexus download of this mod
+		if dn, ok := nexus.ParseDownloadFilename(path); ok &&
+			(iopts.NexusModID == nil || *iopts.NexusModID == dn.ModID) {
+			uploadedAt := dn.UploadedAt.Format("2006-01-02T15:04:05.000Z")
+			iopts.VersionString = &dn.Version
+			iopts.UploadedAt = &uploadedAt
+			iopts.VersionGuessed = true
+			res.GuessedVersion = dn.Version
+		}
+	}
+
+	prep := internal.PreparedArchive{PathToImport: path, Cleanup: func() {}}
+	if opts.Wrap {
+		var err error
+		if prep, err = internal.PrepareArchive(ctx, path, opts.ListTimeout); err != nil {
+			return res, err
+		}
+	} else {
+		ctxT, cancel := internal.ListContext(ctx, opts.ListTimeout)
+		err := internal.CheckArchive(ctxT, path)
+		cancel()
+		if err != nil {
+			return res, fmt.Errorf("%s is not a supported archive: %w", path, err)
+		}
+	}
+	defer prep.Cleanup()
+	iopts.ArchivePath = prep.PathToImport
+	iopts.Wrapped = prep.Wrapped
+	iopts.WrappedFrom = prep.WrappedFrom
+	iopts.MemberName = prep.MemberName
+	if prep.Wrapped {
+		warn("input was not a supported archive; wrapped into an archive for storage")
+	}
+
+	// Keep the readme/changelog/license text so that it can be shown later
+	// without extracting the archive; this is best-effort
+	if !prep.Wrapped {
+		ctxT, cancel := internal.ListContext(ctx, opts.ListTimeout)
+		docs, err := archive.FindDocs(ctxT, viper.GetString("bsdtar"), prep.PathToImport)
+		cancel()
+		if err != nil {
+			warn(fmt.Sprintf("couldn't read documentation files: %v", err))
+		}
+		iopts.Docs = docs
+		for _, d := range docs {
+			res.Docs = append(res.Docs, d.Path)
+		}
+	}
+
+	r, err := c.importArchive(ctx, iopts, opts.Progress)
+	if err != nil {
+		return res, err
+	}
+
+	res.PageID = r.PageID
+	res.FileID = r.FileID
+	res.VersionID = r.VersionID
+	res.SHA256 = r.SHA256
+	res.Size = r.Size
+	res.Wrapped = prep.Wrapped
+	return res, nil
+}
+
+// importArchive copies an archive into the archive store and records it, see
+// ImportMod.
+func (c *Client) importArchive(ctx context.Context, iopts importer.ImportOptions, progress func(done, total int64)) (ImportResult, error) {
+	bs := internal.BlobStoreFromConfig()
+	bs.Progress = progress
+
+	pageID, fileID, versionID, sha, size, err := importer.ImportArchive(ctx, c.db, c.q, bs, iopts)
+	var dup *importer.DuplicateError
+	if errors.As(err, &dup) {
+		return ImportResult{}, &DuplicateError{
+			PageID:    dup.PageID,
+			FileID:    dup.FileID,
+			VersionID: dup.VersionID,
+			SHA256:    dup.SHA256,
+			ModName:   dup.ModName,
+			FileLabel: dup.FileLabel,
+			Version:   dup.Version,
+			Restored:  dup.Restored,
+			err:       err,
+		}
+	}
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return ImportResult{
+		PageID:    pageID,
+		FileID:    fileID,
+		VersionID: versionID,
+		SHA256:    sha,
+		Size:      size,
+	}, nil
+}
+
+// packSource checks that dir can be packed and returns its name.
+func packSource(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("stat input: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return filepath.Base(abs), nil
+	}
+	return filepath.Base(dir), nil
+}
+
+// PackDir packs a directory into a compressed archive (as configured by
+// archive_compression) at output. The archive is reproducible: packing the
+// same files again produces the same archive.
+func PackDir(ctx context.Context, dir, output string) error {
+	if _, err := packSource(dir); err != nil {
+		return err
+	}
+	builder, err := internal.ArchiveBuilderFromConfig()
+	if err != nil {
+		return err
+	}
+	return builder.PackDir(ctx, dir, output)
+}
+
+// PackMod packs a directory (e.g., a mod that the user made) into an archive
+// (see PackDir) and imports it like ImportMod. Of the options only PageID,
+// ModName (which defaults to the name of the directory), FileLabel, Version,
+// AllowDuplicate, Progress, and OnWarning apply: the archive isn't scanned.
+func (c *Client) PackMod(ctx context.Context, gi Game, dir string, opts ImportOptions) (ImportResult, error) {
+	var res ImportResult
+
+	base, err := packSource(dir)
+	if err != nil {
+		return res, err
+	}
+	builder, err := internal.ArchiveBuilderFromConfig()
+	if err != nil {
+		return res, err
+	}
+
+	tmpDir := viper.GetString("tmp_dir")
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return res, fmt.Errorf("create tmp dir: %w", err)
+	}
+	work, err := os.MkdirTemp(tmpDir, "pack-")
+	if err != nil {
+		return res, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(work)
+
+	archiveName := base + builder.Compression.Extension()
+	packed := filepath.Join(work, archiveName)
+	if err := builder.PackDir(ctx, dir, packed); err != nil {
+		return res, err
+	}
+
+	iopts := importer.ImportOptions{
+		GameInstallID:    gi.ID,
+		ArchivePath:      packed,
+		OriginalBasename: archiveName,
+		AllowDuplicate:   opts.AllowDuplicate,
+	}
+	if opts.PageID != 0 {
+		iopts.PageID = &opts.PageID
+	} else {
+		name := base
+		if opts.ModName != "" {
+			name = opts.ModName
+		}
+		iopts.ModName = &name
+	}
+	if opts.FileLabel != "" {
+		iopts.FileLabel = &opts.FileLabel
+	}
+	if opts.Version != "" {
+		iopts.VersionString = &opts.Version
+	}
+
+	docs, err := archive.FindDocs(ctx, viper.GetString("bsdtar"), packed)
+	if err != nil {
+		w := fmt.Sprintf("couldn't read documentation files: %v", err)
+		res.Warnings = append(res.Warnings, w)
+		if opts.OnWarning != nil {
+			opts.OnWarning(w)
+		}
+	}
+	iopts.Docs = docs
+
+	r, err := c.importArchive(ctx, iopts, opts.Progress)
+	if err != nil {
+		return res, err
+	}
+	r.Warnings = res.Warnings
+	for _, d := range docs {
+		r.Docs = append(r.Docs, d.Path)
+	}
+	return r, nil
+}
@@ -0,0 +1,382 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/nexus"
+)
+
+// UpdateStatus is the result of the update check of a mod, see CheckUpdates.
+type UpdateStatus string
+
+const (
+	UpdateStatusUpToDate UpdateStatus = UpdateStatus(internal.UpdateStatusUpToDate)
+	UpdateStatusOutdated UpdateStatus = UpdateStatus(internal.UpdateStatusOutdated)
+	// up to date, but with new files upstream
+	UpdateStatusNewFiles UpdateStatus = UpdateStatus(internal.UpdateStatusNewFiles)
+	// no imported version string
+	UpdateStatusUnknown UpdateStatus = UpdateStatus(internal.UpdateStatusUnknown)
+	// out of api requests (or offline without a cached response)
+	UpdateStatusDeferred UpdateStatus = UpdateStatus(internal.UpdateStatusDeferred)
+	UpdateStatusError    UpdateStatus = UpdateStatus(internal.UpdateStatusError)
+)
+
+// NexusFile is a file on the Nexus Mods page of a mod.
+type NexusFile struct {
+	FileID  int64
+	Name    string
+	Version string
+	// e.g., "main", "optional", or "old_version"
+	Category string
+	FileName string
+	Size     int64
+	// zero if it isn't known
+	UploadedAt time.Time
+}
+
+func nexusFileFrom(f nexus.File) NexusFile {
+	nf := NexusFile{
+		FileID:   f.FileID,
+		Name:     f.Name,
+		Version:  f.Version,
+		Category: f.Category(),
+		FileName: f.FileName,
+		Size:     f.SizeInBytes,
+	}
+	if f.UploadedTimestamp > 0 {
+		nf.UploadedAt = time.Unix(f.UploadedTimestamp, 0)
+	}
+	return nf
+}
+
+// UpdateCheck is the result of the update check of a Nexus-linked mod.
+type UpdateCheck struct {
+	PageID     int64
+	ModName    string
+	GameDomain string
+	NexusModID int64
+
+	// the newest version across the imported archives of the mod and the
+	// version on nexus
+	ImportedVersion string
+	LatestVersion   string
+
+	// imported files that a newer upload replaced, and files that were
+	// uploaded since the last import that aren't updates of imported ones
+	Superseded []SupersededFile
+	NewFiles   []NexusFile
+
+	Status UpdateStatus
+	// why the mod was deferred or couldn't be checked
+	Err error
+}
+
+// FilesReplaced reports whether an outdated mod is outdated only because
+// its files were replaced upstream: the version of the mod didn't change.
+func (u UpdateCheck) FilesReplaced() bool {
+	return u.Status == UpdateStatusOutdated && u.ImportedVersion != "" &&
+		nexus.CompareVersions(u.ImportedVersion, u.LatestVersion) >= 0
+}
+
+// SupersededFile is a mod file whose (newest imported) nexus file was
+// replaced upstream.
+type SupersededFile struct {
+	FileID      int64
+	Label       string
+	NexusFileID int64
+
+	// the file that replaced it (the end of the chain of updates); NewFileID
+	// is 0 if nexus just moved it to the old files without saying what
+	// replaced it
+	NewFileID int64
+	NewFile   NexusFile
+}
+
+// CheckUpdates compares the newest imported version (and the imported files)
+// of every Nexus-linked mod of a game install with Nexus Mods. A failure to
+// check a single mod is recorded in its result instead of aborting the
+// check; once the API rate limit is reached the remaining mods are deferred.
+func (c *Client) CheckUpdates(ctx context.Context, gi Game) ([]UpdateCheck, error) {
+	client, err := c.nexusClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := internal.CheckNexusUpdates(ctx, c.q, client, gi.ID)
+	out := make([]UpdateCheck, 0, len(checks))
+	for _, uc := range checks {
+		u := UpdateCheck{
+			PageID:          uc.ModPageID,
+			ModName:         uc.ModName,
+			GameDomain:      uc.GameDomain,
+			NexusModID:      uc.NexusModID,
+			ImportedVersion: uc.ImportedVersion,
+			LatestVersion:   uc.LatestVersion,
+			Status:          UpdateStatus(uc.Status),
+			Err:             uc.Err,
+		}
+		for _, s := range uc.Superseded {
+			u.Superseded = append(u.Superseded, SupersededFile{
+				FileID:      s.ModFileID,
+				Label:       s.Label,
+				NexusFileID: s.FileID,
+				NewFileID:   s.NewFileID,
+				NewFile:     nexusFileFrom(s.NewFile),
+			})
+		}
+		for _, f := range uc.NewFiles {
+			u.NewFiles = append(u.NewFiles, nexusFileFrom(f))
+		}
+		out = append(out, u)
+	}
+	return out, err
+}
+
+// ChangelogEntry is the changelog of a version of a mod.
+type ChangelogEntry struct {
+	Version string
+	Changes []string
+}
+
+// Changelog returns the upstream changelog entries of a mod between its
+// imported and its latest version.
+func (c *Client) Changelog(ctx context.Context, u UpdateCheck) ([]ChangelogEntry, error) {
+	client, err := c.nexusClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := client.GetChangelogs(ctx, u.GameDomain, u.NexusModID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ChangelogEntry
+	for _, e := range internal.ChangelogBetween(logs, u.ImportedVersion, u.LatestVersion) {
+		entries = append(entries, ChangelogEntry(e))
+	}
+	return entries, nil
+}
+
+// BackfillStatus is the result of the backfill of a mod file version, see
+// BackfillNexus.
+type BackfillStatus string
+
+const (
+	BackfillStatusFilled BackfillStatus = BackfillStatus(internal.BackfillStatusFilled)
+	// nexus doesn't know more either
+	BackfillStatusUnchanged BackfillStatus = BackfillStatus(internal.BackfillStatusUnchanged)
+	// the file is gone from nexus
+	BackfillStatusNotFound BackfillStatus = BackfillStatus(internal.BackfillStatusNotFound)
+	// no nexus file has the archive's filename
+	BackfillStatusUnmatched BackfillStatus = BackfillStatus(internal.BackfillStatusUnmatched)
+	// out of api requests (or offline without a cached response)
+	BackfillStatusDeferred BackfillStatus = BackfillStatus(internal.BackfillStatusDeferred)
+	BackfillStatusError    BackfillStatus = BackfillStatus(internal.BackfillStatusError)
+)
+
+// NexusBackfill is what nexus knows about a mod file version that is missing
+// metadata. The version string, upload time, and notes are only set if they
+// were missing (or, for the version string, guessed from the filename) and
+// nexus has them.
+type NexusBackfill struct {
+	VersionID    int64
+	ModName      string
+	FileLabel    string
+	OriginalName string
+	GameDomain   string
+	NexusModID   int64
+	FileID       int64
+	// the version didn't have a file id: FileID was found by the filename
+	// of its archive
+	FileResolved bool
+
+	VersionString string
+	UploadedAt    string
+	UpstreamNotes string
+
+	Status BackfillStatus
+	Err    error
+}
+
+// BackfillNexus looks up the nexus file of every Nexus-linked mod file
+// version of a game install that is missing its file id, version string,
+// upload time, or upstream notes, and records what nexus knows. A failure to
+// look up a single mod is recorded in its result instead of aborting the
+// backfill; once the API rate limit is reached the remaining versions are
+// deferred.
+func (c *Client) BackfillNexus(ctx context.Context, gi Game) ([]NexusBackfill, error) {
+	client, err := c.nexusClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := internal.PlanNexusBackfill(ctx, c.q, client, gi.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan) == 0 {
+		return nil, nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.q.WithTx(tx)
+
+	out := make([]NexusBackfill, 0, len(plan))
+	for _, b := range plan {
+		if b.Status == internal.BackfillStatusFilled {
+			if err := internal.ApplyNexusBackfill(ctx, qtx, b); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, NexusBackfill{
+			VersionID:     b.VersionID,
+			ModName:       b.ModName,
+			FileLabel:     b.FileLabel,
+			OriginalName:  b.OriginalName,
+			GameDomain:    b.GameDomain,
+			NexusModID:    b.NexusModID,
+			FileID:        b.FileID,
+			FileResolved:  b.FileResolved,
+			VersionString: b.VersionString,
+			UploadedAt:    b.UploadedAt,
+			UpstreamNotes: b.UpstreamNotes,
+			Status:        BackfillStatus(b.Status),
+			Err:           b.Err,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return out, nil
+}
+
+// NexusRateLimits are the Nexus Mods API rate limits as of the last
+// request.
+type NexusRateLimits struct {
+	HourlyLimit     int64
+	HourlyRemaining int64
+	HourlyReset     time.Time
+
+	DailyLimit     int64
+	DailyRemaining int64
+	DailyReset     time.Time
+
+	ObservedAt time.Time
+}
+
+// Remaining returns how many requests can still be made at the given time
+// (modctl defers its requests once only nexus_rate_limit_reserve of them
+// are left). Windows whose reset time has passed count as fully replenished.
+func (rl NexusRateLimits) Remaining(now time.Time) int64 {
+	return nexus.RateLimits(rl).Remaining(now)
+}
+
+// NexusRateLimits returns the Nexus Mods API rate limits as of the last
+// request, or nil if no request was made yet.
+func (c *Client) NexusRateLimits(ctx context.Context) (*NexusRateLimits, error) {
+	rl, err := internal.LoadNexusRateLimits(ctx, c.q)
+	if err != nil {
+		return nil, fmt.Errorf("load rate limits: %w", err)
+	}
+	if rl == nil {
+		return nil, nil
+	}
+	limits := NexusRateLimits(*rl)
+	return &limits, nil
+}
+
+// RefreshNexusRateLimits makes a (cheap) request to Nexus Mods to update the
+// rate limits that NexusRateLimits returns.
+func (c *Client) RefreshNexusRateLimits(ctx context.Context) error {
+	client, err := c.nexusClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.ValidateKey(ctx)
+	return err
+}
+
+// ValidateNexusKey checks a Nexus Mods API key (before it's stored, see
+// CheckNexusKey for the stored one) and returns the name of the user that it
+// belongs to.
+func ValidateNexusKey(ctx context.Context, key string) (string, error) {
+	u, err := nexus.NewClient(key, Version, "").ValidateKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	return u.Name, nil
+}
+
+// CheckNexusKey returns an error if the Nexus Mods API can't be used because
+// there's no API key (see `modctl auth login`).
+func (c *Client) CheckNexusKey(ctx context.Context) error {
+	_, err := c.nexusClient(ctx)
+	return err
+}
+
+// ReportUpdates returns the outdated mods among checks that an earlier call
+// didn't report yet (an update to another version is new), and remembers
+// them as reported. Updates that were reported before for mods that weren't
+// checked (e.g., they were deferred) aren't forgotten, so that they aren't
+// reported again once they are.
+func (c *Client) ReportUpdates(ctx context.Context, checks []UpdateCheck) ([]UpdateCheck, error) {
+	var found []internal.ReportedUpdate
+	checked := map[int64]bool{}
+	for _, u := range checks {
+		if u.Status != UpdateStatusDeferred && u.Status != UpdateStatusError {
+			checked[u.PageID] = true
+		}
+		if u.Status == UpdateStatusOutdated {
+			found = append(found, internal.ReportedUpdate{ModPageID: u.PageID, LatestVersion: u.LatestVersion})
+		}
+	}
+
+	prev, err := internal.LoadReportedUpdates(ctx, c.q)
+	if err != nil {
+		return nil, err
+	}
+	fresh, keep := internal.DiffReportedUpdates(prev, found, checked)
+	if err := internal.SaveReportedUpdates(ctx, c.q, keep); err != nil {
+		return nil, err
+	}
+
+	isFresh := map[internal.ReportedUpdate]bool{}
+	for _, u := range fresh {
+		isFresh[u] = true
+	}
+	var out []UpdateCheck
+	for _, u := range checks {
+		if u.Status == UpdateStatusOutdated &&
+			isFresh[internal.ReportedUpdate{ModPageID: u.PageID, LatestVersion: u.LatestVersion}] {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,384 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/importer"
+	"github.com/mfinelli/modctl/internal/nexus"
+	"github.com/spf13/viper"
+)
+
+// how long listing a downloaded archive may take (see ImportOptions)
+const nexusListTimeout = 60 * time.Second
+
+// NexusMod is a mod on Nexus Mods with its files, see NexusFiles.
+type NexusMod struct {
+	Name       string
+	GameDomain string
+	ModID      int64
+	// the files tab of its page
+	FilesURL string
+
+	// by category, in the order of the files tab
+	Groups []NexusFileGroup
+	// nexus file id -> the label of the mod file that it was imported as
+	Imported map[int64]string
+
+	// the mod that it was imported as (0 if it wasn't imported yet)
+	PageID  int64
+	pageURL string
+	files   map[int64]nexus.File
+}
+
+// NexusFileGroup is the files of a Nexus mod in one category, newest upload
+// first.
+type NexusFileGroup struct {
+	Category string
+	Label    string
+	Files    []NexusFile
+}
+
+// File returns the file of the mod with the given nexus file id.
+func (m *NexusMod) File(id int64) (NexusFile, bool) {
+	f, ok := m.files[id]
+	if !ok {
+		return NexusFile{}, false
+	}
+	return nexusFileFrom(f), true
+}
+
+// NexusFiles returns every file on the Nexus Mods page of a mod. The mod is
+// either a nexus mod page url (that doesn't have to be imported yet) or a mod
+// of the game install with nexus metadata (see ModPage).
+func (c *Client) NexusFiles(ctx context.Context, gi Game, mod string) (*NexusMod, error) {
+	m := &NexusMod{Imported: map[int64]string{}, files: map[int64]nexus.File{}}
+
+	var ref nexus.ModRef
+	if r, perr := nexus.ParseModURL(mod); perr == nil {
+		ref, m.pageURL = r, mod
+		p, err := c.q.GetModPageByNexus(ctx, dbq.GetModPageByNexusParams{
+			GameInstallID:   gi.ID,
+			NexusGameDomain: sql.NullString{String: ref.GameDomain, Valid: true},
+			NexusModID:      sql.NullInt64{Int64: ref.ModID, Valid: true},
+		})
+		if err == nil {
+			m.PageID = p.ID
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("lookup nexus mod page: %w", err)
+		}
+	} else {
+		p, err := internal.ResolveModPageArg(ctx, c.q, gi.ID, mod)
+		if err != nil {
+			return nil, err
+		}
+		if !p.NexusGameDomain.Valid || !p.NexusModID.Valid {
+			return nil, fmt.Errorf("mod %q has no nexus metadata; pass its nexus url instead", p.Name)
+		}
+		ref = nexus.ModRef{GameDomain: p.NexusGameDomain.String, ModID: p.NexusModID.Int64}
+		m.PageID, m.pageURL = p.ID, p.SourceUrl.String
+	}
+	m.GameDomain, m.ModID, m.FilesURL = ref.GameDomain, ref.ModID, ref.FilesURL()
+
+	if m.PageID != 0 {
+		files, err := c.q.ListModFilesByPage(ctx, m.PageID)
+		if err != nil {
+			return nil, fmt.Errorf("list mod files: %w", err)
+		}
+		for _, f := range files {
+			if f.NexusFileID.Valid {
+				m.Imported[f.NexusFileID.Int64] = f.Label
+			}
+		}
+	}
+
+	client, err := c.nexusClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.GetMod(ctx, ref.GameDomain, ref.ModID)
+	if err != nil {
+		return nil, fmt.Errorf("get nexus mod: %w", err)
+	}
+	list, err := client.GetFileList(ctx, ref.GameDomain, ref.ModID)
+	if err != nil {
+		return nil, fmt.Errorf("list nexus files: %w", err)
+	}
+	if err := internal.SaveNexusFileUpdates(ctx, c.q, ref.GameDomain, ref.ModID, list.Updates); err != nil {
+		return nil, err
+	}
+
+	m.Name = info.Name
+	if m.Name == "" {
+		m.Name = fmt.Sprintf("%s/%d", ref.GameDomain, ref.ModID)
+	}
+
+	for _, g := range nexus.GroupFiles(list.Files) {
+		group := NexusFileGroup{Category: g.Category, Label: g.Label}
+		for _, f := range g.Files {
+			m.files[f.FileID] = f
+			group.Files = append(group.Files, nexusFileFrom(f))
+		}
+		m.Groups = append(m.Groups, group)
+	}
+	return m, nil
+}
+
+// NexusImportOptions are the optional settings of ImportNexusFiles.
+type NexusImportOptions struct {
+	// see ImportOptions
+	IgnoreAdvisories bool
+	ForceScan        bool
+	AllowUnscanned   bool
+
+	// Progress, if set, is called while the files are downloaded (all of
+	// them together)
+	Progress func(done, total int64)
+	// OnWarning, if set, is called with e.g. what the advisory feed knows
+	// about a file as soon as it comes up
+	OnWarning func(warning string)
+}
+
+// NexusImport is the result of the import of a file of a Nexus mod.
+type NexusImport struct {
+	File   NexusFile
+	Result ImportResult
+	// why it couldn't be downloaded or imported (a *DuplicateError if it
+	// was already imported)
+	Err error
+}
+
+// ImportNexusFiles downloads files of a Nexus mod (which needs a premium
+// account) and imports them as mod files of the mod, labeled with their name
+// on nexus; the first import creates the mod if it wasn't imported yet. Up
+// to download_concurrency files are downloaded at the same time. A file that
+// fails is recorded in its result instead of aborting the others; if the
+// context is cancelled the results so far are returned with its error.
+func (c *Client) ImportNexusFiles(ctx context.Context, gi Game, m *NexusMod, files []NexusFile, opts NexusImportOptions) ([]NexusImport, error) {
+	todo := make([]nexus.File, 0, len(files))
+	for _, f := range files {
+		nf, ok := m.files[f.FileID]
+		if !ok {
+			return nil, fmt.Errorf("nexus file %d isn't a file of %s", f.FileID, m.Name)
+		}
+		todo = append(todo, nf)
+	}
+
+	client, err := c.nexusClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	downloads, err := c.downloadNexusFiles(ctx, client, m, todo, opts.Progress)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, dl := range downloads {
+			if dl.cleanup != nil {
+				dl.cleanup()
+			}
+		}
+	}()
+
+	out := make([]NexusImport, 0, len(todo))
+	for i, f := range todo {
+		dl := downloads[i]
+		if errors.Is(dl.err, context.Canceled) {
+			return out, dl.err
+		}
+		imp := NexusImport{File: nexusFileFrom(f), Err: dl.err}
+		if dl.err == nil {
+			imp.Result, imp.Err = c.importNexusDownload(ctx, gi, m, f, dl.path, opts)
+			dl.cleanup()
+			if errors.Is(imp.Err, context.Canceled) {
+				return out, imp.Err
+			}
+			if imp.Err == nil {
+				// the first import creates the mod if it's new
+				m.PageID = imp.Result.PageID
+				m.Imported[f.FileID] = f.Name
+			}
+		}
+		out = append(out, imp)
+	}
+	return out, nil
+}
+
+// nexusDownload is a downloaded nexus file that is waiting to be imported;
+// cleanup removes it.
+type nexusDownload struct {
+	path    string
+	cleanup func()
+	err     error
+}
+
+// downloadNexusFiles downloads files of a nexus mod, up to
+// download_concurrency of them at the same time (the client paces them
+// further, see internal.NewDownloadManager). The downloads are in the order
+// of the files.
+func (c *Client) downloadNexusFiles(ctx context.Context, client *nexus.Client, m *NexusMod, files []nexus.File, progress func(done, total int64)) ([]nexusDownload, error) {
+	policy, err := internal.DownloadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	n := policy.Concurrency
+	if n <= 0 || n > len(files) {
+		n = len(files)
+	}
+
+	var total int64
+	for _, f := range files {
+		total += f.SizeInBytes
+	}
+	var mu sync.Mutex
+	done := make([]int64, len(files))
+
+	dir := internal.BlobStoreFromConfig().TmpDir
+	out := make([]nexusDownload, len(files))
+	slots := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i, f := range files {
+		var p func(done, total int64)
+		if progress != nil {
+			p = func(d, _ int64) {
+				mu.Lock()
+				defer mu.Unlock()
+				done[i] = d
+				var sum int64
+				for _, d := range done {
+					sum += d
+				}
+				progress(sum, total)
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			out[i].path, out[i].cleanup, out[i].err = downloadNexusFile(ctx, client, dir, m, f, p)
+		}()
+	}
+	wg.Wait()
+
+	return out, nil
+}
+
+// downloadNexusFile downloads a file of a nexus mod into dir, under its name
+// on nexus (if it has to be wrapped it's the name of the file in the
+// archive).
+func downloadNexusFile(ctx context.Context, client *nexus.Client, dir string, m *NexusMod, f nexus.File, progress func(done, total int64)) (string, func(), error) {
+	path, _, err := internal.DownloadNexusFile(ctx, client, dir, m.GameDomain, m.ModID, f, progress)
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := filepath.Base(f.FileName)
+	if f.FileName == "" || name == "." || name == ".." || name == string(filepath.Separator) {
+		return path, func() { os.Remove(path) }, nil
+	}
+
+	tmp, err := os.MkdirTemp(dir, "nexus-*")
+	if err != nil {
+		os.Remove(path)
+		return "", nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	named := filepath.Join(tmp, name)
+	if err := os.Rename(path, named); err != nil {
+		os.Remove(path)
+		os.RemoveAll(tmp)
+		return "", nil, fmt.Errorf("rename download: %w", err)
+	}
+	return named, func() { os.RemoveAll(tmp) }, nil
+}
+
+// importNexusDownload imports a downloaded nexus file (wrapping it into an
+// archive first if it isn't one, like ImportMod).
+func (c *Client) importNexusDownload(ctx context.Context, gi Game, m *NexusMod, f nexus.File, path string, opts NexusImportOptions) (ImportResult, error) {
+	iopts := importer.ImportOptions{
+		GameInstallID:    gi.ID,
+		OriginalBasename: f.FileName,
+		NexusGameDomain:  &m.GameDomain,
+		NexusModID:       &m.ModID,
+		NexusFileID:      &f.FileID,
+		ModName:          &m.Name,
+		IgnoreAdvisories: opts.IgnoreAdvisories,
+		OnAdvisory:       opts.OnWarning,
+		ScanCommand:      viper.GetStringSlice("scan_command"),
+		ForceScan:        opts.ForceScan,
+		AllowUnscanned:   opts.AllowUnscanned,
+	}
+	if m.PageID != 0 {
+		iopts.PageID = &m.PageID
+	}
+	if m.pageURL != "" {
+		iopts.NexusURL = &m.pageURL
+	}
+	if cat := f.Category(); cat != "" {
+		iopts.FileCategory = &cat
+	}
+	if f.Name != "" {
+		iopts.FileLabel = &f.Name
+	}
+	if f.Version != "" {
+		iopts.VersionString = &f.Version
+	}
+	if f.UploadedTimestamp > 0 {
+		uploadedAt := time.Unix(f.UploadedTimestamp, 0).UTC().Format("2006-01-02T15:04:05.000Z")
+		iopts.UploadedAt = &uploadedAt
+	}
+
+	prep, err := internal.PrepareArchive(ctx, path, nexusListTimeout)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	defer prep.Cleanup()
+
+	iopts.ArchivePath = prep.PathToImport
+	iopts.Wrapped = prep.Wrapped
+	iopts.WrappedFrom = prep.WrappedFrom
+	iopts.MemberName = prep.MemberName
+
+	// the documentation files are best-effort, like in ImportMod
+	if !prep.Wrapped {
+		ctxT, cancel := context.WithTimeout(ctx, nexusListTimeout)
+		docs, err := archive.FindDocs(ctxT, viper.GetString("bsdtar"), prep.PathToImport)
+		cancel()
+		if err == nil {
+			iopts.Docs = docs
+		}
+	}
+
+	res, err := c.importArchive(ctx, iopts, nil)
+	res.Wrapped = prep.Wrapped
+	return res, err
+}
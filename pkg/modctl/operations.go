@@ -0,0 +1,199 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/spf13/viper"
+)
+
+// The verdicts of an operation note (see AddOperationNote).
+const (
+	NoteStatusPass = internal.NoteStatusPass
+	NoteStatusFail = internal.NoteStatusFail
+)
+
+// Operation is an operation that modctl ran (e.g., an apply).
+type Operation struct {
+	ID            int64  `json:"id"`
+	GameInstallID int64  `json:"game_install_id"`
+	ProfileID     int64  `json:"profile_id,omitempty"`
+	Profile       string `json:"profile,omitempty"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	StartedAt     string `json:"started_at"`
+	FinishedAt    string `json:"finished_at,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// RecentOperations returns the newest operations, newest first.
+func (c *Client) RecentOperations(ctx context.Context, limit int64) ([]Operation, error) {
+	rows, err := c.q.ListRecentOperations(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list operations: %w", err)
+	}
+
+	ops := make([]Operation, 0, len(rows))
+	for _, r := range rows {
+		ops = append(ops, Operation{
+			ID:            r.ID,
+			GameInstallID: r.GameInstallID,
+			ProfileID:     r.ProfileID.Int64,
+			Profile:       r.ProfileName.String,
+			Type:          r.OpType,
+			Status:        r.Status,
+			StartedAt:     r.StartedAt,
+			FinishedAt:    r.FinishedAt.String,
+			Message:       r.Message.String,
+		})
+	}
+	return ops, nil
+}
+
+// OperationLogEntry is an operation in the operation log, see OperationLog.
+type OperationLogEntry struct {
+	ID         int64  `json:"id"`
+	Game       string `json:"game"`
+	Profile    string `json:"profile,omitempty"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at,omitempty"`
+	// for applies, the mods of the revision that they deployed (as
+	// "<mod> / <file>", highest priority first)
+	Mods  []string        `json:"mods,omitempty"`
+	Notes []OperationNote `json:"notes,omitempty"`
+}
+
+// OperationNote is a note about an operation.
+type OperationNote struct {
+	Status    string `json:"status,omitempty"`
+	Note      string `json:"note,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Verdict returns the status of the newest note that has one ("" if
+// none does).
+func (e OperationLogEntry) Verdict() string {
+	for i := len(e.Notes) - 1; i >= 0; i-- {
+		if e.Notes[i].Status != "" {
+			return e.Notes[i].Status
+		}
+	}
+	return ""
+}
+
+// OperationLogFilter selects the operations of OperationLog.
+type OperationLogFilter struct {
+	// only the operations of this game install (0: of every game)
+	GameInstallID int64
+	// only the operations with notes
+	NotedOnly bool
+	// only the operations with a note that contains this (ignoring case)
+	Search string
+	// how many operations (0: all of them)
+	Limit int64
+}
+
+// OperationLog returns the newest operations (newest first) with their
+// notes.
+func (c *Client) OperationLog(ctx context.Context, f OperationLogFilter) ([]OperationLogEntry, error) {
+	entries, err := internal.OperationLog(ctx, c.q, internal.OperationLogFilter(f))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]OperationLogEntry, 0, len(entries))
+	for _, e := range entries {
+		oe := OperationLogEntry{
+			ID:         e.ID,
+			Game:       e.Game,
+			Profile:    e.Profile,
+			Type:       e.Type,
+			Status:     e.Status,
+			StartedAt:  e.StartedAt,
+			FinishedAt: e.FinishedAt,
+			Mods:       e.Mods,
+		}
+		for _, n := range e.Notes {
+			oe.Notes = append(oe.Notes, OperationNote(n))
+		}
+		out = append(out, oe)
+	}
+	return out, nil
+}
+
+// AddOperationNote records what testing the revision that an apply
+// deployed found (e.g., "crashes in Whiterun") and, unless status is empty,
+// whether it worked (NoteStatusPass or NoteStatusFail).
+func (c *Client) AddOperationNote(ctx context.Context, opID int64, status, note string) error {
+	return internal.AddOperationNote(ctx, c.q, opID, status, note)
+}
+
+// OperationNotes returns the notes of an operation, oldest first.
+func (c *Client) OperationNotes(ctx context.Context, opID int64) ([]OperationNote, error) {
+	rows, err := c.q.ListOperationNotes(ctx, opID)
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+
+	notes := make([]OperationNote, 0, len(rows))
+	for _, n := range rows {
+		notes = append(notes, OperationNote{
+			Status:    n.Status.String,
+			Note:      n.Note,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return notes, nil
+}
+
+// OperationReport returns the report of an operation: what it changed, as
+// text or, with asJSON, as JSON. It's the report that was written when the
+// operation finished (see the reports_dir config option) or, if there isn't
+// one, built from the database.
+func (c *Client) OperationReport(ctx context.Context, opID int64, asJSON bool) ([]byte, error) {
+	out, err := internal.ReadOperationReport(viper.GetString("reports_dir"), opID, asJSON)
+	if err == nil {
+		return out, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read report of operation %d: %w", opID, err)
+	}
+
+	r, err := internal.BuildOperationReport(ctx, c.q, opID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asJSON {
+		js, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode report: %w", err)
+		}
+		return append(js, '\n'), nil
+	}
+	return []byte(r.Text()), nil
+}
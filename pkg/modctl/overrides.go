@@ -0,0 +1,154 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// Override is a file that a profile deploys in place of the one from its
+// mods (or the game).
+type Override struct {
+	Target    string
+	RelPath   string
+	SHA256    string
+	Size      int64
+	Notes     string
+	Template  bool
+	UpdatedAt string
+}
+
+// Overrides returns the overrides of a profile.
+func (c *Client) Overrides(ctx context.Context, p Profile) ([]Override, error) {
+	rows, err := c.q.ListOverridesForProfile(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list overrides: %w", err)
+	}
+
+	overrides := make([]Override, 0, len(rows))
+	for _, o := range rows {
+		overrides = append(overrides, Override{
+			Target:    o.TargetName,
+			RelPath:   o.Relpath,
+			SHA256:    o.BlobSha256,
+			Size:      o.SizeBytes,
+			Notes:     o.Notes.String,
+			Template:  o.IsTemplate != 0,
+			UpdatedAt: o.UpdatedAt,
+		})
+	}
+	return overrides, nil
+}
+
+// OverrideOptions are the optional settings of SetOverride.
+type OverrideOptions struct {
+	Notes string
+	// expand the ${name} variables of the file whenever it's deployed
+	Template bool
+
+	// OnWarning, if set, is called when a template can't be expanded for the
+	// game install at hand (the variables can differ from one install to the
+	// other, so that doesn't fail)
+	OnWarning func(warning string)
+}
+
+// SetOverride stores the regular file at src as the override of relpath in
+// the named target of a profile, replacing any previous override of the same
+// path. It returns the override with its normalized relpath.
+func (c *Client) SetOverride(ctx context.Context, gi Game, p Profile, target, relpath, src string, opts OverrideOptions) (Override, error) {
+	if st, err := os.Stat(src); err != nil {
+		return Override{}, err
+	} else if !st.Mode().IsRegular() {
+		return Override{}, fmt.Errorf("%s is not a regular file", src)
+	}
+
+	prow, err := c.unlockedProfile(ctx, p)
+	if err != nil {
+		return Override{}, err
+	}
+	t, rel, err := c.overridePath(ctx, gi, target, relpath)
+	if err != nil {
+		return Override{}, err
+	}
+
+	sha, err := internal.SetOverride(ctx, c.db, c.q, internal.BlobStoreFromConfig(),
+		p.ID, t.ID, rel, src, opts.Notes, opts.Template)
+	if err != nil {
+		return Override{}, err
+	}
+
+	if opts.Template && opts.OnWarning != nil {
+		grow, err := c.gameRow(ctx, gi)
+		if err != nil {
+			return Override{}, err
+		}
+		vars, err := internal.OverrideVars(grow, prow)
+		if err == nil {
+			content, rerr := os.ReadFile(src)
+			if rerr != nil {
+				return Override{}, rerr
+			}
+			_, err = internal.RenderOverride(content, vars)
+		}
+		if err != nil {
+			opts.OnWarning(fmt.Sprintf("the template can't be expanded for %s: %v", gi.DisplayName, err))
+		}
+	}
+
+	return Override{
+		Target:   t.Name,
+		RelPath:  rel,
+		SHA256:   sha,
+		Notes:    opts.Notes,
+		Template: opts.Template,
+	}, nil
+}
+
+// UnsetOverride removes the override of relpath in the named target from a
+// profile. It returns the normalized relpath.
+func (c *Client) UnsetOverride(ctx context.Context, gi Game, p Profile, target, relpath string) (string, error) {
+	if _, err := c.unlockedProfile(ctx, p); err != nil {
+		return "", err
+	}
+	t, rel, err := c.overridePath(ctx, gi, target, relpath)
+	if err != nil {
+		return "", err
+	}
+
+	return rel, internal.UnsetOverride(ctx, c.db, c.q, internal.BlobStoreFromConfig(), p.ID, t.ID, rel)
+}
+
+// overridePath looks up the target of an override and normalizes its path.
+func (c *Client) overridePath(ctx context.Context, gi Game, target, relpath string) (Target, string, error) {
+	t, err := c.Target(ctx, gi, target)
+	if err != nil {
+		return t, "", err
+	}
+
+	rel, err := plan.NormalizeRelPath(relpath)
+	if err != nil {
+		return t, "", fmt.Errorf("invalid path %q: %w", relpath, err)
+	}
+	return t, rel, nil
+}
@@ -0,0 +1,119 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/loot"
+)
+
+// Plugin is a plugin in a load order.
+type Plugin struct {
+	Name    string
+	Enabled bool
+}
+
+// LoadOrder is the stored plugin load order of a profile.
+type LoadOrder struct {
+	// in load order, empty if the profile doesn't have one yet
+	Plugins []Plugin
+	// where it came from (e.g., "loot")
+	Source    string
+	UpdatedAt string
+}
+
+// LoadOrder returns the stored plugin load order of a profile.
+func (c *Client) LoadOrder(ctx context.Context, p Profile) (LoadOrder, error) {
+	rows, err := c.q.ListPluginOrderForProfile(ctx, p.ID)
+	if err != nil {
+		return LoadOrder{}, fmt.Errorf("list plugin order: %w", err)
+	}
+
+	var order LoadOrder
+	for _, r := range rows {
+		order.Plugins = append(order.Plugins, Plugin{Name: r.PluginName, Enabled: r.Enabled != 0})
+	}
+	if len(rows) > 0 {
+		order.Source = rows[0].Source
+		order.UpdatedAt = rows[0].UpdatedAt
+	}
+	return order, nil
+}
+
+// SortPlugins runs LOOT (the loot_command config option) on the load order
+// of a profile and stores the result. It returns the load order from before
+// (nil if the profile didn't have one) and after sorting.
+func (c *Client) SortPlugins(ctx context.Context, gi Game, p Profile) (before, after []Plugin, err error) {
+	grow, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, nil, err
+	}
+	prow, err := c.unlockedProfile(ctx, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, a, err := internal.SortPlugins(ctx, c.db, c.q, grow, prow)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pluginsFrom(b), pluginsFrom(a), nil
+}
+
+// WritePlugins writes the stored load order of a profile to the game's
+// plugins.txt (e.g., to restore it after the game or another tool changed
+// it). It returns the path that it wrote and the number of plugins.
+func (c *Client) WritePlugins(ctx context.Context, gi Game, p Profile) (string, int, error) {
+	grow, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return "", 0, err
+	}
+
+	_, pluginsTxt, err := internal.PluginsTxt(grow)
+	if err != nil {
+		return "", 0, err
+	}
+
+	plugins, err := internal.LoadPluginOrder(ctx, c.q, p.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(plugins) == 0 {
+		return "", 0, fmt.Errorf("profile %q doesn't have a load order yet; run `modctl plugins sort`", p.Name)
+	}
+
+	if err := loot.WritePlugins(pluginsTxt, plugins); err != nil {
+		return "", 0, err
+	}
+	return pluginsTxt, len(plugins), nil
+}
+
+func pluginsFrom(plugins []loot.Plugin) []Plugin {
+	if plugins == nil {
+		return nil
+	}
+	out := make([]Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		out = append(out, Plugin(p))
+	}
+	return out
+}
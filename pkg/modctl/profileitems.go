@@ -0,0 +1,448 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// The symlink policies of a mod in a profile (see SetSymlinks).
+const (
+	// deploy the symlinks of its archive as copies of what they point to
+	SymlinksCopy = plan.SymlinksCopy
+	// leave them out
+	SymlinksSkip = plan.SymlinksSkip
+)
+
+// isUniqueViolation reports whether err is a violation of a UNIQUE
+// constraint.
+func isUniqueViolation(err error) bool {
+	var se sqlite3.Error
+	return errors.As(err, &se) && se.Code == sqlite3.ErrConstraint &&
+		se.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+// unlockedProfile looks up the current row of a profile and returns an error
+// if it's locked (see SetProfileLocked).
+func (c *Client) unlockedProfile(ctx context.Context, p Profile) (dbq.Profile, error) {
+	row, err := c.profileRow(ctx, p)
+	if err != nil {
+		return row, err
+	}
+	return row, internal.CheckProfileUnlocked(&row)
+}
+
+// CreateProfile creates an (empty) profile. gameVersion is the version of the
+// game that the profile is built against (empty: any).
+func (c *Client) CreateProfile(ctx context.Context, gi Game, name, description, gameVersion string) (Profile, error) {
+	var desc sql.NullString
+	if description != "" {
+		desc = sql.NullString{String: description, Valid: true}
+	}
+	var version sql.NullString
+	if v := strings.TrimSpace(gameVersion); v != "" {
+		version = sql.NullString{String: v, Valid: true}
+	}
+
+	id, err := c.q.CreateProfile(ctx, dbq.CreateProfileParams{
+		GameInstallID: gi.ID,
+		Name:          name,
+		Description:   desc,
+		GameVersion:   version,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return Profile{}, fmt.Errorf("profile %q already exists for this game", name)
+		}
+		return Profile{}, fmt.Errorf("create profile: %w", err)
+	}
+
+	row, err := c.q.GetProfileByID(ctx, id)
+	if err != nil {
+		return Profile{}, fmt.Errorf("lookup profile %q: %w", name, err)
+	}
+	return profileFromRow(row), nil
+}
+
+// ProfileCopyResult is what CopyProfile made.
+type ProfileCopyResult struct {
+	ProfileID int64
+	Items     int
+
+	// how the mod versions of the items were found on the other install: an
+	// imported version of the same archive, a new version under the mod page
+	// with the same nexus mod, or a new version under a new mod page
+	Reused  int
+	Linked  int
+	Created int
+
+	Overrides int
+	Plugins   int
+	// overrides of targets (target/relpath) that the other install doesn't
+	// have
+	SkippedOverrides []string
+}
+
+// CopyProfile copies a profile to another install of the same game under the
+// given name, with its mods (in the same order and state), plugin order, and
+// overrides. The mods are created on the other install if they aren't
+// imported there; the archives are shared.
+func (c *Client) CopyProfile(ctx context.Context, gi Game, p Profile, to Game, name string) (ProfileCopyResult, error) {
+	from, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return ProfileCopyResult{}, err
+	}
+	dest, err := c.gameRow(ctx, to)
+	if err != nil {
+		return ProfileCopyResult{}, err
+	}
+	row, err := c.profileRow(ctx, p)
+	if err != nil {
+		return ProfileCopyResult{}, err
+	}
+
+	res, err := internal.CopyProfile(ctx, c.db, c.q, from, row, dest, name)
+	return ProfileCopyResult(res), err
+}
+
+// RenameProfile renames a profile.
+func (c *Client) RenameProfile(ctx context.Context, p Profile, name string) error {
+	if err := c.q.RenameProfile(ctx, dbq.RenameProfileParams{
+		Name: name,
+		ID:   p.ID,
+	}); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("profile %q already exists for this game", name)
+		}
+		return fmt.Errorf("rename profile: %w", err)
+	}
+	return nil
+}
+
+// DeleteProfile deletes a profile that isn't locked. It doesn't change the
+// files of the game even if the profile is applied.
+func (c *Client) DeleteProfile(ctx context.Context, p Profile) error {
+	if _, err := c.unlockedProfile(ctx, p); err != nil {
+		return err
+	}
+	if err := c.q.DeleteProfileByID(ctx, p.ID); err != nil {
+		return fmt.Errorf("delete profile: %w", err)
+	}
+	return nil
+}
+
+// SetProfileLocked locks a profile (so that its mods can't be changed and
+// prune keeps their versions) or unlocks it. It reports whether that changed
+// anything.
+func (c *Client) SetProfileLocked(ctx context.Context, p Profile, locked bool) (bool, error) {
+	var n int64
+	var err error
+	if locked {
+		n, err = c.q.LockProfile(ctx, p.ID)
+	} else {
+		n, err = c.q.UnlockProfile(ctx, p.ID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("update profile: %w", err)
+	}
+	return n != 0, nil
+}
+
+// SetProfileGameVersion sets the version of the game that a profile is built
+// against; an empty version clears it.
+func (c *Client) SetProfileGameVersion(ctx context.Context, p Profile, version string) error {
+	var v sql.NullString
+	if version != "" {
+		v = sql.NullString{String: version, Valid: true}
+	}
+	if err := c.q.SetProfileGameVersion(ctx, dbq.SetProfileGameVersionParams{
+		GameVersion: v,
+		ID:          p.ID,
+	}); err != nil {
+		return fmt.Errorf("set game version: %w", err)
+	}
+	return nil
+}
+
+// InstalledGameVersion returns the installed version of the game (the build
+// id of Steam games), or "" if it can't be detected.
+func (c *Client) InstalledGameVersion(ctx context.Context, gi Game) (string, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return "", err
+	}
+	v, err := internal.GameVersion(row)
+	if err != nil {
+		return "", fmt.Errorf("detect game version: %w", err)
+	}
+	return v, nil
+}
+
+// AddOptions are the optional settings of AddToProfile.
+type AddOptions struct {
+	// the priority of the mod (0: after every other mod)
+	Priority int64
+	// add it disabled
+	Disabled bool
+}
+
+// AddToProfile adds a mod file version to a profile and returns the id of its
+// item in the profile and the priority that it got.
+func (c *Client) AddToProfile(ctx context.Context, p Profile, versionID int64, opts AddOptions) (itemID, priority int64, err error) {
+	if _, err := c.unlockedProfile(ctx, p); err != nil {
+		return 0, 0, err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.q.WithTx(tx)
+
+	// nicer than a foreign key failure
+	if _, err := qtx.ExistsModFileVersion(ctx, versionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, fmt.Errorf("mod file version %d not found", versionID)
+		}
+		return 0, 0, fmt.Errorf("check mod file version: %w", err)
+	}
+
+	priority = opts.Priority
+	if priority == 0 {
+		maxPrio, err := qtx.GetMaxPriorityForProfile(ctx, p.ID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("get max priority: %w", err)
+		}
+		priority = maxPrio + 1
+	} else {
+		_, err := qtx.IsPriorityTaken(ctx, dbq.IsPriorityTakenParams{
+			ProfileID: p.ID,
+			Priority:  priority,
+		})
+		if err == nil {
+			return 0, 0, fmt.Errorf("priority %d is already used in profile %q", priority, p.Name)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, fmt.Errorf("check priority: %w", err)
+		}
+	}
+
+	enabled := int64(1)
+	if opts.Disabled {
+		enabled = 0
+	}
+
+	itemID, err = qtx.CreateProfileItem(ctx, dbq.CreateProfileItemParams{
+		ProfileID:        p.ID,
+		ModFileVersionID: versionID,
+		Enabled:          enabled,
+		Priority:         priority,
+	})
+	if err != nil {
+		// a duplicate priority was caught above (unless there was a
+		// race), so it's most likely a duplicate version
+		if isUniqueViolation(err) {
+			if opts.Priority != 0 {
+				return 0, 0, fmt.Errorf("could not add version %d to profile %q (duplicate version or priority conflict)", versionID, p.Name)
+			}
+			return 0, 0, fmt.Errorf("version %d is already in profile %q", versionID, p.Name)
+		}
+		return 0, 0, fmt.Errorf("add to profile: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("commit: %w", err)
+	}
+	return itemID, priority, nil
+}
+
+// profileItem looks up the item of a mod file version in a profile that
+// isn't locked.
+func (c *Client) profileItem(ctx context.Context, p Profile, versionID int64) (dbq.GetProfileItemByVersionRow, error) {
+	if _, err := c.unlockedProfile(ctx, p); err != nil {
+		return dbq.GetProfileItemByVersionRow{}, err
+	}
+
+	item, err := c.q.GetProfileItemByVersion(ctx, dbq.GetProfileItemByVersionParams{
+		ProfileID:        p.ID,
+		ModFileVersionID: versionID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return item, fmt.Errorf("version %d is not in profile %q", versionID, p.Name)
+	}
+	if err != nil {
+		return item, fmt.Errorf("lookup profile item: %w", err)
+	}
+	return item, nil
+}
+
+// RemoveFromProfile removes a mod file version from a profile.
+func (c *Client) RemoveFromProfile(ctx context.Context, p Profile, versionID int64) error {
+	item, err := c.profileItem(ctx, p, versionID)
+	if err != nil {
+		return err
+	}
+	if err := c.q.DeleteProfileItemByID(ctx, item.ID); err != nil {
+		return fmt.Errorf("remove from profile: %w", err)
+	}
+	return nil
+}
+
+// SetEnabled enables or disables a mod file version in a profile. It reports
+// whether that changed anything.
+func (c *Client) SetEnabled(ctx context.Context, p Profile, versionID int64, enabled bool) (bool, error) {
+	item, err := c.profileItem(ctx, p, versionID)
+	if err != nil {
+		return false, err
+	}
+
+	want := int64(0)
+	if enabled {
+		want = 1
+	}
+	if item.Enabled == want {
+		return false, nil
+	}
+
+	if err := c.q.SetProfileItemEnabled(ctx, dbq.SetProfileItemEnabledParams{
+		Enabled: want,
+		ID:      item.ID,
+	}); err != nil {
+		return false, fmt.Errorf("update enabled: %w", err)
+	}
+	return true, nil
+}
+
+// SetFileHidden hides a file of a mod file version in a profile (so that a
+// lower priority mod deploys it, or nothing does) or unhides it. The file is
+// its destination path; the normalized path is returned with whether that
+// changed anything.
+func (c *Client) SetFileHidden(ctx context.Context, p Profile, versionID int64, relpath string, hidden bool) (string, bool, error) {
+	rel, err := plan.NormalizeRelPath(relpath)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid path %q: %w", relpath, err)
+	}
+
+	item, err := c.profileItem(ctx, p, versionID)
+	if err != nil {
+		return rel, false, err
+	}
+
+	var n int64
+	if hidden {
+		n, err = c.q.HideProfileItemFile(ctx, dbq.HideProfileItemFileParams{
+			ProfileItemID: item.ID,
+			Relpath:       rel,
+		})
+	} else {
+		n, err = c.q.UnhideProfileItemFile(ctx, dbq.UnhideProfileItemFileParams{
+			ProfileItemID: item.ID,
+			Relpath:       rel,
+		})
+	}
+	if err != nil {
+		return rel, false, fmt.Errorf("update hidden files: %w", err)
+	}
+	return rel, n != 0, nil
+}
+
+// SetSymlinks sets what happens to the symlinks in the archive of a mod file
+// version in a profile (SymlinksCopy or SymlinksSkip). It reports whether
+// that changed anything.
+func (c *Client) SetSymlinks(ctx context.Context, p Profile, versionID int64, policy string) (bool, error) {
+	if policy != SymlinksCopy && policy != SymlinksSkip {
+		return false, fmt.Errorf("invalid symlink policy %q (expected %s or %s)", policy, SymlinksCopy, SymlinksSkip)
+	}
+
+	item, err := c.profileItem(ctx, p, versionID)
+	if err != nil {
+		return false, err
+	}
+	if item.Symlinks == policy {
+		return false, nil
+	}
+
+	if err := c.q.SetProfileItemSymlinks(ctx, dbq.SetProfileItemSymlinksParams{
+		Symlinks: policy,
+		ID:       item.ID,
+	}); err != nil {
+		return false, fmt.Errorf("update profile item: %w", err)
+	}
+	return true, nil
+}
+
+// ExportResult summarizes an exported collection.
+type ExportResult struct {
+	Mods int
+	// mods with neither a nexus file nor a source url to download them from
+	Unsourced int
+	// overrides of the profile, which aren't part of the collection
+	Overrides int
+}
+
+// ExportProfile writes a profile as a collection (JSON) to w: its mods, where
+// to download them, and its plugin load order.
+func (c *Client) ExportProfile(ctx context.Context, gi Game, p Profile, w io.Writer) (ExportResult, error) {
+	var res ExportResult
+
+	giRow, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return res, err
+	}
+	row, err := c.profileRow(ctx, p)
+	if err != nil {
+		return res, err
+	}
+
+	col, err := internal.BuildCollection(ctx, c.q, giRow, row)
+	if err != nil {
+		return res, err
+	}
+	overrides, err := c.q.ListOverridesForProfile(ctx, p.ID)
+	if err != nil {
+		return res, fmt.Errorf("list overrides: %w", err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(col); err != nil {
+		return res, fmt.Errorf("export: %w", err)
+	}
+
+	res.Mods = len(col.Mods)
+	res.Overrides = len(overrides)
+	for _, m := range col.Mods {
+		if m.Nexus == nil && m.Source.URL == "" {
+			res.Unsourced++
+		}
+	}
+	return res, nil
+}
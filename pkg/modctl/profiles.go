@@ -0,0 +1,545 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/dryrun"
+	"github.com/mfinelli/modctl/internal/integrations"
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/mfinelli/modctl/internal/state"
+	"github.com/spf13/viper"
+)
+
+// ErrApplyCancelled is returned by Apply when ApplyOptions.Confirm declined
+// it.
+var ErrApplyCancelled = internal.ErrApplyCancelled
+
+// ErrProfileNotFound is returned when a game install has no profile with the
+// given name (or no active profile).
+var ErrProfileNotFound = internal.ErrProfileNotFound
+
+// ApplyOptions are the settings of Apply, Unapply, and Switch.
+type ApplyOptions struct {
+	// replace deployed files even if they were changed since
+	Force bool
+	// deploy every file again instead of only what changed
+	Full bool
+	// hash every deployed file to check it for changes instead of trusting
+	// the hashes of the files that weren't touched since they were hashed
+	NoHashCache bool
+	// wait this long for Steam to finish downloading or updating the game
+	// instead of refusing right away (see SteamBusyError); OnSteamWait is
+	// called when the wait starts
+	WaitForSteam time.Duration
+	OnSteamWait  func(reason string)
+	// apply mods that the advisory feed knows to be malicious instead of
+	// refusing the profile (see advisory.BlockedError)
+	IgnoreAdvisories bool
+	// called with the estimate of an apply that is going to take long
+	// before anything is changed; the apply is cancelled with
+	// ErrApplyCancelled unless it returns true (nil doesn't ask)
+	Confirm func(ApplyEstimate) bool
+	// called when a step that runs after an apply deployed the files
+	// starts (e.g., sorting the load order with LOOT), they can take a
+	// while
+	OnAfterApply func(step string)
+	// don't run REDmod's deploy step after an apply that changed REDmod
+	// content (see the redmod_deploy_after_apply config option)
+	NoRedmodDeploy bool
+}
+
+// SwitchError is returned by Switch when deploying the new profile failed
+// after the game files were already touched.
+type SwitchError struct {
+	Profile string
+	Err     error
+	// set if putting back the previous profile failed as well
+	RollbackErr error
+}
+
+func (e *SwitchError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("switch to %q: %v (rollback also failed: %v)", e.Profile, e.Err, e.RollbackErr)
+	}
+	return fmt.Sprintf("switch to %q: %v", e.Profile, e.Err)
+}
+
+func (e *SwitchError) Unwrap() error {
+	return e.Err
+}
+
+// Profile is a mod profile of a game install.
+type Profile struct {
+	ID            int64
+	GameInstallID int64
+	Name          string
+	Description   string
+	// whether it's the active profile of the game install
+	Active bool
+	// the game version that the profile is for (empty: any)
+	GameVersion string
+	// whether its mods are locked (see `modctl profiles lock`) and since
+	// when
+	Locked   bool
+	LockedAt string
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+func profileFromRow(p dbq.Profile) Profile {
+	return Profile{
+		ID:            p.ID,
+		GameInstallID: p.GameInstallID,
+		Name:          p.Name,
+		Description:   p.Description.String,
+		Active:        p.IsActive != 0,
+		GameVersion:   p.GameVersion.String,
+		Locked:        p.LockedAt.Valid,
+		LockedAt:      p.LockedAt.String,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// profileRow looks up the current row of a profile (see gameRow).
+func (c *Client) profileRow(ctx context.Context, p Profile) (dbq.Profile, error) {
+	row, err := c.q.GetProfileByID(ctx, p.ID)
+	if err != nil {
+		return row, fmt.Errorf("lookup profile %q: %w", p.Name, err)
+	}
+	return row, nil
+}
+
+// Profiles returns the profiles of a game install, the active one first.
+func (c *Client) Profiles(ctx context.Context, gi Game) ([]Profile, error) {
+	rows, err := c.q.ListProfilesByGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list profiles: %w", err)
+	}
+
+	profiles := make([]Profile, 0, len(rows))
+	for _, p := range rows {
+		profiles = append(profiles, Profile{
+			ID:            p.ID,
+			GameInstallID: gi.ID,
+			Name:          p.Name,
+			Description:   p.Description.String,
+			Active:        p.IsActive != 0,
+			GameVersion:   p.GameVersion.String,
+			Locked:        p.LockedAt.Valid,
+			LockedAt:      p.LockedAt.String,
+			CreatedAt:     p.CreatedAt,
+			UpdatedAt:     p.UpdatedAt,
+		})
+	}
+	return profiles, nil
+}
+
+// ProfileMod is an enabled mod of a profile.
+type ProfileMod struct {
+	Priority      int64
+	ModName       string
+	FileLabel     string
+	VersionID     int64
+	ArchiveSHA256 string
+}
+
+// ProfileMods returns the enabled mods of a profile, highest priority first.
+func (c *Client) ProfileMods(ctx context.Context, p Profile) ([]ProfileMod, error) {
+	rows, err := c.q.ListEnabledProfileItemsForPlan(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list profile items: %w", err)
+	}
+
+	mods := make([]ProfileMod, 0, len(rows))
+	for _, r := range rows {
+		mods = append(mods, ProfileMod{
+			Priority:      r.Priority,
+			ModName:       r.ModName,
+			FileLabel:     r.FileLabel,
+			VersionID:     r.ModFileVersionID,
+			ArchiveSHA256: r.ArchiveSha256,
+		})
+	}
+	return mods, nil
+}
+
+// Profile returns the profile of a game install with the given name, or the
+// active profile if name is empty.
+func (c *Client) Profile(ctx context.Context, gi Game, name string) (Profile, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	p, err := internal.ResolveProfileArg(ctx, c.q, &row, name)
+	if err != nil {
+		return Profile{}, err
+	}
+	return profileFromRow(p), nil
+}
+
+// ActiveProfile returns the active profile of the game install, or nil if
+// there isn't one.
+func (c *Client) ActiveProfile(ctx context.Context, gi Game) (*Profile, error) {
+	p, err := c.q.GetActiveProfileForGame(ctx, gi.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get active profile: %w", err)
+	}
+	active := profileFromRow(p)
+	return &active, nil
+}
+
+// AppliedProfile returns the profile that is deployed to the game, or nil if
+// there isn't one.
+func (c *Client) AppliedProfile(ctx context.Context, gi Game) (*Profile, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, err
+	}
+	if !row.AppliedProfileID.Valid {
+		return nil, nil
+	}
+
+	p, err := c.q.GetProfileByID(ctx, row.AppliedProfileID.Int64)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup applied profile: %w", err)
+	}
+	applied := profileFromRow(p)
+	return &applied, nil
+}
+
+// ActivateProfile makes the named profile the active profile of the game
+// install. It doesn't deploy anything, see Switch.
+func (c *Client) ActivateProfile(ctx context.Context, gi Game, name string) error {
+	return internal.ActivateProfile(ctx, c.db, c.q, gi.ID, name)
+}
+
+// Plan resolves what applying the profile deploys, and what conflicts. Nothing
+// is changed.
+func (c *Client) Plan(ctx context.Context, gi Game, p Profile) (*Plan, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, err
+	}
+	h := integrations.For(row)
+	pl, err := internal.BuildProfilePlan(ctx, c.q, p.ID, h)
+	if err != nil {
+		return nil, err
+	}
+	internal.AddWorkshopNotes(row, pl)
+
+	overrides, err := c.q.ListOverridesForProfile(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list overrides: %w", err)
+	}
+	overridden := map[string]bool{}
+	for _, o := range overrides {
+		overridden[strings.ToLower(o.Relpath)] = true
+	}
+
+	out := planFrom(pl, h.Name())
+	for i, n := range out.Notes {
+		out.Notes[i].Merged = n.Kind == "script_merge" && overridden[strings.ToLower(
+			"mods/"+integrations.Witcher3MergedDir+"/content/"+n.Key)]
+	}
+	return out, nil
+}
+
+// Apply deploys the plan of a profile (see Plan) to the game.
+func (c *Client) Apply(ctx context.Context, gi Game, p Profile, pl *Plan, opts ApplyOptions) (DeployResult, error) {
+	giRow, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	pRow, err := c.profileRow(ctx, p)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	if pl == nil || pl.p == nil {
+		return DeployResult{}, errors.New("apply needs the plan of the profile (see Client.Plan)")
+	}
+
+	res, err := c.deployer(opts).Apply(ctx, giRow, pRow, pl.p)
+	if err != nil {
+		return deployResultFrom(res), apiError(err)
+	}
+
+	out := deployResultFrom(res)
+	c.afterApply(ctx, giRow, pRow, &out, opts)
+	return out, nil
+}
+
+// afterApply runs the steps that follow an apply that changed files:
+// sorting the load order with LOOT (with the loot_after_apply config
+// option) and REDmod's deploy step if REDmod content changed (unless the
+// redmod_deploy_after_apply config option or NoRedmodDeploy turn it off).
+// The files are deployed by then, so a step that fails is only a warning.
+func (c *Client) afterApply(ctx context.Context, gi dbq.GameInstall, p dbq.Profile, res *DeployResult, opts ApplyOptions) {
+	if len(res.Changed) == 0 {
+		return
+	}
+	step := func(name string) {
+		if opts.OnAfterApply != nil {
+			opts.OnAfterApply(name)
+		}
+	}
+
+	if _, _, err := internal.PluginsTxt(gi); err == nil && viper.GetBool("loot_after_apply") {
+		switch err := internal.CheckProfileUnlocked(&p); {
+		case err != nil:
+			res.Warnings = append(res.Warnings, fmt.Sprintf("the load order wasn't sorted: %v", err))
+		case dryrun.Enabled():
+			dryrun.Note("sort the load order of profile %q with LOOT", p.Name)
+		default:
+			step("sorting the load order with LOOT")
+			_, sorted, err := internal.SortPlugins(ctx, c.db, c.q, gi, p)
+			if err != nil {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("sort the load order with LOOT: %v", err))
+			}
+			res.PluginsSorted = len(sorted)
+		}
+	}
+
+	if !redmodChanged(gi, res.Changed) {
+		return
+	}
+	switch {
+	case opts.NoRedmodDeploy || !viper.GetBool("redmod_deploy_after_apply"):
+		res.RedmodPending = true
+	case dryrun.Enabled():
+		dryrun.Note("run REDmod's deploy step")
+	default:
+		step("running REDmod's deploy step")
+		out, err := integrations.RedmodDeploy(ctx, gi)
+		if err != nil {
+			if out = strings.TrimSpace(out); out != "" {
+				err = fmt.Errorf("%w\n%s", err, out)
+			}
+			res.Warnings = append(res.Warnings, err.Error())
+			res.RedmodPending = true
+			return
+		}
+		res.RedmodDeployed = true
+	}
+}
+
+// redmodChanged reports whether the changed paths of an apply include
+// REDmod content of Cyberpunk 2077, which has to be deployed by REDmod.
+func redmodChanged(gi dbq.GameInstall, changed []ChangedPath) bool {
+	if gi.StoreID != "steam" || gi.StoreGameID != integrations.CyberpunkSteamAppID {
+		return false
+	}
+	for _, c := range changed {
+		if c.Target == plan.DefaultTarget && integrations.IsRedmodPath(c.RelPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unapply removes everything that was deployed to the game and restores
+// the files that were there before.
+func (c *Client) Unapply(ctx context.Context, gi Game, opts ApplyOptions) (DeployResult, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return DeployResult{}, err
+	}
+
+	res, err := c.deployer(opts).Unapply(ctx, row)
+	return deployResultFrom(res), apiError(err)
+}
+
+// Nuke unapplies the game, puts back every backup, compares the game files
+// to the baseline, and removes the game's profiles, mods, and other records.
+// If it was the active game, no game is active afterwards. The archives and
+// backups stay in their stores.
+func (c *Client) Nuke(ctx context.Context, gi Game, opts ApplyOptions) (NukeResult, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return NukeResult{}, err
+	}
+
+	res, err := internal.Nuke(ctx, c.deployer(opts), row)
+	if err != nil {
+		return nukeResultFrom(res), apiError(err)
+	}
+
+	// don't leave the forgotten game selected
+	active, err := state.LoadActive()
+	if err != nil {
+		return nukeResultFrom(res), fmt.Errorf("load active selection: %w", err)
+	}
+	if active.ActiveGameInstallID == gi.ID {
+		if err := internal.SaveActive(ctx, c.db, c.q, state.Active{ActiveStoreID: active.ActiveStoreID}); err != nil {
+			return nukeResultFrom(res), fmt.Errorf("save active selection: %w", err)
+		}
+	}
+
+	return nukeResultFrom(res), nil
+}
+
+// NukePreview is what a Nuke of a game install would remove.
+type NukePreview struct {
+	// the deployed files and the backups that would be put back
+	Files   int
+	Backups int
+	// the records that would be removed
+	Profiles int
+	Mods     int
+}
+
+// PreviewNuke returns what a Nuke of the game install would remove. Nothing
+// is changed.
+func (c *Client) PreviewNuke(ctx context.Context, gi Game) (NukePreview, error) {
+	installed, err := c.q.ListInstalledFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return NukePreview{}, fmt.Errorf("list installed files: %w", err)
+	}
+	backups, err := c.q.ListBackupsForGame(ctx, gi.ID)
+	if err != nil {
+		return NukePreview{}, fmt.Errorf("list backups: %w", err)
+	}
+	profiles, err := c.Profiles(ctx, gi)
+	if err != nil {
+		return NukePreview{}, err
+	}
+	mods, err := c.Mods(ctx, gi)
+	if err != nil {
+		return NukePreview{}, err
+	}
+
+	return NukePreview{
+		Files:    len(installed),
+		Backups:  len(backups),
+		Profiles: len(profiles),
+		Mods:     len(mods),
+	}, nil
+}
+
+// Switch deploys the plan of a profile in place of the applied profile and
+// makes it the active profile. If deploying fails it puts back the profile
+// that was applied before (or removes everything if there wasn't one) and
+// returns a *SwitchError. If it fails before it changed anything (e.g., with
+// a *DriftError, a *SteamBusyError, or ErrApplyCancelled) the error is
+// returned as it is and the previous profile stays active.
+func (c *Client) Switch(ctx context.Context, gi Game, p Profile, pl *Plan, opts ApplyOptions) (DeployResult, error) {
+	prev, err := c.AppliedProfile(ctx, gi)
+	if err != nil {
+		return DeployResult{}, err
+	}
+
+	return switchApply(p.Name,
+		func() (DeployResult, error) { return c.Apply(ctx, gi, p, pl, opts) },
+		func() error { return c.rollbackSwitch(ctx, gi, prev) },
+		func() error { return c.ActivateProfile(ctx, gi, p.Name) })
+}
+
+// switchApply runs the apply of a switch to the named profile, then either
+// activates it or, if the apply failed after it started changing files,
+// rolls it back. The checks before an apply (for changed files, Steam,
+// advisories, disk space, path collisions, and access) and Confirm run
+// before its operation is created, so without an operation there's nothing
+// to put back.
+func switchApply(name string, apply func() (DeployResult, error), rollback, activate func() error) (DeployResult, error) {
+	res, err := apply()
+	if err != nil {
+		if res.OperationID == 0 {
+			return res, fmt.Errorf("switch to %q: %w", name, err)
+		}
+
+		return res, &SwitchError{
+			Profile:     name,
+			Err:         err,
+			RollbackErr: rollback(),
+		}
+	}
+
+	if err := activate(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// rollbackSwitch puts back the previously applied profile (or removes
+// everything if there wasn't one) after a failed switch. The files were
+// checked for changes before the switch so anything that changed since was
+// changed by the switch itself and can be replaced.
+func (c *Client) rollbackSwitch(ctx context.Context, gi Game, prev *Profile) error {
+	// don't let an interrupt stop the rollback halfway as well
+	ctx = context.WithoutCancel(ctx)
+	opts := ApplyOptions{Force: true, IgnoreAdvisories: true}
+
+	if prev == nil {
+		_, err := c.Unapply(ctx, gi, opts)
+		return err
+	}
+
+	pl, err := c.Plan(ctx, gi, *prev)
+	if err != nil {
+		return err
+	}
+	_, err = c.Apply(ctx, gi, *prev, pl, opts)
+	return err
+}
+
+// deployer returns a deployer that uses the configured blob store.
+func (c *Client) deployer(opts ApplyOptions) *internal.Deployer {
+	d := &internal.Deployer{
+		DB:             c.db,
+		Q:              c.q,
+		Blobs:          internal.BlobStoreFromConfig(),
+		Bsdtar:         viper.GetString("bsdtar"),
+		Force:          opts.Force,
+		Full:           opts.Full,
+		SteamManifests: viper.GetBool("steam_depot_manifests"),
+		Baseline:       viper.GetBool("baseline_on_apply"),
+		ReportsDir:     viper.GetString("reports_dir"),
+		SteamWait:      opts.WaitForSteam,
+		OnSteamWait:    opts.OnSteamWait,
+
+		IgnoreAdvisories: opts.IgnoreAdvisories,
+		Mirrors:          internal.BlobMirrors(),
+	}
+	if opts.Confirm != nil {
+		d.Confirm = func(est internal.ApplyEstimate) bool {
+			return opts.Confirm(estimateFrom(est))
+		}
+	}
+	if !opts.NoHashCache {
+		d.Hashes = internal.HashCache{Q: c.q}
+	}
+	return d
+}
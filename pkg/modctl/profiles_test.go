@@ -0,0 +1,239 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient returns a client with a migrated database that has a steam
+// game install (Skyrim, id 1, in a library under a temporary directory)
+// and a profile (id 1) for it.
+func testClient(t *testing.T) (*Client, dbq.GameInstall, dbq.Profile) {
+	t.Helper()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "modctl.db")+internal.DB_PRAGMAS)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	p, err := internal.GooseProvider(db)
+	require.NoError(t, err)
+	_, err = p.Up(ctx)
+	require.NoError(t, err)
+
+	root := filepath.Join(dir, "steamapps", "common", "Skyrim Special Edition")
+	require.NoError(t, os.MkdirAll(root, 0o755))
+	_, err = db.Exec(`INSERT INTO game_installs (id, store_id, store_game_id, display_name, install_root)
+		VALUES (1, 'steam', '489830', 'Skyrim', ?)`, root)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO profiles (id, game_install_id, name) VALUES (1, 1, 'default')`)
+	require.NoError(t, err)
+
+	q := dbq.New(db)
+	gi, err := q.GetGameInstallByID(ctx, 1)
+	require.NoError(t, err)
+	prof, err := q.GetProfileByID(ctx, 1)
+	require.NoError(t, err)
+
+	return &Client{db: db, q: q}, gi, prof
+}
+
+func TestSwitchError(t *testing.T) {
+	cause := errors.New("disk full")
+
+	err := error(&SwitchError{Profile: "main", Err: cause})
+	assert.Equal(t, `switch to "main": disk full`, err.Error())
+	assert.ErrorIs(t, err, cause)
+
+	err = &SwitchError{Profile: "main", Err: cause, RollbackErr: errors.New("gone")}
+	assert.Equal(t, `switch to "main": disk full (rollback also failed: gone)`, err.Error())
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestSwitchApply(t *testing.T) {
+	tests := []struct {
+		name         string
+		res          DeployResult
+		err          error
+		wantRollback bool
+		wantActivate bool
+	}{
+		{name: "applied", res: DeployResult{OperationID: 7}, wantActivate: true},
+		{name: "cancelled", err: ErrApplyCancelled},
+		{name: "drift", err: &DriftError{Paths: []string{"game_dir/a.esp"}}},
+		{name: "failed while deploying", res: DeployResult{OperationID: 7},
+			err: errors.New("disk full"), wantRollback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolledBack, activated := false, false
+			_, err := switchApply("main",
+				func() (DeployResult, error) { return tt.res, tt.err },
+				func() error { rolledBack = true; return nil },
+				func() error { activated = true; return nil })
+
+			assert.Equal(t, tt.wantRollback, rolledBack, "rollback")
+			assert.Equal(t, tt.wantActivate, activated, "activate")
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+
+			// only a switch that changed something is a SwitchError
+			var serr *SwitchError
+			assert.Equal(t, tt.wantRollback, errors.As(err, &serr))
+		})
+	}
+}
+
+func TestAfterApplySortsPlugins(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	ctx := context.Background()
+
+	c, gi, p := testClient(t)
+	_, pluginsTxt, err := internal.PluginsTxt(gi)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(pluginsTxt), 0o755))
+
+	// LOOT writes the sorted load order to plugins.txt
+	viper.Set("loot_command", []string{"sh", "-c", `printf '*b.esp\n*a.esp\n' > "$1"`, "sh", "${plugins_txt}"})
+	t.Cleanup(func() { viper.Set("loot_command", nil) })
+
+	changed := []ChangedPath{{Target: "game_dir", RelPath: "Data/a.esp"}}
+
+	// only with loot_after_apply
+	res := DeployResult{Changed: changed}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{})
+	assert.Zero(t, res.PluginsSorted)
+
+	viper.Set("loot_after_apply", true)
+	t.Cleanup(func() { viper.Set("loot_after_apply", nil) })
+
+	// and only after an apply that changed files
+	res = DeployResult{}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{})
+	assert.Zero(t, res.PluginsSorted)
+
+	var steps []string
+	res = DeployResult{Changed: changed}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{OnAfterApply: func(step string) { steps = append(steps, step) }})
+	assert.Empty(t, res.Warnings)
+	assert.Equal(t, 2, res.PluginsSorted)
+	assert.Equal(t, []string{"sorting the load order with LOOT"}, steps)
+
+	order, err := internal.LoadPluginOrder(ctx, c.q, p.ID)
+	require.NoError(t, err)
+	require.Len(t, order, 2)
+	assert.Equal(t, "b.esp", order[0].Name)
+
+	// the load order of a locked profile stays as it is
+	_, err = c.db.Exec(`UPDATE profiles SET locked_at = '2026-01-01T00:00:00.000Z' WHERE id = 1`)
+	require.NoError(t, err)
+	p, err = c.q.GetProfileByID(ctx, 1)
+	require.NoError(t, err)
+	res = DeployResult{Changed: changed}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{})
+	assert.Zero(t, res.PluginsSorted)
+	if assert.Len(t, res.Warnings, 1) {
+		assert.Contains(t, res.Warnings[0], "is locked")
+	}
+}
+
+func TestAfterApplyDeploysRedmod(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	ctx := context.Background()
+
+	c, gi, p := testClient(t)
+	_, err := c.db.Exec(`UPDATE game_installs SET store_game_id = '1091500', display_name = 'Cyberpunk 2077' WHERE id = 1`)
+	require.NoError(t, err)
+	gi, err = c.q.GetGameInstallByID(ctx, 1)
+	require.NoError(t, err)
+
+	// REDmod and a proton that records how it was run
+	exe := filepath.Join(gi.InstallRoot, "tools", "redmod", "bin", "redMod.exe")
+	require.NoError(t, os.MkdirAll(filepath.Dir(exe), 0o755))
+	require.NoError(t, os.WriteFile(exe, nil, 0o644))
+	prefix, err := internal.SteamProtonPrefix(gi)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(prefix, 0o755))
+	dir := t.TempDir()
+	ran := filepath.Join(dir, "ran")
+	proton := filepath.Join(dir, "proton")
+	require.NoError(t, os.WriteFile(proton, []byte("#!/bin/sh\necho \"$@\" > "+ran+"\n"), 0o755))
+
+	viper.Set("proton", proton)
+	viper.Set("redmod_deploy_after_apply", true)
+	t.Cleanup(func() {
+		viper.Set("proton", nil)
+		viper.Set("redmod_deploy_after_apply", nil)
+	})
+
+	// only REDmod content needs it
+	res := DeployResult{Changed: []ChangedPath{{Target: "game_dir", RelPath: "archive/pc/mod/a.archive"}}}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{})
+	assert.False(t, res.RedmodDeployed)
+	assert.False(t, res.RedmodPending)
+	assert.NoFileExists(t, ran)
+
+	redmod := []ChangedPath{{Target: "game_dir", RelPath: "mods/a/info.json"}}
+	res = DeployResult{Changed: redmod}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{NoRedmodDeploy: true})
+	assert.False(t, res.RedmodDeployed)
+	assert.True(t, res.RedmodPending)
+	assert.NoFileExists(t, ran)
+
+	res = DeployResult{Changed: redmod}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{})
+	assert.Empty(t, res.Warnings)
+	assert.True(t, res.RedmodDeployed)
+	assert.False(t, res.RedmodPending)
+	args, err := os.ReadFile(ran)
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "run "+exe+" deploy")
+
+	// a failed deploy is a warning, the apply itself succeeded
+	require.NoError(t, os.WriteFile(proton, []byte("#!/bin/sh\necho no scripts compiled\nexit 1\n"), 0o755))
+	res = DeployResult{Changed: redmod}
+	c.afterApply(ctx, gi, p, &res, ApplyOptions{})
+	assert.False(t, res.RedmodDeployed)
+	assert.True(t, res.RedmodPending)
+	if assert.Len(t, res.Warnings, 1) {
+		assert.Contains(t, res.Warnings[0], "no scripts compiled")
+	}
+}
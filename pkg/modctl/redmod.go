@@ -0,0 +1,41 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal/integrations"
+)
+
+// RedmodDeploy runs REDmod's deploy step (redMod.exe deploy) of Cyberpunk
+// 2077 through proton, which Apply and Switch do on their own unless told
+// not to. It returns the output of the deploy, also when it fails.
+func (c *Client) RedmodDeploy(ctx context.Context, gi Game) (string, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return "", err
+	}
+	if row.StoreID != "steam" || row.StoreGameID != integrations.CyberpunkSteamAppID {
+		return "", fmt.Errorf("%s is not Cyberpunk 2077", row.DisplayName)
+	}
+
+	return integrations.RedmodDeploy(ctx, row)
+}
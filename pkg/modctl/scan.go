@@ -0,0 +1,194 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+
+	"github.com/mfinelli/modctl/internal"
+)
+
+// ErrNothingToVerify is returned by VerifyVanilla when the game install has
+// neither depot manifests nor a baseline to compare its files to.
+var ErrNothingToVerify = internal.ErrNothingToVerify
+
+// Provenance is where a file in the targets of a game install came from,
+// see ClassifyFiles.
+type Provenance string
+
+const (
+	// ProvenanceVanilla files were there when the baseline was recorded and
+	// haven't changed since.
+	ProvenanceVanilla Provenance = "vanilla"
+	// ProvenanceMod files were deployed by modctl and haven't changed since.
+	ProvenanceMod Provenance = "mod"
+	// ProvenanceUser files were created after the baseline was recorded
+	// (e.g., by the user, the game, or another tool).
+	ProvenanceUser Provenance = "user"
+	// ProvenanceUnknown files changed since they were recorded or deployed,
+	// or there is no baseline to compare them to.
+	ProvenanceUnknown Provenance = "unknown"
+)
+
+// ClassifiedFile is a file in the targets of a game install and where it
+// came from.
+type ClassifiedFile struct {
+	Target     string
+	RelPath    string
+	Size       int64
+	Provenance Provenance
+}
+
+func (f ClassifiedFile) String() string {
+	return f.Target + "/" + f.RelPath
+}
+
+// RecordBaseline records what is in the targets of a game install (what was
+// there before modctl deployed anything), replacing the previous baseline,
+// and returns the number of files recorded. Every file is hashed.
+func (c *Client) RecordBaseline(ctx context.Context, gi Game) (int, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return 0, err
+	}
+	return internal.RecordBaseline(ctx, c.db, c.q, row)
+}
+
+// ClassifyFiles compares the files in the targets of a game install to its
+// baseline and to what modctl deployed. Files whose size and modification
+// time didn't change aren't hashed again unless verify is set.
+func (c *Client) ClassifyFiles(ctx context.Context, gi Game, verify bool) ([]ClassifiedFile, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := internal.ClassifyFiles(ctx, c.q, row, verify)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ClassifiedFile, 0, len(files))
+	for _, f := range files {
+		out = append(out, ClassifiedFile{
+			Target:     f.Target,
+			RelPath:    f.RelPath,
+			Size:       f.Size,
+			Provenance: Provenance(f.Provenance),
+		})
+	}
+	return out, nil
+}
+
+// Leftover is something that another mod manager (Vortex or MO2) left in a
+// target.
+type Leftover struct {
+	Manager string `json:"manager"`
+	// e.g., "deployment manifest"
+	Kind    string `json:"kind"`
+	Target  string `json:"target"`
+	RelPath string `json:"relpath"`
+	Path    string `json:"path"`
+	// what's in it (e.g., how many files a manifest lists)
+	Detail string `json:"detail,omitempty"`
+	// how to clean it up or import it into modctl
+	Suggestion string `json:"suggestion"`
+}
+
+func (l Leftover) String() string {
+	return l.Target + "/" + l.RelPath
+}
+
+// ScanLeftovers looks for what other mod managers left in the targets of a
+// game install. With quick it only looks a couple of directories deep.
+func (c *Client) ScanLeftovers(ctx context.Context, gi Game, quick bool) ([]Leftover, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, err
+	}
+
+	leftovers, err := internal.ScanLeftovers(ctx, c.q, row, quick)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Leftover, 0, len(leftovers))
+	for _, l := range leftovers {
+		out = append(out, Leftover(l))
+	}
+	return out, nil
+}
+
+// VanillaReport is what VerifyVanilla found.
+type VanillaReport struct {
+	// files that modctl deployed (that a profile is applied)
+	Deployed int
+	// vanilla files checked against Steam's depot manifests (0 if they
+	// weren't used)
+	SteamFiles int
+	// the files were compared to the baseline
+	Baseline bool
+
+	// vanilla files that are different or gone
+	Changed []ChangedPath
+	Missing []ChangedPath
+	// files that aren't part of the game; they don't make the game any
+	// less vanilla
+	Extra []ChangedPath
+
+	Warnings []string
+}
+
+// Pristine reports whether the game install is vanilla: nothing is
+// deployed and every vanilla file is as it should be.
+func (r VanillaReport) Pristine() bool {
+	return r.Deployed == 0 && len(r.Changed) == 0 && len(r.Missing) == 0
+}
+
+// VerifyVanilla checks that a game install is vanilla: that modctl deployed
+// nothing to it and that its files are the ones of Steam's depot manifests
+// (steam games) and of the baseline. Every file is hashed. Nothing is
+// changed, see RecordVerifiedVanilla.
+func (c *Client) VerifyVanilla(ctx context.Context, gi Game) (VanillaReport, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return VanillaReport{}, err
+	}
+
+	r, err := internal.VerifyVanilla(ctx, c.q, row)
+	return VanillaReport{
+		Deployed:   r.Deployed,
+		SteamFiles: r.SteamFiles,
+		Baseline:   r.Baseline,
+		Changed:    changedPaths(r.Changed),
+		Missing:    changedPaths(r.Missing),
+		Extra:      changedPaths(r.Extra),
+		Warnings:   r.Warnings,
+	}, err
+}
+
+// RecordVerifiedVanilla records that a game install was verified vanilla
+// (and its baseline, if it doesn't have one yet).
+func (c *Client) RecordVerifiedVanilla(ctx context.Context, gi Game) error {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return err
+	}
+	return internal.RecordVerifiedVanilla(ctx, c.db, c.q, row)
+}
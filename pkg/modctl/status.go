@@ -0,0 +1,185 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfinelli/modctl/internal"
+)
+
+// DriftedFile is a deployed file that something else modified or deleted,
+// as `modctl watch` noticed.
+type DriftedFile struct {
+	Target  string
+	RelPath string
+	// "modified" or "deleted", and when
+	Kind string
+	At   string
+}
+
+func (f DriftedFile) String() string {
+	return f.Target + "/" + f.RelPath
+}
+
+// DriftedFiles returns the deployed files of a game install that were
+// changed on disk since they were deployed, as far as `modctl watch` noticed.
+func (c *Client) DriftedFiles(ctx context.Context, gi Game) ([]DriftedFile, error) {
+	rows, err := c.q.ListDriftedFilesForGame(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list drifted files: %w", err)
+	}
+
+	files := make([]DriftedFile, 0, len(rows))
+	for _, f := range rows {
+		files = append(files, DriftedFile{
+			Target:  f.TargetName,
+			RelPath: f.Relpath,
+			Kind:    f.DriftKind.String,
+			At:      f.DriftedAt.String,
+		})
+	}
+	return files, nil
+}
+
+// DeployedDrift is what Drift found.
+type DeployedDrift struct {
+	// the deployed files that were modified, as target/relpath
+	Paths []string
+	// the ones that are vanilla game files again (e.g., because Steam
+	// verified or updated the game)
+	Vanilla []string
+	// about the steam depot manifests that the vanilla files are known from
+	Warnings []string
+}
+
+// Drift hashes the deployed files of a game install and returns the ones
+// that were modified since they were deployed: the files that Apply and
+// Unapply refuse to replace without ApplyOptions.Force. Unlike DriftedFiles
+// it doesn't depend on `modctl watch`.
+func (c *Client) Drift(ctx context.Context, gi Game) (DeployedDrift, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return DeployedDrift{}, err
+	}
+
+	drift, warnings, err := c.deployer(ApplyOptions{}).Drift(ctx, row)
+	res := DeployedDrift{Warnings: warnings}
+	if drift != nil {
+		res.Paths, res.Vanilla = drift.Paths, drift.Vanilla
+	}
+	return res, apiError(err)
+}
+
+// PendingChange is something that applying a profile again would change
+// since it was last applied.
+type PendingChange struct {
+	// added, removed, version, priority, rules, hidden, symlinks, or
+	// override
+	Kind    string
+	Message string
+}
+
+// PendingChanges returns what changed in a profile since it was applied to
+// the game install: the mods that were enabled, disabled, updated, or
+// reordered, and its overrides. known is false if the applied revision
+// isn't known (the profile was applied by an older modctl). A different
+// applied profile isn't a change of p.
+func (c *Client) PendingChanges(ctx context.Context, gi Game, p Profile) (changes []PendingChange, known bool, err error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rev, ok, err := internal.AppliedRevision(row)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	current, err := internal.CurrentRevision(ctx, c.q, p.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, ch := range internal.DiffRevisions(rev, current) {
+		if ch.Kind != "profile" {
+			changes = append(changes, PendingChange(ch))
+		}
+	}
+	return changes, true, nil
+}
+
+// InstalledFile is a file that modctl deployed into a game install.
+type InstalledFile struct {
+	Target  string
+	RelPath string
+	SHA256  string
+	Size    int64
+
+	// the mod version that it comes from (0 for an override), with its mod
+	// and file, and the profile that deployed it
+	VersionID     int64
+	ModName       string
+	FileLabel     string
+	VersionString string
+	Override      bool
+	Profile       string
+
+	// when it was deployed and last verified (empty: never), and how it
+	// drifted and when (see DriftedFiles; empty if it didn't)
+	InstalledAt string
+	VerifiedAt  string
+	DriftKind   string
+	DriftedAt   string
+}
+
+func (f InstalledFile) String() string {
+	return f.Target + "/" + f.RelPath
+}
+
+// InstalledFiles returns the files that modctl deployed into a game install,
+// ordered by target and path.
+func (c *Client) InstalledFiles(ctx context.Context, gi Game) ([]InstalledFile, error) {
+	rows, err := c.q.ListInstalledFileDetailsForGame(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list installed files: %w", err)
+	}
+
+	files := make([]InstalledFile, 0, len(rows))
+	for _, f := range rows {
+		files = append(files, InstalledFile{
+			Target:        f.TargetName,
+			RelPath:       f.Relpath,
+			SHA256:        f.ContentSha256,
+			Size:          f.SizeBytes,
+			VersionID:     f.OwnerModFileVersionID.Int64,
+			ModName:       f.ModName.String,
+			FileLabel:     f.FileLabel.String,
+			VersionString: f.VersionString.String,
+			Override:      f.OwnerOverrideID.Valid,
+			Profile:       f.ProfileName.String,
+			InstalledAt:   f.InstalledAt,
+			VerifiedAt:    f.VerifiedAt.String,
+			DriftKind:     f.DriftKind.String,
+			DriftedAt:     f.DriftedAt.String,
+		})
+	}
+	return files, nil
+}
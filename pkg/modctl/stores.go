@@ -0,0 +1,112 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/state"
+	"go.finelli.dev/util"
+)
+
+// Store is a store that games are discovered in (e.g., steam).
+type Store struct {
+	ID          string
+	DisplayName string
+	// whether refreshes look for games in it
+	Enabled bool
+}
+
+func storeFromRow(s dbq.Store) Store {
+	return Store{
+		ID:          s.ID,
+		DisplayName: s.DisplayName,
+		Enabled:     util.SqliteIntToBool(s.Enabled),
+	}
+}
+
+// Stores returns the stores (only the enabled ones with enabledOnly).
+func (c *Client) Stores(ctx context.Context, enabledOnly bool) ([]Store, error) {
+	var rows []dbq.Store
+	var err error
+	if enabledOnly {
+		rows, err = c.q.ListEnabledStores(ctx)
+	} else {
+		rows, err = c.q.ListAllStores(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stores: %w", err)
+	}
+
+	stores := make([]Store, 0, len(rows))
+	for _, s := range rows {
+		stores = append(stores, storeFromRow(s))
+	}
+	return stores, nil
+}
+
+// ActiveStore returns the id of the active store (see SetActiveStore), or
+// steam if none was made the active one.
+func (c *Client) ActiveStore(ctx context.Context) (string, error) {
+	a, err := state.LoadActive()
+	if err != nil {
+		return "", fmt.Errorf("error getting active store: %w", err)
+	}
+
+	// we default to steam for now since it's the only store that we
+	// support (TODO when we add more stores)
+	if a.ActiveStoreID == "" {
+		return "steam", nil
+	}
+	return a.ActiveStoreID, nil
+}
+
+// SetActiveStore makes the (enabled) store with the given id (ignoring case)
+// the active one.
+func (c *Client) SetActiveStore(ctx context.Context, id string) (Store, error) {
+	// store ids are meant to be stable identifiers like "steam", lowercase
+	// them to avoid surprising mismatches
+	id = strings.ToLower(strings.TrimSpace(id))
+
+	row, err := c.q.GetStoreById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Store{}, fmt.Errorf("unknown store %q", id)
+	}
+	if err != nil {
+		return Store{}, fmt.Errorf("get store: %w", err)
+	}
+	s := storeFromRow(row)
+	if !s.Enabled {
+		return s, fmt.Errorf("store %q is disabled", id)
+	}
+
+	a, err := state.LoadActive()
+	if err != nil {
+		return s, err
+	}
+	a.ActiveStoreID = s.ID
+
+	return s, internal.SaveActive(ctx, c.db, c.q, a)
+}
@@ -0,0 +1,214 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mfinelli/modctl/dbq"
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/integrations"
+	"github.com/mfinelli/modctl/internal/plan"
+)
+
+// DefaultTarget is the target that files are deployed into unless something
+// says otherwise (the game directory).
+const DefaultTarget = plan.DefaultTarget
+
+// The deploy methods and backup policies of a target, see SetTargetStrategy.
+const (
+	DeployMethodAuto   = internal.DeployMethodAuto
+	BackupPolicyBackup = internal.BackupPolicyBackup
+	BackupPolicyNone   = internal.BackupPolicyNone
+)
+
+var (
+	DeployMethods  = internal.DeployMethods
+	BackupPolicies = internal.BackupPolicies
+)
+
+// Target is a directory of a game install that mods are deployed to (e.g.,
+// the game directory, or its documents in the proton prefix).
+type Target struct {
+	ID   int64
+	Name string
+	// where it is, and the template that it was expanded from (empty if it
+	// doesn't have any variables)
+	Root     string
+	Template string
+	// where it came from: "discovered" (by refresh or detect-targets) or
+	// "user_override"
+	Origin string
+
+	// how apply puts files in place, and what happens to the files that
+	// modctl didn't deploy (see SetTargetStrategy)
+	DeployMethod string
+	BackupPolicy string
+}
+
+func targetFromRow(t dbq.Target) Target {
+	tgt := Target{
+		ID:           t.ID,
+		Name:         t.Name,
+		Root:         t.RootPath,
+		Origin:       t.Origin,
+		DeployMethod: t.DeployMethod,
+		BackupPolicy: t.BackupPolicy,
+	}
+	if tmpl := internal.TargetTemplate(t); strings.Contains(tmpl, "${") {
+		tgt.Template = tmpl
+	}
+	return tgt
+}
+
+// Targets returns the targets of a game install.
+func (c *Client) Targets(ctx context.Context, gi Game) ([]Target, error) {
+	rows, err := c.q.ListTargetsForGameInstall(ctx, gi.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list targets: %w", err)
+	}
+
+	targets := make([]Target, 0, len(rows))
+	for _, t := range rows {
+		targets = append(targets, targetFromRow(t))
+	}
+	return targets, nil
+}
+
+func (c *Client) targetRow(ctx context.Context, gi Game, name string) (dbq.Target, error) {
+	t, err := c.q.GetTargetByName(ctx, dbq.GetTargetByNameParams{
+		GameInstallID: gi.ID,
+		Name:          name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return t, fmt.Errorf("%s has no target %q (try `modctl games refresh`)", gi.DisplayName, name)
+	}
+	if err != nil {
+		return t, fmt.Errorf("get target: %w", err)
+	}
+	return t, nil
+}
+
+// Target returns the target of a game install with the given name.
+func (c *Client) Target(ctx context.Context, gi Game, name string) (Target, error) {
+	t, err := c.targetRow(ctx, gi, name)
+	if err != nil {
+		return Target{}, err
+	}
+	return targetFromRow(t), nil
+}
+
+// SetTargetStrategy sets how apply deploys to a target (one of
+// DeployMethods) and what happens to the files there that modctl didn't
+// deploy (one of BackupPolicies). The next apply uses them. A target with
+// deployed files can't switch to or from an overlay.
+func (c *Client) SetTargetStrategy(ctx context.Context, gi Game, name, method, policy string) (Target, error) {
+	t, err := c.targetRow(ctx, gi, name)
+	if err != nil {
+		return Target{}, err
+	}
+
+	if err := internal.CheckTargetStrategy(method, policy); err != nil {
+		return Target{}, err
+	}
+	if err := internal.CheckTargetMethodChange(ctx, c.q, t, method); err != nil {
+		return Target{}, err
+	}
+
+	if err := c.q.SetTargetStrategy(ctx, dbq.SetTargetStrategyParams{
+		DeployMethod: method,
+		BackupPolicy: policy,
+		ID:           t.ID,
+	}); err != nil {
+		return Target{}, fmt.Errorf("set target strategy: %w", err)
+	}
+
+	t.DeployMethod, t.BackupPolicy = method, policy
+	return targetFromRow(t), nil
+}
+
+// ModFolder is a conventional mod directory of a game install that could
+// be a target, see ProposeTargets.
+type ModFolder struct {
+	Target  string
+	RelPath string // below the install root
+	Reason  string
+	// the directory doesn't exist yet (the game or its mod loader only
+	// looks for it)
+	Missing bool
+}
+
+// ProposeTargets looks for the directories that mod loaders and engines
+// conventionally load mods from in the install root of a game that modctl
+// has no dedicated handler for, and returns the ones that aren't targets
+// yet. Nothing is changed, see CreateTargets.
+func (c *Client) ProposeTargets(ctx context.Context, gi Game) ([]ModFolder, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return nil, err
+	}
+	if row.IsPresent == 0 {
+		return nil, fmt.Errorf("%s is not present at %s", row.DisplayName, row.InstallRoot)
+	}
+	if _, generic := integrations.For(row).(plan.Generic); !generic {
+		return nil, fmt.Errorf("%s has a dedicated handler that already puts mods where the game loads them from", row.DisplayName)
+	}
+
+	proposed, err := internal.ProposeTargets(ctx, c.q, row)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := make([]ModFolder, 0, len(proposed))
+	for _, f := range proposed {
+		folders = append(folders, ModFolder(f))
+	}
+	return folders, nil
+}
+
+// CreateTargets creates targets for mod folders that ProposeTargets
+// returned, all or none of them.
+func (c *Client) CreateTargets(ctx context.Context, gi Game, folders []ModFolder) error {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := c.q.WithTx(tx)
+	for _, f := range folders {
+		if err := internal.CreateDetectedTarget(ctx, qtx, row, internal.ModFolder(f)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
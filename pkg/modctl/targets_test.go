@@ -0,0 +1,53 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTargetStrategy(t *testing.T) {
+	ctx := context.Background()
+	c, row, _ := testClient(t)
+	gi := gameFromRow(row)
+
+	_, err := c.db.Exec(`INSERT INTO targets (id, game_install_id, name, root_path, origin)
+		VALUES (1, 1, 'game_dir', ?, 'discovered')`, row.InstallRoot)
+	require.NoError(t, err)
+
+	tgt, err := c.SetTargetStrategy(ctx, gi, "game_dir", "copy", BackupPolicyNone)
+	require.NoError(t, err)
+	assert.Equal(t, "copy", tgt.DeployMethod)
+	assert.Equal(t, BackupPolicyNone, tgt.BackupPolicy)
+
+	targets, err := c.Targets(ctx, gi)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, tgt, targets[0])
+
+	_, err = c.SetTargetStrategy(ctx, gi, "game_dir", "junction", BackupPolicyNone)
+	assert.ErrorContains(t, err, "invalid deploy method")
+
+	_, err = c.Target(ctx, gi, "data")
+	assert.ErrorContains(t, err, `has no target "data"`)
+}
@@ -0,0 +1,95 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"time"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/lock"
+)
+
+// ErrWatchUnsupported is returned by Watch on platforms where changes to
+// files can't be watched (everything but Linux, for now).
+var ErrWatchUnsupported = internal.ErrWatchUnsupported
+
+// DriftEvent is a change to a deployed file that Watch noticed.
+type DriftEvent struct {
+	Target  string
+	RelPath string
+	// "modified", "deleted", or "" when the file is back to what was
+	// deployed
+	Kind string
+	At   time.Time
+}
+
+func (e DriftEvent) String() string {
+	return internal.DriftEvent(e).String()
+}
+
+// WatchOptions are the settings of Watch.
+type WatchOptions struct {
+	// how long a file has to be left alone before it's checked, and how
+	// often all of the deployed files are checked again
+	Debounce time.Duration
+	Rescan   time.Duration
+
+	// OnDrift is called for every deployed file that drifted (or was
+	// restored), OnWarning for the errors that don't stop watching
+	OnDrift   func(ev DriftEvent)
+	OnWarning func(err error)
+}
+
+// Watch watches the files that modctl deployed into a game install and
+// records the ones that something else changes as drift (see DriftedFiles)
+// until ctx is cancelled. Only one watch of a game install can run at a
+// time; command describes it to the others (e.g., "mygui watch").
+func (c *Client) Watch(ctx context.Context, gi Game, command string, opts WatchOptions) error {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return err
+	}
+
+	l, err := internal.LockWatch(command, gi.ID)
+	if err != nil {
+		return err
+	}
+	defer l.Release()
+
+	w := &internal.Watcher{
+		Q:           c.q,
+		Hashes:      internal.HashCache{Q: c.q},
+		GameInstall: row,
+		Lock: func() (*lock.Lock, error) {
+			return internal.LockState(command)
+		},
+	}
+	emit := func(ev internal.DriftEvent) {
+		if opts.OnDrift != nil {
+			opts.OnDrift(DriftEvent(ev))
+		}
+	}
+	warn := func(err error) {
+		if opts.OnWarning != nil {
+			opts.OnWarning(err)
+		}
+	}
+	return w.Run(ctx, opts.Debounce, opts.Rescan, emit, warn)
+}
@@ -0,0 +1,146 @@
+/*
+ * mod control (modctl): command-line mod manager
+ * Copyright © 2026 Mario Finelli
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package modctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mfinelli/modctl/internal"
+	"github.com/mfinelli/modctl/internal/archive"
+	"github.com/mfinelli/modctl/internal/blobstore"
+	"github.com/mfinelli/modctl/internal/integrations"
+	"github.com/mfinelli/modctl/internal/plan"
+	"github.com/spf13/viper"
+)
+
+// Witcher3MergedDir is the mod directory of The Witcher 3 that the merged
+// scripts are deployed to; the game gives it precedence over every other mod.
+const Witcher3MergedDir = integrations.Witcher3MergedDir
+
+// Witcher3Script is a Witcher 3 script (.ws) of a profile, see
+// Client.Witcher3Script.
+type Witcher3Script struct {
+	// the script relative to the content directory of the mods (e.g.,
+	// "scripts/game/player/r4Player.ws")
+	Key string
+	// where the merged script goes in the default target, i.e., the path
+	// of its override (see SetOverride)
+	RelPath string
+	// the mods that change it in the order that they have to be merged
+	// (the one that the game gives precedence to first); fewer than two if
+	// there's nothing to merge
+	Sources []PlanSource
+
+	sources []plan.Source
+}
+
+// Witcher3Script looks up a script of a Witcher 3 profile and the mods that
+// change it. The merged script is stored with SetOverride (see
+// ExtractWitcher3Script for getting the versions to merge).
+func (c *Client) Witcher3Script(ctx context.Context, gi Game, p Profile, script string) (Witcher3Script, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return Witcher3Script{}, err
+	}
+	if !integrations.IsWitcher3(row) {
+		return Witcher3Script{}, fmt.Errorf("%s is not The Witcher 3", row.DisplayName)
+	}
+	if _, err := c.unlockedProfile(ctx, p); err != nil {
+		return Witcher3Script{}, err
+	}
+	if _, err := c.targetRow(ctx, gi, DefaultTarget); err != nil {
+		return Witcher3Script{}, err
+	}
+
+	s := Witcher3Script{Key: strings.TrimPrefix(filepath.ToSlash(script), "content/")}
+
+	pl, err := internal.BuildProfilePlan(ctx, c.q, p.ID, integrations.Witcher3{})
+	if err != nil {
+		return Witcher3Script{}, err
+	}
+	for _, n := range pl.Notes {
+		if n.Kind == "script_merge" && strings.EqualFold(n.Key, s.Key) {
+			s.Key = n.Key
+			s.sources = append([]plan.Source(nil), n.Sources...)
+			break
+		}
+	}
+	sort.SliceStable(s.sources, func(i, j int) bool {
+		return strings.ToLower(s.sources[i].Member) < strings.ToLower(s.sources[j].Member)
+	})
+	for _, src := range s.sources {
+		s.Sources = append(s.Sources, planSource(src))
+	}
+
+	s.RelPath = "mods/" + Witcher3MergedDir + "/content/" + s.Key
+	return s, nil
+}
+
+// ExtractWitcher3Script writes the game's own copy of a script (empty if the
+// game doesn't have one) and every mod's version of it into dir. It returns
+// the path of the game's copy, the common base of a merge, and those of the
+// mods' versions in the order of s.Sources.
+func (c *Client) ExtractWitcher3Script(ctx context.Context, gi Game, s Witcher3Script, dir string) (string, []string, error) {
+	row, err := c.gameRow(ctx, gi)
+	if err != nil {
+		return "", nil, err
+	}
+
+	bs := internal.BlobStoreFromConfig()
+	bsdtar := viper.GetString("bsdtar")
+
+	var files []string
+	for i, src := range s.sources {
+		ap, err := bs.Locate(blobstore.KindArchive, src.Item.ArchiveSHA256)
+		if err != nil {
+			return "", nil, err
+		}
+		b, truncated, err := archive.ReadMember(ctx, bsdtar, ap, src.File(), 64*1024*1024)
+		if err != nil {
+			return "", nil, fmt.Errorf("extract %s from v%d: %w", src.Member, src.Item.VersionID, err)
+		}
+		if truncated {
+			return "", nil, fmt.Errorf("%s in v%d is too large", src.Member, src.Item.VersionID)
+		}
+
+		f := filepath.Join(dir, fmt.Sprintf("%d-v%d.ws", i, src.Item.VersionID))
+		if err := os.WriteFile(f, b, 0o644); err != nil {
+			return "", nil, err
+		}
+		files = append(files, f)
+	}
+
+	base := filepath.Join(dir, "base.ws")
+	vanilla := filepath.Join(row.InstallRoot, "content", "content0", filepath.FromSlash(s.Key))
+	b, err := os.ReadFile(vanilla)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", nil, fmt.Errorf("read %s: %w", vanilla, err)
+	}
+	if err := os.WriteFile(base, b, 0o644); err != nil {
+		return "", nil, err
+	}
+
+	return base, files, nil
+}
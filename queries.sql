@@ -22,11 +22,27 @@ SELECT * FROM game_installs WHERE store_id = ?
 ORDER BY display_name, store_game_id, instance_id;
 
 -- name: MarkStoreInstallsNotPresent :exec
+-- copies made by `modctl games duplicate` aren't discovered by the store, see
+-- ListStoreInstallCopies
 UPDATE game_installs
 SET
   is_present = FALSE,
   updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
-WHERE store_id = ?;
+WHERE store_id = ?
+  AND json_extract(metadata, '$.copy_of') IS NULL;
+
+-- name: ListStoreInstallCopies :many
+SELECT id, install_root, is_present
+FROM game_installs
+WHERE store_id = ?
+  AND json_extract(metadata, '$.copy_of') IS NOT NULL;
+
+-- name: SetGameInstallPresent :exec
+UPDATE game_installs
+SET
+  is_present = ?,
+  updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;
 
 -- name: UpsertGameInstall :one
 INSERT INTO game_installs (
@@ -150,6 +166,12 @@ LIMIT 10;
 -- name: ListTargetsForGameInstall :many
 SELECT * FROM targets WHERE game_install_id = ? ORDER BY name;
 
+-- name: SetTargetRootPath :exec
+UPDATE targets
+SET root_path = ?,
+    updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;
+
 -- name: GetProfilesForGameInstall :many
 SELECT * FROM profiles WHERE game_install_id = ? ORDER BY name;
 
@@ -163,11 +185,60 @@ VALUES (?, ?, ?, ?, ?);
 -- name: ListBlobsByKind :many
 SELECT * FROM blobs WHERE kind = ? ORDER BY created_at;
 
+-- name: SetBlobUnpackedBytes :exec
+UPDATE blobs SET unpacked_bytes = ? WHERE sha256 = ?;
+
 -- name: TouchBlobVerifiedAt :exec
 UPDATE blobs
 SET verified_at = ?
 WHERE sha256 = ?;
 
+-- name: MarkBlobCorrupted :exec
+UPDATE blobs
+SET corrupted_at = ?
+WHERE sha256 = ?;
+
+-- name: ClearBlobCorrupted :exec
+UPDATE blobs
+SET corrupted_at = NULL, verified_at = ?
+WHERE sha256 = ?;
+
+-- name: SetBlobFetchedFrom :exec
+UPDATE blobs
+SET fetched_from = ?, fetched_at = ?
+WHERE sha256 = ?;
+
+-- name: SetBlobScan :exec
+UPDATE blobs
+SET scan_verdict = ?, scan_detail = ?, scanned_at = ?
+WHERE sha256 = ?;
+
+-- name: ListModsUsingArchive :many
+SELECT v.id, f.label AS file_label, v.version_string, p.id AS mod_page_id,
+  p.name AS mod_name, p.nexus_game_domain, p.nexus_mod_id,
+  gi.display_name AS game_name
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+JOIN game_installs gi ON gi.id = p.game_install_id
+WHERE v.archive_sha256 = ?
+ORDER BY gi.display_name, p.name, v.id;
+
+-- name: ListProfilesUsingArchive :many
+SELECT DISTINCT pr.id, pr.name, gi.display_name AS game_name
+FROM profile_items pi
+JOIN profiles pr ON pr.id = pi.profile_id
+JOIN game_installs gi ON gi.id = pr.game_install_id
+JOIN mod_file_versions v ON v.id = pi.mod_file_version_id
+WHERE v.archive_sha256 = ?
+ORDER BY gi.display_name, pr.name;
+
+-- name: ArchiveWasDeployed :one
+SELECT 1
+FROM operation_changes c
+JOIN mod_file_versions v ON v.id = c.mod_file_version_id
+WHERE v.archive_sha256 = ? LIMIT 1;
+
 -- name: CreateModPage :one
 INSERT INTO mod_pages (
   game_install_id, name, source_kind, source_url, source_ref,
@@ -182,82 +253,92 @@ RETURNING id;
 
 -- name: CreateModFile :one
 INSERT INTO mod_files (
-  mod_page_id, label, is_primary, nexus_file_id, source_url, metadata
+  mod_page_id, label, is_primary, nexus_file_id, category, source_url, metadata
 ) VALUES (
-  ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING id;
 
 -- name: CreateModFileVersion :one
 INSERT INTO mod_file_versions (
   mod_file_id, archive_sha256, original_name, version_string,
-  uploaded_at, upstream_notes, notes, metadata
+  uploaded_at, upstream_notes, notes, metadata, nexus_file_id
 ) VALUES (
   ?, ?, ?, ?,
-  ?, ?, ?, ?
+  ?, ?, ?, ?, ?
 )
 RETURNING id;
 
 -- name: ListModsByGameInstall :many
-WITH joined AS (
+-- the latest version is looked up per page (with an index) instead of
+-- ranking every version of the game with a window function
+WITH latest AS (
   SELECT
-    mp.id AS mod_page_id,
-    mp.name AS mod_name,
-    mp.source_kind,
-    mp.nexus_game_domain,
-    mp.nexus_mod_id,
-
-    mf.id AS mod_file_id,
-    mf.label AS mod_file_label,
-
-    mfv.id AS mod_file_version_id,
-    mfv.version_string,
-    mfv.archive_sha256,
-    mfv.created_at AS imported_at,
-
-    COUNT(DISTINCT mf.id) OVER (PARTITION BY mp.id) AS files_count,
-    COUNT(mfv.id) OVER (PARTITION BY mp.id) AS versions_count,
-
-    ROW_NUMBER() OVER (
-      PARTITION BY mp.id
-      ORDER BY
-        (mfv.created_at IS NULL) ASC,  -- prefer non-NULL versions
-        mfv.created_at DESC,
-        mfv.id DESC
-    ) AS rn
-  FROM mod_pages mp
-  LEFT JOIN mod_files mf
-    ON mf.mod_page_id = mp.id
-  LEFT JOIN mod_file_versions mfv
-    ON mfv.mod_file_id = mf.id
-  WHERE mp.game_install_id = ?
+    p.id AS mod_page_id,
+    COUNT(1) AS versions_count,
+    MAX(v.created_at) AS latest_at
+  FROM mod_pages p
+  JOIN mod_files f ON f.mod_page_id = p.id
+  JOIN mod_file_versions v ON v.mod_file_id = f.id
+  WHERE p.game_install_id = sqlc.arg(game_install_id)
+  GROUP BY p.id
 )
 SELECT
-  mod_page_id,
-  mod_name,
-  source_kind,
-  nexus_game_domain,
-  nexus_mod_id,
+  mp.id AS mod_page_id,
+  mp.name AS mod_name,
+  mp.source_kind,
+  mp.nexus_game_domain,
+  mp.nexus_mod_id,
 
-  files_count,
-  versions_count,
+  (SELECT COUNT(1) FROM mod_files c WHERE c.mod_page_id = mp.id) AS files_count,
+  CAST(COALESCE(l.versions_count, 0) AS INTEGER) AS versions_count,
 
-  mod_file_id,
-  mod_file_label,
-  mod_file_version_id,
-  version_string,
-  archive_sha256,
-  imported_at
-FROM joined
-WHERE rn = 1
-ORDER BY mod_name COLLATE NOCASE, mod_page_id;
+  mf.id AS mod_file_id,
+  mf.label AS mod_file_label,
+  mfv.id AS mod_file_version_id,
+  mfv.version_string,
+  mfv.archive_sha256,
+  mfv.created_at AS imported_at
+FROM mod_pages mp
+LEFT JOIN latest l
+  ON l.mod_page_id = mp.id
+LEFT JOIN mod_file_versions mfv
+  ON mfv.id = (
+    -- newest version of the page, the highest id breaks ties
+    SELECT MAX(lv.id)
+    FROM mod_files lf
+    JOIN mod_file_versions lv ON lv.mod_file_id = lf.id
+    WHERE lf.mod_page_id = mp.id AND lv.created_at = l.latest_at
+  )
+LEFT JOIN mod_files mf
+  ON mf.id = mfv.mod_file_id
+WHERE mp.game_install_id = sqlc.arg(game_install_id)
+ORDER BY mp.name COLLATE NOCASE, mp.id;
+
+-- name: ListModFilesByGameInstall :many
+SELECT f.id, f.mod_page_id, f.label, f.is_primary, f.nexus_file_id, f.source_url,
+  f.created_at, f.updated_at
+FROM mod_files f
+JOIN mod_pages p ON p.id = f.mod_page_id
+WHERE p.game_install_id = ?
+ORDER BY f.mod_page_id, f.is_primary DESC, f.label COLLATE NOCASE, f.id;
 
 -- name: ListModFilesByPage :many
-SELECT id, mod_page_id, label, is_primary, nexus_file_id, source_url, created_at, updated_at
+SELECT id, mod_page_id, label, is_primary, nexus_file_id, category, source_url, created_at, updated_at
 FROM mod_files
 WHERE mod_page_id = ?
 ORDER BY is_primary DESC, label COLLATE NOCASE, id;
 
+-- name: ListModFileVersionsByGameInstall :many
+SELECT v.id, v.mod_file_id, v.archive_sha256, v.original_name, v.version_string,
+  v.uploaded_at, v.created_at, b.size_bytes
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+LEFT JOIN blobs b ON b.sha256 = v.archive_sha256
+WHERE p.game_install_id = ?
+ORDER BY v.mod_file_id, v.created_at DESC, v.id DESC;
+
 -- name: ListModFileVersionsByFile :many
 SELECT id, mod_file_id, archive_sha256, original_name, version_string, created_at
 FROM mod_file_versions
@@ -282,25 +363,57 @@ SELECT id, mod_page_id, label, is_primary, nexus_file_id
 FROM mod_files
 WHERE mod_page_id = ? AND label = ?;
 
+-- name: GetModFileByNexusFileID :one
+SELECT id, mod_page_id, label, is_primary, nexus_file_id
+FROM mod_files
+WHERE mod_page_id = ? AND nexus_file_id = ?
+ORDER BY id
+LIMIT 1;
+
+-- name: SetModFileNexus :exec
+UPDATE mod_files
+SET nexus_file_id = ?,
+  category = ?,
+  updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;
+
 -- name: CountModFilesForPage :one
 SELECT COUNT(1)
 FROM mod_files
 WHERE mod_page_id = ?;
 
 -- name: CreateProfile :one
-INSERT INTO profiles (game_install_id, name, description, is_active)
-VALUES (?, ?, ?, FALSE)
+INSERT INTO profiles (game_install_id, name, description, game_version, is_active)
+VALUES (?, ?, ?, ?, FALSE)
 RETURNING id;
 
 -- name: GetProfileByName :one
 SELECT * FROM profiles WHERE game_install_id = ? AND name = ? LIMIT 1;
 
 -- name: ListProfilesByGameInstall :many
-SELECT id, name, description, is_active, created_at, updated_at
+SELECT id, name, description, game_version, is_active, locked_at, created_at, updated_at
 FROM profiles
 WHERE game_install_id = ?
 ORDER BY is_active DESC, name COLLATE NOCASE, id;
 
+-- name: SetProfileGameVersion :exec
+UPDATE profiles
+SET game_version = ?,
+  updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;
+
+-- name: LockProfile :execrows
+UPDATE profiles
+SET locked_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+  updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ? AND locked_at IS NULL;
+
+-- name: UnlockProfile :execrows
+UPDATE profiles
+SET locked_at = NULL,
+  updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ? AND locked_at IS NOT NULL;
+
 -- name: RenameProfile :exec
 UPDATE profiles
 SET name = ?, updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
@@ -355,7 +468,7 @@ FROM mod_file_versions
 WHERE id = ? LIMIT 1;
 
 -- name: GetProfileItemByVersion :one
-SELECT id, enabled
+SELECT id, enabled, symlinks
 FROM profile_items
 WHERE profile_id = ? AND mod_file_version_id = ? LIMIT 1;
 
@@ -365,6 +478,12 @@ SET enabled = ?,
     updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
 WHERE id = ?;
 
+-- name: SetProfileItemSymlinks :exec
+UPDATE profile_items
+SET symlinks = ?,
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE id = ?;
+
 -- name: GetAppliedProfileIDForGame :one
 SELECT applied_profile_id
 FROM game_installs
@@ -382,3 +501,1036 @@ WHERE profile_id = ? AND mod_file_version_id = ?;
 -- name: DeleteProfileItemByID :exec
 DELETE FROM profile_items
 WHERE id = ?;
+
+-- name: ListModFileVersionsMissingVersion :many
+SELECT
+  mfv.id,
+  mfv.original_name,
+  mp.name AS mod_name,
+  mp.nexus_mod_id
+FROM mod_file_versions mfv
+JOIN mod_files mf ON mf.id = mfv.mod_file_id
+JOIN mod_pages mp ON mp.id = mf.mod_page_id
+WHERE mp.game_install_id = ?
+  AND mfv.version_string IS NULL
+  AND mfv.original_name IS NOT NULL
+ORDER BY mfv.id;
+
+-- name: SetGuessedModFileVersion :exec
+UPDATE mod_file_versions
+SET version_string = sqlc.arg(version_string),
+    uploaded_at = COALESCE(uploaded_at, sqlc.arg(uploaded_at)),
+    metadata = json_set(COALESCE(metadata, '{}'), '$.version_source', 'filename'),
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE id = sqlc.arg(id) AND version_string IS NULL;
+
+-- name: GetModFileVersionForGame :one
+SELECT mfv.id, mfv.mod_file_id, mfv.original_name, mfv.version_string,
+  mfv.uploaded_at, mf.label AS mod_file_label, mp.name AS mod_name
+FROM mod_file_versions mfv
+JOIN mod_files mf ON mf.id = mfv.mod_file_id
+JOIN mod_pages mp ON mp.id = mf.mod_page_id
+WHERE mfv.id = ? AND mp.game_install_id = ?;
+
+-- name: SetModFileVersionString :exec
+UPDATE mod_file_versions
+SET version_string = sqlc.narg(version_string),
+    metadata = CASE
+      WHEN sqlc.narg(version_string) IS NULL THEN json_remove(metadata, '$.version_source')
+      ELSE json_set(COALESCE(metadata, '{}'), '$.version_source', 'manual')
+    END,
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE id = sqlc.arg(id);
+
+-- name: SetModFileVersionUploadedAt :exec
+UPDATE mod_file_versions
+SET uploaded_at = ?,
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE id = ?;
+
+-- name: GetModFileForGame :one
+SELECT mf.id, mf.mod_page_id, mf.label, mf.is_primary, mp.name AS mod_name
+FROM mod_files mf
+JOIN mod_pages mp ON mp.id = mf.mod_page_id
+WHERE mf.id = ? AND mp.game_install_id = ?;
+
+-- name: SetModFileLabel :exec
+UPDATE mod_files
+SET label = ?,
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE id = ?;
+
+-- name: ClearPrimaryModFileForPage :exec
+UPDATE mod_files
+SET is_primary = FALSE,
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE mod_page_id = ? AND is_primary = TRUE;
+
+-- name: SetModFilePrimary :exec
+UPDATE mod_files
+SET is_primary = TRUE,
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE id = ?;
+
+-- name: FindModFileVersionByArchiveForGame :one
+SELECT mfv.id, mfv.mod_file_id, mf.mod_page_id, mfv.version_string,
+  mf.label AS mod_file_label, mp.name AS mod_name
+FROM mod_file_versions mfv
+JOIN mod_files mf ON mf.id = mfv.mod_file_id
+JOIN mod_pages mp ON mp.id = mf.mod_page_id
+WHERE mfv.archive_sha256 = ? AND mp.game_install_id = ?
+ORDER BY mfv.id
+LIMIT 1;
+
+-- name: GetAPIRateLimits :one
+SELECT * FROM api_rate_limits WHERE api = ?;
+
+-- name: UpsertAPIRateLimits :exec
+INSERT INTO api_rate_limits (
+  api, hourly_limit, hourly_remaining, hourly_reset_at,
+  daily_limit, daily_remaining, daily_reset_at, observed_at
+) VALUES (
+  ?, ?, ?, ?,
+  ?, ?, ?, ?
+)
+ON CONFLICT (api) DO UPDATE SET
+  hourly_limit = excluded.hourly_limit,
+  hourly_remaining = excluded.hourly_remaining,
+  hourly_reset_at = excluded.hourly_reset_at,
+  daily_limit = excluded.daily_limit,
+  daily_remaining = excluded.daily_remaining,
+  daily_reset_at = excluded.daily_reset_at,
+  observed_at = excluded.observed_at,
+  updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+
+-- name: ListDownloadHosts :many
+SELECT * FROM download_hosts ORDER BY host;
+
+-- name: UpsertDownloadHost :exec
+INSERT INTO download_hosts (host, limit_rate, concurrency)
+VALUES (?, ?, ?)
+ON CONFLICT (host) DO UPDATE SET
+  limit_rate = excluded.limit_rate,
+  concurrency = excluded.concurrency,
+  updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+
+-- name: DeleteDownloadHost :execrows
+DELETE FROM download_hosts WHERE host = ?;
+
+-- name: ListNexusModPagesByGameInstall :many
+SELECT id, name, nexus_game_domain, nexus_mod_id
+FROM mod_pages
+WHERE game_install_id = ?
+  AND source_kind = 'nexus'
+  AND nexus_game_domain IS NOT NULL
+  AND nexus_mod_id IS NOT NULL
+ORDER BY name COLLATE NOCASE, id;
+
+-- name: ListVersionStringsForPage :many
+SELECT mfv.version_string
+FROM mod_file_versions mfv
+JOIN mod_files mf ON mf.id = mfv.mod_file_id
+WHERE mf.mod_page_id = ? AND mfv.version_string IS NOT NULL;
+
+-- name: ListNexusFilesForPage :many
+-- the nexus file of the newest version of every mod file of a page (or of the
+-- mod file itself if none of its versions knows), with when it was imported
+SELECT id, label, nexus_file_id, imported_at
+FROM (
+  SELECT f.id, f.label, f.is_primary,
+    CAST(COALESCE((
+      SELECT v.nexus_file_id FROM mod_file_versions v
+      WHERE v.mod_file_id = f.id AND v.nexus_file_id IS NOT NULL
+      ORDER BY v.created_at DESC, v.id DESC
+      LIMIT 1
+    ), f.nexus_file_id) AS INTEGER) AS nexus_file_id,
+    CAST(COALESCE((
+      SELECT MAX(v.created_at) FROM mod_file_versions v WHERE v.mod_file_id = f.id
+    ), f.created_at) AS TEXT) AS imported_at
+  FROM mod_files f
+  WHERE f.mod_page_id = sqlc.arg(mod_page_id)
+)
+WHERE nexus_file_id IS NOT NULL
+ORDER BY is_primary DESC, label COLLATE NOCASE, id;
+
+-- name: ListImportedNexusFileIDsForPage :many
+-- every nexus file that was ever imported for a page
+SELECT DISTINCT CAST(v.nexus_file_id AS INTEGER) AS nexus_file_id
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+WHERE f.mod_page_id = sqlc.arg(mod_page_id) AND v.nexus_file_id IS NOT NULL
+UNION
+SELECT CAST(nexus_file_id AS INTEGER)
+FROM mod_files
+WHERE mod_page_id = sqlc.arg(mod_page_id) AND nexus_file_id IS NOT NULL;
+
+-- name: UpsertNexusFileUpdate :exec
+INSERT INTO nexus_file_updates (
+  nexus_game_domain, nexus_mod_id, old_file_id, new_file_id, uploaded_at
+) VALUES (
+  ?, ?, ?, ?, ?
+)
+ON CONFLICT (nexus_game_domain, nexus_mod_id, old_file_id, new_file_id) DO UPDATE SET
+  uploaded_at = excluded.uploaded_at;
+
+-- name: EnsureStore :execrows
+INSERT INTO stores (id, display_name, implementation, enabled)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: ListRecentOperations :many
+SELECT o.id, o.game_install_id, o.profile_id, p.name AS profile_name,
+  o.op_type, o.status, o.started_at, o.finished_at, o.message
+FROM operations o
+LEFT JOIN profiles p ON p.id = o.profile_id
+ORDER BY o.started_at DESC, o.id DESC
+LIMIT ?;
+
+-- name: ListModPagesByNameForGame :many
+SELECT id, game_install_id, name, source_kind, nexus_game_domain, nexus_mod_id
+FROM mod_pages
+WHERE game_install_id = ? AND name = ? COLLATE NOCASE
+ORDER BY id;
+
+-- name: ListModPagesByArchivePrefix :many
+SELECT DISTINCT p.id, p.game_install_id, p.name, p.source_kind,
+  p.nexus_game_domain, p.nexus_mod_id
+FROM mod_pages p
+JOIN mod_files f ON f.mod_page_id = p.id
+JOIN mod_file_versions v ON v.mod_file_id = f.id
+WHERE p.game_install_id = ?
+  AND v.archive_sha256 GLOB sqlc.arg(pattern)
+ORDER BY p.id;
+
+-- name: GetModPageDetails :one
+SELECT * FROM mod_pages WHERE id = ?;
+
+-- name: ListModFileVersionDetailsByFile :many
+SELECT v.id, v.archive_sha256, v.original_name, v.version_string,
+  v.uploaded_at, v.notes, v.created_at, b.size_bytes, b.fetched_from,
+  b.fetched_at
+FROM mod_file_versions v
+LEFT JOIN blobs b ON b.sha256 = v.archive_sha256
+WHERE v.mod_file_id = ?
+ORDER BY v.created_at DESC, v.id DESC;
+
+-- name: ListModFileVersionMetadataForPage :many
+SELECT v.id, f.label AS file_label, v.version_string, v.archive_sha256,
+  v.metadata
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+WHERE f.mod_page_id = ?
+ORDER BY f.is_primary DESC, v.created_at DESC, v.id DESC;
+
+-- name: ListArchivesForModPage :many
+SELECT v.id, v.mod_file_id, f.label AS file_label, v.version_string,
+  v.archive_sha256, b.size_bytes, b.corrupted_at
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+LEFT JOIN blobs b ON b.sha256 = v.archive_sha256
+WHERE f.mod_page_id = ?
+ORDER BY f.is_primary DESC, f.id, v.created_at DESC, v.id DESC;
+
+-- name: ListArchivesForGame :many
+SELECT v.id, v.archive_sha256, v.original_name, f.label AS file_label,
+  f.nexus_file_id, p.id AS mod_page_id, p.name AS mod_name,
+  p.nexus_game_domain, p.nexus_mod_id, b.size_bytes, b.corrupted_at
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+JOIN blobs b ON b.sha256 = v.archive_sha256
+WHERE p.game_install_id = ?
+ORDER BY p.name COLLATE NOCASE, v.id;
+
+-- name: ListProfileItemsForModPage :many
+SELECT pi.mod_file_version_id, pr.name AS profile_name, pi.enabled, pi.priority
+FROM profile_items pi
+JOIN profiles pr ON pr.id = pi.profile_id
+JOIN mod_file_versions v ON v.id = pi.mod_file_version_id
+JOIN mod_files f ON f.id = v.mod_file_id
+WHERE f.mod_page_id = ?
+ORDER BY pr.name COLLATE NOCASE, pi.mod_file_version_id;
+
+-- name: CompleteModPagesForGame :many
+SELECT id, name
+FROM mod_pages
+WHERE game_install_id = ?
+  AND (name LIKE sqlc.arg(pattern) ESCAPE '\')
+ORDER BY name COLLATE NOCASE, id
+LIMIT 200;
+
+-- name: CompleteModPageIDsForGame :many
+SELECT id, name
+FROM mod_pages
+WHERE game_install_id = ?
+  AND (CAST(id AS TEXT) LIKE sqlc.arg(pattern) ESCAPE '\')
+ORDER BY id
+LIMIT 200;
+
+-- name: CompleteModFileVersionsForGame :many
+SELECT v.id, p.name AS mod_name, f.label AS file_label, v.version_string
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+WHERE p.game_install_id = ?
+  AND (CAST(v.id AS TEXT) LIKE sqlc.arg(pattern) ESCAPE '\')
+ORDER BY v.id
+LIMIT 200;
+
+-- name: CompleteArchiveBlobsForGame :many
+SELECT DISTINCT v.archive_sha256, p.name AS mod_name
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+WHERE p.game_install_id = ?
+  AND v.archive_sha256 GLOB sqlc.arg(pattern)
+ORDER BY v.archive_sha256
+LIMIT 50;
+
+-- name: DiskUsageByKind :many
+-- unreferenced blobs are the ones that a garbage collection could remove
+SELECT b.kind,
+  COUNT(*) AS blobs,
+  CAST(COALESCE(SUM(b.size_bytes), 0) AS INTEGER) AS bytes,
+  CAST(COALESCE(SUM(CASE WHEN r.sha256 IS NULL THEN 1 ELSE 0 END), 0) AS INTEGER) AS unreferenced_blobs,
+  CAST(COALESCE(SUM(CASE WHEN r.sha256 IS NULL THEN b.size_bytes ELSE 0 END), 0) AS INTEGER) AS unreferenced_bytes
+FROM blobs b
+LEFT JOIN (
+  SELECT archive_sha256 AS sha256 FROM mod_file_versions
+  UNION
+  SELECT backup_blob_sha256 FROM backups
+  UNION
+  SELECT blob_sha256 FROM overrides
+  UNION
+  SELECT blob_sha256 FROM mod_attachments
+) r ON r.sha256 = b.sha256
+GROUP BY b.kind
+ORDER BY b.kind;
+
+-- name: DiskUsageDedupSavings :one
+-- bytes that would be needed if every reference had its own copy of the blob
+SELECT COUNT(*) AS shared_blobs,
+  CAST(COALESCE(SUM(b.size_bytes * (r.refs - 1)), 0) AS INTEGER) AS saved_bytes
+FROM (
+  SELECT sha256, COUNT(*) AS refs
+  FROM (
+    SELECT archive_sha256 AS sha256 FROM mod_file_versions
+    UNION ALL
+    SELECT backup_blob_sha256 FROM backups
+    UNION ALL
+    SELECT blob_sha256 FROM overrides
+    UNION ALL
+    SELECT blob_sha256 FROM mod_attachments
+  )
+  GROUP BY sha256
+  HAVING COUNT(*) > 1
+) r
+JOIN blobs b ON b.sha256 = r.sha256;
+
+-- name: DiskUsageByGame :many
+SELECT gi.id, gi.store_id, gi.store_game_id, gi.instance_id, gi.display_name,
+  (SELECT COUNT(*) FROM mod_pages p WHERE p.game_install_id = gi.id) AS mods,
+  CAST(COALESCE((
+    SELECT SUM(b.size_bytes) FROM blobs b
+    WHERE b.sha256 IN (
+      SELECT v.archive_sha256
+      FROM mod_file_versions v
+      JOIN mod_files f ON f.id = v.mod_file_id
+      JOIN mod_pages p ON p.id = f.mod_page_id
+      WHERE p.game_install_id = gi.id
+    )
+  ), 0) AS INTEGER) AS archive_bytes,
+  CAST(COALESCE((
+    SELECT SUM(b.size_bytes) FROM blobs b
+    WHERE b.sha256 IN (
+      SELECT bk.backup_blob_sha256 FROM backups bk WHERE bk.game_install_id = gi.id
+    )
+  ), 0) AS INTEGER) AS backup_bytes,
+  CAST(COALESCE((
+    SELECT SUM(b.size_bytes) FROM blobs b
+    WHERE b.sha256 IN (
+      SELECT o.blob_sha256
+      FROM overrides o
+      JOIN profiles pr ON pr.id = o.profile_id
+      WHERE pr.game_install_id = gi.id
+    )
+  ), 0) AS INTEGER) AS override_bytes
+FROM game_installs gi
+ORDER BY gi.id;
+
+-- name: DiskUsageByModPage :many
+-- pass a negative row_limit for all rows
+SELECT p.id, p.game_install_id, p.name, gi.display_name AS game_name,
+  (SELECT COUNT(*)
+    FROM mod_file_versions v
+    JOIN mod_files f ON f.id = v.mod_file_id
+    WHERE f.mod_page_id = p.id) AS versions,
+  CAST(COALESCE((
+    SELECT SUM(b.size_bytes) FROM blobs b
+    WHERE b.sha256 IN (
+      SELECT v.archive_sha256
+      FROM mod_file_versions v
+      JOIN mod_files f ON f.id = v.mod_file_id
+      WHERE f.mod_page_id = p.id
+    )
+  ), 0) AS INTEGER) AS bytes
+FROM mod_pages p
+JOIN game_installs gi ON gi.id = p.game_install_id
+WHERE sqlc.narg(game_install_id) IS NULL OR p.game_install_id = sqlc.narg(game_install_id)
+ORDER BY bytes DESC, p.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListPruneCandidates :many
+-- versions of each mod file beyond the newest keep_latest (by import time)
+WITH ranked AS (
+  SELECT v.id, v.mod_file_id, v.archive_sha256, v.version_string,
+    v.created_at, f.label AS file_label, p.id AS mod_page_id,
+    p.name AS mod_name,
+    ROW_NUMBER() OVER (
+      PARTITION BY v.mod_file_id
+      ORDER BY v.created_at DESC, v.id DESC
+    ) AS rn,
+    EXISTS (
+      SELECT 1 FROM installed_files i WHERE i.owner_mod_file_version_id = v.id
+    ) AS installed,
+    -- everything that refers to the archive, like CountBlobReferences
+    -- (backups and overrides can share the blob of an archive)
+    (SELECT COUNT(*) FROM mod_file_versions v2
+      WHERE v2.archive_sha256 = v.archive_sha256)
+    + (SELECT COUNT(*) FROM mod_attachments a
+      WHERE a.blob_sha256 = v.archive_sha256)
+    + (SELECT COUNT(*) FROM overrides o
+      WHERE o.blob_sha256 = v.archive_sha256)
+    + (SELECT COUNT(*) FROM backups bk
+      WHERE bk.backup_blob_sha256 = v.archive_sha256) AS archive_refs,
+    -- a newer upload replaced its nexus file (see mods outdated)
+    EXISTS (
+      SELECT 1 FROM nexus_file_updates u
+      WHERE u.nexus_game_domain = p.nexus_game_domain
+        AND u.nexus_mod_id = p.nexus_mod_id
+        AND u.old_file_id = v.nexus_file_id
+    ) AS superseded
+  FROM mod_file_versions v
+  JOIN mod_files f ON f.id = v.mod_file_id
+  JOIN mod_pages p ON p.id = f.mod_page_id
+  WHERE p.game_install_id = sqlc.arg(game_install_id)
+)
+SELECT ranked.id, ranked.mod_file_id, ranked.archive_sha256,
+  ranked.version_string, ranked.created_at, ranked.file_label,
+  ranked.mod_page_id, ranked.mod_name, ranked.installed, ranked.archive_refs,
+  ranked.superseded,
+  CAST(COALESCE(b.size_bytes, 0) AS INTEGER) AS size_bytes
+FROM ranked
+LEFT JOIN blobs b ON b.sha256 = ranked.archive_sha256
+WHERE ranked.rn > CAST(sqlc.arg(keep_latest) AS INTEGER)
+ORDER BY ranked.mod_name COLLATE NOCASE, ranked.mod_page_id,
+  ranked.file_label, ranked.rn;
+
+-- name: ListProfileNamesForVersion :many
+SELECT pr.name, CAST(pr.locked_at IS NOT NULL AS INTEGER) AS locked
+FROM profile_items pi
+JOIN profiles pr ON pr.id = pi.profile_id
+WHERE pi.mod_file_version_id = ?
+ORDER BY pr.name COLLATE NOCASE;
+
+-- name: DeleteProfileItemsForVersion :exec
+DELETE FROM profile_items WHERE mod_file_version_id = ?;
+
+-- name: DeleteModFileVersion :exec
+DELETE FROM mod_file_versions WHERE id = ?;
+
+-- name: CountBlobReferences :one
+SELECT
+  (SELECT COUNT(*) FROM mod_file_versions WHERE archive_sha256 = sqlc.arg(sha256))
+  + (SELECT COUNT(*) FROM mod_attachments WHERE blob_sha256 = sqlc.arg(sha256))
+  + (SELECT COUNT(*) FROM overrides WHERE blob_sha256 = sqlc.arg(sha256))
+  + (SELECT COUNT(*) FROM backups WHERE backup_blob_sha256 = sqlc.arg(sha256)) AS refs;
+
+-- name: CreateModAttachment :one
+INSERT INTO mod_attachments (
+  mod_page_id, label, blob_sha256, original_name, notes
+) VALUES (
+  ?, ?, ?, ?, ?
+)
+RETURNING id;
+
+-- name: ListModAttachmentsForPage :many
+SELECT a.id, a.label, a.blob_sha256, a.original_name, a.notes, a.created_at,
+  b.kind, b.size_bytes
+FROM mod_attachments a
+JOIN blobs b ON b.sha256 = a.blob_sha256
+WHERE a.mod_page_id = ?
+ORDER BY a.label COLLATE NOCASE, a.id;
+
+-- name: GetModAttachmentByLabel :one
+SELECT a.id, a.label, a.blob_sha256, a.original_name, a.notes, a.created_at,
+  b.kind, b.size_bytes
+FROM mod_attachments a
+JOIN blobs b ON b.sha256 = a.blob_sha256
+WHERE a.mod_page_id = ? AND a.label = ?;
+
+-- name: DeleteModAttachment :exec
+DELETE FROM mod_attachments WHERE id = ?;
+
+-- name: DeleteBlob :exec
+DELETE FROM blobs WHERE sha256 = ?;
+
+-- name: ListPluginOrderForProfile :many
+SELECT position, plugin_name, enabled, source, updated_at
+FROM plugin_orders
+WHERE profile_id = ?
+ORDER BY position;
+
+-- name: DeletePluginOrderForProfile :exec
+DELETE FROM plugin_orders WHERE profile_id = ?;
+
+-- name: InsertPluginOrderEntry :exec
+INSERT INTO plugin_orders (profile_id, position, plugin_name, enabled, source)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ListEnabledProfileItemsForPlan :many
+SELECT pi.id, pi.mod_file_version_id, pi.priority, pi.symlinks,
+  v.archive_sha256, p.name AS mod_name, f.label AS file_label
+FROM profile_items pi
+JOIN mod_file_versions v ON v.id = pi.mod_file_version_id
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+WHERE pi.profile_id = ? AND pi.enabled = TRUE
+ORDER BY pi.priority DESC;
+
+-- name: ListProfileItemsForCollection :many
+SELECT pi.id, pi.enabled, pi.priority, pi.symlinks,
+  p.name AS mod_name, p.source_kind, p.source_url, p.nexus_game_domain,
+  p.nexus_mod_id, f.label AS file_label, f.nexus_file_id,
+  f.source_url AS file_source_url, v.version_string, v.original_name,
+  v.archive_sha256, b.size_bytes
+FROM profile_items pi
+JOIN mod_file_versions v ON v.id = pi.mod_file_version_id
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+JOIN blobs b ON b.sha256 = v.archive_sha256
+WHERE pi.profile_id = ?
+ORDER BY pi.priority, pi.id;
+
+-- name: ListRemapRulesForProfile :many
+SELECT pi.id AS profile_item_id, r.rule_type, r.int_value, r.text_value
+FROM profile_items pi
+JOIN remap_rules r ON r.remap_config_id = pi.remap_config_id
+WHERE pi.profile_id = ?
+ORDER BY pi.id, r.position;
+
+-- name: UpsertOverride :exec
+INSERT INTO overrides (profile_id, target_id, relpath, blob_sha256, notes, is_template)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (profile_id, target_id, relpath) DO UPDATE SET
+  blob_sha256 = excluded.blob_sha256,
+  notes = excluded.notes,
+  is_template = excluded.is_template,
+  updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now');
+
+-- name: GetOverrideForPath :one
+SELECT * FROM overrides
+WHERE profile_id = ? AND target_id = ? AND relpath = ?;
+
+-- name: DeleteOverride :exec
+DELETE FROM overrides WHERE id = ?;
+
+-- name: CountInstalledFilesForOverride :one
+SELECT COUNT(*) FROM installed_files WHERE owner_override_id = ?;
+
+-- name: CountInstalledFilesForTarget :one
+SELECT COUNT(*) FROM installed_files WHERE target_id = ?;
+
+-- name: CountInstalledFilesWithContent :one
+-- the files deployed as symlinks to a blob of the extracted store (see
+-- blobstore.KindExtracted) are the only references to it
+SELECT COUNT(*) FROM installed_files WHERE content_sha256 = ?;
+
+-- name: ListOverridesForProfile :many
+SELECT o.id, o.target_id, t.name AS target_name, o.relpath, o.blob_sha256,
+  o.notes, o.updated_at, o.is_template, b.size_bytes
+FROM overrides o
+JOIN blobs b ON b.sha256 = o.blob_sha256
+JOIN targets t ON t.id = o.target_id
+WHERE o.profile_id = ?
+ORDER BY t.name, o.relpath;
+
+-- name: ListAllProfiles :many
+SELECT * FROM profiles ORDER BY game_install_id, name COLLATE NOCASE, id;
+
+-- name: ListProfileItemArchives :many
+-- left joins so that dangling references still show up
+SELECT pi.id, pi.profile_id, pr.name AS profile_name, pr.game_install_id,
+  pi.mod_file_version_id, pi.enabled, pi.priority,
+  CAST(v.id IS NOT NULL AS INTEGER) AS version_exists,
+  CAST(COALESCE(v.mod_file_id, 0) AS INTEGER) AS mod_file_id,
+  CAST(COALESCE(v.archive_sha256, '') AS TEXT) AS archive_sha256,
+  CAST(b.sha256 IS NOT NULL AS INTEGER) AS blob_recorded,
+  CAST(b.corrupted_at IS NOT NULL AS INTEGER) AS blob_corrupted
+FROM profile_items pi
+JOIN profiles pr ON pr.id = pi.profile_id
+LEFT JOIN mod_file_versions v ON v.id = pi.mod_file_version_id
+LEFT JOIN blobs b ON b.sha256 = v.archive_sha256
+ORDER BY pr.game_install_id, pr.name COLLATE NOCASE, pi.priority DESC;
+
+-- name: GetProfileByID :one
+SELECT * FROM profiles WHERE id = ? LIMIT 1;
+
+-- name: ListInstalledFilesForGame :many
+SELECT * FROM installed_files
+WHERE game_install_id = ?
+ORDER BY target_id, relpath;
+
+-- name: UpsertInstalledFile :exec
+INSERT INTO installed_files (
+  game_install_id,
+  target_id,
+  relpath,
+  content_sha256,
+  size_bytes,
+  owner_mod_file_version_id,
+  owner_override_id,
+  owner_profile_id,
+  last_operation_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (game_install_id, target_id, relpath) DO UPDATE SET
+  content_sha256 = excluded.content_sha256,
+  size_bytes = excluded.size_bytes,
+  owner_mod_file_version_id = excluded.owner_mod_file_version_id,
+  owner_override_id = excluded.owner_override_id,
+  owner_profile_id = excluded.owner_profile_id,
+  last_operation_id = excluded.last_operation_id,
+  installed_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+  verified_at = NULL,
+  drifted_at = NULL,
+  drift_kind = NULL;
+
+-- name: DeleteInstalledFile :exec
+DELETE FROM installed_files WHERE id = ?;
+
+-- name: MarkInstalledFileDrifted :execrows
+-- Only if the row still expects the content that the file was compared to:
+-- an apply that deployed it again in the meantime wins.
+UPDATE installed_files
+SET drifted_at = ?,
+    drift_kind = ?
+WHERE id = ? AND content_sha256 = ?;
+
+-- name: ClearInstalledFileDrift :execrows
+UPDATE installed_files
+SET drifted_at = NULL,
+    drift_kind = NULL
+WHERE id = ? AND drifted_at IS NOT NULL;
+
+-- name: ListInstalledFileDetailsForGame :many
+-- the deployed files of a game install with the mod (file and version) or
+-- override that owns them, for `modctl files list`
+SELECT
+  t.name AS target_name,
+  i.relpath,
+  i.content_sha256,
+  i.size_bytes,
+  i.owner_mod_file_version_id,
+  mp.name AS mod_name,
+  mf.label AS file_label,
+  v.version_string,
+  i.owner_override_id,
+  p.name AS profile_name,
+  i.installed_at,
+  i.verified_at,
+  i.drift_kind,
+  i.drifted_at
+FROM installed_files i
+JOIN targets t ON t.id = i.target_id
+LEFT JOIN mod_file_versions v ON v.id = i.owner_mod_file_version_id
+LEFT JOIN mod_files mf ON mf.id = v.mod_file_id
+LEFT JOIN mod_pages mp ON mp.id = mf.mod_page_id
+LEFT JOIN profiles p ON p.id = i.owner_profile_id
+WHERE i.game_install_id = ?
+ORDER BY t.name, i.relpath;
+
+-- name: ListDriftedFilesForGame :many
+SELECT
+  i.id,
+  t.name AS target_name,
+  i.relpath,
+  i.drift_kind,
+  i.drifted_at
+FROM installed_files i
+JOIN targets t ON t.id = i.target_id
+WHERE i.game_install_id = ? AND i.drifted_at IS NOT NULL
+ORDER BY t.name, i.relpath;
+
+-- name: GetBackupForPath :one
+-- blob_kind: the store that the content is in (a backup can share the blob
+-- of an override, for example)
+SELECT b.*, bl.kind AS blob_kind
+FROM backups b
+JOIN blobs bl ON bl.sha256 = b.backup_blob_sha256
+WHERE b.game_install_id = ? AND b.target_id = ? AND b.relpath = ?
+LIMIT 1;
+
+-- name: UpsertBackup :exec
+INSERT INTO backups (
+  game_install_id,
+  target_id,
+  relpath,
+  backup_blob_sha256,
+  original_content_sha256,
+  size_bytes,
+  created_by_operation_id,
+  read_only
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (game_install_id, target_id, relpath) DO UPDATE SET
+  backup_blob_sha256 = excluded.backup_blob_sha256,
+  original_content_sha256 = excluded.original_content_sha256,
+  size_bytes = excluded.size_bytes,
+  read_only = excluded.read_only,
+  created_by_operation_id = excluded.created_by_operation_id,
+  created_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now');
+
+-- name: DeleteBackup :exec
+DELETE FROM backups WHERE id = ?;
+
+-- name: ListBackupsForGame :many
+SELECT
+  t.name AS target_name,
+  b.target_id,
+  b.relpath,
+  b.backup_blob_sha256,
+  b.size_bytes,
+  b.read_only,
+  bl.kind AS blob_kind
+FROM backups b
+JOIN targets t ON t.id = b.target_id
+JOIN blobs bl ON bl.sha256 = b.backup_blob_sha256
+WHERE b.game_install_id = ?
+ORDER BY t.name, b.relpath;
+
+-- name: CreateOperation :one
+INSERT INTO operations (game_install_id, profile_id, op_type, status, revision)
+VALUES (?, ?, ?, 'running', ?)
+RETURNING id;
+
+-- name: FinishOperation :exec
+UPDATE operations
+SET status = ?,
+    message = ?,
+    metadata = ?,
+    finished_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;
+
+-- name: GetOperation :one
+SELECT o.id, o.game_install_id, g.display_name AS game_name, o.profile_id,
+  p.name AS profile_name, o.op_type, o.status, o.started_at, o.finished_at,
+  o.message, o.metadata
+FROM operations o
+JOIN game_installs g ON g.id = o.game_install_id
+LEFT JOIN profiles p ON p.id = o.profile_id
+WHERE o.id = ?;
+
+-- name: ListApplyMetadata :many
+-- the results of the newest successful applies (of every game install), to
+-- estimate how long the next one takes
+SELECT metadata FROM operations
+WHERE op_type = 'apply' AND status = 'success' AND metadata IS NOT NULL
+ORDER BY id DESC
+LIMIT ?;
+
+-- name: AddOperationNote :exec
+INSERT INTO operation_notes (operation_id, status, note)
+VALUES (?, ?, ?);
+
+-- name: ListOperationNotes :many
+SELECT * FROM operation_notes
+WHERE operation_id = ?
+ORDER BY created_at, id;
+
+-- name: ListOperationLog :many
+-- the newest operations, optionally only of one game install, only those
+-- with notes, or only those with a note that contains search (ignoring case)
+SELECT o.id, o.game_install_id, g.display_name AS game_name, o.profile_id,
+  p.name AS profile_name, o.op_type, o.status, o.started_at, o.finished_at,
+  o.revision
+FROM operations o
+JOIN game_installs g ON g.id = o.game_install_id
+LEFT JOIN profiles p ON p.id = o.profile_id
+WHERE (sqlc.narg(game_install_id) IS NULL OR o.game_install_id = sqlc.narg(game_install_id))
+  AND (CAST(sqlc.arg(noted_only) AS INTEGER) = 0
+    OR EXISTS (SELECT 1 FROM operation_notes n WHERE n.operation_id = o.id))
+  AND (CAST(sqlc.arg(search) AS TEXT) = ''
+    OR EXISTS (SELECT 1 FROM operation_notes n WHERE n.operation_id = o.id
+      AND instr(lower(n.note), lower(CAST(sqlc.arg(search) AS TEXT))) > 0))
+ORDER BY o.started_at DESC, o.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListOperationChanges :many
+SELECT c.id, t.name AS target_name, c.relpath, c.action, c.backup_blob_sha256,
+  c.notes, c.mod_file_version_id, mp.name AS mod_name, f.label AS file_label
+FROM operation_changes c
+JOIN targets t ON t.id = c.target_id
+LEFT JOIN mod_file_versions v ON v.id = c.mod_file_version_id
+LEFT JOIN mod_files f ON f.id = v.mod_file_id
+LEFT JOIN mod_pages mp ON mp.id = f.mod_page_id
+WHERE c.operation_id = ?
+ORDER BY c.id;
+
+-- name: InsertOperationChange :exec
+INSERT INTO operation_changes (
+  operation_id,
+  game_install_id,
+  target_id,
+  relpath,
+  action,
+  old_content_sha256,
+  new_content_sha256,
+  old_size_bytes,
+  new_size_bytes,
+  mod_file_version_id,
+  backup_blob_sha256,
+  notes
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: SetAppliedProfile :exec
+UPDATE game_installs
+SET applied_profile_id = ?,
+    applied_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+    applied_operation_id = ?,
+    applied_revision = ?
+WHERE id = ?;
+
+-- name: ClearAppliedProfile :exec
+UPDATE game_installs
+SET applied_profile_id = NULL,
+    applied_at = NULL,
+    applied_operation_id = ?,
+    applied_revision = NULL
+WHERE id = ?;
+
+-- name: SetInstalledFilesProfile :exec
+UPDATE installed_files
+SET owner_profile_id = ?
+WHERE game_install_id = ?;
+
+-- name: ListHiddenFilesForProfile :many
+SELECT h.profile_item_id, h.relpath
+FROM profile_item_hidden_files h
+JOIN profile_items pi ON pi.id = h.profile_item_id
+WHERE pi.profile_id = ?
+ORDER BY h.profile_item_id, h.relpath;
+
+-- name: HideProfileItemFile :execrows
+INSERT INTO profile_item_hidden_files (profile_item_id, relpath)
+VALUES (?, ?)
+ON CONFLICT (profile_item_id, relpath) DO NOTHING;
+
+-- name: UnhideProfileItemFile :execrows
+DELETE FROM profile_item_hidden_files
+WHERE profile_item_id = ? AND relpath = ?;
+
+-- name: DeleteBaselineForGame :exec
+DELETE FROM baseline_files WHERE game_install_id = ?;
+
+-- name: InsertBaselineFile :exec
+INSERT INTO baseline_files (
+  game_install_id,
+  target_id,
+  relpath,
+  content_sha256,
+  size_bytes,
+  mtime_ns
+) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListBaselineFilesForGame :many
+SELECT * FROM baseline_files
+WHERE game_install_id = ?
+ORDER BY target_id, relpath;
+
+-- name: SetBaselineScanned :exec
+UPDATE game_installs
+SET baseline_scanned_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;
+
+-- name: SetVerifiedVanilla :exec
+UPDATE game_installs
+SET verified_vanilla_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;
+
+-- name: DeleteProfilesForGameInstall :execrows
+-- their items, overrides, and plugin orders go with them
+DELETE FROM profiles WHERE game_install_id = ?;
+
+-- name: DeleteGameInstall :exec
+-- its targets, mods, operations, backups, and baseline go with it (the
+-- profiles have to go first: their items restrict the mod file versions)
+DELETE FROM game_installs WHERE id = ?;
+
+-- name: GetFileHash :one
+SELECT sha256
+FROM file_hashes
+WHERE path = ? AND device = ? AND inode = ? AND size_bytes = ? AND mtime_ns = ?;
+
+-- name: UpsertFileHash :exec
+INSERT INTO file_hashes (path, device, inode, size_bytes, mtime_ns, sha256)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (path) DO UPDATE SET
+  device = excluded.device,
+  inode = excluded.inode,
+  size_bytes = excluded.size_bytes,
+  mtime_ns = excluded.mtime_ns,
+  sha256 = excluded.sha256,
+  hashed_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+
+-- name: ListSettings :many
+SELECT * FROM settings ORDER BY key;
+
+-- name: SetSetting :exec
+INSERT INTO settings (key, value, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET
+  value = excluded.value,
+  updated_at = excluded.updated_at;
+
+-- name: GetSetting :one
+SELECT * FROM settings WHERE key = ?;
+
+-- name: DeleteAdvisories :exec
+DELETE FROM advisories;
+
+-- name: InsertAdvisory :exec
+INSERT INTO advisories (advisory_id, severity, summary, url, published_at,
+  archive_sha256, nexus_game_domain, nexus_mod_id, nexus_file_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListAdvisories :many
+SELECT * FROM advisories ORDER BY published_at DESC, advisory_id;
+
+-- name: ListAdvisoriesForArchive :many
+-- the advisories of an archive that's about to be imported (it isn't
+-- attached to a mod yet)
+SELECT * FROM advisories
+WHERE archive_sha256 = sqlc.arg(archive_sha256)
+  OR (nexus_game_domain = sqlc.narg(nexus_game_domain)
+    AND nexus_mod_id = sqlc.narg(nexus_mod_id)
+    AND (nexus_file_id IS NULL OR nexus_file_id = sqlc.narg(nexus_file_id)))
+ORDER BY advisory_id;
+
+-- name: ListAdvisoryMatches :many
+-- the imported mod file versions that have an advisory
+SELECT a.advisory_id, a.severity, a.summary, a.url,
+  gi.id AS game_install_id, gi.display_name, p.name AS mod_name,
+  f.label AS file_label, v.id AS mod_file_version_id, v.version_string
+FROM mod_file_versions v
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+JOIN game_installs gi ON gi.id = p.game_install_id
+JOIN advisories a ON a.archive_sha256 = v.archive_sha256
+  OR (a.nexus_game_domain = p.nexus_game_domain
+    AND a.nexus_mod_id = p.nexus_mod_id
+    AND (a.nexus_file_id IS NULL OR a.nexus_file_id = f.nexus_file_id))
+ORDER BY gi.id, p.name, f.label, v.id, a.advisory_id;
+
+-- name: ListAdvisoriesForProfile :many
+-- the advisories of the enabled mods of a profile
+SELECT a.advisory_id, a.severity, a.summary, a.url, p.name AS mod_name,
+  f.label AS file_label, v.id AS mod_file_version_id
+FROM profile_items pi
+JOIN mod_file_versions v ON v.id = pi.mod_file_version_id
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+JOIN advisories a ON a.archive_sha256 = v.archive_sha256
+  OR (a.nexus_game_domain = p.nexus_game_domain
+    AND a.nexus_mod_id = p.nexus_mod_id
+    AND (a.nexus_file_id IS NULL OR a.nexus_file_id = f.nexus_file_id))
+WHERE pi.profile_id = ? AND pi.enabled = 1
+ORDER BY pi.priority, pi.id, a.advisory_id;
+
+-- name: ListNexusVersionsToBackfill :many
+-- the mod file versions of nexus mods of a game install that miss their
+-- nexus file id, version string (or only have one guessed from the
+-- filename), upload time, or upstream notes
+SELECT mfv.id, mfv.nexus_file_id, mfv.original_name, mfv.version_string,
+  mfv.uploaded_at, mfv.upstream_notes,
+  CAST(COALESCE(json_extract(mfv.metadata, '$.version_source'), '') AS TEXT) AS version_source,
+  mf.label AS file_label, mp.name AS mod_name, mp.nexus_game_domain,
+  mp.nexus_mod_id
+FROM mod_file_versions mfv
+JOIN mod_files mf ON mf.id = mfv.mod_file_id
+JOIN mod_pages mp ON mp.id = mf.mod_page_id
+WHERE mp.game_install_id = ?
+  AND mp.nexus_game_domain IS NOT NULL
+  AND mp.nexus_mod_id IS NOT NULL
+  AND (mfv.nexus_file_id IS NULL
+    OR mfv.version_string IS NULL
+    OR json_extract(mfv.metadata, '$.version_source') = 'filename'
+    OR mfv.uploaded_at IS NULL
+    OR mfv.upstream_notes IS NULL)
+ORDER BY mp.nexus_game_domain, mp.nexus_mod_id, mfv.id;
+
+-- name: BackfillModFileVersion :exec
+-- fills in what's missing (and replaces a version string that was guessed
+-- from the filename) with what nexus says
+UPDATE mod_file_versions
+SET nexus_file_id = COALESCE(nexus_file_id, sqlc.narg(nexus_file_id)),
+    version_string = CASE
+      WHEN sqlc.narg(version_string) IS NOT NULL AND (version_string IS NULL
+        OR json_extract(metadata, '$.version_source') = 'filename')
+      THEN sqlc.narg(version_string)
+      ELSE version_string
+    END,
+    metadata = CASE
+      WHEN sqlc.narg(version_string) IS NOT NULL AND (version_string IS NULL
+        OR json_extract(metadata, '$.version_source') = 'filename')
+      THEN json_set(COALESCE(metadata, '{}'), '$.version_source', 'nexus')
+      ELSE metadata
+    END,
+    uploaded_at = COALESCE(uploaded_at, sqlc.narg(uploaded_at)),
+    upstream_notes = COALESCE(upstream_notes, sqlc.narg(upstream_notes)),
+    updated_at = (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+WHERE id = sqlc.arg(id);
+
+-- name: ListProfileItemsForCopy :many
+-- everything that it takes to recreate the items of a profile (and their
+-- mods) on another game install
+SELECT pi.id, pi.enabled, pi.priority, pi.symlinks, pi.remap_config_id,
+  pi.notes, v.archive_sha256, v.original_name, v.version_string,
+  v.uploaded_at, v.upstream_notes, v.notes AS version_notes,
+  v.metadata AS version_metadata, v.nexus_file_id AS version_nexus_file_id,
+  f.mod_page_id, f.label AS file_label, f.is_primary, f.nexus_file_id,
+  f.category, f.source_url AS file_source_url, f.metadata AS file_metadata,
+  p.name AS mod_name, p.source_kind, p.source_url, p.source_ref,
+  p.nexus_game_domain, p.nexus_mod_id, p.notes AS page_notes,
+  p.metadata AS page_metadata
+FROM profile_items pi
+JOIN mod_file_versions v ON v.id = pi.mod_file_version_id
+JOIN mod_files f ON f.id = v.mod_file_id
+JOIN mod_pages p ON p.id = f.mod_page_id
+WHERE pi.profile_id = ?
+ORDER BY pi.priority, pi.id;
+
+-- name: CopyProfileItem :one
+INSERT INTO profile_items (
+  profile_id, policy, mod_file_version_id, enabled, priority, symlinks,
+  remap_config_id, notes
+) VALUES (?, 'pinned', ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: CreateRemapConfig :one
+INSERT INTO remap_configs DEFAULT VALUES
+RETURNING id;
+
+-- name: CopyRemapRules :exec
+INSERT INTO remap_rules (
+  remap_config_id, position, rule_type, int_value, text_value, json_value
+)
+SELECT CAST(sqlc.arg(new_remap_config_id) AS INTEGER), position, rule_type, int_value,
+  text_value, json_value
+FROM remap_rules
+WHERE remap_config_id = sqlc.arg(remap_config_id);
+
+-- name: SetTargetStrategy :exec
+UPDATE targets
+SET deploy_method = ?,
+    backup_policy = ?,
+    updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = ?;